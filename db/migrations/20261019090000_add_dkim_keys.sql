@@ -0,0 +1,69 @@
+-- migrate:up
+
+-- A Domain's DKIM keys, one row per selector. A single key pair on the domains
+-- row could only be replaced in place, and replacing it in place breaks every
+-- message still in flight: a verifier looks the selector up after delivery,
+-- sometimes days after, and finds a public key that no longer matches. A
+-- rotation therefore adds a key under a new selector, publishes it, activates
+-- it, and only then retires the old one.
+CREATE TABLE dkim_keys (
+    id serial PRIMARY KEY,
+    domain character varying(254) NOT NULL REFERENCES domains (domain) ON DELETE CASCADE,
+
+    -- The selector is a DNS label sequence (RFC 6376 §3.1) and is what the
+    -- DKIM-Signature's s= tag names, so it is unique per Domain rather than
+    -- globally: every Domain may publish a "kannon" selector under its own zone.
+    selector character varying(253) NOT NULL,
+
+    -- The k= tag of the published record. Text and not an enum, for the reason
+    -- audit_records.outcome gives: the vocabulary lives in internal/dkim.
+    algorithm text NOT NULL DEFAULT 'rsa',
+
+    private_key character varying NOT NULL,
+    public_key character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+
+    -- NULL until the key is activated, which is what makes it pending: created,
+    -- returned to the operator with its TXT record, but not yet signing. The
+    -- signing key is the most recently activated one that is not retired, so
+    -- activating a newer key supersedes the older without touching its row.
+    activated_at timestamp without time zone,
+
+    -- Set when the operator has withdrawn the record from DNS. A retired key is
+    -- still listed, so what a Domain once signed with stays answerable.
+    retired_at timestamp without time zone,
+
+    UNIQUE (domain, selector)
+);
+
+-- The key every existing Domain has been signing with, under the selector that
+-- was hard-coded until now, so the records already published keep verifying.
+INSERT INTO dkim_keys (domain, selector, algorithm, private_key, public_key, created_at, activated_at)
+SELECT domain, 'kannon', 'rsa', dkim_private_key, dkim_public_key, created_at, created_at
+FROM domains;
+
+ALTER TABLE domains DROP COLUMN dkim_private_key;
+ALTER TABLE domains DROP COLUMN dkim_public_key;
+
+-- migrate:down
+
+ALTER TABLE domains ADD COLUMN dkim_private_key character varying NOT NULL DEFAULT '';
+ALTER TABLE domains ADD COLUMN dkim_public_key character varying NOT NULL DEFAULT '';
+
+-- Lossy: only the signing RSA key survives, and a Domain with no active RSA
+-- key comes back with empty columns, as before it could only sign with RSA.
+UPDATE domains d
+SET dkim_private_key = k.private_key,
+    dkim_public_key = k.public_key
+FROM (
+    SELECT DISTINCT ON (domain) domain, private_key, public_key
+    FROM dkim_keys
+    WHERE algorithm = 'rsa' AND activated_at IS NOT NULL AND retired_at IS NULL
+    ORDER BY domain, activated_at DESC, id DESC
+) k
+WHERE k.domain = d.domain;
+
+ALTER TABLE domains ALTER COLUMN dkim_private_key DROP DEFAULT;
+ALTER TABLE domains ALTER COLUMN dkim_public_key DROP DEFAULT;
+
+DROP TABLE dkim_keys;
@@ -0,0 +1,24 @@
+-- migrate:up
+
+-- Where a DKIM key came from. A Domain migrated from another provider brings
+-- the key its records already publish rather than having one generated, and a
+-- key generated here may be of more than one size; both are worth knowing when
+-- the key is next rotated, and neither can be read back off the key material
+-- reliably once it has been normalised for storage.
+--
+-- Text and not an enum, as for algorithm: the vocabulary lives in
+-- internal/domains.
+ALTER TABLE dkim_keys ADD COLUMN origin text NOT NULL DEFAULT 'generated';
+
+-- The key's size in bits: the modulus for RSA, 256 for Ed25519.
+ALTER TABLE dkim_keys ADD COLUMN bits integer;
+
+-- Every key so far was generated here, at the one size each algorithm had.
+UPDATE dkim_keys SET bits = CASE algorithm WHEN 'ed25519' THEN 256 ELSE 2048 END;
+
+ALTER TABLE dkim_keys ALTER COLUMN bits SET NOT NULL;
+
+-- migrate:down
+
+ALTER TABLE dkim_keys DROP COLUMN bits;
+ALTER TABLE dkim_keys DROP COLUMN origin;
@@ -0,0 +1,20 @@
+-- migrate:up
+
+-- The domain a Domain's bounces are addressed to: the domain part of the
+-- envelope sender (MAIL FROM) of everything it sends. NULL keeps the Domain
+-- itself, which is what every Domain did before this column, and which means
+-- pointing the Domain's own MX at Kannon for bounces to arrive at all.
+--
+-- A subdomain of the Domain and nothing else: SPF authenticates the MAIL FROM
+-- domain, and DMARC passes on SPF only when that domain aligns with the From
+-- header's — which a subdomain always does, and an unrelated domain never.
+ALTER TABLE domains ADD COLUMN return_path_domain character varying(254);
+
+ALTER TABLE domains
+  ADD CONSTRAINT domains_return_path_domain_check
+  CHECK (right(return_path_domain, length(domain) + 1) = '.' || domain);
+
+-- migrate:down
+
+ALTER TABLE domains DROP CONSTRAINT domains_return_path_domain_check;
+ALTER TABLE domains DROP COLUMN return_path_domain;
@@ -0,0 +1,12 @@
+-- migrate:up
+
+-- The Feedback Identity a Domain states for the Feedback-ID and List-Id
+-- headers of everything it sends: {"sender_id", "campaign", "list_id"}, each
+-- optional. A Batch may override any of them, in messages.headers, which needs
+-- no column of its own. The empty object states nothing, so every Domain that
+-- existed before this column keeps sending without either header.
+ALTER TABLE domains ADD COLUMN feedback jsonb DEFAULT '{}'::jsonb NOT NULL;
+
+-- migrate:down
+
+ALTER TABLE domains DROP COLUMN feedback;
@@ -0,0 +1,23 @@
+-- migrate:up
+
+-- A Domain's S/MIME signing certificate chain and private key, both PEM, set
+-- and cleared together. A Domain without them sends unsigned mail, as every
+-- Domain did before these columns.
+ALTER TABLE domains
+    ADD COLUMN smime_certificate text,
+    ADD COLUMN smime_private_key text,
+    ADD CONSTRAINT domains_smime_check CHECK ((smime_certificate IS NULL) = (smime_private_key IS NULL));
+
+-- The certificate, DER, a Recipient asked to have its mail encrypted to. It
+-- travels with the Delivery rather than with the Batch because each Recipient
+-- brings their own.
+ALTER TABLE sending_pool_emails ADD COLUMN smime_certificate bytea;
+
+-- migrate:down
+
+ALTER TABLE sending_pool_emails DROP COLUMN smime_certificate;
+
+ALTER TABLE domains
+    DROP CONSTRAINT domains_smime_check,
+    DROP COLUMN smime_private_key,
+    DROP COLUMN smime_certificate;
@@ -0,0 +1,14 @@
+-- migrate:up
+
+-- The Link Decoration a Domain states: the query parameters appended to the
+-- links of everything it sends, and the hosts they are appended on —
+-- {"params": {...}, "allow_hosts": [...], "deny_hosts": [...]}, each optional.
+-- A Batch may override the parameters in messages.headers, beside its other
+-- per-Batch statements, which needs no column of its own. The empty object
+-- decorates nothing, so every Domain that existed before this column keeps
+-- sending its links exactly as written.
+ALTER TABLE domains ADD COLUMN link_decoration jsonb DEFAULT '{}'::jsonb NOT NULL;
+
+-- migrate:down
+
+ALTER TABLE domains DROP COLUMN link_decoration;
@@ -0,0 +1,13 @@
+-- migrate:up
+
+-- Whether every message of a Domain must leave over authenticated TLS: a
+-- STARTTLS the receiver must offer and a certificate that must verify, or the
+-- message waits rather than going out in the clear. A Batch may require it
+-- for itself, in messages.headers, which needs no column of its own; it can
+-- never relax what its Domain requires. False for every Domain that existed
+-- before this column, which keeps sending opportunistically as it did.
+ALTER TABLE domains ADD COLUMN require_tls boolean DEFAULT false NOT NULL;
+
+-- migrate:down
+
+ALTER TABLE domains DROP COLUMN require_tls;
@@ -0,0 +1,12 @@
+-- migrate:up
+
+-- The IP pool a Domain's mail is sent from: a name in the sender's
+-- `sender.ip_pools`, which the database knows nothing about, so nothing here
+-- checks it. A Batch may name a pool of its own in messages.headers, which
+-- takes priority over this one. Empty, for every Domain that existed before
+-- this column, is the sender's default pool.
+ALTER TABLE domains ADD COLUMN ip_pool character varying DEFAULT '' NOT NULL;
+
+-- migrate:down
+
+ALTER TABLE domains DROP COLUMN ip_pool;
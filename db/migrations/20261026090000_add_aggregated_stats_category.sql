@@ -0,0 +1,25 @@
+-- migrate:up
+
+-- The category of the bounces a counter counts (internal/bounce), so that a
+-- Domain's bounces can be told apart by kind. It is part of the key: an hour
+-- of bounces is a counter per category. Empty for every other type, and for
+-- the bounces counted before they were classified, which stay one counter of
+-- their own rather than being guessed at.
+ALTER TABLE aggregated_stats ADD COLUMN category character varying DEFAULT '' NOT NULL;
+ALTER TABLE aggregated_stats DROP CONSTRAINT aggregated_stats_pkey;
+ALTER TABLE aggregated_stats ADD CONSTRAINT aggregated_stats_pkey PRIMARY KEY (domain, "timestamp", type, category);
+
+-- migrate:down
+
+-- Folds each hour's categories back into the one counter the key allows.
+CREATE TEMPORARY TABLE aggregated_stats_rollup AS
+    SELECT domain, "timestamp", type, SUM(count)::bigint AS count
+    FROM aggregated_stats
+    GROUP BY domain, "timestamp", type;
+DELETE FROM aggregated_stats;
+ALTER TABLE aggregated_stats DROP CONSTRAINT aggregated_stats_pkey;
+ALTER TABLE aggregated_stats DROP COLUMN category;
+INSERT INTO aggregated_stats (domain, "timestamp", type, count)
+    SELECT domain, "timestamp", type, count FROM aggregated_stats_rollup;
+DROP TABLE aggregated_stats_rollup;
+ALTER TABLE aggregated_stats ADD CONSTRAINT aggregated_stats_pkey PRIMARY KEY (domain, "timestamp", type);
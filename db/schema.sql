@@ -119,7 +119,8 @@ CREATE TABLE public.aggregated_stats (
     domain character varying NOT NULL,
     "timestamp" timestamp without time zone NOT NULL,
     type character varying NOT NULL,
-    count bigint DEFAULT 0 NOT NULL
+    count bigint DEFAULT 0 NOT NULL,
+    category character varying DEFAULT ''::character varying NOT NULL
 );
 
 
@@ -155,6 +156,45 @@ CREATE TABLE public.audit_records (
 );
 
 
+--
+-- Name: dkim_keys; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.dkim_keys (
+    id integer NOT NULL,
+    domain character varying(254) NOT NULL,
+    selector character varying(253) NOT NULL,
+    algorithm text DEFAULT 'rsa'::text NOT NULL,
+    private_key character varying NOT NULL,
+    public_key character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    activated_at timestamp without time zone,
+    retired_at timestamp without time zone,
+    origin text DEFAULT 'generated'::text NOT NULL,
+    bits integer NOT NULL
+);
+
+
+--
+-- Name: dkim_keys_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.dkim_keys_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: dkim_keys_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.dkim_keys_id_seq OWNED BY public.dkim_keys.id;
+
+
 --
 -- Name: domain_template; Type: TABLE; Schema: public; Owner: -
 --
@@ -185,10 +225,17 @@ CREATE TABLE public.domains (
     id integer NOT NULL,
     domain character varying(254) NOT NULL,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
-    dkim_private_key character varying NOT NULL,
-    dkim_public_key character varying NOT NULL,
     tracking jsonb DEFAULT '{"links": "identified", "opens": "identified"}'::jsonb NOT NULL,
-    CONSTRAINT domains_domain_check CHECK (((domain)::text ~ '^[a-z0-9_-]+(\.[a-z0-9_-]+)+$'::text))
+    return_path_domain character varying(254),
+    feedback jsonb DEFAULT '{}'::jsonb NOT NULL,
+    smime_certificate text,
+    smime_private_key text,
+    link_decoration jsonb DEFAULT '{}'::jsonb NOT NULL,
+    require_tls boolean DEFAULT false NOT NULL,
+    ip_pool character varying DEFAULT ''::character varying NOT NULL,
+    CONSTRAINT domains_domain_check CHECK (((domain)::text ~ '^[a-z0-9_-]+(\.[a-z0-9_-]+)+$'::text)),
+    CONSTRAINT domains_return_path_domain_check CHECK (("right"((return_path_domain)::text, (length((domain)::text) + 1)) = ('.'::text || (domain)::text))),
+    CONSTRAINT domains_smime_check CHECK (((smime_certificate IS NULL) = (smime_private_key IS NULL)))
 );
 
 
@@ -254,7 +301,8 @@ CREATE TABLE public.sending_pool_emails (
     created_at timestamp without time zone DEFAULT now() NOT NULL,
     domain character varying NOT NULL,
     tracking jsonb DEFAULT '{"links": "identified", "opens": "identified"}'::jsonb NOT NULL,
-    claimed_at timestamp without time zone
+    claimed_at timestamp without time zone,
+    smime_certificate bytea
 );
 
 
@@ -362,6 +410,13 @@ CREATE SEQUENCE public.templates_id_seq
 ALTER SEQUENCE public.templates_id_seq OWNED BY public.templates.id;
 
 
+--
+-- Name: dkim_keys id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.dkim_keys ALTER COLUMN id SET DEFAULT nextval('public.dkim_keys_id_seq'::regclass);
+
+
 --
 -- Name: domains id; Type: DEFAULT; Schema: public; Owner: -
 --
@@ -427,7 +482,7 @@ ALTER TABLE ONLY public."User"
 --
 
 ALTER TABLE ONLY public.aggregated_stats
-    ADD CONSTRAINT aggregated_stats_pkey PRIMARY KEY (domain, "timestamp", type);
+    ADD CONSTRAINT aggregated_stats_pkey PRIMARY KEY (domain, "timestamp", type, category);
 
 
 --
@@ -454,6 +509,22 @@ ALTER TABLE ONLY public.audit_records
     ADD CONSTRAINT audit_records_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: dkim_keys dkim_keys_domain_selector_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.dkim_keys
+    ADD CONSTRAINT dkim_keys_domain_selector_key UNIQUE (domain, selector);
+
+
+--
+-- Name: dkim_keys dkim_keys_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.dkim_keys
+    ADD CONSTRAINT dkim_keys_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: domain_template domain_template_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -733,6 +804,14 @@ ALTER TABLE ONLY public.api_keys
     ADD CONSTRAINT api_keys_domain_fkey FOREIGN KEY (domain) REFERENCES public.domains(domain) ON DELETE CASCADE;
 
 
+--
+-- Name: dkim_keys dkim_keys_domain_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.dkim_keys
+    ADD CONSTRAINT dkim_keys_domain_fkey FOREIGN KEY (domain) REFERENCES public.domains(domain) ON DELETE CASCADE;
+
+
 --
 -- Name: domain_user domain_user_userId_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -780,4 +859,13 @@ INSERT INTO public.schema_migrations (version) VALUES
     ('20260727071416'),
     ('20260803094036'),
     ('20260804082406'),
-    ('20260804135145');
+    ('20260804135145'),
+    ('20261019090000'),
+    ('20261019100000'),
+    ('20261020090000'),
+    ('20261021090000'),
+    ('20261022090000'),
+    ('20261023090000'),
+    ('20261024090000'),
+    ('20261025090000'),
+    ('20261026090000');
@@ -2,6 +2,7 @@ package e2e_test
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"sync"
@@ -38,7 +39,14 @@ type msg struct {
 	Body []byte
 }
 
-func (s *senderMock) Send(from string, to string, body []byte) smtp.SenderError {
+func (s *senderMock) Send(from string, to string, b smtp.Body, _ smtp.SendOptions) (smtp.Sent, smtp.SenderError) {
+	// Read the body the way the real Sender does, from its first byte, so an
+	// Envelope carried out of band is captured as the message it reassembles to.
+	body, err := readBody(b)
+	if err != nil {
+		return smtp.Sent{}, &mockSenderError{err: err, permanent: false, code: 451}
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.ensureInit()
@@ -48,7 +56,7 @@ func (s *senderMock) Send(from string, to string, body []byte) smtp.SenderError
 	s.history[to] = append(s.history[to], m)
 
 	if isBounceAddress(to) {
-		return &mockSenderError{
+		return smtp.Sent{}, &mockSenderError{
 			err:       fmt.Errorf("550 user unknown: %s", to),
 			permanent: true,
 			code:      550,
@@ -56,7 +64,7 @@ func (s *senderMock) Send(from string, to string, body []byte) smtp.SenderError
 	}
 
 	if n, ok := transientFailCount(to); ok && s.attempts[to] <= n {
-		return &mockSenderError{
+		return smtp.Sent{}, &mockSenderError{
 			err:       fmt.Errorf("451 transient failure (attempt %d/%d): %s", s.attempts[to], n, to),
 			permanent: false,
 			code:      451,
@@ -64,7 +72,16 @@ func (s *senderMock) Send(from string, to string, body []byte) smtp.SenderError
 	}
 
 	s.latest[to] = m
-	return nil
+	return smtp.Sent{}, nil
+}
+
+func readBody(b smtp.Body) ([]byte, error) {
+	r, err := b()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
 }
 
 // GetEmail returns the most recent successfully-sent Envelope for the
@@ -183,6 +183,18 @@ func TestCreateDomainIsReachableOnlyFromTheRoot(t *testing.T) {
 	})
 }
 
+// An address carries the mail of every Domain sent from it, so reading its
+// warm-up is for the root alone: admin on every Domain is not admin of the
+// installation.
+func TestSendingAddressesAreReachableOnlyFromTheRoot(t *testing.T) {
+	runDecisions(t, []decision{
+		{"admin on the root reads them", adminOn(authz.RootAnchor()), authz.Read, authz.SendingAddresses(), true},
+		{"admin on every Domain does not", adminOn(authz.AllDomainsAnchor()), authz.Read, authz.SendingAddresses(), false},
+		{"admin on one Domain does not", adminOn(authz.DomainAnchor(example)), authz.Read, authz.SendingAddresses(), false},
+		{"sender on every Domain does not", senderOn(authz.AllDomainsAnchor()), authz.Read, authz.SendingAddresses(), false},
+	})
+}
+
 // admin on the root is the Role that can do everything on every Domain: one at()
 // rule holding the five resource Actions, extended to every kind by domination.
 func TestAdminOnTheRootCanDoEverythingEverywhere(t *testing.T) {
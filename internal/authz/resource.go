@@ -9,9 +9,11 @@ import (
 
 // The segments of the Resource tree, named here rather than inline: domains, domains/<name>
 // (update = SetTrackingPolicy), .../batches (create = SendHTML / SendTemplate),
-// .../templates/<id>, .../apikeys/<id>, .../stats (per-Delivery rows), .../stats/aggregated.
+// .../templates/<id>, .../apikeys/<id>, .../stats (per-Delivery rows), .../stats/aggregated;
+// and, beside domains, addresses (read = GetWarmupProgress, GetDestinationCooldowns).
 const (
 	segDomains    = "domains"
+	segAddresses  = "addresses"
 	segBatches    = "batches"
 	segTemplates  = "templates"
 	segAPIKeys    = "apikeys"
@@ -42,6 +44,13 @@ func Domains() Resource {
 	return Resource{segments: []string{segDomains}}
 }
 
+// SendingAddresses names the installation's outbound IP addresses and their warm-up. Beside
+// domains rather than beneath one: an address carries the mail of every Domain sent from it, so
+// no Domain owns it, and only a Grant anchored at the root reaches it.
+func SendingAddresses() Resource {
+	return Resource{segments: []string{segAddresses}}
+}
+
 // Domain names one Domain. Authority over it reaches that Domain's Templates,
 // Batches, API Keys and statistics, so update here is also update on its
 // Templates — see ADR 0008.
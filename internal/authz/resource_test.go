@@ -29,6 +29,8 @@ func TestResourcePaths(t *testing.T) {
 		// rows implies authority over the counters, which is true anyway since
 		// anyone who can read every event can count them.
 		{"the counters", authz.AggregatedStats(example), "domains/example.com/stats/aggregated"},
+		// Beside domains: no Domain owns an address its mail shares with others.
+		{"the sending addresses", authz.SendingAddresses(), "addresses"},
 	}
 
 	for _, tc := range tests {
@@ -10,7 +10,10 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
 	"github.com/kannon-email/kannon/internal/tracking"
+	"github.com/kannon-email/kannon/internal/values"
 )
 
 // Domain errors.
@@ -91,7 +94,11 @@ type Batch struct {
 	attachments         Attachments
 	headers             Headers
 	oneClickUnsubscribe OneClickUnsubscribe
+	feedback            feedback.Identity
+	linkParams          linkparams.Params
 	tracking            tracking.Policy
+	requireTLS          bool
+	ipPool              string
 }
 
 // NewParams contains all fields needed to create a fresh Batch.
@@ -105,9 +112,23 @@ type NewParams struct {
 	// OneClickUnsubscribe is the sender's own unsubscribe endpoint. Zero when
 	// the caller states none, in which case no unsubscribe header is emitted.
 	OneClickUnsubscribe OneClickUnsubscribe
+	// Feedback overrides the Domain's Feedback Identity field by field. Zero
+	// when the caller overrides nothing.
+	Feedback feedback.Identity
+	// LinkParams overrides the Domain's link query parameters one by one, an
+	// empty value removing the Domain's of that name. Nil when the caller
+	// overrides nothing.
+	LinkParams linkparams.Params
 	// Tracking is the Tracking Policy as the caller stated it for this Batch —
 	// persisted as provenance only (ADR 0003).
 	Tracking tracking.Policy
+	// RequireTLS has every message of the Batch refused rather than sent
+	// without authenticated TLS. False leaves it to the Domain; a Batch can
+	// tighten its Domain's setting but never relax it.
+	RequireTLS bool
+	// IPPool names the IP pool the Batch's mail is sent from, taking priority
+	// over its Domain's. Empty leaves it to the Domain.
+	IPPool string
 }
 
 // New creates a new Batch with a freshly generated ID for the given domain.
@@ -132,6 +153,15 @@ func New(p NewParams) (*Batch, error) {
 	if err := p.OneClickUnsubscribe.validate(); err != nil {
 		return nil, err
 	}
+	if err := p.Feedback.Validate(); err != nil {
+		return nil, err
+	}
+	if err := p.LinkParams.Validate(); err != nil {
+		return nil, err
+	}
+	if err := values.ValidateIPPool(p.IPPool); err != nil {
+		return nil, err
+	}
 	return &Batch{
 		id:                  NewID(p.Domain),
 		subject:             p.Subject,
@@ -141,7 +171,11 @@ func New(p NewParams) (*Batch, error) {
 		attachments:         p.Attachments,
 		headers:             p.Headers,
 		oneClickUnsubscribe: p.OneClickUnsubscribe,
+		feedback:            p.Feedback,
+		linkParams:          p.LinkParams,
 		tracking:            p.Tracking,
+		requireTLS:          p.RequireTLS,
+		ipPool:              p.IPPool,
 	}, nil
 }
 
@@ -155,7 +189,11 @@ type LoadParams struct {
 	Attachments         Attachments
 	Headers             Headers
 	OneClickUnsubscribe OneClickUnsubscribe
+	Feedback            feedback.Identity
+	LinkParams          linkparams.Params
 	Tracking            tracking.Policy
+	RequireTLS          bool
+	IPPool              string
 }
 
 // Load rehydrates a Batch from stored data (used by repository implementations).
@@ -169,7 +207,11 @@ func Load(p LoadParams) *Batch {
 		attachments:         p.Attachments,
 		headers:             p.Headers,
 		oneClickUnsubscribe: p.OneClickUnsubscribe,
+		feedback:            p.Feedback,
+		linkParams:          p.LinkParams,
 		tracking:            p.Tracking,
+		requireTLS:          p.RequireTLS,
+		ipPool:              p.IPPool,
 	}
 }
 
@@ -187,8 +229,26 @@ func (b *Batch) Headers() Headers         { return b.headers }
 // when none was stated.
 func (b *Batch) OneClickUnsubscribe() OneClickUnsubscribe { return b.oneClickUnsubscribe }
 
+// Feedback is the Batch's override of its Domain's Feedback Identity, zero when
+// it overrides nothing. Like the Tracking Policy it is stored as stated and
+// resolved against the Domain only when a message is built.
+func (b *Batch) Feedback() feedback.Identity { return b.feedback }
+
+// LinkParams is the Batch's override of its Domain's link query parameters,
+// nil when it overrides nothing. Resolved against the Domain's, like the
+// Feedback Identity, only when a message is built.
+func (b *Batch) LinkParams() linkparams.Params { return b.linkParams }
+
 // TrackingPolicy is the Tracking Policy as stated by the caller for this
 // Batch — not the resolved value that governs any Delivery. It participates
 // in resolution as the middle level of the cascade, between the Domain's
 // ceiling and the Recipient (ADR 0003).
 func (b *Batch) TrackingPolicy() tracking.Policy { return b.tracking }
+
+// RequireTLS reports whether the Batch itself requires authenticated TLS. Its
+// messages also require it when their Domain does.
+func (b *Batch) RequireTLS() bool { return b.requireTLS }
+
+// IPPool is the IP pool the Batch chose for its mail, empty when it leaves the
+// choice to its Domain.
+func (b *Batch) IPPool() string { return b.ipPool }
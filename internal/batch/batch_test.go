@@ -4,7 +4,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
 	"github.com/kannon-email/kannon/internal/tracking"
+	"github.com/kannon-email/kannon/internal/values"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -146,3 +149,56 @@ func TestBatchExposesTheUnsubscribeEndpoint(t *testing.T) {
 	assert.Equal(t, "https://test.com/unsub", b.OneClickUnsubscribe().URLTemplate)
 	assert.False(t, b.OneClickUnsubscribe().IsZero())
 }
+
+func TestNewBatchValidatesTheFeedbackOverride(t *testing.T) {
+	newWith := func(f feedback.Identity) error {
+		_, err := New(NewParams{
+			Domain:     "example.com",
+			Subject:    "subject",
+			Sender:     Sender{Email: "from@example.com", Alias: "From"},
+			TemplateID: "tpl_abc",
+			Feedback:   f,
+		})
+		return err
+	}
+
+	assert.NoError(t, newWith(feedback.Identity{Campaign: "spring", ListID: "news.example.com"}))
+	assert.ErrorIs(t, newWith(feedback.Identity{Campaign: "spring:sale"}), feedback.ErrInvalidIdentity)
+	assert.ErrorIs(t, newWith(feedback.Identity{ListID: "news.example.com>\r\nBcc: victim@test.com"}), feedback.ErrInvalidIdentity)
+}
+
+func TestNewBatchValidatesTheLinkParamsOverride(t *testing.T) {
+	newWith := func(p linkparams.Params) error {
+		_, err := New(NewParams{
+			Domain:     "example.com",
+			Subject:    "subject",
+			Sender:     Sender{Email: "from@example.com", Alias: "From"},
+			TemplateID: "tpl_abc",
+			LinkParams: p,
+		})
+		return err
+	}
+
+	assert.NoError(t, newWith(linkparams.Params{"utm_campaign": "{{ campaign }}", "utm_content": ""}))
+	assert.ErrorIs(t, newWith(linkparams.Params{"utm campaign": "spring"}), linkparams.ErrInvalid)
+}
+
+func TestNewBatchValidatesTheIPPool(t *testing.T) {
+	newWith := func(pool string) (*Batch, error) {
+		return New(NewParams{
+			Domain:     "example.com",
+			Subject:    "subject",
+			Sender:     Sender{Email: "from@example.com", Alias: "From"},
+			TemplateID: "tpl_abc",
+			IPPool:     pool,
+		})
+	}
+
+	b, err := newWith("transactional")
+	require.NoError(t, err)
+	assert.Equal(t, "transactional", b.IPPool())
+	_, err = newWith("")
+	assert.NoError(t, err, "no pool leaves the choice to the Domain")
+	_, err = newWith("pools.bulk")
+	assert.ErrorIs(t, err, values.ErrInvalidIPPool)
+}
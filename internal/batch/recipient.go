@@ -25,6 +25,10 @@ type Recipient struct {
 	// narrow what its Batch and Domain allow. Zero when it states nothing, which
 	// imposes no restriction of its own.
 	Tracking tracking.Policy
+	// SMIMECertificate is the PEM certificate this Recipient asked to have
+	// their mail encrypted to, empty when they asked for nothing of the kind.
+	// It is checked at intake, where a bad one Rejects the Recipient.
+	SMIMECertificate string
 }
 
 // HasAddress reports whether the Recipient names an address at all. Whitespace is
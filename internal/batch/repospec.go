@@ -3,6 +3,8 @@ package batch
 import (
 	"testing"
 
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
 	"github.com/kannon-email/kannon/internal/tracking"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -89,6 +91,64 @@ func testCreate(t *testing.T, repo Repository, helper RepoTestHelper) {
 		assert.Equal(t, u, fetched.OneClickUnsubscribe())
 	})
 
+	t.Run("WithFeedbackOverride", func(t *testing.T) {
+		ctx := t.Context()
+		domain := helper.CreateDomain(t)
+		tpl := helper.CreateTemplate(t, domain)
+
+		f := feedback.Identity{Campaign: "spring", ListID: "news." + domain}
+		b, err := New(NewParams{Domain: domain, Subject: testSubject, Sender: Sender{Email: "from@" + domain, Alias: testSenderAlias}, TemplateID: tpl, Feedback: f})
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, b))
+
+		fetched, err := repo.GetByID(ctx, b.ID())
+		require.NoError(t, err)
+		assert.Equal(t, f, fetched.Feedback())
+	})
+
+	t.Run("WithLinkParamsOverride", func(t *testing.T) {
+		ctx := t.Context()
+		domain := helper.CreateDomain(t)
+		tpl := helper.CreateTemplate(t, domain)
+
+		p := linkparams.Params{"utm_campaign": "spring", "utm_content": ""}
+		b, err := New(NewParams{Domain: domain, Subject: testSubject, Sender: Sender{Email: "from@" + domain, Alias: testSenderAlias}, TemplateID: tpl, LinkParams: p})
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, b))
+
+		fetched, err := repo.GetByID(ctx, b.ID())
+		require.NoError(t, err)
+		assert.Equal(t, p, fetched.LinkParams(), "an empty value is an instruction, and is kept")
+	})
+
+	t.Run("WithRequireTLS", func(t *testing.T) {
+		ctx := t.Context()
+		domain := helper.CreateDomain(t)
+		tpl := helper.CreateTemplate(t, domain)
+
+		b, err := New(NewParams{Domain: domain, Subject: testSubject, Sender: Sender{Email: "from@" + domain, Alias: testSenderAlias}, TemplateID: tpl, RequireTLS: true})
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, b))
+
+		fetched, err := repo.GetByID(ctx, b.ID())
+		require.NoError(t, err)
+		assert.True(t, fetched.RequireTLS())
+	})
+
+	t.Run("WithIPPool", func(t *testing.T) {
+		ctx := t.Context()
+		domain := helper.CreateDomain(t)
+		tpl := helper.CreateTemplate(t, domain)
+
+		b, err := New(NewParams{Domain: domain, Subject: testSubject, Sender: Sender{Email: "from@" + domain, Alias: testSenderAlias}, TemplateID: tpl, IPPool: "transactional"})
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, b))
+
+		fetched, err := repo.GetByID(ctx, b.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "transactional", fetched.IPPool())
+	})
+
 	t.Run("WithoutOneClickUnsubscribe", func(t *testing.T) {
 		// The key is absent from the headers JSONB, which is the same state every
 		// Batch written before ADR 0005 is in — hence no migration.
@@ -103,6 +163,9 @@ func testCreate(t *testing.T, repo Repository, helper RepoTestHelper) {
 		fetched, err := repo.GetByID(ctx, b.ID())
 		require.NoError(t, err)
 		assert.True(t, fetched.OneClickUnsubscribe().IsZero())
+		assert.True(t, fetched.Feedback().IsZero(), "no override is stored as no key at all")
+		assert.Empty(t, fetched.LinkParams())
+		assert.False(t, fetched.RequireTLS())
 	})
 }
 
@@ -0,0 +1,180 @@
+// Package bodystore carries large Envelope bodies out of band, so that they
+// do not travel whole on kannon.sending.
+//
+// An Envelope is a whole signed message per recipient, and a Batch with a
+// 10 MB attachment to 10,000 recipients would put 100 GB through JetStream —
+// and stop at its max_payload long before that. Most of those bytes are the
+// same for every recipient: an attachment renders to identical MIME, and only
+// the headers, the personalised HTML and the signatures differ. So a body over
+// the inline limit is cut at its large MIME parts, each stored once in a
+// JetStream Object Store under the digest of its content, and the Envelope
+// published carries the remaining bytes and the references between them. The
+// SMTPSender reads the parts back in order while it writes DATA, fetching each
+// referenced one only when it gets to it.
+//
+// The cut is an optimisation and never a transformation: the parts
+// concatenated are the built body byte for byte, DKIM and S/MIME signatures
+// included, wherever the cuts fall. A body the MIME walk cannot make sense of
+// is stored whole.
+package bodystore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kannon-email/kannon/internal/envelope"
+)
+
+// ErrNotFound is a reference to an object the store does not hold, because it
+// expired or was never written.
+var ErrNotFound = errors.New("body part not found")
+
+// DefaultMaxInline is the largest body published inline when the
+// configuration states no limit: a quarter of NATS' default max_payload,
+// which leaves room for everything else on the stream.
+const DefaultMaxInline = 256 * 1024
+
+// minSharedPart is the smallest MIME part worth a reference. Below it, the
+// round trip to the store costs more than carrying the bytes inline.
+const minSharedPart = 32 * 1024
+
+// Store holds the shared parts of out-of-band bodies, by reference.
+type Store interface {
+	// Put stores data under ref. A ref is derived from the content, so putting
+	// one that is already held is a no-op, however many Envelopes share it.
+	Put(ctx context.Context, ref string, data []byte) error
+	// Open reads the object stored under ref, or fails with ErrNotFound.
+	Open(ctx context.Context, ref string) (io.ReadCloser, error)
+}
+
+// Offload returns env ready to publish. A body of at most maxInline bytes is
+// left as it is; a larger one is stored in s, shared parts once each, and the
+// returned Envelope carries Parts in its place.
+func Offload(ctx context.Context, s Store, env *envelope.Envelope, maxInline int) (*envelope.Envelope, error) {
+	body := env.Body()
+	if len(body) <= maxInline {
+		return env, nil
+	}
+
+	parts := cut(body, sharedParts(body, minSharedPart))
+	if inlineSize(parts) > maxInline {
+		// The per-recipient remainder is too large by itself — one huge HTML
+		// part, or an encrypted body with no structure left to walk. Storing
+		// it whole keeps it off the stream, at the cost of sharing nothing.
+		parts = cut(body, []span{{0, len(body)}})
+	}
+
+	out := make([]envelope.Part, 0, len(parts))
+	for _, p := range parts {
+		if !p.shared {
+			out = append(out, envelope.Part{Inline: p.data})
+			continue
+		}
+		ref := Ref(p.data)
+		if err := s.Put(ctx, ref, p.data); err != nil {
+			return nil, fmt.Errorf("cannot store body part %s: %w", ref, err)
+		}
+		out = append(out, envelope.Part{Ref: ref})
+	}
+
+	return envelope.New(envelope.Params{
+		EmailID:     env.EmailID(),
+		From:        env.From(),
+		To:          env.To(),
+		ReturnPath:  env.ReturnPath(),
+		Parts:       out,
+		ShouldRetry: env.ShouldRetry(),
+		RequireTLS:  env.RequireTLS(),
+		IPPool:      env.IPPool(),
+		Size:        env.Size(),
+		SMTPUTF8:    env.SMTPUTF8(),
+	}), nil
+}
+
+// Ref is the name data is stored under: the hex SHA-256 of its content, so
+// that the same attachment built for ten thousand recipients is one object.
+func Ref(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256-" + hex.EncodeToString(sum[:])
+}
+
+// Body returns a function that opens env's body from its first byte, the
+// shape smtp.Body takes. An Envelope with no Parts is read from memory; one
+// with Parts is streamed, each referenced part fetched from s only once the
+// parts before it have been read.
+func Body(ctx context.Context, s Store, env *envelope.Envelope) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		if len(env.Parts()) == 0 {
+			return io.NopCloser(bytes.NewReader(env.Body())), nil
+		}
+		return &partsReader{ctx: ctx, store: s, parts: env.Parts()}, nil
+	}
+}
+
+// partsReader reads the parts of a body one after the other, holding at most
+// one object open at a time.
+type partsReader struct {
+	ctx   context.Context
+	store Store
+	parts []envelope.Part
+	cur   io.ReadCloser
+}
+
+func (r *partsReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if len(r.parts) == 0 {
+				return 0, io.EOF
+			}
+			next := r.parts[0]
+			r.parts = r.parts[1:]
+			if next.Ref == "" {
+				r.cur = io.NopCloser(bytes.NewReader(next.Inline))
+			} else {
+				obj, err := r.store.Open(r.ctx, next.Ref)
+				if err != nil {
+					return 0, fmt.Errorf("cannot open body part %s: %w", next.Ref, err)
+				}
+				r.cur = obj
+			}
+		}
+
+		n, err := r.cur.Read(p)
+		if err == io.EOF {
+			closeErr := r.cur.Close()
+			r.cur = nil
+			if closeErr != nil {
+				return n, closeErr
+			}
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *partsReader) Close() error {
+	if r.cur == nil {
+		return nil
+	}
+	err := r.cur.Close()
+	r.cur = nil
+	return err
+}
+
+func inlineSize(parts []piece) int {
+	n := 0
+	for _, p := range parts {
+		if !p.shared {
+			n += len(p.data)
+		}
+	}
+	return n
+}
@@ -0,0 +1,206 @@
+package bodystore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/envelope"
+	"github.com/kannon-email/kannon/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// message renders the shape the Builder writes: a per-recipient header and
+// HTML part, then an attachment identical for every recipient.
+func message(to string, attachment []byte) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "To: %s\r\nMIME-Version: 1.0\r\n", to)
+	b.WriteString("Content-Type: multipart/mixed; boundary=\"outer\"\r\n\r\n")
+	b.WriteString("--outer\r\n")
+	b.WriteString("Content-Type: multipart/alternative; boundary=\"inner\"\r\n\r\n")
+	b.WriteString("--inner\r\nContent-Type: text/html\r\n\r\n")
+	fmt.Fprintf(&b, "<p>Hello %s</p>\r\n", to)
+	b.WriteString("--inner--\r\n")
+	b.WriteString("\r\n--outer\r\n")
+	b.WriteString("Content-Type: application/pdf\r\nContent-Transfer-Encoding: base64\r\n\r\n")
+	enc := base64.StdEncoding.EncodeToString(attachment)
+	for len(enc) > 76 {
+		b.WriteString(enc[:76] + "\r\n")
+		enc = enc[76:]
+	}
+	b.WriteString(enc)
+	b.WriteString("\r\n--outer--\r\n")
+	return []byte(b.String())
+}
+
+func envelopeOf(body []byte) *envelope.Envelope {
+	return envelope.New(envelope.Params{EmailID: "id", To: "t@x", ReturnPath: "rp", Body: body, ShouldRetry: true})
+}
+
+func read(t *testing.T, s Store, env *envelope.Envelope) []byte {
+	t.Helper()
+	r, err := Body(t.Context(), s, env)()
+	require.NoError(t, err)
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return b
+}
+
+func TestOffloadLeavesASmallBodyInline(t *testing.T) {
+	s := newMemStore()
+	env := envelopeOf([]byte("small"))
+
+	got, err := Offload(t.Context(), s, env, 1024)
+	require.NoError(t, err)
+
+	assert.Same(t, env, got)
+	assert.Zero(t, s.puts())
+}
+
+// The attachment every recipient of a Batch shares is stored once, and each
+// Envelope carries only its own bytes and the reference.
+func TestOffloadSharesTheAttachmentAcrossEnvelopes(t *testing.T) {
+	s := newMemStore()
+	attachment := bytes.Repeat([]byte("pdf!"), 64*1024)
+
+	var refs []string
+	for _, to := range []string{"a@x", "b@x", "c@x"} {
+		body := message(to, attachment)
+		env, err := Offload(t.Context(), s, envelopeOf(body), 64*1024)
+		require.NoError(t, err)
+
+		assert.Empty(t, env.Body())
+		assert.Equal(t, "t@x", env.To())
+		assert.True(t, env.ShouldRetry())
+		assert.Equal(t, body, read(t, s, env), "the parts must reassemble to the built body, byte for byte")
+
+		inline := 0
+		for _, p := range env.Parts() {
+			if p.Ref != "" {
+				refs = append(refs, p.Ref)
+			}
+			inline += len(p.Inline)
+		}
+		assert.Less(t, inline, 1024, "only the per-recipient bytes travel inline")
+	}
+
+	require.Len(t, refs, 3)
+	assert.Equal(t, refs[0], refs[1])
+	assert.Equal(t, refs[0], refs[2])
+	assert.Equal(t, 1, s.objects(), "one object for the one attachment")
+}
+
+// A body with no structure to cut — here, one large single-part message — is
+// stored whole rather than published.
+func TestOffloadStoresAnUnstructuredBodyWhole(t *testing.T) {
+	s := newMemStore()
+	body := append([]byte("Content-Type: application/pkcs7-mime\r\n\r\n"), bytes.Repeat([]byte("x"), 4096)...)
+	body = append([]byte("To: t@x\r\n"), body...)
+
+	env, err := Offload(t.Context(), s, envelopeOf(body), 1024)
+	require.NoError(t, err)
+
+	require.Len(t, env.Parts(), 1)
+	assert.NotEmpty(t, env.Parts()[0].Ref)
+	assert.Equal(t, body, read(t, s, env))
+}
+
+// The MIME walk only decides where to cut; a body it cannot follow still
+// reassembles exactly.
+func TestOffloadOfAMalformedBodyRoundTrips(t *testing.T) {
+	s := newMemStore()
+	body := []byte("Content-Type: multipart/mixed; boundary=\"b\"\r\n\r\n--b\r\nno end" + strings.Repeat("y", 8192))
+
+	env, err := Offload(t.Context(), s, envelopeOf(body), 1024)
+	require.NoError(t, err)
+	assert.Equal(t, body, read(t, s, env))
+}
+
+func TestSharedPartsFindsTheLeavesOfNestedMultiparts(t *testing.T) {
+	attachment := bytes.Repeat([]byte{0xAB}, 4096)
+	body := message("a@x", attachment)
+
+	spans := sharedParts(body, 1024)
+
+	require.Len(t, spans, 1, "the HTML part is too small to share")
+	got := body[spans[0].start:spans[0].end]
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(got), "\r\n", ""))
+	require.NoError(t, err)
+	assert.Equal(t, attachment, decoded, "the span is the attachment's body, without the delimiter's CRLF")
+}
+
+// A reference the store has lost fails the read, and says which part.
+func TestBodyReportsAMissingPart(t *testing.T) {
+	env := envelope.New(envelope.Params{Parts: []envelope.Part{{Inline: []byte("head")}, {Ref: "sha256-gone"}}})
+
+	r, err := Body(t.Context(), newMemStore(), env)()
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorContains(t, err, "sha256-gone")
+}
+
+// Against the real Object Store: a part is written once however often it is
+// put, and read back as written.
+func TestNATSStore(t *testing.T) {
+	ctx := t.Context()
+	s, err := OpenNATS(ctx, tests.NatsJetStream(t))
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte("attachment"), 20000)
+	ref := Ref(data)
+	require.NoError(t, s.Put(ctx, ref, data))
+	require.NoError(t, s.Put(ctx, ref, data))
+
+	env := envelope.New(envelope.Params{Parts: []envelope.Part{{Inline: []byte("head\r\n")}, {Ref: ref}}})
+	assert.Equal(t, append([]byte("head\r\n"), data...), read(t, s, env))
+
+	_, err = s.Open(ctx, "sha256-missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// memStore is a Store in memory, counting writes.
+type memStore struct {
+	mu     sync.Mutex
+	data   map[string][]byte
+	writes int
+}
+
+func newMemStore() *memStore { return &memStore{data: map[string][]byte{}} }
+
+func (s *memStore) Put(_ context.Context, ref string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes++
+	s.data[ref] = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *memStore) Open(_ context.Context, ref string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.data[ref]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *memStore) puts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writes
+}
+
+func (s *memStore) objects() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.data)
+}
@@ -0,0 +1,73 @@
+package bodystore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// Bucket is the Object Store the shared parts are kept in.
+	Bucket = "kannon-envelope-bodies"
+
+	// TTL is how long a stored part is kept after it was last written. It has
+	// to outlast every Envelope that refers to it: kannon-sending keeps a
+	// message for a day, and a redelivery can come at the end of that day.
+	TTL = 72 * time.Hour
+
+	// refreshAfter is the age past which a Put writes an object it already
+	// holds again. The TTL counts from the write, not from the last use, so a
+	// part shared by a Batch that goes out over days would otherwise expire
+	// under the Envelopes still referring to it.
+	refreshAfter = TTL / 2
+)
+
+// OpenNATS opens the Object Store the Dispatcher writes shared parts to and
+// the SMTPSender reads them from, creating it if it does not exist yet.
+func OpenNATS(ctx context.Context, js jetstream.JetStream) (Store, error) {
+	obs, err := js.CreateOrUpdateObjectStore(ctx, jetstream.ObjectStoreConfig{
+		Bucket:      Bucket,
+		Description: "Envelope body parts too large for kannon-sending, shared across a Batch",
+		TTL:         TTL,
+		Storage:     jetstream.FileStorage,
+		Replicas:    1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot open object store %s: %w", Bucket, err)
+	}
+	return &natsStore{obs: obs}, nil
+}
+
+type natsStore struct {
+	obs jetstream.ObjectStore
+}
+
+func (s *natsStore) Put(ctx context.Context, ref string, data []byte) error {
+	// Two Dispatchers may both find the part missing and both write it. That
+	// is harmless: the ref is the content, so the second write stores what
+	// the first one did.
+	info, err := s.obs.GetInfo(ctx, ref)
+	if err == nil && time.Since(info.ModTime) < refreshAfter {
+		return nil
+	}
+	if err != nil && !errors.Is(err, jetstream.ErrObjectNotFound) {
+		return err
+	}
+	_, err = s.obs.PutBytes(ctx, ref, data)
+	return err
+}
+
+func (s *natsStore) Open(ctx context.Context, ref string) (io.ReadCloser, error) {
+	obj, err := s.obs.Get(ctx, ref)
+	if errors.Is(err, jetstream.ErrObjectNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
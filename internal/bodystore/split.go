@@ -0,0 +1,132 @@
+package bodystore
+
+import (
+	"bufio"
+	"bytes"
+	"mime"
+	"strings"
+
+	"github.com/emersion/go-message/textproto"
+)
+
+var crlf = []byte("\r\n")
+
+// span is a byte range of a body, start inclusive and end exclusive.
+type span struct{ start, end int }
+
+// piece is one run of a body's bytes, and whether it is stored out of band.
+type piece struct {
+	data   []byte
+	shared bool
+}
+
+// sharedParts returns the bodies of the leaf MIME parts of msg that are at
+// least minSize bytes long, in order. Those are what a Batch's Envelopes have in
+// common — attachments, above all, render identically for every recipient —
+// while the headers and delimiters around them are what differs.
+//
+// The walk reads the CRLF form the Builder writes. It does not validate:
+// anything it cannot follow it stops at, and whatever it has not marked stays
+// inline, so a message it misreads costs sharing and never correctness.
+func sharedParts(msg []byte, minSize int) []span {
+	return leafBodies(msg, 0, minSize, nil)
+}
+
+func leafBodies(entity []byte, offset, minSize int, out []span) []span {
+	var header []byte
+	bodyStart := 0
+	switch {
+	case bytes.HasPrefix(entity, crlf):
+		// A part with no header at all: plain text, by RFC 2046's default.
+		bodyStart = len(crlf)
+	default:
+		i := bytes.Index(entity, []byte("\r\n\r\n"))
+		if i < 0 {
+			return out
+		}
+		bodyStart = i + 4
+		header = entity[:bodyStart]
+	}
+	body := entity[bodyStart:]
+
+	if boundary, ok := multipartBoundary(header); ok {
+		for _, p := range multipartParts(body, boundary) {
+			out = leafBodies(body[p.start:p.end], offset+bodyStart+p.start, minSize, out)
+		}
+		return out
+	}
+	if len(body) >= minSize {
+		out = append(out, span{offset + bodyStart, offset + len(entity)})
+	}
+	return out
+}
+
+func multipartBoundary(header []byte) (string, bool) {
+	if len(header) == 0 {
+		return "", false
+	}
+	h, err := textproto.ReadHeader(bufio.NewReader(bytes.NewReader(header)))
+	if err != nil {
+		return "", false
+	}
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return "", false
+	}
+	return params["boundary"], true
+}
+
+// multipartParts returns the parts of a multipart body, each without the
+// delimiter lines around it and without the CRLF that belongs to the next
+// delimiter (RFC 2046 §5.1.1).
+func multipartParts(body []byte, boundary string) []span {
+	dash := []byte("--" + boundary)
+	next := append(append([]byte{}, crlf...), dash...)
+
+	// i is the start of a delimiter line. The first may open the body; every
+	// later one follows the CRLF that ends the part before it.
+	i := 0
+	if !bytes.HasPrefix(body, dash) {
+		j := bytes.Index(body, next)
+		if j < 0 {
+			return nil
+		}
+		i = j + len(crlf)
+	}
+
+	var parts []span
+	for {
+		lineEnd := bytes.Index(body[i:], crlf)
+		if lineEnd < 0 {
+			return parts
+		}
+		if bytes.HasPrefix(body[i+len(dash):], []byte("--")) {
+			return parts
+		}
+		start := i + lineEnd + len(crlf)
+		end := bytes.Index(body[start:], next)
+		if end < 0 {
+			return parts
+		}
+		parts = append(parts, span{start, start + end})
+		i = start + end + len(crlf)
+	}
+}
+
+// cut splits body into pieces along spans, which are ordered and disjoint:
+// each span becomes a shared piece, and the bytes between them inline ones.
+func cut(body []byte, spans []span) []piece {
+	var out []piece
+	pos := 0
+	for _, s := range spans {
+		if s.start > pos {
+			out = append(out, piece{data: body[pos:s.start]})
+		}
+		out = append(out, piece{data: body[s.start:s.end], shared: true})
+		pos = s.end
+	}
+	if pos < len(body) {
+		out = append(out, piece{data: body[pos:]})
+	}
+	return out
+}
@@ -0,0 +1,153 @@
+// Package bounce says what kind of failure a Bounce was. A Bounced stat
+// carries the reply class, the reply code and the reply text, which tell a
+// reader that a receiver refused but not whether the mailbox was full, the
+// address never existed or the message was taken for spam — the difference
+// between writing an address off, trying again next week and fixing the
+// sending. The Classifier turns what a bounce came with into a Category from a
+// fixed set, so that Bounces can be counted by kind.
+package bounce
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Category is the kind of failure a Bounce was. The set is closed: the
+// categories are what Bounces are aggregated by, so a rule file can change
+// which Bounce lands in which category but cannot add one.
+type Category string
+
+const (
+	// CategoryInvalidRecipient is an address that does not exist or no
+	// longer takes mail.
+	CategoryInvalidRecipient Category = "invalid_recipient"
+	// CategoryMailboxFull is a mailbox over its quota.
+	CategoryMailboxFull Category = "mailbox_full"
+	// CategoryPolicyBlock is the receiver refusing the message by a policy
+	// of its own or of the sender's Domain: authentication, reverse DNS,
+	// relaying and the like.
+	CategoryPolicyBlock Category = "policy_block"
+	// CategorySpamBlock is the message or its sender taken for spam,
+	// blocklists included.
+	CategorySpamBlock Category = "spam_block"
+	// CategoryDNSFailure is the recipient's domain not resolving to a host
+	// that takes mail.
+	CategoryDNSFailure Category = "dns_failure"
+	// CategoryTLSFailure is a session that could not be secured as the
+	// receiver or Kannon's TLS policy requires.
+	CategoryTLSFailure Category = "tls_failure"
+	// CategoryRateLimited is the receiver refusing more mail from the sender
+	// for now.
+	CategoryRateLimited Category = "rate_limited"
+	// CategoryOther is every Bounce no rule recognises.
+	CategoryOther Category = "other"
+)
+
+// Categories are every Category, in the order the documentation lists them.
+var Categories = []Category{
+	CategoryInvalidRecipient,
+	CategoryMailboxFull,
+	CategoryPolicyBlock,
+	CategorySpamBlock,
+	CategoryDNSFailure,
+	CategoryTLSFailure,
+	CategoryRateLimited,
+	CategoryOther,
+}
+
+func (c Category) valid() bool {
+	for _, k := range Categories {
+		if c == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Classifier assigns a Bounce its Category by rules in three tiers, each tried
+// only when the one before matched nothing:
+//
+//  1. the enhanced status code, when a rule names it to the detail — X.1.1 is
+//     an unknown mailbox whoever wrote the reply;
+//  2. the reply text, by patterns written for what each provider says — what
+//     5.7.1 means depends on who sent it, and a reply with no enhanced code
+//     says everything it does in words;
+//  3. the enhanced status code by its subject alone (X.7.*), the last word on
+//     a reply the patterns do not know.
+//
+// A Bounce no rule matches is CategoryOther. The operator's rules are tried
+// before the embedded ones within each tier, so one can override a default.
+type Classifier struct {
+	codes    []codeRule
+	patterns []patternRule
+	subjects []codeRule
+}
+
+// Classify is the Category of a Bounce with the given enhanced status code —
+// empty when there is none — and reply text.
+func (c *Classifier) Classify(enhancedCode, reply string) Category {
+	code, ok := parseStatus(enhancedCode)
+	if ok {
+		for _, r := range c.codes {
+			if r.code.matches(code) {
+				return r.category
+			}
+		}
+	}
+	for _, r := range c.patterns {
+		if r.match.MatchString(reply) {
+			return r.category
+		}
+	}
+	if ok {
+		for _, r := range c.subjects {
+			if r.code.matches(code) {
+				return r.category
+			}
+		}
+	}
+	return CategoryOther
+}
+
+// enhancedCodeRE is an RFC 3463 status code anywhere in a text.
+var enhancedCodeRE = regexp.MustCompile(`\b[245]\.\d{1,3}\.\d{1,3}\b`)
+
+// EnhancedCodeOf is the first enhanced status code in text, empty when it has
+// none. It is how the code of a Bounce that arrived as a DSN is found, whose
+// text quotes the reply as the remote MTA reported it.
+func EnhancedCodeOf(text string) string {
+	return enhancedCodeRE.FindString(text)
+}
+
+// status is an enhanced status code, class.subject.detail, with the class 'X'
+// and the detail "*" standing for any in a rule.
+type status struct {
+	class   string
+	subject string
+	detail  string
+}
+
+func parseStatus(s string) (status, bool) {
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return status{}, false
+	}
+	return status{class: parts[0], subject: parts[1], detail: parts[2]}, true
+}
+
+// matches reports whether the code a Bounce came with is one the rule s names.
+func (s status) matches(code status) bool {
+	return (s.class == "X" || s.class == code.class) &&
+		s.subject == code.subject &&
+		(s.detail == "*" || s.detail == code.detail)
+}
+
+type codeRule struct {
+	code     status
+	category Category
+}
+
+type patternRule struct {
+	match    *regexp.Regexp
+	category Category
+}
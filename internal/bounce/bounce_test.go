@@ -0,0 +1,94 @@
+package bounce
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRulesLoad(t *testing.T) {
+	c, err := Load("")
+	require.NoError(t, err)
+	assert.NotEmpty(t, c.codes)
+	assert.NotEmpty(t, c.patterns)
+	assert.NotEmpty(t, c.subjects)
+}
+
+func TestClassify(t *testing.T) {
+	c := Default()
+	cases := []struct {
+		name     string
+		enhanced string
+		reply    string
+		want     Category
+	}{
+		{"code decides over the text", "5.1.1", "550 5.1.1 mailbox full", CategoryInvalidRecipient},
+		{"mailbox full", "4.2.2", "452 4.2.2 over quota", CategoryMailboxFull},
+		{"gmail rate limit", "4.7.28", "421 4.7.28 Our system has detected an unusual rate of unsolicited mail", CategoryRateLimited},
+		{"microsoft server busy", "4.7.500", "451 4.7.500 Server busy. Please try again later", CategoryRateLimited},
+		{"tls by our own policy", "4.7.5", "", CategoryTLSFailure},
+		{"a domain that does not resolve", "5.4.4", "", CategoryDNSFailure},
+		{"null MX", "5.1.10", "", CategoryInvalidRecipient},
+		{"gmail spam on 5.7.1", "5.7.1", "550 5.7.1 Our system has detected that this message is likely unsolicited mail", CategorySpamBlock},
+		{"blocklisted on 5.7.1", "5.7.1", "554 5.7.1 Service unavailable; Client host [192.0.2.1] blocked using zen.spamhaus.org", CategorySpamBlock},
+		{"outlook block list", "5.7.1", "550 5.7.1 Unfortunately, messages from [192.0.2.1] weren't sent. (S3150)", CategorySpamBlock},
+		{"relaying on 5.7.1", "5.7.1", "554 5.7.1 Relay access denied", CategoryPolicyBlock},
+		{"5.7.1 nothing recognises", "5.7.1", "550 5.7.1 go away", CategoryPolicyBlock},
+		{"gmail unknown user with no detail", "5.0.0", "550 The email account that you tried to reach does not exist", CategoryInvalidRecipient},
+		{"yahoo deferral", "4.7.0", "421 4.7.0 [TSS04] Messages from 192.0.2.1 temporarily deferred due to unexpected volume", CategoryRateLimited},
+		{"no code at all", "", "SMTP; 550 User unknown", CategoryInvalidRecipient},
+		{"nothing matches", "5.3.0", "550 5.3.0 something went wrong", CategoryOther},
+		{"nothing at all", "", "", CategoryOther},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, c.Classify(tc.enhanced, tc.reply))
+		})
+	}
+}
+
+func TestOperatorRulesComeFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+codes:
+  - { code: 5.1.1, category: other }
+patterns:
+  - provider: acme
+    match: 'acme says no'
+    category: spam_block
+`), 0o600))
+
+	c, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, CategoryOther, c.Classify("5.1.1", "550 5.1.1 user unknown"))
+	assert.Equal(t, CategoryInvalidRecipient, c.Classify("4.1.1", "450 4.1.1 user unknown"), "the embedded rules still apply")
+	assert.Equal(t, CategorySpamBlock, c.Classify("5.7.1", "550 5.7.1 ACME says no"))
+}
+
+func TestLoadRefusesABrokenRuleFile(t *testing.T) {
+	for name, body := range map[string]string{
+		"unknown category": "codes:\n  - { code: 5.1.1, category: lost }\n",
+		"bad code":         "codes:\n  - { code: 5.1, category: other }\n",
+		"bad class":        "codes:\n  - { code: 2.1.1, category: other }\n",
+		"bad pattern":      "patterns:\n  - { provider: acme, match: '(', category: other }\n",
+		"not yaml":         "codes: [",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+			_, err := Load(path)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestEnhancedCodeOf(t *testing.T) {
+	assert.Equal(t, "5.1.1", EnhancedCodeOf("SMTP; 550 5.1.1 <a@b.test>: Recipient address rejected"))
+	assert.Equal(t, "", EnhancedCodeOf("SMTP; 550 User unknown"))
+}
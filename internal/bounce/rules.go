@@ -0,0 +1,112 @@
+package bounce
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// defaultRules are the rules every Classifier starts from. They live in a file
+// of their own, in the format an operator writes theirs in, so that reading
+// what Kannon does by default is reading the file an operator would copy from.
+//
+//go:embed rules.yaml
+var defaultRules []byte
+
+// ruleFile is the format of a rule file. See rules.yaml for what each field
+// means.
+type ruleFile struct {
+	Codes []struct {
+		Code     string   `yaml:"code"`
+		Category Category `yaml:"category"`
+	} `yaml:"codes"`
+	Patterns []struct {
+		Provider string   `yaml:"provider"`
+		Match    string   `yaml:"match"`
+		Category Category `yaml:"category"`
+	} `yaml:"patterns"`
+}
+
+// Default is the Classifier of the embedded rules alone.
+func Default() *Classifier {
+	c := &Classifier{}
+	if err := c.add(defaultRules, "embedded rules"); err != nil {
+		// Only reachable if rules.yaml is broken, which TestDefaultRulesLoad
+		// would have caught before any build shipped it.
+		panic(err)
+	}
+	return c
+}
+
+// Load is the Classifier of the rule file at path in front of the embedded
+// rules, or of the embedded rules alone when path is empty. A file that cannot
+// be read, names a category not in Categories or holds a pattern that does not
+// compile is an error, rather than rules silently skipped: a misclassified
+// Bounce looks like any other.
+func Load(path string) (*Classifier, error) {
+	c := &Classifier{}
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read bounce rules: %w", err)
+		}
+		if err := c.add(raw, path); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.add(defaultRules, "embedded rules"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// add appends the rules in raw to c's, each in its tier.
+func (c *Classifier) add(raw []byte, source string) error {
+	var f ruleFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+
+	for i, r := range f.Codes {
+		if !r.Category.valid() {
+			return fmt.Errorf("%s: codes[%d]: unknown category %q", source, i, r.Category)
+		}
+		code, ok := parseStatus(r.Code)
+		if !ok || !validRuleStatus(code) {
+			return fmt.Errorf("%s: codes[%d]: %q is not an enhanced status code", source, i, r.Code)
+		}
+		rule := codeRule{code: code, category: r.Category}
+		if code.detail == "*" {
+			c.subjects = append(c.subjects, rule)
+		} else {
+			c.codes = append(c.codes, rule)
+		}
+	}
+
+	for i, r := range f.Patterns {
+		if !r.Category.valid() {
+			return fmt.Errorf("%s: patterns[%d] (%s): unknown category %q", source, i, r.Provider, r.Category)
+		}
+		re, err := regexp.Compile("(?i)" + r.Match)
+		if err != nil {
+			return fmt.Errorf("%s: patterns[%d] (%s): %w", source, i, r.Provider, err)
+		}
+		c.patterns = append(c.patterns, patternRule{match: re, category: r.Category})
+	}
+
+	return nil
+}
+
+var ruleNumberRE = regexp.MustCompile(`^\d{1,3}$`)
+
+// validRuleStatus reports whether s is a code a rule may name: a class of 4, 5
+// or X, a numeric subject, and a numeric detail or "*".
+func validRuleStatus(s status) bool {
+	return strings.Contains("45X", s.class) && len(s.class) == 1 &&
+		ruleNumberRE.MatchString(s.subject) &&
+		(s.detail == "*" || ruleNumberRE.MatchString(s.detail))
+}
@@ -0,0 +1,79 @@
+// Package capture is a Sender for development and CI that delivers nothing:
+// it keeps every message it is handed, with the metadata it was handed with,
+// and serves them back from an inbox — an HTTP API and a small UI — in the
+// same process. What a message would have looked like in a recipient's
+// mailbox, whether its DKIM signatures verify against its Domain's keys and
+// whether its tracked links land where they should can all be checked without
+// an MX host, a published DNS record or a real mailbox.
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Config is `sender.capture`.
+type Config struct {
+	// Enabled replaces the Sender with the capture sink. It cannot be
+	// combined with `sender.demo_sender`.
+	Enabled bool `mapstructure:"enabled"`
+	// Dir is where messages are written, one `.eml` per message beside a
+	// `.json` of its metadata, so that they outlive the process and can be
+	// opened in any mail client. Empty keeps them in memory.
+	Dir string `mapstructure:"dir"`
+	// MaxMessages is how many messages the in-memory store keeps, the
+	// oldest dropped first: 1000 when zero. A directory keeps them all.
+	MaxMessages int `mapstructure:"max_messages"`
+	// Host is the address the inbox is served on: 127.0.0.1 when empty. The
+	// inbox has no authentication and shows every message whole, so it must
+	// never be reachable from a public network; set 0.0.0.0 only where
+	// something else keeps it private, such as a container's published port
+	// bound to the loopback.
+	Host string `mapstructure:"host"`
+	// Port is where the inbox is served: 8025 when zero.
+	Port uint `mapstructure:"port"`
+	// TrackerURL is the local Tracker tracked links and open pixels are sent
+	// to in place of the tracking host they name, which resolves to nothing
+	// on a development machine: http://localhost:8080, the Tracker's default
+	// port, when empty.
+	TrackerURL string `mapstructure:"tracker_url"`
+}
+
+// Defaults.
+const (
+	DefaultMaxMessages = 1000
+	DefaultHost        = "127.0.0.1"
+	DefaultPort        = 8025
+	DefaultTrackerURL  = "http://localhost:8080"
+)
+
+func (c *Config) setDefaults() {
+	if c.MaxMessages == 0 {
+		c.MaxMessages = DefaultMaxMessages
+	}
+	if c.Host == "" {
+		c.Host = DefaultHost
+	}
+	if c.Port == 0 {
+		c.Port = DefaultPort
+	}
+	if c.TrackerURL == "" {
+		c.TrackerURL = DefaultTrackerURL
+	}
+}
+
+// Validate refuses a negative store size and a tracker URL links cannot be
+// sent to.
+func (c Config) Validate() error {
+	if c.MaxMessages < 0 {
+		return errors.New("capture: max_messages must not be negative")
+	}
+	if c.TrackerURL != "" {
+		u, err := url.Parse(c.TrackerURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("capture: tracker_url %q is not an http(s) URL", c.TrackerURL)
+		}
+	}
+	return nil
+}
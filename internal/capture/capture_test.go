@@ -0,0 +1,186 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/dkim"
+	"github.com/kannon-email/kannon/internal/domains"
+	"github.com/kannon-email/kannon/internal/smtp"
+	"github.com/kannon-email/kannon/internal/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	dir, err := NewDirStore(t.TempDir())
+	require.NoError(t, err)
+	stores := map[string]Store{"memory": NewMemoryStore(10), "dir": dir}
+
+	for name, st := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+			var ids []string
+			for i := range 3 {
+				m := &Message{ID: newID(at.Add(time.Duration(i) * time.Second)), To: "to@example.test", Raw: []byte("Subject: hi\r\n\r\nhi\r\n")}
+				require.NoError(t, st.Put(ctx, m))
+				ids = append(ids, m.ID)
+			}
+
+			list, err := st.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, list, 3)
+			assert.Equal(t, ids[2], list[0].ID, "newest first")
+			assert.Nil(t, list[0].Raw)
+
+			m, err := st.Get(ctx, ids[1])
+			require.NoError(t, err)
+			assert.Equal(t, "Subject: hi\r\n\r\nhi\r\n", string(m.Raw))
+
+			_, err = st.Get(ctx, "../../etc/passwd")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			require.NoError(t, st.Clear(ctx))
+			list, err = st.List(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, list)
+		})
+	}
+}
+
+func TestMemoryStoreDropsTheOldest(t *testing.T) {
+	st := NewMemoryStore(2)
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, st.Put(context.Background(), &Message{ID: id}))
+	}
+	_, err := st.Get(context.Background(), "a")
+	assert.ErrorIs(t, err, ErrNotFound)
+	list, err := st.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
+type domainsByName map[values.DomainName]*domains.Domain
+
+func (d domainsByName) FindByName(_ context.Context, n values.DomainName) (*domains.Domain, error) {
+	if found, ok := d[n]; ok {
+		return found, nil
+	}
+	return nil, domains.ErrDomainNotFound
+}
+
+const capturedHTML = `<html><body><a href="https://stats.example.test/c/tok">tracked</a> ` +
+	`<a href="https://example.test/plain">plain</a><img src="https://stats.example.test/o/px"></body></html>`
+
+// A message kept by the sink reads back with its signatures verified against
+// its Domain's keys, and its tracked links lead to the local Tracker.
+func TestInbox(t *testing.T) {
+	tracker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/c/tok", r.URL.Path)
+		http.Redirect(w, r, "https://example.test/landing", http.StatusTemporaryRedirect)
+	}))
+	defer tracker.Close()
+
+	d, err := domains.New(values.MustParse("example.test"))
+	require.NoError(t, err)
+	sink, err := New("sender.test", Config{Enabled: true, TrackerURL: tracker.URL}, WithDomains(domainsByName{d.Name(): d}))
+	require.NoError(t, err)
+
+	raw := "From: Sender <noreply@example.test>\r\nTo: to@example.test\r\nSubject: =?utf-8?q?Buongiorno_=C3=A8?=\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n\r\n" + capturedHTML + "\r\n"
+	k := d.ActiveDKIMKeys()[0]
+	signed, err := dkim.SignMessageWithKeys("example.test", []string{"From", "To", "Subject"},
+		[]dkim.SigningKey{{Selector: k.Selector(), PrivateKey: k.PrivateKey()}}, []byte(raw))
+	require.NoError(t, err)
+
+	_, serr := sink.Send("bounce@example.test", "to@example.test", smtp.BytesBody(signed), smtp.SendOptions{IPPool: "bulk"})
+	require.Nil(t, serr)
+
+	srv := httptest.NewServer(sink.Handler())
+	defer srv.Close()
+
+	var list []Message
+	getJSON(t, srv.URL+"/api/messages", &list)
+	require.Len(t, list, 1)
+	assert.Equal(t, "bounce@example.test", list[0].From)
+	assert.Equal(t, "bulk", list[0].IPPool)
+	id := list[0].ID
+
+	var v View
+	getJSON(t, srv.URL+"/api/messages/"+id, &v)
+	assert.Equal(t, "Buongiorno è", v.Subject)
+	assert.Equal(t, []DKIMCheck{{Domain: "example.test", Valid: true}}, v.DKIM)
+	assert.Equal(t, []Link{
+		{URL: "https://stats.example.test/c/tok", Tracked: true, Local: tracker.URL + "/c/tok"},
+		{URL: "https://example.test/plain"},
+	}, v.Links)
+
+	html := get(t, srv.URL+"/messages/"+id+"/html")
+	assert.Contains(t, html, tracker.URL+"/o/px", "opening the message hits the local Tracker")
+	assert.Contains(t, get(t, srv.URL+"/"), "Buongiorno è")
+	assert.Contains(t, get(t, srv.URL+"/messages/"+id), "pass")
+
+	res, err := http.Post(srv.URL+"/api/messages/"+id+"/links/0/click", "", nil)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	var c Click
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&c))
+	assert.Equal(t, Click{URL: tracker.URL + "/c/tok", Status: http.StatusTemporaryRedirect, Location: "https://example.test/landing"}, c)
+
+	// A message that changed after it was signed fails verification.
+	tampered := strings.Replace(string(signed), "to@example.test", "else@example.test", 1)
+	_, serr = sink.Send("bounce@example.test", "to@example.test", smtp.BytesBody([]byte(tampered)), smtp.SendOptions{})
+	require.Nil(t, serr)
+	getJSON(t, srv.URL+"/api/messages", &list)
+	getJSON(t, srv.URL+"/api/messages/"+list[0].ID, &v)
+	require.Len(t, v.DKIM, 1)
+	assert.False(t, v.DKIM[0].Valid)
+
+	req, err := http.NewRequest(http.MethodDelete, srv.URL+"/api/messages", nil)
+	require.NoError(t, err)
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	getJSON(t, srv.URL+"/api/messages", &list)
+	assert.Empty(t, list)
+
+	res, err = http.Get(srv.URL + "/api/messages/" + id)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+	res, err := http.Get(url)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	b, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func getJSON(t *testing.T, url string, v any) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal([]byte(get(t, url)), v))
+}
+
+// The inbox has no authentication, so it is served on the loopback unless an
+// address is named.
+func TestInboxIsServedOnTheLoopbackByDefault(t *testing.T) {
+	sink, err := New("sender.test", Config{Enabled: true})
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", sink.cfg.Host)
+
+	sink, err = New("sender.test", Config{Enabled: true, Host: "0.0.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "0.0.0.0", sink.cfg.Host)
+}
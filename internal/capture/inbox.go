@@ -0,0 +1,239 @@
+package capture
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//go:embed inbox.html
+var inboxHTML string
+
+var inboxTmpl = template.Must(template.New("inbox").Parse(inboxHTML))
+
+// clickTimeout bounds a click the inbox makes on the local Tracker.
+const clickTimeout = 10 * time.Second
+
+// Handler is the inbox:
+//
+//	GET    /                                  the UI: every message, newest first
+//	GET    /messages/{id}                     the UI: one message
+//	GET    /messages/{id}/html                its HTML body, tracked links on the local Tracker
+//	GET    /messages/{id}/raw                 the message as it would have gone out
+//	GET    /messages/{id}/links/{n}           a click on its n-th link, through the local Tracker
+//	GET    /api/messages                      every message's metadata, as JSON
+//	DELETE /api/messages                      drop every message
+//	GET    /api/messages/{id}                 one message read: headers, bodies, DKIM, links
+//	POST   /api/messages/{id}/links/{n}/click click its n-th link and report where it went
+func (s *Sink) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /messages/{id}", s.handleMessage)
+	mux.HandleFunc("GET /messages/{id}/html", s.handleHTML)
+	mux.HandleFunc("GET /messages/{id}/raw", s.handleRaw)
+	mux.HandleFunc("GET /messages/{id}/links/{n}", s.handleFollow)
+	mux.HandleFunc("GET /api/messages", s.handleList)
+	mux.HandleFunc("DELETE /api/messages", s.handleClear)
+	mux.HandleFunc("GET /api/messages/{id}", s.handleGet)
+	mux.HandleFunc("POST /api/messages/{id}/links/{n}/click", s.handleClick)
+	return mux
+}
+
+// Serve serves the inbox on cfg.Host and cfg.Port until ctx ends.
+func (s *Sink) Serve(ctx context.Context) error {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.FormatUint(uint64(s.cfg.Port), 10))
+	slog.Info("serving the capture inbox on " + addr)
+
+	server := &http.Server{Addr: addr, Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("error shutting down the capture inbox", "err", err)
+		}
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Sink) handleIndex(w http.ResponseWriter, r *http.Request) {
+	msgs, err := s.store.List(r.Context())
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	views := make([]*View, len(msgs))
+	for i, m := range msgs {
+		full, err := s.store.Get(r.Context(), m.ID)
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		views[i] = summary(full)
+	}
+	render(w, "index", views)
+}
+
+func (s *Sink) handleMessage(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+	render(w, "message", s.view(r.Context(), m))
+}
+
+func (s *Sink) handleHTML(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+	v := s.read(m)
+	// The HTML is the sender's, shown as a mail client would: no script of it
+	// runs, and it is framed by the message page rather than a page of its
+	// own origin.
+	w.Header().Set("Content-Security-Policy", "sandbox allow-popups allow-popups-to-escape-sandbox")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(s.localHTML(v.HTML)))
+}
+
+func (s *Sink) handleRaw(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "message/rfc822")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", m.ID+".eml"))
+	_, _ = w.Write(m.Raw)
+}
+
+func (s *Sink) handleFollow(w http.ResponseWriter, r *http.Request) {
+	link, ok := s.link(w, r)
+	if !ok {
+		return
+	}
+	to := link.URL
+	if link.Tracked {
+		to = link.Local
+	}
+	http.Redirect(w, r, to, http.StatusFound)
+}
+
+func (s *Sink) handleList(w http.ResponseWriter, r *http.Request) {
+	msgs, err := s.store.List(r.Context())
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	writeJSON(w, msgs)
+}
+
+func (s *Sink) handleClear(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.Clear(r.Context()); err != nil {
+		httpError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Sink) handleGet(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.message(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, s.view(r.Context(), m))
+}
+
+// Click is where clicking a link went: the local Tracker's reply to a tracked
+// link, which records the click and redirects to the link's destination.
+type Click struct {
+	URL      string `json:"url"`
+	Status   int    `json:"status"`
+	Location string `json:"location,omitempty"`
+}
+
+// handleClick clicks a tracked link the way a recipient would, without
+// following the Tracker's redirect: a test asserts on the click being
+// recorded and on where it leads, not on the destination's page.
+func (s *Sink) handleClick(w http.ResponseWriter, r *http.Request) {
+	link, ok := s.link(w, r)
+	if !ok {
+		return
+	}
+	if !link.Tracked {
+		http.Error(w, "the link is not tracked", http.StatusUnprocessableEntity)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), clickTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.Local, nil)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	res, err := client.Do(req)
+	if err != nil {
+		http.Error(w, "the local Tracker did not answer: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	_ = res.Body.Close()
+	writeJSON(w, Click{URL: link.Local, Status: res.StatusCode, Location: res.Header.Get("Location")})
+}
+
+func (s *Sink) message(w http.ResponseWriter, r *http.Request) (*Message, bool) {
+	m, err := s.store.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		httpError(w, err)
+		return nil, false
+	}
+	return m, true
+}
+
+func (s *Sink) link(w http.ResponseWriter, r *http.Request) (Link, bool) {
+	m, ok := s.message(w, r)
+	if !ok {
+		return Link{}, false
+	}
+	links := s.read(m).Links
+	n, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil || n < 0 || n >= len(links) {
+		http.Error(w, "no such link", http.StatusNotFound)
+		return Link{}, false
+	}
+	return links[n], true
+}
+
+func httpError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	slog.Error("capture inbox", "err", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("capture inbox: cannot write the response", "err", err)
+	}
+}
+
+func render(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := inboxTmpl.ExecuteTemplate(w, name, data); err != nil {
+		slog.Error("capture inbox: cannot render the page", "err", err)
+	}
+}
@@ -0,0 +1,125 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/domains"
+	"github.com/kannon-email/kannon/internal/smtp"
+	"github.com/kannon-email/kannon/internal/values"
+)
+
+// Domains finds the Domain whose keys a captured message's DKIM signatures
+// are verified against. domains.Repository is one.
+type Domains interface {
+	FindByName(ctx context.Context, domain values.DomainName) (*domains.Domain, error)
+}
+
+// Sink is the capture Sender: it keeps every message in its Store instead of
+// sending it, and reports it delivered.
+type Sink struct {
+	hostname string
+	cfg      Config
+	store    Store
+	domains  Domains
+	now      func() time.Time
+}
+
+// Option configures a Sink beyond its configuration.
+type Option func(*Sink)
+
+// WithStore keeps messages in st instead of the store cfg names.
+func WithStore(st Store) Option {
+	return func(s *Sink) { s.store = st }
+}
+
+// WithDomains verifies DKIM signatures against d's keys. Without it they are
+// verified against the DNS, where a development Domain's keys are seldom
+// published.
+func WithDomains(d Domains) Option {
+	return func(s *Sink) { s.domains = d }
+}
+
+// New is a Sink keeping messages as cfg says: in cfg.Dir, or in memory.
+func New(hostname string, cfg Config, opts ...Option) (*Sink, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg.setDefaults()
+	s := &Sink{hostname: hostname, cfg: cfg, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.store != nil {
+		return s, nil
+	}
+	if cfg.Dir == "" {
+		s.store = NewMemoryStore(cfg.MaxMessages)
+		return s, nil
+	}
+	st, err := NewDirStore(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	s.store = st
+	return s, nil
+}
+
+// Send keeps the message. Failing to read its body, or to keep it, is
+// transient, as a receiver's local error would be: the message is tried again
+// rather than lost.
+func (s *Sink) Send(from, to string, body smtp.Body, opts smtp.SendOptions) (smtp.Sent, smtp.SenderError) {
+	raw, err := readBody(body)
+	if err != nil {
+		return smtp.Sent{}, sinkError{fmt.Errorf("capture: cannot read the message body: %w", err)}
+	}
+	now := s.now()
+	m := &Message{
+		ID:         newID(now),
+		From:       from,
+		To:         to,
+		ReceivedAt: now.UTC(),
+		Size:       int64(len(raw)),
+		IPPool:     opts.IPPool,
+		RequireTLS: opts.RequireTLS,
+		SMTPUTF8:   opts.SMTPUTF8,
+		Raw:        raw,
+	}
+	if err := s.store.Put(context.Background(), m); err != nil {
+		return smtp.Sent{}, sinkError{fmt.Errorf("capture: cannot keep the message: %w", err)}
+	}
+	slog.Debug("captured message", "id", m.ID, "to", to)
+	return smtp.Sent{IPPool: opts.IPPool}, nil
+}
+
+// SenderName is the hostname the Sender would greet with.
+func (s *Sink) SenderName() string {
+	return s.hostname
+}
+
+// Store is where the Sink keeps messages.
+func (s *Sink) Store() Store {
+	return s.store
+}
+
+func readBody(body smtp.Body) ([]byte, error) {
+	r, err := body()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// sinkError is a message the Sink could not keep: transient, with the code a
+// receiver's local error in processing has.
+type sinkError struct {
+	err error
+}
+
+func (e sinkError) Error() string     { return e.err.Error() }
+func (e sinkError) IsPermanent() bool { return false }
+func (e sinkError) Code() uint32      { return 451 }
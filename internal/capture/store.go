@@ -0,0 +1,213 @@
+package capture
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is a message the store does not hold.
+var ErrNotFound = errors.New("capture: message not found")
+
+// Message is one message the sink was handed: the metadata Send was called
+// with, and the message itself as it would have gone out in DATA.
+type Message struct {
+	ID string `json:"id"`
+	// From is the envelope sender, the MAIL FROM — the return path, not the
+	// From header.
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	ReceivedAt time.Time `json:"received_at"`
+	Size       int64     `json:"size"`
+	IPPool     string    `json:"ip_pool,omitempty"`
+	RequireTLS bool      `json:"require_tls,omitempty"`
+	SMTPUTF8   bool      `json:"smtputf8,omitempty"`
+	// Raw is the message, headers and signatures included. Listings leave
+	// it out.
+	Raw []byte `json:"-"`
+}
+
+// Store keeps captured messages.
+type Store interface {
+	// Put keeps m.
+	Put(ctx context.Context, m *Message) error
+	// List returns every message kept, newest first, without Raw.
+	List(ctx context.Context) ([]*Message, error)
+	// Get returns the message id, Raw included, or ErrNotFound.
+	Get(ctx context.Context, id string) (*Message, error)
+	// Clear drops every message.
+	Clear(ctx context.Context) error
+}
+
+// newID names a message so that names sort in the order messages arrived,
+// and so that a name is safe as a file name: hex only.
+func newID(now time.Time) string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return fmt.Sprintf("%016x%s", now.UnixNano(), hex.EncodeToString(b[:]))
+}
+
+var idRe = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// memoryStore keeps the last max messages.
+type memoryStore struct {
+	mu   sync.Mutex
+	max  int
+	msgs []*Message
+}
+
+// NewMemoryStore is a Store holding at most max messages in memory, the
+// oldest dropped first.
+func NewMemoryStore(max int) Store {
+	return &memoryStore{max: max}
+}
+
+func (s *memoryStore) Put(_ context.Context, m *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, m)
+	if len(s.msgs) > s.max {
+		s.msgs = slices.Delete(s.msgs, 0, len(s.msgs)-s.max)
+	}
+	return nil
+}
+
+func (s *memoryStore) List(context.Context) ([]*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*Message, len(s.msgs))
+	for i, m := range s.msgs {
+		meta := *m
+		meta.Raw = nil
+		out[len(s.msgs)-1-i] = &meta
+	}
+	return out, nil
+}
+
+func (s *memoryStore) Get(_ context.Context, id string) (*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.msgs {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memoryStore) Clear(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = nil
+	return nil
+}
+
+// dirStore writes each message to dir as <id>.eml, with its metadata in
+// <id>.json.
+type dirStore struct {
+	dir string
+}
+
+// NewDirStore is a Store writing messages to dir, which it creates.
+func NewDirStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("capture: %w", err)
+	}
+	return &dirStore{dir: dir}, nil
+}
+
+func (s *dirStore) path(id, ext string) string {
+	return filepath.Join(s.dir, id+ext)
+}
+
+func (s *dirStore) Put(_ context.Context, m *Message) error {
+	meta, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	// The metadata is written last: a message is listed once its .json
+	// exists, and by then its .eml is whole.
+	if err := os.WriteFile(s.path(m.ID, ".eml"), m.Raw, 0o640); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(m.ID, ".json"), meta, 0o640)
+}
+
+func (s *dirStore) List(context.Context) ([]*Message, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []*Message
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || !idRe.MatchString(id) {
+			continue
+		}
+		m, err := s.meta(id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	slices.SortFunc(out, func(a, b *Message) int { return strings.Compare(b.ID, a.ID) })
+	return out, nil
+}
+
+func (s *dirStore) meta(id string) (*Message, error) {
+	b, err := os.ReadFile(s.path(id, ".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Message
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("capture: %s: %w", s.path(id, ".json"), err)
+	}
+	return &m, nil
+}
+
+func (s *dirStore) Get(_ context.Context, id string) (*Message, error) {
+	// The id comes from a URL: only a name newID could have made is looked
+	// for, so that none can reach outside dir.
+	if !idRe.MatchString(id) {
+		return nil, ErrNotFound
+	}
+	m, err := s.meta(id)
+	if err != nil {
+		return nil, err
+	}
+	if m.Raw, err = os.ReadFile(s.path(id, ".eml")); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *dirStore) Clear(context.Context) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		id := strings.TrimSuffix(strings.TrimSuffix(e.Name(), ".json"), ".eml")
+		if id == e.Name() || !idRe.MatchString(id) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-message"
+	"github.com/emersion/go-message/mail"
+	msgauth "github.com/emersion/go-msgauth/dkim"
+	"github.com/kannon-email/kannon/internal/values"
+
+	// go-message decodes the charsets a message may declare only when they
+	// are registered.
+	_ "github.com/emersion/go-message/charset"
+)
+
+// View is a captured message as the inbox shows it: its metadata, its
+// headers decoded, its HTML and text bodies, its DKIM results and its links.
+type View struct {
+	*Message
+	Subject   string      `json:"subject"`
+	MessageID string      `json:"message_id"`
+	Headers   []Header    `json:"headers"`
+	HTML      string      `json:"html"`
+	Text      string      `json:"text"`
+	DKIM      []DKIMCheck `json:"dkim"`
+	Links     []Link      `json:"links"`
+	// Error is why the message could not be read past its headers, for one
+	// S/MIME-encrypted or malformed. The headers read are still shown.
+	Error string `json:"error,omitempty"`
+}
+
+// Header is one header field, in the order the message has them.
+type Header struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// DKIMCheck is the result of verifying one DKIM-Signature.
+type DKIMCheck struct {
+	Domain string `json:"domain"`
+	Valid  bool   `json:"valid"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Link is one link of the HTML body. A tracked one — to the Tracker's /c/ —
+// has Local set to the same path on the local Tracker, which is where the
+// inbox sends a click on it.
+type Link struct {
+	URL     string `json:"url"`
+	Tracked bool   `json:"tracked"`
+	Local   string `json:"local,omitempty"`
+}
+
+// view reads m for the inbox, and verifies its signatures.
+func (s *Sink) view(ctx context.Context, m *Message) *View {
+	v := s.read(m)
+	v.DKIM = s.verify(ctx, m.Raw)
+	return v
+}
+
+// read reads m's headers, bodies and links.
+func (s *Sink) read(m *Message) *View {
+	v := &View{Message: m, Headers: []Header{}, DKIM: []DKIMCheck{}, Links: []Link{}}
+	e, err := message.Read(bytes.NewReader(m.Raw))
+	if e == nil {
+		v.Error = err.Error()
+		return v
+	}
+	var dec mime.WordDecoder
+	fields := e.Header.Fields()
+	for fields.Next() {
+		value, derr := dec.DecodeHeader(fields.Value())
+		if derr != nil {
+			value = fields.Value()
+		}
+		v.Headers = append(v.Headers, Header{Key: fields.Key(), Value: value})
+	}
+	h := mail.Header{Header: e.Header}
+	if v.Subject, err = h.Subject(); err != nil {
+		v.Subject = h.Get("Subject")
+	}
+	v.MessageID = h.Get("Message-Id")
+
+	if err := v.readBodies(e); err != nil {
+		v.Error = err.Error()
+	}
+	for _, l := range regHref.FindAllStringSubmatch(v.HTML, -1) {
+		link := Link{URL: l[1]}
+		link.Local, link.Tracked = s.local(l[1])
+		v.Links = append(v.Links, link)
+	}
+	return v
+}
+
+// summary is what the message list shows of m: its metadata and subject,
+// read without its bodies and without verifying it.
+func summary(m *Message) *View {
+	v := &View{Message: m}
+	if e, _ := message.Read(bytes.NewReader(m.Raw)); e != nil {
+		h := mail.Header{Header: e.Header}
+		var err error
+		if v.Subject, err = h.Subject(); err != nil {
+			v.Subject = h.Get("Subject")
+		}
+		v.MessageID = h.Get("Message-Id")
+	}
+	return v
+}
+
+// readBodies finds the first text/html and text/plain parts of e.
+func (v *View) readBodies(e *message.Entity) error {
+	return e.Walk(func(_ []int, part *message.Entity, err error) error {
+		if err != nil {
+			return err
+		}
+		t, _, _ := part.Header.ContentType()
+		if disp, _, _ := part.Header.ContentDisposition(); disp == "attachment" {
+			return nil
+		}
+		switch {
+		case t == "text/html" && v.HTML == "":
+			b, err := io.ReadAll(part.Body)
+			v.HTML = string(b)
+			return err
+		case t == "text/plain" && v.Text == "":
+			b, err := io.ReadAll(part.Body)
+			v.Text = string(b)
+			return err
+		}
+		return nil
+	})
+}
+
+// verify checks every DKIM-Signature of raw against its Domain's keys.
+func (s *Sink) verify(ctx context.Context, raw []byte) []DKIMCheck {
+	opts := &msgauth.VerifyOptions{}
+	if s.domains != nil {
+		opts.LookupTXT = func(name string) ([]string, error) { return s.lookupKey(ctx, name) }
+	}
+	vs, err := msgauth.VerifyWithOptions(bytes.NewReader(raw), opts)
+	if err != nil {
+		return []DKIMCheck{{Error: err.Error()}}
+	}
+	out := make([]DKIMCheck, 0, len(vs))
+	for _, v := range vs {
+		c := DKIMCheck{Domain: v.Domain, Valid: v.Err == nil}
+		if v.Err != nil {
+			c.Error = v.Err.Error()
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// keyLookupTimeout bounds reading a Domain for one signature.
+const keyLookupTimeout = 5 * time.Second
+
+// lookupKey answers a DKIM key record lookup with the record the Domain
+// would publish for the selector: any key the Domain has under it, whatever
+// its state, so that a message signed before a rotation still verifies.
+func (s *Sink) lookupKey(ctx context.Context, name string) ([]string, error) {
+	selector, domain, ok := strings.Cut(name, "._domainkey.")
+	if !ok {
+		return nil, fmt.Errorf("%s is not a DKIM key record", name)
+	}
+	dn, err := values.Parse(domain)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, keyLookupTimeout)
+	defer cancel()
+	d, err := s.domains.FindByName(ctx, dn)
+	if err != nil {
+		return nil, fmt.Errorf("domain %s: %w", domain, err)
+	}
+	k, ok := d.DKIMKey(selector)
+	if !ok {
+		return nil, errors.New("domain " + domain + " has no DKIM key under selector " + selector)
+	}
+	return []string{k.RecordValue()}, nil
+}
+
+// regHref matches the value of an href attribute, quoted either way.
+var regHref = regexp.MustCompile(`(?i)\bhref\s*=\s*["']([^"']*)["']`)
+
+// regTracked matches the start of a tracked link or open pixel: the
+// Tracker's /c/ or /o/ on the tracking host of any Domain.
+var regTracked = regexp.MustCompile(`https?://stats\.[A-Za-z0-9.-]+/([co])/`)
+
+// local is link on the local Tracker, when it is a tracked link.
+func (s *Sink) local(link string) (string, bool) {
+	loc := regTracked.FindStringSubmatchIndex(link)
+	if loc == nil || loc[0] != 0 {
+		return "", false
+	}
+	return s.trackerBase() + link[loc[1]-3:], true
+}
+
+// localHTML is html with every tracked link and open pixel sent to the local
+// Tracker, so that opening the message and clicking in it are recorded as a
+// recipient's would be.
+func (s *Sink) localHTML(html string) string {
+	return regTracked.ReplaceAllString(html, s.trackerBase()+"/$1/")
+}
+
+func (s *Sink) trackerBase() string {
+	u, err := url.Parse(s.cfg.TrackerURL)
+	if err != nil {
+		return s.cfg.TrackerURL
+	}
+	return strings.TrimSuffix(u.String(), "/")
+}
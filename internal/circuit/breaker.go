@@ -0,0 +1,158 @@
+package circuit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/kvstate"
+	"github.com/kannon-email/kannon/internal/smtp"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// Bucket holds the circuits.
+	Bucket = "kannon-mx-circuits"
+
+	// entryTTL is how long an entry is kept after it was written. It has to
+	// outlast Window and OpenFor, and a probe; an open circuit forgotten
+	// because no mail was offered to its host for that long closes.
+	entryTTL = 2*maxSpan + probeTimeout
+
+	// kvTimeout bounds each read or write of the bucket. The Sender asks
+	// with no context of its own, on the path of every message.
+	kvTimeout = 2 * time.Second
+)
+
+// Breaker is the smtp.CircuitBreaker keeping circuits in a JetStream
+// key/value bucket, one kvstate entry per MX host.
+//
+// It fails open: a host whose circuit cannot be read is dialled, and a result
+// that cannot be written is lost. A bucket that cannot be reached is no reason
+// to stop sending mail, and costs no more than the timeouts a circuit would
+// have spared.
+type Breaker struct {
+	kv  jetstream.KeyValue
+	cfg Config
+	now func() time.Time
+
+	skipped, failures, opened, probes, recovered atomic.Uint64
+}
+
+var _ smtp.CircuitBreaker = (*Breaker)(nil)
+
+// OpenNATS opens the circuits' key/value bucket. It is held in memory: a
+// circuit lost to a restart opens again after Failures more timeouts.
+func OpenNATS(ctx context.Context, js jetstream.JetStream, cfg Config) (*Breaker, error) {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      Bucket,
+		Description: "Circuits of MX hosts that stopped answering",
+		TTL:         entryTTL,
+		Storage:     jetstream.MemoryStorage,
+		Replicas:    1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot open key/value bucket %s: %w", Bucket, err)
+	}
+	cfg.setDefaults()
+	return &Breaker{kv: kv, cfg: cfg, now: time.Now}, nil
+}
+
+// Allow implements smtp.CircuitBreaker.
+func (b *Breaker) Allow(mx string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), kvTimeout)
+	defer cancel()
+
+	var allowed bool
+	err := b.update(ctx, mx, func(prev *Circuit) (Circuit, bool) {
+		next, changed, ok := b.cfg.allow(prev, b.now())
+		allowed = ok
+		return next, changed
+	})
+	switch {
+	case err != nil:
+		slog.Error("MX circuits unavailable, dialling anyway", "mx", mx, "err", err)
+		return true
+	case !allowed:
+		b.skipped.Add(1)
+	}
+	return allowed
+}
+
+// Record implements smtp.CircuitBreaker.
+func (b *Breaker) Record(mx string, failure error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kvTimeout)
+	defer cancel()
+
+	if failure != nil {
+		b.failures.Add(1)
+	}
+	var from, to State
+	err := b.update(ctx, mx, func(prev *Circuit) (Circuit, bool) {
+		from = Closed
+		if prev != nil {
+			from = prev.State
+		}
+		next, changed := b.cfg.record(prev, mx, failure, b.now())
+		to = next.State
+		return next, changed
+	})
+	if err != nil {
+		slog.Error("cannot record MX circuit", "mx", mx, "err", err)
+		return
+	}
+	switch {
+	case to == Open && from != Open:
+		b.opened.Add(1)
+		slog.Warn("MX host stopped answering, opening its circuit", "mx", mx, "from", from, "err", failure)
+	case to == Closed && from != Closed:
+		b.recovered.Add(1)
+		slog.Info("MX host answers again, closing its circuit", "mx", mx)
+	}
+}
+
+// Stats implements smtp.CircuitBreaker.
+func (b *Breaker) Stats() smtp.CircuitStats {
+	return smtp.CircuitStats{
+		Skipped:   b.skipped.Load(),
+		Failures:  b.failures.Load(),
+		Opened:    b.opened.Load(),
+		Probes:    b.probes.Load(),
+		Recovered: b.recovered.Load(),
+	}
+}
+
+// List returns every circuit not closed, the one opened last first.
+func (b *Breaker) List(ctx context.Context) ([]Circuit, error) {
+	all, err := kvstate.List[Circuit](ctx, b.kv)
+	if err != nil {
+		return nil, err
+	}
+	out := slices.DeleteFunc(all, func(c Circuit) bool { return c.State == Closed })
+	slices.SortFunc(out, func(a, b Circuit) int { return b.OpenedAt.Compare(a.OpenedAt) })
+	return out, nil
+}
+
+// update applies fn to mx's circuit and writes what it returns when it
+// reports a change. A probe is counted once its half-open state is written:
+// that write is what makes this replica the one probing.
+func (b *Breaker) update(ctx context.Context, mx string, fn func(prev *Circuit) (Circuit, bool)) error {
+	next, written, err := kvstate.Update(ctx, b.kv, key(mx), func(prev *Circuit) (Circuit, bool) {
+		next, changed := fn(prev)
+		next.MX = mx
+		return next, changed
+	})
+	if written && next.State == HalfOpen {
+		b.probes.Add(1)
+	}
+	return err
+}
+
+// key names mx's entry; the name itself is kept in the entry.
+func key(mx string) string {
+	return kvstate.Key("mx", strings.ToLower(strings.TrimSuffix(mx, ".")))
+}
@@ -0,0 +1,183 @@
+// Package circuit keeps the Sender from waiting on MX hosts that do not
+// answer.
+//
+// A host that is down, or that accepts connections and never greets, costs
+// every message offered to it the whole of a dial or a session timeout — two
+// minutes of a worker — before the message falls back to the next host. So
+// each MX host has a circuit: closed, it is dialled as usual; once it has
+// failed to answer Failures times within Window it opens, and the Sender
+// passes it over without dialling; after OpenFor it is half-open, and one
+// message — across every replica — is let through to probe it. The probe
+// answering closes the circuit; the probe failing opens it again.
+//
+// Circuits are kept in a NATS key/value bucket, so a host one replica found
+// dead is skipped by all of them, and the HZ service lists the open ones from
+// the API process.
+package circuit
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config is the `sender.circuit` section.
+type Config struct {
+	// Disabled dials every MX host however often it has failed to answer.
+	// Circuits are on unless it is set.
+	Disabled bool `mapstructure:"disabled"`
+	// Failures is how many times a host has to fail to answer within Window
+	// for its circuit to open: 5 when zero.
+	Failures int `mapstructure:"failures"`
+	// Window is the span failures are counted over: 1m when zero.
+	Window time.Duration `mapstructure:"window"`
+	// OpenFor is how long an open circuit passes its host over before one
+	// message probes it: 5m when zero.
+	OpenFor time.Duration `mapstructure:"open_for"`
+}
+
+// Defaults.
+const (
+	DefaultFailures = 5
+	DefaultWindow   = time.Minute
+	DefaultOpenFor  = 5 * time.Minute
+)
+
+// maxSpan bounds Window and OpenFor: an entry has to outlive both, and the
+// bucket forgets it entryTTL after it was written.
+const maxSpan = 30 * time.Minute
+
+// Validate reports a configuration circuits cannot be kept to.
+func (c Config) Validate() error {
+	if c.Failures < 0 || c.Window < 0 || c.OpenFor < 0 {
+		return fmt.Errorf("failures, window and open_for cannot be negative")
+	}
+	if c.Window > maxSpan || c.OpenFor > maxSpan {
+		return fmt.Errorf("window and open_for cannot be longer than %v", maxSpan)
+	}
+	return nil
+}
+
+func (c *Config) setDefaults() {
+	if c.Failures == 0 {
+		c.Failures = DefaultFailures
+	}
+	if c.Window == 0 {
+		c.Window = DefaultWindow
+	}
+	if c.OpenFor == 0 {
+		c.OpenFor = DefaultOpenFor
+	}
+}
+
+// State is where a circuit is.
+type State string
+
+const (
+	// Closed is a host dialled as usual.
+	Closed State = "closed"
+	// Open is a host passed over without being dialled.
+	Open State = "open"
+	// HalfOpen is a host one message is probing.
+	HalfOpen State = "half_open"
+)
+
+// probeTimeout is how long a probe is waited for before another message may
+// take it over: longer than the Sender gives a dial and a session together,
+// so that only a replica that died mid-probe loses it.
+const probeTimeout = 3 * time.Minute
+
+// Circuit is one MX host's circuit.
+type Circuit struct {
+	MX    string `json:"mx"`
+	State State  `json:"state"`
+	// Failures are the host's failures to answer in the window that started
+	// at WindowStart, while the circuit is closed.
+	Failures    int       `json:"failures,omitempty"`
+	WindowStart time.Time `json:"window_start,omitzero"`
+	// OpenedAt is when the circuit last opened, and RetryAt when an open
+	// circuit lets a probe through.
+	OpenedAt time.Time `json:"opened_at,omitzero"`
+	RetryAt  time.Time `json:"retry_at,omitzero"`
+	// ProbeAt is when the probe of a half-open circuit started.
+	ProbeAt time.Time `json:"probe_at,omitzero"`
+	// Reason is the failure that last opened it.
+	Reason string `json:"reason,omitempty"`
+}
+
+// allow is whether a message may be offered to the host of c — nil for a
+// host with no circuit yet — at now, and the circuit as the decision leaves
+// it. It reports false as its second value when the decision changes nothing
+// to be written.
+//
+// An open circuit whose OpenFor is over lets the one message through that
+// turns it half-open: that message is the probe, and every other is passed
+// over until it is done — or until probeTimeout says it never will be.
+func (c Config) allow(prev *Circuit, now time.Time) (next Circuit, changed, allowed bool) {
+	if prev == nil {
+		return Circuit{}, false, true
+	}
+	switch prev.State {
+	case Open:
+		if now.Before(prev.RetryAt) {
+			return *prev, false, false
+		}
+	case HalfOpen:
+		if now.Before(prev.ProbeAt.Add(probeTimeout)) {
+			return *prev, false, false
+		}
+	default:
+		return *prev, false, true
+	}
+	next = *prev
+	next.State = HalfOpen
+	next.ProbeAt = now
+	return next, true, true
+}
+
+// record is c — nil for a host with no circuit yet — once a message offered
+// to mx at now has found it answering, or not when failure is set. It reports
+// false when nothing changes.
+//
+// A host that answered closes its circuit, whatever state it was in: the
+// answer may be a refusal, but a host that refuses is up. A failure while the
+// circuit is open comes from a message offered before it opened, and changes
+// nothing.
+func (c Config) record(prev *Circuit, mx string, failure error, now time.Time) (Circuit, bool) {
+	if failure == nil {
+		if prev == nil || (prev.State == Closed && prev.Failures == 0) {
+			return Circuit{}, false
+		}
+		return Circuit{MX: mx, State: Closed}, true
+	}
+
+	next := Circuit{MX: mx, State: Closed}
+	if prev != nil {
+		next = *prev
+	}
+	switch next.State {
+	case Open:
+		return next, false
+	case HalfOpen:
+		return c.open(next, failure, now), true
+	}
+	if next.WindowStart.IsZero() || !now.Before(next.WindowStart.Add(c.Window)) {
+		next.WindowStart = now
+		next.Failures = 0
+	}
+	next.Failures++
+	if next.Failures >= c.Failures {
+		return c.open(next, failure, now), true
+	}
+	return next, true
+}
+
+func (c Config) open(next Circuit, failure error, now time.Time) Circuit {
+	next.State = Open
+	next.Failures = 0
+	next.WindowStart = time.Time{}
+	next.OpenedAt = now
+	next.RetryAt = now.Add(c.OpenFor)
+	next.ProbeAt = time.Time{}
+	next.Reason = failure.Error()
+	return next
+}
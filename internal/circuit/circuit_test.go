@@ -0,0 +1,117 @@
+package circuit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errRefused = errors.New("dial tcp 192.0.2.1:25: connect: connection refused")
+
+// Failures open a circuit only when Failures of them fall within one Window:
+// a host that times out now and then is never passed over.
+func TestFailuresAreCountedWithinTheWindow(t *testing.T) {
+	cfg := Config{Failures: 3, Window: time.Minute, OpenFor: 5 * time.Minute}
+	start := time.Unix(0, 0).UTC()
+
+	var c *Circuit
+	fail := func(after time.Duration) State {
+		next, changed := cfg.record(c, "mx.test", errRefused, start.Add(after))
+		require.True(t, changed)
+		c = &next
+		return next.State
+	}
+	fail(0)
+	fail(30 * time.Second)
+	assert.Equal(t, Closed, fail(90*time.Second), "the window that counted two is over")
+	assert.Equal(t, 1, c.Failures)
+	assert.Equal(t, start.Add(90*time.Second), c.WindowStart)
+
+	fail(100 * time.Second)
+	require.Equal(t, Open, fail(110*time.Second), "three within a minute of each other")
+	assert.Equal(t, errRefused.Error(), c.Reason)
+	assert.Equal(t, start.Add(110*time.Second+5*time.Minute), c.RetryAt)
+
+	_, changed := cfg.record(c, "mx.test", errRefused, start.Add(111*time.Second))
+	assert.False(t, changed, "a failure while open comes from a message offered before")
+}
+
+// A host that answers — even with a refusal — starts its count over, and is
+// not written at all when it had nothing counted.
+func TestAnswerResetsTheCount(t *testing.T) {
+	cfg := Config{Failures: 2, Window: time.Minute}
+	now := time.Unix(0, 0).UTC()
+
+	_, changed := cfg.record(nil, "mx.test", nil, now)
+	assert.False(t, changed, "a host that answers as usual is not written")
+
+	failed, _ := cfg.record(nil, "mx.test", errRefused, now)
+	reset, changed := cfg.record(&failed, "mx.test", nil, now.Add(time.Second))
+	require.True(t, changed)
+	assert.Equal(t, Circuit{MX: "mx.test", State: Closed}, reset)
+
+	again, _ := cfg.record(&reset, "mx.test", errRefused, now.Add(2*time.Second))
+	assert.Equal(t, Closed, again.State, "the failure before the answer no longer counts")
+}
+
+// Once OpenFor is over one message, across every Breaker on the bucket,
+// probes the host; the rest are passed over until it is heard of or given up
+// on. The probe's outcome closes the circuit or opens it again.
+func TestHalfOpenCircuitLetsOneProbeThrough(t *testing.T) {
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+	cfg := Config{Failures: 1, OpenFor: time.Minute}
+	now := time.Unix(0, 0).UTC()
+	clock := func() time.Time { return now }
+
+	b, err := OpenNATS(ctx, js, cfg)
+	require.NoError(t, err)
+	b.now = clock
+	other, err := OpenNATS(ctx, js, cfg)
+	require.NoError(t, err)
+	other.now = clock
+
+	b.Record("MX1.Example.com.", errRefused)
+	assert.False(t, b.Allow("mx1.example.com"), "the host is passed over under any spelling")
+	assert.True(t, b.Allow("mx2.example.com"))
+
+	now = now.Add(time.Minute)
+	require.True(t, b.Allow("mx1.example.com"), "one message probes the host")
+	assert.False(t, b.Allow("mx1.example.com"))
+	assert.False(t, other.Allow("mx1.example.com"), "the probe is the only one on every replica")
+
+	now = now.Add(probeTimeout)
+	require.True(t, other.Allow("mx1.example.com"), "a probe never heard of is taken over")
+	other.Record("mx1.example.com", errRefused)
+	list, err := b.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, Open, list[0].State, "a failed probe opens the circuit again")
+	assert.Equal(t, now.Add(time.Minute), list[0].RetryAt)
+
+	now = now.Add(time.Minute)
+	require.True(t, b.Allow("mx1.example.com"))
+	b.Record("mx1.example.com", nil)
+	assert.True(t, other.Allow("mx1.example.com"), "a probe that answers closes the circuit")
+	list, err = b.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	assert.Equal(t, uint64(2), b.Stats().Probes)
+	assert.Equal(t, uint64(1), other.Stats().Probes)
+	assert.Equal(t, uint64(1), b.Stats().Recovered)
+	assert.Equal(t, uint64(2), b.Stats().Skipped)
+}
+
+// An entry has to outlive Window and OpenFor, and the bucket forgets it
+// entryTTL after it was written.
+func TestWindowAndOpenForFitTheBucket(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{Failures: 3, Window: 10 * time.Minute, OpenFor: maxSpan}.Validate())
+	assert.Error(t, Config{Failures: -1}.Validate())
+	assert.Error(t, Config{OpenFor: time.Hour}.Validate(), "an entry would be forgotten while its circuit is open")
+}
@@ -0,0 +1,154 @@
+// Package cooldown keeps the destinations that have told the SMTPSender to
+// slow down.
+//
+// A provider throttling a sender does not throttle one message: Gmail's
+// 421 4.7.28 and Outlook's 451 4.7.500 are about everything the sending
+// address offers it, and every further attempt while the throttle lasts is one
+// more deferral counted against that address's reputation. So a throttling
+// reply puts the whole destination — a `sender.throttle` group, or the
+// recipient domain — into a cool-down during which nothing is offered to it,
+// and a destination that throttles again as soon as its cool-down is over is
+// put into a longer one. The cool-downs are kept in a NATS key/value bucket, so
+// a reply one replica received holds every replica back, and the HZ service
+// reports them from the API process.
+package cooldown
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config is the `sender.cooldown` section.
+type Config struct {
+	// Disabled offers every Envelope to its destination whatever the
+	// destination last replied. Cool-downs are on unless it is set.
+	Disabled bool `mapstructure:"disabled"`
+	// Initial is the first cool-down of a destination: 1m when zero.
+	Initial time.Duration `mapstructure:"initial"`
+	// Max caps a cool-down however often the destination has throttled: 1h
+	// when zero.
+	Max time.Duration `mapstructure:"max"`
+	// ResetAfter is how long a destination has to go without throttling,
+	// once its cool-down is over, for the next one to start again from
+	// Initial: 1h when zero.
+	ResetAfter time.Duration `mapstructure:"reset_after"`
+}
+
+// Defaults.
+const (
+	DefaultInitial    = time.Minute
+	DefaultMax        = time.Hour
+	DefaultResetAfter = time.Hour
+)
+
+// maxSpan bounds Max and ResetAfter each. An entry outlives its cool-down by
+// ResetAfter, and the bucket forgets an entry entryTTL after it was written,
+// which has to be after both are over.
+const maxSpan = 12 * time.Hour
+
+// Validate reports a configuration the cool-downs cannot be kept to.
+func (c Config) Validate() error {
+	if c.Initial < 0 || c.Max < 0 || c.ResetAfter < 0 {
+		return fmt.Errorf("initial, max and reset_after cannot be negative")
+	}
+	if c.Max > maxSpan || c.ResetAfter > maxSpan {
+		return fmt.Errorf("max and reset_after cannot be longer than %v", maxSpan)
+	}
+	longest := c.Max
+	if longest == 0 {
+		longest = DefaultMax
+	}
+	if c.Initial > longest {
+		return fmt.Errorf("initial (%v) cannot be longer than max (%v)", c.Initial, longest)
+	}
+	return nil
+}
+
+func (c *Config) setDefaults() {
+	if c.Initial == 0 {
+		c.Initial = DefaultInitial
+	}
+	if c.Max == 0 {
+		c.Max = DefaultMax
+	}
+	if c.ResetAfter == 0 {
+		c.ResetAfter = DefaultResetAfter
+	}
+}
+
+// Reply is a throttling reply, as it is recorded on the cool-down it starts.
+type Reply struct {
+	// Code is its enhanced status code, 4.7.28 for Gmail's.
+	Code string `json:"code,omitempty"`
+	// Text is the reply as the host wrote it.
+	Text string `json:"text,omitempty"`
+	// MX is the host that gave it.
+	MX string `json:"mx,omitempty"`
+}
+
+// Cooldown is one destination's cool-down.
+type Cooldown struct {
+	Destination string `json:"destination"`
+	// Level is how many times in a row the destination has throttled again
+	// once a cool-down was over: 0 for a first cool-down, which lasts
+	// Initial, and each level twice as long as the one before, up to Max.
+	Level int `json:"level"`
+	// Since is when the cool-down started, and Until when it ends.
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+	// Reply is the reply that started it.
+	Reply Reply `json:"reply"`
+}
+
+// Active reports whether the destination is still cooling down at now.
+func (c *Cooldown) Active(now time.Time) bool {
+	return c != nil && now.Before(c.Until)
+}
+
+// Remaining is how long the cool-down has left at now, zero once it is over.
+func (c *Cooldown) Remaining(now time.Time) time.Duration {
+	if !c.Active(now) {
+		return 0
+	}
+	return c.Until.Sub(now)
+}
+
+// next is the cool-down a throttling reply received at now puts the
+// destination into, given the one it was in, nil for none. It reports false
+// when the reply changes nothing.
+//
+// A reply received while the destination is still cooling down changes
+// nothing: it comes from a send that started before the cool-down did, and
+// says no more than the reply that started it. One received once the
+// cool-down is over means the destination was not done throttling, and
+// escalates to the next level — unless the destination went ResetAfter
+// without throttling, which starts it over from the first.
+func (c Config) next(prev *Cooldown, dest string, r Reply, now time.Time) (Cooldown, bool) {
+	if prev.Active(now) {
+		return *prev, false
+	}
+	level := 0
+	if prev != nil && now.Before(prev.Until.Add(c.ResetAfter)) {
+		level = prev.Level + 1
+	}
+	return Cooldown{
+		Destination: dest,
+		Level:       level,
+		Since:       now,
+		Until:       now.Add(c.span(level)),
+		Reply:       r,
+	}, true
+}
+
+// span is how long a cool-down of level lasts: Initial doubled level times,
+// and no longer than Max.
+func (c Config) span(level int) time.Duration {
+	d := c.Initial
+	for range level {
+		if d >= c.Max/2 {
+			return c.Max
+		}
+		d *= 2
+	}
+	return min(d, c.Max)
+}
@@ -0,0 +1,142 @@
+package cooldown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/authz"
+	"github.com/kannon-email/kannon/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var gmailThrottle = Reply{Code: "4.7.28", Text: "421-4.7.28 Our system has detected an unusual rate of unsolicited mail", MX: "gmail-smtp-in.l.google.com"}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{Initial: 30 * time.Second, Max: 2 * time.Hour, ResetAfter: 6 * time.Hour}.Validate())
+	assert.Error(t, Config{Initial: -time.Second}.Validate())
+	assert.Error(t, Config{Max: 24 * time.Hour}.Validate(), "an entry would be forgotten before its cool-down ends")
+	assert.Error(t, Config{Initial: 2 * time.Hour}.Validate(), "initial is longer than the default max")
+}
+
+func TestCooldownsEscalateAndReset(t *testing.T) {
+	cfg := Config{Initial: time.Minute, Max: 10 * time.Minute, ResetAfter: time.Hour}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	first, changed := cfg.next(nil, "google", gmailThrottle, now)
+	require.True(t, changed)
+	assert.Equal(t, 0, first.Level)
+	assert.Equal(t, now.Add(time.Minute), first.Until)
+
+	_, changed = cfg.next(&first, "google", gmailThrottle, now.Add(30*time.Second))
+	assert.False(t, changed, "a reply during the cool-down comes from a send that started before it")
+
+	c := first
+	var spans []time.Duration
+	for range 5 {
+		at := c.Until.Add(time.Second)
+		c, _ = cfg.next(&c, "google", gmailThrottle, at)
+		spans = append(spans, c.Until.Sub(at))
+	}
+	assert.Equal(t, []time.Duration{2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute}, spans,
+		"each throttle once a cool-down is over doubles the next, up to max")
+
+	quiet, _ := cfg.next(&c, "google", gmailThrottle, c.Until.Add(time.Hour))
+	assert.Equal(t, 0, quiet.Level, "a destination quiet for reset_after starts over")
+	assert.Equal(t, time.Minute, quiet.Until.Sub(quiet.Since))
+}
+
+func TestStoreSharesCooldownsAcrossReplicas(t *testing.T) {
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	open := func() *kvStore {
+		s, err := OpenNATS(ctx, js, Config{})
+		require.NoError(t, err)
+		kv := s.(*kvStore)
+		kv.now = func() time.Time { return now }
+		return kv
+	}
+	replicaA, replicaB := open(), open()
+
+	none, err := replicaB.Get(ctx, "google")
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	started, err := replicaA.Throttled(ctx, "google", gmailThrottle)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(DefaultInitial), started.Until)
+
+	seen, err := replicaB.Get(ctx, "google")
+	require.NoError(t, err)
+	require.NotNil(t, seen)
+	assert.Equal(t, time.Minute, seen.Remaining(now), "a reply one replica received holds the others back")
+	assert.Equal(t, gmailThrottle, seen.Reply)
+
+	_, err = replicaB.Throttled(ctx, "outlook.com", Reply{Code: "4.7.500"})
+	require.NoError(t, err)
+	now = now.Add(90 * time.Second)
+	escalated, err := replicaB.Throttled(ctx, "google", gmailThrottle)
+	require.NoError(t, err)
+	assert.Equal(t, 1, escalated.Level)
+
+	list, err := replicaA.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1, "outlook.com's cool-down is over")
+	assert.Equal(t, "google", list[0].Destination)
+}
+
+// A cool-down holds back its own destination alone: internationalized domains
+// that share no character a key may hold are kept apart.
+func TestCooldownsOfIDNDestinationsAreKeptApart(t *testing.T) {
+	ctx := t.Context()
+	s, err := OpenNATS(ctx, tests.NatsJetStream(t), Config{})
+	require.NoError(t, err)
+	assert.NotEqual(t, key("例子.中国"), key("中国.中国"))
+
+	_, err = s.Throttled(ctx, "例子.中国", gmailThrottle)
+	require.NoError(t, err)
+	other, err := s.Get(ctx, "中国.中国")
+	require.NoError(t, err)
+	assert.Nil(t, other, "a cool-down of one domain holds back no other")
+}
+
+// listStore is a Store whose List returns what it holds.
+type listStore []Cooldown
+
+func (s listStore) Get(context.Context, string) (*Cooldown, error) { return nil, nil }
+func (s listStore) Throttled(context.Context, string, Reply) (Cooldown, error) {
+	return Cooldown{}, nil
+}
+func (s listStore) List(context.Context) ([]Cooldown, error) { return s, nil }
+
+// The destinations, and the MX host that throttled, are the root's to read;
+// how many there are is anybody's, since it names none of them.
+func TestServiceListsForTheRootAndCountsForAnybody(t *testing.T) {
+	service := NewService(true, listStore{{Destination: "google", Reply: gmailThrottle}})
+
+	_, err := service.List(t.Context())
+	assert.ErrorIs(t, err, authz.ErrNoPrincipal)
+
+	everyDomain := authz.MustNewPrincipal("every-domain-admin", authz.MustNewGrant(authz.RoleAdmin, authz.AllDomainsAnchor()))
+	_, err = service.List(authz.NewContext(t.Context(), everyDomain))
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+
+	list, err := service.List(tests.AdminContext(t.Context()))
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, gmailThrottle.MX, list[0].Reply.MX)
+
+	n, err := service.Count(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = NewService(true, nil).Count(t.Context())
+	assert.ErrorIs(t, err, ErrNoStore)
+
+	n, err = NewService(false, nil).Count(t.Context())
+	require.NoError(t, err)
+	assert.Zero(t, n, "cool-downs off are none")
+}
@@ -0,0 +1,54 @@
+package cooldown
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kannon-email/kannon/internal/authz"
+)
+
+// ErrNoStore is cool-downs that cannot be read, because the process reporting
+// them could not reach the bucket they are kept in.
+var ErrNoStore = errors.New("destination cool-downs are not available")
+
+// Service reports cool-downs on behalf of the Admin API and the HZ service.
+// The list is guarded like warm-up progress: a cool-down names a recipient
+// domain and the MX host that throttled the installation's addresses, which
+// are not any Domain's to read. How many there are is not, and is all health
+// reports.
+type Service struct {
+	enabled bool
+	store   Store
+}
+
+// NewService reports the cool-downs kept in store. A Service not enabled —
+// `sender.cooldown` off, or not readable — reports none; a nil store is
+// ErrNoStore.
+func NewService(enabled bool, store Store) *Service {
+	return &Service{enabled: enabled, store: store}
+}
+
+// List returns every destination cooling down now, the one cooling down
+// longest first.
+func (s *Service) List(ctx context.Context) ([]Cooldown, error) {
+	return authz.Guard(ctx, authz.Read, authz.SendingAddresses(), func() ([]Cooldown, error) {
+		return s.list(ctx)
+	})
+}
+
+// Count returns how many destinations are cooling down now. It names none, so
+// it is not guarded.
+func (s *Service) Count(ctx context.Context) (int, error) {
+	all, err := s.list(ctx)
+	return len(all), err
+}
+
+func (s *Service) list(ctx context.Context) ([]Cooldown, error) {
+	if !s.enabled {
+		return nil, nil
+	}
+	if s.store == nil {
+		return nil, ErrNoStore
+	}
+	return s.store.List(ctx)
+}
@@ -0,0 +1,88 @@
+package cooldown
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/kvstate"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Store keeps the cool-downs, across every replica.
+type Store interface {
+	// Get returns dest's cool-down, nil when it has none. A cool-down that is
+	// over is still returned until it is forgotten: what it escalates from.
+	Get(ctx context.Context, dest string) (*Cooldown, error)
+	// Throttled records a throttling reply from dest, and returns the
+	// cool-down it is in for it.
+	Throttled(ctx context.Context, dest string, r Reply) (Cooldown, error)
+	// List returns every destination cooling down now, the one cooling down
+	// longest first.
+	List(ctx context.Context) ([]Cooldown, error)
+}
+
+const (
+	// Bucket holds the cool-downs.
+	Bucket = "kannon-destination-cooldown"
+
+	// entryTTL is how long an entry is kept after it was written. It has to
+	// outlast the longest cool-down and the ResetAfter that follows it, or
+	// a destination that keeps throttling would start over from Initial.
+	entryTTL = 2*maxSpan + time.Hour
+)
+
+// OpenNATS opens the cool-downs' key/value bucket. It is held in memory, like
+// the throttle's: a cool-down lost to a restart is started again by the next
+// throttling reply, which costs one deferral.
+func OpenNATS(ctx context.Context, js jetstream.JetStream, cfg Config) (Store, error) {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      Bucket,
+		Description: "Destinations cooling down after a throttling reply",
+		TTL:         entryTTL,
+		Storage:     jetstream.MemoryStorage,
+		Replicas:    1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot open key/value bucket %s: %w", Bucket, err)
+	}
+	cfg.setDefaults()
+	return &kvStore{kv: kv, cfg: cfg, now: time.Now}, nil
+}
+
+// kvStore keeps each destination's cool-down under its key, as a kvstate
+// entry.
+type kvStore struct {
+	kv  jetstream.KeyValue
+	cfg Config
+	now func() time.Time
+}
+
+func (s *kvStore) Get(ctx context.Context, dest string) (*Cooldown, error) {
+	c, _, err := kvstate.Get[Cooldown](ctx, s.kv, key(dest))
+	return c, err
+}
+
+func (s *kvStore) Throttled(ctx context.Context, dest string, r Reply) (Cooldown, error) {
+	c, _, err := kvstate.Update(ctx, s.kv, key(dest), func(prev *Cooldown) (Cooldown, bool) {
+		return s.cfg.next(prev, dest, r, s.now())
+	})
+	return c, err
+}
+
+func (s *kvStore) List(ctx context.Context) ([]Cooldown, error) {
+	all, err := kvstate.List[Cooldown](ctx, s.kv)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	out := slices.DeleteFunc(all, func(c Cooldown) bool { return !c.Active(now) })
+	slices.SortFunc(out, func(a, b Cooldown) int { return b.Until.Compare(a.Until) })
+	return out, nil
+}
+
+// key names dest's entry; the name itself is kept in the entry.
+func key(dest string) string {
+	return kvstate.Key("dest", dest)
+}
@@ -12,9 +12,9 @@ import (
 )
 
 const incrementAggregatedStat = `-- name: IncrementAggregatedStat :exec
-INSERT INTO aggregated_stats (domain, timestamp, type, count)
-VALUES ($1, $2, $3, 1)
-ON CONFLICT (domain, timestamp, type)
+INSERT INTO aggregated_stats (domain, timestamp, type, category, count)
+VALUES ($1, $2, $3, $4, 1)
+ON CONFLICT (domain, timestamp, type, category)
 DO UPDATE SET count = aggregated_stats.count + 1
 `
 
@@ -22,18 +22,24 @@ type IncrementAggregatedStatParams struct {
 	Domain    string
 	Timestamp pgtype.Timestamp
 	Type      StatsType
+	Category  string
 }
 
 func (q *Queries) IncrementAggregatedStat(ctx context.Context, arg IncrementAggregatedStatParams) error {
-	_, err := q.db.Exec(ctx, incrementAggregatedStat, arg.Domain, arg.Timestamp, arg.Type)
+	_, err := q.db.Exec(ctx, incrementAggregatedStat,
+		arg.Domain,
+		arg.Timestamp,
+		arg.Type,
+		arg.Category,
+	)
 	return err
 }
 
 const queryAggregatedStats = `-- name: QueryAggregatedStats :many
-SELECT domain, timestamp, type, count FROM aggregated_stats
+SELECT domain, timestamp, type, count, category FROM aggregated_stats
 WHERE domain = $1
 AND timestamp >= $2 AND timestamp < $3
-ORDER BY timestamp ASC, type
+ORDER BY timestamp ASC, type, category
 `
 
 type QueryAggregatedStatsParams struct {
@@ -56,6 +62,7 @@ func (q *Queries) QueryAggregatedStats(ctx context.Context, arg QueryAggregatedS
 			&i.Timestamp,
 			&i.Type,
 			&i.Count,
+			&i.Category,
 		); err != nil {
 			return nil, err
 		}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/stats"
 	"github.com/kannon-email/kannon/internal/values"
 )
@@ -19,12 +20,13 @@ func NewAggregatedStatsRepository(db *pgxpool.Pool) *AggregatedStatsRepository {
 	return &AggregatedStatsRepository{db: db}
 }
 
-func (r *AggregatedStatsRepository) Increment(ctx context.Context, domain values.DomainName, timestamp time.Time, statType stats.Type) error {
+func (r *AggregatedStatsRepository) Increment(ctx context.Context, domain values.DomainName, timestamp time.Time, statType stats.Type, category bounce.Category) error {
 	q := New(r.db)
 	return q.IncrementAggregatedStat(ctx, IncrementAggregatedStatParams{
 		Domain:    domain.String(),
 		Timestamp: pgtype.Timestamp{Time: timestamp, Valid: true},
 		Type:      StatsType(statType),
+		Category:  string(category),
 	})
 }
 
@@ -43,6 +45,7 @@ func (r *AggregatedStatsRepository) Query(ctx context.Context, domain values.Dom
 	for _, row := range rows {
 		result = append(result, &stats.AggregatedStat{
 			Type:      stats.Type(row.Type),
+			Category:  bounce.Category(row.Category),
 			Timestamp: row.Timestamp.Time,
 			Count:     row.Count,
 		})
@@ -8,6 +8,8 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/kannon-email/kannon/internal/batch"
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
 )
 
 type batchRepository struct {
@@ -30,7 +32,7 @@ func (r *batchRepository) Create(ctx context.Context, b *batch.Batch) error {
 		TemplateID:  b.TemplateID(),
 		Domain:      b.Domain(),
 		Attachments: toSQLCAttachments(b.Attachments()),
-		Headers:     toSQLCHeaders(b.Headers(), b.OneClickUnsubscribe()),
+		Headers:     toSQLCHeaders(b),
 		Tracking:    b.TrackingPolicy(),
 	})
 	return err
@@ -61,7 +63,11 @@ func rowToBatch(row Message) *batch.Batch {
 		Attachments:         fromSQLCAttachments(row.Attachments),
 		Headers:             fromSQLCHeaders(row.Headers),
 		OneClickUnsubscribe: fromSQLCUnsubscribe(row.Headers),
+		Feedback:            fromSQLCBatchFeedback(row.Headers),
+		LinkParams:          linkparams.Params(row.Headers.LinkParams),
 		Tracking:            row.Tracking,
+		RequireTLS:          row.Headers.RequireTLS,
+		IPPool:              row.Headers.IPPool,
 	})
 }
 
@@ -87,14 +93,19 @@ func fromSQLCAttachments(a Attachments) batch.Attachments {
 	return out
 }
 
-// toSQLCHeaders folds both header-shaped statements of a Batch into the single
+// toSQLCHeaders folds every header-shaped statement of a Batch into the single
 // JSONB column that holds them. They are separate concepts in the domain but
 // share one column, so the mapping is the one place that knows it.
-func toSQLCHeaders(h batch.Headers, u batch.OneClickUnsubscribe) Headers {
-	out := Headers{To: h.To, Cc: h.Cc}
-	if !u.IsZero() {
+func toSQLCHeaders(b *batch.Batch) Headers {
+	h := b.Headers()
+	out := Headers{To: h.To, Cc: h.Cc, LinkParams: b.LinkParams(), RequireTLS: b.RequireTLS(), IPPool: b.IPPool()}
+	if u := b.OneClickUnsubscribe(); !u.IsZero() {
 		out.OneClickUnsubscribe = &OneClickUnsubscribe{URLTemplate: u.URLTemplate}
 	}
+	if f := b.Feedback(); !f.IsZero() {
+		stored := toSQLCFeedback(f)
+		out.Feedback = &stored
+	}
 	return out
 }
 
@@ -102,6 +113,15 @@ func fromSQLCHeaders(h Headers) batch.Headers {
 	return batch.Headers{To: h.To, Cc: h.Cc}
 }
 
+// fromSQLCBatchFeedback returns the zero Identity for a Batch that overrides
+// nothing of its Domain's, whether it was stored before the key existed or not.
+func fromSQLCBatchFeedback(h Headers) feedback.Identity {
+	if h.Feedback == nil {
+		return feedback.Identity{}
+	}
+	return h.Feedback.Identity()
+}
+
 // fromSQLCUnsubscribe returns the zero value for a Batch stored before the key
 // existed, which is the same thing as a Batch that states no endpoint.
 func fromSQLCUnsubscribe(h Headers) batch.OneClickUnsubscribe {
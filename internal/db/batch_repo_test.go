@@ -15,11 +15,7 @@ type batchTestHelper struct{}
 func (h batchTestHelper) CreateDomain(t *testing.T) string {
 	ctx := t.Context()
 	domainName := fmt.Sprintf("test-batch-%d.com", time.Now().UnixNano())
-	_, err := q.CreateDomain(ctx, CreateDomainParams{
-		Domain:         domainName,
-		DkimPrivateKey: "test-private",
-		DkimPublicKey:  "test-public",
-	})
+	_, err := q.CreateDomain(ctx, domainName)
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		cleanupCtx := context.Background()
@@ -37,6 +37,7 @@ func (r iteratorForCreatePool) Values() ([]interface{}, error) {
 		r.rows[0].Fields,
 		r.rows[0].Domain,
 		r.rows[0].Tracking,
+		r.rows[0].SmimeCertificate,
 	}, nil
 }
 
@@ -45,5 +46,5 @@ func (r iteratorForCreatePool) Err() error {
 }
 
 func (q *Queries) CreatePool(ctx context.Context, arg []CreatePoolParams) (int64, error) {
-	return q.db.CopyFrom(ctx, []string{"sending_pool_emails"}, []string{"email", "status", "scheduled_time", "original_scheduled_time", "message_id", "fields", "domain", "tracking"}, &iteratorForCreatePool{rows: arg})
+	return q.db.CopyFrom(ctx, []string{"sending_pool_emails"}, []string{"email", "status", "scheduled_time", "original_scheduled_time", "message_id", "fields", "domain", "tracking", "smime_certificate"}, &iteratorForCreatePool{rows: arg})
 }
@@ -42,11 +42,7 @@ func TestMain(m *testing.M) {
 
 func TestDomains(t *testing.T) {
 	// when user create a domain
-	domain, err := q.CreateDomain(t.Context(), CreateDomainParams{
-		Domain:         "test.com",
-		DkimPrivateKey: "test",
-		DkimPublicKey:  "test",
-	})
+	domain, err := q.CreateDomain(t.Context(), "test.com")
 	assert.Nil(t, err)
 	assert.Equal(t, domain.Domain, "test.com")
 
@@ -88,11 +84,7 @@ func TestHashKeyMatchesPostgres(t *testing.T) {
 }
 
 func TestTemplates(t *testing.T) {
-	domain, err := q.CreateDomain(t.Context(), CreateDomainParams{
-		Domain:         "test.com",
-		DkimPrivateKey: "test",
-		DkimPublicKey:  "test",
-	})
+	domain, err := q.CreateDomain(t.Context(), "test.com")
 	assert.Nil(t, err)
 	assert.Equal(t, domain.Domain, "test.com")
 
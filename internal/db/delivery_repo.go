@@ -51,6 +51,7 @@ func (r *deliveryRepository) Schedule(ctx context.Context, ds ...*delivery.Deliv
 			Fields:                toCustomFields(d.Fields()),
 			Domain:                d.Domain(),
 			Tracking:              d.TrackingPolicy(),
+			SmimeCertificate:      d.SMIMECertificate(),
 		}
 	}
 
@@ -115,6 +116,15 @@ func (r *deliveryRepository) Reschedule(ctx context.Context, batchID batch.ID, e
 	})
 }
 
+func (r *deliveryRepository) Postpone(ctx context.Context, batchID batch.ID, email string, until time.Time) error {
+	q := New(r.db)
+	return q.PostponePool(ctx, PostponePoolParams{
+		Email:         email,
+		MessageID:     batchID.String(),
+		ScheduledTime: PgTimestampFromTime(until.UTC()),
+	})
+}
+
 func (r *deliveryRepository) Clean(ctx context.Context, batchID batch.ID, email string) error {
 	q := New(r.db)
 	return q.CleanPool(ctx, CleanPoolParams{
@@ -196,6 +206,7 @@ func (r *deliveryRepository) rowToDelivery(row SendingPoolEmail) *delivery.Deliv
 		Backoff:               r.backoff,
 		RetryWindow:           r.retryWindow,
 		Tracking:              row.Tracking,
+		SMIMECertificate:      row.SmimeCertificate,
 	})
 }
 
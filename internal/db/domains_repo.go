@@ -6,9 +6,14 @@ import (
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/kannon-email/kannon/internal/domains"
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/smime"
 	"github.com/kannon-email/kannon/internal/tracking"
 	"github.com/kannon-email/kannon/internal/values"
 )
@@ -22,17 +27,37 @@ func NewDomainsRepository(db *pgxpool.Pool) domains.Repository {
 	return &domainsRepository{db: db}
 }
 
+// Create writes the Domain and its keys in one transaction, so a Domain is never visible
+// without the key it was created to sign with.
 func (r *domainsRepository) Create(ctx context.Context, d *domains.Domain) error {
-	q := New(r.db)
-	row, err := q.CreateDomain(ctx, CreateDomainParams{
-		Domain:         d.Name().String(),
-		DkimPrivateKey: d.DkimPrivateKey(),
-		DkimPublicKey:  d.DkimPublicKey(),
-	})
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	//nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	txq := New(r.db).WithTx(tx)
+
+	row, err := txq.CreateDomain(ctx, d.Name().String())
 	if err != nil {
 		return err
 	}
-	loaded, err := rowToDomain(row)
+	keys := make([]DkimKey, 0, len(d.DKIMKeys()))
+	for _, k := range d.DKIMKeys() {
+		key, err := txq.CreateDKIMKey(ctx, createDKIMKeyParams(d.Name(), k))
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	loaded, err := rowToDomain(row, keys)
 	if err != nil {
 		return err
 	}
@@ -40,6 +65,97 @@ func (r *domainsRepository) Create(ctx context.Context, d *domains.Domain) error
 	return nil
 }
 
+func (r *domainsRepository) SetReturnPathDomain(ctx context.Context, domain values.DomainName, rp values.DomainName) (*domains.Domain, error) {
+	q := New(r.db)
+	row, err := q.SetDomainReturnPath(ctx, SetDomainReturnPathParams{
+		Domain:           domain.String(),
+		ReturnPathDomain: pgtype.Text{String: rp.String(), Valid: !rp.IsZero()},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domains.ErrDomainNotFound
+		}
+		return nil, err
+	}
+	return r.withKeys(ctx, q, row)
+}
+
+func (r *domainsRepository) SetFeedbackIdentity(ctx context.Context, domain values.DomainName, id feedback.Identity) (*domains.Domain, error) {
+	q := New(r.db)
+	row, err := q.SetDomainFeedback(ctx, SetDomainFeedbackParams{
+		Domain:   domain.String(),
+		Feedback: toSQLCFeedback(id),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domains.ErrDomainNotFound
+		}
+		return nil, err
+	}
+	return r.withKeys(ctx, q, row)
+}
+
+func (r *domainsRepository) SetSMIME(ctx context.Context, domain values.DomainName, c smime.Credentials) (*domains.Domain, error) {
+	q := New(r.db)
+	row, err := q.SetDomainSMIME(ctx, SetDomainSMIMEParams{
+		Domain:           domain.String(),
+		SmimeCertificate: pgtype.Text{String: c.Certificate, Valid: !c.IsZero()},
+		SmimePrivateKey:  pgtype.Text{String: c.PrivateKey, Valid: !c.IsZero()},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domains.ErrDomainNotFound
+		}
+		return nil, err
+	}
+	return r.withKeys(ctx, q, row)
+}
+
+func (r *domainsRepository) SetLinkDecoration(ctx context.Context, domain values.DomainName, d linkparams.Decoration) (*domains.Domain, error) {
+	q := New(r.db)
+	row, err := q.SetDomainLinkDecoration(ctx, SetDomainLinkDecorationParams{
+		Domain:         domain.String(),
+		LinkDecoration: toSQLCLinkDecoration(d),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domains.ErrDomainNotFound
+		}
+		return nil, err
+	}
+	return r.withKeys(ctx, q, row)
+}
+
+func (r *domainsRepository) SetRequireTLS(ctx context.Context, domain values.DomainName, require bool) (*domains.Domain, error) {
+	q := New(r.db)
+	row, err := q.SetDomainRequireTLS(ctx, SetDomainRequireTLSParams{
+		Domain:     domain.String(),
+		RequireTls: require,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domains.ErrDomainNotFound
+		}
+		return nil, err
+	}
+	return r.withKeys(ctx, q, row)
+}
+
+func (r *domainsRepository) SetIPPool(ctx context.Context, domain values.DomainName, pool string) (*domains.Domain, error) {
+	q := New(r.db)
+	row, err := q.SetDomainIPPool(ctx, SetDomainIPPoolParams{
+		Domain: domain.String(),
+		IpPool: pool,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domains.ErrDomainNotFound
+		}
+		return nil, err
+	}
+	return r.withKeys(ctx, q, row)
+}
+
 func (r *domainsRepository) SetTrackingPolicy(ctx context.Context, domain values.DomainName, p tracking.Policy) (*domains.Domain, error) {
 	q := New(r.db)
 	row, err := q.SetDomainTracking(ctx, SetDomainTrackingParams{
@@ -52,7 +168,7 @@ func (r *domainsRepository) SetTrackingPolicy(ctx context.Context, domain values
 		}
 		return nil, err
 	}
-	return rowToDomain(row)
+	return r.withKeys(ctx, q, row)
 }
 
 func (r *domainsRepository) FindByName(ctx context.Context, domain values.DomainName) (*domains.Domain, error) {
@@ -64,7 +180,7 @@ func (r *domainsRepository) FindByName(ctx context.Context, domain values.Domain
 		}
 		return nil, err
 	}
-	return rowToDomain(row)
+	return r.withKeys(ctx, q, row)
 }
 
 func (r *domainsRepository) List(ctx context.Context) ([]*domains.Domain, error) {
@@ -73,9 +189,18 @@ func (r *domainsRepository) List(ctx context.Context) ([]*domains.Domain, error)
 	if err != nil {
 		return nil, err
 	}
+	// One query for every key rather than one per Domain; grouped here by name.
+	allKeys, err := q.GetAllDKIMKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keysByDomain := make(map[string][]DkimKey, len(rows))
+	for _, k := range allKeys {
+		keysByDomain[k.Domain] = append(keysByDomain[k.Domain], k)
+	}
 	out := make([]*domains.Domain, 0, len(rows))
 	for _, row := range rows {
-		d, err := rowToDomain(row)
+		d, err := rowToDomain(row, keysByDomain[row.Domain])
 		if err != nil {
 			return nil, err
 		}
@@ -84,23 +209,127 @@ func (r *domainsRepository) List(ctx context.Context) ([]*domains.Domain, error)
 	return out, nil
 }
 
-// rowToDomain rebuilds the entity from its row. The stored name goes back through
-// values.Parse rather than being trusted: a row predating the canonical form must not become
-// a Domain whose name no Grant and no query can match, so the data fault is named instead.
-func rowToDomain(row Domain) (*domains.Domain, error) {
+func (r *domainsRepository) AddDKIMKey(ctx context.Context, domain values.DomainName, k domains.DKIMKey) (*domains.Domain, error) {
+	q := New(r.db)
+	row, err := q.FindDomain(ctx, domain.String())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domains.ErrDomainNotFound
+		}
+		return nil, err
+	}
+	if _, err := q.CreateDKIMKey(ctx, createDKIMKeyParams(domain, k)); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "dkim_keys_domain_selector_key" {
+			return nil, domains.ErrDKIMSelectorTaken
+		}
+		return nil, err
+	}
+	return r.withKeys(ctx, q, row)
+}
+
+func (r *domainsRepository) ActivateDKIMKey(ctx context.Context, domain values.DomainName, selector string) (*domains.Domain, error) {
+	return r.stampDKIMKey(ctx, domain, func(q *Queries) error {
+		_, err := q.ActivateDKIMKey(ctx, ActivateDKIMKeyParams{Domain: domain.String(), Selector: selector})
+		return err
+	})
+}
+
+func (r *domainsRepository) RetireDKIMKey(ctx context.Context, domain values.DomainName, selector string) (*domains.Domain, error) {
+	return r.stampDKIMKey(ctx, domain, func(q *Queries) error {
+		_, err := q.RetireDKIMKey(ctx, RetireDKIMKeyParams{Domain: domain.String(), Selector: selector})
+		return err
+	})
+}
+
+// stampDKIMKey runs one of the key UPDATEs, which match no row both for an unknown selector and
+// for a retired key, and reads the Domain back with the stamp applied.
+func (r *domainsRepository) stampDKIMKey(ctx context.Context, domain values.DomainName, update func(q *Queries) error) (*domains.Domain, error) {
+	q := New(r.db)
+	if err := update(q); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domains.ErrDKIMKeyNotFound
+		}
+		return nil, err
+	}
+	return r.FindByName(ctx, domain)
+}
+
+func (r *domainsRepository) withKeys(ctx context.Context, q *Queries, row Domain) (*domains.Domain, error) {
+	keys, err := q.ListDKIMKeys(ctx, row.Domain)
+	if err != nil {
+		return nil, err
+	}
+	return rowToDomain(row, keys)
+}
+
+func createDKIMKeyParams(domain values.DomainName, k domains.DKIMKey) CreateDKIMKeyParams {
+	return CreateDKIMKeyParams{
+		Domain:     domain.String(),
+		Selector:   k.Selector(),
+		Algorithm:  k.Algorithm(),
+		PrivateKey: k.PrivateKey(),
+		PublicKey:  k.PublicKey(),
+		Origin:     string(k.Origin()),
+		Bits:       int32(k.Bits()),
+		Activate:   k.Activated(),
+	}
+}
+
+// rowToDomain rebuilds the entity from its row and its key rows. The stored name goes back
+// through values.Parse rather than being trusted: a row predating the canonical form must not
+// become a Domain whose name no Grant and no query can match, so the data fault is named instead.
+func rowToDomain(row Domain, keys []DkimKey) (*domains.Domain, error) {
 	name, err := values.Parse(row.Domain)
 	if err != nil {
 		return nil, fmt.Errorf("domain row %q holds a non-canonical name: %w", row.Domain, err)
 	}
+	var rp values.DomainName
+	if row.ReturnPathDomain.Valid {
+		if rp, err = values.Parse(row.ReturnPathDomain.String); err != nil {
+			return nil, fmt.Errorf("domain row %q holds a non-canonical return-path domain: %w", row.Domain, err)
+		}
+	}
 	return domains.Load(domains.LoadParams{
-		ID:             row.ID,
-		Domain:         name,
-		DkimPrivateKey: row.DkimPrivateKey,
-		DkimPublicKey:  row.DkimPublicKey,
-		CreatedAt:      row.CreatedAt.Time,
+		ID:        row.ID,
+		Domain:    name,
+		DKIMKeys:  rowsToDKIMKeys(keys),
+		CreatedAt: row.CreatedAt.Time,
 		// Normalised on the way out, so a Domain always states a ceiling on both axes. A ceiling
 		// that states nothing enforces nothing (ADR 0003), and that invariant should rest on one
 		// enforcement point rather than on the column default and the write path both holding.
-		Tracking: row.Tracking.Normalized(),
+		Tracking:         row.Tracking.Normalized(),
+		ReturnPathDomain: rp,
+		Feedback:         row.Feedback.Identity(),
+		SMIME:            rowToSMIME(row.SmimeCertificate, row.SmimePrivateKey),
+		LinkDecoration:   row.LinkDecoration.Decoration(),
+		RequireTLS:       row.RequireTls,
+		IPPool:           row.IpPool,
 	}), nil
 }
+
+// rowToSMIME reads the pair of S/MIME columns, which a constraint keeps NULL together.
+func rowToSMIME(cert, key pgtype.Text) smime.Credentials {
+	if !cert.Valid {
+		return smime.Credentials{}
+	}
+	return smime.Credentials{Certificate: cert.String, PrivateKey: key.String}
+}
+
+func rowsToDKIMKeys(rows []DkimKey) []domains.DKIMKey {
+	keys := make([]domains.DKIMKey, 0, len(rows))
+	for _, k := range rows {
+		keys = append(keys, domains.LoadDKIMKey(domains.DKIMKeyParams{
+			Selector:    k.Selector,
+			Algorithm:   k.Algorithm,
+			PrivateKey:  k.PrivateKey,
+			PublicKey:   k.PublicKey,
+			Origin:      domains.DKIMKeyOrigin(k.Origin),
+			Bits:        int(k.Bits),
+			CreatedAt:   k.CreatedAt.Time,
+			ActivatedAt: k.ActivatedAt.Time,
+			RetiredAt:   k.RetiredAt.Time,
+		}))
+	}
+	return keys
+}
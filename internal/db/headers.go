@@ -1,5 +1,10 @@
 package sqlc
 
+import (
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+)
+
 // Headers is the JSONB payload of messages.headers: everything a Batch states
 // about the headers of its outgoing messages. Adding a key here needs no
 // migration — rows written before the key existed simply decode without it.
@@ -9,9 +14,65 @@ type Headers struct {
 	// OneClickUnsubscribe is absent on every Batch written before ADR 0005, and
 	// on every Batch whose caller states no unsubscribe endpoint.
 	OneClickUnsubscribe *OneClickUnsubscribe `json:"one_click_unsubscribe,omitempty"`
+	// Feedback is the Batch's override of its Domain's Feedback Identity, absent
+	// when it overrides nothing.
+	Feedback *Feedback `json:"feedback,omitempty"`
+	// LinkParams is the Batch's override of its Domain's link query parameters,
+	// absent when it overrides nothing. It shapes the body rather than a header,
+	// but it is a per-Batch statement like the others here, and this column is
+	// where those live without a migration each.
+	LinkParams map[string]string `json:"link_params,omitempty"`
+	// RequireTLS is the Batch requiring authenticated TLS for itself, absent
+	// when it leaves the question to its Domain.
+	RequireTLS bool `json:"require_tls,omitempty"`
+	// IPPool is the IP pool the Batch chose for itself, absent when it leaves
+	// the choice to its Domain.
+	IPPool string `json:"ip_pool,omitempty"`
 }
 
 // OneClickUnsubscribe is the stored form of the sender's unsubscribe endpoint.
 type OneClickUnsubscribe struct {
 	URLTemplate string `json:"url_template"`
 }
+
+// Feedback is the stored form of a Feedback Identity: domains.feedback, and the
+// override a Batch keeps in messages.headers. Every key is optional, and the
+// empty object states nothing.
+type Feedback struct {
+	SenderID string `json:"sender_id,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	ListID   string `json:"list_id,omitempty"`
+}
+
+// Identity reads the stored form back as the domain type. Both the repositories
+// and the Builder's sending-data source read this column, so the mapping lives
+// beside the type rather than in either of them.
+func (f Feedback) Identity() feedback.Identity {
+	return feedback.Identity{SenderID: f.SenderID, Campaign: f.Campaign, ListID: f.ListID}
+}
+
+func toSQLCFeedback(i feedback.Identity) Feedback {
+	return Feedback{SenderID: i.SenderID, Campaign: i.Campaign, ListID: i.ListID}
+}
+
+// LinkDecoration is the stored form of a Domain's Link Decoration in
+// domains.link_decoration. Every key is optional, and the empty object
+// decorates nothing.
+type LinkDecoration struct {
+	Params     map[string]string `json:"params,omitempty"`
+	AllowHosts []string          `json:"allow_hosts,omitempty"`
+	DenyHosts  []string          `json:"deny_hosts,omitempty"`
+}
+
+// Decoration reads the stored form back as the domain type, for the same two
+// readers as Feedback.Identity.
+func (l LinkDecoration) Decoration() linkparams.Decoration {
+	return linkparams.Decoration{
+		Params: l.Params,
+		Hosts:  linkparams.Hosts{Allow: l.AllowHosts, Deny: l.DenyHosts},
+	}
+}
+
+func toSQLCLinkDecoration(d linkparams.Decoration) LinkDecoration {
+	return LinkDecoration{Params: d.Params, AllowHosts: d.Hosts.Allow, DenyHosts: d.Hosts.Deny}
+}
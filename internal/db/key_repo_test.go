@@ -17,11 +17,7 @@ func (h testHelper) CreateDomain(t *testing.T) values.DomainName {
 	domainName := fmt.Sprintf("test-apikeys-%d.com", time.Now().UnixNano())
 
 	// Create domain directly using queries
-	_, err := q.CreateDomain(ctx, CreateDomainParams{
-		Domain:         domainName,
-		DkimPrivateKey: "test-private",
-		DkimPublicKey:  "test-public",
-	})
+	_, err := q.CreateDomain(ctx, domainName)
 	require.NoError(t, err)
 
 	return values.MustParse(domainName)
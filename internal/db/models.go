@@ -59,6 +59,7 @@ type AggregatedStat struct {
 	Timestamp pgtype.Timestamp
 	Type      StatsType
 	Count     int64
+	Category  string
 }
 
 type ApiKey struct {
@@ -73,13 +74,32 @@ type ApiKey struct {
 	KeyPrefix     string
 }
 
+type DkimKey struct {
+	ID          int32
+	Domain      string
+	Selector    string
+	Algorithm   string
+	PrivateKey  string
+	PublicKey   string
+	CreatedAt   pgtype.Timestamp
+	ActivatedAt pgtype.Timestamp
+	RetiredAt   pgtype.Timestamp
+	Origin      string
+	Bits        int32
+}
+
 type Domain struct {
-	ID             int32
-	Domain         string
-	CreatedAt      pgtype.Timestamp
-	DkimPrivateKey string
-	DkimPublicKey  string
-	Tracking       tracking.Policy
+	ID               int32
+	Domain           string
+	CreatedAt        pgtype.Timestamp
+	Tracking         tracking.Policy
+	ReturnPathDomain pgtype.Text
+	Feedback         Feedback
+	SmimeCertificate pgtype.Text
+	SmimePrivateKey  pgtype.Text
+	LinkDecoration   LinkDecoration
+	RequireTls       bool
+	IpPool           string
 }
 
 type Message struct {
@@ -107,6 +127,7 @@ type SendingPoolEmail struct {
 	Domain                string
 	Tracking              tracking.Policy
 	ClaimedAt             pgtype.Timestamp
+	SmimeCertificate      []byte
 }
 
 type Stat struct {
@@ -63,6 +63,15 @@ WHERE email = @email AND message_id = @message_id;
 UPDATE sending_pool_emails 
 SET status='scheduled', scheduled_time =  @scheduled_time, send_attempts_cnt = send_attempts_cnt + 1 WHERE email = @email AND message_id = @message_id;
 
+-- PostponePool hands a Delivery claimed for dispatch back to the Pool to be
+-- claimed again at scheduled_time, with no attempt spent: the SMTPSender held
+-- its Envelope back without offering it. A row no longer in 'sending' has been
+-- reclaimed, and possibly dispatched again, in the meantime, and is left alone.
+-- name: PostponePool :exec
+UPDATE sending_pool_emails
+SET status = 'scheduled', scheduled_time = @scheduled_time, claimed_at = NULL
+WHERE email = @email AND message_id = @message_id AND status = 'sending';
+
 -- name: GetPool :one
 SELECT * FROM  sending_pool_emails 
 WHERE email = @email AND message_id = @message_id;
@@ -79,21 +88,44 @@ INSERT INTO messages
 SELECT * FROM messages WHERE message_id = $1;
 
 -- name: CreatePool :copyfrom
-INSERT INTO sending_pool_emails (email, status, scheduled_time, original_scheduled_time, message_id, fields, domain, tracking) VALUES
-    ($1, $2, $3, $4, $5, $6, $7, $8);
+INSERT INTO sending_pool_emails (email, status, scheduled_time, original_scheduled_time, message_id, fields, domain, tracking, smime_certificate) VALUES
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9);
 
 -- name: GetSendingData :one
 SELECT
     t.html,
     m.domain,
-    d.dkim_private_key,
-    d.dkim_public_key,
     m.subject,
     m.message_id,
     m.sender_email,
     m.sender_alias,
     m.attachments,
-    m.headers
+    m.headers,
+    d.return_path_domain,
+    d.feedback,
+    d.smime_certificate,
+    d.smime_private_key,
+    d.link_decoration,
+    d.require_tls,
+    d.ip_pool
+FROM messages as m
+    JOIN templates as t ON t.template_id = m.template_id
+    JOIN domains as d ON d.domain = m.domain
+    WHERE m.message_id = @message_id;
+
+-- GetSendingDataVersion is what the Builder checks a cached preparation of a
+-- Batch against: a digest of everything GetSendingData and GetSigningDKIMKeys
+-- read that can change after the Batch is created — its Template, its Domain's
+-- row and its Domain's keys. The Batch's own row is written once and is not in
+-- it.
+--
+-- name: GetSendingDataVersion :one
+SELECT md5(
+    t.updated_at::text || d::text || COALESCE(
+        (SELECT string_agg(k::text, ',' ORDER BY k.id) FROM dkim_keys AS k WHERE k.domain = d.domain),
+        ''
+    )
+)::text AS version
 FROM messages as m
     JOIN templates as t ON t.template_id = m.template_id
     JOIN domains as d ON d.domain = m.domain
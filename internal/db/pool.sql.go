@@ -81,6 +81,7 @@ type CreatePoolParams struct {
 	Fields                CustomFields
 	Domain                string
 	Tracking              tracking.Policy
+	SmimeCertificate      []byte
 }
 
 const getMessage = `-- name: GetMessage :one
@@ -105,7 +106,7 @@ func (q *Queries) GetMessage(ctx context.Context, messageID string) (Message, er
 }
 
 const getPool = `-- name: GetPool :one
-SELECT id, scheduled_time, original_scheduled_time, send_attempts_cnt, email, message_id, fields, status, created_at, domain, tracking, claimed_at FROM  sending_pool_emails 
+SELECT id, scheduled_time, original_scheduled_time, send_attempts_cnt, email, message_id, fields, status, created_at, domain, tracking, claimed_at, smime_certificate FROM  sending_pool_emails 
 WHERE email = $1 AND message_id = $2
 `
 
@@ -130,6 +131,7 @@ func (q *Queries) GetPool(ctx context.Context, arg GetPoolParams) (SendingPoolEm
 		&i.Domain,
 		&i.Tracking,
 		&i.ClaimedAt,
+		&i.SmimeCertificate,
 	)
 	return i, err
 }
@@ -138,14 +140,19 @@ const getSendingData = `-- name: GetSendingData :one
 SELECT
     t.html,
     m.domain,
-    d.dkim_private_key,
-    d.dkim_public_key,
     m.subject,
     m.message_id,
     m.sender_email,
     m.sender_alias,
     m.attachments,
-    m.headers
+    m.headers,
+    d.return_path_domain,
+    d.feedback,
+    d.smime_certificate,
+    d.smime_private_key,
+    d.link_decoration,
+    d.require_tls,
+    d.ip_pool
 FROM messages as m
     JOIN templates as t ON t.template_id = m.template_id
     JOIN domains as d ON d.domain = m.domain
@@ -153,16 +160,21 @@ FROM messages as m
 `
 
 type GetSendingDataRow struct {
-	Html           string
-	Domain         string
-	DkimPrivateKey string
-	DkimPublicKey  string
-	Subject        string
-	MessageID      string
-	SenderEmail    string
-	SenderAlias    string
-	Attachments    Attachments
-	Headers        Headers
+	Html             string
+	Domain           string
+	Subject          string
+	MessageID        string
+	SenderEmail      string
+	SenderAlias      string
+	Attachments      Attachments
+	Headers          Headers
+	ReturnPathDomain pgtype.Text
+	Feedback         Feedback
+	SmimeCertificate pgtype.Text
+	SmimePrivateKey  pgtype.Text
+	LinkDecoration   LinkDecoration
+	RequireTls       bool
+	IpPool           string
 }
 
 func (q *Queries) GetSendingData(ctx context.Context, messageID string) (GetSendingDataRow, error) {
@@ -171,20 +183,50 @@ func (q *Queries) GetSendingData(ctx context.Context, messageID string) (GetSend
 	err := row.Scan(
 		&i.Html,
 		&i.Domain,
-		&i.DkimPrivateKey,
-		&i.DkimPublicKey,
 		&i.Subject,
 		&i.MessageID,
 		&i.SenderEmail,
 		&i.SenderAlias,
 		&i.Attachments,
 		&i.Headers,
+		&i.ReturnPathDomain,
+		&i.Feedback,
+		&i.SmimeCertificate,
+		&i.SmimePrivateKey,
+		&i.LinkDecoration,
+		&i.RequireTls,
+		&i.IpPool,
 	)
 	return i, err
 }
 
+const getSendingDataVersion = `-- name: GetSendingDataVersion :one
+SELECT md5(
+    t.updated_at::text || d::text || COALESCE(
+        (SELECT string_agg(k::text, ',' ORDER BY k.id) FROM dkim_keys AS k WHERE k.domain = d.domain),
+        ''
+    )
+)::text AS version
+FROM messages as m
+    JOIN templates as t ON t.template_id = m.template_id
+    JOIN domains as d ON d.domain = m.domain
+    WHERE m.message_id = $1
+`
+
+// GetSendingDataVersion is what the Builder checks a cached preparation of a
+// Batch against: a digest of everything GetSendingData and GetSigningDKIMKeys
+// read that can change after the Batch is created — its Template, its Domain's
+// row and its Domain's keys. The Batch's own row is written once and is not in
+// it.
+func (q *Queries) GetSendingDataVersion(ctx context.Context, messageID string) (string, error) {
+	row := q.db.QueryRow(ctx, getSendingDataVersion, messageID)
+	var version string
+	err := row.Scan(&version)
+	return version, err
+}
+
 const getSendingPoolsEmails = `-- name: GetSendingPoolsEmails :many
-SELECT id, scheduled_time, original_scheduled_time, send_attempts_cnt, email, message_id, fields, status, created_at, domain, tracking, claimed_at FROM sending_pool_emails WHERE message_id = $1 ORDER BY id LIMIT $2 OFFSET $3
+SELECT id, scheduled_time, original_scheduled_time, send_attempts_cnt, email, message_id, fields, status, created_at, domain, tracking, claimed_at, smime_certificate FROM sending_pool_emails WHERE message_id = $1 ORDER BY id LIMIT $2 OFFSET $3
 `
 
 type GetSendingPoolsEmailsParams struct {
@@ -215,6 +257,7 @@ func (q *Queries) GetSendingPoolsEmails(ctx context.Context, arg GetSendingPools
 			&i.Domain,
 			&i.Tracking,
 			&i.ClaimedAt,
+			&i.SmimeCertificate,
 		); err != nil {
 			return nil, err
 		}
@@ -236,7 +279,7 @@ UPDATE sending_pool_emails AS sp
             LIMIT $1
         ) AS t
     WHERE sp.id = t.id
-    RETURNING sp.id, sp.scheduled_time, sp.original_scheduled_time, sp.send_attempts_cnt, sp.email, sp.message_id, sp.fields, sp.status, sp.created_at, sp.domain, sp.tracking, sp.claimed_at
+    RETURNING sp.id, sp.scheduled_time, sp.original_scheduled_time, sp.send_attempts_cnt, sp.email, sp.message_id, sp.fields, sp.status, sp.created_at, sp.domain, sp.tracking, sp.claimed_at, sp.smime_certificate
 `
 
 func (q *Queries) PrepareForSend(ctx context.Context, limit int32) ([]SendingPoolEmail, error) {
@@ -261,6 +304,7 @@ func (q *Queries) PrepareForSend(ctx context.Context, limit int32) ([]SendingPoo
 			&i.Domain,
 			&i.Tracking,
 			&i.ClaimedAt,
+			&i.SmimeCertificate,
 		); err != nil {
 			return nil, err
 		}
@@ -282,7 +326,7 @@ UPDATE sending_pool_emails AS sp
             LIMIT $1
         ) AS t
     WHERE sp.id = t.id
-    RETURNING sp.id, sp.scheduled_time, sp.original_scheduled_time, sp.send_attempts_cnt, sp.email, sp.message_id, sp.fields, sp.status, sp.created_at, sp.domain, sp.tracking, sp.claimed_at
+    RETURNING sp.id, sp.scheduled_time, sp.original_scheduled_time, sp.send_attempts_cnt, sp.email, sp.message_id, sp.fields, sp.status, sp.created_at, sp.domain, sp.tracking, sp.claimed_at, sp.smime_certificate
 `
 
 func (q *Queries) PrepareForValidate(ctx context.Context, limit int32) ([]SendingPoolEmail, error) {
@@ -307,6 +351,7 @@ func (q *Queries) PrepareForValidate(ctx context.Context, limit int32) ([]Sendin
 			&i.Domain,
 			&i.Tracking,
 			&i.ClaimedAt,
+			&i.SmimeCertificate,
 		); err != nil {
 			return nil, err
 		}
@@ -331,7 +376,7 @@ UPDATE sending_pool_emails AS sp
             LIMIT $5
         ) AS t
     WHERE sp.id = t.id
-    RETURNING sp.id, sp.scheduled_time, sp.original_scheduled_time, sp.send_attempts_cnt, sp.email, sp.message_id, sp.fields, sp.status, sp.created_at, sp.domain, sp.tracking, sp.claimed_at
+    RETURNING sp.id, sp.scheduled_time, sp.original_scheduled_time, sp.send_attempts_cnt, sp.email, sp.message_id, sp.fields, sp.status, sp.created_at, sp.domain, sp.tracking, sp.claimed_at, sp.smime_certificate
 `
 
 type ReclaimStrandedParams struct {
@@ -379,6 +424,7 @@ func (q *Queries) ReclaimStranded(ctx context.Context, arg ReclaimStrandedParams
 			&i.Domain,
 			&i.Tracking,
 			&i.ClaimedAt,
+			&i.SmimeCertificate,
 		); err != nil {
 			return nil, err
 		}
@@ -390,6 +436,27 @@ func (q *Queries) ReclaimStranded(ctx context.Context, arg ReclaimStrandedParams
 	return items, nil
 }
 
+const postponePool = `-- name: PostponePool :exec
+UPDATE sending_pool_emails
+SET status = 'scheduled', scheduled_time = $1, claimed_at = NULL
+WHERE email = $2 AND message_id = $3 AND status = 'sending'
+`
+
+type PostponePoolParams struct {
+	ScheduledTime pgtype.Timestamp
+	Email         string
+	MessageID     string
+}
+
+// PostponePool hands a Delivery claimed for dispatch back to the Pool to be
+// claimed again at scheduled_time, with no attempt spent: the SMTPSender held
+// its Envelope back without offering it. A row no longer in 'sending' has been
+// reclaimed, and possibly dispatched again, in the meantime, and is left alone.
+func (q *Queries) PostponePool(ctx context.Context, arg PostponePoolParams) error {
+	_, err := q.db.Exec(ctx, postponePool, arg.ScheduledTime, arg.Email, arg.MessageID)
+	return err
+}
+
 const reschedulePool = `-- name: ReschedulePool :exec
 UPDATE sending_pool_emails 
 SET status='scheduled', scheduled_time =  $1, send_attempts_cnt = send_attempts_cnt + 1 WHERE email = $2 AND message_id = $3
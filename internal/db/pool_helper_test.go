@@ -17,11 +17,7 @@ func seedBatchFixture(tb testing.TB) (batch.ID, string) {
 	tb.Helper()
 	ctx := tb.Context()
 	domainName := fmt.Sprintf("test-pool-%d.com", time.Now().UnixNano())
-	_, err := q.CreateDomain(ctx, CreateDomainParams{
-		Domain:         domainName,
-		DkimPrivateKey: "test-private",
-		DkimPublicKey:  "test-public",
-	})
+	_, err := q.CreateDomain(ctx, domainName)
 	require.NoError(tb, err)
 
 	tplID := fmt.Sprintf("tpl_%d", time.Now().UnixNano())
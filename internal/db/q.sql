@@ -17,8 +17,44 @@ ORDER BY id
 
 -- name: CreateDomain :one
 INSERT INTO domains
-    (domain, dkim_private_key, dkim_public_key)
-    VALUES ($1, $2, $3)
+    (domain)
+    VALUES ($1)
+    RETURNING *;
+
+-- name: SetDomainFeedback :one
+UPDATE domains
+    SET feedback = $2
+    WHERE domain = $1
+    RETURNING *;
+
+-- name: SetDomainSMIME :one
+UPDATE domains
+    SET smime_certificate = $2, smime_private_key = $3
+    WHERE domain = $1
+    RETURNING *;
+
+-- name: SetDomainLinkDecoration :one
+UPDATE domains
+    SET link_decoration = $2
+    WHERE domain = $1
+    RETURNING *;
+
+-- name: SetDomainIPPool :one
+UPDATE domains
+    SET ip_pool = $2
+    WHERE domain = $1
+    RETURNING *;
+
+-- name: SetDomainRequireTLS :one
+UPDATE domains
+    SET require_tls = $2
+    WHERE domain = $1
+    RETURNING *;
+
+-- name: SetDomainReturnPath :one
+UPDATE domains
+    SET return_path_domain = $2
+    WHERE domain = $1
     RETURNING *;
 
 -- name: SetDomainTracking :one
@@ -27,6 +63,41 @@ UPDATE domains
     WHERE domain = $1
     RETURNING *;
 
+-- name: CreateDKIMKey :one
+INSERT INTO dkim_keys
+    (domain, selector, algorithm, private_key, public_key, origin, bits, activated_at)
+    VALUES (@domain, @selector, @algorithm, @private_key, @public_key, @origin, @bits,
+        CASE WHEN @activate::boolean THEN now() END)
+    RETURNING *;
+
+-- name: ListDKIMKeys :many
+SELECT * FROM dkim_keys
+    WHERE domain = $1
+    ORDER BY created_at, id;
+
+-- name: GetAllDKIMKeys :many
+SELECT * FROM dkim_keys
+    ORDER BY domain, created_at, id;
+
+-- name: GetSigningDKIMKeys :many
+SELECT DISTINCT ON (algorithm)
+    selector, algorithm, private_key
+FROM dkim_keys
+    WHERE domain = $1 AND activated_at IS NOT NULL AND retired_at IS NULL
+    ORDER BY algorithm, activated_at DESC, id DESC;
+
+-- name: ActivateDKIMKey :one
+UPDATE dkim_keys
+    SET activated_at = now()
+    WHERE domain = $1 AND selector = $2 AND retired_at IS NULL
+    RETURNING *;
+
+-- name: RetireDKIMKey :one
+UPDATE dkim_keys
+    SET retired_at = now()
+    WHERE domain = $1 AND selector = $2 AND retired_at IS NULL
+    RETURNING *;
+
 -- name: FindTemplate :one
 SELECT * FROM templates
 WHERE template_id = $1
@@ -8,39 +8,108 @@ package sqlc
 import (
 	"context"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	tracking "github.com/kannon-email/kannon/internal/tracking"
 )
 
-const createDomain = `-- name: CreateDomain :one
-INSERT INTO domains
-    (domain, dkim_private_key, dkim_public_key)
-    VALUES ($1, $2, $3)
-    RETURNING id, domain, created_at, dkim_private_key, dkim_public_key, tracking
+const activateDKIMKey = `-- name: ActivateDKIMKey :one
+UPDATE dkim_keys
+    SET activated_at = now()
+    WHERE domain = $1 AND selector = $2 AND retired_at IS NULL
+    RETURNING id, domain, selector, algorithm, private_key, public_key, created_at, activated_at, retired_at, origin, bits
 `
 
-type CreateDomainParams struct {
-	Domain         string
-	DkimPrivateKey string
-	DkimPublicKey  string
+type ActivateDKIMKeyParams struct {
+	Domain   string
+	Selector string
+}
+
+func (q *Queries) ActivateDKIMKey(ctx context.Context, arg ActivateDKIMKeyParams) (DkimKey, error) {
+	row := q.db.QueryRow(ctx, activateDKIMKey, arg.Domain, arg.Selector)
+	var i DkimKey
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.Selector,
+		&i.Algorithm,
+		&i.PrivateKey,
+		&i.PublicKey,
+		&i.CreatedAt,
+		&i.ActivatedAt,
+		&i.RetiredAt,
+		&i.Origin,
+		&i.Bits,
+	)
+	return i, err
+}
+
+const createDKIMKey = `-- name: CreateDKIMKey :one
+INSERT INTO dkim_keys
+    (domain, selector, algorithm, private_key, public_key, origin, bits, activated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7,
+        CASE WHEN $8::boolean THEN now() END)
+    RETURNING id, domain, selector, algorithm, private_key, public_key, created_at, activated_at, retired_at, origin, bits
+`
+
+type CreateDKIMKeyParams struct {
+	Domain     string
+	Selector   string
+	Algorithm  string
+	PrivateKey string
+	PublicKey  string
+	Origin     string
+	Bits       int32
+	Activate   bool
 }
 
-func (q *Queries) CreateDomain(ctx context.Context, arg CreateDomainParams) (Domain, error) {
-	row := q.db.QueryRow(ctx, createDomain, arg.Domain, arg.DkimPrivateKey, arg.DkimPublicKey)
+func (q *Queries) CreateDKIMKey(ctx context.Context, arg CreateDKIMKeyParams) (DkimKey, error) {
+	row := q.db.QueryRow(ctx, createDKIMKey, arg.Domain, arg.Selector, arg.Algorithm, arg.PrivateKey, arg.PublicKey, arg.Origin, arg.Bits, arg.Activate)
+	var i DkimKey
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.Selector,
+		&i.Algorithm,
+		&i.PrivateKey,
+		&i.PublicKey,
+		&i.CreatedAt,
+		&i.ActivatedAt,
+		&i.RetiredAt,
+		&i.Origin,
+		&i.Bits,
+	)
+	return i, err
+}
+
+const createDomain = `-- name: CreateDomain :one
+INSERT INTO domains
+    (domain)
+    VALUES ($1)
+    RETURNING id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool
+`
+
+func (q *Queries) CreateDomain(ctx context.Context, domain string) (Domain, error) {
+	row := q.db.QueryRow(ctx, createDomain, domain)
 	var i Domain
 	err := row.Scan(
 		&i.ID,
 		&i.Domain,
 		&i.CreatedAt,
-		&i.DkimPrivateKey,
-		&i.DkimPublicKey,
 		&i.Tracking,
+		&i.ReturnPathDomain,
+		&i.Feedback,
+		&i.SmimeCertificate,
+		&i.SmimePrivateKey,
+		&i.LinkDecoration,
+		&i.RequireTls,
+		&i.IpPool,
 	)
 	return i, err
 }
 
 const findDomain = `-- name: FindDomain :one
 SELECT
-    id, domain, created_at, dkim_private_key, dkim_public_key, tracking
+    id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool
 FROM domains
     WHERE domain = $1
 `
@@ -52,9 +121,14 @@ func (q *Queries) FindDomain(ctx context.Context, domain string) (Domain, error)
 		&i.ID,
 		&i.Domain,
 		&i.CreatedAt,
-		&i.DkimPrivateKey,
-		&i.DkimPublicKey,
 		&i.Tracking,
+		&i.ReturnPathDomain,
+		&i.Feedback,
+		&i.SmimeCertificate,
+		&i.SmimePrivateKey,
+		&i.LinkDecoration,
+		&i.RequireTls,
+		&i.IpPool,
 	)
 	return i, err
 }
@@ -86,9 +160,46 @@ func (q *Queries) FindTemplate(ctx context.Context, arg FindTemplateParams) (Tem
 	return i, err
 }
 
+const getAllDKIMKeys = `-- name: GetAllDKIMKeys :many
+SELECT id, domain, selector, algorithm, private_key, public_key, created_at, activated_at, retired_at, origin, bits FROM dkim_keys
+    ORDER BY domain, created_at, id
+`
+
+func (q *Queries) GetAllDKIMKeys(ctx context.Context) ([]DkimKey, error) {
+	rows, err := q.db.Query(ctx, getAllDKIMKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DkimKey
+	for rows.Next() {
+		var i DkimKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Domain,
+			&i.Selector,
+			&i.Algorithm,
+			&i.PrivateKey,
+			&i.PublicKey,
+			&i.CreatedAt,
+			&i.ActivatedAt,
+			&i.RetiredAt,
+			&i.Origin,
+			&i.Bits,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAllDomains = `-- name: GetAllDomains :many
 SELECT
-    id, domain, created_at, dkim_private_key, dkim_public_key, tracking
+    id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool
 FROM domains
 ORDER BY id
 `
@@ -106,9 +217,14 @@ func (q *Queries) GetAllDomains(ctx context.Context) ([]Domain, error) {
 			&i.ID,
 			&i.Domain,
 			&i.CreatedAt,
-			&i.DkimPrivateKey,
-			&i.DkimPublicKey,
 			&i.Tracking,
+			&i.ReturnPathDomain,
+			&i.Feedback,
+			&i.SmimeCertificate,
+			&i.SmimePrivateKey,
+			&i.LinkDecoration,
+			&i.RequireTls,
+			&i.IpPool,
 		); err != nil {
 			return nil, err
 		}
@@ -121,7 +237,7 @@ func (q *Queries) GetAllDomains(ctx context.Context) ([]Domain, error) {
 }
 
 const getDomains = `-- name: GetDomains :many
-SELECT id, domain, created_at, dkim_private_key, dkim_public_key, tracking FROM domains ORDER BY id
+SELECT id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool FROM domains ORDER BY id
 `
 
 func (q *Queries) GetDomains(ctx context.Context) ([]Domain, error) {
@@ -137,9 +253,90 @@ func (q *Queries) GetDomains(ctx context.Context) ([]Domain, error) {
 			&i.ID,
 			&i.Domain,
 			&i.CreatedAt,
-			&i.DkimPrivateKey,
-			&i.DkimPublicKey,
 			&i.Tracking,
+			&i.ReturnPathDomain,
+			&i.Feedback,
+			&i.SmimeCertificate,
+			&i.SmimePrivateKey,
+			&i.LinkDecoration,
+			&i.RequireTls,
+			&i.IpPool,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSigningDKIMKeys = `-- name: GetSigningDKIMKeys :many
+SELECT DISTINCT ON (algorithm)
+    selector, algorithm, private_key
+FROM dkim_keys
+    WHERE domain = $1 AND activated_at IS NOT NULL AND retired_at IS NULL
+    ORDER BY algorithm, activated_at DESC, id DESC
+`
+
+type GetSigningDKIMKeysRow struct {
+	Selector   string
+	Algorithm  string
+	PrivateKey string
+}
+
+func (q *Queries) GetSigningDKIMKeys(ctx context.Context, domain string) ([]GetSigningDKIMKeysRow, error) {
+	rows, err := q.db.Query(ctx, getSigningDKIMKeys, domain)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSigningDKIMKeysRow
+	for rows.Next() {
+		var i GetSigningDKIMKeysRow
+		if err := rows.Scan(
+			&i.Selector,
+			&i.Algorithm,
+			&i.PrivateKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDKIMKeys = `-- name: ListDKIMKeys :many
+SELECT id, domain, selector, algorithm, private_key, public_key, created_at, activated_at, retired_at, origin, bits FROM dkim_keys
+    WHERE domain = $1
+    ORDER BY created_at, id
+`
+
+func (q *Queries) ListDKIMKeys(ctx context.Context, domain string) ([]DkimKey, error) {
+	rows, err := q.db.Query(ctx, listDKIMKeys, domain)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DkimKey
+	for rows.Next() {
+		var i DkimKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Domain,
+			&i.Selector,
+			&i.Algorithm,
+			&i.PrivateKey,
+			&i.PublicKey,
+			&i.CreatedAt,
+			&i.ActivatedAt,
+			&i.RetiredAt,
+			&i.Origin,
+			&i.Bits,
 		); err != nil {
 			return nil, err
 		}
@@ -151,11 +348,229 @@ func (q *Queries) GetDomains(ctx context.Context) ([]Domain, error) {
 	return items, nil
 }
 
+const retireDKIMKey = `-- name: RetireDKIMKey :one
+UPDATE dkim_keys
+    SET retired_at = now()
+    WHERE domain = $1 AND selector = $2 AND retired_at IS NULL
+    RETURNING id, domain, selector, algorithm, private_key, public_key, created_at, activated_at, retired_at, origin, bits
+`
+
+type RetireDKIMKeyParams struct {
+	Domain   string
+	Selector string
+}
+
+func (q *Queries) RetireDKIMKey(ctx context.Context, arg RetireDKIMKeyParams) (DkimKey, error) {
+	row := q.db.QueryRow(ctx, retireDKIMKey, arg.Domain, arg.Selector)
+	var i DkimKey
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.Selector,
+		&i.Algorithm,
+		&i.PrivateKey,
+		&i.PublicKey,
+		&i.CreatedAt,
+		&i.ActivatedAt,
+		&i.RetiredAt,
+		&i.Origin,
+		&i.Bits,
+	)
+	return i, err
+}
+
+const setDomainFeedback = `-- name: SetDomainFeedback :one
+UPDATE domains
+    SET feedback = $2
+    WHERE domain = $1
+    RETURNING id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool
+`
+
+type SetDomainFeedbackParams struct {
+	Domain   string
+	Feedback Feedback
+}
+
+func (q *Queries) SetDomainFeedback(ctx context.Context, arg SetDomainFeedbackParams) (Domain, error) {
+	row := q.db.QueryRow(ctx, setDomainFeedback, arg.Domain, arg.Feedback)
+	var i Domain
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.CreatedAt,
+		&i.Tracking,
+		&i.ReturnPathDomain,
+		&i.Feedback,
+		&i.SmimeCertificate,
+		&i.SmimePrivateKey,
+		&i.LinkDecoration,
+		&i.RequireTls,
+		&i.IpPool,
+	)
+	return i, err
+}
+
+const setDomainLinkDecoration = `-- name: SetDomainLinkDecoration :one
+UPDATE domains
+    SET link_decoration = $2
+    WHERE domain = $1
+    RETURNING id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool
+`
+
+type SetDomainLinkDecorationParams struct {
+	Domain         string
+	LinkDecoration LinkDecoration
+}
+
+func (q *Queries) SetDomainLinkDecoration(ctx context.Context, arg SetDomainLinkDecorationParams) (Domain, error) {
+	row := q.db.QueryRow(ctx, setDomainLinkDecoration, arg.Domain, arg.LinkDecoration)
+	var i Domain
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.CreatedAt,
+		&i.Tracking,
+		&i.ReturnPathDomain,
+		&i.Feedback,
+		&i.SmimeCertificate,
+		&i.SmimePrivateKey,
+		&i.LinkDecoration,
+		&i.RequireTls,
+		&i.IpPool,
+	)
+	return i, err
+}
+
+const setDomainIPPool = `-- name: SetDomainIPPool :one
+UPDATE domains
+    SET ip_pool = $2
+    WHERE domain = $1
+    RETURNING id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool
+`
+
+type SetDomainIPPoolParams struct {
+	Domain string
+	IpPool string
+}
+
+func (q *Queries) SetDomainIPPool(ctx context.Context, arg SetDomainIPPoolParams) (Domain, error) {
+	row := q.db.QueryRow(ctx, setDomainIPPool, arg.Domain, arg.IpPool)
+	var i Domain
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.CreatedAt,
+		&i.Tracking,
+		&i.ReturnPathDomain,
+		&i.Feedback,
+		&i.SmimeCertificate,
+		&i.SmimePrivateKey,
+		&i.LinkDecoration,
+		&i.RequireTls,
+		&i.IpPool,
+	)
+	return i, err
+}
+
+const setDomainRequireTLS = `-- name: SetDomainRequireTLS :one
+UPDATE domains
+    SET require_tls = $2
+    WHERE domain = $1
+    RETURNING id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool
+`
+
+type SetDomainRequireTLSParams struct {
+	Domain     string
+	RequireTls bool
+}
+
+func (q *Queries) SetDomainRequireTLS(ctx context.Context, arg SetDomainRequireTLSParams) (Domain, error) {
+	row := q.db.QueryRow(ctx, setDomainRequireTLS, arg.Domain, arg.RequireTls)
+	var i Domain
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.CreatedAt,
+		&i.Tracking,
+		&i.ReturnPathDomain,
+		&i.Feedback,
+		&i.SmimeCertificate,
+		&i.SmimePrivateKey,
+		&i.LinkDecoration,
+		&i.RequireTls,
+		&i.IpPool,
+	)
+	return i, err
+}
+
+const setDomainReturnPath = `-- name: SetDomainReturnPath :one
+UPDATE domains
+    SET return_path_domain = $2
+    WHERE domain = $1
+    RETURNING id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool
+`
+
+type SetDomainReturnPathParams struct {
+	Domain           string
+	ReturnPathDomain pgtype.Text
+}
+
+func (q *Queries) SetDomainReturnPath(ctx context.Context, arg SetDomainReturnPathParams) (Domain, error) {
+	row := q.db.QueryRow(ctx, setDomainReturnPath, arg.Domain, arg.ReturnPathDomain)
+	var i Domain
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.CreatedAt,
+		&i.Tracking,
+		&i.ReturnPathDomain,
+		&i.Feedback,
+		&i.SmimeCertificate,
+		&i.SmimePrivateKey,
+		&i.LinkDecoration,
+		&i.RequireTls,
+		&i.IpPool,
+	)
+	return i, err
+}
+
+const setDomainSMIME = `-- name: SetDomainSMIME :one
+UPDATE domains
+    SET smime_certificate = $2, smime_private_key = $3
+    WHERE domain = $1
+    RETURNING id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool
+`
+
+type SetDomainSMIMEParams struct {
+	Domain           string
+	SmimeCertificate pgtype.Text
+	SmimePrivateKey  pgtype.Text
+}
+
+func (q *Queries) SetDomainSMIME(ctx context.Context, arg SetDomainSMIMEParams) (Domain, error) {
+	row := q.db.QueryRow(ctx, setDomainSMIME, arg.Domain, arg.SmimeCertificate, arg.SmimePrivateKey)
+	var i Domain
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.CreatedAt,
+		&i.Tracking,
+		&i.ReturnPathDomain,
+		&i.Feedback,
+		&i.SmimeCertificate,
+		&i.SmimePrivateKey,
+		&i.LinkDecoration,
+		&i.RequireTls,
+		&i.IpPool,
+	)
+	return i, err
+}
+
 const setDomainTracking = `-- name: SetDomainTracking :one
 UPDATE domains
     SET tracking = $2
     WHERE domain = $1
-    RETURNING id, domain, created_at, dkim_private_key, dkim_public_key, tracking
+    RETURNING id, domain, created_at, tracking, return_path_domain, feedback, smime_certificate, smime_private_key, link_decoration, require_tls, ip_pool
 `
 
 type SetDomainTrackingParams struct {
@@ -170,9 +585,14 @@ func (q *Queries) SetDomainTracking(ctx context.Context, arg SetDomainTrackingPa
 		&i.ID,
 		&i.Domain,
 		&i.CreatedAt,
-		&i.DkimPrivateKey,
-		&i.DkimPublicKey,
 		&i.Tracking,
+		&i.ReturnPathDomain,
+		&i.Feedback,
+		&i.SmimeCertificate,
+		&i.SmimePrivateKey,
+		&i.LinkDecoration,
+		&i.RequireTls,
+		&i.IpPool,
 	)
 	return i, err
 }
@@ -11,7 +11,10 @@ const (
 	StatsTypeOpened    StatsType = "opened"
 	StatsTypeClicked   StatsType = "clicked"
 	StatsTypeBounce    StatsType = "bounced"
+	StatsTypeDelayed   StatsType = "delayed"
+	StatsTypeReported  StatsType = "reported"
 	StatsTypeError     StatsType = "error"
+	StatsTypePostponed StatsType = "postponed"
 	StatsTypeFailed    StatsType = "failed"
 	StatsTypeUnknown   StatsType = "unknown"
 )
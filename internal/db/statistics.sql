@@ -21,15 +21,18 @@ WHERE domain = $1
 AND timestamp >= @start AND timestamp < @stop;
 
 -- name: QueryStatsTimeline :many
+-- Bounces are counted per category, as aggregated_stats counts them; a bounce
+-- stored before bounces were classified, and every other type, has none.
 SELECT
 	type,
+	COALESCE(data->'bounced'->>'category', '')::VARCHAR AS category,
 	COUNT(*) as count,
 	date_trunc('hour', timestamp)::TIMESTAMP AS ts
 FROM stats
 WHERE domain = @domain
 AND timestamp >= @start AND timestamp < @stop
-GROUP BY type, ts
-ORDER BY ts ASC, type;
+GROUP BY type, category, ts
+ORDER BY ts ASC, type, category;
 
 -- name: DeleteStatsOlderThan :execrows
 DELETE FROM stats WHERE timestamp < @before;
\ No newline at end of file
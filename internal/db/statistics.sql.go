@@ -127,13 +127,14 @@ func (q *Queries) QueryStats(ctx context.Context, arg QueryStatsParams) ([]Stat,
 const queryStatsTimeline = `-- name: QueryStatsTimeline :many
 SELECT
 	type,
+	COALESCE(data->'bounced'->>'category', '')::VARCHAR AS category,
 	COUNT(*) as count,
 	date_trunc('hour', timestamp)::TIMESTAMP AS ts
 FROM stats
 WHERE domain = $1
 AND timestamp >= $2 AND timestamp < $3
-GROUP BY type, ts
-ORDER BY ts ASC, type
+GROUP BY type, category, ts
+ORDER BY ts ASC, type, category
 `
 
 type QueryStatsTimelineParams struct {
@@ -143,11 +144,14 @@ type QueryStatsTimelineParams struct {
 }
 
 type QueryStatsTimelineRow struct {
-	Type  StatsType
-	Count int64
-	Ts    pgtype.Timestamp
+	Type     StatsType
+	Category string
+	Count    int64
+	Ts       pgtype.Timestamp
 }
 
+// Bounces are counted per category, as aggregated_stats counts them; a bounce
+// stored before bounces were classified, and every other type, has none.
 func (q *Queries) QueryStatsTimeline(ctx context.Context, arg QueryStatsTimelineParams) ([]QueryStatsTimelineRow, error) {
 	rows, err := q.db.Query(ctx, queryStatsTimeline, arg.Domain, arg.Start, arg.Stop)
 	if err != nil {
@@ -157,7 +161,12 @@ func (q *Queries) QueryStatsTimeline(ctx context.Context, arg QueryStatsTimeline
 	var items []QueryStatsTimelineRow
 	for rows.Next() {
 		var i QueryStatsTimelineRow
-		if err := rows.Scan(&i.Type, &i.Count, &i.Ts); err != nil {
+		if err := rows.Scan(
+			&i.Type,
+			&i.Category,
+			&i.Count,
+			&i.Ts,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/stats"
 	"github.com/kannon-email/kannon/internal/values"
 )
@@ -80,6 +81,7 @@ func (r *StatsRepository) QueryTimeline(ctx context.Context, domain values.Domai
 	for _, row := range rows {
 		result = append(result, &stats.AggregatedStat{
 			Type:      stats.Type(row.Type),
+			Category:  bounce.Category(row.Category),
 			Timestamp: row.Ts.Time,
 			Count:     row.Count,
 		})
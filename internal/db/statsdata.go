@@ -1,6 +1,11 @@
 package sqlc
 
-import "github.com/kannon-email/kannon/internal/stats"
+import (
+	"time"
+
+	"github.com/kannon-email/kannon/internal/bounce"
+	"github.com/kannon-email/kannon/internal/stats"
+)
 
 // StatsData is the JSONB payload of the stats.data column: what one Delivery
 // outcome looks like on disk, stated by Kannon rather than inherited from a wire
@@ -26,7 +31,7 @@ import "github.com/kannon-email/kannon/internal/stats"
 // rendered as, rather than the SQL NULL a nil pointer would have encoded to and
 // the column would have refused.
 //
-// At most one variant is ever set. It is eight nullable pointers rather than a
+// At most one variant is ever set. It is eleven nullable pointers rather than a
 // tag and a union because that is precisely the JSON document being described,
 // and the domain type this maps to and from — stats.Outcome — is the place where
 // "exactly one of these" is enforced by construction.
@@ -39,6 +44,9 @@ type StatsData struct {
 	Clicked   *StatsDataClicked   `json:"clicked,omitempty"`
 	Rejected  *StatsDataRejected  `json:"rejected,omitempty"`
 	Error     *StatsDataError     `json:"error,omitempty"`
+	Delayed   *StatsDataDelayed   `json:"delayed,omitempty"`
+	Postponed *StatsDataPostponed `json:"postponed,omitempty"`
+	Reported  *StatsDataReported  `json:"reported,omitempty"`
 }
 
 // StatsDataAccepted carries nothing: the Validator having accepted an address is
@@ -46,9 +54,45 @@ type StatsData struct {
 // is present with an empty object, as protojson wrote it.
 type StatsDataAccepted struct{}
 
-// StatsDataDelivered likewise carries nothing. The remote MX's reply text is not
-// retained; that a next hop took responsibility is the event.
-type StatsDataDelivered struct{}
+// StatsDataDelivered carries where the message was sent from and the SMTP
+// session it was accepted in, the reply and the queue ID it was given included.
+// Rows written before IP pools are {}, and rows written before sessions were
+// recorded have no smtp.
+type StatsDataDelivered struct {
+	IPPool   string         `json:"ipPool,omitempty"`
+	SourceIP string         `json:"sourceIp,omitempty"`
+	SMTP     *StatsDataSMTP `json:"smtp,omitempty"`
+}
+
+// StatsDataSMTP is the SMTP session an outcome of the SMTPSender came out of.
+// It is omitted rather than stored empty, as protojson leaves out an unset
+// message, so that an outcome without one is stored as it always was.
+type StatsDataSMTP struct {
+	MXHost       string `json:"mxHost,omitempty"`
+	MXIP         string `json:"mxIp,omitempty"`
+	Stage        string `json:"stage,omitempty"`
+	EnhancedCode string `json:"enhancedCode,omitempty"`
+	TLSVersion   string `json:"tlsVersion,omitempty"`
+	TLSCipher    string `json:"tlsCipher,omitempty"`
+	Reply        string `json:"reply,omitempty"`
+	QueueID      string `json:"queueId,omitempty"`
+	Transcript   string `json:"transcript,omitempty"`
+}
+
+func statsDataSMTPFrom(s stats.SMTPSession) *StatsDataSMTP {
+	if s == (stats.SMTPSession{}) {
+		return nil
+	}
+	d := StatsDataSMTP(s)
+	return &d
+}
+
+func (d *StatsDataSMTP) session() stats.SMTPSession {
+	if d == nil {
+		return stats.SMTPSession{}
+	}
+	return stats.SMTPSession(*d)
+}
 
 // StatsDataRejected carries Kannon's own account of why a Recipient was refused.
 type StatsDataRejected struct {
@@ -67,17 +111,45 @@ type StatsDataFailed struct {
 // classification of that reply by SMTP class and not of the retry decision that
 // led here (#378, #433).
 type StatsDataBounced struct {
-	Permanent bool   `json:"permanent,omitempty"`
-	Code      uint32 `json:"code,omitempty"`
-	Msg       string `json:"msg,omitempty"`
+	Permanent bool           `json:"permanent,omitempty"`
+	Code      uint32         `json:"code,omitempty"`
+	Msg       string         `json:"msg,omitempty"`
+	IPPool    string         `json:"ipPool,omitempty"`
+	SourceIP  string         `json:"sourceIp,omitempty"`
+	SMTP      *StatsDataSMTP `json:"smtp,omitempty"`
+	Category  string         `json:"category,omitempty"`
+}
+
+// StatsDataDelayed is a DSN whose remote MTA is still trying: not terminal,
+// and quoting the report as an asynchronous Bounce does.
+type StatsDataDelayed struct {
+	Code uint32         `json:"code,omitempty"`
+	Msg  string         `json:"msg,omitempty"`
+	SMTP *StatsDataSMTP `json:"smtp,omitempty"`
+}
+
+// StatsDataReported is a DSN saying the message was delivered, relayed or
+// expanded: not terminal, and naming the remote MTA as a Delayed does.
+type StatsDataReported struct {
+	Action string         `json:"action,omitempty"`
+	SMTP   *StatsDataSMTP `json:"smtp,omitempty"`
 }
 
 // StatsDataError is the transient retry signal CONTEXT.md keeps out of the
 // shared language. It is stored because it travels the kannon.stats.* path
 // today, not because it is an outcome of the Delivery.
 type StatsDataError struct {
-	Code uint32 `json:"code,omitempty"`
-	Msg  string `json:"msg,omitempty"`
+	Code uint32         `json:"code,omitempty"`
+	Msg  string         `json:"msg,omitempty"`
+	SMTP *StatsDataSMTP `json:"smtp,omitempty"`
+}
+
+// StatsDataPostponed is an Envelope the SMTPSender held back and the
+// Dispatcher scheduled again at Until. Like StatsDataError it is stored because
+// it travels the kannon.stats.* path, not because it is an outcome.
+type StatsDataPostponed struct {
+	Until  time.Time `json:"until"`
+	Reason string    `json:"reason,omitempty"`
 }
 
 // StatsDataOpened holds the request detail retained under the Full Tracking Mode
@@ -107,7 +179,11 @@ func StatsDataFromOutcome(o stats.Outcome) StatsData {
 	case stats.TypeAccepted:
 		return StatsData{Accepted: &StatsDataAccepted{}}
 	case stats.TypeDelivered:
-		return StatsData{Delivered: &StatsDataDelivered{}}
+		return StatsData{Delivered: &StatsDataDelivered{
+			IPPool:   o.IPPool(),
+			SourceIP: o.SourceIP(),
+			SMTP:     statsDataSMTPFrom(o.SMTP()),
+		}}
 	case stats.TypeRejected:
 		return StatsData{Rejected: &StatsDataRejected{Reason: o.Reason()}}
 	case stats.TypeFailed:
@@ -117,9 +193,19 @@ func StatsDataFromOutcome(o stats.Outcome) StatsData {
 			Permanent: o.Permanent(),
 			Code:      o.Code(),
 			Msg:       o.Msg(),
+			IPPool:    o.IPPool(),
+			SourceIP:  o.SourceIP(),
+			SMTP:      statsDataSMTPFrom(o.SMTP()),
+			Category:  string(o.Category()),
 		}}
+	case stats.TypeDelayed:
+		return StatsData{Delayed: &StatsDataDelayed{Code: o.Code(), Msg: o.Msg(), SMTP: statsDataSMTPFrom(o.SMTP())}}
+	case stats.TypeReported:
+		return StatsData{Reported: &StatsDataReported{Action: o.Action(), SMTP: statsDataSMTPFrom(o.SMTP())}}
 	case stats.TypeError:
-		return StatsData{Error: &StatsDataError{Code: o.Code(), Msg: o.Msg()}}
+		return StatsData{Error: &StatsDataError{Code: o.Code(), Msg: o.Msg(), SMTP: statsDataSMTPFrom(o.SMTP())}}
+	case stats.TypePostponed:
+		return StatsData{Postponed: &StatsDataPostponed{Until: o.Until(), Reason: o.Reason()}}
 	case stats.TypeOpened:
 		return StatsData{Opened: &StatsDataOpened{UserAgent: o.UserAgent(), IP: o.IP()}}
 	case stats.TypeClicked:
@@ -156,11 +242,15 @@ func (d StatsData) Outcome() stats.Outcome {
 	case d.Accepted != nil:
 		return stats.Accepted()
 	case d.Delivered != nil:
-		return stats.Delivered()
+		return stats.Delivered().WithSource(d.Delivered.IPPool, d.Delivered.SourceIP).
+			WithSMTP(d.Delivered.SMTP.session())
 	case d.Failed != nil:
 		return stats.Failed(d.Failed.Reason)
 	case d.Bounced != nil:
-		return stats.Bounced(d.Bounced.Permanent, d.Bounced.Code, d.Bounced.Msg)
+		return stats.Bounced(d.Bounced.Permanent, d.Bounced.Code, d.Bounced.Msg).
+			WithSource(d.Bounced.IPPool, d.Bounced.SourceIP).
+			WithSMTP(d.Bounced.SMTP.session()).
+			WithCategory(bounce.Category(d.Bounced.Category))
 	case d.Opened != nil:
 		return stats.Opened(d.Opened.UserAgent, d.Opened.IP)
 	case d.Clicked != nil:
@@ -168,7 +258,13 @@ func (d StatsData) Outcome() stats.Outcome {
 	case d.Rejected != nil:
 		return stats.Rejected(d.Rejected.Reason)
 	case d.Error != nil:
-		return stats.Errored(d.Error.Code, d.Error.Msg)
+		return stats.Errored(d.Error.Code, d.Error.Msg).WithSMTP(d.Error.SMTP.session())
+	case d.Delayed != nil:
+		return stats.Delayed(d.Delayed.Code, d.Delayed.Msg).WithSMTP(d.Delayed.SMTP.session())
+	case d.Postponed != nil:
+		return stats.Postponed(d.Postponed.Until, d.Postponed.Reason)
+	case d.Reported != nil:
+		return stats.Reported(d.Reported.Action).WithSMTP(d.Reported.SMTP.session())
 	default:
 		return stats.Outcome{}
 	}
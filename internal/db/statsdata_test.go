@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/stats"
 	"github.com/kannon-email/kannon/internal/values"
 	"github.com/stretchr/testify/assert"
@@ -36,7 +37,16 @@ func TestStatsDataGoldenShape(t *testing.T) {
 		{"rejected", stats.Rejected("bad addr"), `{"rejected":{"reason":"bad addr"}}`},
 		{"failed", stats.Failed("budget spent"), `{"failed":{"reason":"budget spent"}}`},
 		{"bounced", stats.Bounced(true, 550, "no such user"), `{"bounced":{"permanent":true,"code":550,"msg":"no such user"}}`},
+		{"delivered/from a pool", stats.Delivered().WithSource("bulk", "192.0.2.1"), `{"delivered":{"ipPool":"bulk","sourceIp":"192.0.2.1"}}`},
+		{"bounced/from a pool", stats.Bounced(true, 550, "no").WithSource("bulk", "192.0.2.1"), `{"bounced":{"permanent":true,"code":550,"msg":"no","ipPool":"bulk","sourceIp":"192.0.2.1"}}`},
 		{"error", stats.Errored(421, "try later"), `{"error":{"code":421,"msg":"try later"}}`},
+		{"delayed", stats.Delayed(451, "smtp; 451 4.4.1 no answer").WithSMTP(stats.SMTPSession{MXHost: "mx.example.com", EnhancedCode: "4.4.1"}), `{"delayed":{"code":451,"msg":"smtp; 451 4.4.1 no answer","smtp":{"mxHost":"mx.example.com","enhancedCode":"4.4.1"}}}`},
+		{"postponed", stats.Postponed(time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), "IP warm-up"), `{"postponed":{"until":"2026-10-20T00:00:00Z","reason":"IP warm-up"}}`},
+		{"reported", stats.Reported("delivered").WithSMTP(stats.SMTPSession{MXHost: "mx.example.com", EnhancedCode: "2.0.0"}), `{"reported":{"action":"delivered","smtp":{"mxHost":"mx.example.com","enhancedCode":"2.0.0"}}}`},
+		{"bounced/in a session", stats.Bounced(true, 550, "no").WithSMTP(stats.SMTPSession{MXHost: "mx.example.com", MXIP: "192.0.2.25", Stage: "rcpt", EnhancedCode: "5.1.1", Reply: "550 5.1.1 no"}), `{"bounced":{"permanent":true,"code":550,"msg":"no","smtp":{"mxHost":"mx.example.com","mxIp":"192.0.2.25","stage":"rcpt","enhancedCode":"5.1.1","reply":"550 5.1.1 no"}}}`},
+		{"bounced/with a category", stats.Bounced(false, 452, "full").WithCategory(bounce.CategoryMailboxFull), `{"bounced":{"code":452,"msg":"full","category":"mailbox_full"}}`},
+		{"delivered/with a queue ID", stats.Delivered().WithSMTP(stats.SMTPSession{EnhancedCode: "2.0.0", TLSVersion: "TLS 1.3", QueueID: "4Xy3Zt"}), `{"delivered":{"smtp":{"enhancedCode":"2.0.0","tlsVersion":"TLS 1.3","queueId":"4Xy3Zt"}}}`},
+		{"error/no reply", stats.Errored(111, "dial tcp: refused").WithSMTP(stats.SMTPSession{MXHost: "mx.example.com", Stage: "connect", EnhancedCode: "4.4.1"}), `{"error":{"code":111,"msg":"dial tcp: refused","smtp":{"mxHost":"mx.example.com","stage":"connect","enhancedCode":"4.4.1"}}}`},
 		{"opened", stats.Opened("curl/8", "1.2.3.4"), `{"opened":{"userAgent":"curl/8","ip":"1.2.3.4"}}`},
 		{"clicked", stats.Clicked("curl/8", "1.2.3.4", "https://example.com/a"), `{"clicked":{"userAgent":"curl/8","ip":"1.2.3.4","url":"https://example.com/a"}}`},
 
@@ -16,11 +16,7 @@ type templatesTestHelper struct{}
 func (h templatesTestHelper) CreateDomain(t *testing.T) values.DomainName {
 	ctx := t.Context()
 	domainName := fmt.Sprintf("test-tpl-%d.com", time.Now().UnixNano())
-	_, err := q.CreateDomain(ctx, CreateDomainParams{
-		Domain:         domainName,
-		DkimPrivateKey: "test-private",
-		DkimPublicKey:  "test-public",
-	})
+	_, err := q.CreateDomain(ctx, domainName)
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		cleanupCtx := context.Background()
@@ -35,6 +35,7 @@ type Delivery struct {
 	backoff               BackoffPolicy
 	retryWindow           time.Duration
 	tracking              tracking.Policy
+	smimeCertificate      []byte
 }
 
 // NewParams contains all fields needed to create a fresh Delivery.
@@ -51,6 +52,10 @@ type NewParams struct {
 	// Tracking is the effective Tracking Policy for this Delivery, already
 	// resolved by the caller at intake (ADR 0003).
 	Tracking tracking.Policy
+	// SMIMECertificate is the DER certificate the Recipient asked to have
+	// this Delivery encrypted to, already checked by the caller at intake;
+	// nil sends it unencrypted.
+	SMIMECertificate []byte
 }
 
 // New creates a new Delivery scheduled for first attempt. The Tracking Policy
@@ -76,6 +81,7 @@ func New(p NewParams) (*Delivery, error) {
 		backoff:               policyOrDefault(p.Backoff),
 		retryWindow:           windowOrDefault(p.RetryWindow),
 		tracking:              p.Tracking.Normalized(),
+		smimeCertificate:      p.SMIMECertificate,
 	}, nil
 }
 
@@ -91,6 +97,7 @@ type LoadParams struct {
 	Backoff               BackoffPolicy
 	RetryWindow           time.Duration
 	Tracking              tracking.Policy
+	SMIMECertificate      []byte
 }
 
 // Load rehydrates a Delivery from stored data (used by repository implementations).
@@ -106,6 +113,7 @@ func Load(p LoadParams) *Delivery {
 		backoff:               policyOrDefault(p.Backoff),
 		retryWindow:           windowOrDefault(p.RetryWindow),
 		tracking:              p.Tracking,
+		smimeCertificate:      p.SMIMECertificate,
 	}
 }
 
@@ -127,6 +135,10 @@ func (d *Delivery) OriginalScheduledTime() time.Time {
 // applied to it (ADR 0003). Nothing downstream resolves it again.
 func (d *Delivery) TrackingPolicy() tracking.Policy { return d.tracking }
 
+// SMIMECertificate is the DER certificate this Delivery is encrypted to, nil
+// when the Recipient supplied none (internal/smime).
+func (d *Delivery) SMIMECertificate() []byte { return d.smimeCertificate }
+
 // NextRetryAt returns the time at which this Delivery should next be
 // attempted, given its current attempt count and the original scheduled
 // time. The repository uses this when applying a reschedule.
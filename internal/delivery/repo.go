@@ -61,6 +61,11 @@ type Repository interface {
 	// counter and rolls the scheduled time forward by NextRetryAt.
 	Reschedule(ctx context.Context, batchID batch.ID, email string) error
 
+	// Postpone hands a Delivery claimed for dispatch back to be claimed again
+	// at until, leaving its attempt counter alone. A Delivery that is no
+	// longer claimed for dispatch is left as it is.
+	Postpone(ctx context.Context, batchID batch.ID, email string, until time.Time) error
+
 	// Clean removes a terminated Delivery.
 	Clean(ctx context.Context, batchID batch.ID, email string) error
 
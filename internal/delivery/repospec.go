@@ -36,12 +36,18 @@ func RunRepoSpec(t *testing.T, repo Repository, helper RepoTestHelper) {
 	t.Run("Reschedule", func(t *testing.T) {
 		testReschedule(t, repo, helper)
 	})
+	t.Run("Postpone", func(t *testing.T) {
+		testPostpone(t, repo, helper)
+	})
 	t.Run("Clean", func(t *testing.T) {
 		testClean(t, repo, helper)
 	})
 	t.Run("TrackingPolicy", func(t *testing.T) {
 		testTrackingPolicy(t, repo, helper)
 	})
+	t.Run("SMIMECertificate", func(t *testing.T) {
+		testSMIMECertificate(t, repo, helper)
+	})
 }
 
 func newDelivery(t *testing.T, batchID batch.ID, domain, email string) *Delivery {
@@ -174,6 +180,35 @@ func testReschedule(t *testing.T, repo Repository, helper RepoTestHelper) {
 		"scheduled time should advance after reschedule")
 }
 
+func testPostpone(t *testing.T, repo Repository, helper RepoTestHelper) {
+	ctx := t.Context()
+	batchID, domain := helper.CreateBatch(t)
+	email := "p@" + domain
+	require.NoError(t, repo.Schedule(ctx, newDelivery(t, batchID, domain, email)))
+	require.NoError(t, repo.SetScheduled(ctx, batchID, email))
+	_, err := repo.PrepareForSend(ctx, 1000)
+	require.NoError(t, err)
+
+	until := time.Now().Add(time.Hour).UTC()
+	require.NoError(t, repo.Postpone(ctx, batchID, email, until))
+
+	got, err := repo.Get(ctx, batchID, email)
+	require.NoError(t, err)
+	assert.Equal(t, 0, got.SendAttempts(), "a postponed Delivery has spent no attempt")
+	assert.WithinDuration(t, until, got.ScheduledTime(), time.Second)
+
+	claimed, err := repo.PrepareForSend(ctx, 1000)
+	require.NoError(t, err)
+	for _, d := range claimed {
+		assert.False(t, d.BatchID() == batchID && d.Email() == email, "a postponed Delivery is not claimed before until")
+	}
+
+	require.NoError(t, repo.Postpone(ctx, batchID, email, time.Now().Add(2*time.Hour)))
+	got, err = repo.Get(ctx, batchID, email)
+	require.NoError(t, err)
+	assert.WithinDuration(t, until, got.ScheduledTime(), time.Second, "a Delivery not claimed for dispatch is left alone")
+}
+
 // testTrackingPolicy asserts the Pool round-trips the Policy frozen on the
 // Delivery: the Builder reads it back on the dispatch path, so a Policy that
 // changed shape in storage would silently change what is tracked.
@@ -203,6 +238,33 @@ func testTrackingPolicy(t *testing.T, repo Repository, helper RepoTestHelper) {
 	})
 }
 
+// testSMIMECertificate asserts the Pool round-trips the certificate a
+// Recipient asked to be encrypted to, byte for byte, and keeps none for a
+// Recipient that asked for nothing.
+func testSMIMECertificate(t *testing.T, repo Repository, helper RepoTestHelper) {
+	ctx := t.Context()
+	batchID, domain := helper.CreateBatch(t)
+
+	cert := []byte{0x30, 0x03, 0x02, 0x01, 0x00}
+	encrypted, err := New(NewParams{
+		BatchID:          batchID,
+		Email:            "e@" + domain,
+		Domain:           domain,
+		ScheduledTime:    time.Now().UTC(),
+		SMIMECertificate: cert,
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Schedule(ctx, encrypted, newDelivery(t, batchID, domain, "p@"+domain)))
+
+	got, err := repo.Get(ctx, batchID, "e@"+domain)
+	require.NoError(t, err)
+	assert.Equal(t, cert, got.SMIMECertificate())
+
+	plain, err := repo.Get(ctx, batchID, "p@"+domain)
+	require.NoError(t, err)
+	assert.Empty(t, plain.SMIMECertificate())
+}
+
 func testClean(t *testing.T, repo Repository, helper RepoTestHelper) {
 	ctx := t.Context()
 	batchID, domain := helper.CreateBatch(t)
@@ -0,0 +1,134 @@
+package dkim_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	msgauth "github.com/emersion/go-msgauth/dkim"
+	"github.com/kannon-email/kannon/internal/dkim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dualKeys generates an RSA and an Ed25519 key and serves both records from a
+// fake DNS, keyed by the name each is published at.
+func dualKeys(t *testing.T) ([]dkim.SigningKey, func(string) ([]string, error)) {
+	t.Helper()
+
+	records := map[string][]string{}
+	var keys []dkim.SigningKey
+	for _, alg := range []string{dkim.AlgorithmRSA, dkim.AlgorithmEd25519} {
+		pair, err := dkim.GenerateDKIMKeysPair(alg)
+		require.NoError(t, err)
+		selector := "kannon-" + alg
+		keys = append(keys, dkim.SigningKey{Selector: selector, PrivateKey: pair.PrivateKey})
+		records[dkim.RecordName(selector, "test.com")] = []string{dkim.RecordValue(alg, pair.PublicKey)}
+	}
+	return keys, func(name string) ([]string, error) {
+		if r, ok := records[name]; ok {
+			return r, nil
+		}
+		return nil, errors.New("no such record: " + name)
+	}
+}
+
+func TestDualSignatureVerifies(t *testing.T) {
+	keys, lookup := dualKeys(t)
+
+	signed, err := dkim.SignMessageWithKeys("test.com", signedHeaders, keys, []byte(plainMessage))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(signed), "DKIM-Signature:"), "one signature per key")
+
+	verifications, err := msgauth.VerifyWithOptions(bytes.NewReader(signed), &msgauth.VerifyOptions{LookupTXT: lookup})
+	require.NoError(t, err)
+	require.Len(t, verifications, 2)
+
+	for _, v := range verifications {
+		assert.NoError(t, v.Err)
+		assert.Equal(t, "test.com", v.Domain)
+	}
+}
+
+// A verifier that knows only RSA — or a resolver that lost the Ed25519 record —
+// still finds the RSA signature intact beside the one it cannot check, which is
+// the point of signing twice.
+func TestDualSignatureSurvivesAnUncheckableSibling(t *testing.T) {
+	keys, lookup := dualKeys(t)
+
+	signed, err := dkim.SignMessageWithKeys("test.com", signedHeaders, keys, []byte(plainMessage))
+	require.NoError(t, err)
+
+	verifications, err := msgauth.VerifyWithOptions(bytes.NewReader(signed), &msgauth.VerifyOptions{
+		LookupTXT: func(name string) ([]string, error) {
+			if strings.HasPrefix(name, "kannon-"+dkim.AlgorithmEd25519+".") {
+				return nil, errors.New("record lost")
+			}
+			return lookup(name)
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, verifications, 2)
+
+	var passed int
+	for _, v := range verifications {
+		if v.Err == nil {
+			passed++
+		}
+	}
+	assert.Equal(t, 1, passed, "the RSA signature still verifies")
+}
+
+// Tampering breaks both, or a dual signature would be weaker than a single one.
+func TestDualSignatureBreaksTogether(t *testing.T) {
+	keys, lookup := dualKeys(t)
+
+	signed, err := dkim.SignMessageWithKeys("test.com", signedHeaders, keys, []byte(plainMessage))
+	require.NoError(t, err)
+	tampered := strings.Replace(string(signed), "Subject: Hello", "Subject: Hullo", 1)
+
+	verifications, err := msgauth.VerifyWithOptions(strings.NewReader(tampered), &msgauth.VerifyOptions{LookupTXT: lookup})
+	require.NoError(t, err)
+	for _, v := range verifications {
+		assert.Error(t, v.Err)
+	}
+}
+
+func TestSigningWithNoKeyIsRefused(t *testing.T) {
+	_, err := dkim.SignMessageWithKeys("test.com", signedHeaders, nil, []byte(plainMessage))
+	assert.ErrorIs(t, err, dkim.ErrNoSigningKey)
+}
+
+// One Signer is reused for a whole Batch, from several workers at once; each
+// message it signs must verify on its own.
+func TestSignerIsReusableAcrossMessages(t *testing.T) {
+	keys, lookup := dualKeys(t)
+	s, err := dkim.NewSigner("test.com", signedHeaders, keys)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			msg := strings.Replace(plainMessage, "Subject: Hello", fmt.Sprintf("Subject: Hello %d", i), 1)
+			signed, err := s.Sign([]byte(msg))
+			assert.NoError(t, err)
+
+			verifications, err := msgauth.VerifyWithOptions(bytes.NewReader(signed), &msgauth.VerifyOptions{LookupTXT: lookup})
+			assert.NoError(t, err)
+			for _, v := range verifications {
+				assert.NoError(t, v.Err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSignerRefusesAnUndecodableKey(t *testing.T) {
+	_, err := dkim.NewSigner("test.com", signedHeaders, []dkim.SigningKey{{Selector: "s", PrivateKey: "not base64!"}})
+	assert.Error(t, err)
+}
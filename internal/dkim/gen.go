@@ -1,27 +1,72 @@
 package dkim
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
+	"errors"
 )
 
+// ErrUnsupportedAlgorithm is returned for a key algorithm Kannon cannot sign with.
+var ErrUnsupportedAlgorithm = errors.New("unsupported DKIM key algorithm")
+
+// ErrUnsupportedKeySize is returned for an RSA key size Kannon does not generate.
+var ErrUnsupportedKeySize = errors.New("unsupported DKIM RSA key size: want 1024, 2048 or 4096")
+
+// RSA key sizes, in bits.
+const (
+	// DefaultRSABits is the size of an RSA key generated without one being asked for, and the
+	// size RFC 8301 recommends.
+	DefaultRSABits = 2048
+	// MinRSABits is the smallest RSA key RFC 8301 lets a verifier accept. A smaller key is
+	// refused, generated or imported, since mail signed with it would fail DKIM anyway.
+	MinRSABits = 1024
+)
+
+// Ed25519Bits is the size of every Ed25519 key.
+const Ed25519Bits = 256
+
 // KeysPair sturct contains public and private key in base64 ecoding
 type KeysPair struct {
 	PrivateKey string
 	PublicKey  string
 }
 
-// GenerateDKIMKeysPair generates DKIM private and public keys pair
-func GenerateDKIMKeysPair() (KeysPair, error) {
-	reader := rand.Reader
-	bitSize := 2048
-	key, err := rsa.GenerateKey(reader, bitSize)
+// GenerateDKIMKeysPair generates DKIM private and public keys pair for algorithm, one of
+// AlgorithmRSA or AlgorithmEd25519. The public key is encoded the way the algorithm's p= tag
+// wants it, which is not the same way for both: RFC 8463 publishes the bare Ed25519 key where an
+// RSA record carries a SubjectPublicKeyInfo.
+func GenerateDKIMKeysPair(algorithm string) (KeysPair, error) {
+	switch algorithm {
+	case AlgorithmRSA:
+		return GenerateRSAKeysPair(DefaultRSABits)
+	case AlgorithmEd25519:
+		return generateEd25519()
+	default:
+		return KeysPair{}, ErrUnsupportedAlgorithm
+	}
+}
+
+// GenerateRSAKeysPair generates an RSA key pair of bits, one of 1024, 2048 or 4096. 1024 is there
+// for the DNS hosts that cannot publish a TXT record longer than 255 bytes, which a 2048-bit key
+// overflows; 4096 for the operators whose policy asks for it, at the cost of a record most hosts
+// must split.
+func GenerateRSAKeysPair(bits int) (KeysPair, error) {
+	switch bits {
+	case 1024, 2048, 4096:
+	default:
+		return KeysPair{}, ErrUnsupportedKeySize
+	}
+	key, err := rsa.GenerateKey(rand.Reader, bits)
 	if err != nil {
 		return KeysPair{}, err
 	}
+	return rsaKeysPair(key)
+}
 
+func rsaKeysPair(key *rsa.PrivateKey) (KeysPair, error) {
 	privateKey := exportRsaPrivateKeyAsStr(key)
 	publicKey, err := exportRsaPublicKeyAsStr(&key.PublicKey)
 	if err != nil {
@@ -33,6 +78,23 @@ func GenerateDKIMKeysPair() (KeysPair, error) {
 	}, nil
 }
 
+// generateEd25519 stores the private key as PKCS#8, the one DER form that can hold it; decodeKey
+// tells it from a PKCS#1 RSA key by trying both.
+func generateEd25519() (KeysPair, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return KeysPair{}, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return KeysPair{}, err
+	}
+	return KeysPair{
+		PrivateKey: base64.StdEncoding.EncodeToString(der),
+		PublicKey:  base64.StdEncoding.EncodeToString(pub),
+	}, nil
+}
+
 func exportRsaPrivateKeyAsStr(privkey *rsa.PrivateKey) string {
 	privkeyBytes := x509.MarshalPKCS1PrivateKey(privkey)
 	return base64.StdEncoding.EncodeToString(privkeyBytes)
@@ -1,9 +1,14 @@
 package dkim
 
-import "testing"
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
 
 func TestDKIMKeyGeneration(t *testing.T) {
-	dkimKeys, err := GenerateDKIMKeysPair()
+	dkimKeys, err := GenerateDKIMKeysPair(AlgorithmRSA)
 	if err != nil {
 		t.Errorf("Cannot generate key, %v", err)
 	}
@@ -16,3 +21,31 @@ func TestDKIMKeyGeneration(t *testing.T) {
 		t.Errorf("public key is not valid: %v", dkimKeys.PublicKey)
 	}
 }
+
+// RFC 8463 §4 publishes the bare 32-byte key, not a SubjectPublicKeyInfo, and a
+// record in the RSA shape would not verify anywhere.
+func TestEd25519KeyGeneration(t *testing.T) {
+	dkimKeys, err := GenerateDKIMKeysPair(AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("Cannot generate key, %v", err)
+	}
+
+	pub, err := base64.StdEncoding.DecodeString(dkimKeys.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		t.Errorf("public key is not a bare Ed25519 key: %q", dkimKeys.PublicKey)
+	}
+
+	signer, err := decodeKey(dkimKeys.PrivateKey)
+	if err != nil {
+		t.Fatalf("private key does not decode: %v", err)
+	}
+	if _, ok := signer.(ed25519.PrivateKey); !ok {
+		t.Errorf("private key decoded as %T", signer)
+	}
+}
+
+func TestUnsupportedAlgorithm(t *testing.T) {
+	if _, err := GenerateDKIMKeysPair("dsa"); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("expected ErrUnsupportedAlgorithm, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package dkim
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Import errors.
+var (
+	ErrMalformedKey = errors.New("malformed DKIM private key: want one unencrypted PEM block, PKCS#1 or PKCS#8")
+	ErrWeakKey      = errors.New("DKIM RSA key is shorter than 1024 bits")
+)
+
+// ImportedKey is a private key brought from elsewhere, in the form a generated key is stored in,
+// with what was learnt about it on the way.
+type ImportedKey struct {
+	KeysPair
+	Algorithm string
+	Bits      int
+}
+
+// ImportPrivateKey reads a PEM-encoded private key — an "RSA PRIVATE KEY" block (PKCS#1) or a
+// "PRIVATE KEY" block (PKCS#8) holding an RSA or Ed25519 key — and derives its public half.
+//
+// The key is re-encoded, not stored as given: RSA as PKCS#1 and Ed25519 as PKCS#8, as
+// GenerateDKIMKeysPair stores them, so that signing never has to care where a key came from. An
+// encrypted block, trailing data after the block, or a key that fails its own consistency check is
+// ErrMalformedKey; an RSA key under MinRSABits is ErrWeakKey; any other key type is
+// ErrUnsupportedAlgorithm.
+func ImportPrivateKey(pemText string) (ImportedKey, error) {
+	block, rest := pem.Decode([]byte(strings.TrimSpace(pemText)))
+	if block == nil || len(strings.TrimSpace(string(rest))) > 0 || len(block.Headers) > 0 {
+		return ImportedKey{}, ErrMalformedKey
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return ImportedKey{}, ErrMalformedKey
+	}
+	if err != nil {
+		return ImportedKey{}, fmt.Errorf("%w: %v", ErrMalformedKey, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return importRSA(k)
+	case ed25519.PrivateKey:
+		return importEd25519(k)
+	default:
+		return ImportedKey{}, ErrUnsupportedAlgorithm
+	}
+}
+
+func importRSA(key *rsa.PrivateKey) (ImportedKey, error) {
+	if err := key.Validate(); err != nil {
+		return ImportedKey{}, fmt.Errorf("%w: %v", ErrMalformedKey, err)
+	}
+	bits := key.N.BitLen()
+	if bits < MinRSABits {
+		return ImportedKey{}, ErrWeakKey
+	}
+	pair, err := rsaKeysPair(key)
+	if err != nil {
+		return ImportedKey{}, err
+	}
+	return ImportedKey{KeysPair: pair, Algorithm: AlgorithmRSA, Bits: bits}, nil
+}
+
+func importEd25519(key ed25519.PrivateKey) (ImportedKey, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return ImportedKey{}, fmt.Errorf("%w: %v", ErrMalformedKey, err)
+	}
+	return ImportedKey{
+		KeysPair: KeysPair{
+			PrivateKey: base64.StdEncoding.EncodeToString(der),
+			PublicKey:  base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		},
+		Algorithm: AlgorithmEd25519,
+		Bits:      Ed25519Bits,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package dkim
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Key algorithms, as the k= tag of a key record names them.
+const (
+	// AlgorithmRSA is rsa-sha256, which every verifier understands.
+	AlgorithmRSA = "rsa"
+	// AlgorithmEd25519 is RFC 8463's ed25519-sha256: a far shorter key and
+	// signature, but not yet verified everywhere, so it is signed with beside an
+	// RSA key rather than instead of one.
+	AlgorithmEd25519 = "ed25519"
+)
+
+// ErrInvalidSelector is returned for a selector that cannot be published as a
+// DNS name under _domainkey.
+var ErrInvalidSelector = errors.New("invalid DKIM selector")
+
+// selectorRe is RFC 6376's selector, a dot-separated sequence of sub-domain
+// labels, in its lower-case form: DNS compares it case-insensitively, so two
+// spellings of one selector would name one record but two rows.
+var selectorRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
+// ValidateSelector reports whether selector can be published and signed with.
+func ValidateSelector(selector string) error {
+	if len(selector) > 253 || !selectorRe.MatchString(selector) {
+		return ErrInvalidSelector
+	}
+	return nil
+}
+
+// RecordName is the DNS name a selector's key record is published at.
+func RecordName(selector, domain string) string {
+	return selector + "._domainkey." + domain
+}
+
+// RecordValue is the TXT record that publishes a public key.
+func RecordValue(algorithm, publicKey string) string {
+	return "v=DKIM1; k=" + algorithm + "; p=" + publicKey
+}
+
+// RecordPublishes reports whether one of the TXT records found at a selector's
+// name publishes publicKey. Only the p= tag is compared: the operator may well
+// have written the other tags differently, or split the key over several
+// strings, and a verifier would accept either.
+func RecordPublishes(records []string, publicKey string) bool {
+	for _, record := range records {
+		for _, tag := range strings.Split(record, ";") {
+			name, value, ok := strings.Cut(tag, "=")
+			if !ok || strings.TrimSpace(name) != "p" {
+				continue
+			}
+			if strings.Join(strings.Fields(value), "") == publicKey {
+				return true
+			}
+		}
+	}
+	return false
+}
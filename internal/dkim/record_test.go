@@ -0,0 +1,32 @@
+package dkim_test
+
+import (
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/dkim"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSelector(t *testing.T) {
+	for _, s := range []string{"kannon", "kannon-20261019-090000", "s1.mail", "2026"} {
+		assert.NoError(t, dkim.ValidateSelector(s), s)
+	}
+	for _, s := range []string{"", "Kannon", "-kannon", "kannon-", "a..b", "with space", "under_score", "kannon.", "a;b"} {
+		assert.ErrorIs(t, dkim.ValidateSelector(s), dkim.ErrInvalidSelector, s)
+	}
+}
+
+// The check ActivateDKIMKey relies on: a record counts as published when its
+// p= tag carries the key, however the operator laid out the rest of it.
+func TestRecordPublishes(t *testing.T) {
+	const pub = "MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAq"
+
+	assert.True(t, dkim.RecordPublishes([]string{dkim.RecordValue(dkim.AlgorithmRSA, pub)}, pub))
+	assert.True(t, dkim.RecordPublishes([]string{"v=DKIM1;p=MIIBIjANBgkqhkiG9w0BAQEF AAOCAQ8AMIIBCgKCAQEAq;k=rsa"}, pub),
+		"a key split by whitespace is the same key")
+	assert.True(t, dkim.RecordPublishes([]string{"google-site-verification=x", "v=DKIM1; p=" + pub}, pub))
+
+	assert.False(t, dkim.RecordPublishes(nil, pub))
+	assert.False(t, dkim.RecordPublishes([]string{"v=DKIM1; k=rsa; p=other"}, pub))
+	assert.False(t, dkim.RecordPublishes([]string{"v=DKIM1; k=rsa; p="}, pub), "an empty p= is a revoked key")
+}
@@ -2,13 +2,19 @@ package dkim
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
+	"errors"
 
 	"github.com/emersion/go-msgauth/dkim"
 )
 
+// ErrNoSigningKey is returned when a message is to be signed with no key at all.
+var ErrNoSigningKey = errors.New("no DKIM signing key")
+
 // SignData to pass to dkim
 type SignData struct {
 	PrivateKey string
@@ -17,6 +23,13 @@ type SignData struct {
 	Headers    []string
 }
 
+// SigningKey is one key a message is signed with, named by the selector its
+// public half is published under.
+type SigningKey struct {
+	Selector   string
+	PrivateKey string
+}
+
 // SignMessage signes an email message with DKIM
 func SignMessage(data SignData, reader *bytes.Reader) ([]byte, error) {
 	signer, err := decodeKey(data.PrivateKey)
@@ -37,10 +50,94 @@ func SignMessage(data SignData, reader *bytes.Reader) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
-func decodeKey(dkimPrivateKey string) (*rsa.PrivateKey, error) {
-	dkimPrivateKeyInBytes, err := base64.StdEncoding.DecodeString(dkimPrivateKey)
+// SignMessageWithKeys adds one DKIM-Signature per key, so that a verifier that
+// understands only one of the algorithms still finds a signature it can check
+// (RFC 8463 §1). Each signature is made over the output of the previous one;
+// none names DKIM-Signature in its h= list, so adding the next does not break
+// the last.
+func SignMessageWithKeys(domain string, headers []string, keys []SigningKey, msg []byte) ([]byte, error) {
+	s, err := NewSigner(domain, headers, keys)
+	if err != nil {
+		return nil, err
+	}
+	return s.Sign(msg)
+}
+
+// Signer signs messages for one Domain with its keys decoded once. Decoding a
+// stored key is a base64 pass and an ASN.1 parse, which is nothing against one
+// message and most of the overhead against a Batch of them: the Builder holds one
+// Signer per Batch and signs every Delivery of it with that.
+//
+// A Signer is safe for concurrent use.
+type Signer struct {
+	domain  string
+	headers []string
+	keys    []decodedKey
+}
+
+type decodedKey struct {
+	selector string
+	signer   crypto.Signer
+}
+
+// NewSigner decodes keys for signing domain's mail over headers, in the order
+// SignMessageWithKeys signs with them. It fails with ErrNoSigningKey when there is
+// no key at all, so a Domain that cannot sign is found out before anything is
+// rendered.
+func NewSigner(domain string, headers []string, keys []SigningKey) (*Signer, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoSigningKey
+	}
+	s := &Signer{domain: domain, headers: headers, keys: make([]decodedKey, 0, len(keys))}
+	for _, k := range keys {
+		signer, err := decodeKey(k.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		s.keys = append(s.keys, decodedKey{selector: k.Selector, signer: signer})
+	}
+	return s, nil
+}
+
+// Sign adds one DKIM-Signature per key to msg, as SignMessageWithKeys does.
+func (s *Signer) Sign(msg []byte) ([]byte, error) {
+	for _, k := range s.keys {
+		options := &dkim.SignOptions{
+			Domain:     s.domain,
+			Selector:   k.selector,
+			Signer:     k.signer,
+			HeaderKeys: s.headers,
+		}
+		var b bytes.Buffer
+		if err := dkim.Sign(&b, bytes.NewReader(msg), options); err != nil {
+			return nil, err
+		}
+		msg = b.Bytes()
+	}
+	return msg, nil
+}
+
+// decodeKey reads a stored private key. An RSA key generated before Ed25519
+// existed here is PKCS#1; anything else is PKCS#8, which is also the only form
+// an Ed25519 key has.
+func decodeKey(dkimPrivateKey string) (crypto.Signer, error) {
+	der, err := base64.StdEncoding.DecodeString(dkimPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
 	if err != nil {
 		return nil, err
 	}
-	return x509.ParsePKCS1PrivateKey(dkimPrivateKeyInBytes)
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
 }
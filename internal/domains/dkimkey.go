@@ -0,0 +1,178 @@
+package domains
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/dkim"
+)
+
+// DKIM key errors.
+var (
+	ErrDKIMKeyNotFound       = errors.New("dkim key not found")
+	ErrDKIMSelectorTaken     = errors.New("dkim selector already in use for this domain")
+	ErrDKIMKeyRetired        = errors.New("dkim key is retired")
+	ErrDKIMKeyActive         = errors.New("dkim key is the last one the domain signs with")
+	ErrDKIMRecordNotVerified = errors.New("dkim record is not published in DNS")
+)
+
+// InitialSelector is the selector a Domain's first key is published under. It was the only
+// selector before keys could be rotated, so every record already in DNS lives at it.
+const InitialSelector = "kannon"
+
+// DKIMKeyState is where a DKIM key stands in a rotation. It is derived from the key's timestamps
+// and from its siblings, never stored: "active" is a property of the Domain's whole key set.
+type DKIMKeyState string
+
+const (
+	// DKIMKeyPending is a key created but not yet activated: its record may not be in DNS yet,
+	// so nothing is signed with it.
+	DKIMKeyPending DKIMKeyState = "pending"
+	// DKIMKeyActive is a key the Domain signs with: the one of its algorithm.
+	DKIMKeyActive DKIMKeyState = "active"
+	// DKIMKeySuperseded is a key that was active until a newer one of its algorithm was activated.
+	// Its record must stay published while mail it signed may still be verified.
+	DKIMKeySuperseded DKIMKeyState = "superseded"
+	// DKIMKeyRetired is a key whose record the operator has withdrawn. It is kept for the record.
+	DKIMKeyRetired DKIMKeyState = "retired"
+)
+
+// DKIMKeyOrigin is where a DKIM key came from. It changes nothing about how the key signs; it
+// is kept because an imported key was generated, and may still be held, somewhere Kannon cannot
+// vouch for, which is worth knowing when deciding what to rotate first.
+type DKIMKeyOrigin string
+
+const (
+	// DKIMKeyGenerated is a key Kannon generated, and only Kannon has held.
+	DKIMKeyGenerated DKIMKeyOrigin = "generated"
+	// DKIMKeyImported is a key an operator brought along, typically from the provider a Domain
+	// migrated from, so that the records already published keep verifying.
+	DKIMKeyImported DKIMKeyOrigin = "imported"
+)
+
+// DKIMKey is one of a Domain's DKIM keys, published under its own selector.
+type DKIMKey struct {
+	selector    string
+	algorithm   string
+	privateKey  string
+	publicKey   string
+	origin      DKIMKeyOrigin
+	bits        int
+	createdAt   time.Time
+	activatedAt time.Time
+	retiredAt   time.Time
+}
+
+// NewDKIMKey generates a pending key of algorithm to be published under selector. An RSA key is
+// dkim.DefaultRSABits long; NewRSADKIMKey chooses another size.
+func NewDKIMKey(selector, algorithm string) (DKIMKey, error) {
+	if algorithm == dkim.AlgorithmRSA {
+		return NewRSADKIMKey(selector, dkim.DefaultRSABits)
+	}
+	if err := dkim.ValidateSelector(selector); err != nil {
+		return DKIMKey{}, err
+	}
+	keys, err := dkim.GenerateDKIMKeysPair(algorithm)
+	if err != nil {
+		return DKIMKey{}, err
+	}
+	return DKIMKey{
+		selector:   selector,
+		algorithm:  algorithm,
+		privateKey: keys.PrivateKey,
+		publicKey:  keys.PublicKey,
+		origin:     DKIMKeyGenerated,
+		bits:       dkim.Ed25519Bits,
+	}, nil
+}
+
+// NewRSADKIMKey generates a pending RSA key of bits — 1024, 2048 or 4096 — to be published under
+// selector.
+func NewRSADKIMKey(selector string, bits int) (DKIMKey, error) {
+	if err := dkim.ValidateSelector(selector); err != nil {
+		return DKIMKey{}, err
+	}
+	keys, err := dkim.GenerateRSAKeysPair(bits)
+	if err != nil {
+		return DKIMKey{}, err
+	}
+	return DKIMKey{
+		selector:   selector,
+		algorithm:  dkim.AlgorithmRSA,
+		privateKey: keys.PrivateKey,
+		publicKey:  keys.PublicKey,
+		origin:     DKIMKeyGenerated,
+		bits:       bits,
+	}, nil
+}
+
+// ImportDKIMKey builds a pending key from a PEM-encoded private key already published under
+// selector elsewhere. Its algorithm and size are read off the key; see dkim.ImportPrivateKey for
+// what is refused.
+func ImportDKIMKey(selector, privateKeyPEM string) (DKIMKey, error) {
+	if err := dkim.ValidateSelector(selector); err != nil {
+		return DKIMKey{}, err
+	}
+	imported, err := dkim.ImportPrivateKey(privateKeyPEM)
+	if err != nil {
+		return DKIMKey{}, err
+	}
+	return DKIMKey{
+		selector:   selector,
+		algorithm:  imported.Algorithm,
+		privateKey: imported.PrivateKey,
+		publicKey:  imported.PublicKey,
+		origin:     DKIMKeyImported,
+		bits:       imported.Bits,
+	}, nil
+}
+
+// DKIMKeyParams contains all fields needed to rehydrate a DKIMKey from storage.
+type DKIMKeyParams struct {
+	Selector    string
+	Algorithm   string
+	PrivateKey  string
+	PublicKey   string
+	Origin      DKIMKeyOrigin
+	Bits        int
+	CreatedAt   time.Time
+	ActivatedAt time.Time
+	RetiredAt   time.Time
+}
+
+// LoadDKIMKey rehydrates a DKIMKey from stored data (used by repository implementations).
+func LoadDKIMKey(p DKIMKeyParams) DKIMKey {
+	return DKIMKey{
+		selector:    p.Selector,
+		algorithm:   p.Algorithm,
+		privateKey:  p.PrivateKey,
+		publicKey:   p.PublicKey,
+		origin:      p.Origin,
+		bits:        p.Bits,
+		createdAt:   p.CreatedAt,
+		activatedAt: p.ActivatedAt,
+		retiredAt:   p.RetiredAt,
+	}
+}
+
+func (k DKIMKey) Selector() string       { return k.selector }
+func (k DKIMKey) Algorithm() string      { return k.algorithm }
+func (k DKIMKey) PrivateKey() string     { return k.privateKey }
+func (k DKIMKey) PublicKey() string      { return k.publicKey }
+func (k DKIMKey) Origin() DKIMKeyOrigin  { return k.origin }
+func (k DKIMKey) Bits() int              { return k.bits }
+func (k DKIMKey) CreatedAt() time.Time   { return k.createdAt }
+func (k DKIMKey) ActivatedAt() time.Time { return k.activatedAt }
+func (k DKIMKey) RetiredAt() time.Time   { return k.retiredAt }
+
+// Activated reports whether the key was ever activated; a pending key was not.
+func (k DKIMKey) Activated() bool { return !k.activatedAt.IsZero() }
+
+// Retired reports whether the key was retired.
+func (k DKIMKey) Retired() bool { return !k.retiredAt.IsZero() }
+
+// RecordName is the DNS name the key's TXT record is published at for domain.
+func (k DKIMKey) RecordName(domain string) string { return dkim.RecordName(k.selector, domain) }
+
+// RecordValue is the TXT record that publishes the key.
+func (k DKIMKey) RecordValue() string { return dkim.RecordValue(k.algorithm, k.publicKey) }
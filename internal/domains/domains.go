@@ -1,5 +1,5 @@
 // Package domains defines the SenderDomain domain entity per CONTEXT.md: the sender-tenant
-// identity (domain name + DKIM key pair) under which Batches are authored and mail is signed. The
+// identity (domain name + DKIM keys) under which Batches are authored and mail is signed. The
 // Go type is Domain for historical reasons; storage row sqlc.Domain, wire payload proto Domain.
 package domains
 
@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/kannon-email/kannon/internal/dkim"
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/smime"
 	"github.com/kannon-email/kannon/internal/tracking"
 	"github.com/kannon-email/kannon/internal/values"
 )
@@ -18,57 +21,149 @@ var (
 )
 
 // Domain is the SenderDomain entity: a sender-tenant identified by its domain
-// name and the DKIM key pair used to sign outgoing mail for it.
+// name, and the DKIM keys it has published to sign outgoing mail with.
 type Domain struct {
-	id             int32
-	domain         values.DomainName
-	dkimPrivateKey string
-	dkimPublicKey  string
-	createdAt      time.Time
-	tracking       tracking.Policy
+	id               int32
+	domain           values.DomainName
+	dkimKeys         []DKIMKey
+	createdAt        time.Time
+	tracking         tracking.Policy
+	returnPathDomain values.DomainName
+	feedback         feedback.Identity
+	smime            smime.Credentials
+	linkDecoration   linkparams.Decoration
+	requireTLS       bool
+	ipPool           string
 }
 
-// New creates a new SenderDomain with a freshly generated DKIM key pair. The numeric id, createdAt
-// and Tracking Policy are populated by the repository on Create, so the starting Policy is stated
-// only by the column default. The name needs no check: only Parse can have produced it.
+// New creates a new SenderDomain with a freshly generated DKIM key pair under InitialSelector,
+// active from the start: a Domain that could not sign would be no use to anyone. The numeric id,
+// createdAt and Tracking Policy are populated by the repository on Create, so the starting Policy
+// is stated only by the column default. The name needs no check: only Parse can have produced it.
 func New(domain values.DomainName) (*Domain, error) {
-	keys, err := dkim.GenerateDKIMKeysPair()
+	key, err := NewDKIMKey(InitialSelector, dkim.AlgorithmRSA)
 	if err != nil {
 		return nil, err
 	}
+	return NewWithDKIMKey(domain, key), nil
+}
+
+// NewWithDKIMKey creates a new SenderDomain whose first key is key, active from the start as in
+// New: a key of another size than New's, or one imported with the selector it is already
+// published under.
+func NewWithDKIMKey(domain values.DomainName, key DKIMKey) *Domain {
+	// Stamped here only so the key reads as active before it is stored; the repository replaces
+	// the timestamp with its own on Create.
+	key.activatedAt = time.Now()
 	return &Domain{
-		domain:         domain,
-		dkimPrivateKey: keys.PrivateKey,
-		dkimPublicKey:  keys.PublicKey,
-	}, nil
+		domain:   domain,
+		dkimKeys: []DKIMKey{key},
+	}
 }
 
 // LoadParams contains all fields needed to rehydrate a Domain from storage.
 type LoadParams struct {
-	ID             int32
-	Domain         values.DomainName
-	DkimPrivateKey string
-	DkimPublicKey  string
-	CreatedAt      time.Time
-	Tracking       tracking.Policy
+	ID               int32
+	Domain           values.DomainName
+	DKIMKeys         []DKIMKey
+	CreatedAt        time.Time
+	Tracking         tracking.Policy
+	ReturnPathDomain values.DomainName
+	Feedback         feedback.Identity
+	SMIME            smime.Credentials
+	LinkDecoration   linkparams.Decoration
+	RequireTLS       bool
+	IPPool           string
 }
 
 // Load rehydrates a Domain from stored data (used by repository implementations).
 func Load(p LoadParams) *Domain {
 	return &Domain{
-		id:             p.ID,
-		domain:         p.Domain,
-		dkimPrivateKey: p.DkimPrivateKey,
-		dkimPublicKey:  p.DkimPublicKey,
-		createdAt:      p.CreatedAt,
-		tracking:       p.Tracking,
+		id:               p.ID,
+		domain:           p.Domain,
+		dkimKeys:         p.DKIMKeys,
+		createdAt:        p.CreatedAt,
+		tracking:         p.Tracking,
+		returnPathDomain: p.ReturnPathDomain,
+		feedback:         p.Feedback,
+		smime:            p.SMIME,
+		linkDecoration:   p.LinkDecoration,
+		requireTLS:       p.RequireTLS,
+		ipPool:           p.IPPool,
+	}
+}
+
+func (d *Domain) ID() int32            { return d.id }
+func (d *Domain) CreatedAt() time.Time { return d.createdAt }
+
+// DKIMKeys lists every key the Domain has had, retired ones included, oldest first.
+func (d *Domain) DKIMKeys() []DKIMKey { return d.dkimKeys }
+
+// DKIMKey finds the key published under selector.
+func (d *Domain) DKIMKey(selector string) (DKIMKey, bool) {
+	for _, k := range d.dkimKeys {
+		if k.selector == selector {
+			return k, true
+		}
 	}
+	return DKIMKey{}, false
 }
 
-func (d *Domain) ID() int32              { return d.id }
-func (d *Domain) DkimPrivateKey() string { return d.dkimPrivateKey }
-func (d *Domain) DkimPublicKey() string  { return d.dkimPublicKey }
-func (d *Domain) CreatedAt() time.Time   { return d.createdAt }
+// ActiveDKIMKey is the key mail is signed with under algorithm: the most recently activated key of
+// that algorithm not retired. Each algorithm rotates on its own, so activating an Ed25519 key
+// supersedes earlier Ed25519 keys and leaves the RSA key signing beside it.
+func (d *Domain) ActiveDKIMKey(algorithm string) (DKIMKey, bool) {
+	var active DKIMKey
+	found := false
+	for _, k := range d.dkimKeys {
+		if k.algorithm != algorithm || !k.Activated() || k.Retired() {
+			continue
+		}
+		if !found || !k.activatedAt.Before(active.activatedAt) {
+			active, found = k, true
+		}
+	}
+	return active, found
+}
+
+// ActiveDKIMKeys lists every key mail is signed with, at most one per algorithm, in the order
+// the keys were created. A Domain whose every key is pending or retired has none, and cannot sign.
+func (d *Domain) ActiveDKIMKeys() []DKIMKey {
+	var active []DKIMKey
+	for _, k := range d.dkimKeys {
+		if d.DKIMKeyState(k) == DKIMKeyActive {
+			active = append(active, k)
+		}
+	}
+	return active
+}
+
+// DKIMKeyState is where the key stands in a rotation of this Domain's keys of its algorithm.
+func (d *Domain) DKIMKeyState(k DKIMKey) DKIMKeyState {
+	switch {
+	case k.Retired():
+		return DKIMKeyRetired
+	case !k.Activated():
+		return DKIMKeyPending
+	}
+	if active, ok := d.ActiveDKIMKey(k.algorithm); ok && active.selector == k.selector {
+		return DKIMKeyActive
+	}
+	return DKIMKeySuperseded
+}
+
+// DkimPrivateKey is the active RSA key's private half, empty when the Domain has none. RSA because
+// these two predate every other algorithm, and a caller reading them means the RSA key.
+func (d *Domain) DkimPrivateKey() string {
+	k, _ := d.ActiveDKIMKey(dkim.AlgorithmRSA)
+	return k.privateKey
+}
+
+// DkimPublicKey is the active RSA key's public half, empty when the Domain has none.
+func (d *Domain) DkimPublicKey() string {
+	k, _ := d.ActiveDKIMKey(dkim.AlgorithmRSA)
+	return k.publicKey
+}
 
 // Name is the Domain's canonical domain name. It is what a Repository, and
 // later an authorization Anchor, is addressed with: both must be unable to
@@ -83,3 +178,28 @@ func (d *Domain) Domain() string { return d.domain.String() }
 // TrackingPolicy is the Domain's Tracking Policy: the ceiling every Batch and
 // Recipient of this Domain is resolved against.
 func (d *Domain) TrackingPolicy() tracking.Policy { return d.tracking }
+
+// ReturnPathDomain is the domain the Domain's bounces are addressed to — the domain of the MAIL
+// FROM of everything it sends — or the zero name when they are addressed to the Domain itself.
+func (d *Domain) ReturnPathDomain() values.DomainName { return d.returnPathDomain }
+
+// Feedback is the Domain's Feedback Identity: the Feedback-ID and List-Id every Batch it sends
+// carries, unless the Batch overrides them. The zero Identity writes neither header.
+func (d *Domain) Feedback() feedback.Identity { return d.feedback }
+
+// SMIME is the certificate and key the Domain's mail is S/MIME-signed with. The zero Credentials
+// sign nothing.
+func (d *Domain) SMIME() smime.Credentials { return d.smime }
+
+// LinkDecoration is the query parameters appended to the links of the Domain's mail, unless a
+// Batch overrides them, and the hosts they are appended on. The zero Decoration decorates nothing.
+func (d *Domain) LinkDecoration() linkparams.Decoration { return d.linkDecoration }
+
+// RequireTLS reports whether the Domain's mail is held back rather than sent without
+// authenticated TLS — STARTTLS to an MX host whose certificate verifies. False sends it as the
+// sender's TLS policy allows. A Batch may require it when its Domain does not, never the reverse.
+func (d *Domain) RequireTLS() bool { return d.requireTLS }
+
+// IPPool names the IP pool the Domain's mail is sent from, unless a Batch names its own; empty is
+// the sender's default pool.
+func (d *Domain) IPPool() string { return d.ipPool }
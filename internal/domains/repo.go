@@ -3,6 +3,9 @@ package domains
 import (
 	"context"
 
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/smime"
 	"github.com/kannon-email/kannon/internal/tracking"
 	"github.com/kannon-email/kannon/internal/values"
 )
@@ -11,8 +14,9 @@ import (
 // values.DomainName, so a query cannot be reached with a name that was never canonicalised: two
 // spellings of one mail domain would otherwise be two Domains, each with its own DKIM keypair.
 type Repository interface {
-	// Create persists a new Domain. The DKIM key pair must already be
-	// populated by New.
+	// Create persists a new Domain together with its DKIM keys, which must
+	// already be populated by New. A key that reads as activated is stored
+	// activated, stamped with the repository's own time.
 	Create(ctx context.Context, d *Domain) error
 
 	// SetTrackingPolicy replaces the Domain's Tracking Policy and returns the updated Domain. A
@@ -20,10 +24,53 @@ type Repository interface {
 	// never appears at rest. Returns ErrDomainNotFound if not present.
 	SetTrackingPolicy(ctx context.Context, domain values.DomainName, p tracking.Policy) (*Domain, error)
 
+	// SetReturnPathDomain replaces the Domain's return-path domain — the zero name clears it — and
+	// returns the updated Domain. Whether rp may serve the Domain is the caller's to decide.
+	// Returns ErrDomainNotFound if not present.
+	SetReturnPathDomain(ctx context.Context, domain values.DomainName, rp values.DomainName) (*Domain, error)
+
+	// SetFeedbackIdentity replaces the Domain's Feedback Identity — the zero Identity clears it —
+	// and returns the updated Domain. Returns ErrDomainNotFound if not present.
+	SetFeedbackIdentity(ctx context.Context, domain values.DomainName, id feedback.Identity) (*Domain, error)
+
+	// SetSMIME replaces the Domain's S/MIME signing certificate and key — the zero Credentials
+	// clear them — and returns the updated Domain. Whether they are usable is the caller's to
+	// decide. Returns ErrDomainNotFound if not present.
+	SetSMIME(ctx context.Context, domain values.DomainName, c smime.Credentials) (*Domain, error)
+
+	// SetLinkDecoration replaces the Domain's Link Decoration — the zero Decoration clears it —
+	// and returns the updated Domain. Returns ErrDomainNotFound if not present.
+	SetLinkDecoration(ctx context.Context, domain values.DomainName, d linkparams.Decoration) (*Domain, error)
+
+	// SetRequireTLS sets whether the Domain's mail requires authenticated TLS and returns the
+	// updated Domain. Returns ErrDomainNotFound if not present.
+	SetRequireTLS(ctx context.Context, domain values.DomainName, require bool) (*Domain, error)
+
+	// SetIPPool assigns the Domain's mail to an IP pool — empty assigns it to none — and returns
+	// the updated Domain. Returns ErrDomainNotFound if not present.
+	SetIPPool(ctx context.Context, domain values.DomainName, pool string) (*Domain, error)
+
 	// FindByName looks up a Domain by its domain name.
 	// Returns ErrDomainNotFound if not present.
 	FindByName(ctx context.Context, domain values.DomainName) (*Domain, error)
 
 	// List returns all SenderDomains.
 	List(ctx context.Context) ([]*Domain, error)
+
+	// AddDKIMKey stores a new pending key for the Domain and returns the updated Domain.
+	// Returns ErrDomainNotFound if not present, ErrDKIMSelectorTaken if the Domain already
+	// has a key under the selector — retired ones included, since a resolver may still cache
+	// the record a retired selector published.
+	AddDKIMKey(ctx context.Context, domain values.DomainName, k DKIMKey) (*Domain, error)
+
+	// ActivateDKIMKey stamps the key as activated now, which makes it the key the Domain
+	// signs with, and returns the updated Domain. Activating a superseded key again is how a
+	// rotation is rolled back. Returns ErrDKIMKeyNotFound if the Domain has no such key or
+	// it is retired.
+	ActivateDKIMKey(ctx context.Context, domain values.DomainName, selector string) (*Domain, error)
+
+	// RetireDKIMKey stamps the key as retired now and returns the updated Domain. Whether the
+	// key may be retired is the caller's to decide. Returns ErrDKIMKeyNotFound if the Domain
+	// has no such key or it is already retired.
+	RetireDKIMKey(ctx context.Context, domain values.DomainName, selector string) (*Domain, error)
 }
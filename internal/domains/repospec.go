@@ -6,6 +6,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/dkim"
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/smime"
 	"github.com/kannon-email/kannon/internal/tracking"
 	"github.com/kannon-email/kannon/internal/values"
 	"github.com/stretchr/testify/assert"
@@ -19,6 +23,13 @@ func RunRepoSpec(t *testing.T, repo Repository) {
 	t.Run("FindByName", func(t *testing.T) { testFindByName(t, repo) })
 	t.Run("List", func(t *testing.T) { testList(t, repo) })
 	t.Run("SetTrackingPolicy", func(t *testing.T) { testSetTrackingPolicy(t, repo) })
+	t.Run("SetReturnPathDomain", func(t *testing.T) { testSetReturnPathDomain(t, repo) })
+	t.Run("SetFeedbackIdentity", func(t *testing.T) { testSetFeedbackIdentity(t, repo) })
+	t.Run("SetSMIME", func(t *testing.T) { testSetSMIME(t, repo) })
+	t.Run("SetLinkDecoration", func(t *testing.T) { testSetLinkDecoration(t, repo) })
+	t.Run("SetRequireTLS", func(t *testing.T) { testSetRequireTLS(t, repo) })
+	t.Run("SetIPPool", func(t *testing.T) { testSetIPPool(t, repo) })
+	t.Run("DKIMKeys", func(t *testing.T) { testDKIMKeys(t, repo) })
 }
 
 // freshName mints a domain name no other test run uses. MustParse is right
@@ -51,6 +62,33 @@ func testCreate(t *testing.T, repo Repository) {
 		assert.Equal(t, name, fetched.Name())
 		assert.Equal(t, d.DkimPublicKey(), fetched.DkimPublicKey())
 		assert.Equal(t, d.DkimPrivateKey(), fetched.DkimPrivateKey())
+
+		active, ok := fetched.ActiveDKIMKey(dkim.AlgorithmRSA)
+		require.True(t, ok, "a created Domain signs from the start")
+		assert.Equal(t, InitialSelector, active.Selector())
+		assert.False(t, active.ActivatedAt().IsZero())
+		assert.Equal(t, DKIMKeyGenerated, active.Origin())
+		assert.Equal(t, dkim.DefaultRSABits, active.Bits())
+	})
+
+	// What CreateDomain was asked for survives the round trip: the origin and
+	// size of the first key are not derivable from the stored key material.
+	t.Run("WithChosenKey", func(t *testing.T) {
+		ctx := t.Context()
+		name := freshName("create-key")
+
+		k, err := NewRSADKIMKey("s1", 1024)
+		require.NoError(t, err)
+		k.origin = DKIMKeyImported
+		require.NoError(t, repo.Create(ctx, NewWithDKIMKey(name, k)))
+
+		fetched, err := repo.FindByName(ctx, name)
+		require.NoError(t, err)
+		active, ok := fetched.ActiveDKIMKey(dkim.AlgorithmRSA)
+		require.True(t, ok)
+		assert.Equal(t, "s1", active.Selector())
+		assert.Equal(t, DKIMKeyImported, active.Origin())
+		assert.Equal(t, 1024, active.Bits())
 	})
 }
 
@@ -118,6 +156,185 @@ func testSetTrackingPolicy(t *testing.T, repo Repository) {
 	})
 }
 
+func testSetReturnPathDomain(t *testing.T, repo Repository) {
+	t.Run("SetAndCleared", func(t *testing.T) {
+		ctx := t.Context()
+		name := freshName("return-path")
+		d, err := New(name)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, d))
+		assert.True(t, d.ReturnPathDomain().IsZero(), "a new Domain takes its own bounces")
+
+		rp := values.MustParse("bounces." + name.String())
+		updated, err := repo.SetReturnPathDomain(ctx, name, rp)
+		require.NoError(t, err)
+		assert.Equal(t, rp, updated.ReturnPathDomain())
+
+		fetched, err := repo.FindByName(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, rp, fetched.ReturnPathDomain())
+		_, ok := fetched.ActiveDKIMKey(dkim.AlgorithmRSA)
+		assert.True(t, ok, "the keys come back with the Domain")
+
+		cleared, err := repo.SetReturnPathDomain(ctx, name, values.DomainName{})
+		require.NoError(t, err)
+		assert.True(t, cleared.ReturnPathDomain().IsZero())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		name := freshName("return-path-missing")
+		_, err := repo.SetReturnPathDomain(t.Context(), name, values.MustParse("bounces."+name.String()))
+		assert.ErrorIs(t, err, ErrDomainNotFound)
+	})
+}
+
+func testSetFeedbackIdentity(t *testing.T, repo Repository) {
+	t.Run("SetAndCleared", func(t *testing.T) {
+		ctx := t.Context()
+		name := freshName("feedback")
+		d, err := New(name)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, d))
+		assert.True(t, d.Feedback().IsZero(), "a new Domain states no Feedback Identity")
+
+		id := feedback.Identity{SenderID: "acme", Campaign: "spring", ListID: "news." + name.String()}
+		updated, err := repo.SetFeedbackIdentity(ctx, name, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, updated.Feedback())
+
+		fetched, err := repo.FindByName(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, id, fetched.Feedback())
+
+		cleared, err := repo.SetFeedbackIdentity(ctx, name, feedback.Identity{})
+		require.NoError(t, err)
+		assert.True(t, cleared.Feedback().IsZero())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := repo.SetFeedbackIdentity(t.Context(), freshName("feedback-missing"), feedback.Identity{SenderID: "acme"})
+		assert.ErrorIs(t, err, ErrDomainNotFound)
+	})
+}
+
+func testSetSMIME(t *testing.T, repo Repository) {
+	t.Run("SetAndCleared", func(t *testing.T) {
+		ctx := t.Context()
+		name := freshName("smime")
+		d, err := New(name)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, d))
+		assert.True(t, d.SMIME().IsZero(), "a new Domain signs nothing with S/MIME")
+
+		// The repository stores what it is given; checking it is the Service's job.
+		creds := smime.Credentials{Certificate: "certificate", PrivateKey: "private key"}
+		updated, err := repo.SetSMIME(ctx, name, creds)
+		require.NoError(t, err)
+		assert.Equal(t, creds, updated.SMIME())
+
+		fetched, err := repo.FindByName(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, creds, fetched.SMIME())
+
+		cleared, err := repo.SetSMIME(ctx, name, smime.Credentials{})
+		require.NoError(t, err)
+		assert.True(t, cleared.SMIME().IsZero())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := repo.SetSMIME(t.Context(), freshName("smime-missing"), smime.Credentials{Certificate: "c", PrivateKey: "k"})
+		assert.ErrorIs(t, err, ErrDomainNotFound)
+	})
+}
+
+func testSetLinkDecoration(t *testing.T, repo Repository) {
+	t.Run("SetAndCleared", func(t *testing.T) {
+		ctx := t.Context()
+		name := freshName("links")
+		d, err := New(name)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, d))
+		assert.True(t, d.LinkDecoration().IsZero(), "a new Domain decorates no link")
+
+		dec := linkparams.Decoration{
+			Params: linkparams.Params{"utm_source": "kannon", "utm_term": "{{ segment }}"},
+			Hosts:  linkparams.Hosts{Allow: []string{name.String()}, Deny: []string{"pay." + name.String()}},
+		}
+		updated, err := repo.SetLinkDecoration(ctx, name, dec)
+		require.NoError(t, err)
+		assert.Equal(t, dec, updated.LinkDecoration())
+
+		fetched, err := repo.FindByName(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, dec, fetched.LinkDecoration())
+
+		cleared, err := repo.SetLinkDecoration(ctx, name, linkparams.Decoration{})
+		require.NoError(t, err)
+		assert.True(t, cleared.LinkDecoration().IsZero())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := repo.SetLinkDecoration(t.Context(), freshName("links-missing"), linkparams.Decoration{Params: linkparams.Params{"utm_source": "kannon"}})
+		assert.ErrorIs(t, err, ErrDomainNotFound)
+	})
+}
+
+func testSetRequireTLS(t *testing.T, repo Repository) {
+	t.Run("SetAndCleared", func(t *testing.T) {
+		ctx := t.Context()
+		name := freshName("require-tls")
+		d, err := New(name)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, d))
+		assert.False(t, d.RequireTLS(), "a new Domain requires no TLS")
+
+		updated, err := repo.SetRequireTLS(ctx, name, true)
+		require.NoError(t, err)
+		assert.True(t, updated.RequireTLS())
+
+		fetched, err := repo.FindByName(ctx, name)
+		require.NoError(t, err)
+		assert.True(t, fetched.RequireTLS())
+
+		cleared, err := repo.SetRequireTLS(ctx, name, false)
+		require.NoError(t, err)
+		assert.False(t, cleared.RequireTLS())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := repo.SetRequireTLS(t.Context(), freshName("require-tls-missing"), true)
+		assert.ErrorIs(t, err, ErrDomainNotFound)
+	})
+}
+
+func testSetIPPool(t *testing.T, repo Repository) {
+	t.Run("SetAndCleared", func(t *testing.T) {
+		ctx := t.Context()
+		name := freshName("ip-pool")
+		d, err := New(name)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, d))
+		assert.Empty(t, d.IPPool(), "a new Domain sends from the default pool")
+
+		updated, err := repo.SetIPPool(ctx, name, "transactional")
+		require.NoError(t, err)
+		assert.Equal(t, "transactional", updated.IPPool())
+
+		fetched, err := repo.FindByName(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, "transactional", fetched.IPPool())
+
+		cleared, err := repo.SetIPPool(ctx, name, "")
+		require.NoError(t, err)
+		assert.Empty(t, cleared.IPPool())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := repo.SetIPPool(t.Context(), freshName("ip-pool-missing"), "transactional")
+		assert.ErrorIs(t, err, ErrDomainNotFound)
+	})
+}
+
 func testList(t *testing.T, repo Repository) {
 	t.Run("ContainsCreatedDomains", func(t *testing.T) {
 		ctx := t.Context()
@@ -143,3 +360,115 @@ func testList(t *testing.T, repo Repository) {
 		}
 	})
 }
+
+// testDKIMKeys walks a rotation through the repository: what makes a key the signing one is only
+// observable once timestamps the repository stamps are read back.
+func testDKIMKeys(t *testing.T, repo Repository) {
+	create := func(t *testing.T, prefix string) values.DomainName {
+		t.Helper()
+		name := freshName(prefix)
+		d, err := New(name)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(t.Context(), d))
+		return name
+	}
+	newKey := func(t *testing.T, selector string) DKIMKey {
+		t.Helper()
+		k, err := NewDKIMKey(selector, dkim.AlgorithmRSA)
+		require.NoError(t, err)
+		return k
+	}
+
+	t.Run("AddedKeyIsPending", func(t *testing.T) {
+		ctx := t.Context()
+		name := create(t, "dkim-add")
+		k := newKey(t, "next")
+
+		d, err := repo.AddDKIMKey(ctx, name, k)
+		require.NoError(t, err)
+
+		stored, ok := d.DKIMKey("next")
+		require.True(t, ok)
+		assert.Equal(t, k.PublicKey(), stored.PublicKey())
+		assert.Equal(t, k.PrivateKey(), stored.PrivateKey())
+		assert.Equal(t, DKIMKeyPending, d.DKIMKeyState(stored))
+		assert.False(t, stored.CreatedAt().IsZero())
+
+		active, ok := d.ActiveDKIMKey(dkim.AlgorithmRSA)
+		require.True(t, ok)
+		assert.Equal(t, InitialSelector, active.Selector(), "a pending key must not take over signing")
+	})
+
+	t.Run("SelectorTaken", func(t *testing.T) {
+		_, err := repo.AddDKIMKey(t.Context(), create(t, "dkim-taken"), newKey(t, InitialSelector))
+		assert.ErrorIs(t, err, ErrDKIMSelectorTaken)
+	})
+
+	t.Run("AddToMissingDomain", func(t *testing.T) {
+		_, err := repo.AddDKIMKey(t.Context(), freshName("dkim-missing"), newKey(t, "next"))
+		assert.ErrorIs(t, err, ErrDomainNotFound)
+	})
+
+	t.Run("ActivateSupersedesAndRetireKeepsTheKey", func(t *testing.T) {
+		ctx := t.Context()
+		name := create(t, "dkim-rotate")
+		_, err := repo.AddDKIMKey(ctx, name, newKey(t, "next"))
+		require.NoError(t, err)
+
+		d, err := repo.ActivateDKIMKey(ctx, name, "next")
+		require.NoError(t, err)
+		active, ok := d.ActiveDKIMKey(dkim.AlgorithmRSA)
+		require.True(t, ok)
+		assert.Equal(t, "next", active.Selector())
+		old, _ := d.DKIMKey(InitialSelector)
+		assert.Equal(t, DKIMKeySuperseded, d.DKIMKeyState(old))
+
+		d, err = repo.RetireDKIMKey(ctx, name, InitialSelector)
+		require.NoError(t, err)
+		old, ok = d.DKIMKey(InitialSelector)
+		require.True(t, ok, "a retired key stays listed")
+		assert.Equal(t, DKIMKeyRetired, d.DKIMKeyState(old))
+
+		fetched, err := repo.FindByName(ctx, name)
+		require.NoError(t, err)
+		assert.Len(t, fetched.DKIMKeys(), 2)
+		assert.Equal(t, active.PublicKey(), fetched.DkimPublicKey())
+	})
+
+	t.Run("AlgorithmsRotateIndependently", func(t *testing.T) {
+		ctx := t.Context()
+		name := create(t, "dkim-dual")
+		ed, err := NewDKIMKey("ed", dkim.AlgorithmEd25519)
+		require.NoError(t, err)
+		_, err = repo.AddDKIMKey(ctx, name, ed)
+		require.NoError(t, err)
+
+		d, err := repo.ActivateDKIMKey(ctx, name, "ed")
+		require.NoError(t, err)
+
+		active := d.ActiveDKIMKeys()
+		require.Len(t, active, 2, "an Ed25519 key signs beside the RSA key, not instead of it")
+		assert.Equal(t, dkim.AlgorithmRSA, active[0].Algorithm())
+		assert.Equal(t, dkim.AlgorithmEd25519, active[1].Algorithm())
+		assert.Equal(t, ed.PublicKey(), active[1].PublicKey())
+	})
+
+	t.Run("RetiredKeyCannotBeActivated", func(t *testing.T) {
+		ctx := t.Context()
+		name := create(t, "dkim-retired")
+		_, err := repo.AddDKIMKey(ctx, name, newKey(t, "next"))
+		require.NoError(t, err)
+		_, err = repo.RetireDKIMKey(ctx, name, "next")
+		require.NoError(t, err)
+
+		_, err = repo.ActivateDKIMKey(ctx, name, "next")
+		assert.ErrorIs(t, err, ErrDKIMKeyNotFound)
+		_, err = repo.RetireDKIMKey(ctx, name, "next")
+		assert.ErrorIs(t, err, ErrDKIMKeyNotFound)
+	})
+
+	t.Run("UnknownSelector", func(t *testing.T) {
+		_, err := repo.ActivateDKIMKey(t.Context(), create(t, "dkim-unknown"), "nope")
+		assert.ErrorIs(t, err, ErrDKIMKeyNotFound)
+	})
+}
@@ -0,0 +1,61 @@
+package domains
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/kannon-email/kannon/internal/values"
+)
+
+// ErrReturnPathNotSubdomain is returned for a return-path domain that is not a subdomain of the
+// Domain it would serve.
+var ErrReturnPathNotSubdomain = errors.New("return-path domain must be a subdomain of the domain")
+
+// validateReturnPath reports whether rp can carry domain's bounces. Only a strict subdomain can:
+// SPF authenticates the MAIL FROM domain, and DMARC counts an SPF pass only when that domain
+// aligns with the From header's, which a subdomain always does and an unrelated domain never
+// does. The zero name is valid, and means the Domain itself.
+func validateReturnPath(domain, rp values.DomainName) error {
+	if rp.IsZero() {
+		return nil
+	}
+	if !strings.HasSuffix(rp.String(), "."+domain.String()) {
+		return ErrReturnPathNotSubdomain
+	}
+	return nil
+}
+
+// DNSRecord is a record an operator publishes for a Domain to work as configured.
+type DNSRecord struct {
+	Type  string
+	Name  string
+	Value string
+}
+
+// ReturnPathDNS names the hosts a return-path domain's records point at. Neither is the Domain's
+// to know — both are properties of the installation — so the caller supplies them, and a record
+// whose host it does not know is not reported rather than reported wrong.
+type ReturnPathDNS struct {
+	// MXHost is the host Kannon's SMTP server answers on, where bounces must arrive.
+	MXHost string
+	// SenderHost is the host mail leaves from, which the SPF record authorises.
+	SenderHost string
+}
+
+// ReturnPathRecords lists the records the Domain's return-path domain needs: an MX for bounces
+// to reach Kannon, and an SPF record for the MAIL FROM to pass. A Domain with no return-path
+// domain needs none beyond those of the Domain itself, and gets none.
+func (d *Domain) ReturnPathRecords(dns ReturnPathDNS) []DNSRecord {
+	if d.returnPathDomain.IsZero() {
+		return nil
+	}
+	name := d.returnPathDomain.String()
+	var records []DNSRecord
+	if dns.MXHost != "" {
+		records = append(records, DNSRecord{Type: "MX", Name: name, Value: "10 " + dns.MXHost})
+	}
+	if dns.SenderHost != "" {
+		records = append(records, DNSRecord{Type: "TXT", Name: name, Value: "v=spf1 a:" + dns.SenderHost + " -all"})
+	}
+	return records
+}
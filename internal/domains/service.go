@@ -2,8 +2,16 @@ package domains
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
 
 	"github.com/kannon-email/kannon/internal/authz"
+	"github.com/kannon-email/kannon/internal/dkim"
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/smime"
 	"github.com/kannon-email/kannon/internal/tracking"
 	"github.com/kannon-email/kannon/internal/values"
 )
@@ -12,22 +20,81 @@ import (
 // the one place each is authorized — a property of the operation rather than of one transport. The
 // Mailer API stays out: its Domain read is part of authentication, and sender holds no read.
 type Service struct {
-	repo Repository
+	repo      Repository
+	lookupTXT TXTLookup
+	now       func() time.Time
 }
 
-func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+// TXTLookup resolves the TXT records at a DNS name, the way net.Resolver.LookupTXT does.
+type TXTLookup func(ctx context.Context, name string) ([]string, error)
+
+func NewService(repo Repository, opts ...ServiceOption) *Service {
+	s := &Service{repo: repo, lookupTXT: net.DefaultResolver.LookupTXT, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ServiceOption configures optional dependencies for Service.
+type ServiceOption func(*Service)
+
+// WithTXTLookup replaces the resolver ActivateDKIMKey checks a published record against.
+func WithTXTLookup(lookup TXTLookup) ServiceOption {
+	return func(s *Service) {
+		s.lookupTXT = lookup
+	}
+}
+
+// InitialDKIMKey says how CreateDomain comes by a Domain's first DKIM key. The zero value
+// generates a dkim.DefaultRSABits RSA key under InitialSelector, which is what every Domain got
+// before there was a choice.
+type InitialDKIMKey struct {
+	// RSABits generates an RSA key of this size instead: 1024, 2048 or 4096.
+	RSABits int
+	// PrivateKeyPEM imports an existing key instead of generating one, so a Domain migrated from
+	// another provider keeps verifying against the records it already publishes.
+	PrivateKeyPEM string
+	// Selector is the selector the first key is published under. It is required with
+	// PrivateKeyPEM, since it can only be the one the key is already published under, and
+	// defaults to InitialSelector otherwise.
+	Selector string
+}
+
+// ErrInitialDKIMKeyConflict is returned for an InitialDKIMKey that both sizes a generated key and
+// imports one.
+var ErrInitialDKIMKeyConflict = errors.New("an imported dkim key has no size to choose")
+
+func (k InitialDKIMKey) build() (DKIMKey, error) {
+	if k.PrivateKeyPEM != "" {
+		if k.RSABits != 0 {
+			return DKIMKey{}, ErrInitialDKIMKeyConflict
+		}
+		return ImportDKIMKey(k.Selector, k.PrivateKeyPEM)
+	}
+	selector := k.Selector
+	if selector == "" {
+		selector = InitialSelector
+	}
+	bits := k.RSABits
+	if bits == 0 {
+		bits = dkim.DefaultRSABits
+	}
+	return NewRSADKIMKey(selector, bits)
 }
 
-// CreateDomain registers a new SenderDomain, generating its DKIM key pair. Create on the Domains
-// collection — the shorter path, not a separate tier. This is the operation ADR 0008 opens with:
-// unauthenticated, anyone reaching the listener can mint a Domain, a key and a valid credential.
-func (s *Service) CreateDomain(ctx context.Context, name values.DomainName) (*Domain, error) {
+// CreateDomain registers a new SenderDomain with the first DKIM key initial describes. Create on
+// the Domains collection — the shorter path, not a separate tier. This is the operation ADR 0008
+// opens with: unauthenticated, anyone reaching the listener can mint a Domain, a key and a valid
+// credential. An imported key is trusted to be published already, and is active at once like a
+// generated one; nothing resolves its record first.
+func (s *Service) CreateDomain(ctx context.Context, name values.DomainName, initial InitialDKIMKey) (*Domain, error) {
 	return authz.Guard(ctx, authz.Create, authz.Domains(), func() (*Domain, error) {
-		d, err := New(name)
+		key, err := initial.build()
 		if err != nil {
 			return nil, err
 		}
+		d := NewWithDKIMKey(name, key)
 		if err := s.repo.Create(ctx, d); err != nil {
 			return nil, err
 		}
@@ -45,7 +112,7 @@ func (s *Service) GetDomains(ctx context.Context) ([]*Domain, error) {
 }
 
 // GetDomain reads one SenderDomain, including its Tracking Policy and public
-// DKIM key.
+// DKIM keys.
 func (s *Service) GetDomain(ctx context.Context, name values.DomainName) (*Domain, error) {
 	return authz.Guard(ctx, authz.Read, authz.Domain(name), func() (*Domain, error) {
 		return s.repo.FindByName(ctx, name)
@@ -60,3 +127,179 @@ func (s *Service) SetTrackingPolicy(ctx context.Context, name values.DomainName,
 		return s.repo.SetTrackingPolicy(ctx, name, p)
 	})
 }
+
+// SetReturnPathDomain has the Domain's bounces addressed to rp, a subdomain of it, rather than to
+// the Domain itself; the zero name goes back to the Domain. Mail built from then on carries rp in
+// its MAIL FROM, so its MX must already point at Kannon: ReturnPathRecords lists what to publish.
+// Bounces for mail sent before still arrive at the Domain and are still understood. Update on the
+// Domain.
+func (s *Service) SetReturnPathDomain(ctx context.Context, name, rp values.DomainName) (*Domain, error) {
+	return authz.Guard(ctx, authz.Update, authz.Domain(name), func() (*Domain, error) {
+		if err := validateReturnPath(name, rp); err != nil {
+			return nil, err
+		}
+		return s.repo.SetReturnPathDomain(ctx, name, rp)
+	})
+}
+
+// SetFeedbackIdentity replaces the Feedback-ID and List-Id the Domain's mail carries; the zero
+// Identity stops both. A Batch may still state its own, field by field, over whatever is set here.
+// Mail built from then on carries the new headers. Update on the Domain.
+func (s *Service) SetFeedbackIdentity(ctx context.Context, name values.DomainName, id feedback.Identity) (*Domain, error) {
+	return authz.Guard(ctx, authz.Update, authz.Domain(name), func() (*Domain, error) {
+		if err := id.Validate(); err != nil {
+			return nil, err
+		}
+		return s.repo.SetFeedbackIdentity(ctx, name, id)
+	})
+}
+
+// SetLinkDecoration replaces the query parameters appended to the Domain's links and the hosts
+// they are appended on; the zero Decoration stops decorating. A Batch may still override the
+// parameters, one by one, but not the hosts. Mail built from then on is decorated with it. Update
+// on the Domain.
+func (s *Service) SetLinkDecoration(ctx context.Context, name values.DomainName, d linkparams.Decoration) (*Domain, error) {
+	return authz.Guard(ctx, authz.Update, authz.Domain(name), func() (*Domain, error) {
+		if err := d.Validate(); err != nil {
+			return nil, err
+		}
+		return s.repo.SetLinkDecoration(ctx, name, d)
+	})
+}
+
+// SetRequireTLS has the Domain's mail held back rather than sent to an MX host that cannot
+// offer authenticated TLS, or lets it go as the sender's TLS policy allows again. A Batch may
+// still require it for itself. Update on the Domain.
+func (s *Service) SetRequireTLS(ctx context.Context, name values.DomainName, require bool) (*Domain, error) {
+	return authz.Guard(ctx, authz.Update, authz.Domain(name), func() (*Domain, error) {
+		return s.repo.SetRequireTLS(ctx, name, require)
+	})
+}
+
+// SetIPPool has the Domain's mail sent from an IP pool of the sender's, or from its default pool
+// when pool is empty. A Batch may still name a pool of its own. Whether the sender has a pool of
+// that name is its configuration, out of reach here; mail assigned to one it lacks goes out from
+// its default pool. Update on the Domain.
+func (s *Service) SetIPPool(ctx context.Context, name values.DomainName, pool string) (*Domain, error) {
+	return authz.Guard(ctx, authz.Update, authz.Domain(name), func() (*Domain, error) {
+		if err := values.ValidateIPPool(pool); err != nil {
+			return nil, err
+		}
+		return s.repo.SetIPPool(ctx, name, pool)
+	})
+}
+
+// SetSMIMECertificate has the Domain's mail S/MIME-signed with the certificate chain and private
+// key given, both PEM, leaf first; both empty stops signing. The pair is checked before it is
+// stored — it must be valid now, fit for signing mail, and belong together — and an unusable one
+// is smime.ErrInvalidCertificate. Mail built from then on is signed with it. Update on the Domain,
+// like every change to its signing.
+func (s *Service) SetSMIMECertificate(ctx context.Context, name values.DomainName, certPEM, keyPEM string) (*Domain, error) {
+	return authz.Guard(ctx, authz.Update, authz.Domain(name), func() (*Domain, error) {
+		var creds smime.Credentials
+		if certPEM != "" || keyPEM != "" {
+			var err error
+			if creds, err = smime.ImportCredentials(certPEM, keyPEM, time.Now()); err != nil {
+				return nil, err
+			}
+		}
+		return s.repo.SetSMIME(ctx, name, creds)
+	})
+}
+
+// RotateDKIMKey starts a rotation: it generates a pending key of algorithm — RSA when empty —
+// under selector, or under a time-stamped one when selector is empty, and returns it so its record
+// can be published. Nothing is signed with it until ActivateDKIMKey. Adding a first Ed25519 key is
+// a rotation too, of a kind that supersedes nothing. Update on the Domain, like every change to
+// its signing.
+func (s *Service) RotateDKIMKey(ctx context.Context, name values.DomainName, selector, algorithm string) (DKIMKey, error) {
+	return authz.Guard(ctx, authz.Update, authz.Domain(name), func() (DKIMKey, error) {
+		if algorithm == "" {
+			algorithm = dkim.AlgorithmRSA
+		}
+		if selector == "" {
+			selector = defaultSelector(algorithm, s.now())
+		}
+		k, err := NewDKIMKey(selector, algorithm)
+		if err != nil {
+			return DKIMKey{}, err
+		}
+		d, err := s.repo.AddDKIMKey(ctx, name, k)
+		if err != nil {
+			return DKIMKey{}, err
+		}
+		stored, _ := d.DKIMKey(selector)
+		return stored, nil
+	})
+}
+
+// ActivateDKIMKey makes the key the one the Domain signs with under its algorithm; the key of the
+// same algorithm it replaces stays published, superseded, until it is retired. With verifyDNS the
+// key's record must already be resolvable: activating before the record has propagated fails
+// every signature made meanwhile.
+func (s *Service) ActivateDKIMKey(ctx context.Context, name values.DomainName, selector string, verifyDNS bool) (*Domain, error) {
+	return authz.Guard(ctx, authz.Update, authz.Domain(name), func() (*Domain, error) {
+		d, err := s.repo.FindByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		k, ok := d.DKIMKey(selector)
+		if !ok {
+			return nil, ErrDKIMKeyNotFound
+		}
+		if k.Retired() {
+			return nil, ErrDKIMKeyRetired
+		}
+		if verifyDNS {
+			if err := s.verifyRecord(ctx, d, k); err != nil {
+				return nil, err
+			}
+		}
+		return s.repo.ActivateDKIMKey(ctx, name, selector)
+	})
+}
+
+// RetireDKIMKey ends a rotation by marking the key withdrawn from DNS. An active key can be
+// retired only while a key of another algorithm goes on signing — which is how a Domain stops
+// signing with Ed25519 — never the last one: the Domain would be left with nothing to sign with.
+func (s *Service) RetireDKIMKey(ctx context.Context, name values.DomainName, selector string) (*Domain, error) {
+	return authz.Guard(ctx, authz.Update, authz.Domain(name), func() (*Domain, error) {
+		d, err := s.repo.FindByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		k, ok := d.DKIMKey(selector)
+		if !ok {
+			return nil, ErrDKIMKeyNotFound
+		}
+		if k.Retired() {
+			return nil, ErrDKIMKeyRetired
+		}
+		if d.DKIMKeyState(k) == DKIMKeyActive && len(d.ActiveDKIMKeys()) == 1 {
+			return nil, ErrDKIMKeyActive
+		}
+		return s.repo.RetireDKIMKey(ctx, name, selector)
+	})
+}
+
+// defaultSelector names a rotation's key after the moment it was made. The algorithm is named too
+// unless it is RSA, so that an RSA and an Ed25519 key added together cannot collide.
+func defaultSelector(algorithm string, now time.Time) string {
+	stamp := now.UTC().Format("20060102-150405")
+	if algorithm == dkim.AlgorithmRSA {
+		return InitialSelector + "-" + stamp
+	}
+	return InitialSelector + "-" + algorithm + "-" + stamp
+}
+
+func (s *Service) verifyRecord(ctx context.Context, d *Domain, k DKIMKey) error {
+	recordName := k.RecordName(d.Domain())
+	records, err := s.lookupTXT(ctx, recordName)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrDKIMRecordNotVerified, recordName, err)
+	}
+	if !dkim.RecordPublishes(records, k.PublicKey()) {
+		return fmt.Errorf("%w: %s does not carry the key", ErrDKIMRecordNotVerified, recordName)
+	}
+	return nil
+}
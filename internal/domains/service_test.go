@@ -2,11 +2,21 @@ package domains_test
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"testing"
 	"time"
 
 	"github.com/kannon-email/kannon/internal/authz"
+	"github.com/kannon-email/kannon/internal/dkim"
 	"github.com/kannon-email/kannon/internal/domains"
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/smime"
+	"github.com/kannon-email/kannon/internal/tests"
 	"github.com/kannon-email/kannon/internal/tracking"
 	"github.com/kannon-email/kannon/internal/values"
 	"github.com/stretchr/testify/assert"
@@ -45,7 +55,7 @@ func TestServiceAuthorization(t *testing.T) {
 		{
 			name: "CreateDomain",
 			call: func(ctx context.Context, s *domains.Service) error {
-				_, err := s.CreateDomain(ctx, values.MustParse("fresh.example.com"))
+				_, err := s.CreateDomain(ctx, values.MustParse("fresh.example.com"), domains.InitialDKIMKey{})
 				return err
 			},
 			allow: []authz.Principal{rootAdmin},
@@ -78,6 +88,87 @@ func TestServiceAuthorization(t *testing.T) {
 			allow: []authz.Principal{rootAdmin, everyDomainAdmin, homeDomainAdmin},
 			deny:  []authz.Principal{otherDomainAdmin, senderOnly, noGrants},
 		},
+		{
+			name: "SetReturnPathDomain",
+			call: func(ctx context.Context, s *domains.Service) error {
+				_, err := s.SetReturnPathDomain(ctx, homeDomain, values.MustParse("bounces.example.com"))
+				return err
+			},
+			allow: []authz.Principal{rootAdmin, everyDomainAdmin, homeDomainAdmin},
+			deny:  []authz.Principal{otherDomainAdmin, senderOnly, noGrants},
+		},
+		{
+			name: "SetFeedbackIdentity",
+			call: func(ctx context.Context, s *domains.Service) error {
+				_, err := s.SetFeedbackIdentity(ctx, homeDomain, feedback.Identity{SenderID: "acme"})
+				return err
+			},
+			allow: []authz.Principal{rootAdmin, everyDomainAdmin, homeDomainAdmin},
+			deny:  []authz.Principal{otherDomainAdmin, senderOnly, noGrants},
+		},
+		{
+			name: "SetSMIMECertificate",
+			call: func(ctx context.Context, s *domains.Service) error {
+				_, err := s.SetSMIMECertificate(ctx, homeDomain, "", "")
+				return err
+			},
+			allow: []authz.Principal{rootAdmin, everyDomainAdmin, homeDomainAdmin},
+			deny:  []authz.Principal{otherDomainAdmin, senderOnly, noGrants},
+		},
+		{
+			name: "SetLinkDecoration",
+			call: func(ctx context.Context, s *domains.Service) error {
+				_, err := s.SetLinkDecoration(ctx, homeDomain, linkparams.Decoration{Params: linkparams.Params{"utm_source": "kannon"}})
+				return err
+			},
+			allow: []authz.Principal{rootAdmin, everyDomainAdmin, homeDomainAdmin},
+			deny:  []authz.Principal{otherDomainAdmin, senderOnly, noGrants},
+		},
+		{
+			name: "SetRequireTLS",
+			call: func(ctx context.Context, s *domains.Service) error {
+				_, err := s.SetRequireTLS(ctx, homeDomain, true)
+				return err
+			},
+			allow: []authz.Principal{rootAdmin, everyDomainAdmin, homeDomainAdmin},
+			deny:  []authz.Principal{otherDomainAdmin, senderOnly, noGrants},
+		},
+		{
+			name: "SetIPPool",
+			call: func(ctx context.Context, s *domains.Service) error {
+				_, err := s.SetIPPool(ctx, homeDomain, "transactional")
+				return err
+			},
+			allow: []authz.Principal{rootAdmin, everyDomainAdmin, homeDomainAdmin},
+			deny:  []authz.Principal{otherDomainAdmin, senderOnly, noGrants},
+		},
+		{
+			name: "RotateDKIMKey",
+			call: func(ctx context.Context, s *domains.Service) error {
+				_, err := s.RotateDKIMKey(ctx, homeDomain, "", "")
+				return err
+			},
+			allow: []authz.Principal{rootAdmin, everyDomainAdmin, homeDomainAdmin},
+			deny:  []authz.Principal{otherDomainAdmin, senderOnly, noGrants},
+		},
+		{
+			name: "ActivateDKIMKey",
+			call: func(ctx context.Context, s *domains.Service) error {
+				_, err := s.ActivateDKIMKey(ctx, homeDomain, pendingSelector, false)
+				return err
+			},
+			allow: []authz.Principal{rootAdmin, everyDomainAdmin, homeDomainAdmin},
+			deny:  []authz.Principal{otherDomainAdmin, senderOnly, noGrants},
+		},
+		{
+			name: "RetireDKIMKey",
+			call: func(ctx context.Context, s *domains.Service) error {
+				_, err := s.RetireDKIMKey(ctx, homeDomain, pendingSelector)
+				return err
+			},
+			allow: []authz.Principal{rootAdmin, everyDomainAdmin, homeDomainAdmin},
+			deny:  []authz.Principal{otherDomainAdmin, senderOnly, noGrants},
+		},
 	}
 
 	for _, op := range ops {
@@ -141,10 +232,13 @@ func TestServiceOperationsProduceWhatTheyUsedTo(t *testing.T) {
 	repo := seededRepo()
 	service := domains.NewService(repo)
 
-	created, err := service.CreateDomain(ctx, values.MustParse("fresh.example.com"))
+	created, err := service.CreateDomain(ctx, values.MustParse("fresh.example.com"), domains.InitialDKIMKey{})
 	require.NoError(t, err)
 	assert.Equal(t, "fresh.example.com", created.Domain())
 	assert.NotEmpty(t, created.DkimPublicKey(), "a new Domain carries a freshly generated key pair")
+	first, _ := created.DKIMKey(domains.InitialSelector)
+	assert.Equal(t, domains.DKIMKeyGenerated, first.Origin())
+	assert.Equal(t, dkim.DefaultRSABits, first.Bits())
 
 	all, err := service.GetDomains(ctx)
 	require.NoError(t, err)
@@ -159,6 +253,274 @@ func TestServiceOperationsProduceWhatTheyUsedTo(t *testing.T) {
 	assert.Equal(t, tracking.ModeAnonymous, updated.TrackingPolicy().Opens)
 }
 
+// A Domain migrating from another provider brings the key its records already publish, under the
+// selector they publish it at; one starting afresh may pick its RSA key's size. Either way the
+// Domain signs with that key from the start, and what was chosen is kept on it.
+func TestCreateDomainInitialDKIMKey(t *testing.T) {
+	ctx := authz.NewContext(context.Background(), rootAdmin)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+	imported := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+	spki, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	t.Run("Imported", func(t *testing.T) {
+		service := domains.NewService(seededRepo())
+		d, err := service.CreateDomain(ctx, values.MustParse("migrated.example.com"), domains.InitialDKIMKey{
+			PrivateKeyPEM: imported,
+			Selector:      "s1",
+		})
+		require.NoError(t, err)
+		k, ok := d.DKIMKey("s1")
+		require.True(t, ok)
+		assert.Equal(t, domains.DKIMKeyActive, d.DKIMKeyState(k))
+		assert.Equal(t, domains.DKIMKeyImported, k.Origin())
+		assert.Equal(t, 2048, k.Bits())
+		assert.Equal(t, base64.StdEncoding.EncodeToString(spki), d.DkimPublicKey(),
+			"the record the previous provider published keeps verifying")
+	})
+
+	t.Run("Sized", func(t *testing.T) {
+		service := domains.NewService(seededRepo())
+		d, err := service.CreateDomain(ctx, values.MustParse("small.example.com"), domains.InitialDKIMKey{RSABits: 1024})
+		require.NoError(t, err)
+		k, ok := d.DKIMKey(domains.InitialSelector)
+		require.True(t, ok)
+		assert.Equal(t, domains.DKIMKeyGenerated, k.Origin())
+		assert.Equal(t, 1024, k.Bits())
+	})
+
+	refused := map[string]struct {
+		initial domains.InitialDKIMKey
+		want    error
+	}{
+		"unsupported size":        {domains.InitialDKIMKey{RSABits: 3072}, dkim.ErrUnsupportedKeySize},
+		"malformed key":           {domains.InitialDKIMKey{PrivateKeyPEM: "not a key", Selector: "s1"}, dkim.ErrMalformedKey},
+		"import without selector": {domains.InitialDKIMKey{PrivateKeyPEM: imported}, dkim.ErrInvalidSelector},
+		"import with a size":      {domains.InitialDKIMKey{PrivateKeyPEM: imported, Selector: "s1", RSABits: 2048}, domains.ErrInitialDKIMKeyConflict},
+	}
+	for name, tc := range refused {
+		t.Run(name, func(t *testing.T) {
+			repo := seededRepo()
+			service := domains.NewService(repo)
+			_, err := service.CreateDomain(ctx, values.MustParse("refused.example.com"), tc.initial)
+			assert.ErrorIs(t, err, tc.want)
+			assert.NotContains(t, repo.byName, values.MustParse("refused.example.com"), "nothing is stored")
+		})
+	}
+}
+
+// A return-path domain is a subdomain of the Domain or nothing: anything else
+// would fail DMARC's SPF alignment on every message, so it is refused before it
+// reaches the repository. The zero name goes back to the Domain itself.
+func TestSetReturnPathDomain(t *testing.T) {
+	ctx := authz.NewContext(context.Background(), rootAdmin)
+	repo := seededRepo()
+	service := domains.NewService(repo)
+
+	for _, rp := range []string{"example.com", "bounces.other.com", "notexample.com", "bounces.example.com.evil.com"} {
+		_, err := service.SetReturnPathDomain(ctx, homeDomain, values.MustParse(rp))
+		assert.ErrorIs(t, err, domains.ErrReturnPathNotSubdomain, rp)
+	}
+
+	d, err := service.SetReturnPathDomain(ctx, homeDomain, values.MustParse("bounces.example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, values.MustParse("bounces.example.com"), d.ReturnPathDomain())
+	assert.Equal(t, []domains.DNSRecord{
+		{Type: "MX", Name: "bounces.example.com", Value: "10 mx.kannon.test"},
+		{Type: "TXT", Name: "bounces.example.com", Value: "v=spf1 a:out.kannon.test -all"},
+	}, d.ReturnPathRecords(domains.ReturnPathDNS{MXHost: "mx.kannon.test", SenderHost: "out.kannon.test"}))
+	assert.Len(t, d.ReturnPathRecords(domains.ReturnPathDNS{SenderHost: "out.kannon.test"}), 1,
+		"a record whose host is unknown is left out, not reported wrong")
+
+	_, err = service.SetReturnPathDomain(ctx, otherDomain, values.MustParse("bounces.other.com"))
+	assert.ErrorIs(t, err, domains.ErrDomainNotFound)
+
+	d, err = service.SetReturnPathDomain(ctx, homeDomain, values.DomainName{})
+	require.NoError(t, err)
+	assert.True(t, d.ReturnPathDomain().IsZero())
+	assert.Empty(t, d.ReturnPathRecords(domains.ReturnPathDNS{MXHost: "mx.kannon.test", SenderHost: "out.kannon.test"}))
+}
+
+func TestSetFeedbackIdentity(t *testing.T) {
+	ctx := authz.NewContext(context.Background(), rootAdmin)
+	repo := seededRepo()
+	service := domains.NewService(repo)
+
+	_, err := service.SetFeedbackIdentity(ctx, homeDomain, feedback.Identity{SenderID: "not:allowed"})
+	assert.ErrorIs(t, err, feedback.ErrInvalidIdentity)
+
+	id := feedback.Identity{SenderID: "acme", Campaign: "spring", ListID: "news.example.com"}
+	d, err := service.SetFeedbackIdentity(ctx, homeDomain, id)
+	require.NoError(t, err)
+	assert.Equal(t, id, d.Feedback())
+
+	_, err = service.SetFeedbackIdentity(ctx, otherDomain, id)
+	assert.ErrorIs(t, err, domains.ErrDomainNotFound)
+}
+
+func TestSetLinkDecoration(t *testing.T) {
+	ctx := authz.NewContext(context.Background(), rootAdmin)
+	repo := seededRepo()
+	service := domains.NewService(repo)
+
+	_, err := service.SetLinkDecoration(ctx, homeDomain, linkparams.Decoration{Params: linkparams.Params{"utm source": "kannon"}})
+	assert.ErrorIs(t, err, linkparams.ErrInvalid)
+
+	dec := linkparams.Decoration{
+		Params: linkparams.Params{"utm_source": "kannon", "utm_medium": "email"},
+		Hosts:  linkparams.Hosts{Deny: []string{"pay.example.com"}},
+	}
+	d, err := service.SetLinkDecoration(ctx, homeDomain, dec)
+	require.NoError(t, err)
+	assert.Equal(t, dec, d.LinkDecoration())
+
+	_, err = service.SetLinkDecoration(ctx, otherDomain, dec)
+	assert.ErrorIs(t, err, domains.ErrDomainNotFound)
+}
+
+func TestSetRequireTLS(t *testing.T) {
+	ctx := authz.NewContext(context.Background(), rootAdmin)
+	repo := seededRepo()
+	service := domains.NewService(repo)
+
+	d, err := service.SetRequireTLS(ctx, homeDomain, true)
+	require.NoError(t, err)
+	assert.True(t, d.RequireTLS())
+	assert.Equal(t, homeDomain, d.Name(), "the rest of the Domain is kept")
+
+	_, err = service.SetRequireTLS(ctx, otherDomain, true)
+	assert.ErrorIs(t, err, domains.ErrDomainNotFound)
+}
+
+func TestSetIPPool(t *testing.T) {
+	ctx := authz.NewContext(context.Background(), rootAdmin)
+	repo := seededRepo()
+	service := domains.NewService(repo)
+
+	_, err := service.SetIPPool(ctx, homeDomain, "Transactional")
+	assert.ErrorIs(t, err, values.ErrInvalidIPPool, "configured pool names are lower-case")
+	assert.Zero(t, repo.reached, "an invalid name never reaches the repository")
+
+	d, err := service.SetIPPool(ctx, homeDomain, "transactional")
+	require.NoError(t, err)
+	assert.Equal(t, "transactional", d.IPPool())
+	assert.Equal(t, homeDomain, d.Name(), "the rest of the Domain is kept")
+
+	d, err = service.SetIPPool(ctx, homeDomain, "")
+	require.NoError(t, err)
+	assert.Empty(t, d.IPPool(), "empty assigns the default pool")
+
+	_, err = service.SetIPPool(ctx, otherDomain, "transactional")
+	assert.ErrorIs(t, err, domains.ErrDomainNotFound)
+}
+
+func TestSetSMIMECertificate(t *testing.T) {
+	ctx := authz.NewContext(context.Background(), rootAdmin)
+	repo := seededRepo()
+	service := domains.NewService(repo)
+	certPEM, keyPEM := tests.SMIMECertificate(t, "news@example.com")
+	_, otherKey := tests.SMIMECertificate(t, "news@example.com")
+
+	_, err := service.SetSMIMECertificate(ctx, homeDomain, certPEM, otherKey)
+	assert.ErrorIs(t, err, smime.ErrInvalidCertificate, "a key that is not the certificate's")
+	_, err = service.SetSMIMECertificate(ctx, homeDomain, certPEM, "")
+	assert.ErrorIs(t, err, smime.ErrInvalidCertificate, "half a pair")
+
+	d, err := service.SetSMIMECertificate(ctx, homeDomain, certPEM, keyPEM)
+	require.NoError(t, err)
+	assert.Equal(t, certPEM, d.SMIME().Certificate)
+	_, err = d.SMIME().Signer()
+	assert.NoError(t, err, "what is stored can sign")
+
+	cleared, err := service.SetSMIMECertificate(ctx, homeDomain, "", "")
+	require.NoError(t, err)
+	assert.True(t, cleared.SMIME().IsZero())
+
+	_, err = service.SetSMIMECertificate(ctx, otherDomain, certPEM, keyPEM)
+	assert.ErrorIs(t, err, domains.ErrDomainNotFound)
+}
+
+// A rotation end to end through the Service: the pending key takes over signing only once its
+// record resolves, and the key it superseded can then be retired — but never the active one.
+func TestDKIMKeyRotation(t *testing.T) {
+	ctx := authz.NewContext(context.Background(), rootAdmin)
+	repo := seededRepo()
+	published := map[string][]string{}
+	service := domains.NewService(repo, domains.WithTXTLookup(func(_ context.Context, name string) ([]string, error) {
+		return published[name], nil
+	}))
+
+	k, err := service.RotateDKIMKey(ctx, homeDomain, "", "")
+	require.NoError(t, err)
+	assert.Regexp(t, `^kannon-\d{8}-\d{6}$`, k.Selector(), "an unnamed rotation gets a time-stamped selector")
+	assert.NotEmpty(t, k.PublicKey())
+	assert.Equal(t, k.Selector()+"._domainkey.example.com", k.RecordName(homeDomain.String()))
+
+	assert.Equal(t, dkim.AlgorithmRSA, k.Algorithm(), "a rotation without an algorithm stays on RSA")
+
+	_, err = service.RotateDKIMKey(ctx, homeDomain, "Not A Selector", "")
+	assert.ErrorIs(t, err, dkim.ErrInvalidSelector)
+	_, err = service.RotateDKIMKey(ctx, homeDomain, "", "dsa")
+	assert.ErrorIs(t, err, dkim.ErrUnsupportedAlgorithm)
+
+	_, err = service.ActivateDKIMKey(ctx, homeDomain, k.Selector(), true)
+	assert.ErrorIs(t, err, domains.ErrDKIMRecordNotVerified, "the record is not published yet")
+
+	published[k.RecordName(homeDomain.String())] = []string{k.RecordValue()}
+	d, err := service.ActivateDKIMKey(ctx, homeDomain, k.Selector(), true)
+	require.NoError(t, err)
+	assert.Equal(t, k.PublicKey(), d.DkimPublicKey())
+
+	_, err = service.RetireDKIMKey(ctx, homeDomain, k.Selector())
+	assert.ErrorIs(t, err, domains.ErrDKIMKeyActive)
+
+	d, err = service.RetireDKIMKey(ctx, homeDomain, domains.InitialSelector)
+	require.NoError(t, err)
+	old, ok := d.DKIMKey(domains.InitialSelector)
+	require.True(t, ok)
+	assert.Equal(t, domains.DKIMKeyRetired, d.DKIMKeyState(old))
+
+	_, err = service.ActivateDKIMKey(ctx, homeDomain, domains.InitialSelector, false)
+	assert.ErrorIs(t, err, domains.ErrDKIMKeyRetired)
+	_, err = service.ActivateDKIMKey(ctx, homeDomain, "missing", false)
+	assert.ErrorIs(t, err, domains.ErrDKIMKeyNotFound)
+}
+
+// An Ed25519 key is added, and later dropped, beside the RSA key without disturbing it: the two
+// algorithms rotate independently, and an active key may be retired while another goes on signing.
+func TestEd25519KeySignsBesideRSA(t *testing.T) {
+	ctx := authz.NewContext(context.Background(), rootAdmin)
+	repo := seededRepo()
+	service := domains.NewService(repo)
+
+	ed, err := service.RotateDKIMKey(ctx, homeDomain, "", dkim.AlgorithmEd25519)
+	require.NoError(t, err)
+	assert.Regexp(t, `^kannon-ed25519-\d{8}-\d{6}$`, ed.Selector())
+	assert.Contains(t, ed.RecordValue(), "k=ed25519;")
+
+	d, err := service.ActivateDKIMKey(ctx, homeDomain, ed.Selector(), false)
+	require.NoError(t, err)
+	active := d.ActiveDKIMKeys()
+	require.Len(t, active, 2)
+	assert.Equal(t, domains.InitialSelector, active[0].Selector())
+	assert.Equal(t, ed.Selector(), active[1].Selector())
+	assert.Equal(t, "seeded-public-key", d.DkimPublicKey(), "dkim_pub_key keeps naming the RSA key")
+
+	d, err = service.RetireDKIMKey(ctx, homeDomain, ed.Selector())
+	require.NoError(t, err)
+	require.Len(t, d.ActiveDKIMKeys(), 1)
+
+	_, err = service.RetireDKIMKey(ctx, homeDomain, domains.InitialSelector)
+	assert.ErrorIs(t, err, domains.ErrDKIMKeyActive, "the last signing key stays")
+}
+
+// pendingSelector names the seeded Domain's second key, created but never activated.
+const pendingSelector = "pending"
+
 // fakeRepo is an in-memory Repository for these tests. It counts how many times it
 // was reached, which is what distinguishes an operation that was refused from one
 // that ran and then failed.
@@ -171,11 +533,25 @@ func seededRepo() *fakeRepo {
 	return &fakeRepo{
 		byName: map[values.DomainName]*domains.Domain{
 			homeDomain: domains.Load(domains.LoadParams{
-				ID:            1,
-				Domain:        homeDomain,
-				DkimPublicKey: "seeded-public-key",
-				CreatedAt:     time.Now(),
-				Tracking:      tracking.Policy{Opens: tracking.ModeIdentified, Links: tracking.ModeIdentified},
+				ID:     1,
+				Domain: homeDomain,
+				DKIMKeys: []domains.DKIMKey{
+					domains.LoadDKIMKey(domains.DKIMKeyParams{
+						Selector:    domains.InitialSelector,
+						Algorithm:   dkim.AlgorithmRSA,
+						PublicKey:   "seeded-public-key",
+						CreatedAt:   time.Now().Add(-time.Hour),
+						ActivatedAt: time.Now().Add(-time.Hour),
+					}),
+					domains.LoadDKIMKey(domains.DKIMKeyParams{
+						Selector:  pendingSelector,
+						Algorithm: dkim.AlgorithmRSA,
+						PublicKey: "pending-public-key",
+						CreatedAt: time.Now(),
+					}),
+				},
+				CreatedAt: time.Now(),
+				Tracking:  tracking.Policy{Opens: tracking.ModeIdentified, Links: tracking.ModeIdentified},
 			}),
 		},
 	}
@@ -194,12 +570,155 @@ func (r *fakeRepo) SetTrackingPolicy(_ context.Context, domain values.DomainName
 		return nil, domains.ErrDomainNotFound
 	}
 	updated := domains.Load(domains.LoadParams{
-		ID:             d.ID(),
-		Domain:         d.Name(),
-		DkimPrivateKey: d.DkimPrivateKey(),
-		DkimPublicKey:  d.DkimPublicKey(),
-		CreatedAt:      d.CreatedAt(),
-		Tracking:       p,
+		ID:               d.ID(),
+		Domain:           d.Name(),
+		DKIMKeys:         d.DKIMKeys(),
+		CreatedAt:        d.CreatedAt(),
+		Tracking:         p,
+		ReturnPathDomain: d.ReturnPathDomain(),
+		Feedback:         d.Feedback(),
+		SMIME:            d.SMIME(),
+		LinkDecoration:   d.LinkDecoration(),
+		RequireTLS:       d.RequireTLS(),
+		IPPool:           d.IPPool(),
+	})
+	r.byName[domain] = updated
+	return updated, nil
+}
+
+func (r *fakeRepo) SetReturnPathDomain(_ context.Context, domain values.DomainName, rp values.DomainName) (*domains.Domain, error) {
+	r.reached++
+	d, ok := r.byName[domain]
+	if !ok {
+		return nil, domains.ErrDomainNotFound
+	}
+	updated := domains.Load(domains.LoadParams{
+		ID:               d.ID(),
+		Domain:           d.Name(),
+		DKIMKeys:         d.DKIMKeys(),
+		CreatedAt:        d.CreatedAt(),
+		Tracking:         d.TrackingPolicy(),
+		ReturnPathDomain: rp,
+		Feedback:         d.Feedback(),
+		SMIME:            d.SMIME(),
+		LinkDecoration:   d.LinkDecoration(),
+		RequireTLS:       d.RequireTLS(),
+		IPPool:           d.IPPool(),
+	})
+	r.byName[domain] = updated
+	return updated, nil
+}
+
+func (r *fakeRepo) SetFeedbackIdentity(_ context.Context, domain values.DomainName, id feedback.Identity) (*domains.Domain, error) {
+	r.reached++
+	d, ok := r.byName[domain]
+	if !ok {
+		return nil, domains.ErrDomainNotFound
+	}
+	updated := domains.Load(domains.LoadParams{
+		ID:               d.ID(),
+		Domain:           d.Name(),
+		DKIMKeys:         d.DKIMKeys(),
+		CreatedAt:        d.CreatedAt(),
+		Tracking:         d.TrackingPolicy(),
+		ReturnPathDomain: d.ReturnPathDomain(),
+		Feedback:         id,
+		SMIME:            d.SMIME(),
+		LinkDecoration:   d.LinkDecoration(),
+		RequireTLS:       d.RequireTLS(),
+		IPPool:           d.IPPool(),
+	})
+	r.byName[domain] = updated
+	return updated, nil
+}
+
+func (r *fakeRepo) SetSMIME(_ context.Context, domain values.DomainName, c smime.Credentials) (*domains.Domain, error) {
+	r.reached++
+	d, ok := r.byName[domain]
+	if !ok {
+		return nil, domains.ErrDomainNotFound
+	}
+	updated := domains.Load(domains.LoadParams{
+		ID:               d.ID(),
+		Domain:           d.Name(),
+		DKIMKeys:         d.DKIMKeys(),
+		CreatedAt:        d.CreatedAt(),
+		Tracking:         d.TrackingPolicy(),
+		ReturnPathDomain: d.ReturnPathDomain(),
+		Feedback:         d.Feedback(),
+		SMIME:            c,
+		LinkDecoration:   d.LinkDecoration(),
+		RequireTLS:       d.RequireTLS(),
+		IPPool:           d.IPPool(),
+	})
+	r.byName[domain] = updated
+	return updated, nil
+}
+
+func (r *fakeRepo) SetLinkDecoration(_ context.Context, domain values.DomainName, dec linkparams.Decoration) (*domains.Domain, error) {
+	r.reached++
+	d, ok := r.byName[domain]
+	if !ok {
+		return nil, domains.ErrDomainNotFound
+	}
+	updated := domains.Load(domains.LoadParams{
+		ID:               d.ID(),
+		Domain:           d.Name(),
+		DKIMKeys:         d.DKIMKeys(),
+		CreatedAt:        d.CreatedAt(),
+		Tracking:         d.TrackingPolicy(),
+		ReturnPathDomain: d.ReturnPathDomain(),
+		Feedback:         d.Feedback(),
+		SMIME:            d.SMIME(),
+		LinkDecoration:   dec,
+		RequireTLS:       d.RequireTLS(),
+		IPPool:           d.IPPool(),
+	})
+	r.byName[domain] = updated
+	return updated, nil
+}
+
+func (r *fakeRepo) SetIPPool(_ context.Context, domain values.DomainName, pool string) (*domains.Domain, error) {
+	r.reached++
+	d, ok := r.byName[domain]
+	if !ok {
+		return nil, domains.ErrDomainNotFound
+	}
+	updated := domains.Load(domains.LoadParams{
+		ID:               d.ID(),
+		Domain:           d.Name(),
+		DKIMKeys:         d.DKIMKeys(),
+		CreatedAt:        d.CreatedAt(),
+		Tracking:         d.TrackingPolicy(),
+		ReturnPathDomain: d.ReturnPathDomain(),
+		Feedback:         d.Feedback(),
+		SMIME:            d.SMIME(),
+		LinkDecoration:   d.LinkDecoration(),
+		RequireTLS:       d.RequireTLS(),
+		IPPool:           pool,
+	})
+	r.byName[domain] = updated
+	return updated, nil
+}
+
+func (r *fakeRepo) SetRequireTLS(_ context.Context, domain values.DomainName, require bool) (*domains.Domain, error) {
+	r.reached++
+	d, ok := r.byName[domain]
+	if !ok {
+		return nil, domains.ErrDomainNotFound
+	}
+	updated := domains.Load(domains.LoadParams{
+		ID:               d.ID(),
+		Domain:           d.Name(),
+		DKIMKeys:         d.DKIMKeys(),
+		CreatedAt:        d.CreatedAt(),
+		Tracking:         d.TrackingPolicy(),
+		ReturnPathDomain: d.ReturnPathDomain(),
+		Feedback:         d.Feedback(),
+		SMIME:            d.SMIME(),
+		LinkDecoration:   d.LinkDecoration(),
+		RequireTLS:       require,
+		IPPool:           d.IPPool(),
 	})
 	r.byName[domain] = updated
 	return updated, nil
@@ -222,3 +741,80 @@ func (r *fakeRepo) List(_ context.Context) ([]*domains.Domain, error) {
 	}
 	return all, nil
 }
+
+func (r *fakeRepo) AddDKIMKey(_ context.Context, domain values.DomainName, k domains.DKIMKey) (*domains.Domain, error) {
+	r.reached++
+	d, ok := r.byName[domain]
+	if !ok {
+		return nil, domains.ErrDomainNotFound
+	}
+	if _, taken := d.DKIMKey(k.Selector()); taken {
+		return nil, domains.ErrDKIMSelectorTaken
+	}
+	stored := domains.LoadDKIMKey(domains.DKIMKeyParams{
+		Selector:   k.Selector(),
+		Algorithm:  k.Algorithm(),
+		PrivateKey: k.PrivateKey(),
+		PublicKey:  k.PublicKey(),
+		CreatedAt:  time.Now(),
+	})
+	return r.replaceKeys(d, append(d.DKIMKeys(), stored)), nil
+}
+
+func (r *fakeRepo) ActivateDKIMKey(_ context.Context, domain values.DomainName, selector string) (*domains.Domain, error) {
+	r.reached++
+	return r.stamp(domain, selector, func(p *domains.DKIMKeyParams) { p.ActivatedAt = time.Now() })
+}
+
+func (r *fakeRepo) RetireDKIMKey(_ context.Context, domain values.DomainName, selector string) (*domains.Domain, error) {
+	r.reached++
+	return r.stamp(domain, selector, func(p *domains.DKIMKeyParams) { p.RetiredAt = time.Now() })
+}
+
+// stamp rewrites one non-retired key, the way the UPDATEs behind Activate and Retire do.
+func (r *fakeRepo) stamp(domain values.DomainName, selector string, set func(*domains.DKIMKeyParams)) (*domains.Domain, error) {
+	d, ok := r.byName[domain]
+	if !ok {
+		return nil, domains.ErrDomainNotFound
+	}
+	keys := make([]domains.DKIMKey, 0, len(d.DKIMKeys()))
+	found := false
+	for _, k := range d.DKIMKeys() {
+		if k.Selector() == selector && !k.Retired() {
+			p := domains.DKIMKeyParams{
+				Selector:    k.Selector(),
+				Algorithm:   k.Algorithm(),
+				PrivateKey:  k.PrivateKey(),
+				PublicKey:   k.PublicKey(),
+				CreatedAt:   k.CreatedAt(),
+				ActivatedAt: k.ActivatedAt(),
+				RetiredAt:   k.RetiredAt(),
+			}
+			set(&p)
+			k, found = domains.LoadDKIMKey(p), true
+		}
+		keys = append(keys, k)
+	}
+	if !found {
+		return nil, domains.ErrDKIMKeyNotFound
+	}
+	return r.replaceKeys(d, keys), nil
+}
+
+func (r *fakeRepo) replaceKeys(d *domains.Domain, keys []domains.DKIMKey) *domains.Domain {
+	updated := domains.Load(domains.LoadParams{
+		ID:               d.ID(),
+		Domain:           d.Name(),
+		DKIMKeys:         keys,
+		CreatedAt:        d.CreatedAt(),
+		Tracking:         d.TrackingPolicy(),
+		ReturnPathDomain: d.ReturnPathDomain(),
+		Feedback:         d.Feedback(),
+		SMIME:            d.SMIME(),
+		LinkDecoration:   d.LinkDecoration(),
+		RequireTLS:       d.RequireTLS(),
+		IPPool:           d.IPPool(),
+	})
+	r.byName[d.Name()] = updated
+	return updated
+}
@@ -0,0 +1,145 @@
+package envelope
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kannon-email/kannon/internal/batch"
+	"github.com/kannon-email/kannon/internal/dkim"
+	"github.com/kannon-email/kannon/internal/utils"
+)
+
+// preparedBatchGeneration is how many Batches one generation of the preparation
+// cache holds before it is rotated. A preparation holds its Batch's template and
+// attachments twice over — raw and encoded — so this bounds the cache by Batch
+// count rather than bytes, which is the shape of the access pattern: a page of
+// Deliveries spans a handful of Batches, not hundreds.
+const preparedBatchGeneration = 64
+
+// preparedBatch is what every Delivery of one Batch is rendered from, worked out
+// once: the SendingData, the subject and HTML with their placeholders located,
+// the Domain's DKIM keys decoded, and the attachments base64-encoded. What is
+// left for each Delivery is its own — the personalisation, its tracking tokens,
+// the MIME layout, S/MIME and the signatures themselves.
+//
+// It is read-only once prepared, so any number of workers may build from it.
+type preparedBatch struct {
+	b       *defaultBuilder
+	batchID batch.ID
+	// version is the source's version of data, empty against a source that
+	// cannot tell one.
+	version     string
+	data        SendingData
+	html        utils.Template
+	subject     utils.Template
+	signer      *dkim.Signer
+	attachments []attachmentPart
+}
+
+// ForBatch returns the Batch's preparation, reusing the one held from an earlier
+// call while the source reports the same version for it.
+//
+// The version is read before the data. A change landing between the two is
+// therefore held under the older version, and the next ForBatch sees the version
+// move and prepares again: the cache can lag a change by one call, never keep a
+// stale preparation beyond it.
+func (b *defaultBuilder) ForBatch(ctx context.Context, batchID batch.ID) (BatchBuilder, error) {
+	versioned, ok := b.source.(VersionedSource)
+	if !ok {
+		return b.prepare(ctx, batchID, "")
+	}
+
+	version, err := versioned.SendingDataVersion(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if p := b.prepared.get(batchID, version); p != nil {
+		return p, nil
+	}
+	p, err := b.prepare(ctx, batchID, version)
+	if err != nil {
+		return nil, err
+	}
+	b.prepared.put(p)
+	return p, nil
+}
+
+// prepare loads and works out a Batch's preparation. A Domain without a usable
+// DKIM key fails here, for the whole Batch, before any Delivery is rendered.
+func (b *defaultBuilder) prepare(ctx context.Context, batchID batch.ID, version string) (*preparedBatch, error) {
+	data, err := b.source.GetSendingData(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := dkim.NewSigner(data.Domain, dkimSignedHeaders, data.DkimKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &preparedBatch{
+		b:           b,
+		batchID:     batchID,
+		version:     version,
+		data:        data,
+		html:        utils.ParseTemplate(data.HTML),
+		subject:     utils.ParseTemplate(data.Subject),
+		signer:      signer,
+		attachments: encodeAttachments(data.Attachments),
+	}, nil
+}
+
+// preparedBatches holds preparations by Batch, bounded the way sharedTokens is
+// and for the same reason: a Batch's Deliveries are built in runs, so one that
+// falls out of the live generation is finished with or promoted back on its next
+// use.
+//
+// Unlike a shared token, two preparations of one Batch are interchangeable, so a
+// miss is prepared outside the lock and two workers racing on it merely do the
+// work twice.
+type preparedBatches struct {
+	mu   sync.Mutex
+	live map[batch.ID]*preparedBatch
+	prev map[batch.ID]*preparedBatch
+}
+
+func newPreparedBatches() *preparedBatches {
+	return &preparedBatches{
+		live: make(map[batch.ID]*preparedBatch, preparedBatchGeneration),
+	}
+}
+
+// get returns the preparation held for the Batch at version, nil if there is
+// none or it was prepared from another version.
+func (c *preparedBatches) get(id batch.ID, version string) *preparedBatch {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p, ok := c.live[id]; ok && p.version == version {
+		return p
+	}
+	if p, ok := c.prev[id]; ok && p.version == version {
+		c.putLocked(p)
+		return p
+	}
+	return nil
+}
+
+func (c *preparedBatches) put(p *preparedBatch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.putLocked(p)
+}
+
+func (c *preparedBatches) putLocked(p *preparedBatch) {
+	if _, ok := c.live[p.batchID]; !ok && len(c.live) >= preparedBatchGeneration {
+		c.prev, c.live = c.live, make(map[batch.ID]*preparedBatch, preparedBatchGeneration)
+	}
+	c.live[p.batchID] = p
+}
+
+// len reports how many preparations the cache holds across both generations,
+// for the test that pins the bound.
+func (c *preparedBatches) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.live) + len(c.prev)
+}
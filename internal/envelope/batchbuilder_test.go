@@ -0,0 +1,175 @@
+package envelope_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/mail"
+	"sync"
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/batch"
+	"github.com/kannon-email/kannon/internal/dkim"
+	"github.com/kannon-email/kannon/internal/envelope"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versionedSource serves SendingData under a version the test moves by hand,
+// counting how often the Builder loads it.
+type versionedSource struct {
+	mu      sync.Mutex
+	data    envelope.SendingData
+	version string
+	loads   int
+}
+
+func (s *versionedSource) GetSendingData(_ context.Context, _ batch.ID) (envelope.SendingData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loads++
+	return s.data, nil
+}
+
+func (s *versionedSource) SendingDataVersion(_ context.Context, _ batch.ID) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version, nil
+}
+
+func (s *versionedSource) update(subject, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Subject = subject
+	s.version = version
+}
+
+func (s *versionedSource) loaded() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loads
+}
+
+func newVersionedSource(t *testing.T) *versionedSource {
+	return &versionedSource{
+		version: "v1",
+		data: envelope.SendingData{
+			Subject:     "Hello {{ name }}",
+			HTML:        "<html><body>hi {{ name }}</body></html>",
+			Domain:      "test.com",
+			MessageID:   testBatchID,
+			SenderEmail: "noreply@test.com",
+			SenderAlias: "Test",
+			DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: newDKIMKeys(t)}},
+			Attachments: map[string][]byte{"a.txt": []byte("first")},
+		},
+	}
+}
+
+func subjectOf(t *testing.T, env *envelope.Envelope) string {
+	t.Helper()
+	parsed, err := mail.ReadMessage(bytes.NewReader(env.Body()))
+	require.NoError(t, err)
+	return parsed.Header.Get("Subject")
+}
+
+func TestForBatchReusesAPreparationWhileItsVersionHolds(t *testing.T) {
+	src := newVersionedSource(t)
+	b := envelope.NewBuilderWith(src, stubTokens{link: "ltok", open: "otok"})
+
+	for _, name := range []string{"Mario", "Luigi"} {
+		env, err := b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", map[string]string{"name": name}))
+		require.NoError(t, err)
+		assert.Equal(t, "Hello "+name, subjectOf(t, env))
+	}
+	assert.Equal(t, 1, src.loaded(), "the second Build reuses the first one's preparation")
+
+	src.update("Welcome {{ name }}", "v2")
+	env, err := b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", map[string]string{"name": "Mario"}))
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome Mario", subjectOf(t, env), "a changed Template is picked up on the next Build")
+	assert.Equal(t, 2, src.loaded())
+}
+
+// Against a source that cannot tell a version, nothing is held across calls.
+func TestForBatchPreparesAfreshWithoutAVersion(t *testing.T) {
+	src := newVersionedSource(t)
+	b := envelope.NewBuilderWith(stubSource{data: src.data}, stubTokens{link: "ltok", open: "otok"})
+
+	p, err := b.ForBatch(t.Context(), batch.ID(testBatchID))
+	require.NoError(t, err)
+	q, err := b.ForBatch(t.Context(), batch.ID(testBatchID))
+	require.NoError(t, err)
+	assert.NotSame(t, p, q)
+}
+
+func TestBatchBuilderBuildsConcurrently(t *testing.T) {
+	src := newVersionedSource(t)
+	b := envelope.NewBuilderWith(src, &countingTokens{})
+
+	p, err := b.ForBatch(t.Context(), batch.ID(testBatchID))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("rcpt-%d", i)
+			env, err := p.Build(t.Context(), mustDelivery(t, name+"@example.com", map[string]string{"name": name}))
+			if assert.NoError(t, err) {
+				assert.Equal(t, "Hello "+name, subjectOf(t, env))
+				assert.Equal(t, name+"@example.com", env.To())
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, src.loaded())
+}
+
+func TestBatchBuilderRefusesAnotherBatchesDelivery(t *testing.T) {
+	b := envelope.NewBuilderWith(newVersionedSource(t), stubTokens{})
+
+	p, err := b.ForBatch(t.Context(), batch.ID("other@test.com"))
+	require.NoError(t, err)
+	_, err = p.Build(t.Context(), mustDelivery(t, "rcpt@example.com", nil))
+	assert.Error(t, err)
+}
+
+// A Domain that cannot sign fails the Batch once, at preparation, rather than
+// every one of its Deliveries after rendering it.
+func TestForBatchFailsWithoutASigningKey(t *testing.T) {
+	src := newVersionedSource(t)
+	src.data.DkimKeys = nil
+	b := envelope.NewBuilderWith(src, stubTokens{})
+
+	_, err := b.ForBatch(t.Context(), batch.ID(testBatchID))
+	assert.ErrorIs(t, err, dkim.ErrNoSigningKey)
+}
+
+// The Envelope carries the TLS requirement of its Domain or Batch to the
+// SMTPSender, which is the one that can enforce it.
+func TestBuiltEnvelopeCarriesTheTLSRequirement(t *testing.T) {
+	src := newVersionedSource(t)
+	b := envelope.NewBuilderWith(stubSource{data: src.data}, stubTokens{link: "ltok", open: "otok"})
+	env, err := b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", nil))
+	require.NoError(t, err)
+	assert.False(t, env.RequireTLS())
+
+	src.data.RequireTLS = true
+	b = envelope.NewBuilderWith(stubSource{data: src.data}, stubTokens{link: "ltok", open: "otok"})
+	env, err = b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", nil))
+	require.NoError(t, err)
+	assert.True(t, env.RequireTLS())
+}
+
+// The IP pool the Batch's mail is sent from travels with each Envelope, so the
+// SMTPSender never has to ask whose it is.
+func TestBuiltEnvelopeCarriesTheIPPool(t *testing.T) {
+	src := newVersionedSource(t)
+	src.data.IPPool = "transactional"
+	b := envelope.NewBuilderWith(stubSource{data: src.data}, stubTokens{link: "ltok", open: "otok"})
+	env, err := b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "transactional", env.IPPool())
+}
@@ -1,36 +1,63 @@
 package envelope
 
 import (
-	"bytes"
+	"cmp"
 	"context"
+	"crypto/x509"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/kannon-email/kannon/internal/batch"
 	sqlc "github.com/kannon-email/kannon/internal/db"
 	"github.com/kannon-email/kannon/internal/delivery"
 	"github.com/kannon-email/kannon/internal/dkim"
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/returnpath"
+	"github.com/kannon-email/kannon/internal/smime"
 	"github.com/kannon-email/kannon/internal/statssec"
 	"github.com/kannon-email/kannon/internal/tracking"
 	"github.com/kannon-email/kannon/internal/utils"
 )
 
 // SendingData is the per-Batch lookup the Builder needs to render an
-// outgoing Envelope: template HTML + Domain DKIM keys + Batch metadata.
-// It is populated by a SendingDataSource at the storage boundary.
+// outgoing Envelope: template HTML + the Domain's active DKIM keys + Batch metadata.
+// It is populated by a SendingDataSource at the storage boundary. DkimKeys holds
+// one key per algorithm the Domain signs with, each beside its selector, because
+// a rotation changes both at once.
 type SendingData struct {
-	Subject        string
-	HTML           string
-	Domain         string
-	MessageID      string
-	SenderEmail    string
-	SenderAlias    string
-	DkimPrivateKey string
-	Attachments    map[string][]byte
-	Headers        batch.Headers
+	Subject     string
+	HTML        string
+	Domain      string
+	MessageID   string
+	SenderEmail string
+	SenderAlias string
+	DkimKeys    []dkim.SigningKey
+	// ReturnPathDomain is the domain the Envelope's MAIL FROM is addressed to,
+	// empty for the sending Domain itself.
+	ReturnPathDomain string
+	Attachments      map[string][]byte
+	Headers          batch.Headers
 	// OneClickUnsubscribe is the sender's unsubscribe endpoint as stated for the
 	// Batch, zero when it stated none.
 	OneClickUnsubscribe batch.OneClickUnsubscribe
+	// Feedback is the Feedback Identity the Batch's mail carries: the Domain's,
+	// with whatever the Batch overrode laid over it.
+	Feedback feedback.Identity
+	// LinkDecoration is what the Batch's links are decorated with: the Domain's
+	// parameters with the Batch's overrides laid over them, on the Domain's
+	// hosts.
+	LinkDecoration linkparams.Decoration
+	// SMIME signs the Batch's mail with the Domain's S/MIME certificate; nil
+	// when the Domain has none.
+	SMIME *smime.Signer
+	// RequireTLS is set when the Domain or the Batch requires authenticated
+	// TLS; either is enough.
+	RequireTLS bool
+	// IPPool is the IP pool the Batch's mail is sent from: the Batch's own
+	// choice over the Domain's.
+	IPPool string
 }
 
 // SendingDataSource looks up the rendering inputs for a Batch.
@@ -38,6 +65,16 @@ type SendingDataSource interface {
 	GetSendingData(ctx context.Context, batchID batch.ID) (SendingData, error)
 }
 
+// VersionedSource is a SendingDataSource that can also say, far more cheaply
+// than loading it, which version of a Batch's SendingData it would load: a
+// string that changes whenever the Template or the Domain behind the Batch does.
+// The Builder keeps a Batch's preparation across dispatch cycles only against
+// such a source, and prepares it afresh for each ForBatch against any other.
+type VersionedSource interface {
+	SendingDataSource
+	SendingDataVersion(ctx context.Context, batchID batch.ID) (string, error)
+}
+
 // TokenIssuer mints click/open tokens for tracking link rewriting. Each token
 // carries the Tracking Mode of the axis it belongs to — the opens Mode on an
 // open token, the links Mode on a link token — signed, so the Tracker can trust
@@ -55,34 +92,57 @@ type TokenIssuer interface {
 
 // Builder renders a Delivery into an outgoing Envelope.
 type Builder interface {
+	// Build renders a single Delivery. It is ForBatch followed by the
+	// BatchBuilder's Build, for a caller with one Delivery in hand.
+	Build(ctx context.Context, d *delivery.Delivery) (*Envelope, error)
+	// ForBatch prepares what every Delivery of a Batch is rendered from — its
+	// SendingData, its parsed subject and HTML, its decoded DKIM keys and its
+	// encoded attachments — so that building each Delivery is left with only
+	// what is its own. See batchbuilder.go.
+	ForBatch(ctx context.Context, batchID batch.ID) (BatchBuilder, error)
+}
+
+// BatchBuilder renders the Deliveries of the one Batch it was prepared for. It
+// is safe for concurrent use, which is how the Dispatcher builds a page.
+type BatchBuilder interface {
 	Build(ctx context.Context, d *delivery.Delivery) (*Envelope, error)
 }
 
+// BuilderOption configures a Builder beyond its source and token issuer.
+type BuilderOption func(*defaultBuilder)
+
+// WithReturnPathKeys signs every bounce address the Builder writes with the
+// given Keyring (internal/returnpath). Without it, or with a Keyring holding
+// no keys, bounce addresses go out unsigned, as they always did.
+func WithReturnPathKeys(k *returnpath.Keyring) BuilderOption {
+	return func(b *defaultBuilder) {
+		b.returnPaths = k
+	}
+}
+
 // NewBuilder returns the default Builder backed by sqlc and the given
 // stats service. The sqlc-backed source resolves the Batch + Template +
 // Domain join in a single query (see internal/db/pool.sql).
-func NewBuilder(q *sqlc.Queries, st statssec.StatsService) Builder {
-	return &defaultBuilder{
-		source: sqlcSource{q: q},
-		tokens: st,
-		shared: newSharedTokens(),
-		baseHeaders: headers{
-			"X-Mailer": {"SMTP Mailer"},
-		},
-	}
+func NewBuilder(q *sqlc.Queries, st statssec.StatsService, opts ...BuilderOption) Builder {
+	return NewBuilderWith(sqlcSource{q: q}, st, opts...)
 }
 
 // NewBuilderWith wires a Builder against an explicit source + token issuer.
 // Useful for unit tests that want to stub both sides.
-func NewBuilderWith(source SendingDataSource, tokens TokenIssuer) Builder {
-	return &defaultBuilder{
-		source: source,
-		tokens: tokens,
-		shared: newSharedTokens(),
+func NewBuilderWith(source SendingDataSource, tokens TokenIssuer, opts ...BuilderOption) Builder {
+	b := &defaultBuilder{
+		source:   source,
+		tokens:   tokens,
+		shared:   newSharedTokens(),
+		prepared: newPreparedBatches(),
 		baseHeaders: headers{
 			"X-Mailer": {"SMTP Mailer"},
 		},
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
 }
 
 type defaultBuilder struct {
@@ -91,28 +151,41 @@ type defaultBuilder struct {
 	// shared holds the tokens that name no Recipient, so they are issued once per
 	// Batch rather than once per Delivery. It is per-Builder, and a Builder lives
 	// as long as the Dispatcher does.
-	shared      *sharedTokens
+	shared *sharedTokens
+	// prepared holds each Batch's preparation for as long as its version
+	// holds, when the source can tell one; see batchbuilder.go.
+	prepared    *preparedBatches
 	baseHeaders headers
+	// returnPaths signs the bounce address; nil leaves it unsigned.
+	returnPaths *returnpath.Keyring
 }
 
 func (b *defaultBuilder) Build(ctx context.Context, d *delivery.Delivery) (*Envelope, error) {
-	data, err := b.source.GetSendingData(ctx, d.BatchID())
+	p, err := b.ForBatch(ctx, d.BatchID())
 	if err != nil {
 		return nil, err
 	}
+	return p.Build(ctx, d)
+}
 
-	attachments := make(Attachments)
-	for name, raw := range data.Attachments {
-		attachments[name] = bytes.NewReader(raw)
+func (p *preparedBatch) Build(ctx context.Context, d *delivery.Delivery) (*Envelope, error) {
+	if d.BatchID() != p.batchID {
+		return nil, fmt.Errorf("delivery of batch %s cannot be built as batch %s", d.BatchID(), p.batchID)
 	}
+	data := p.data
 
-	returnPath := buildReturnPath(d.Email(), data.MessageID)
-	msg, err := b.prepareMessage(ctx, d, data, attachments)
+	tag := p.b.returnPaths.Tag(d.Email(), data.MessageID, time.Now())
+	returnPath := buildReturnPath(d.Email(), data.MessageID, data.ReturnPathDomain, tag)
+	msg, err := p.prepareMessage(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	msg, err = applySMIME(msg, data.SMIME, d.SMIMECertificate())
 	if err != nil {
 		return nil, err
 	}
 
-	signedMsg, err := signMessage(data.Domain, data.DkimPrivateKey, msg)
+	signedMsg, err := p.signer.Sign(msg)
 	if err != nil {
 		return nil, err
 	}
@@ -128,22 +201,51 @@ func (b *defaultBuilder) Build(ctx context.Context, d *delivery.Delivery) (*Enve
 		// decision to stop belongs to the Delivery and is a span of time, not a
 		// count of attempts (ADR 0007).
 		ShouldRetry: d.CanRetry(),
+		RequireTLS:  data.RequireTLS,
+		IPPool:      data.IPPool,
+		SMTPUTF8:    headerNeedsSMTPUTF8(signedMsg),
 	}), nil
 }
 
-func (b *defaultBuilder) prepareMessage(ctx context.Context, d *delivery.Delivery, data SendingData, attachments Attachments) ([]byte, error) {
+func (p *preparedBatch) prepareMessage(ctx context.Context, d *delivery.Delivery) ([]byte, error) {
+	data := p.data
 	emailMessageID := buildEmailID(d.Email(), data.MessageID)
 	fields := utils.EffectiveFields(d.Email(), d.Fields())
-	html, err := b.preparedHTML(ctx, d, data, fields)
+	html, err := p.preparedHTML(ctx, d, fields)
 	if err != nil {
 		return nil, err
 	}
-	subject := utils.ReplaceCustomFields(data.Subject, fields)
+	subject := p.subject.Render(fields)
 
 	sender := batch.Sender{Email: data.SenderEmail, Alias: data.SenderAlias}
-	h := buildHeaders(subject, sender, d.Email(), data.MessageID, emailMessageID, b.baseHeaders, data.Headers,
+	h := buildHeaders(subject, sender, d.Email(), data.MessageID, emailMessageID, p.b.baseHeaders, data.Headers,
 		resolveUnsubscribeURL(data.OneClickUnsubscribe, fields))
-	return renderMsg(html, h, attachments)
+	addFeedbackHeaders(h, data.Domain, data.Feedback)
+	return renderMsg(html, h, p.attachments)
+}
+
+// applySMIME signs the rendered message with the Domain's certificate, then
+// encrypts it to the Recipient's, each only when there is one. Both happen
+// before DKIM signs: S/MIME rewrites the body and the Content-Type, which DKIM
+// covers, while the headers DKIM signs are left where they were.
+func applySMIME(msg []byte, signer *smime.Signer, recipientDER []byte) ([]byte, error) {
+	var err error
+	if signer != nil {
+		if msg, err = signer.Sign(msg); err != nil {
+			return nil, fmt.Errorf("cannot S/MIME-sign the message: %w", err)
+		}
+	}
+	if len(recipientDER) == 0 {
+		return msg, nil
+	}
+	cert, err := x509.ParseCertificate(recipientDER)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the recipient's S/MIME certificate: %w", err)
+	}
+	if msg, err = smime.Encrypt(msg, cert); err != nil {
+		return nil, fmt.Errorf("cannot S/MIME-encrypt the message: %w", err)
+	}
+	return msg, nil
 }
 
 // resolveUnsubscribeURL personalises the Batch's unsubscribe endpoint for one
@@ -189,17 +291,7 @@ var dkimSignedHeaders = []string{
 	"From", "To", "Cc", "Subject", "Message-ID",
 	headerListUnsubscribe, headerListUnsubscribe,
 	headerListUnsubscribePost, headerListUnsubscribePost,
-}
-
-func signMessage(domain, dkimPrivateKey string, msg []byte) ([]byte, error) {
-	signData := dkim.SignData{
-		PrivateKey: dkimPrivateKey,
-		Domain:     domain,
-		Selector:   "kannon",
-		Headers:    dkimSignedHeaders,
-	}
-
-	return dkim.SignMessage(signData, bytes.NewReader(msg))
+	headerFeedbackID, headerListID,
 }
 
 // preparedHTML renders the Batch template for one Delivery and applies the
@@ -215,9 +307,16 @@ func signMessage(domain, dkimPrivateKey string, msg []byte) ([]byte, error) {
 // Whatever the Mode of a tracked axis is, it is minted into the token of that
 // axis, so the Tracker acts on the Policy frozen on this Delivery rather than on
 // whatever is configured when the engagement arrives.
-func (b *defaultBuilder) preparedHTML(ctx context.Context, d *delivery.Delivery, data SendingData, fields map[string]string) (string, error) {
+func (p *preparedBatch) preparedHTML(ctx context.Context, d *delivery.Delivery, fields map[string]string) (string, error) {
+	b, data := p.b, p.data
 	policy := d.TrackingPolicy()
-	html := utils.ReplaceCustomFields(data.HTML, fields)
+	html := p.html.Render(fields)
+
+	// Decorated before anything is minted from them, so that a link token
+	// records — and the Tracker redirects to — the URL with its parameters.
+	// Decoration does not depend on the links Mode: an untracked link is still
+	// one the landing page wants to attribute.
+	html = decorateLinks(html, data.LinkDecoration, fields)
 
 	identity, err := newTrackingIdentity(policy, d.Email(), data.Domain)
 	if err != nil {
@@ -394,28 +493,76 @@ func (s sqlcSource) GetSendingData(ctx context.Context, batchID batch.ID) (Sendi
 		return SendingData{}, err
 	}
 
+	keyRows, err := s.q.GetSigningDKIMKeys(ctx, row.Domain)
+	if err != nil {
+		return SendingData{}, err
+	}
+	keys := make([]dkim.SigningKey, 0, len(keyRows))
+	for _, k := range keyRows {
+		keys = append(keys, dkim.SigningKey{Selector: k.Selector, PrivateKey: k.PrivateKey})
+	}
+
+	var signer *smime.Signer
+	if row.SmimeCertificate.Valid {
+		creds := smime.Credentials{Certificate: row.SmimeCertificate.String, PrivateKey: row.SmimePrivateKey.String}
+		if signer, err = creds.Signer(); err != nil {
+			return SendingData{}, fmt.Errorf("domain %s: %w", row.Domain, err)
+		}
+	}
+
 	atts := make(map[string][]byte, len(row.Attachments))
 	for name, raw := range row.Attachments {
 		atts[name] = raw
 	}
 
 	return SendingData{
-		Subject:        row.Subject,
-		HTML:           row.Html,
-		Domain:         row.Domain,
-		MessageID:      row.MessageID,
-		SenderEmail:    row.SenderEmail,
-		SenderAlias:    row.SenderAlias,
-		DkimPrivateKey: row.DkimPrivateKey,
-		Attachments:    atts,
+		Subject:     row.Subject,
+		HTML:        row.Html,
+		Domain:      row.Domain,
+		MessageID:   row.MessageID,
+		SenderEmail: row.SenderEmail,
+		SenderAlias: row.SenderAlias,
+		DkimKeys:    keys,
+		// NULL reads as the empty string, which is the Domain itself.
+		ReturnPathDomain: row.ReturnPathDomain.String,
+		Attachments:      atts,
 		Headers: batch.Headers{
 			To: row.Headers.To,
 			Cc: row.Headers.Cc,
 		},
 		OneClickUnsubscribe: unsubscribeFromRow(row.Headers),
+		Feedback:            feedbackFromRow(row.Headers).Over(row.Feedback.Identity()),
+		LinkDecoration:      linkDecorationFromRow(row.Headers, row.LinkDecoration),
+		SMIME:               signer,
+		RequireTLS:          row.RequireTls || row.Headers.RequireTLS,
+		IPPool:              cmp.Or(row.Headers.IPPool, row.IpPool),
 	}, nil
 }
 
+// SendingDataVersion is a digest of the Template, the Domain row and the
+// Domain's keys behind the Batch, which is everything GetSendingData reads that
+// can change once the Batch exists.
+func (s sqlcSource) SendingDataVersion(ctx context.Context, batchID batch.ID) (string, error) {
+	return s.q.GetSendingDataVersion(ctx, batchID.String())
+}
+
+// linkDecorationFromRow lays the Batch's parameters over its Domain's. The
+// hosts are the Domain's alone: a Batch cannot widen where its parameters go.
+func linkDecorationFromRow(h sqlc.Headers, domain sqlc.LinkDecoration) linkparams.Decoration {
+	d := domain.Decoration()
+	d.Params = linkparams.Params(h.LinkParams).Over(d.Params)
+	return d
+}
+
+// feedbackFromRow reads the Batch's override of its Domain's Feedback Identity
+// out of the headers JSONB; absent, it overrides nothing.
+func feedbackFromRow(h sqlc.Headers) feedback.Identity {
+	if h.Feedback == nil {
+		return feedback.Identity{}
+	}
+	return h.Feedback.Identity()
+}
+
 // unsubscribeFromRow reads the unsubscribe endpoint out of the headers JSONB.
 // A Batch written before ADR 0005 has no such key, which is indistinguishable
 // from — and treated as — a Batch that states no endpoint.
@@ -13,11 +13,18 @@ import (
 	"testing"
 	"time"
 
+	msgauth "github.com/emersion/go-msgauth/dkim"
 	"github.com/kannon-email/kannon/internal/batch"
 	"github.com/kannon-email/kannon/internal/delivery"
 	"github.com/kannon-email/kannon/internal/dkim"
 	"github.com/kannon-email/kannon/internal/envelope"
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/returnpath"
+	"github.com/kannon-email/kannon/internal/smime"
+	"github.com/kannon-email/kannon/internal/tests"
 	"github.com/kannon-email/kannon/internal/tracking"
+	"github.com/kannon-email/kannon/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -125,7 +132,7 @@ func mustDeliveryTracked(t *testing.T, batchID batch.ID, email string, fields ma
 
 func newDKIMKeys(t *testing.T) (privateKey string) {
 	t.Helper()
-	keys, err := dkim.GenerateDKIMKeysPair()
+	keys, err := dkim.GenerateDKIMKeysPair(dkim.AlgorithmRSA)
 	assert.Nil(t, err)
 	return keys.PrivateKey
 }
@@ -133,13 +140,13 @@ func newDKIMKeys(t *testing.T) (privateKey string) {
 func TestBuilderRendersSubjectFromAndTo(t *testing.T) {
 	priv := newDKIMKeys(t)
 	src := stubSource{data: envelope.SendingData{
-		Subject:        "Hello {{ name }}",
-		HTML:           "<html><body>hi {{name }}</body></html>",
-		Domain:         "test.com",
-		MessageID:      "msg-1",
-		SenderEmail:    "noreply@test.com",
-		SenderAlias:    "Test",
-		DkimPrivateKey: priv,
+		Subject:     "Hello {{ name }}",
+		HTML:        "<html><body>hi {{name }}</body></html>",
+		Domain:      "test.com",
+		MessageID:   "msg-1",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: priv}},
 	}}
 	b := envelope.NewBuilderWith(src, stubTokens{link: "ltok", open: "otok"})
 
@@ -161,13 +168,13 @@ func TestBuilderRendersSubjectFromAndTo(t *testing.T) {
 func TestBuilderInsertsTrackingPixelAndRewritesLinks(t *testing.T) {
 	priv := newDKIMKeys(t)
 	src := stubSource{data: envelope.SendingData{
-		Subject:        "S",
-		HTML:           `<html><body><a href="https://example.com">x</a></body></html>`,
-		Domain:         "test.com",
-		MessageID:      "msg-1",
-		SenderEmail:    "noreply@test.com",
-		SenderAlias:    "Test",
-		DkimPrivateKey: priv,
+		Subject:     "S",
+		HTML:        `<html><body><a href="https://example.com">x</a></body></html>`,
+		Domain:      "test.com",
+		MessageID:   "msg-1",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: priv}},
 	}}
 	b := envelope.NewBuilderWith(src, stubTokens{link: "LTOK", open: "OTOK"})
 
@@ -189,13 +196,13 @@ func TestBuilderHonoursFrozenTrackingPolicy(t *testing.T) {
 	const authoredLink = "https://example.com/landing"
 	priv := newDKIMKeys(t)
 	src := stubSource{data: envelope.SendingData{
-		Subject:        "S",
-		HTML:           fmt.Sprintf(`<html><body><a href=%q>x</a></body></html>`, authoredLink),
-		Domain:         "test.com",
-		MessageID:      "msg-1",
-		SenderEmail:    "noreply@test.com",
-		SenderAlias:    "Test",
-		DkimPrivateKey: priv,
+		Subject:     "S",
+		HTML:        fmt.Sprintf(`<html><body><a href=%q>x</a></body></html>`, authoredLink),
+		Domain:      "test.com",
+		MessageID:   "msg-1",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: priv}},
 	}}
 	b := envelope.NewBuilderWith(src, stubTokens{link: "LTOK", open: "OTOK"})
 
@@ -276,11 +283,11 @@ func TestBuilderHonoursThePerLinkOptOut(t *testing.T) {
 		HTML: fmt.Sprintf(
 			`<html><body><a href=%q>promo</a><a href=%q data-no-track>preferences</a></body></html>`,
 			trackedLink, optedOutLink),
-		Domain:         "test.com",
-		MessageID:      "msg-1",
-		SenderEmail:    "noreply@test.com",
-		SenderAlias:    "Test",
-		DkimPrivateKey: priv,
+		Domain:      "test.com",
+		MessageID:   "msg-1",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: priv}},
 	}}
 	b := envelope.NewBuilderWith(src, stubTokens{link: "LTOK", open: "OTOK"})
 
@@ -324,13 +331,13 @@ func TestBuilderHonoursThePerLinkOptOut(t *testing.T) {
 func TestBuilderMintsTokensCarryingTheFrozenMode(t *testing.T) {
 	priv := newDKIMKeys(t)
 	src := stubSource{data: envelope.SendingData{
-		Subject:        "S",
-		HTML:           `<html><body><a href="https://example.com/landing">x</a></body></html>`,
-		Domain:         "test.com",
-		MessageID:      "msg-1",
-		SenderEmail:    "noreply@test.com",
-		SenderAlias:    "Test",
-		DkimPrivateKey: priv,
+		Subject:     "S",
+		HTML:        `<html><body><a href="https://example.com/landing">x</a></body></html>`,
+		Domain:      "test.com",
+		MessageID:   "msg-1",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: priv}},
 	}}
 	b := envelope.NewBuilderWith(src, modeEchoTokens{})
 
@@ -430,12 +437,12 @@ func trackedBuilderWith(t *testing.T, tokens envelope.TokenIssuer, links ...stri
 	body.WriteString("</body></html>")
 
 	return envelope.NewBuilderWith(batchSource{data: envelope.SendingData{
-		Subject:        "S",
-		HTML:           body.String(),
-		Domain:         "test.com",
-		SenderEmail:    "noreply@test.com",
-		SenderAlias:    "Test",
-		DkimPrivateKey: newDKIMKeys(t),
+		Subject:     "S",
+		HTML:        body.String(),
+		Domain:      "test.com",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: newDKIMKeys(t)}},
 	}}, tokens)
 }
 
@@ -533,12 +540,12 @@ func decodeQuotedPrintable(b []byte) (string, error) {
 func TestBuilderShouldRetryFollowsTheRetryBudget(t *testing.T) {
 	priv := newDKIMKeys(t)
 	src := stubSource{data: envelope.SendingData{
-		HTML:           "<html><body>x</body></html>",
-		Domain:         "test.com",
-		MessageID:      "msg-1",
-		SenderEmail:    "noreply@test.com",
-		SenderAlias:    "Test",
-		DkimPrivateKey: priv,
+		HTML:        "<html><body>x</body></html>",
+		Domain:      "test.com",
+		MessageID:   "msg-1",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: priv}},
 	}}
 	b := envelope.NewBuilderWith(src, stubTokens{})
 
@@ -567,13 +574,13 @@ func TestBuilderShouldRetryFollowsTheRetryBudget(t *testing.T) {
 func TestBuilderCarriesTheUnsubscribeEndpoint(t *testing.T) {
 	priv := newDKIMKeys(t)
 	src := stubSource{data: envelope.SendingData{
-		Subject:        "Hello",
-		HTML:           "<html><body>hi</body></html>",
-		Domain:         "test.com",
-		MessageID:      "msg-1",
-		SenderEmail:    "noreply@test.com",
-		SenderAlias:    "Test",
-		DkimPrivateKey: priv,
+		Subject:     "Hello",
+		HTML:        "<html><body>hi</body></html>",
+		Domain:      "test.com",
+		MessageID:   "msg-1",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: priv}},
 		OneClickUnsubscribe: batch.OneClickUnsubscribe{
 			URLTemplate: "https://sender.example/unsub?email={{ email }}",
 		},
@@ -601,13 +608,13 @@ func TestBuilderCarriesTheUnsubscribeEndpoint(t *testing.T) {
 func TestBuilderSignsAFixedHeaderSet(t *testing.T) {
 	priv := newDKIMKeys(t)
 	src := stubSource{data: envelope.SendingData{
-		Subject:        "Hello",
-		HTML:           "<html><body>hi</body></html>",
-		Domain:         "test.com",
-		MessageID:      "msg-1",
-		SenderEmail:    "noreply@test.com",
-		SenderAlias:    "Test",
-		DkimPrivateKey: priv,
+		Subject:     "Hello",
+		HTML:        "<html><body>hi</body></html>",
+		Domain:      "test.com",
+		MessageID:   "msg-1",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: priv}},
 	}}
 	b := envelope.NewBuilderWith(src, stubTokens{link: "ltok", open: "otok"})
 
@@ -630,5 +637,298 @@ func TestBuilderSignsAFixedHeaderSet(t *testing.T) {
 		"From", "To", "Cc", "Subject", "Message-ID",
 		"List-Unsubscribe", "List-Unsubscribe",
 		"List-Unsubscribe-Post", "List-Unsubscribe-Post",
+		"Feedback-ID", "List-Id",
 	}, signed)
 }
+
+// TestBuilderSignsWithEveryActiveKey is what a Domain signing with RSA and
+// Ed25519 at once looks like on the wire: one DKIM-Signature per key, each
+// naming its own selector and algorithm, and both verifying.
+func TestBuilderSignsWithEveryActiveKey(t *testing.T) {
+	records := map[string][]string{}
+	var keys []dkim.SigningKey
+	for _, alg := range []string{dkim.AlgorithmRSA, dkim.AlgorithmEd25519} {
+		pair, err := dkim.GenerateDKIMKeysPair(alg)
+		require.NoError(t, err)
+		selector := "kannon-" + alg
+		keys = append(keys, dkim.SigningKey{Selector: selector, PrivateKey: pair.PrivateKey})
+		records[dkim.RecordName(selector, "test.com")] = []string{dkim.RecordValue(alg, pair.PublicKey)}
+	}
+
+	src := stubSource{data: envelope.SendingData{
+		Subject:     "Hello",
+		HTML:        "<html><body>hi</body></html>",
+		Domain:      "test.com",
+		MessageID:   "msg-1",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    keys,
+	}}
+	b := envelope.NewBuilderWith(src, stubTokens{link: "ltok", open: "otok"})
+
+	env, err := b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", nil))
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(env.Body()))
+	require.NoError(t, err)
+	sigs := parsed.Header["Dkim-Signature"]
+	require.Len(t, sigs, 2)
+	joined := strings.Join(sigs, "\n")
+	assert.Contains(t, joined, "a=rsa-sha256")
+	assert.Contains(t, joined, "a=ed25519-sha256")
+
+	verifications, err := msgauth.VerifyWithOptions(bytes.NewReader(env.Body()), &msgauth.VerifyOptions{
+		LookupTXT: func(name string) ([]string, error) { return records[name], nil },
+	})
+	require.NoError(t, err)
+	require.Len(t, verifications, 2)
+	for _, v := range verifications {
+		assert.NoError(t, v.Err)
+	}
+}
+
+// A Domain with no active key cannot sign, and an unsigned message must not
+// leave: the Build fails rather than producing one.
+func TestBuilderRefusesToBuildWithoutAKey(t *testing.T) {
+	src := stubSource{data: envelope.SendingData{
+		Subject:     "Hello",
+		HTML:        "<html><body>hi</body></html>",
+		Domain:      "test.com",
+		MessageID:   "msg-1",
+		SenderEmail: "noreply@test.com",
+	}}
+	b := envelope.NewBuilderWith(src, stubTokens{link: "ltok", open: "otok"})
+
+	_, err := b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", nil))
+	assert.ErrorIs(t, err, dkim.ErrNoSigningKey)
+}
+
+// The MAIL FROM moves to the Domain's return-path domain when it has one, and
+// stays on the Domain when it does not.
+func TestBuilderAddressesTheReturnPathDomain(t *testing.T) {
+	data := envelope.SendingData{
+		Subject:     "Hello",
+		HTML:        "<html><body>hi</body></html>",
+		Domain:      "test.com",
+		MessageID:   "msg-1@test.com",
+		SenderEmail: "noreply@test.com",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: newDKIMKeys(t)}},
+	}
+
+	b := envelope.NewBuilderWith(stubSource{data: data}, stubTokens{link: "ltok", open: "otok"})
+	env, err := b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", nil))
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(env.ReturnPath(), "+msg-1@test.com"), env.ReturnPath())
+
+	data.ReturnPathDomain = "bounces.test.com"
+	b = envelope.NewBuilderWith(stubSource{data: data}, stubTokens{link: "ltok", open: "otok"})
+	env, err = b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", nil))
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(env.ReturnPath(), "+msg-1=test.com@bounces.test.com"), env.ReturnPath())
+
+	_, messageID, domain, found, err := utils.ParseBounceReturnPath(env.ReturnPath())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "msg-1@test.com", messageID)
+	assert.Equal(t, "test.com", domain)
+}
+
+// With a Keyring the bounce address carries a signature the SMTPServer will
+// accept, and still parses back to the Delivery it was written for.
+func TestBuilderSignsTheReturnPath(t *testing.T) {
+	keys, err := returnpath.New(returnpath.Config{Keys: []returnpath.KeyConfig{{ID: "1", Secret: "0123456789abcdef"}}})
+	require.NoError(t, err)
+
+	data := envelope.SendingData{
+		Subject:          "Hello",
+		HTML:             "<html><body>hi</body></html>",
+		Domain:           "test.com",
+		MessageID:        "msg-1@test.com",
+		SenderEmail:      "noreply@test.com",
+		DkimKeys:         []dkim.SigningKey{{Selector: "kannon", PrivateKey: newDKIMKeys(t)}},
+		ReturnPathDomain: "bounces.test.com",
+	}
+
+	b := envelope.NewBuilderWith(stubSource{data: data}, stubTokens{link: "ltok", open: "otok"}, envelope.WithReturnPathKeys(keys))
+	env, err := b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", nil))
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, utils.BounceReturnPathTag(env.ReturnPath()))
+	assert.NoError(t, keys.Verify(env.ReturnPath(), time.Now()))
+
+	email, messageID, domain, found, err := utils.ParseBounceReturnPath(env.ReturnPath())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "rcpt@example.com", email)
+	assert.Equal(t, "msg-1@test.com", messageID)
+	assert.Equal(t, "test.com", domain)
+}
+
+// The Feedback Identity reaches the message as the Domain stated it with the
+// Batch's overrides laid over it, and neither header appears without it.
+func TestBuilderWritesTheFeedbackHeaders(t *testing.T) {
+	data := envelope.SendingData{
+		Subject:     "Hello",
+		HTML:        "<html><body>hi</body></html>",
+		Domain:      "test.com",
+		MessageID:   "msg-1@test.com",
+		SenderEmail: "noreply@test.com",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: newDKIMKeys(t)}},
+	}
+	build := func(data envelope.SendingData) mail.Header {
+		t.Helper()
+		b := envelope.NewBuilderWith(stubSource{data: data}, stubTokens{link: "ltok", open: "otok"})
+		env, err := b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", nil))
+		require.NoError(t, err)
+		parsed, err := mail.ReadMessage(bytes.NewReader(env.Body()))
+		require.NoError(t, err)
+		return parsed.Header
+	}
+
+	h := build(data)
+	assert.Empty(t, h.Get("Feedback-ID"))
+	assert.Empty(t, h.Get("List-Id"))
+
+	data.Feedback = feedback.Identity{Campaign: "spring"}.Over(feedback.Identity{SenderID: "acme", ListID: "news.test.com"})
+	h = build(data)
+	assert.Equal(t, "spring:test.com:acme", h.Get("Feedback-ID"))
+	assert.Equal(t, "<news.test.com>", h.Get("List-Id"))
+}
+
+// S/MIME is applied to the rendered message and DKIM signs what results: the
+// signature still verifies, over a body that is now multipart/signed, or an
+// enveloped-data entity when the Recipient supplied a certificate.
+func TestBuilderAppliesSMIMEBeforeDKIM(t *testing.T) {
+	pair, err := dkim.GenerateDKIMKeysPair(dkim.AlgorithmRSA)
+	require.NoError(t, err)
+	records := map[string][]string{
+		dkim.RecordName("kannon", "test.com"): {dkim.RecordValue(dkim.AlgorithmRSA, pair.PublicKey)},
+	}
+
+	certPEM, keyPEM := tests.SMIMECertificate(t, "noreply@test.com")
+	creds, err := smime.ImportCredentials(certPEM, keyPEM, time.Now())
+	require.NoError(t, err)
+	signer, err := creds.Signer()
+	require.NoError(t, err)
+
+	src := stubSource{data: envelope.SendingData{
+		Subject:     "Hello",
+		HTML:        "<html><body>secret-offer</body></html>",
+		Domain:      "test.com",
+		MessageID:   "msg-1@test.com",
+		SenderEmail: "noreply@test.com",
+		SenderAlias: "Test",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: pair.PrivateKey}},
+		SMIME:       signer,
+	}}
+	b := envelope.NewBuilderWith(src, stubTokens{link: "ltok", open: "otok"})
+
+	rcptPEM, _ := tests.SMIMECertificate(t, "rcpt@example.com")
+	rcpt, err := smime.ParseRecipientCertificate(rcptPEM, time.Now())
+	require.NoError(t, err)
+	encrypted, err := delivery.New(delivery.NewParams{
+		BatchID:          batch.ID(testBatchID),
+		Email:            "rcpt@example.com",
+		Domain:           "test.com",
+		ScheduledTime:    time.Now(),
+		SMIMECertificate: rcpt.Raw,
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		d           *delivery.Delivery
+		contentType string
+	}{
+		"Signed":             {mustDelivery(t, "rcpt@example.com", nil), "multipart/signed"},
+		"SignedAndEncrypted": {encrypted, "application/pkcs7-mime"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			env, err := b.Build(t.Context(), tc.d)
+			require.NoError(t, err)
+
+			parsed, err := mail.ReadMessage(bytes.NewReader(env.Body()))
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(parsed.Header.Get("Content-Type"), tc.contentType), parsed.Header.Get("Content-Type"))
+			assert.Equal(t, "Hello", parsed.Header.Get("Subject"), "the transport headers stay outside")
+
+			verifications, err := msgauth.VerifyWithOptions(bytes.NewReader(env.Body()), &msgauth.VerifyOptions{
+				LookupTXT: func(name string) ([]string, error) { return records[name], nil },
+			})
+			require.NoError(t, err)
+			require.Len(t, verifications, 1)
+			assert.NoError(t, verifications[0].Err)
+		})
+	}
+
+	env, err := b.Build(t.Context(), encrypted)
+	require.NoError(t, err)
+	assert.NotContains(t, string(env.Body()), "secret-offer", "an encrypted body is not readable in transit")
+}
+
+// urlTokens records the URL every link token is minted for.
+type urlTokens struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+func (u *urlTokens) CreateLinkToken(ctx context.Context, messageID, email, url string, mode tracking.Mode) (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.urls = append(u.urls, url)
+	return "ltok", nil
+}
+
+func (u *urlTokens) CreateOpenToken(ctx context.Context, messageID, email string, mode tracking.Mode) (string, error) {
+	return "otok", nil
+}
+
+// Links are decorated before their tokens are minted, so the Tracker redirects
+// to the decorated URL; the links tracking leaves alone, decoration leaves
+// alone too.
+func TestBuilderDecoratesLinksBeforeMintingTokens(t *testing.T) {
+	data := envelope.SendingData{
+		Subject: "Hello",
+		HTML: `<html><body>` +
+			`<a href="https://shop.test.com/p">shop</a>` +
+			`<a href="https://shop.test.com/q?utm_source=partner">partner</a>` +
+			`<a href="https://pay.test.com/checkout">pay</a>` +
+			`<a href="https://shop.test.com/prefs" data-no-track>prefs</a>` +
+			`<a href="mailto:help@test.com">mail</a>` +
+			`<a href="#top">top</a>` +
+			`</body></html>`,
+		Domain:      "test.com",
+		MessageID:   "msg-1@test.com",
+		SenderEmail: "noreply@test.com",
+		DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: newDKIMKeys(t)}},
+		LinkDecoration: linkparams.Decoration{
+			Params: linkparams.Params{"utm_source": "kannon", "utm_term": "{{ segment }}"},
+			Hosts:  linkparams.Hosts{Deny: []string{"pay.test.com"}},
+		},
+	}
+	tokens := &urlTokens{}
+	b := envelope.NewBuilderWith(stubSource{data: data}, tokens)
+
+	_, err := b.Build(t.Context(), mustDelivery(t, "rcpt@example.com", map[string]string{"segment": "vip"}))
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"https://shop.test.com/p?utm_source=kannon&utm_term=vip",
+		"https://shop.test.com/q?utm_source=partner&utm_term=vip",
+		"https://pay.test.com/checkout",
+	}, tokens.urls)
+
+	// Under a links Mode of Off nothing is minted, and the delivered href is
+	// the decorated one.
+	env, err := b.Build(t.Context(), mustDeliveryTracked(t, batch.ID(testBatchID), "rcpt@example.com", map[string]string{"segment": "vip"},
+		tracking.Policy{Opens: tracking.ModeOff, Links: tracking.ModeOff}))
+	require.NoError(t, err)
+	parsed, err := mail.ReadMessage(bytes.NewReader(env.Body()))
+	require.NoError(t, err)
+	raw, err := io.ReadAll(parsed.Body)
+	require.NoError(t, err)
+	body, err := decodeQuotedPrintable(raw)
+	require.NoError(t, err)
+	assert.Contains(t, body, `href="https://shop.test.com/p?utm_source=kannon&utm_term=vip"`)
+	assert.Contains(t, body, `href="https://shop.test.com/prefs"`)
+	assert.Contains(t, body, `href="mailto:help@test.com"`)
+}
@@ -7,13 +7,31 @@ package envelope
 
 // Envelope is the per-recipient outgoing email: the signed RFC 2822 body
 // plus the addressing metadata needed by the SMTPSender.
+//
+// The body is held one of two ways. A built Envelope has it whole, in Body.
+// One whose body was too large to publish inline (internal/bodystore) has it
+// as Parts instead, and Body is empty: the bytes that are this recipient's
+// own, and references to the MIME parts the rest of its Batch shares.
 type Envelope struct {
 	emailID     string
 	from        string
 	to          string
 	returnPath  string
 	body        []byte
+	parts       []Part
 	shouldRetry bool
+	requireTLS  bool
+	ipPool      string
+	size        int64
+	smtpUTF8    bool
+}
+
+// Part is one piece of an Envelope's body: bytes carried with the Envelope,
+// or Ref, the name of an object in the body store holding them. Exactly one
+// of the two is set.
+type Part struct {
+	Inline []byte
+	Ref    string
 }
 
 // Params groups the fields needed to construct an Envelope.
@@ -23,18 +41,42 @@ type Params struct {
 	To          string
 	ReturnPath  string
 	Body        []byte
+	Parts       []Part
 	ShouldRetry bool
+	// RequireTLS has the SMTPSender refuse to send the Envelope without
+	// authenticated TLS: its Domain or its Batch asked for it.
+	RequireTLS bool
+	// IPPool names the IP pool the Envelope is sent from: its Batch's, or its
+	// Domain's when the Batch names none. Empty for the default pool.
+	IPPool string
+	// Size is the body's length in bytes, which the SMTPSender declares with
+	// SIZE and checks against the receiver's limit before sending any of it.
+	// The length of Body when zero.
+	Size int64
+	// SMTPUTF8 is set when the header carries UTF-8 the Builder could not
+	// encode, an internationalised address's local part: only a receiver that
+	// offers SMTPUTF8 may be given the message (RFC 6532).
+	SMTPUTF8 bool
 }
 
 // New builds an Envelope from the given fields.
 func New(p Params) *Envelope {
+	size := p.Size
+	if size == 0 {
+		size = int64(len(p.Body))
+	}
 	return &Envelope{
 		emailID:     p.EmailID,
 		from:        p.From,
 		to:          p.To,
 		returnPath:  p.ReturnPath,
 		body:        p.Body,
+		parts:       p.Parts,
 		shouldRetry: p.ShouldRetry,
+		requireTLS:  p.RequireTLS,
+		ipPool:      p.IPPool,
+		size:        size,
+		smtpUTF8:    p.SMTPUTF8,
 	}
 }
 
@@ -45,4 +87,9 @@ func (e *Envelope) From() string       { return e.from }
 func (e *Envelope) To() string         { return e.to }
 func (e *Envelope) ReturnPath() string { return e.returnPath }
 func (e *Envelope) Body() []byte       { return e.body }
+func (e *Envelope) Parts() []Part      { return e.parts }
 func (e *Envelope) ShouldRetry() bool  { return e.shouldRetry }
+func (e *Envelope) RequireTLS() bool   { return e.requireTLS }
+func (e *Envelope) IPPool() string     { return e.ipPool }
+func (e *Envelope) Size() int64        { return e.size }
+func (e *Envelope) SMTPUTF8() bool     { return e.smtpUTF8 }
@@ -6,12 +6,21 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
+	"mime/quotedprintable"
+	netmail "net/mail"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/emersion/go-message/mail"
+	"github.com/emersion/go-message/textproto"
 	"github.com/kannon-email/kannon/internal/batch"
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/utils"
+	"golang.org/x/net/idna"
 )
 
 type headers map[string][]string
@@ -24,8 +33,54 @@ const (
 	headerListUnsubscribePost = "List-Unsubscribe-Post"
 )
 
-// Attachments maps an attachment filename to a reader producing its bytes.
-type Attachments map[string]io.Reader
+// The feedback-loop pair (internal/feedback). Feedback-ID is Gmail's and has no
+// RFC; List-Id is RFC 2919's.
+const (
+	headerFeedbackID = "Feedback-ID"
+	headerListID     = "List-Id"
+)
+
+// attachmentPart is one attachment as it goes on the wire: its part header and
+// its body, already base64-encoded. A Batch's attachments are the same bytes in
+// every one of its messages, so they are encoded once per Batch and copied into
+// each message rather than encoded again for every Delivery.
+type attachmentPart struct {
+	header textproto.Header
+	body   []byte
+}
+
+// base64LineLen is the longest line of an encoded attachment, RFC 2045 §6.8's
+// limit and the one go-message wraps at.
+const base64LineLen = 76
+
+// encodeAttachments encodes a Batch's attachments in filename order, so every
+// message of the Batch lays them out the same way.
+func encodeAttachments(atts map[string][]byte) []attachmentPart {
+	names := make([]string, 0, len(atts))
+	for name := range atts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]attachmentPart, 0, len(names))
+	for _, name := range names {
+		var ah mail.AttachmentHeader
+		ah.SetFilename(name)
+		ah.Set("Content-Transfer-Encoding", "base64")
+
+		encoded := base64.StdEncoding.EncodeToString(atts[name])
+		var body bytes.Buffer
+		body.Grow(len(encoded) + len(encoded)/base64LineLen*2)
+		for len(encoded) > base64LineLen {
+			body.WriteString(encoded[:base64LineLen])
+			body.WriteString("\r\n")
+			encoded = encoded[base64LineLen:]
+		}
+		body.WriteString(encoded)
+		parts = append(parts, attachmentPart{header: ah.Header.Header, body: body.Bytes()})
+	}
+	return parts
+}
 
 func buildEmailID(to, messageID string) string {
 	emailBase64 := base64.URLEncoding.EncodeToString([]byte(to))
@@ -34,9 +89,37 @@ func buildEmailID(to, messageID string) string {
 
 // The "bump_" prefix is the wire-format token interpreted by the Tracker
 // when parsing return-path bounces; renaming it would be wire-breaking.
-func buildReturnPath(to, messageID string) string {
+//
+// With no return-path domain the message ID is the address's tail, so bounces
+// go to the sending Domain itself. With one, the address moves to that domain
+// and the message ID's own "@" becomes "=" — the Domain it names is what
+// ParseBounceReturnPath attributes the bounce to, and the return-path domain
+// says nothing about it.
+//
+// A non-empty tag is internal/returnpath's signature, written after the email
+// with a '.', which the URL-safe alphabet cannot produce.
+func buildReturnPath(to, messageID, returnPathDomain, tag string) string {
 	emailBase64 := base64.URLEncoding.EncodeToString([]byte(to))
-	return fmt.Sprintf("bump_%v+%v", emailBase64, messageID)
+	if tag != "" {
+		emailBase64 += "." + tag
+	}
+	if returnPathDomain == "" {
+		return fmt.Sprintf("bump_%v+%v", emailBase64, messageID)
+	}
+	return fmt.Sprintf("bump_%v+%v@%v", emailBase64, strings.Replace(messageID, "@", "=", 1), returnPathDomain)
+}
+
+// addFeedbackHeaders writes the Feedback Identity resolved for a Batch into h.
+// Each header is written only when the Identity has what it needs, so a Domain
+// that states nothing sends exactly what it sent before the headers existed.
+// The values were validated when they were stated, and are written as they are.
+func addFeedbackHeaders(h headers, domain string, id feedback.Identity) {
+	if v := id.FeedbackID(domain); v != "" {
+		h[headerFeedbackID] = []string{v}
+	}
+	if v := id.ListIDHeader(); v != "" {
+		h[headerListID] = []string{v}
+	}
 }
 
 func buildHeaders(subject string, sender batch.Sender, to, poolMessageID, messageID string, baseHeaders headers, customHeaders batch.Headers, unsubscribeURL string) headers {
@@ -69,10 +152,10 @@ func buildHeaders(subject string, sender batch.Sender, to, poolMessageID, messag
 	return h
 }
 
-func renderMsg(html string, hdrs headers, attachments Attachments) ([]byte, error) {
+func renderMsg(html string, hdrs headers, attachments []attachmentPart) ([]byte, error) {
 	var h mail.Header
 	for key, values := range hdrs {
-		h.Set(key, strings.Join(values, ", "))
+		h.Set(key, encodeHeader(key, strings.Join(values, ", ")))
 	}
 	h.SetDate(time.Now())
 
@@ -84,9 +167,90 @@ func renderMsg(html string, hdrs headers, attachments Attachments) ([]byte, erro
 	return buf.Bytes(), nil
 }
 
-func writeMessage(buf *bytes.Buffer, h mail.Header, html string, attachments Attachments) error {
+// addressHeaders are the headers encodeHeader reads as address lists.
+var addressHeaders = map[string]bool{"From": true, "Reply-To": true, "To": true, "Cc": true}
+
+// encodeHeader writes a header value that is not ASCII so that a receiver
+// without SMTPUTF8 can take it: a Subject as RFC 2047 encoded-words, and the
+// addresses of an address header with their display names encoded and their
+// domains as A-labels. An ASCII value is written as it is, and so is what
+// cannot be encoded — a UTF-8 local part, a header of another kind — which
+// leaves the message needing SMTPUTF8 (RFC 6532).
+func encodeHeader(key, value string) string {
+	if utils.IsASCII(value) {
+		return value
+	}
+	if key == "Subject" {
+		return mime.QEncoding.Encode("utf-8", value)
+	}
+	if !addressHeaders[key] {
+		return value
+	}
+	list, err := netmail.ParseAddressList(value)
+	if err != nil {
+		return value
+	}
+	out := make([]string, len(list))
+	for i, a := range list {
+		if at := strings.LastIndexByte(a.Address, '@'); at >= 0 {
+			if d, err := idna.Lookup.ToASCII(a.Address[at+1:]); err == nil {
+				a.Address = a.Address[:at+1] + d
+			}
+		}
+		out[i] = a.String()
+	}
+	return strings.Join(out, ", ")
+}
+
+// headerNeedsSMTPUTF8 reports whether msg's header section is not ASCII: what
+// encodeHeader could not encode is there as UTF-8, and only a receiver that
+// offers SMTPUTF8 may be given it.
+func headerNeedsSMTPUTF8(msg []byte) bool {
+	end := bytes.Index(msg, []byte("\r\n\r\n"))
+	if end < 0 {
+		end = len(msg)
+	}
+	return !utils.IsASCII(string(msg[:end]))
+}
+
+// maxLineLen is the longest line RFC 5322 §2.1.1 allows, CRLF excluded.
+const maxLineLen = 998
+
+// bodyEncoding is the Content-Transfer-Encoding the html body is written in:
+// 7bit when it is ASCII in lines a receiver must accept, and quoted-printable
+// otherwise. A body declared 7bit that is not would need BODY=8BITMIME, which
+// not every receiver offers; quoted-printable needs nothing and keeps text
+// that is mostly ASCII readable.
+func bodyEncoding(html string) string {
+	if !utils.IsASCII(html) {
+		return "quoted-printable"
+	}
+	for line := range strings.SplitSeq(html, "\n") {
+		if len(strings.TrimSuffix(line, "\r")) > maxLineLen {
+			return "quoted-printable"
+		}
+	}
+	return "7bit"
+}
+
+// writeBody writes html to w in enc, as bodyEncoding chose it.
+func writeBody(w io.Writer, enc, html string) error {
+	if enc != "quoted-printable" {
+		_, err := io.WriteString(w, html)
+		return err
+	}
+	qw := quotedprintable.NewWriter(w)
+	if _, err := io.WriteString(qw, html); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+func writeMessage(buf *bytes.Buffer, h mail.Header, html string, attachments []attachmentPart) error {
+	enc := bodyEncoding(html)
 	if len(attachments) == 0 {
 		h.Set("Content-Type", "text/html; charset=utf-8")
+		h.Set("Content-Transfer-Encoding", enc)
 		w, err := mail.CreateSingleInlineWriter(buf, h)
 		if err != nil {
 			return err
@@ -97,35 +261,35 @@ func writeMessage(buf *bytes.Buffer, h mail.Header, html string, attachments Att
 		return w.Close()
 	}
 
-	mw, err := mail.CreateWriter(buf, h)
-	if err != nil {
+	// The multipart/mixed message go-message's mail.Writer would write, put
+	// together by hand so that the attachments can go in already encoded.
+	mw := textproto.NewMultipartWriter(buf)
+	h.SetContentType("multipart/mixed", map[string]string{"boundary": mw.Boundary()})
+	h.Set("MIME-Version", "1.0")
+	if err := textproto.WriteHeader(buf, h.Header.Header); err != nil {
 		return err
 	}
 
 	var ih mail.InlineHeader
 	ih.SetContentType("text/html", map[string]string{"charset": "utf-8"})
-	bw, err := mw.CreateSingleInline(ih)
+	ih.Set("Content-Disposition", "inline")
+	ih.Set("Content-Transfer-Encoding", enc)
+	pw, err := mw.CreatePart(ih.Header.Header)
 	if err != nil {
 		return err
 	}
-	if _, err := io.WriteString(bw, html); err != nil {
-		return err
-	}
-	if err := bw.Close(); err != nil {
+	if err := writeBody(pw, enc, html); err != nil {
 		return err
 	}
 
-	for name, r := range attachments {
-		var ah mail.AttachmentHeader
-		ah.SetFilename(name)
-		aw, err := mw.CreateAttachment(ah)
+	for _, a := range attachments {
+		// Writing a header may cache its rendering in it, and the part is
+		// shared by every worker building the Batch.
+		aw, err := mw.CreatePart(a.header.Copy())
 		if err != nil {
 			return err
 		}
-		if _, err := io.Copy(aw, r); err != nil {
-			return err
-		}
-		if err := aw.Close(); err != nil {
+		if _, err := aw.Write(a.body); err != nil {
 			return err
 		}
 	}
@@ -226,6 +390,21 @@ func rewriteATag(tag string, replace func(link string) (string, error)) (string,
 	return tag[:href[2]] + newLink + tag[href[3]:], nil
 }
 
+// decorateLinks appends the Link Decoration's parameters to every href that
+// replaceLinks would mint a token for, and to no other: a link that opts out
+// with data-no-track is one the sender wants delivered exactly as written, and
+// a mailto: link or an anchor has no query a landing page could read.
+func decorateLinks(html string, d linkparams.Decoration, fields map[string]string) string {
+	if len(d.Params) == 0 {
+		return html
+	}
+	// replace never fails here, and neither can replaceLinks then.
+	out, _ := replaceLinks(html, func(link string) (string, error) {
+		return d.Apply(link, fields), nil
+	})
+	return out
+}
+
 // stripNoTrackAttrs removes the opt-out attribute from every <a> tag in the
 // HTML. The attribute is an instruction to Kannon rather than content, so it is
 // dropped whatever the Tracking Policy says — including under a links Mode that
@@ -17,6 +17,7 @@ import (
 	"github.com/kannon-email/kannon/internal/batch"
 	"github.com/kannon-email/kannon/internal/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBuildHeaders(t *testing.T) {
@@ -228,9 +229,9 @@ func TestRenderMsgPreservesHeadersAndBody(t *testing.T) {
 
 func TestRenderMsgWithSingleAttachment(t *testing.T) {
 	html := `<html><body>hi</body></html>`
-	atts := Attachments{
-		"file.txt": strings.NewReader("hello world"),
-	}
+	atts := encodeAttachments(map[string][]byte{
+		"file.txt": []byte("hello world"),
+	})
 	out, err := renderMsg(html, sampleHeaders(), atts)
 	assert.Nil(t, err)
 
@@ -281,10 +282,10 @@ func TestRenderMsgWithSingleAttachment(t *testing.T) {
 
 func TestRenderMsgWithMultipleAttachments(t *testing.T) {
 	html := `<html><body>hi</body></html>`
-	atts := Attachments{
-		"a.txt": strings.NewReader("first"),
-		"b.bin": strings.NewReader("second"),
-	}
+	atts := encodeAttachments(map[string][]byte{
+		"a.txt": []byte("first"),
+		"b.bin": []byte("second"),
+	})
 	out, err := renderMsg(html, sampleHeaders(), atts)
 	assert.Nil(t, err)
 
@@ -319,6 +320,22 @@ func TestRenderMsgWithMultipleAttachments(t *testing.T) {
 	assert.Equal(t, []byte("second"), got["b.bin"])
 }
 
+// An attachment is encoded once per Batch, by hand rather than by go-message, so
+// it must come out the way go-message would have written it: lines no longer
+// than RFC 2045 allows, decoding to the bytes that went in.
+func TestEncodeAttachmentsWrapsLikeGoMessage(t *testing.T) {
+	raw := bytes.Repeat([]byte("0123456789"), 100)
+	parts := encodeAttachments(map[string][]byte{"b.bin": raw, "a.txt": []byte("x")})
+
+	require.Len(t, parts, 2)
+	assert.Equal(t, "attachment; filename=a.txt", parts[0].header.Get("Content-Disposition"),
+		"attachments are laid out in filename order")
+	for _, line := range strings.Split(string(parts[1].body), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+	assert.Equal(t, raw, decodePartBody(t, "base64", parts[1].body))
+}
+
 // appendX is a stand-in for the click-redirect rewriter: it makes a rewritten
 // link recognisable without pulling a token issuer into the test.
 func appendX(link string) (string, error) {
@@ -684,7 +701,7 @@ func TestBuildReturnPathRoundTripsThroughParseBounceReturnPath(t *testing.T) {
 
 	for _, want := range emails {
 		t.Run(want, func(t *testing.T) {
-			returnPath := buildReturnPath(want, messageID)
+			returnPath := buildReturnPath(want, messageID, "", "")
 
 			email, gotMessageID, domain, found, err := utils.ParseBounceReturnPath(returnPath)
 
@@ -696,3 +713,61 @@ func TestBuildReturnPathRoundTripsThroughParseBounceReturnPath(t *testing.T) {
 		})
 	}
 }
+
+// A return-path domain moves the address, not the attribution: the bounce
+// still names the Domain that sent the mail, which the return-path domain
+// deliberately does not encode.
+func TestBuildReturnPathOnAReturnPathDomain(t *testing.T) {
+	messageID := "msg_cl6g7ndft0001018ut5octeun@k.test.com"
+
+	returnPath := buildReturnPath("test@test.com", messageID, "bounces.k.test.com", "")
+	assert.Equal(t, "bump_dGVzdEB0ZXN0LmNvbQ==+msg_cl6g7ndft0001018ut5octeun=k.test.com@bounces.k.test.com", returnPath)
+
+	email, gotMessageID, domain, found, err := utils.ParseBounceReturnPath(returnPath)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "test@test.com", email)
+	assert.Equal(t, messageID, gotMessageID)
+	assert.Equal(t, "k.test.com", domain)
+}
+
+func TestBodyEncoding(t *testing.T) {
+	assert.Equal(t, "7bit", bodyEncoding("<p>hi</p>\r\n<p>bye</p>"))
+	assert.Equal(t, "quoted-printable", bodyEncoding("<p>ciao, è tardi</p>"))
+	assert.Equal(t, "quoted-printable", bodyEncoding("<p>"+strings.Repeat("a", maxLineLen)+"</p>"),
+		"a line longer than a receiver must accept")
+}
+
+// A header the Builder can encode goes out as ASCII; one it cannot — an
+// internationalised local part — leaves the message needing SMTPUTF8.
+func TestRenderMsgEncodesInternationalHeaders(t *testing.T) {
+	h := sampleHeaders()
+	h["Subject"] = []string{"Buongiorno, è pronto"}
+	h["From"] = []string{"Jörg <noreply@müller.test>"}
+	html := `<html><body>è</body></html>`
+
+	out, err := renderMsg(html, h, nil)
+	require.NoError(t, err)
+	assert.False(t, headerNeedsSMTPUTF8(out))
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(out))
+	require.NoError(t, err)
+	var dec mime.WordDecoder
+	subject, err := dec.DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Buongiorno, è pronto", subject)
+	from, err := parsed.Header.AddressList("From")
+	require.NoError(t, err)
+	assert.Equal(t, "Jörg", from[0].Name)
+	assert.Equal(t, "noreply@xn--mller-kva.test", from[0].Address)
+	assert.Equal(t, "quoted-printable", parsed.Header.Get("Content-Transfer-Encoding"))
+	body, err := io.ReadAll(parsed.Body)
+	require.NoError(t, err)
+	assert.Equal(t, html, string(decodePartBody(t, "quoted-printable", body)))
+
+	h["To"] = []string{"用户@例子.中国"}
+	out, err = renderMsg(html, h, nil)
+	require.NoError(t, err)
+	assert.True(t, headerNeedsSMTPUTF8(out))
+	assert.Contains(t, string(out), "To: <用户@xn--fsqu00a.xn--fiqs8s>")
+}
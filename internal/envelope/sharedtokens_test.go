@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/kannon-email/kannon/internal/batch"
 	"github.com/kannon-email/kannon/internal/tracking"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -76,3 +77,17 @@ func TestSharedTokensDoNotCacheAFailedMint(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "tok", token)
 }
+
+// The same leak test for the preparation cache.
+func TestPreparedBatchesAreBounded(t *testing.T) {
+	c := newPreparedBatches()
+
+	for i := range 10 * preparedBatchGeneration {
+		p := &preparedBatch{batchID: batch.ID(fmt.Sprintf("msg-%d@test.com", i)), version: "v1"}
+		c.put(p)
+		require.Same(t, p, c.get(p.batchID, "v1"))
+		require.Nil(t, c.get(p.batchID, "v2"), "a preparation of another version is not served")
+		require.LessOrEqual(t, c.len(), 2*preparedBatchGeneration,
+			"the cache must never hold more than two generations")
+	}
+}
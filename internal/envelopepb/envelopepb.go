@@ -48,10 +48,30 @@ func FromEnvelope(env *envelope.Envelope) *pb.EmailToSend {
 		To:          env.To(),
 		ReturnPath:  env.ReturnPath(),
 		Body:        env.Body(),
+		BodyParts:   fromParts(env.Parts()),
 		ShouldRetry: env.ShouldRetry(),
+		RequireTls:  env.RequireTLS(),
+		IpPool:      env.IPPool(),
+		Size:        env.Size(),
+		Smtputf8:    env.SMTPUTF8(),
 	}
 }
 
+func fromParts(parts []envelope.Part) []*pb.BodyPart {
+	if len(parts) == 0 {
+		return nil
+	}
+	out := make([]*pb.BodyPart, len(parts))
+	for i, p := range parts {
+		if p.Ref != "" {
+			out[i] = &pb.BodyPart{Part: &pb.BodyPart_Ref{Ref: p.Ref}}
+		} else {
+			out[i] = &pb.BodyPart{Part: &pb.BodyPart_Inline{Inline: p.Inline}}
+		}
+	}
+	return out
+}
+
 // ToEnvelope reads a published EmailToSend back into the domain, which is what
 // the SMTPSender does with every message it takes off the sending stream.
 //
@@ -68,6 +88,22 @@ func ToEnvelope(m *pb.EmailToSend) *envelope.Envelope {
 		To:          m.GetTo(),
 		ReturnPath:  m.GetReturnPath(),
 		Body:        m.GetBody(),
+		Parts:       toParts(m.GetBodyParts()),
 		ShouldRetry: m.GetShouldRetry(),
+		RequireTLS:  m.GetRequireTls(),
+		IPPool:      m.GetIpPool(),
+		Size:        m.GetSize(),
+		SMTPUTF8:    m.GetSmtputf8(),
 	})
 }
+
+func toParts(parts []*pb.BodyPart) []envelope.Part {
+	if len(parts) == 0 {
+		return nil
+	}
+	out := make([]envelope.Part, len(parts))
+	for i, p := range parts {
+		out[i] = envelope.Part{Inline: p.GetInline(), Ref: p.GetRef()}
+	}
+	return out
+}
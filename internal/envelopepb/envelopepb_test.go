@@ -20,6 +20,9 @@ func oneEnvelope() *envelope.Envelope {
 		ReturnPath:  "rp",
 		Body:        []byte("body"),
 		ShouldRetry: true,
+		RequireTLS:  true,
+		IPPool:      "bulk",
+		SMTPUTF8:    true,
 	})
 }
 
@@ -37,6 +40,10 @@ func TestFromEnvelope(t *testing.T) {
 	assert.Equal(t, "rp", msg.ReturnPath)
 	assert.Equal(t, []byte("body"), msg.Body)
 	assert.True(t, msg.ShouldRetry)
+	assert.True(t, msg.RequireTls)
+	assert.Equal(t, "bulk", msg.IpPool)
+	assert.Equal(t, int64(4), msg.Size, "the length of the body")
+	assert.True(t, msg.Smtputf8)
 }
 
 // TestToEnvelope pins the read side, which is the one that decides where a real
@@ -50,6 +57,10 @@ func TestToEnvelope(t *testing.T) {
 		ReturnPath:  "rp",
 		Body:        []byte("body"),
 		ShouldRetry: true,
+		RequireTls:  true,
+		IpPool:      "bulk",
+		Size:        1 << 20,
+		Smtputf8:    true,
 	})
 
 	assert.Equal(t, "id", env.EmailID())
@@ -58,6 +69,10 @@ func TestToEnvelope(t *testing.T) {
 	assert.Equal(t, "rp", env.ReturnPath())
 	assert.Equal(t, []byte("body"), env.Body())
 	assert.True(t, env.ShouldRetry())
+	assert.True(t, env.RequireTLS())
+	assert.Equal(t, "bulk", env.IPPool())
+	assert.Equal(t, int64(1<<20), env.Size(), "an offloaded body's size is carried, not measured")
+	assert.True(t, env.SMTPUTF8())
 }
 
 // TestEnvelopeRoundTrip pins that the Envelope the SMTPSender transmits is the
@@ -75,3 +90,23 @@ func TestEnvelopeRoundTrip(t *testing.T) {
 func TestToEnvelopeOfNil(t *testing.T) {
 	assert.Equal(t, envelope.New(envelope.Params{}), envelopepb.ToEnvelope(nil))
 }
+
+// TestPartsRoundTrip pins the out-of-band form: the order of the parts is the
+// order of the bytes, and an inline part and a reference must each come back
+// as what they were.
+func TestPartsRoundTrip(t *testing.T) {
+	env := envelope.New(envelope.Params{
+		EmailID: "id",
+		To:      "t@x",
+		Parts: []envelope.Part{
+			{Inline: []byte("head")},
+			{Ref: "sha256-00"},
+			{Inline: []byte("tail")},
+		},
+	})
+
+	msg := envelopepb.FromEnvelope(env)
+	assert.Empty(t, msg.Body)
+	assert.Equal(t, "sha256-00", msg.BodyParts[1].GetRef())
+	assert.Equal(t, env, envelopepb.ToEnvelope(msg))
+}
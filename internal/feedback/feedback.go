@@ -0,0 +1,114 @@
+// Package feedback holds the Feedback Identity: what a Domain, or a Batch overriding it, states
+// about the two headers mailbox providers key their complaint feedback on.
+//
+//   - Feedback-ID is Gmail's (and, following it, other providers'): a colon-separated list of
+//     identifiers whose last one names the sender, under which Postmaster Tools aggregates spam
+//     complaints. Kannon writes "<campaign>:<domain>:<sender ID>", the campaign omitted when none
+//     is stated. The sender ID is last because that is the one position the format fixes; the
+//     Domain is the sending Domain's own name and is not stated anywhere, so it cannot disagree
+//     with the mail it is on.
+//   - List-Id (RFC 2919) names the list a message belongs to, which is what a provider's "report
+//     spam" groups a sender's traffic by, and what a recipient's client filters on.
+//
+// Both are signed: a header that changes how complaints are attributed is one an intermediary must
+// not be able to add or rewrite.
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidIdentity is a Feedback Identity carrying a character its header cannot, or one too
+// long to be an identifier.
+var ErrInvalidIdentity = errors.New("invalid feedback identity")
+
+// maxPartLen bounds each Feedback-ID identifier. The format sets no limit; providers truncate, and
+// an identifier that gets truncated stops aggregating with the ones that did not.
+const maxPartLen = 64
+
+// maxListIDLen is RFC 2919's limit on the list-id.
+const maxListIDLen = 255
+
+// feedbackPartPattern is the set a Feedback-ID identifier may use. Narrower than what a header
+// could carry: the colon is the separator, and anything beyond letters, digits, '.', '_' and '-'
+// is one a provider's report may render differently from how it was sent.
+var feedbackPartPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// listIDPattern is RFC 2919's list-id without its angle brackets: a list-label and a namespace,
+// both dot-atoms, so at least one dot.
+var listIDPattern = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+/=?^_`{|}~-]+(\\.[A-Za-z0-9!#$%&'*+/=?^_`{|}~-]+)+$")
+
+// Identity is a Feedback Identity. Every field is optional, and the zero Identity states nothing,
+// so no header is written for it.
+type Identity struct {
+	// SenderID is the last identifier of Feedback-ID, naming the sender to the provider. Without
+	// it no Feedback-ID is written, since one without a sender attributes complaints to nobody.
+	SenderID string
+	// Campaign is the first identifier of Feedback-ID, the one a sender tells its own traffic
+	// apart by.
+	Campaign string
+	// ListID is the List-Id, e.g. "newsletter.example.com", written in angle brackets.
+	ListID string
+}
+
+// IsZero reports whether the Identity states nothing at all.
+func (i Identity) IsZero() bool { return i == Identity{} }
+
+// Validate refuses an Identity that cannot be written as stated. Checked where it is stated —
+// the Admin API for a Domain, the Mailer API for a Batch — rather than when the header is
+// rendered, where the only option left would be to drop it.
+func (i Identity) Validate() error {
+	for name, part := range map[string]string{"sender ID": i.SenderID, "campaign": i.Campaign} {
+		if part == "" {
+			continue
+		}
+		if len(part) > maxPartLen || !feedbackPartPattern.MatchString(part) {
+			return fmt.Errorf("%w: %s %q must be at most %d letters, digits, '.', '_' or '-'", ErrInvalidIdentity, name, part, maxPartLen)
+		}
+	}
+	if i.ListID != "" && (len(i.ListID) > maxListIDLen || !listIDPattern.MatchString(i.ListID)) {
+		return fmt.Errorf("%w: list ID %q must be a dot-atom such as newsletter.example.com", ErrInvalidIdentity, i.ListID)
+	}
+	return nil
+}
+
+// Over returns the Identity with every field i states in place of base's: a Batch's statement
+// over its Domain's. Field by field, so a Batch can name its own campaign and keep the Domain's
+// sender ID.
+func (i Identity) Over(base Identity) Identity {
+	if i.SenderID != "" {
+		base.SenderID = i.SenderID
+	}
+	if i.Campaign != "" {
+		base.Campaign = i.Campaign
+	}
+	if i.ListID != "" {
+		base.ListID = i.ListID
+	}
+	return base
+}
+
+// FeedbackID renders the Feedback-ID header of mail the Domain named domain sends, or "" when the
+// Identity names no sender.
+func (i Identity) FeedbackID(domain string) string {
+	if i.SenderID == "" {
+		return ""
+	}
+	parts := make([]string, 0, 3)
+	if i.Campaign != "" {
+		parts = append(parts, i.Campaign)
+	}
+	parts = append(parts, domain, i.SenderID)
+	return strings.Join(parts, ":")
+}
+
+// ListIDHeader renders the List-Id header, or "" when the Identity names no list.
+func (i Identity) ListIDHeader() string {
+	if i.ListID == "" {
+		return ""
+	}
+	return "<" + i.ListID + ">"
+}
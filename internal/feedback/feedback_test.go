@@ -0,0 +1,53 @@
+package feedback_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedbackID(t *testing.T) {
+	assert.Equal(t, "", feedback.Identity{Campaign: "spring"}.FeedbackID("example.com"),
+		"no sender ID, no header")
+	assert.Equal(t, "example.com:acme", feedback.Identity{SenderID: "acme"}.FeedbackID("example.com"))
+	assert.Equal(t, "spring:example.com:acme", feedback.Identity{SenderID: "acme", Campaign: "spring"}.FeedbackID("example.com"))
+}
+
+func TestListIDHeader(t *testing.T) {
+	assert.Equal(t, "", feedback.Identity{}.ListIDHeader())
+	assert.Equal(t, "<news.example.com>", feedback.Identity{ListID: "news.example.com"}.ListIDHeader())
+}
+
+func TestBatchOverridesItsDomainFieldByField(t *testing.T) {
+	domain := feedback.Identity{SenderID: "acme", Campaign: "default", ListID: "all.example.com"}
+	batch := feedback.Identity{Campaign: "spring"}
+
+	assert.Equal(t, feedback.Identity{SenderID: "acme", Campaign: "spring", ListID: "all.example.com"}, batch.Over(domain))
+	assert.Equal(t, domain, feedback.Identity{}.Over(domain))
+}
+
+func TestValidate(t *testing.T) {
+	valid := []feedback.Identity{
+		{},
+		{SenderID: "acme", Campaign: "spring_2026-v1.2", ListID: "news.example.com"},
+		{ListID: "a+b.localhost"},
+	}
+	for _, id := range valid {
+		assert.NoError(t, id.Validate(), "%+v", id)
+	}
+
+	invalid := []feedback.Identity{
+		{SenderID: "ac:me"},
+		{Campaign: "spring sale"},
+		{Campaign: "sprïng"},
+		{SenderID: strings.Repeat("a", 65)},
+		{ListID: "news"},
+		{ListID: "news.example.com>\r\nBcc: x@y"},
+		{ListID: "<news.example.com>"},
+	}
+	for _, id := range invalid {
+		assert.ErrorIs(t, id.Validate(), feedback.ErrInvalidIdentity, "%+v", id)
+	}
+}
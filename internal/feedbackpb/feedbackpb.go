@@ -0,0 +1,33 @@
+// Package feedbackpb translates Feedback Identities between the wire message and
+// the internal/feedback domain type, for the Admin API that states a Domain's
+// and the Mailer API that states a Batch's. Like trackingpb, it keeps
+// internal/feedback free of any protobuf dependency.
+package feedbackpb
+
+import (
+	"github.com/kannon-email/kannon/internal/feedback"
+	pb "github.com/kannon-email/kannon/proto/kannon/feedback/types"
+)
+
+// ToIdentity translates an Identity stated on the wire. A nil message states
+// nothing. It does not validate: that is the domain type's to do, at the point
+// where the Identity is stated.
+func ToIdentity(m *pb.FeedbackIdentity) feedback.Identity {
+	if m == nil {
+		return feedback.Identity{}
+	}
+	return feedback.Identity{
+		SenderID: m.GetSenderId(),
+		Campaign: m.GetCampaign(),
+		ListID:   m.GetListId(),
+	}
+}
+
+// FromIdentity renders an Identity onto the wire.
+func FromIdentity(i feedback.Identity) *pb.FeedbackIdentity {
+	return &pb.FeedbackIdentity{
+		SenderId: i.SenderID,
+		Campaign: i.Campaign,
+		ListId:   i.ListID,
+	}
+}
@@ -0,0 +1,18 @@
+package feedbackpb_test
+
+import (
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/feedbackpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityRoundTrips(t *testing.T) {
+	id := feedback.Identity{SenderID: "acme", Campaign: "spring", ListID: "news.example.com"}
+	assert.Equal(t, id, feedbackpb.ToIdentity(feedbackpb.FromIdentity(id)))
+}
+
+func TestNilStatesNothing(t *testing.T) {
+	assert.True(t, feedbackpb.ToIdentity(nil).IsZero())
+}
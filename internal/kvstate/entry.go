@@ -0,0 +1,87 @@
+package kvstate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// updateRetries is how many times an update lost to another replica is tried
+// again before Update gives up.
+const updateRetries = 5
+
+// Get reads key's entry, as JSON, nil when there is none, and the revision it
+// is to be updated at.
+func Get[T any](ctx context.Context, kv jetstream.KeyValue, key string) (*T, uint64, error) {
+	entry, err := kv.Get(ctx, key)
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot read %s: %w", key, err)
+	}
+	var v T
+	if err := json.Unmarshal(entry.Value(), &v); err != nil {
+		return nil, 0, fmt.Errorf("cannot read %s: %w", key, err)
+	}
+	return &v, entry.Revision(), nil
+}
+
+// Update applies fn to key's entry — nil when there is none — and writes what
+// it returns when it reports a change, by compare-and-swap on the revision it
+// read: when another replica wrote first, fn is applied again to what that
+// replica wrote. It returns what fn returned last, and whether it was written.
+func Update[T any](ctx context.Context, kv jetstream.KeyValue, key string, fn func(prev *T) (T, bool)) (T, bool, error) {
+	var zero T
+	for range updateRetries {
+		prev, rev, err := Get[T](ctx, kv, key)
+		if err != nil {
+			return zero, false, err
+		}
+		next, changed := fn(prev)
+		if !changed {
+			return next, false, nil
+		}
+		value, err := json.Marshal(next)
+		if err != nil {
+			return zero, false, err
+		}
+		if prev == nil {
+			_, err = kv.Create(ctx, key, value)
+		} else {
+			_, err = kv.Update(ctx, key, value, rev)
+		}
+		if err == nil {
+			return next, true, nil
+		}
+		if !errors.Is(err, jetstream.ErrKeyExists) {
+			return zero, false, fmt.Errorf("cannot write %s: %w", key, err)
+		}
+	}
+	return zero, false, fmt.Errorf("cannot write %s: lost %d updates in a row", key, updateRetries)
+}
+
+// List reads every entry of kv. An entry forgotten between listing its key
+// and reading it is skipped.
+func List[T any](ctx context.Context, kv jetstream.KeyValue) ([]T, error) {
+	keys, err := kv.ListKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list %s: %w", kv.Bucket(), err)
+	}
+	defer func() { _ = keys.Stop() }()
+
+	var out []T
+	for k := range keys.Keys() {
+		v, _, err := Get[T](ctx, kv, k)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			out = append(out, *v)
+		}
+	}
+	return out, nil
+}
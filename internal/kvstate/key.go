@@ -0,0 +1,57 @@
+// Package kvstate is what the Sender's shared state in NATS key/value buckets
+// has in common — the throttle's counters, the cool-downs, the circuits and
+// the warm-up counters: keys that two names never share, and JSON entries
+// updated by compare-and-swap on their revision.
+package kvstate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Key names an entry by kind and the names it is about, each made a Token
+// and joined by underscores, which no token holds: two lists of names never
+// make the same key, however they are spelled.
+func Key(kind string, names ...string) string {
+	parts := make([]string, 0, len(names)+1)
+	parts = append(parts, kind)
+	for _, name := range names {
+		parts = append(parts, Token(name))
+	}
+	return strings.Join(parts, "_")
+}
+
+// Token is name as a key may hold it. A domain, an internationalized one
+// included, keeps its Punycode form, and an IPv4 address or a number is kept
+// as it is; any other name, such as a group name with a space or an IPv6
+// address, is a hash of it. A key token may hold few characters, and mapping
+// the others to one of them would have two names share an entry.
+func Token(name string) string {
+	token, err := idna.Punycode.ToASCII(name)
+	if err != nil || !safe(token) {
+		sum := sha256.Sum256([]byte(name))
+		return "h=" + hex.EncodeToString(sum[:12])
+	}
+	return token
+}
+
+// safe reports whether s is non-empty dot-separated labels of letters, digits
+// and hyphens.
+func safe(s string) bool {
+	for label := range strings.SplitSeq(s, ".") {
+		if label == "" {
+			return false
+		}
+		for _, r := range label {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
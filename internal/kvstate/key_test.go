@@ -0,0 +1,28 @@
+package kvstate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Every name keeps an entry of its own: internationalized domains of the same
+// length, which have no character a key may hold in common, are told apart by
+// their Punycode form, and a name a key cannot hold by a hash.
+func TestKeysDoNotCollide(t *testing.T) {
+	names := []string{
+		"müller.de", "möller.de", "例え.jp", "テスト.jp", "例子.中国", "中国.中国",
+		"example.com", "Google Mail", "Google_Mail", "a..b", "2001:db8::1", "2001:db8::2",
+	}
+	seen := map[string]string{}
+	for _, name := range names {
+		key := Key("rate", name, "0")
+		if prev, ok := seen[key]; ok {
+			t.Fatalf("%q and %q share key %q", prev, name, key)
+		}
+		seen[key] = name
+	}
+	assert.Equal(t, "rate_xn--mller-kva.de_0", Key("rate", "müller.de", "0"))
+	assert.Equal(t, "day_192.0.2.10_20261001", Key("day", "192.0.2.10", "20261001"))
+	assert.NotEqual(t, Key("day", "a", "b.c"), Key("day", "a.b", "c"), "names are joined by what no token holds")
+}
@@ -0,0 +1,226 @@
+// Package linkparams holds Link Decoration: the query parameters — UTM tags, in practice — that a
+// Domain, or a Batch overriding it, asks to have appended to every link of its mail, so that the
+// landing page's analytics can attribute the visit without each template spelling them out.
+//
+// Decoration happens in the Builder, after the template is personalised and before any link is
+// minted into a click-tracking token, so the Tracker redirects to the decorated URL and the token
+// records it. It is deliberately conservative about what it touches:
+//
+//   - only the links the Builder would track: an <a> tag opting out with data-no-track, a mailto:,
+//     tel: or sms: link and an in-page anchor are left exactly as written;
+//   - only http and https URLs, and only on the hosts the Domain allows;
+//   - never a parameter the URL already carries: a template that spells out its own utm_campaign
+//     for one link meant that one.
+package linkparams
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kannon-email/kannon/internal/utils"
+)
+
+// ErrInvalid is a Link Decoration that cannot be applied as stated: a parameter name outside the
+// allowed set, a value too long to belong in a URL, or a host that is not a host name.
+var ErrInvalid = errors.New("invalid link decoration")
+
+const (
+	// maxParams bounds how many parameters one statement may carry. Every one of them lands on
+	// every link of every message, so a long list is a mistake rather than a need.
+	maxParams = 20
+	// maxNameLen and maxValueLen bound one parameter. A value is measured before its
+	// placeholders are substituted; the Recipient's fields were bounded at intake.
+	maxNameLen  = 64
+	maxValueLen = 512
+	// maxHosts bounds each of the allow and deny lists.
+	maxHosts = 100
+	// maxHostLen is the longest name DNS can carry.
+	maxHostLen = 253
+)
+
+// namePattern is the set a parameter name may use: what every analytics package reads without
+// escaping, so the name on the wire is the name the sender typed.
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// hostPattern is a host name: dot-separated labels of letters, digits and '-'.
+var hostPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+// Params are the query parameters appended to a link, by name. A value may hold the same
+// `{{ field }}` placeholders a template does, resolved from the Recipient's fields.
+type Params map[string]string
+
+// Validate refuses Params that cannot be appended as stated. Checked where they are stated — the
+// Admin API for a Domain, the Mailer API for a Batch — rather than when a link is decorated, where
+// the only option left would be to drop them silently.
+//
+// An empty value is allowed: in a Batch's override it removes the Domain's parameter of that name,
+// and anywhere else it adds nothing.
+func (p Params) Validate() error {
+	if len(p) > maxParams {
+		return fmt.Errorf("%w: %d parameters, at most %d are allowed", ErrInvalid, len(p), maxParams)
+	}
+	for name, value := range p {
+		if len(name) > maxNameLen || !namePattern.MatchString(name) {
+			return fmt.Errorf("%w: parameter name %q must be at most %d letters, digits, '.', '_' or '-'", ErrInvalid, name, maxNameLen)
+		}
+		if len(value) > maxValueLen || !utf8.ValidString(value) || strings.IndexFunc(value, unicode.IsControl) >= 0 {
+			return fmt.Errorf("%w: the value of %q must be at most %d printable characters", ErrInvalid, name, maxValueLen)
+		}
+	}
+	return nil
+}
+
+// Over returns base with p's parameters laid over it: a Batch's override over its Domain's
+// defaults. A parameter p states replaces base's of the same name, and one p states with an empty
+// value removes it, so a Batch can drop a default it has no use for. Neither map is modified.
+func (p Params) Over(base Params) Params {
+	out := make(Params, len(base)+len(p))
+	for name, value := range base {
+		out[name] = value
+	}
+	for name, value := range p {
+		if value == "" {
+			delete(out, name)
+			continue
+		}
+		out[name] = value
+	}
+	return out
+}
+
+// Hosts restricts decoration by the host a link points at. An entry names a host and every
+// subdomain of it, so "example.com" covers "www.example.com" too.
+type Hosts struct {
+	// Allow lists the only hosts decorated. Empty, every host is.
+	Allow []string
+	// Deny lists hosts never decorated, even when Allow covers them: a payment provider's or a
+	// partner's links, whose URLs must reach them exactly as written.
+	Deny []string
+}
+
+// Validate refuses an entry that is not a host name.
+func (h Hosts) Validate() error {
+	for list, hosts := range map[string][]string{"allow": h.Allow, "deny": h.Deny} {
+		if len(hosts) > maxHosts {
+			return fmt.Errorf("%w: %d %s hosts, at most %d are allowed", ErrInvalid, len(hosts), list, maxHosts)
+		}
+		for _, host := range hosts {
+			if len(host) > maxHostLen || !hostPattern.MatchString(strings.ToLower(host)) {
+				return fmt.Errorf("%w: %s host %q must be a host name such as example.com", ErrInvalid, list, host)
+			}
+		}
+	}
+	return nil
+}
+
+// Covers reports whether a link to host is decorated.
+func (h Hosts) Covers(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, entry := range h.Deny {
+		if matchesHost(host, entry) {
+			return false
+		}
+	}
+	if len(h.Allow) == 0 {
+		return true
+	}
+	for _, entry := range h.Allow {
+		if matchesHost(host, entry) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesHost(host, entry string) bool {
+	entry = strings.ToLower(entry)
+	return host == entry || strings.HasSuffix(host, "."+entry)
+}
+
+// Decoration is a Link Decoration: the parameters to append and the hosts to append them on. A
+// Domain states a whole one; a Batch overrides only its Params, since which hosts may be touched
+// is the Domain's to decide. The zero Decoration decorates nothing.
+type Decoration struct {
+	Params Params
+	Hosts  Hosts
+}
+
+// IsZero reports whether the Decoration states nothing at all.
+func (d Decoration) IsZero() bool {
+	return len(d.Params) == 0 && len(d.Hosts.Allow) == 0 && len(d.Hosts.Deny) == 0
+}
+
+// Validate refuses a Decoration whose parameters or hosts cannot be applied as stated.
+func (d Decoration) Validate() error {
+	if err := d.Params.Validate(); err != nil {
+		return err
+	}
+	return d.Hosts.Validate()
+}
+
+// Apply returns link with the Decoration's parameters appended, each value personalised from
+// fields. link is an href as it stands in the HTML, and everything already in it is left as
+// written: the parameters go at the end of its query, before any fragment, in name order.
+//
+// A link Apply cannot read as an http or https URL, or whose host the Decoration does not cover,
+// is returned unchanged. So is each parameter the URL already carries, one whose value is empty,
+// and one whose value still holds a placeholder after substitution — a link with `{{ source }}` in
+// it is worse than a link without the parameter.
+func (d Decoration) Apply(link string, fields map[string]string) string {
+	if len(d.Params) == 0 {
+		return link
+	}
+
+	trimmed := strings.TrimRightFunc(link, unicode.IsSpace)
+	trailing := link[len(trimmed):]
+	// The href is still HTML-escaped here: an "&amp;" separating two parameters is one "&".
+	u, err := url.Parse(html.UnescapeString(strings.TrimSpace(trimmed)))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return link
+	}
+	if !d.Hosts.Covers(u.Hostname()) {
+		return link
+	}
+
+	present := u.Query()
+	names := make([]string, 0, len(d.Params))
+	for name := range d.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var add []string
+	for _, name := range names {
+		if _, ok := present[name]; ok {
+			continue
+		}
+		value := utils.ReplaceCustomFields(d.Params[name], fields)
+		if value == "" || utils.HasUnresolvedPlaceholders(value) {
+			continue
+		}
+		add = append(add, url.QueryEscape(name)+"="+url.QueryEscape(value))
+	}
+	if len(add) == 0 {
+		return link
+	}
+
+	base, fragment := trimmed, ""
+	if i := strings.IndexByte(trimmed, '#'); i >= 0 {
+		base, fragment = trimmed[:i], trimmed[i:]
+	}
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+		if strings.HasSuffix(base, "?") || strings.HasSuffix(base, "&") {
+			sep = ""
+		}
+	}
+	return base + sep + strings.Join(add, "&") + fragment + trailing
+}
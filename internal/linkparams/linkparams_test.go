@@ -0,0 +1,82 @@
+package linkparams_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/stretchr/testify/assert"
+)
+
+var utm = linkparams.Params{"utm_source": "kannon", "utm_medium": "email"}
+
+func TestApplyAppendsInNameOrder(t *testing.T) {
+	d := linkparams.Decoration{Params: utm}
+
+	assert.Equal(t, "https://example.com/p?utm_medium=email&utm_source=kannon", d.Apply("https://example.com/p", nil))
+	assert.Equal(t, "https://example.com/p?a=1&utm_medium=email&utm_source=kannon", d.Apply("https://example.com/p?a=1", nil))
+	assert.Equal(t, "https://example.com/p?utm_medium=email&utm_source=kannon#top", d.Apply("https://example.com/p#top", nil),
+		"the fragment stays last")
+	assert.Equal(t, "https://example.com/?utm_medium=email&utm_source=kannon", d.Apply("https://example.com/?", nil))
+}
+
+func TestApplyNeverOverridesAParameterOnTheURL(t *testing.T) {
+	d := linkparams.Decoration{Params: utm}
+
+	assert.Equal(t, "https://example.com/?utm_source=partner&utm_medium=email", d.Apply("https://example.com/?utm_source=partner", nil))
+	assert.Equal(t, "https://example.com/?a=1&amp;utm_source=partner&utm_medium=email", d.Apply("https://example.com/?a=1&amp;utm_source=partner", nil),
+		"an href is read HTML-unescaped, and otherwise left as written")
+}
+
+func TestApplyPersonalisesValues(t *testing.T) {
+	d := linkparams.Decoration{Params: linkparams.Params{"utm_term": "{{ segment }}", "rid": "{{ id }}"}}
+
+	assert.Equal(t, "https://example.com/?utm_term=vip+%26+friends", d.Apply("https://example.com/", map[string]string{"segment": "vip & friends"}),
+		"a value is escaped, and one left with a placeholder is dropped")
+}
+
+func TestApplyLeavesWhatItCannotDecorate(t *testing.T) {
+	d := linkparams.Decoration{Params: utm}
+
+	for _, link := range []string{"mailto:a@example.com", "#top", "/relative", "ftp://example.com/f", "https://exa mple.com/%zz"} {
+		assert.Equal(t, link, d.Apply(link, nil))
+	}
+	assert.Equal(t, "https://example.com/", linkparams.Decoration{}.Apply("https://example.com/", nil))
+}
+
+func TestHosts(t *testing.T) {
+	h := linkparams.Hosts{Allow: []string{"example.com"}, Deny: []string{"pay.example.com"}}
+
+	assert.True(t, h.Covers("example.com"))
+	assert.True(t, h.Covers("WWW.Example.com"))
+	assert.False(t, h.Covers("pay.example.com"), "deny wins over allow")
+	assert.False(t, h.Covers("checkout.pay.example.com"))
+	assert.False(t, h.Covers("notexample.com"))
+	assert.False(t, h.Covers("other.org"))
+
+	assert.True(t, linkparams.Hosts{}.Covers("other.org"), "no allow list allows every host")
+	assert.Equal(t, "https://pay.example.com/", linkparams.Decoration{Params: utm, Hosts: h}.Apply("https://pay.example.com/", nil))
+}
+
+func TestBatchOverridesItsDomainParameterByParameter(t *testing.T) {
+	domain := linkparams.Params{"utm_source": "kannon", "utm_medium": "email", "utm_content": "default"}
+	batch := linkparams.Params{"utm_campaign": "spring", "utm_content": ""}
+
+	assert.Equal(t, linkparams.Params{"utm_source": "kannon", "utm_medium": "email", "utm_campaign": "spring"}, batch.Over(domain))
+	assert.Equal(t, "default", domain["utm_content"], "the Domain's map is left alone")
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, linkparams.Decoration{Params: utm, Hosts: linkparams.Hosts{Allow: []string{"Example.com"}}}.Validate())
+	assert.NoError(t, linkparams.Params{"utm_content": ""}.Validate())
+
+	for _, d := range []linkparams.Decoration{
+		{Params: linkparams.Params{"utm source": "x"}},
+		{Params: linkparams.Params{"utm_source": "a\r\nb"}},
+		{Params: linkparams.Params{"utm_source": strings.Repeat("x", 513)}},
+		{Hosts: linkparams.Hosts{Deny: []string{"https://example.com"}}},
+		{Hosts: linkparams.Hosts{Allow: []string{""}}},
+	} {
+		assert.ErrorIs(t, d.Validate(), linkparams.ErrInvalid, "%+v", d)
+	}
+}
@@ -0,0 +1,43 @@
+// Package linkparamspb translates Link Decorations between the wire message and the
+// internal/linkparams domain type, for the Admin API that states a Domain's and the Mailer API
+// that states a Batch's parameters. Like feedbackpb, it keeps internal/linkparams free of any
+// protobuf dependency.
+package linkparamspb
+
+import (
+	"github.com/kannon-email/kannon/internal/linkparams"
+	pb "github.com/kannon-email/kannon/proto/kannon/linkparams/types"
+)
+
+// ToDecoration translates a Decoration stated on the wire. A nil message states nothing. It does
+// not validate: that is the domain type's to do, at the point where the Decoration is stated.
+func ToDecoration(m *pb.LinkDecoration) linkparams.Decoration {
+	if m == nil {
+		return linkparams.Decoration{}
+	}
+	return linkparams.Decoration{
+		Params: ToParams(m.GetParams()),
+		Hosts: linkparams.Hosts{
+			Allow: m.GetAllowHosts(),
+			Deny:  m.GetDenyHosts(),
+		},
+	}
+}
+
+// ToParams translates a Batch's parameter override. An empty map overrides nothing, and is
+// returned as nil so that it is stored as nothing.
+func ToParams(m map[string]string) linkparams.Params {
+	if len(m) == 0 {
+		return nil
+	}
+	return linkparams.Params(m)
+}
+
+// FromDecoration renders a Decoration onto the wire.
+func FromDecoration(d linkparams.Decoration) *pb.LinkDecoration {
+	return &pb.LinkDecoration{
+		Params:     d.Params,
+		AllowHosts: d.Hosts.Allow,
+		DenyHosts:  d.Hosts.Deny,
+	}
+}
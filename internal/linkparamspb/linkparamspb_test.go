@@ -0,0 +1,22 @@
+package linkparamspb_test
+
+import (
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/linkparamspb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorationRoundTrips(t *testing.T) {
+	d := linkparams.Decoration{
+		Params: linkparams.Params{"utm_source": "kannon"},
+		Hosts:  linkparams.Hosts{Allow: []string{"example.com"}, Deny: []string{"pay.example.com"}},
+	}
+	assert.Equal(t, d, linkparamspb.ToDecoration(linkparamspb.FromDecoration(d)))
+}
+
+func TestNilStatesNothing(t *testing.T) {
+	assert.True(t, linkparamspb.ToDecoration(nil).IsZero())
+	assert.Nil(t, linkparamspb.ToParams(map[string]string{}))
+}
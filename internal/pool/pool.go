@@ -34,6 +34,11 @@ type Claimer interface {
 	// exponential backoff window.
 	Reschedule(ctx context.Context, d *delivery.Delivery) error
 
+	// Postpone hands a Delivery claimed for dispatch back to the pool to
+	// be claimed again at until, without spending an attempt: its
+	// Envelope was held back unsent.
+	Postpone(ctx context.Context, d *delivery.Delivery, until time.Time) error
+
 	// Drop removes a terminated Delivery from the pool.
 	Drop(ctx context.Context, d *delivery.Delivery) error
 
@@ -78,6 +83,10 @@ func (c *claimer) Reschedule(ctx context.Context, d *delivery.Delivery) error {
 	return c.deliveries.Reschedule(ctx, d.BatchID(), d.Email())
 }
 
+func (c *claimer) Postpone(ctx context.Context, d *delivery.Delivery, until time.Time) error {
+	return c.deliveries.Postpone(ctx, d.BatchID(), d.Email(), until)
+}
+
 func (c *claimer) Drop(ctx context.Context, d *delivery.Delivery) error {
 	return c.deliveries.Clean(ctx, d.BatchID(), d.Email())
 }
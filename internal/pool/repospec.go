@@ -74,6 +74,25 @@ func RunClaimerSpec(t *testing.T, c Claimer, helper ClaimerTestHelper) {
 			"scheduled time should advance after reschedule")
 	})
 
+	t.Run("Postpone", func(t *testing.T) {
+		ctx := t.Context()
+		batchID, domain := helper.CreateBatch(t)
+		email := "p@" + domain
+		dlv := mustNewDelivery(t, batchID, domain, email)
+		helper.Schedule(t, dlv)
+		require.NoError(t, c.MarkValidated(ctx, dlv))
+		_, err := c.ClaimForDispatch(ctx, 1000)
+		require.NoError(t, err)
+
+		until := time.Now().Add(time.Hour).UTC()
+		require.NoError(t, c.Postpone(ctx, dlv, until))
+
+		got, err := c.Lookup(ctx, batchID, email)
+		require.NoError(t, err)
+		assert.Equal(t, 0, got.SendAttempts())
+		assert.WithinDuration(t, until, got.ScheduledTime(), time.Second)
+	})
+
 	t.Run("Drop", func(t *testing.T) {
 		ctx := t.Context()
 		batchID, domain := helper.CreateBatch(t)
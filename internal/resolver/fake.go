@@ -0,0 +1,63 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+)
+
+// Fake is a Resolver that answers from its maps, for tests: a domain in MX
+// takes mail at its hosts, none for a null MX; a host in IP has its addresses,
+// and an address literal is itself; a name in Err fails with it, wrapped in an
+// *Error unless it is one; any other name does not exist.
+type Fake struct {
+	MX  map[string][]*net.MX
+	IP  map[string][]net.IP
+	Err map[string]error
+}
+
+// LookupMX implements Resolver.
+func (f *Fake) LookupMX(_ context.Context, domain string) ([]*net.MX, error) {
+	domain = normalize(domain)
+	if err := f.err(domain); err != nil {
+		return nil, err
+	}
+	mxs, ok := f.MX[domain]
+	if !ok {
+		return nil, &Error{Domain: domain, Err: ErrNXDomain}
+	}
+	return shuffled(mxs), nil
+}
+
+// LookupIP implements Resolver.
+func (f *Fake) LookupIP(_ context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	host = normalize(host)
+	if err := f.err(host); err != nil {
+		return nil, err
+	}
+	ips, ok := f.IP[host]
+	if !ok {
+		return nil, &Error{Domain: host, Err: ErrNXDomain}
+	}
+	if len(ips) == 0 {
+		return nil, &Error{Domain: host, Err: ErrNoAddress}
+	}
+	return slices.Clone(ips), nil
+}
+
+func (f *Fake) err(name string) error {
+	err, ok := f.Err[name]
+	if !ok {
+		return nil
+	}
+	var rerr *Error
+	if errors.As(err, &rerr) {
+		return err
+	}
+	return &Error{Domain: name, Err: err}
+}
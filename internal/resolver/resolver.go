@@ -0,0 +1,340 @@
+// Package resolver finds the hosts that take mail for a domain, and their
+// addresses. It is one
+// component shared by everything in a process that needs to — the Sender, the
+// SMTPSender's destination groups — so that one cache serves them all, one
+// `dns` section says which nameservers are asked, and what a failed lookup
+// means is decided in one place.
+//
+// The lookup follows RFC 5321 §5.1: the MX records, sorted by preference; for
+// a domain without any, the domain itself, provided it has an A or AAAA
+// record; and for a null MX (RFC 7505) no host at all. A domain that does not
+// exist, and one with neither MX nor address, is a permanent failure; a
+// nameserver that fails to answer is a temporary one. Answers are cached for
+// their TTL, and failures for as long as RFC 2308 and RFC 9520 allow.
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resolver looks up the hosts that take mail for a domain, and their addresses.
+type Resolver interface {
+	// LookupMX is the hosts that take mail for domain, most preferred first:
+	// its MX hosts, or the domain itself when it has no MX record but has an
+	// address, or none at all when it publishes a null MX. A failure is an
+	// *Error.
+	LookupMX(ctx context.Context, domain string) ([]*net.MX, error)
+	// LookupIP is host's addresses, its A and its AAAA records both, each
+	// family in the order it was given; an address literal is itself. A host
+	// with neither fails with ErrNoAddress. A failure is an *Error.
+	LookupIP(ctx context.Context, host string) ([]net.IP, error)
+}
+
+var (
+	// ErrNXDomain is a domain that does not exist.
+	ErrNXDomain = errors.New("no such domain")
+	// ErrNoAddress is a domain with neither an MX record nor an address to
+	// fall back to, which can take no mail, or a host without an address.
+	ErrNoAddress = errors.New("no MX record and no address")
+	// ErrServFail is a lookup no nameserver answered: SERVFAIL, a refusal, a
+	// timeout or no nameserver reachable. Asking again later may succeed.
+	ErrServFail = errors.New("no nameserver answered")
+)
+
+// Error is a lookup that failed, wrapping one of ErrNXDomain, ErrNoAddress and
+// ErrServFail.
+type Error struct {
+	Domain string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("lookup %s: %v", e.Domain, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Temporary reports whether asking again may succeed: it is false for a domain
+// the DNS said cannot take mail, and true for every lookup it did not answer.
+func (e *Error) Temporary() bool {
+	return !errors.Is(e.Err, ErrNXDomain) && !errors.Is(e.Err, ErrNoAddress)
+}
+
+const (
+	defaultTimeout     = 5 * time.Second
+	defaultMinTTL      = time.Minute
+	defaultMaxTTL      = time.Hour
+	defaultNegativeTTL = 5 * time.Minute
+	defaultCacheSize   = 10000
+
+	// failureTTL is how long a lookup no nameserver answered is remembered:
+	// long enough that a domain whose nameservers are down is not asked again
+	// for every message queued to it, and well under the five minutes RFC
+	// 9520 §3.2 allows.
+	failureTTL = 30 * time.Second
+)
+
+// Config is the `dns` section.
+type Config struct {
+	// Nameservers are the recursive resolvers asked, host or host:port (port
+	// 53 by default), in order: the next is asked when one fails to answer.
+	// Empty for the system's resolver, whose answers carry no TTL and are
+	// cached for MinTTL.
+	Nameservers []string `mapstructure:"nameservers"`
+	// Timeout bounds one query to one nameserver. Default 5s.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MinTTL and MaxTTL bound how long an answer is cached, whatever its TTL
+	// says. Defaults 1m and 1h.
+	MinTTL time.Duration `mapstructure:"min_ttl"`
+	MaxTTL time.Duration `mapstructure:"max_ttl"`
+	// NegativeTTL is the longest a domain that cannot take mail is cached as
+	// such; shorter when its zone's SOA says so (RFC 2308). Default 5m.
+	NegativeTTL time.Duration `mapstructure:"negative_ttl"`
+	// CacheSize bounds how many domains are remembered. Default 10000.
+	CacheSize int `mapstructure:"cache_size"`
+}
+
+func (c *Config) setDefaults() {
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.MinTTL <= 0 {
+		c.MinTTL = defaultMinTTL
+	}
+	if c.MaxTTL <= 0 {
+		c.MaxTTL = defaultMaxTTL
+	}
+	if c.NegativeTTL <= 0 {
+		c.NegativeTTL = defaultNegativeTTL
+	}
+	if c.CacheSize <= 0 {
+		c.CacheSize = defaultCacheSize
+	}
+}
+
+// Validate refuses a section that cannot be applied as written.
+func (c Config) Validate() error {
+	for _, ns := range c.Nameservers {
+		host, _, err := net.SplitHostPort(Addr(ns))
+		if err == nil && strings.Contains(host, ":") && net.ParseIP(host) == nil {
+			err = errors.New("too many colons")
+		}
+		if err != nil {
+			return fmt.Errorf("dns: nameserver %q is not host or host:port: %w", ns, err)
+		}
+	}
+	if c.MaxTTL > 0 && c.MinTTL > c.MaxTTL {
+		return fmt.Errorf("dns: min_ttl %v is above max_ttl %v", c.MinTTL, c.MaxTTL)
+	}
+	return nil
+}
+
+// Addr adds the DNS port to a nameserver given without one.
+func Addr(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	return net.JoinHostPort(strings.Trim(addr, "[]"), "53")
+}
+
+// New is the caching Resolver cfg describes. cfg is expected to have been
+// validated.
+func New(cfg Config) Resolver {
+	cfg.setDefaults()
+	var up upstream
+	if len(cfg.Nameservers) > 0 {
+		addrs := make([]string, 0, len(cfg.Nameservers))
+		for _, ns := range cfg.Nameservers {
+			addrs = append(addrs, Addr(ns))
+		}
+		up = &nameservers{servers: addrs, timeout: cfg.Timeout}
+	} else {
+		up = &system{ttl: cfg.MinTTL, timeout: cfg.Timeout}
+	}
+	return newCache(cfg, up)
+}
+
+// upstream is where the answers a cache keeps come from. Each method reports
+// a domain that does not exist as errNXDomain and a nameserver that did not
+// answer as any other error; ttl is how long the answer — records, no records
+// or errNXDomain — may be kept.
+type upstream interface {
+	// mx is the domain's MX records, the null MX's "." included, and none
+	// when it has none.
+	mx(ctx context.Context, domain string) (records []*net.MX, ttl time.Duration, err error)
+	// addrs is the host's A and AAAA records, none when it has neither.
+	addrs(ctx context.Context, host string) (ips []net.IP, ttl time.Duration, err error)
+}
+
+// errNXDomain is an upstream's NXDOMAIN.
+var errNXDomain = errors.New("NXDOMAIN")
+
+// cache is the Resolver New returns: the RFC 5321 lookup over an upstream,
+// each domain's outcome kept for its TTL, and each host's addresses likewise.
+type cache struct {
+	cfg Config
+	up  upstream
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[entryKey]entry
+}
+
+// entryKey is a name and which of the two lookups it was asked of: a domain
+// may well be an MX host too.
+type entryKey struct {
+	name string
+	ip   bool
+}
+
+type entry struct {
+	mxs     []*net.MX
+	ips     []net.IP
+	err     error
+	expires time.Time
+}
+
+func newCache(cfg Config, up upstream) *cache {
+	return &cache{cfg: cfg, up: up, now: time.Now, entries: map[entryKey]entry{}}
+}
+
+func (c *cache) LookupMX(ctx context.Context, domain string) ([]*net.MX, error) {
+	e := c.get(ctx, entryKey{name: normalize(domain)})
+	if e.err != nil {
+		return nil, e.err
+	}
+	return shuffled(e.mxs), nil
+}
+
+func (c *cache) LookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	e := c.get(ctx, entryKey{name: normalize(host), ip: true})
+	if e.err != nil {
+		return nil, e.err
+	}
+	return slices.Clone(e.ips), nil
+}
+
+// get is the entry for k, looked up afresh when there is none or it expired.
+func (c *cache) get(ctx context.Context, k entryKey) entry {
+	now := c.now()
+
+	c.mu.Lock()
+	e, ok := c.entries[k]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e
+	}
+
+	var ttl time.Duration
+	if k.ip {
+		e.ips, ttl, e.err = c.lookupIP(ctx, k.name)
+	} else {
+		e.mxs, ttl, e.err = c.lookup(ctx, k.name)
+	}
+	e.expires = now.Add(ttl)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[k]; !ok && len(c.entries) >= c.cfg.CacheSize {
+		c.entries = map[entryKey]entry{}
+	}
+	c.entries[k] = e
+	return e
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// lookup is the RFC 5321 §5.1 lookup, and how long its outcome may be kept.
+func (c *cache) lookup(ctx context.Context, domain string) ([]*net.MX, time.Duration, error) {
+	records, ttl, err := c.up.mx(ctx, domain)
+	if err != nil {
+		ttl, err := c.failed(domain, ttl, err)
+		return nil, ttl, err
+	}
+
+	if len(records) == 0 {
+		if _, ttl, err := c.lookupIP(ctx, domain); err != nil {
+			return nil, ttl, err
+		}
+		return []*net.MX{{Host: domain}}, c.positiveTTL(ttl), nil
+	}
+
+	mxs := make([]*net.MX, 0, len(records))
+	for _, r := range records {
+		// RFC 7505 §3: a null MX is the only record of its domain. Where
+		// it is not, the hosts beside it are used and the "." skipped.
+		if r.Host == "." || r.Host == "" {
+			continue
+		}
+		mxs = append(mxs, &net.MX{Host: strings.TrimSuffix(r.Host, "."), Pref: r.Pref})
+	}
+	slices.SortStableFunc(mxs, func(a, b *net.MX) int { return int(a.Pref) - int(b.Pref) })
+	return mxs, c.positiveTTL(ttl), nil
+}
+
+// lookupIP is host's addresses, and how long they may be kept.
+func (c *cache) lookupIP(ctx context.Context, host string) ([]net.IP, time.Duration, error) {
+	ips, ttl, err := c.up.addrs(ctx, host)
+	if err != nil {
+		ttl, err := c.failed(host, ttl, err)
+		return nil, ttl, err
+	}
+	if len(ips) == 0 {
+		return nil, c.negativeTTL(ttl), &Error{Domain: host, Err: ErrNoAddress}
+	}
+	return ips, c.positiveTTL(ttl), nil
+}
+
+// failed is the Error of an upstream's err, and ttl how long it is kept.
+func (c *cache) failed(domain string, ttl time.Duration, err error) (time.Duration, error) {
+	if errors.Is(err, errNXDomain) {
+		return c.negativeTTL(ttl), &Error{Domain: domain, Err: ErrNXDomain}
+	}
+	return failureTTL, &Error{Domain: domain, Err: fmt.Errorf("%w: %v", ErrServFail, err)}
+}
+
+func (c *cache) positiveTTL(ttl time.Duration) time.Duration {
+	return min(max(ttl, c.cfg.MinTTL), c.cfg.MaxTTL)
+}
+
+// negativeTTL is the SOA's negative TTL (RFC 2308 §5) up to NegativeTTL, and
+// NegativeTTL when the answer came without one.
+func (c *cache) negativeTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return c.cfg.NegativeTTL
+	}
+	return min(ttl, c.cfg.NegativeTTL)
+}
+
+// shuffled is a copy of mxs, hosts of the same preference in random order as
+// RFC 5321 §5.1 asks, so that they share the load.
+func shuffled(mxs []*net.MX) []*net.MX {
+	out := make([]*net.MX, len(mxs))
+	for i, mx := range mxs {
+		cp := *mx
+		out[i] = &cp
+	}
+	for i := 0; i < len(out); {
+		j := i + 1
+		for j < len(out) && out[j].Pref == out[i].Pref {
+			j++
+		}
+		group := out[i:j]
+		rand.Shuffle(len(group), func(a, b int) { group[a], group[b] = group[b], group[a] })
+		i = j
+	}
+	return out
+}
@@ -0,0 +1,296 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// fakeUpstream answers from its maps and counts the questions it was asked.
+type fakeUpstream struct {
+	mxs   map[string][]*net.MX
+	ips   map[string][]net.IP
+	errs  map[string]error
+	ttl   time.Duration
+	asked int
+}
+
+func (f *fakeUpstream) mx(_ context.Context, domain string) ([]*net.MX, time.Duration, error) {
+	f.asked++
+	if err, ok := f.errs[domain]; ok {
+		return nil, f.ttl, err
+	}
+	return f.mxs[domain], f.ttl, nil
+}
+
+func (f *fakeUpstream) addrs(_ context.Context, host string) ([]net.IP, time.Duration, error) {
+	f.asked++
+	if err, ok := f.errs[host]; ok {
+		return nil, f.ttl, err
+	}
+	return f.ips[host], f.ttl, nil
+}
+
+func newTestCache(up upstream) (*cache, *time.Time) {
+	cfg := Config{}
+	cfg.setDefaults()
+	c := newCache(cfg, up)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestLookupMX(t *testing.T) {
+	up := &fakeUpstream{
+		mxs: map[string][]*net.MX{
+			"mx.test": {
+				{Host: "backup.mx.test.", Pref: 20},
+				{Host: "primary.mx.test.", Pref: 10},
+			},
+			"null.test": {{Host: ".", Pref: 0}},
+		},
+		ips: map[string][]net.IP{"address.test": {net.ParseIP("192.0.2.1")}},
+		errs: map[string]error{
+			"nx.test":       errNXDomain,
+			"servfail.test": errors.New("127.0.0.1:53 answered ServerFailure"),
+		},
+		ttl: 10 * time.Minute,
+	}
+	c, _ := newTestCache(up)
+	ctx := t.Context()
+
+	mxs, err := c.LookupMX(ctx, "MX.test.")
+	require.NoError(t, err)
+	assert.Equal(t, []*net.MX{{Host: "primary.mx.test", Pref: 10}, {Host: "backup.mx.test", Pref: 20}}, mxs,
+		"most preferred first, without the trailing dot")
+
+	mxs, err = c.LookupMX(ctx, "address.test")
+	require.NoError(t, err)
+	assert.Equal(t, []*net.MX{{Host: "address.test"}}, mxs, "a domain without MX but with an address is its own MX")
+
+	mxs, err = c.LookupMX(ctx, "null.test")
+	require.NoError(t, err)
+	assert.Empty(t, mxs, "a null MX takes no mail")
+
+	var rerr *Error
+	_, err = c.LookupMX(ctx, "nx.test")
+	require.ErrorAs(t, err, &rerr)
+	assert.ErrorIs(t, err, ErrNXDomain)
+	assert.False(t, rerr.Temporary())
+
+	_, err = c.LookupMX(ctx, "noaddress.test")
+	require.ErrorAs(t, err, &rerr)
+	assert.ErrorIs(t, err, ErrNoAddress)
+	assert.False(t, rerr.Temporary())
+
+	_, err = c.LookupMX(ctx, "servfail.test")
+	require.ErrorAs(t, err, &rerr)
+	assert.ErrorIs(t, err, ErrServFail)
+	assert.True(t, rerr.Temporary())
+}
+
+func TestLookupIP(t *testing.T) {
+	up := &fakeUpstream{
+		ips: map[string][]net.IP{
+			"mx.test": {net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")},
+		},
+		errs: map[string]error{"nx.test": errNXDomain, "servfail.test": errors.New("timeout")},
+		ttl:  10 * time.Minute,
+	}
+	c, _ := newTestCache(up)
+	ctx := t.Context()
+
+	ips, err := c.LookupIP(ctx, "MX.test.")
+	require.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")}, ips)
+
+	ips, err = c.LookupIP(ctx, "[2001:db8::2]")
+	require.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("2001:db8::2")}, ips, "an address literal is not looked up")
+	assert.Equal(t, 1, up.asked)
+
+	_, err = c.LookupIP(ctx, "noaddress.test")
+	assert.ErrorIs(t, err, ErrNoAddress)
+	_, err = c.LookupIP(ctx, "nx.test")
+	assert.ErrorIs(t, err, ErrNXDomain)
+	_, err = c.LookupIP(ctx, "servfail.test")
+	assert.ErrorIs(t, err, ErrServFail)
+}
+
+func TestLookupMXCachesForTheTTL(t *testing.T) {
+	up := &fakeUpstream{
+		mxs: map[string][]*net.MX{
+			"mx.test":       {{Host: "mx.test.", Pref: 10}},
+			"short.mx.test": {{Host: "short.mx.test.", Pref: 10}},
+		},
+		errs: map[string]error{"nx.test": errNXDomain, "servfail.test": errors.New("timeout")},
+		ttl:  10 * time.Minute,
+	}
+	c, now := newTestCache(up)
+	ctx := t.Context()
+
+	lookup := func(domain string) int {
+		before := up.asked
+		_, _ = c.LookupMX(ctx, domain)
+		return up.asked - before
+	}
+
+	assert.Equal(t, 1, lookup("mx.test"))
+	*now = now.Add(9 * time.Minute)
+	assert.Equal(t, 0, lookup("mx.test"), "kept for its TTL")
+	*now = now.Add(time.Minute)
+	assert.Equal(t, 1, lookup("mx.test"), "asked again once it expires")
+
+	assert.Equal(t, 1, lookup("nx.test"))
+	*now = now.Add(4 * time.Minute)
+	assert.Equal(t, 0, lookup("nx.test"))
+	*now = now.Add(time.Minute)
+	assert.Equal(t, 1, lookup("nx.test"), "NXDOMAIN is kept for no longer than NegativeTTL")
+
+	assert.Equal(t, 1, lookup("servfail.test"))
+	*now = now.Add(failureTTL - time.Second)
+	assert.Equal(t, 0, lookup("servfail.test"))
+	*now = now.Add(time.Second)
+	assert.Equal(t, 1, lookup("servfail.test"), "a failure to answer is retried soon")
+
+	up.ttl = 5 * time.Second
+	assert.Equal(t, 1, lookup("short.mx.test"))
+	*now = now.Add(59 * time.Second)
+	assert.Equal(t, 0, lookup("short.mx.test"), "a TTL below MinTTL is raised to it")
+}
+
+func TestLookupMXShufflesEqualPreferences(t *testing.T) {
+	up := &fakeUpstream{
+		mxs: map[string][]*net.MX{"mx.test": {
+			{Host: "a.mx.test", Pref: 10},
+			{Host: "b.mx.test", Pref: 10},
+			{Host: "c.mx.test", Pref: 20},
+		}},
+		ttl: time.Hour,
+	}
+	c, _ := newTestCache(up)
+
+	firsts := map[string]bool{}
+	for range 100 {
+		mxs, err := c.LookupMX(t.Context(), "mx.test")
+		require.NoError(t, err)
+		require.Len(t, mxs, 3)
+		assert.Equal(t, "c.mx.test", mxs[2].Host, "a less preferred host never comes first")
+		firsts[mxs[0].Host] = true
+	}
+	assert.Equal(t, map[string]bool{"a.mx.test": true, "b.mx.test": true}, firsts)
+}
+
+func TestNameservers(t *testing.T) {
+	addr := dnsServer(t)
+	up := &nameservers{servers: []string{unreachable(t), addr}, timeout: 200 * time.Millisecond}
+	ctx := t.Context()
+
+	records, ttl, err := up.mx(ctx, "mx.test")
+	require.NoError(t, err, "the next nameserver is asked when one does not answer")
+	assert.Equal(t, []*net.MX{{Host: "primary.mx.test.", Pref: 10}}, records)
+	assert.Equal(t, 10*time.Minute, ttl)
+
+	records, ttl, err = up.mx(ctx, "address.test")
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	assert.Equal(t, 2*time.Minute, ttl, "the SOA's MINIMUM, being below its TTL")
+
+	ips, ttl, err := up.addrs(ctx, "address.test")
+	require.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("::1")}, ips, "an AAAA record is as good as an A one")
+	assert.Equal(t, 2*time.Minute, ttl, "no longer than the A record's absence may be kept")
+
+	_, ttl, err = up.mx(ctx, "nx.test")
+	assert.ErrorIs(t, err, errNXDomain)
+	assert.Equal(t, 2*time.Minute, ttl)
+
+	_, _, err = up.mx(ctx, "servfail.test")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, errNXDomain)
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{Nameservers: []string{"1.1.1.1", "[2606:4700:4700::1111]:53", "dns.test:5353"}}.Validate())
+	assert.Error(t, Config{Nameservers: []string{"1.1.1.1:53:53"}}.Validate())
+	assert.Error(t, Config{MinTTL: time.Hour, MaxTTL: time.Minute}.Validate())
+}
+
+func TestAddr(t *testing.T) {
+	assert.Equal(t, "1.1.1.1:53", Addr("1.1.1.1"))
+	assert.Equal(t, "[2606:4700:4700::1111]:53", Addr("2606:4700:4700::1111"))
+	assert.Equal(t, "[2606:4700:4700::1111]:53", Addr("[2606:4700:4700::1111]"))
+	assert.Equal(t, "127.0.0.1:5353", Addr("127.0.0.1:5353"))
+}
+
+// unreachable is the address of a UDP port nothing answers on.
+func unreachable(t *testing.T) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+	return pc.LocalAddr().String()
+}
+
+// dnsServer answers MX, A and AAAA questions for mx.test, address.test and
+// nx.test, and SERVFAIL for anything else.
+func dnsServer(t *testing.T) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+
+	soa := dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName("test."), Type: dnsmessage.TypeSOA, Class: dnsmessage.ClassINET, TTL: 3600},
+		Body: &dnsmessage.SOAResource{
+			NS: dnsmessage.MustNewName("ns.test."), MBox: dnsmessage.MustNewName("hostmaster.test."),
+			Serial: 1, Refresh: 3600, Retry: 600, Expire: 86400, MinTTL: 120,
+		},
+	}
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var q dnsmessage.Message
+			if err := q.Unpack(buf[:n]); err != nil {
+				continue
+			}
+			question := q.Questions[0]
+			hdr := dnsmessage.ResourceHeader{Name: question.Name, Type: question.Type, Class: dnsmessage.ClassINET, TTL: 600}
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: q.Header.ID, Response: true},
+				Questions: q.Questions,
+			}
+			switch name := question.Name.String(); {
+			case name == "mx.test." && question.Type == dnsmessage.TypeMX:
+				resp.Answers = []dnsmessage.Resource{{Header: hdr, Body: &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("primary.mx.test.")}}}
+			case name == "address.test." && question.Type == dnsmessage.TypeAAAA:
+				resp.Answers = []dnsmessage.Resource{{Header: hdr, Body: &dnsmessage.AAAAResource{AAAA: [16]byte{15: 1}}}}
+			case name == "mx.test." || name == "address.test.":
+				resp.Authorities = []dnsmessage.Resource{soa}
+			case name == "nx.test.":
+				resp.Header.RCode = dnsmessage.RCodeNameError
+				resp.Authorities = []dnsmessage.Resource{soa}
+			default:
+				resp.Header.RCode = dnsmessage.RCodeServerFailure
+			}
+			out, err := resp.Pack()
+			if err != nil {
+				continue
+			}
+			_, _ = pc.WriteTo(out, addr)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
@@ -0,0 +1,259 @@
+package resolver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// system asks the system's resolver. Its answers carry no TTL, so they are all
+// kept for ttl, and it cannot tell NXDOMAIN from a name without records of the
+// type asked: a domain it knows nothing of is one without MX records, and then
+// one without an address, which is as permanent a failure as NXDOMAIN.
+type system struct {
+	r       net.Resolver
+	ttl     time.Duration
+	timeout time.Duration
+}
+
+func (s *system) mx(ctx context.Context, domain string) ([]*net.MX, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	records, err := s.r.LookupMX(ctx, domain)
+	if isNotFound(err) {
+		return nil, s.ttl, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, s.ttl, nil
+}
+
+func (s *system) addrs(ctx context.Context, host string) ([]net.IP, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	addrs, err := s.r.LookupIPAddr(ctx, host)
+	if isNotFound(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, a := range addrs {
+		ips = append(ips, a.IP)
+	}
+	return ips, s.ttl, nil
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// nameservers asks the configured recursive resolvers over DNS, and so has
+// TTLs to honour and NXDOMAIN to tell apart.
+type nameservers struct {
+	servers []string
+	timeout time.Duration
+}
+
+func (n *nameservers) mx(ctx context.Context, domain string) ([]*net.MX, time.Duration, error) {
+	m, ttl, err := n.query(ctx, domain, dnsmessage.TypeMX)
+	if err != nil {
+		return nil, ttl, err
+	}
+	var records []*net.MX
+	for _, rr := range m.Answers {
+		if body, ok := rr.Body.(*dnsmessage.MXResource); ok {
+			records = append(records, &net.MX{Host: body.MX.String(), Pref: body.Pref})
+		}
+	}
+	if len(records) == 0 {
+		return nil, negativeTTLOf(m), nil
+	}
+	return records, ttl, nil
+}
+
+// addrs asks for A and AAAA, and keeps the answer for the shorter of the two
+// TTLs — the negative one of a family the host has no address of included,
+// since a record added to it is as much news as one removed from the other.
+func (n *nameservers) addrs(ctx context.Context, host string) ([]net.IP, time.Duration, error) {
+	var ips []net.IP
+	var ttl time.Duration
+	for i, typ := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		m, t, err := n.query(ctx, host, typ)
+		if err != nil {
+			return nil, t, err
+		}
+		found := false
+		for _, rr := range m.Answers {
+			switch body := rr.Body.(type) {
+			case *dnsmessage.AResource:
+				ips, found = append(ips, net.IP(body.A[:])), true
+			case *dnsmessage.AAAAResource:
+				ips, found = append(ips, net.IP(body.AAAA[:])), true
+			}
+		}
+		if !found {
+			t = negativeTTLOf(m)
+		}
+		if i == 0 || t < ttl {
+			ttl = t
+		}
+	}
+	return ips, ttl, nil
+}
+
+// query asks each nameserver in turn until one answers, reporting NXDOMAIN as
+// errNXDomain with the negative TTL, and an answer as the message and the
+// shortest TTL of its records of typ.
+func (n *nameservers) query(ctx context.Context, domain string, typ dnsmessage.Type) (dnsmessage.Message, time.Duration, error) {
+	q, id, err := newQuery(domain, typ)
+	if err != nil {
+		// A name the DNS cannot carry is one no nameserver has.
+		return dnsmessage.Message{}, 0, errNXDomain
+	}
+	var lastErr error
+	for _, addr := range n.servers {
+		m, err := n.exchange(ctx, addr, q, id)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		switch m.Header.RCode {
+		case dnsmessage.RCodeSuccess:
+			return m, answerTTL(m, typ), nil
+		case dnsmessage.RCodeNameError:
+			return m, negativeTTLOf(m), errNXDomain
+		default:
+			lastErr = fmt.Errorf("%s answered %v", addr, m.Header.RCode)
+		}
+	}
+	return dnsmessage.Message{}, 0, lastErr
+}
+
+func (n *nameservers) exchange(ctx context.Context, addr string, q []byte, id uint16) (dnsmessage.Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+	m, err := Exchange(ctx, "udp", addr, q, id)
+	if err == nil && m.Header.Truncated {
+		m, err = Exchange(ctx, "tcp", addr, q, id)
+	}
+	return m, err
+}
+
+func newQuery(domain string, typ dnsmessage.Type) ([]byte, uint16, error) {
+	name, err := dnsmessage.NewName(domain + ".")
+	if err != nil {
+		return nil, 0, err
+	}
+	var idb [2]byte
+	_, _ = rand.Read(idb[:])
+	id := binary.BigEndian.Uint16(idb[:])
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, RecursionDesired: true})
+	if err := b.StartQuestions(); err != nil {
+		return nil, 0, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: name, Type: typ, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, 0, err
+	}
+	if err := b.StartAdditionals(); err != nil {
+		return nil, 0, err
+	}
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(1232, dnsmessage.RCodeSuccess, false); err != nil {
+		return nil, 0, err
+	}
+	if err := b.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+		return nil, 0, err
+	}
+	msg, err := b.Finish()
+	return msg, id, err
+}
+
+// answerTTL is the shortest TTL of m's records of typ.
+func answerTTL(m dnsmessage.Message, typ dnsmessage.Type) time.Duration {
+	var ttl time.Duration
+	found := false
+	for _, rr := range m.Answers {
+		if rr.Header.Type != typ {
+			continue
+		}
+		t := time.Duration(rr.Header.TTL) * time.Second
+		if !found || t < ttl {
+			ttl, found = t, true
+		}
+	}
+	return ttl
+}
+
+// negativeTTLOf is how long a negative answer may be kept by RFC 2308 §5: the
+// lesser of its SOA's TTL and the SOA's MINIMUM field, and 0 when the answer
+// came without an SOA.
+func negativeTTLOf(m dnsmessage.Message) time.Duration {
+	for _, rr := range m.Authorities {
+		if soa, ok := rr.Body.(*dnsmessage.SOAResource); ok {
+			return time.Duration(min(rr.Header.TTL, soa.MinTTL)) * time.Second
+		}
+	}
+	return 0
+}
+
+// Exchange sends one query and reads its answer, over UDP or — with the
+// length prefix RFC 1035 §4.2.2 puts before each message — over TCP. ctx
+// bounds it.
+func Exchange(ctx context.Context, network, addr string, query []byte, id uint16) (dnsmessage.Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return dnsmessage.Message{}, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	var buf []byte
+	if network == "tcp" {
+		framed := binary.BigEndian.AppendUint16(nil, uint16(len(query)))
+		if _, err := conn.Write(append(framed, query...)); err != nil {
+			return dnsmessage.Message{}, err
+		}
+		var size [2]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return dnsmessage.Message{}, err
+		}
+		buf = make([]byte, binary.BigEndian.Uint16(size[:]))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return dnsmessage.Message{}, err
+		}
+	} else {
+		if _, err := conn.Write(query); err != nil {
+			return dnsmessage.Message{}, err
+		}
+		buf = make([]byte, 65535)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return dnsmessage.Message{}, err
+		}
+		buf = buf[:n]
+	}
+
+	var m dnsmessage.Message
+	if err := m.Unpack(buf); err != nil {
+		return dnsmessage.Message{}, err
+	}
+	if m.Header.ID != id || !m.Header.Response {
+		return dnsmessage.Message{}, errors.New("resolver answered another query")
+	}
+	return m, nil
+}
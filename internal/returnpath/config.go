@@ -0,0 +1,63 @@
+package returnpath
+
+import (
+	"time"
+
+	"github.com/kannon-email/kannon/x/config"
+)
+
+// configKey is the section both ends of the scheme read: the Dispatcher signs
+// with it and the SMTPServer verifies with it. Named once, because a second
+// spelling would leave one of them signing with keys the other never sees.
+const configKey = "return_path"
+
+// Config is the "return_path" section.
+//
+//	return_path:
+//	  keys:
+//	    - id: "2"
+//	      secret: env://KANNON_RETURN_PATH_KEY_2
+//	    - id: "1"
+//	      secret: env://KANNON_RETURN_PATH_KEY_1
+//	      retired_at: "2026-10-19"
+//	  max_age: 168h
+//	  accept_unsigned: false
+type Config struct {
+	// Keys signs with the first key that is not retired and verifies with every
+	// one of them. An empty list turns signing off altogether.
+	Keys []KeyConfig `mapstructure:"keys"`
+	// MaxAge is how long after it was signed an address is accepted for,
+	// DefaultMaxAge when zero. It is counted in whole days, rounded up.
+	MaxAge time.Duration `mapstructure:"max_age"`
+	// AcceptUnsigned keeps the unsigned form accepted while keys are configured:
+	// the switch for the migration, since mail sent before signing was turned
+	// on can still bounce for as long as MaxAge afterwards.
+	AcceptUnsigned bool `mapstructure:"accept_unsigned"`
+}
+
+// KeyConfig is one signing key.
+type KeyConfig struct {
+	// ID is written into every tag the key signs, so it is one character, a
+	// digit or a lowercase letter.
+	ID string `mapstructure:"id"`
+	// Secret is the HMAC key. At least 16 bytes; take it from the environment.
+	Secret string `mapstructure:"secret"`
+	// RetiredAt, a date or an RFC 3339 time, stops the key signing. It still
+	// verifies for MaxAge afterwards — the grace period in which the mail it
+	// signed can still bounce — and not at all after that.
+	RetiredAt string `mapstructure:"retired_at"`
+}
+
+// MustLoad reads the section and builds its Keyring, panicking on a malformed
+// one as every other section read on the boot path does: a key the file gets
+// wrong is the operator's mistake, and finding it at the first bounce would be
+// finding it too late.
+func MustLoad() *Keyring {
+	var cfg Config
+	config.LoadSection(configKey, &cfg)
+	k, err := New(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
@@ -0,0 +1,235 @@
+// Package returnpath signs the bounce addresses Kannon sends mail from, so the
+// SMTPServer can tell a bounce to an address Kannon wrote from one somebody
+// made up.
+//
+// An unsigned bounce address is "bump_<email>+<message ID>", and both halves
+// can be guessed: the email is a Recipient, and the message ID is printed in
+// every Envelope of its Batch. Anyone who can reach the SMTPServer could
+// therefore mark any Recipient of any Batch they had seen a message of as
+// Bounced. The tag this package adds closes that, in the shape BATV (the
+// "prvs=" scheme) gives its own:
+//
+//	bump_<email>.<K><DDD><MAC>+<message ID>
+//
+// K is the key that signed it, DDD the day it was signed on (days since the
+// Unix epoch, modulo 1000) and MAC a truncated HMAC-SHA256 over the email,
+// the message ID and the day. The day is what lets an address expire: a
+// bounce is only ever the answer to mail Kannon sent recently, so an address
+// older than MaxAge is refused even when its MAC is good, and a leaked one
+// stops being useful on its own.
+package returnpath
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/utils"
+)
+
+// DefaultMaxAge is how long a signed address is accepted for when the
+// configuration does not say — BATV's own default. A remote MTA gives up on a
+// deferred message after five days at most, so a week covers the slowest
+// bounce that is still a real one.
+const DefaultMaxAge = 7 * 24 * time.Hour
+
+// macLen is how many hex digits of the HMAC the tag carries: 64 bits, where
+// BATV settles for 24. Each guess costs a forger one RCPT, but the SMTPServer
+// does not rate-limit them, so the margin is spent here instead.
+const macLen = 16
+
+// dayModulus is the range the tag's day wraps in. An address is never
+// accepted for anything close to that long, so the wrap is never ambiguous.
+const dayModulus = 1000
+
+var (
+	// ErrUnsigned is a bounce address without a tag: mail Kannon sent before
+	// signing was configured, or somebody who knows the old format.
+	ErrUnsigned = errors.New("return path is not signed")
+	// ErrBadSignature is a tag no configured key produced: a forgery, or an
+	// address signed with a key that has since been dropped.
+	ErrBadSignature = errors.New("return path signature does not verify")
+	// ErrStale is a tag that verifies but is older than MaxAge, or signed by a
+	// retired key after its grace period ran out.
+	ErrStale = errors.New("return path has expired")
+	// ErrMalformed is a bounce address Kannon could not have written.
+	ErrMalformed = errors.New("return path is malformed")
+)
+
+// minSecretLen is the shortest secret accepted. HMAC takes any length, and a
+// short one is a password somebody can guess offline from a single tag.
+const minSecretLen = 16
+
+var keyIDPattern = regexp.MustCompile(`^[0-9a-z]$`)
+
+type key struct {
+	id        byte
+	secret    []byte
+	retiredAt time.Time
+}
+
+// Keyring signs and verifies bounce addresses. A nil Keyring, like one with no
+// keys, signs nothing and accepts everything: the behaviour of a Kannon that
+// has not been configured to sign.
+type Keyring struct {
+	keys           []key
+	signer         *key
+	maxAgeDays     int
+	acceptUnsigned bool
+}
+
+// New validates the section and builds the Keyring it describes. Every
+// mistake in the section is an error rather than a key quietly skipped,
+// because the one it leaves out is the one a bounce will be refused over.
+func New(cfg Config) (*Keyring, error) {
+	maxAge := cfg.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	days := int((maxAge + 24*time.Hour - 1) / (24 * time.Hour))
+	if days >= dayModulus/2 {
+		return nil, fmt.Errorf("return_path.max_age %v is longer than a signed address can tell apart", maxAge)
+	}
+
+	k := &Keyring{maxAgeDays: days, acceptUnsigned: cfg.AcceptUnsigned}
+	seen := make(map[string]bool)
+	for i, kc := range cfg.Keys {
+		if !keyIDPattern.MatchString(kc.ID) {
+			return nil, fmt.Errorf("return_path.keys[%d]: id %q must be one digit or lowercase letter", i, kc.ID)
+		}
+		if seen[kc.ID] {
+			return nil, fmt.Errorf("return_path.keys[%d]: id %q is used twice", i, kc.ID)
+		}
+		seen[kc.ID] = true
+		if len(kc.Secret) < minSecretLen {
+			return nil, fmt.Errorf("return_path.keys[%d]: secret must be at least %d bytes", i, minSecretLen)
+		}
+		retiredAt, err := parseRetiredAt(kc.RetiredAt)
+		if err != nil {
+			return nil, fmt.Errorf("return_path.keys[%d]: %w", i, err)
+		}
+		k.keys = append(k.keys, key{id: kc.ID[0], secret: []byte(kc.Secret), retiredAt: retiredAt})
+	}
+	for i := range k.keys {
+		if k.keys[i].retiredAt.IsZero() {
+			k.signer = &k.keys[i]
+			break
+		}
+	}
+	if len(k.keys) > 0 && k.signer == nil {
+		return nil, errors.New("return_path.keys: every key is retired, so none can sign")
+	}
+	return k, nil
+}
+
+func parseRetiredAt(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("retired_at %q is neither a date nor an RFC 3339 time", s)
+	}
+	return t, nil
+}
+
+// Enabled reports whether addresses are signed at all.
+func (k *Keyring) Enabled() bool {
+	return k != nil && k.signer != nil
+}
+
+// Tag returns the tag to write into the bounce address of a message to email
+// with the given message ID, or "" when signing is off.
+func (k *Keyring) Tag(email, messageID string, now time.Time) string {
+	if !k.Enabled() {
+		return ""
+	}
+	day := dayNumber(now)
+	return string(k.signer.id) + fmt.Sprintf("%03d", day) + k.signer.mac(email, messageID, day)
+}
+
+// Verify checks the bounce address an inbound message was addressed to. An
+// address that is not a bounce address at all is none of its business and
+// passes; so does everything when signing is off.
+func (k *Keyring) Verify(returnPath string, now time.Time) error {
+	if !k.Enabled() {
+		return nil
+	}
+	email, messageID, _, found, err := utils.ParseBounceReturnPath(returnPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+	if !found {
+		return nil
+	}
+
+	tag := utils.BounceReturnPathTag(returnPath)
+	if tag == "" {
+		if k.acceptUnsigned {
+			return nil
+		}
+		return ErrUnsigned
+	}
+	if len(tag) != 1+3+macLen {
+		return ErrMalformed
+	}
+	day, err := strconv.Atoi(tag[1:4])
+	if err != nil {
+		return ErrMalformed
+	}
+
+	signer := k.key(tag[0])
+	if signer == nil || !hmac.Equal([]byte(tag[4:]), []byte(signer.mac(email, messageID, day))) {
+		return ErrBadSignature
+	}
+
+	age := ageInDays(dayNumber(now), day)
+	if age > k.maxAgeDays {
+		return ErrStale
+	}
+	if !signer.retiredAt.IsZero() && now.After(signer.retiredAt.AddDate(0, 0, k.maxAgeDays)) {
+		return ErrStale
+	}
+	return nil
+}
+
+func (k *Keyring) key(id byte) *key {
+	for i := range k.keys {
+		if k.keys[i].id == id {
+			return &k.keys[i]
+		}
+	}
+	return nil
+}
+
+// mac covers the key id as well as the address, so a tag cannot be moved onto
+// another key by rewriting its first character. The fields are joined with a
+// NUL, which neither an address nor a message ID may contain.
+func (k *key) mac(email, messageID string, day int) string {
+	h := hmac.New(sha256.New, k.secret)
+	fmt.Fprintf(h, "%c\x00%s\x00%s\x00%03d", k.id, email, messageID, day)
+	return hex.EncodeToString(h.Sum(nil))[:macLen]
+}
+
+func dayNumber(t time.Time) int {
+	return int(t.Unix()/86400) % dayModulus
+}
+
+// ageInDays is how many days separate the tag's day from today, across the
+// wrap. A tag dated tomorrow counts as today's: the Dispatcher's clock and the
+// SMTPServer's need not agree on when midnight is.
+func ageInDays(today, signed int) int {
+	age := (today - signed + dayModulus) % dayModulus
+	if age == dayModulus-1 {
+		return 0
+	}
+	return age
+}
@@ -0,0 +1,137 @@
+package returnpath_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/returnpath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	email     = "rcpt@example.com"
+	messageID = "msg_test01@k.test.com"
+	secretOne = "0123456789abcdef-one"
+	secretTwo = "0123456789abcdef-two"
+)
+
+var now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+// address writes a bounce address the way internal/envelope does.
+func address(tag string) string {
+	b64 := base64.URLEncoding.EncodeToString([]byte(email))
+	if tag != "" {
+		b64 += "." + tag
+	}
+	return "bump_" + b64 + "+" + messageID
+}
+
+func mustNew(t *testing.T, cfg returnpath.Config) *returnpath.Keyring {
+	t.Helper()
+	k, err := returnpath.New(cfg)
+	require.NoError(t, err)
+	return k
+}
+
+func TestSignedAddressVerifies(t *testing.T) {
+	k := mustNew(t, returnpath.Config{Keys: []returnpath.KeyConfig{{ID: "1", Secret: secretOne}}})
+
+	tag := k.Tag(email, messageID, now)
+	require.Len(t, tag, 20)
+	assert.Equal(t, byte('1'), tag[0])
+
+	assert.NoError(t, k.Verify(address(tag), now))
+	assert.NoError(t, k.Verify(address(tag), now.Add(7*24*time.Hour)))
+}
+
+func TestTamperedAddressIsRefused(t *testing.T) {
+	k := mustNew(t, returnpath.Config{Keys: []returnpath.KeyConfig{{ID: "1", Secret: secretOne}}})
+	tag := k.Tag(email, messageID, now)
+
+	// The same tag on another Recipient of the Batch.
+	other := "bump_" + base64.URLEncoding.EncodeToString([]byte("victim@example.com")) + "." + tag + "+" + messageID
+	assert.ErrorIs(t, k.Verify(other, now), returnpath.ErrBadSignature)
+
+	// A tag claiming a key nobody configured.
+	assert.ErrorIs(t, k.Verify(address("9"+tag[1:]), now), returnpath.ErrBadSignature)
+
+	// A day moved forward to dodge expiry.
+	assert.ErrorIs(t, k.Verify(address(tag[:1]+"999"+tag[4:]), now), returnpath.ErrBadSignature)
+
+	assert.ErrorIs(t, k.Verify(address("short"), now), returnpath.ErrMalformed)
+}
+
+func TestStaleAddressIsRefused(t *testing.T) {
+	k := mustNew(t, returnpath.Config{
+		Keys:   []returnpath.KeyConfig{{ID: "1", Secret: secretOne}},
+		MaxAge: 48 * time.Hour,
+	})
+	tag := k.Tag(email, messageID, now)
+
+	assert.NoError(t, k.Verify(address(tag), now.Add(48*time.Hour)))
+	assert.ErrorIs(t, k.Verify(address(tag), now.Add(72*time.Hour)), returnpath.ErrStale)
+	// A clock a day behind the signer's is not a reason to refuse.
+	assert.NoError(t, k.Verify(address(tag), now.Add(-24*time.Hour)))
+}
+
+func TestUnsignedAddressNeedsTheMigrationFlag(t *testing.T) {
+	keys := []returnpath.KeyConfig{{ID: "1", Secret: secretOne}}
+
+	strict := mustNew(t, returnpath.Config{Keys: keys})
+	assert.ErrorIs(t, strict.Verify(address(""), now), returnpath.ErrUnsigned)
+
+	migrating := mustNew(t, returnpath.Config{Keys: keys, AcceptUnsigned: true})
+	assert.NoError(t, migrating.Verify(address(""), now))
+	// Accepting the old form is not accepting a bad new one.
+	assert.ErrorIs(t, migrating.Verify(address("1000"+"0000000000000000"), now), returnpath.ErrBadSignature)
+}
+
+// A retired key stops signing at once and keeps verifying for MaxAge, the
+// grace period in which mail it signed can still bounce.
+func TestRetiredKeyVerifiesThroughItsGracePeriod(t *testing.T) {
+	before := mustNew(t, returnpath.Config{Keys: []returnpath.KeyConfig{{ID: "1", Secret: secretOne}}})
+	oldTag := before.Tag(email, messageID, now)
+
+	after := mustNew(t, returnpath.Config{Keys: []returnpath.KeyConfig{
+		{ID: "2", Secret: secretTwo},
+		{ID: "1", Secret: secretOne, RetiredAt: "2026-10-19"},
+	}})
+	assert.Equal(t, byte('2'), after.Tag(email, messageID, now)[0])
+
+	assert.NoError(t, after.Verify(address(oldTag), now.Add(24*time.Hour)))
+	assert.ErrorIs(t, after.Verify(address(oldTag), now.Add(8*24*time.Hour)), returnpath.ErrStale)
+}
+
+func TestKeyringWithoutKeysAcceptsEverything(t *testing.T) {
+	var nilKeyring *returnpath.Keyring
+	empty := mustNew(t, returnpath.Config{})
+
+	for _, k := range []*returnpath.Keyring{nilKeyring, empty} {
+		assert.False(t, k.Enabled())
+		assert.Empty(t, k.Tag(email, messageID, now))
+		assert.NoError(t, k.Verify(address(""), now))
+	}
+}
+
+func TestNonBounceAddressIsNotChecked(t *testing.T) {
+	k := mustNew(t, returnpath.Config{Keys: []returnpath.KeyConfig{{ID: "1", Secret: secretOne}}})
+	assert.NoError(t, k.Verify("postmaster@k.test.com", now))
+}
+
+func TestNewRefusesAMalformedSection(t *testing.T) {
+	for name, cfg := range map[string]returnpath.Config{
+		"long id":      {Keys: []returnpath.KeyConfig{{ID: "10", Secret: secretOne}}},
+		"short secret": {Keys: []returnpath.KeyConfig{{ID: "1", Secret: "short"}}},
+		"duplicate id": {Keys: []returnpath.KeyConfig{{ID: "1", Secret: secretOne}, {ID: "1", Secret: secretTwo}}},
+		"bad date":     {Keys: []returnpath.KeyConfig{{ID: "1", Secret: secretOne, RetiredAt: "yesterday"}}},
+		"all retired":  {Keys: []returnpath.KeyConfig{{ID: "1", Secret: secretOne, RetiredAt: "2026-10-19"}}},
+		"max age":      {Keys: []returnpath.KeyConfig{{ID: "1", Secret: secretOne}}, MaxAge: 600 * 24 * time.Hour},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := returnpath.New(cfg)
+			assert.Error(t, err)
+		})
+	}
+}
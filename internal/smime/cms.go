@@ -0,0 +1,265 @@
+package smime
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"slices"
+	"time"
+)
+
+// The object identifiers of the CMS subset written here.
+var (
+	oidData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidEnvelopedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3}
+
+	oidAttributeContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttributeMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttributeSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+
+	oidSHA256          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidRSAEncryption   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidAES256CBC       = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// contentInfo is the outermost CMS structure. Content is the [0] EXPLICIT
+// wrapper itself, built by hand: encoding/asn1 writes a RawValue as it is and
+// would drop the explicit tag a struct tag asked for.
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapsulatedContentInfo
+	// Certificates is the [0] IMPLICIT SET OF Certificate.
+	Certificates asn1.RawValue
+	SignerInfos  []signerInfo `asn1:"set"`
+}
+
+// encapsulatedContentInfo names the content type and leaves eContent out:
+// the signature is detached, and the content is the MIME part beside it.
+type encapsulatedContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+}
+
+type signerInfo struct {
+	Version         int
+	SID             issuerAndSerialNumber
+	DigestAlgorithm pkix.AlgorithmIdentifier
+	// SignedAttrs is the [0] IMPLICIT SET OF Attribute.
+	SignedAttrs        asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+type envelopedData struct {
+	Version              int
+	RecipientInfos       []keyTransRecipientInfo `asn1:"set"`
+	EncryptedContentInfo encryptedContentInfo
+}
+
+type keyTransRecipientInfo struct {
+	Version                int
+	RID                    issuerAndSerialNumber
+	KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedKey           []byte
+}
+
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0"`
+}
+
+// signDetached returns a DER ContentInfo holding SignedData over content,
+// which it does not include. The signature covers the signed attributes, as
+// RFC 5652 §5.4 requires once there are any: the content type, the signing
+// time and the content's SHA-256 digest.
+func signDetached(content []byte, chain []*x509.Certificate, key crypto.Signer, now time.Time) ([]byte, error) {
+	digest := sha256.Sum256(content)
+	attrs, err := signedAttributes(digest[:], now)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature is computed over the attributes encoded as a universal SET,
+	// not under the [0] tag they are carried with (RFC 5652 §5.4).
+	set, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: attrs})
+	if err != nil {
+		return nil, err
+	}
+	setDigest := sha256.Sum256(set)
+	signature, err := key.Sign(rand.Reader, setDigest[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	sigAlg, err := signatureAlgorithm(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []byte
+	for _, c := range chain {
+		certs = append(certs, c.Raw...)
+	}
+	leaf := chain[0]
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{{Algorithm: oidSHA256}},
+		EncapContentInfo: encapsulatedContentInfo{EContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certs},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SID:                issuerAndSerial(leaf),
+			DigestAlgorithm:    pkix.AlgorithmIdentifier{Algorithm: oidSHA256},
+			SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrs},
+			SignatureAlgorithm: sigAlg,
+			Signature:          signature,
+		}},
+	}
+	return wrapContentInfo(oidSignedData, sd)
+}
+
+// signedAttributes returns the encoded attributes, concatenated in the order
+// DER demands of a SET OF: sorted by their encodings.
+func signedAttributes(digest []byte, now time.Time) ([]byte, error) {
+	contentType, err := asn1.Marshal(oidData)
+	if err != nil {
+		return nil, err
+	}
+	// encoding/asn1 writes a time.Time as UTCTime up to 2049, which is what
+	// RFC 5652 §11.3 asks for, and in UTC only when it is given one.
+	signingTime, err := asn1.Marshal(now.UTC().Truncate(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	messageDigest, err := asn1.Marshal(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	var encoded [][]byte
+	for _, a := range []attribute{
+		{Type: oidAttributeContentType, Values: []asn1.RawValue{{FullBytes: contentType}}},
+		{Type: oidAttributeSigningTime, Values: []asn1.RawValue{{FullBytes: signingTime}}},
+		{Type: oidAttributeMessageDigest, Values: []asn1.RawValue{{FullBytes: messageDigest}}},
+	} {
+		b, err := asn1.Marshal(a)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, b)
+	}
+	slices.SortFunc(encoded, bytes.Compare)
+	return bytes.Join(encoded, nil), nil
+}
+
+func signatureAlgorithm(key crypto.Signer) (pkix.AlgorithmIdentifier, error) {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue}, nil
+	case *ecdsa.PrivateKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256}, nil
+	default:
+		return pkix.AlgorithmIdentifier{}, errors.New("smime: unsupported signing key")
+	}
+}
+
+// envelope returns a DER ContentInfo holding EnvelopedData: content
+// encrypted under a fresh AES-256-CBC key, and that key encrypted to the
+// recipient's RSA key with PKCS#1 v1.5 — the key transport RFC 8551 §2.7
+// requires every client to read.
+func envelope(content []byte, recipient *x509.Certificate) ([]byte, error) {
+	pub, ok := recipient.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("smime: only RSA certificates can be encrypted to")
+	}
+
+	key := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := pad(content, aes.BlockSize)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	encryptedKey, err := rsa.EncryptPKCS1v15(rand.Reader, pub, key)
+	if err != nil {
+		return nil, err
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+
+	ed := envelopedData{
+		Version: 0,
+		RecipientInfos: []keyTransRecipientInfo{{
+			Version:                0,
+			RID:                    issuerAndSerial(recipient),
+			KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue},
+			EncryptedKey:           encryptedKey,
+		}},
+		EncryptedContentInfo: encryptedContentInfo{
+			ContentType:                oidData,
+			ContentEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+			EncryptedContent:           ciphertext,
+		},
+	}
+	return wrapContentInfo(oidEnvelopedData, ed)
+}
+
+// pad applies the PKCS#7 padding CBC needs (RFC 5652 §6.3), returning a copy.
+func pad(b []byte, size int) []byte {
+	n := size - len(b)%size
+	out := make([]byte, len(b), len(b)+n)
+	copy(out, b)
+	return append(out, bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+func issuerAndSerial(c *x509.Certificate) issuerAndSerialNumber {
+	return issuerAndSerialNumber{Issuer: asn1.RawValue{FullBytes: c.RawIssuer}, SerialNumber: c.SerialNumber}
+}
+
+func wrapContentInfo(contentType asn1.ObjectIdentifier, content any) ([]byte, error) {
+	inner, err := asn1.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(contentInfo{
+		ContentType: contentType,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: inner},
+	})
+}
@@ -0,0 +1,149 @@
+package smime
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-message/textproto"
+)
+
+// Sign wraps a rendered message in multipart/signed with a detached
+// signature (RFC 8551 §3.5). The message's Content-* headers move into the
+// signed part with its body; every other header stays where it was, in the
+// order it was written, so what DKIM is about to sign is unchanged.
+func (s *Signer) Sign(msg []byte) ([]byte, error) {
+	outer, entity, err := splitEntity(msg)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signDetached(entity, s.chain, s.key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	boundary, err := newBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.Write(outer)
+	fmt.Fprintf(&b, "Content-Type: multipart/signed; protocol=\"application/pkcs7-signature\"; micalg=sha-256;\r\n boundary=\"%s\"\r\n", boundary)
+	b.WriteString("\r\nThis is a cryptographically signed message in MIME format.\r\n\r\n")
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	b.Write(entity)
+	fmt.Fprintf(&b, "\r\n--%s\r\n", boundary)
+	b.WriteString("Content-Type: application/pkcs7-signature; name=\"smime.p7s\"\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	b.WriteString("Content-Disposition: attachment; filename=\"smime.p7s\"\r\n")
+	b.WriteString("Content-Description: S/MIME Cryptographic Signature\r\n\r\n")
+	writeBase64(&b, signature)
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes(), nil
+}
+
+// Encrypt replaces a rendered message's body with an enveloped-data entity
+// only the holder of to's private key can open (RFC 8551 §3.3). Its Content-*
+// headers are encrypted with the body; the others stay outside.
+func Encrypt(msg []byte, to *x509.Certificate) ([]byte, error) {
+	outer, entity, err := splitEntity(msg)
+	if err != nil {
+		return nil, err
+	}
+	enveloped, err := envelope(entity, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.Write(outer)
+	b.WriteString("Content-Type: application/pkcs7-mime; smime-type=enveloped-data; name=\"smime.p7m\"\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	b.WriteString("Content-Disposition: attachment; filename=\"smime.p7m\"\r\n\r\n")
+	writeBase64(&b, enveloped)
+	return b.Bytes(), nil
+}
+
+// splitEntity separates a message into the header fields that stay on the
+// outside, raw and in order and without the blank line that ends them, and
+// the MIME entity that gets signed or encrypted: the Content-* fields and
+// the body, in canonical CRLF form (RFC 8551 §3.1.1). A message with no
+// Content-Type gets the text/plain default RFC 2045 would have read it as,
+// so that the entity says what it is once it is nested.
+func splitEntity(msg []byte) (outer, entity []byte, err error) {
+	r := bufio.NewReader(bytes.NewReader(msg))
+	h, err := textproto.ReadHeader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("smime: cannot read the message header: %w", err)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out, in bytes.Buffer
+	hasMIMEVersion := false
+	hasContentType := false
+	fields := h.Fields()
+	for fields.Next() {
+		raw, err := fields.Raw()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := strings.ToLower(fields.Key())
+		switch {
+		case strings.HasPrefix(key, "content-"):
+			hasContentType = hasContentType || key == "content-type"
+			in.Write(raw)
+		default:
+			hasMIMEVersion = hasMIMEVersion || key == "mime-version"
+			out.Write(raw)
+		}
+	}
+	if !hasMIMEVersion {
+		out.WriteString("MIME-Version: 1.0\r\n")
+	}
+	if !hasContentType {
+		in.WriteString("Content-Type: text/plain; charset=us-ascii\r\n")
+	}
+	in.WriteString("\r\n")
+	in.Write(body)
+	return canonicalize(out.Bytes()), canonicalize(in.Bytes()), nil
+}
+
+// canonicalize turns every line ending into CRLF. What is signed must be
+// what a verifier reconstructs from the wire, and the wire has CRLF; the
+// outer header is given the same treatment so the two do not mix.
+func canonicalize(b []byte) []byte {
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(b, []byte("\n"), []byte("\r\n"))
+}
+
+func newBoundary() (string, error) {
+	var raw [16]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", err
+	}
+	return "smime-" + hex.EncodeToString(raw[:]), nil
+}
+
+// writeBase64 writes b base64-encoded in lines of 76 characters, the limit
+// RFC 2045 sets for the encoding.
+func writeBase64(w *bytes.Buffer, b []byte) {
+	const lineLen = 76
+	enc := base64.StdEncoding.EncodeToString(b)
+	for len(enc) > lineLen {
+		w.WriteString(enc[:lineLen])
+		w.WriteString("\r\n")
+		enc = enc[lineLen:]
+	}
+	w.WriteString(enc)
+	w.WriteString("\r\n")
+}
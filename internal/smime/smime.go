@@ -0,0 +1,257 @@
+// Package smime signs and encrypts outgoing mail with S/MIME (RFC 8551): the
+// Domain's certificate signs every message it sends, and a Recipient who
+// supplied a certificate of their own gets the message encrypted to it.
+//
+// Signing wraps the rendered message in multipart/signed with a detached
+// PKCS#7 (CMS, RFC 5652) signature, so a client that does not speak S/MIME
+// still shows the message as it was, with an attachment it can ignore.
+// Encrypting replaces the whole body with an application/pkcs7-mime
+// enveloped-data entity. A message that is both is signed first and then
+// encrypted, as RFC 8551 §3.7 recommends, so the signature is not exposed to
+// anyone who cannot read the message.
+//
+// Only the body is protected. The headers a transport needs — From, To,
+// Subject, Date, Message-ID and the rest — stay outside, where DKIM signs
+// them after this package is done.
+//
+// The CMS structures are written here with encoding/asn1 rather than taken
+// from a library: the subset is small (one signer, one recipient, SHA-256,
+// AES-256-CBC, RSA key transport) and pinned, and is what every client in
+// use reads.
+package smime
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCertificate is a certificate, or a certificate and key, that
+// cannot be used for what it was supplied for. The wrapped message says why.
+var ErrInvalidCertificate = errors.New("invalid S/MIME certificate")
+
+// minRSABits is the shortest RSA key accepted, for signing and for key
+// transport alike: RFC 8551 §4.1 calls anything shorter unacceptable.
+const minRSABits = 2048
+
+// Credentials are a Domain's signing certificate and key, as stored: the
+// certificate chain in PEM, leaf first, and the private key as an
+// unencrypted PKCS#8 PEM block. The zero value signs nothing.
+type Credentials struct {
+	Certificate string
+	PrivateKey  string
+}
+
+// IsZero reports whether the Credentials hold nothing to sign with.
+func (c Credentials) IsZero() bool { return c == Credentials{} }
+
+// ImportCredentials checks a certificate chain and private key uploaded for a
+// Domain and returns them in the form they are stored in.
+//
+// The leaf must be valid at now, carry the private key's public half, and be
+// usable for signing mail: its key usage, when it states one, must allow
+// digital signatures, and its extended key usage, when it states one, must
+// allow email protection. The key must be RSA of at least 2048 bits or ECDSA
+// on P-256, P-384 or P-521. The chain is not verified against any root:
+// which roots a recipient trusts is the recipient's business, and a
+// certificate from a private CA is a legitimate thing to sign with.
+func ImportCredentials(certPEM, keyPEM string, now time.Time) (Credentials, error) {
+	chain, err := parseCertificates(certPEM)
+	if err != nil {
+		return Credentials{}, err
+	}
+	leaf := chain[0]
+	if err := checkValidity(leaf, now); err != nil {
+		return Credentials{}, err
+	}
+	if leaf.KeyUsage != 0 && leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return Credentials{}, fmt.Errorf("%w: the certificate's key usage does not allow signing", ErrInvalidCertificate)
+	}
+	if err := checkEmailProtection(leaf); err != nil {
+		return Credentials{}, err
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if err := checkSigningKey(key); err != nil {
+		return Credentials{}, err
+	}
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(leaf.PublicKey) {
+		return Credentials{}, fmt.Errorf("%w: the private key does not belong to the certificate", ErrInvalidCertificate)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+	var certs strings.Builder
+	for _, c := range chain {
+		certs.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}))
+	}
+	return Credentials{
+		Certificate: certs.String(),
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+	}, nil
+}
+
+// Signer signs messages with one Domain's Credentials.
+type Signer struct {
+	chain []*x509.Certificate
+	key   crypto.Signer
+}
+
+// Signer parses the Credentials into a Signer. It does not check the
+// certificate's validity again: that was checked when it was uploaded, and a
+// certificate that has since expired still signs, so that the message goes
+// out and the recipient's client says what is wrong with it.
+func (c Credentials) Signer() (*Signer, error) {
+	chain, err := parseCertificates(c.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(c.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{chain: chain, key: key}, nil
+}
+
+// Certificate is the certificate the Signer signs as.
+func (s *Signer) Certificate() *x509.Certificate { return s.chain[0] }
+
+// ParseRecipientCertificate checks a certificate a Recipient supplied to have
+// their mail encrypted to. It must be a single PEM certificate, valid at now,
+// with an RSA key of at least 2048 bits that its key usage, when it states
+// one, allows to encipher keys, and an extended key usage, when it states
+// one, that allows email protection.
+//
+// RSA only, because key transport is the one recipient type every S/MIME
+// client implements; a certificate for an elliptic-curve key would need key
+// agreement, which is not implemented here.
+func ParseRecipientCertificate(certPEM string, now time.Time) (*x509.Certificate, error) {
+	certs, err := parseCertificates(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) != 1 {
+		return nil, fmt.Errorf("%w: want exactly one certificate, got %d", ErrInvalidCertificate, len(certs))
+	}
+	cert := certs[0]
+	if err := checkValidity(cert, now); err != nil {
+		return nil, err
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: only RSA certificates can be encrypted to", ErrInvalidCertificate)
+	}
+	if pub.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("%w: the RSA key is shorter than %d bits", ErrInvalidCertificate, minRSABits)
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageKeyEncipherment == 0 {
+		return nil, fmt.Errorf("%w: the certificate's key usage does not allow key encipherment", ErrInvalidCertificate)
+	}
+	if err := checkEmailProtection(cert); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+func parseCertificates(certPEM string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(strings.TrimSpace(certPEM))
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil || block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("%w: want PEM CERTIFICATE blocks only", ErrInvalidCertificate)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+		}
+		certs = append(certs, cert)
+		rest = []byte(strings.TrimSpace(string(rest)))
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%w: no certificate", ErrInvalidCertificate)
+	}
+	return certs, nil
+}
+
+func parsePrivateKey(keyPEM string) (crypto.Signer, error) {
+	block, rest := pem.Decode([]byte(strings.TrimSpace(keyPEM)))
+	if block == nil || len(strings.TrimSpace(string(rest))) > 0 || len(block.Headers) > 0 {
+		return nil, fmt.Errorf("%w: want one unencrypted PEM private key", ErrInvalidCertificate)
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: unsupported PEM block %q", ErrInvalidCertificate, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported private key type %T", ErrInvalidCertificate, key)
+	}
+	return signer, nil
+}
+
+func checkSigningKey(key crypto.Signer) error {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSABits {
+			return fmt.Errorf("%w: the RSA key is shorter than %d bits", ErrInvalidCertificate, minRSABits)
+		}
+		return nil
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+			return nil
+		}
+		return fmt.Errorf("%w: unsupported elliptic curve", ErrInvalidCertificate)
+	default:
+		return fmt.Errorf("%w: only RSA and ECDSA keys can sign", ErrInvalidCertificate)
+	}
+}
+
+func checkValidity(cert *x509.Certificate, now time.Time) error {
+	if now.Before(cert.NotBefore) {
+		return fmt.Errorf("%w: the certificate is not valid before %s", ErrInvalidCertificate, cert.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(cert.NotAfter) {
+		return fmt.Errorf("%w: the certificate expired on %s", ErrInvalidCertificate, cert.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func checkEmailProtection(cert *x509.Certificate) error {
+	if len(cert.ExtKeyUsage) == 0 && len(cert.UnknownExtKeyUsage) == 0 {
+		return nil
+	}
+	for _, u := range cert.ExtKeyUsage {
+		if u == x509.ExtKeyUsageEmailProtection || u == x509.ExtKeyUsageAny {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: the certificate's extended key usage does not allow email protection", ErrInvalidCertificate)
+}
@@ -0,0 +1,100 @@
+package smtp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// CircuitBreaker says which MX hosts are not worth dialling because they have
+// stopped answering, from what the Sender tells it of each host it dials.
+// internal/circuit is one, shared across replicas.
+type CircuitBreaker interface {
+	// Allow reports whether mx may be dialled now.
+	Allow(mx string) bool
+	// Record tells it how dialling mx went: failure is why the host did not
+	// answer, nil when it did — with a refusal as much as with an
+	// acceptance.
+	Record(mx string, failure error)
+	// Stats are its counters since it was created.
+	Stats() CircuitStats
+}
+
+// CircuitStats are a CircuitBreaker's counters.
+type CircuitStats struct {
+	// Skipped are MX hosts passed over without being dialled.
+	Skipped uint64
+	// Failures are MX hosts that did not answer.
+	Failures uint64
+	// Opened are circuits opened, by failures or by a probe that failed.
+	Opened uint64
+	// Probes are messages let through to a host whose circuit was open.
+	Probes uint64
+	// Recovered are circuits closed by a host that answered again.
+	Recovered uint64
+}
+
+// CircuitReporter is a Sender that holds MX hosts to a CircuitBreaker and can
+// say how often it did. The SMTPSender logs it.
+type CircuitReporter interface {
+	CircuitStats() CircuitStats
+}
+
+// WithCircuitBreaker passes over the MX hosts b says not to dial. Without it
+// every host is dialled, however long it has gone without answering. Relays
+// are not held to it: a relay that does not answer has no next host to fall
+// back to.
+func WithCircuitBreaker(b CircuitBreaker) SenderOption {
+	return func(s *sender) {
+		s.circuits = b
+	}
+}
+
+// CircuitStats implements CircuitReporter: zero without a CircuitBreaker.
+func (s *sender) CircuitStats() CircuitStats {
+	if s.circuits == nil {
+		return CircuitStats{}
+	}
+	return s.circuits.Stats()
+}
+
+// circuitAllows reports whether mx may be dialled.
+func (s *sender) circuitAllows(mx string) bool {
+	return s.circuits == nil || s.circuits.Allow(mx)
+}
+
+// recordCircuit tells the CircuitBreaker how dialling mx ended, when it ended
+// in a way that says whether the host answers: a name that would not resolve
+// says nothing of the host.
+func (s *sender) recordCircuit(mx string, err *smtpError) {
+	switch {
+	case s.circuits == nil:
+	case err.unreachable():
+		s.circuits.Record(mx, err)
+	case err == nil || err.reply != "" || (err.stage != StageConnect && err.stage != ""):
+		s.circuits.Record(mx, nil)
+	}
+}
+
+// circuitOpenError is an MX host passed over because its circuit is open:
+// transient, and reported as a host that did not answer, which is what the
+// circuit knows of it.
+func circuitOpenError(mx string) *smtpError {
+	return sessionError(fmt.Errorf("%s has stopped answering, not dialling it until its circuit closes", mx), StageConnect, "4.4.1")
+}
+
+// unreachable reports whether e is an MX host not answering at all: a
+// connection that could not be opened or that never drew a greeting, or a
+// session that timed out waiting for a reply. Any reply — a refusal included
+// — is a host that is up, and a host that cannot be resolved, or secured as
+// its policy requires, is not one a circuit would spare a wait for.
+func (e *smtpError) unreachable() bool {
+	if e == nil || e.reply != "" || e.tlsFailure != "" {
+		return false
+	}
+	if e.stage == StageConnect && (e.enhanced == "4.4.1" || e.enhanced == "4.4.2") {
+		return true
+	}
+	var ne net.Error
+	return errors.As(e.err, &ne) && ne.Timeout()
+}
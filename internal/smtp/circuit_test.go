@@ -0,0 +1,86 @@
+package smtp
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBreaker keeps the circuits it is told are open, and what it was told of
+// each host dialled.
+type fakeBreaker struct {
+	mu      sync.Mutex
+	open    map[string]bool
+	records map[string][]error
+}
+
+func (b *fakeBreaker) Allow(mx string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.open[mx]
+}
+
+func (b *fakeBreaker) Record(mx string, failure error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.records == nil {
+		b.records = make(map[string][]error)
+	}
+	b.records[mx] = append(b.records[mx], failure)
+}
+
+func (b *fakeBreaker) Stats() CircuitStats { return CircuitStats{} }
+
+// deadMX is a loopback address nothing listens on, where a dial is refused.
+const deadMX = "127.0.0.2"
+
+func TestCircuitsPassOverHostsThatStoppedAnswering(t *testing.T) {
+	s, rcv := newExtensionSender(t, 0, false)
+	s.resolver = &resolver.Fake{MX: map[string][]*net.MX{
+		"mx.test": {{Host: deadMX, Pref: 10}, {Host: loopbackMX, Pref: 20}},
+	}}
+	b := &fakeBreaker{open: map[string]bool{}}
+	s.circuits = b
+	body := BytesBody([]byte("hi\r\n"))
+
+	_, err := s.Send("bounce@k.sender.test", "to@mx.test", body, SendOptions{})
+	require.Nil(t, err)
+	require.Len(t, b.records[deadMX], 1)
+	assert.Error(t, b.records[deadMX][0], "a refused dial is a host not answering")
+	assert.Equal(t, []error{nil}, b.records[loopbackMX], "a host that answered is recorded as such")
+
+	b.open[deadMX] = true
+	_, err = s.Send("bounce@k.sender.test", "to@mx.test", body, SendOptions{})
+	require.Nil(t, err)
+	assert.Len(t, b.records[deadMX], 1, "a host whose circuit is open is not dialled")
+	assert.Equal(t, 2, rcv.accepted())
+
+	b.open[loopbackMX] = true
+	sent, err := s.Send("bounce@k.sender.test", "to@mx.test", body, SendOptions{})
+	require.NotNil(t, err)
+	assert.False(t, err.IsPermanent(), "the message is tried again once a circuit closes")
+	assert.Equal(t, "4.4.1", sent.Transcript.EnhancedCode)
+	assert.Equal(t, 2, rcv.accepted())
+}
+
+func TestUnreachable(t *testing.T) {
+	assert.True(t, sessionError(assert.AnError, StageConnect, "4.4.1").unreachable(), "a dial that failed")
+	assert.True(t, sessionError(timeoutError{}, StageDATA, "4.4.2").unreachable(), "a session that timed out")
+	assert.False(t, sessionError(assert.AnError, StageConnect, "4.4.3").unreachable(), "a name that would not resolve")
+	assert.False(t, replyError(StageRCPT, &net.OpError{Op: "read", Err: assert.AnError}).unreachable(), "a connection reset is not a timeout")
+	refusal := newSMTPError(assert.AnError, false, 421).status("4.7.0")
+	refusal.reply = "421 4.7.0 try again later"
+	assert.False(t, refusal.unreachable(), "a host that refuses is up")
+	var none *smtpError
+	assert.False(t, none.unreachable())
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
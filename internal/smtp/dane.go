@@ -0,0 +1,416 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/resolver"
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/net/idna"
+)
+
+const (
+	// typeTLSA is the TLSA record type (RFC 6698), which dnsmessage has no
+	// constant for.
+	typeTLSA dnsmessage.Type = 52
+
+	// daneQueryTimeout bounds one exchange with the validating resolver.
+	daneQueryTimeout = 5 * time.Second
+	// daneMinTTL and daneMaxTTL bound how long an answer is kept, whatever its
+	// TTL says; daneNegativeTTL is how long a host without usable records, or
+	// whose records could not be trusted, is remembered as such.
+	daneMinTTL      = time.Minute
+	daneMaxTTL      = time.Hour
+	daneNegativeTTL = 5 * time.Minute
+	// daneCacheMax bounds how many MX hosts are remembered, as stsCacheMax.
+	daneCacheMax = 10000
+)
+
+// errTLSAMismatch is a certificate none of the host's TLSA records matches.
+var errTLSAMismatch = errors.New("certificate matches none of the TLSA records")
+
+// tlsaRecord is one TLSA record (RFC 6698 §2.1).
+type tlsaRecord struct {
+	usage    uint8
+	selector uint8
+	matching uint8
+	data     []byte
+}
+
+// TLSA certificate usages DANE for SMTP uses (RFC 7672 §3.1): a trust anchor
+// the chain must lead to, or the host's own certificate.
+const (
+	tlsaUsageDANETA = 2
+	tlsaUsageDANEEE = 3
+)
+
+// usable reports whether the record is one DANE for SMTP applies: PKIX usages
+// are not (RFC 7672 §3.1.3), and a digest of the wrong length matches nothing.
+func (r tlsaRecord) usable() bool {
+	if r.usage != tlsaUsageDANETA && r.usage != tlsaUsageDANEEE {
+		return false
+	}
+	if r.selector > 1 {
+		return false
+	}
+	switch r.matching {
+	case 0:
+		return len(r.data) > 0
+	case 1:
+		return len(r.data) == sha256.Size
+	case 2:
+		return len(r.data) == sha512.Size
+	}
+	return false
+}
+
+// matches reports whether c is the certificate, or holds the key, r names.
+func (r tlsaRecord) matches(c *x509.Certificate) bool {
+	data := c.Raw
+	if r.selector == 1 {
+		data = c.RawSubjectPublicKeyInfo
+	}
+	switch r.matching {
+	case 1:
+		sum := sha256.Sum256(data)
+		data = sum[:]
+	case 2:
+		sum := sha512.Sum512(data)
+		data = sum[:]
+	}
+	return bytes.Equal(data, r.data)
+}
+
+// daneVerifier checks the certificate a host presented against its TLSA
+// records (RFC 7672 §3.1): a DANE-EE record must match the host's own
+// certificate, whose names and dates are then beside the point; a DANE-TA
+// record must match a certificate of the chain the host's certificate is then
+// verified up to, names included.
+func daneVerifier(records []tlsaRecord, name string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		certs := cs.PeerCertificates
+		if len(certs) == 0 {
+			return errTLSAMismatch
+		}
+		for _, r := range records {
+			switch r.usage {
+			case tlsaUsageDANEEE:
+				if r.matches(certs[0]) {
+					return nil
+				}
+			case tlsaUsageDANETA:
+				for _, anchor := range certs {
+					if !r.matches(anchor) {
+						continue
+					}
+					roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+					roots.AddCert(anchor)
+					for _, c := range certs[1:] {
+						intermediates.AddCert(c)
+					}
+					if _, err := certs[0].Verify(x509.VerifyOptions{
+						DNSName:       name,
+						Roots:         roots,
+						Intermediates: intermediates,
+					}); err == nil {
+						return nil
+					}
+				}
+			}
+		}
+		return errTLSAMismatch
+	}
+}
+
+// tlsaAnswer is what the resolver said about a host's TLSA records: the usable
+// ones, empty when the host has none or its zone is not signed.
+type tlsaAnswer struct {
+	records []tlsaRecord
+	ttl     time.Duration
+}
+
+// mxAnswer is what the resolver said about a domain's MX records: whether the
+// answer was validated, and the hosts it names.
+type mxAnswer struct {
+	validated bool
+	hosts     []string
+	ttl       time.Duration
+}
+
+// daneCache keeps each MX host's TLSA records, and each recipient domain's
+// validated MX hosts, for their TTL.
+type daneCache struct {
+	query   func(ctx context.Context, name string) (tlsaAnswer, error)
+	mxQuery func(ctx context.Context, domain string) (mxAnswer, error)
+	now     func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]daneEntry
+	mxEntries map[string]mxEntry
+}
+
+type daneEntry struct {
+	records []tlsaRecord
+	err     error
+	expires time.Time
+}
+
+type mxEntry struct {
+	ans     mxAnswer
+	err     error
+	expires time.Time
+}
+
+func newDANECache(
+	query func(ctx context.Context, name string) (tlsaAnswer, error),
+	mxQuery func(ctx context.Context, domain string) (mxAnswer, error),
+) *daneCache {
+	return &daneCache{
+		query:     query,
+		mxQuery:   mxQuery,
+		now:       time.Now,
+		entries:   map[string]daneEntry{},
+		mxEntries: map[string]mxEntry{},
+	}
+}
+
+// lookup returns the usable TLSA records of mx, an MX host of domain. DANE
+// applies only to a host the domain's validated MX records name (RFC 7672
+// §2.2.2): the host's own signed zone says nothing of whether mail for the
+// domain belongs there, so a host taken from an MX answer that was not
+// validated has no records to be held to. A validated answer that does not
+// name mx, or one that could not be had or trusted, rules the host out, as
+// its TLSA records do.
+func (c *daneCache) lookup(ctx context.Context, domain, mx string) ([]tlsaRecord, error) {
+	validated, err := c.validatedMX(ctx, domain, mx)
+	if err != nil || !validated {
+		return nil, err
+	}
+	return c.tlsa(ctx, mx)
+}
+
+// validatedMX reports whether domain's MX records were validated and name mx.
+// A domain whose validated answer has no MX records is its own host (RFC 5321
+// §5.1).
+func (c *daneCache) validatedMX(ctx context.Context, domain, mx string) (bool, error) {
+	if d, err := idna.Lookup.ToASCII(domain); err == nil {
+		domain = d
+	}
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	mx = strings.ToLower(strings.TrimSuffix(mx, "."))
+	now := c.now()
+
+	c.mu.Lock()
+	e, ok := c.mxEntries[domain]
+	c.mu.Unlock()
+	if !ok || !now.Before(e.expires) {
+		ans, err := c.mxQuery(ctx, domain+".")
+		if err != nil {
+			e = mxEntry{err: err, expires: now.Add(daneMinTTL)}
+		} else {
+			e = mxEntry{ans: ans, expires: now.Add(min(max(ans.ttl, daneMinTTL), daneMaxTTL))}
+		}
+		c.mu.Lock()
+		if _, ok := c.mxEntries[domain]; !ok && len(c.mxEntries) >= daneCacheMax {
+			c.mxEntries = map[string]mxEntry{}
+		}
+		c.mxEntries[domain] = e
+		c.mu.Unlock()
+	}
+
+	if e.err != nil {
+		return false, fmt.Errorf("MX records of %s cannot be trusted: %w", domain, e.err)
+	}
+	if !e.ans.validated {
+		return false, nil
+	}
+	hosts := e.ans.hosts
+	if len(hosts) == 0 {
+		hosts = []string{domain}
+	}
+	if !slices.Contains(hosts, mx) {
+		return false, fmt.Errorf("%s is not among the validated MX hosts of %s", mx, domain)
+	}
+	return true, nil
+}
+
+// tlsa returns mx's usable TLSA records — none for a host that has none or
+// whose zone is unsigned, which is then not held to DANE — or an error when the
+// answer could not be had or could not be trusted, which rules the host out:
+// a host whose records an attacker can suppress must not lose its protection
+// by their doing so (RFC 7672 §2.2).
+func (c *daneCache) tlsa(ctx context.Context, mx string) ([]tlsaRecord, error) {
+	mx = strings.ToLower(strings.TrimSuffix(mx, "."))
+	now := c.now()
+
+	c.mu.Lock()
+	e, ok := c.entries[mx]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.records, e.err
+	}
+
+	ans, err := c.query(ctx, "_25._tcp."+mx+".")
+	if err != nil {
+		e = daneEntry{err: err, expires: now.Add(daneMinTTL)}
+	} else {
+		ttl := min(max(ans.ttl, daneMinTTL), daneMaxTTL)
+		if len(ans.records) == 0 {
+			ttl = daneNegativeTTL
+		}
+		e = daneEntry{records: ans.records, expires: now.Add(ttl)}
+	}
+
+	c.mu.Lock()
+	if _, ok := c.entries[mx]; !ok && len(c.entries) >= daneCacheMax {
+		c.entries = map[string]daneEntry{}
+	}
+	c.entries[mx] = e
+	c.mu.Unlock()
+	return e.records, e.err
+}
+
+// newTLSAQuery asks the resolver at addr for TLSA records with the DNSSEC OK bit, and
+// trusts its AD bit to say the answer was validated: an answer without it is
+// from an unsigned zone, and counts as no records at all (RFC 7672 §2.2).
+// SERVFAIL is what a validating resolver answers for a bogus zone, and is an
+// error.
+func newTLSAQuery(addr string) func(ctx context.Context, name string) (tlsaAnswer, error) {
+	return func(ctx context.Context, name string) (tlsaAnswer, error) {
+		resp, err := dnssecExchange(ctx, addr, name, typeTLSA)
+		if err != nil {
+			return tlsaAnswer{}, err
+		}
+		return readTLSAAnswer(resp)
+	}
+}
+
+// newMXQuery asks the resolver at addr for a domain's MX records the same way,
+// to learn whether the hosts mail is sent to were validated.
+func newMXQuery(addr string) func(ctx context.Context, domain string) (mxAnswer, error) {
+	return func(ctx context.Context, domain string) (mxAnswer, error) {
+		resp, err := dnssecExchange(ctx, addr, domain, dnsmessage.TypeMX)
+		if err != nil {
+			return mxAnswer{}, err
+		}
+		return readMXAnswer(resp)
+	}
+}
+
+// dnssecExchange sends one query with the DNSSEC OK bit to the resolver at
+// addr, over TCP when the UDP answer was truncated.
+func dnssecExchange(ctx context.Context, addr, name string, typ dnsmessage.Type) (dnsmessage.Message, error) {
+	q, id, err := buildDNSSECQuery(name, typ)
+	if err != nil {
+		return dnsmessage.Message{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, daneQueryTimeout)
+	defer cancel()
+	resp, err := resolver.Exchange(ctx, "udp", addr, q, id)
+	if err == nil && resp.Header.Truncated {
+		resp, err = resolver.Exchange(ctx, "tcp", addr, q, id)
+	}
+	return resp, err
+}
+
+func buildDNSSECQuery(name string, typ dnsmessage.Type) ([]byte, uint16, error) {
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	var idb [2]byte
+	_, _ = rand.Read(idb[:])
+	id := binary.BigEndian.Uint16(idb[:])
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, RecursionDesired: true, AuthenticData: true})
+	if err := b.StartQuestions(); err != nil {
+		return nil, 0, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: qname, Type: typ, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, 0, err
+	}
+	if err := b.StartAdditionals(); err != nil {
+		return nil, 0, err
+	}
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(1232, dnsmessage.RCodeSuccess, true); err != nil {
+		return nil, 0, err
+	}
+	if err := b.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+		return nil, 0, err
+	}
+	msg, err := b.Finish()
+	return msg, id, err
+}
+
+// readTLSAAnswer reads the usable TLSA records out of a resolver's answer. A
+// host whose records are all unusable is treated as one without records.
+func readTLSAAnswer(m dnsmessage.Message) (tlsaAnswer, error) {
+	switch m.Header.RCode {
+	case dnsmessage.RCodeSuccess, dnsmessage.RCodeNameError:
+	default:
+		return tlsaAnswer{}, fmt.Errorf("resolver answered %v", m.Header.RCode)
+	}
+	if !m.Header.AuthenticData || m.Header.RCode == dnsmessage.RCodeNameError {
+		return tlsaAnswer{}, nil
+	}
+
+	var ans tlsaAnswer
+	for _, rr := range m.Answers {
+		if rr.Header.Type != typeTLSA {
+			continue
+		}
+		raw, ok := rr.Body.(*dnsmessage.UnknownResource)
+		if !ok || len(raw.Data) < 3 {
+			continue
+		}
+		r := tlsaRecord{usage: raw.Data[0], selector: raw.Data[1], matching: raw.Data[2], data: raw.Data[3:]}
+		if !r.usable() {
+			continue
+		}
+		ttl := time.Duration(rr.Header.TTL) * time.Second
+		if len(ans.records) == 0 || ttl < ans.ttl {
+			ans.ttl = ttl
+		}
+		ans.records = append(ans.records, r)
+	}
+	return ans, nil
+}
+
+// readMXAnswer reads the MX hosts out of a resolver's answer, lower-cased and
+// without the trailing dot. A null MX names no host.
+func readMXAnswer(m dnsmessage.Message) (mxAnswer, error) {
+	switch m.Header.RCode {
+	case dnsmessage.RCodeSuccess, dnsmessage.RCodeNameError:
+	default:
+		return mxAnswer{}, fmt.Errorf("resolver answered %v", m.Header.RCode)
+	}
+	ans := mxAnswer{validated: m.Header.AuthenticData, ttl: daneNegativeTTL}
+	first := true
+	for _, rr := range m.Answers {
+		mx, ok := rr.Body.(*dnsmessage.MXResource)
+		if !ok {
+			continue
+		}
+		ttl := time.Duration(rr.Header.TTL) * time.Second
+		if first || ttl < ans.ttl {
+			ans.ttl, first = ttl, false
+		}
+		if host := strings.ToLower(strings.TrimSuffix(mx.MX.String(), ".")); host != "" {
+			ans.hosts = append(ans.hosts, host)
+		}
+	}
+	return ans, nil
+}
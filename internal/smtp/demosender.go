@@ -9,11 +9,11 @@ type demoSender struct {
 	Hostname string
 }
 
-func (s *demoSender) Send(from, to string, msg []byte) SenderError {
+func (s *demoSender) Send(from, to string, body Body, _ SendOptions) (Sent, SenderError) {
 	if strings.Contains(to, "error") {
-		return newSMTPError(errors.New("error sending email"), true, 512)
+		return Sent{}, newSMTPError(errors.New("error sending email"), true, 512)
 	}
-	return nil
+	return Sent{}, nil
 }
 
 func (s *demoSender) SenderName() string {
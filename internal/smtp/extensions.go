@@ -0,0 +1,84 @@
+package smtp
+
+import (
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/kannon-email/kannon/internal/utils"
+
+	"golang.org/x/net/idna"
+)
+
+// mailParams is what a message's MAIL FROM says on a session, chosen from the
+// extensions its EHLO offered: the parameters, and the reverse-path and
+// recipient as they are written there. A message the session cannot take — one
+// larger than its SIZE, or one that needs SMTPUTF8 where it is not offered —
+// fails here, permanently, before any of it is sent: no attempt later can
+// change what the receiver offers, and it is the receiver's refusal that would
+// otherwise come, after the whole body had gone over the wire.
+//
+// The failure is reported at StageMAIL, with no reply; it does not fall back
+// to the domain's next MX host, as a refusal of the message would not.
+func mailParams(c *smtp.Client, from, to string, opts SendOptions) (params, mailFrom, rcptTo string, err *smtpError) {
+	// RFC 1870: a receiver states the largest message it takes, and is told
+	// the size of each, so that it can refuse one without reading it.
+	if ok, arg := c.Extension("SIZE"); ok && opts.Size > 0 {
+		if limit, perr := strconv.ParseInt(strings.TrimSpace(arg), 10, 64); perr == nil && limit > 0 && opts.Size > limit {
+			return "", "", "", mailError(fmt.Errorf("message of %d bytes exceeds the receiver's SIZE of %d", opts.Size, limit), 552, "5.3.4")
+		}
+		params += fmt.Sprintf(" SIZE=%d", opts.Size)
+	}
+
+	// The parameter net/smtp's Mail has always added for a receiver that
+	// offers it.
+	if ok, _ := c.Extension("8BITMIME"); ok {
+		params += " BODY=8BITMIME"
+	}
+
+	// RFC 6531: an internationalised local part, or a header the Builder
+	// could not encode, can only be handed to a receiver that offers
+	// SMTPUTF8. A domain can always be written as its A-label, and is, unless
+	// the message goes over SMTPUTF8 anyway.
+	utf8Addrs := !utils.IsASCII(localPart(from)) || !utils.IsASCII(localPart(to))
+	if !utf8Addrs && !opts.SMTPUTF8 {
+		return params, asciiDomain(from), asciiDomain(to), nil
+	}
+	if ok, _ := c.Extension("SMTPUTF8"); !ok {
+		if utf8Addrs {
+			return "", "", "", mailError(errors.New("receiver does not offer SMTPUTF8, which the message's addresses need"), 553, "5.6.7")
+		}
+		return "", "", "", mailError(errors.New("receiver does not offer SMTPUTF8, which the message's header needs"), 553, "5.6.9")
+	}
+	return params + " SMTPUTF8", from, to, nil
+}
+
+// mailError is a message mailParams refuses to send on the session.
+func mailError(err error, code uint32, enhanced string) *smtpError {
+	e := newSMTPError(err, true, code).status(enhanced)
+	e.stage = StageMAIL
+	return e
+}
+
+func localPart(addr string) string {
+	if at := strings.LastIndexByte(addr, '@'); at >= 0 {
+		return addr[:at]
+	}
+	return addr
+}
+
+// asciiDomain is addr with its domain as an A-label, or addr as it is when
+// the domain has none.
+func asciiDomain(addr string) string {
+	at := strings.LastIndexByte(addr, '@')
+	if at < 0 || utils.IsASCII(addr[at+1:]) {
+		return addr
+	}
+	d, err := idna.Lookup.ToASCII(addr[at+1:])
+	if err != nil {
+		return addr
+	}
+	return addr[:at+1] + d
+}
@@ -0,0 +1,79 @@
+package smtp
+
+import (
+	"net"
+	"testing"
+
+	gosmtp "github.com/emersion/go-smtp"
+	"github.com/kannon-email/kannon/internal/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExtensionSender is a Sender whose MX hosts are one plaintext receiver
+// offering SIZE with maxBytes, and SMTPUTF8 when smtputf8 is set.
+func newExtensionSender(t *testing.T, maxBytes int64, smtputf8 bool) (*sender, *receiver) {
+	t.Helper()
+
+	rcv := &receiver{}
+	addr := startReceiver(t, rcv, withServer(func(srv *gosmtp.Server) {
+		srv.MaxMessageBytes = maxBytes
+		srv.EnableSMTPUTF8 = smtputf8
+	}))
+
+	s := newLoopbackSender(t, addr)
+	loopback := []*net.MX{{Host: loopbackMX, Pref: 10}}
+	s.resolver = &resolver.Fake{MX: map[string][]*net.MX{"mx.test": loopback, "xn--fsqu00a.test": loopback}}
+	return s, rcv
+}
+
+func TestSizeIsDeclaredAndEnforced(t *testing.T) {
+	s, rcv := newExtensionSender(t, 100, false)
+	body := BytesBody([]byte("hi\r\n"))
+
+	sent, err := s.Send("bounce@k.sender.test", "to@mx.test", body, SendOptions{Size: 50})
+	require.Nil(t, err)
+	assert.Equal(t, "C: MAIL FROM:<bounce@k.sender.test> SIZE=50 BODY=8BITMIME", sent.Transcript.Lines[0])
+
+	sent, err = s.Send("bounce@k.sender.test", "to@mx.test", body, SendOptions{Size: 200})
+	require.NotNil(t, err)
+	assert.True(t, err.IsPermanent(), "no later attempt makes the message smaller")
+	assert.Equal(t, uint32(552), err.Code())
+	assert.Equal(t, StageMAIL, sent.Transcript.Stage)
+	assert.Equal(t, "5.3.4", sent.Transcript.EnhancedCode)
+	assert.Empty(t, sent.Transcript.Lines, "nothing of the message was sent")
+	assert.Equal(t, 1, rcv.accepted())
+}
+
+func TestSMTPUTF8IsRequiredWhereTheMessageNeedsIt(t *testing.T) {
+	s, rcv := newExtensionSender(t, 0, false)
+	body := BytesBody([]byte("hi\r\n"))
+
+	sent, err := s.Send("bounce@k.sender.test", "someone@例子.test", body, SendOptions{})
+	require.Nil(t, err, "an internationalised domain goes out as its A-label")
+	assert.Equal(t, "C: RCPT TO:<someone@xn--fsqu00a.test>", sent.Transcript.Lines[2])
+
+	sent, err = s.Send("bounce@k.sender.test", "用户@mx.test", body, SendOptions{})
+	require.NotNil(t, err)
+	assert.True(t, err.IsPermanent())
+	assert.Equal(t, uint32(553), err.Code())
+	assert.Equal(t, "5.6.7", sent.Transcript.EnhancedCode)
+
+	sent, err = s.Send("bounce@k.sender.test", "to@mx.test", body, SendOptions{SMTPUTF8: true})
+	require.NotNil(t, err)
+	assert.True(t, err.IsPermanent())
+	assert.Equal(t, "5.6.9", sent.Transcript.EnhancedCode)
+	assert.Equal(t, 1, rcv.accepted())
+
+	s, rcv = newExtensionSender(t, 0, true)
+	sent, err = s.Send("bounce@k.sender.test", "用户@例子.test", body, SendOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, "C: MAIL FROM:<bounce@k.sender.test> BODY=8BITMIME SMTPUTF8", sent.Transcript.Lines[0])
+	assert.Equal(t, "C: RCPT TO:<用户@例子.test>", sent.Transcript.Lines[2])
+
+	sent, err = s.Send("bounce@k.sender.test", "to@mx.test", body, SendOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, "C: MAIL FROM:<bounce@k.sender.test> BODY=8BITMIME", sent.Transcript.Lines[0],
+		"SMTPUTF8 is asked for only by a message that needs it")
+	assert.Equal(t, 2, rcv.accepted())
+}
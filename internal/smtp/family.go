@@ -0,0 +1,72 @@
+package smtp
+
+import (
+	"fmt"
+	"net"
+)
+
+// AddressFamily is `sender.address_family`: which of an MX host's addresses
+// are dialled, and in what order.
+//
+// Go's dialer would otherwise race the two families and take whichever
+// answers first, which a host with a broken IPv6 route wins or loses by the
+// millisecond. Receivers also keep reputation per address, and a sending
+// address's IPv6 reputation can be better — or worse — than its IPv4 one, so
+// the choice is the operator's.
+type AddressFamily string
+
+// Address families.
+const (
+	// FamilyPreferIPv4, the default, dials a host's IPv4 addresses first and
+	// its IPv6 ones when none of those answered.
+	FamilyPreferIPv4 AddressFamily = "prefer_ipv4"
+	// FamilyPreferIPv6 dials the IPv6 addresses first, then the IPv4 ones.
+	FamilyPreferIPv6 AddressFamily = "prefer_ipv6"
+	// FamilyIPv4 dials only IPv4 addresses: a host without one is skipped.
+	FamilyIPv4 AddressFamily = "ipv4"
+	// FamilyIPv6 dials only IPv6 addresses.
+	FamilyIPv6 AddressFamily = "ipv6"
+)
+
+// Validate refuses a family that is not one of the four.
+func (f AddressFamily) Validate() error {
+	switch f {
+	case "", FamilyPreferIPv4, FamilyPreferIPv6, FamilyIPv4, FamilyIPv6:
+		return nil
+	}
+	return fmt.Errorf("address_family %q is none of %q, %q, %q and %q",
+		string(f), FamilyPreferIPv4, FamilyPreferIPv6, FamilyIPv4, FamilyIPv6)
+}
+
+// allows reports whether ip is of a family f dials.
+func (f AddressFamily) allows(ip net.IP) bool {
+	switch f {
+	case FamilyIPv4:
+		return ip.To4() != nil
+	case FamilyIPv6:
+		return ip.To4() == nil
+	}
+	return true
+}
+
+// order is ips in the order f dials them, without those it does not dial.
+// Within a family the resolver's order is kept.
+func (f AddressFamily) order(ips []net.IP) []net.IP {
+	var v4, v6 []net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			v4 = append(v4, ip)
+		} else {
+			v6 = append(v6, ip)
+		}
+	}
+	switch f {
+	case FamilyIPv4:
+		return v4
+	case FamilyIPv6:
+		return v6
+	case FamilyPreferIPv6:
+		return append(v6, v4...)
+	}
+	return append(v4, v6...)
+}
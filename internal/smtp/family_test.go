@@ -0,0 +1,88 @@
+package smtp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressFamilyOrder(t *testing.T) {
+	ips := []net.IP{
+		net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1"),
+		net.ParseIP("2001:db8::2"), net.ParseIP("192.0.2.2"),
+	}
+	v4 := []net.IP{ips[1], ips[3]}
+	v6 := []net.IP{ips[0], ips[2]}
+
+	assert.Equal(t, append(v4, v6...), FamilyPreferIPv4.order(ips))
+	assert.Equal(t, append(v6, v4...), FamilyPreferIPv6.order(ips))
+	assert.Equal(t, v4, FamilyIPv4.order(ips))
+	assert.Equal(t, v6, FamilyIPv6.order(ips))
+	assert.Empty(t, FamilyIPv6.order(v4), "a host without an address of the family is not dialled")
+
+	assert.NoError(t, AddressFamily("").Validate())
+	assert.NoError(t, FamilyPreferIPv6.Validate())
+	assert.Error(t, AddressFamily("ipv5").Validate())
+}
+
+// A connection is opened from an address of its pool of the family it is
+// opened to, whichever address the pool's selection picked.
+func TestIPPoolsPairTheSourceWithTheFamily(t *testing.T) {
+	pools, err := NewIPPools(map[string]IPPoolConfig{
+		"dual":   {Addresses: []string{"192.0.2.1", "2001:db8::1", "192.0.2.2", "2001:db8::2"}},
+		"v4only": {Addresses: []string{"192.0.2.3"}},
+	}, "")
+	require.NoError(t, err)
+	v4, v6 := net.ParseIP("198.51.100.1"), net.ParseIP("2001:db8:1::1")
+
+	src, ok := pools.toward(source{pool: "dual", ip: "192.0.2.2"}, v4)
+	require.True(t, ok)
+	assert.Equal(t, "192.0.2.2", src.ip, "an address of the right family is kept")
+
+	src, ok = pools.toward(source{pool: "dual", ip: "192.0.2.2"}, v6)
+	require.True(t, ok)
+	assert.Equal(t, "2001:db8::2", src.ip, "the next address of the pool of the family dialled")
+
+	_, ok = pools.toward(source{pool: "v4only", ip: "192.0.2.3"}, v6)
+	assert.False(t, ok)
+
+	src, ok = pools.toward(source{}, v6)
+	require.True(t, ok)
+	assert.Equal(t, source{}, src, "mail of no pool goes out from the system's choice")
+
+	_, addrs := pools.sources("dual", "example.com", FamilyIPv6)
+	assert.ElementsMatch(t, []string{"2001:db8::1", "2001:db8::2"}, addrs, "only addresses that will be used are offered")
+
+	assert.NoError(t, pools.ValidateFamily(FamilyPreferIPv6))
+	assert.NoError(t, pools.ValidateFamily(FamilyIPv4))
+	assert.Error(t, pools.ValidateFamily(FamilyIPv6), "v4only has nothing to send from over IPv6")
+}
+
+// An address that does not answer is passed over for the host's next one,
+// and the Transcript names the address the message went to.
+func TestSendFallsBackToTheOtherFamily(t *testing.T) {
+	s, rcv, _, _ := newPolicySender(t, true)
+	s.resolver = &resolver.Fake{
+		MX: map[string][]*net.MX{"dual.test": {{Host: "mx.dual.test", Pref: 10}}},
+		// Nothing listens on the receiver's port at ::1, whether or not the
+		// host has IPv6 at all.
+		IP: map[string][]net.IP{"mx.dual.test": {net.ParseIP("127.0.0.1"), net.ParseIP("::1")}},
+	}
+
+	s.family = FamilyPreferIPv6
+	sent, err := s.Send("bounce@k.sender.test", "someone@dual.test", BytesBody([]byte("hi\r\n")), SendOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, "127.0.0.1", sent.Transcript.RemoteIP)
+	assert.Equal(t, "127.0.0.1", sent.SourceIP)
+	assert.Equal(t, 1, rcv.accepted())
+
+	s.family = FamilyIPv6
+	sent, err = s.Send("bounce@k.sender.test", "someone@dual.test", BytesBody([]byte("hi\r\n")), SendOptions{})
+	require.NotNil(t, err)
+	assert.False(t, err.IsPermanent())
+	assert.Equal(t, "::1", sent.Transcript.RemoteIP, "IPv4 is not fallen back to under ipv6")
+	assert.Equal(t, 1, rcv.accepted())
+}
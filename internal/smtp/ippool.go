@@ -0,0 +1,226 @@
+package smtp
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/kannon-email/kannon/internal/values"
+)
+
+// IPPoolConfig is one entry of `sender.ip_pools`: local addresses mail can be
+// sent from, and the name they greet receivers with.
+//
+// Receivers keep reputation per sending address, so mail whose reputation
+// should not be shared — a tenant's transactional mail and another's
+// newsletters — is kept apart by sending it from separate pools.
+type IPPoolConfig struct {
+	// Addresses are the local addresses connections are opened from. Each must
+	// be configured on the host the Sender runs on, and should have a PTR
+	// record naming Hostname.
+	Addresses []string `mapstructure:"addresses"`
+	// Hostname is what the pool says in EHLO, sender.hostname when empty.
+	// Receivers check it against the address's PTR record.
+	Hostname string `mapstructure:"hostname"`
+	// Selection is how an address of the pool is chosen for each message:
+	// "round_robin", the default, takes them in turn; "hash" keeps each
+	// recipient domain on the same address, so a provider sees a stable
+	// sender.
+	Selection string `mapstructure:"selection"`
+}
+
+// IP pool selections.
+const (
+	SelectionRoundRobin = "round_robin"
+	SelectionHash       = "hash"
+)
+
+// ipPool is a validated IPPoolConfig.
+type ipPool struct {
+	name      string
+	addresses []string
+	hostname  string
+	hash      bool
+	next      atomic.Uint64
+}
+
+// pick chooses the address to send a message for domain from, by its index.
+func (p *ipPool) pick(domain string) int {
+	if len(p.addresses) == 1 {
+		return 0
+	}
+	if p.hash {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(strings.ToLower(domain)))
+		return int(h.Sum32() % uint32(len(p.addresses)))
+	}
+	return int((p.next.Add(1) - 1) % uint64(len(p.addresses)))
+}
+
+// has reports whether ip is one of the pool's addresses.
+func (p *ipPool) has(ip string) bool {
+	return slices.Contains(p.addresses, ip)
+}
+
+// IPPools are the Sender's IP pools, by name.
+type IPPools struct {
+	pools map[string]*ipPool
+	def   *ipPool
+}
+
+// NewIPPools validates the `sender.ip_pools` section and the pool mail
+// assigned to none is sent from, `sender.default_ip_pool` — none at all, the
+// system's choice of address, when empty. An address that does not parse, or a
+// default nobody defined, is an error here rather than mail that fails on its
+// way out.
+func NewIPPools(pools map[string]IPPoolConfig, defaultPool string) (*IPPools, error) {
+	ps := &IPPools{pools: make(map[string]*ipPool, len(pools))}
+	for name, pc := range pools {
+		p, err := newIPPool(name, pc)
+		if err != nil {
+			return nil, err
+		}
+		ps.pools[name] = p
+	}
+	if defaultPool != "" {
+		p, ok := ps.pools[defaultPool]
+		if !ok {
+			return nil, fmt.Errorf("default IP pool %q is not defined", defaultPool)
+		}
+		ps.def = p
+	}
+	return ps, nil
+}
+
+func newIPPool(name string, pc IPPoolConfig) (*ipPool, error) {
+	if err := values.ValidateIPPool(name); err != nil {
+		return nil, err
+	}
+	if len(pc.Addresses) == 0 {
+		return nil, fmt.Errorf("IP pool %q has no addresses", name)
+	}
+	p := &ipPool{name: name, hostname: pc.Hostname}
+	for _, a := range pc.Addresses {
+		ip := net.ParseIP(a)
+		if ip == nil {
+			return nil, fmt.Errorf("IP pool %q: %q is not an IP address", name, a)
+		}
+		p.addresses = append(p.addresses, ip.String())
+	}
+	switch pc.Selection {
+	case "", SelectionRoundRobin:
+	case SelectionHash:
+		p.hash = true
+	default:
+		return nil, fmt.Errorf("IP pool %q: selection %q is neither %q nor %q", name, pc.Selection, SelectionRoundRobin, SelectionHash)
+	}
+	return p, nil
+}
+
+// poolFor returns the pool a message assigned to name is sent from. A name no
+// pool answers to — a Domain assigned to a pool this Sender was not given —
+// falls back to the default pool: mail sent from the wrong address is a
+// reputation matter an operator can correct, mail that is not sent at all is
+// an outage. A nil IPPools has no pools.
+func (ps *IPPools) poolFor(name string) *ipPool {
+	if ps == nil {
+		return nil
+	}
+	if name != "" {
+		if p, ok := ps.pools[name]; ok {
+			return p
+		}
+	}
+	return ps.def
+}
+
+// source is where one message is sent from: the local address and the EHLO
+// name, both empty for the system's address and the Sender's hostname.
+type source struct {
+	pool string
+	ip   string
+	helo string
+}
+
+// sourceFor chooses the source of a message to domain assigned to pool: ip,
+// when that is one of the pool's addresses, and otherwise the one the pool's
+// selection picks.
+func (ps *IPPools) sourceFor(pool, domain, ip string) source {
+	p := ps.poolFor(pool)
+	if p == nil {
+		return source{}
+	}
+	if ip == "" || !p.has(ip) {
+		ip = p.addresses[p.pick(domain)]
+	}
+	return source{pool: p.name, ip: ip, helo: p.hostname}
+}
+
+// sources returns the pool a message to domain assigned to pool is sent from,
+// and its addresses of a family f dials in the order they are to be tried: the
+// one the selection picks, then the others after it in turn.
+func (ps *IPPools) sources(pool, domain string, f AddressFamily) (string, []string) {
+	p := ps.poolFor(pool)
+	if p == nil {
+		return "", nil
+	}
+	first := p.pick(domain)
+	var addrs []string
+	for _, a := range append(slices.Clone(p.addresses[first:]), p.addresses[:first]...) {
+		if f.allows(net.ParseIP(a)) {
+			addrs = append(addrs, a)
+		}
+	}
+	return p.name, addrs
+}
+
+// toward is src made fit to dial ip: src itself when it is of ip's family or
+// of no pool, and otherwise the first address of its pool after src.ip that
+// is. A connection can only be opened from an address of the family it is
+// opened to, so a pool that sends over both needs addresses of both; ok is
+// false when src's pool has none of ip's.
+func (ps *IPPools) toward(src source, ip net.IP) (source, bool) {
+	p := ps.poolFor(src.pool)
+	if p == nil || src.ip == "" || sameFamily(net.ParseIP(src.ip), ip) {
+		return src, true
+	}
+	at := slices.Index(p.addresses, src.ip)
+	for i := 1; i <= len(p.addresses); i++ {
+		a := p.addresses[(at+i)%len(p.addresses)]
+		if sameFamily(net.ParseIP(a), ip) {
+			src.ip = a
+			return src, true
+		}
+	}
+	return source{}, false
+}
+
+// ValidateFamily refuses pools f leaves without an address to send from: under
+// an IPv4- or IPv6-only family, every pool must have an address of it.
+func (ps *IPPools) ValidateFamily(f AddressFamily) error {
+	if ps == nil || (f != FamilyIPv4 && f != FamilyIPv6) {
+		return nil
+	}
+	for name, p := range ps.pools {
+		if !slices.ContainsFunc(p.addresses, func(a string) bool { return f.allows(net.ParseIP(a)) }) {
+			return fmt.Errorf("IP pool %q has no address of address_family %q", name, string(f))
+		}
+	}
+	return nil
+}
+
+func sameFamily(a, b net.IP) bool {
+	return (a.To4() != nil) == (b.To4() != nil)
+}
+
+// sourceIPOf is the local address conn was opened from, as sent mail records
+// it.
+func sourceIPOf(conn net.Conn) string {
+	if a, ok := conn.LocalAddr().(*net.TCPAddr); ok {
+		return a.IP.String()
+	}
+	return ""
+}
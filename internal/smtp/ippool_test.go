@@ -0,0 +1,116 @@
+package smtp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPPoolsRefuseWhatCannotBeUsed(t *testing.T) {
+	cases := []struct {
+		name  string
+		pools map[string]IPPoolConfig
+		def   string
+	}{
+		{"not a pool name", map[string]IPPoolConfig{"Bulk": {Addresses: []string{"192.0.2.1"}}}, ""},
+		{"no addresses", map[string]IPPoolConfig{"tx": {}}, ""},
+		{"not an address", map[string]IPPoolConfig{"tx": {Addresses: []string{"mail.example.com"}}}, ""},
+		{"unknown selection", map[string]IPPoolConfig{"tx": {Addresses: []string{"192.0.2.1"}, Selection: "random"}}, ""},
+		{"undefined default", map[string]IPPoolConfig{"tx": {Addresses: []string{"192.0.2.1"}}}, "bulk"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := NewIPPools(c.pools, c.def)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestIPPoolSelection(t *testing.T) {
+	pools, err := NewIPPools(map[string]IPPoolConfig{
+		"rr":   {Addresses: []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}},
+		"hash": {Addresses: []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}, Selection: SelectionHash},
+	}, "rr")
+	require.NoError(t, err)
+
+	var turns []string
+	for range 4 {
+		turns = append(turns, pools.sourceFor("rr", "example.com", "").ip)
+	}
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2", "192.0.2.3", "192.0.2.1"}, turns)
+
+	first := pools.sourceFor("hash", "Example.com", "").ip
+	for range 3 {
+		assert.Equal(t, first, pools.sourceFor("hash", "example.com", "").ip, "a recipient domain keeps its address")
+	}
+
+	assert.Equal(t, "rr", pools.sourceFor("", "example.com", "").pool, "unassigned mail takes the default pool")
+	assert.Equal(t, "rr", pools.sourceFor("bulk", "example.com", "").pool, "an unknown pool falls back to the default")
+
+	assert.Equal(t, "192.0.2.3", pools.sourceFor("hash", "example.com", "192.0.2.3").ip, "an address of the pool can be asked for")
+	assert.Equal(t, first, pools.sourceFor("hash", "example.com", "198.51.100.1").ip, "one of another is not taken")
+
+	var none *IPPools
+	assert.Equal(t, source{}, none.sourceFor("rr", "example.com", ""))
+}
+
+// The addresses a SourceChooser offers start with the one the selection picks
+// and go on through the rest, so that the first is the address the message
+// would have gone out from unasked.
+func TestIPPoolSources(t *testing.T) {
+	pools, err := NewIPPools(map[string]IPPoolConfig{
+		"hash": {Addresses: []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}, Selection: SelectionHash},
+	}, "")
+	require.NoError(t, err)
+
+	first := pools.sourceFor("hash", "example.com", "").ip
+	pool, addrs := pools.sources("hash", "example.com", FamilyPreferIPv4)
+	assert.Equal(t, "hash", pool)
+	require.Len(t, addrs, 3)
+	assert.Equal(t, first, addrs[0])
+	assert.ElementsMatch(t, []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}, addrs)
+
+	pool, addrs = pools.sources("", "example.com", FamilyPreferIPv4)
+	assert.Empty(t, pool, "mail of no pool, without a default, has no addresses to choose from")
+	assert.Empty(t, addrs)
+}
+
+// A pool's message goes out from one of its addresses, greeting with its
+// hostname, and the Sender says which address it was.
+func TestSendFromAnIPPool(t *testing.T) {
+	s, rcv, _, _ := newPolicySender(t, true)
+	pools, err := NewIPPools(map[string]IPPoolConfig{
+		"tx": {Addresses: []string{"127.0.0.1", "127.0.0.2"}, Hostname: "tx.sender.test"},
+	}, "")
+	require.NoError(t, err)
+	s.ipPools = pools
+
+	var ips []string
+	for range 2 {
+		sent, err := s.Send("bounce@k.sender.test", "someone@recipient.test", BytesBody([]byte("hi\r\n")), SendOptions{IPPool: "tx"})
+		require.Nil(t, err)
+		assert.Equal(t, "tx", sent.IPPool)
+		ips = append(ips, sent.SourceIP)
+	}
+	assert.Equal(t, []string{"127.0.0.1", "127.0.0.2"}, ips)
+	require.Equal(t, 2, rcv.sessions(), "each address has connections of its own")
+	rcv.mu.Lock()
+	for _, c := range rcv.conns {
+		assert.Equal(t, "tx.sender.test", c.Hostname())
+	}
+	rcv.mu.Unlock()
+
+	sent, err := s.Send("bounce@k.sender.test", "someone@recipient.test", BytesBody([]byte("hi\r\n")), SendOptions{IPPool: "tx", SourceIP: "127.0.0.2"})
+	require.Nil(t, err)
+	assert.Equal(t, "127.0.0.2", sent.SourceIP, "the address asked for, out of turn")
+
+	sent, err = s.Send("bounce@k.sender.test", "someone@recipient.test", BytesBody([]byte("hi\r\n")), SendOptions{})
+	require.Nil(t, err)
+	assert.Empty(t, sent.IPPool)
+	assert.NotEmpty(t, sent.SourceIP, "the system's choice of address is recorded too")
+	require.Equal(t, 3, rcv.sessions(), "mail of no pool does not ride a pool's connection")
+	rcv.mu.Lock()
+	assert.Equal(t, "sender.test", rcv.conns[2].Hostname())
+	rcv.mu.Unlock()
+}
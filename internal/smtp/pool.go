@@ -0,0 +1,331 @@
+package smtp
+
+import (
+	"net"
+	"net/smtp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PoolConfig is the `sender.pool` section: how outbound connections are kept
+// open and reused across messages to the same MX host.
+//
+// A provider that receives a great deal of our mail sees one connection carry
+// many messages instead of a TCP handshake, an EHLO and a TLS handshake per
+// message — cheaper for both sides, and the connection churn large providers
+// throttle on is gone.
+type PoolConfig struct {
+	// MaxMessagesPerConn is how many messages one connection carries before it
+	// is closed with QUIT. Providers cap this themselves, commonly around a
+	// hundred; one reproduces a connection per message.
+	MaxMessagesPerConn int `mapstructure:"max_messages_per_conn"`
+	// IdleTimeout is how long a connection may wait for its next message
+	// before it is closed. Receivers drop idle clients after a minute or so,
+	// and a connection they dropped first costs a failed RSET to find out.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+	// MaxIdlePerHost is how many connections to one key are kept open while
+	// idle. More are opened when sends to the host run in parallel; those past
+	// this many are closed when their message is done.
+	MaxIdlePerHost int `mapstructure:"max_idle_per_host"`
+}
+
+// Pool defaults, for a section that states nothing.
+const (
+	DefaultMaxMessagesPerConn = 100
+	DefaultIdleTimeout        = 30 * time.Second
+	DefaultMaxIdlePerHost     = 4
+)
+
+func (c *PoolConfig) setDefaults() {
+	if c.MaxMessagesPerConn <= 0 {
+		c.MaxMessagesPerConn = DefaultMaxMessagesPerConn
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = DefaultIdleTimeout
+	}
+	if c.MaxIdlePerHost <= 0 {
+		c.MaxIdlePerHost = DefaultMaxIdlePerHost
+	}
+}
+
+// tlsMode is how a connection was asked to secure itself. It is part of what
+// makes two connections interchangeable: a message that asked for one mode must
+// never ride a connection opened under another.
+type tlsMode string
+
+// tlsOpportunistic upgrades with STARTTLS when the receiver offers it, and
+// tries again without verifying the certificate when the verified handshake
+// fails — many MX hosts present self-signed certificates.
+const tlsOpportunistic tlsMode = "opportunistic"
+
+// connKey is what a pooled connection is reused by: the MX host it is
+// connected to and the address of it dialled — empty for the host's name,
+// resolved by the system — the local address it was opened from — empty for
+// whatever the system chooses — the name it greeted with — empty for the
+// Sender's hostname — and the TLS mode it was opened under. A connection to a
+// relay also carries the transport's name, since it is logged in with that
+// transport's credentials.
+type connKey struct {
+	mx        string
+	ip        string
+	localIP   string
+	helo      string
+	tls       tlsMode
+	transport string
+}
+
+// pooledConn is one open SMTP session, greeted and — when the receiver offers
+// it — under TLS, ready for MAIL FROM.
+type pooledConn struct {
+	key    connKey
+	conn   net.Conn
+	client *smtp.Client
+	// tlsResult is how the session was secured, as a TLS-RPT result: success,
+	// or under opportunistic TLS the failure it settled for.
+	tlsResult string
+	// sent counts the messages the connection has carried.
+	sent int
+	// idleSince is when it was last handed back to the pool.
+	idleSince time.Time
+}
+
+// PoolStats are the pool's counters since the Sender was created.
+type PoolStats struct {
+	// Hits are messages sent on a connection an earlier one opened.
+	Hits uint64
+	// Misses are messages that had to open a connection.
+	Misses uint64
+	// Evicted are connections closed for failing, for idling too long or for
+	// answering RSET with anything but success.
+	Evicted uint64
+	// Idle is how many connections are open and waiting right now.
+	Idle int
+}
+
+// HitRate is the share of messages that reused a connection, zero before the
+// first message.
+func (s PoolStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// PoolReporter is a Sender that pools its connections and can say how well
+// that is going. The SMTPSender logs it.
+type PoolReporter interface {
+	PoolStats() PoolStats
+}
+
+// connPool holds the idle connections by key. A connection is checked out for
+// exactly one transaction and is then either handed back or closed; the pool
+// never shares one between two goroutines.
+type connPool struct {
+	cfg  PoolConfig
+	dial func(key connKey) (*pooledConn, *smtpError)
+	now  func() time.Time
+
+	mu   sync.Mutex
+	idle map[connKey][]*pooledConn
+	// closed is set by close; a connection handed back after it is QUIT
+	// rather than kept.
+	closed bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	hits, misses, evicted atomic.Uint64
+}
+
+func newConnPool(cfg PoolConfig, dial func(connKey) (*pooledConn, *smtpError)) *connPool {
+	cfg.setDefaults()
+	p := &connPool{
+		cfg:  cfg,
+		dial: dial,
+		now:  time.Now,
+		idle: make(map[connKey][]*pooledConn),
+		stop: make(chan struct{}),
+	}
+	go p.reap()
+	return p
+}
+
+// minReapInterval bounds how often the reaper wakes, whatever IdleTimeout is:
+// half of a nanosecond is no interval a ticker can keep.
+const minReapInterval = 10 * time.Millisecond
+
+// reap closes the connections that idled past IdleTimeout every half of it
+// until the pool is closed. popIdle expires them too, but only when a message
+// is sent; a host that gets no more mail would otherwise keep its sockets open
+// until the receiver gives up on them.
+func (p *connPool) reap() {
+	t := time.NewTicker(max(p.cfg.IdleTimeout/2, minReapInterval))
+	defer t.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-t.C:
+			p.mu.Lock()
+			stale := p.expireLocked()
+			p.mu.Unlock()
+			for _, pc := range stale {
+				p.evict(pc)
+			}
+		}
+	}
+}
+
+// get returns a connection for key, and whether it was reused rather than
+// opened for this message.
+//
+// An idle connection is reset before it is handed out. RSET is what clears
+// the previous transaction, and it is also the cheapest way to learn that the
+// receiver closed the connection while it sat idle: one that does not answer
+// 250 is closed and the next is tried, until a new one is opened.
+func (p *connPool) get(key connKey) (*pooledConn, bool, *smtpError) {
+	for {
+		pc := p.popIdle(key)
+		if pc == nil {
+			break
+		}
+		if err := pc.conn.SetDeadline(p.now().Add(smtpTotalTimeout)); err == nil {
+			if err := pc.client.Reset(); err == nil {
+				p.hits.Add(1)
+				return pc, true, nil
+			}
+		}
+		p.evict(pc)
+	}
+
+	pc, err := p.open(key)
+	return pc, false, err
+}
+
+// open dials a new connection for key, bypassing the idle ones.
+func (p *connPool) open(key connKey) (*pooledConn, *smtpError) {
+	pc, err := p.dial(key)
+	if err != nil {
+		return nil, err
+	}
+	p.misses.Add(1)
+	return pc, nil
+}
+
+// popIdle takes the most recently used idle connection for key, closing on the
+// way every connection of any key that has idled past IdleTimeout. The newest
+// is taken first so that the oldest age out when traffic drops.
+func (p *connPool) popIdle(key connKey) *pooledConn {
+	p.mu.Lock()
+	stale := p.expireLocked()
+	var pc *pooledConn
+	if conns := p.idle[key]; len(conns) > 0 {
+		pc = conns[len(conns)-1]
+		p.setIdleLocked(key, conns[:len(conns)-1])
+	}
+	p.mu.Unlock()
+
+	for _, s := range stale {
+		p.evict(s)
+	}
+	return pc
+}
+
+// put hands a connection back after a transaction that left the session in
+// step — delivered, or refused with a reply. One that has carried its quota,
+// that would exceed MaxIdlePerHost, or that comes back after the pool was
+// closed is closed with QUIT instead.
+func (p *connPool) put(pc *pooledConn) {
+	pc.sent++
+	if pc.sent >= p.cfg.MaxMessagesPerConn {
+		p.quit(pc)
+		return
+	}
+
+	p.mu.Lock()
+	if p.closed || len(p.idle[pc.key]) >= p.cfg.MaxIdlePerHost {
+		p.mu.Unlock()
+		p.quit(pc)
+		return
+	}
+	pc.idleSince = p.now()
+	p.idle[pc.key] = append(p.idle[pc.key], pc)
+	p.mu.Unlock()
+}
+
+// evict closes a connection that failed, without a QUIT: whatever state the
+// session is in, it is not one worth talking to again.
+func (p *connPool) evict(pc *pooledConn) {
+	p.evicted.Add(1)
+	_ = pc.conn.Close()
+}
+
+// quit closes a healthy connection politely.
+func (p *connPool) quit(pc *pooledConn) {
+	_ = pc.conn.SetDeadline(p.now().Add(smtpDialTimeout))
+	_ = pc.client.Quit()
+	_ = pc.conn.Close()
+}
+
+// expireLocked removes every idle connection past IdleTimeout and returns them
+// for the caller to close outside the lock.
+func (p *connPool) expireLocked() []*pooledConn {
+	cutoff := p.now().Add(-p.cfg.IdleTimeout)
+	var stale []*pooledConn
+	for key, conns := range p.idle {
+		fresh := conns[:0]
+		for _, pc := range conns {
+			if pc.idleSince.Before(cutoff) {
+				stale = append(stale, pc)
+				continue
+			}
+			fresh = append(fresh, pc)
+		}
+		p.setIdleLocked(key, fresh)
+	}
+	return stale
+}
+
+func (p *connPool) setIdleLocked(key connKey, conns []*pooledConn) {
+	if len(conns) == 0 {
+		delete(p.idle, key)
+		return
+	}
+	p.idle[key] = conns
+}
+
+// stats reads the counters.
+func (p *connPool) stats() PoolStats {
+	p.mu.Lock()
+	idle := 0
+	for _, conns := range p.idle {
+		idle += len(conns)
+	}
+	p.mu.Unlock()
+	return PoolStats{
+		Hits:    p.hits.Load(),
+		Misses:  p.misses.Load(),
+		Evicted: p.evicted.Load(),
+		Idle:    idle,
+	}
+}
+
+// close stops the reaper and QUITs every idle connection. Connections checked
+// out at the time are QUIT by put when their transaction ends.
+func (p *connPool) close() {
+	p.stopOnce.Do(func() { close(p.stop) })
+
+	p.mu.Lock()
+	p.closed = true
+	var all []*pooledConn
+	for _, conns := range p.idle {
+		all = append(all, conns...)
+	}
+	p.idle = make(map[connKey][]*pooledConn)
+	p.mu.Unlock()
+
+	for _, pc := range all {
+		p.quit(pc)
+	}
+}
@@ -0,0 +1,164 @@
+package smtp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/bodystore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPooledSender starts a receiver and returns a Sender whose MX hosts are
+// dialled on its port, so that deliverLoopback reaches it.
+func newPooledSender(t *testing.T, cfg PoolConfig) (*sender, *receiver) {
+	t.Helper()
+	rcv := &receiver{}
+	return newLoopbackSender(t, startReceiver(t, rcv), WithPool(cfg)), rcv
+}
+
+const loopbackMX = "127.0.0.1"
+
+// deliverLoopback sends one message to the receiver of newPooledSender, on
+// the connection key of an MX host with no TLS policy.
+func deliverLoopback(s *sender, from, to string, body Body) *smtpError {
+	_, err := s.deliverKey(connKey{mx: loopbackMX, tls: tlsOpportunistic}, from, to, body, SendOptions{})
+	return err
+}
+
+func TestSenderReusesConnectionAcrossMessages(t *testing.T) {
+	s, rcv := newPooledSender(t, PoolConfig{})
+
+	for range 3 {
+		require.Nil(t, deliverLoopback(s, "from@sender.test", "to@mx.test", BytesBody([]byte("Subject: hi\r\n\r\nhi\r\n"))))
+	}
+
+	assert.Equal(t, 1, rcv.sessions(), "one connection carries all three messages")
+	assert.Equal(t, 3, rcv.accepted())
+
+	st := s.PoolStats()
+	assert.Equal(t, uint64(2), st.Hits)
+	assert.Equal(t, uint64(1), st.Misses)
+	assert.InDelta(t, 2.0/3.0, st.HitRate(), 0.001)
+	assert.Equal(t, 1, st.Idle)
+}
+
+func TestSenderClosesConnectionAfterItsQuota(t *testing.T) {
+	s, rcv := newPooledSender(t, PoolConfig{MaxMessagesPerConn: 2})
+
+	for range 3 {
+		require.Nil(t, deliverLoopback(s, "from@sender.test", "to@mx.test", BytesBody([]byte("hi\r\n"))))
+	}
+
+	assert.Equal(t, 2, rcv.sessions(), "the third message needs a connection of its own")
+	assert.Equal(t, 3, rcv.accepted())
+}
+
+func TestSenderClosesConnectionIdlePastTimeout(t *testing.T) {
+	s, rcv := newPooledSender(t, PoolConfig{IdleTimeout: time.Minute})
+	now := time.Now()
+	s.pool.now = func() time.Time { return now }
+
+	require.Nil(t, deliverLoopback(s, "from@sender.test", "to@mx.test", BytesBody([]byte("hi\r\n"))))
+	now = now.Add(2 * time.Minute)
+	require.Nil(t, deliverLoopback(s, "from@sender.test", "to@mx.test", BytesBody([]byte("hi\r\n"))))
+
+	assert.Equal(t, 2, rcv.sessions())
+	assert.Equal(t, uint64(1), s.PoolStats().Evicted)
+}
+
+func TestPoolReapsIdleConnectionsWithoutTraffic(t *testing.T) {
+	s, rcv := newPooledSender(t, PoolConfig{IdleTimeout: 50 * time.Millisecond})
+
+	require.Nil(t, deliverLoopback(s, "from@sender.test", "to@mx.test", BytesBody([]byte("hi\r\n"))))
+	require.Equal(t, 1, s.PoolStats().Idle)
+
+	assert.Eventually(t, func() bool { return s.PoolStats().Idle == 0 }, 2*time.Second, 10*time.Millisecond,
+		"a connection no message comes for is closed all the same")
+	assert.Equal(t, uint64(1), s.PoolStats().Evicted)
+	assert.Equal(t, 1, rcv.sessions())
+}
+
+func TestSenderReplacesConnectionTheReceiverDropped(t *testing.T) {
+	s, rcv := newPooledSender(t, PoolConfig{})
+
+	require.Nil(t, deliverLoopback(s, "from@sender.test", "to@mx.test", BytesBody([]byte("hi\r\n"))))
+	rcv.dropAll()
+	require.Nil(t, deliverLoopback(s, "from@sender.test", "to@mx.test", BytesBody([]byte("hi\r\n"))),
+		"a connection that fails its RSET is replaced, not reported")
+
+	assert.Equal(t, 2, rcv.sessions())
+	assert.Equal(t, 2, rcv.accepted())
+	assert.Equal(t, uint64(1), s.PoolStats().Evicted)
+}
+
+func TestSenderKeepsConnectionAfterRefusedRecipient(t *testing.T) {
+	s, rcv := newPooledSender(t, PoolConfig{})
+
+	err := deliverLoopback(s, "from@sender.test", "nobody@refused.test", BytesBody([]byte("hi\r\n")))
+	require.NotNil(t, err)
+	assert.Equal(t, uint32(550), err.Code())
+	assert.True(t, err.IsPermanent())
+
+	require.Nil(t, deliverLoopback(s, "from@sender.test", "to@mx.test", BytesBody([]byte("hi\r\n"))))
+	assert.Equal(t, 1, rcv.sessions(), "a refusal leaves the session fit for the next message")
+	assert.Equal(t, uint64(0), s.PoolStats().Evicted)
+}
+
+func TestSenderEvictsConnectionOnUnreadableBody(t *testing.T) {
+	s, rcv := newPooledSender(t, PoolConfig{})
+
+	broken := func() (io.ReadCloser, error) {
+		return io.NopCloser(io.MultiReader(strings.NewReader("half"), errReader{})), nil
+	}
+	err := deliverLoopback(s, "from@sender.test", "to@mx.test", broken)
+	require.NotNil(t, err)
+	assert.Equal(t, uint32(451), err.Code())
+
+	require.Nil(t, deliverLoopback(s, "from@sender.test", "to@mx.test", BytesBody([]byte("hi\r\n"))))
+	assert.Equal(t, 2, rcv.sessions(), "a message cut short mid-DATA takes its connection with it")
+	assert.Equal(t, 1, rcv.accepted())
+}
+
+// A body the store no longer holds fails the message for good, where one that
+// could not be read is tried again.
+func TestSenderFailsMessageWhoseBodyIsGone(t *testing.T) {
+	s, rcv := newPooledSender(t, PoolConfig{})
+
+	gone := func() (io.ReadCloser, error) {
+		return nil, fmt.Errorf("%w: body_1", bodystore.ErrNotFound)
+	}
+	err := deliverLoopback(s, "from@sender.test", "to@mx.test", gone)
+	require.NotNil(t, err)
+	assert.Equal(t, uint32(554), err.Code())
+	assert.True(t, err.IsPermanent())
+	assert.Zero(t, rcv.accepted())
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("body store went away") }
+
+// A connection checked out when the pool is closed is QUIT when its
+// transaction ends, rather than kept idle by a pool nobody will close again.
+func TestPoolQuitsConnectionHandedBackAfterClose(t *testing.T) {
+	s, _ := newPooledSender(t, PoolConfig{})
+	pc, _, err := s.pool.get(connKey{mx: loopbackMX, tls: tlsOpportunistic})
+	require.Nil(t, err)
+
+	s.pool.close()
+	s.pool.put(pc)
+
+	assert.Zero(t, s.PoolStats().Idle)
+	assert.Error(t, pc.client.Noop(), "the connection was closed")
+}
+
+// An IdleTimeout too short for a ticker still makes a pool.
+func TestPoolAcceptsTheShortestIdleTimeout(t *testing.T) {
+	p := newConnPool(PoolConfig{IdleTimeout: time.Nanosecond}, nil)
+	p.close()
+}
@@ -0,0 +1,137 @@
+package smtp
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gosmtp "github.com/emersion/go-smtp"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is an MX host on the loopback interface that counts the sessions
+// opened to it and the messages it accepted. It refuses any recipient at
+// refused.test, replying 550 and leaving the session open.
+type receiver struct {
+	mu       sync.Mutex
+	conns    []*gosmtp.Conn
+	messages int
+}
+
+func (r *receiver) NewSession(c *gosmtp.Conn) (gosmtp.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conns = append(r.conns, c)
+	return &receiverSession{r: r}, nil
+}
+
+func (r *receiver) sessions() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.conns)
+}
+
+func (r *receiver) accepted() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.messages
+}
+
+// dropAll closes every session from the receiver's side, the way a host that
+// times out idle clients does.
+func (r *receiver) dropAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.conns {
+		_ = c.Close()
+	}
+}
+
+type receiverSession struct {
+	r *receiver
+}
+
+func (s *receiverSession) Mail(string, *gosmtp.MailOptions) error { return nil }
+
+func (s *receiverSession) Rcpt(to string, _ *gosmtp.RcptOptions) error {
+	if strings.HasSuffix(to, "@refused.test") {
+		return &gosmtp.SMTPError{Code: 550, EnhancedCode: gosmtp.EnhancedCode{5, 1, 1}, Message: "no such user"}
+	}
+	return nil
+}
+
+func (s *receiverSession) Data(r io.Reader) error {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return err
+	}
+	s.r.mu.Lock()
+	s.r.messages++
+	s.r.mu.Unlock()
+	return nil
+}
+
+func (s *receiverSession) Reset()        {}
+func (s *receiverSession) Logout() error { return nil }
+
+// receiverSetup is what a receiverOption may change before the receiver is
+// started: the server, and the listener it serves on.
+type receiverSetup struct {
+	srv  *gosmtp.Server
+	wrap func(net.Listener) net.Listener
+}
+
+type receiverOption func(*receiverSetup)
+
+// withServer changes the server, whose domain is mx.test and whose timeouts
+// are ten seconds until it does.
+func withServer(fn func(srv *gosmtp.Server)) receiverOption {
+	return func(r *receiverSetup) { fn(r.srv) }
+}
+
+// withImplicitTLS serves TLS from the first byte, as a submission port does,
+// rather than after STARTTLS.
+func withImplicitTLS(config *tls.Config) receiverOption {
+	return func(r *receiverSetup) {
+		r.wrap = func(l net.Listener) net.Listener { return tls.NewListener(l, config) }
+	}
+}
+
+// startReceiver serves backend on a port of the loopback interface until the
+// test ends, and returns the address it listens on.
+func startReceiver(t *testing.T, backend gosmtp.Backend, opts ...receiverOption) string {
+	t.Helper()
+
+	r := &receiverSetup{srv: gosmtp.NewServer(backend)}
+	r.srv.Domain = "mx.test"
+	r.srv.ReadTimeout = 10 * time.Second
+	r.srv.WriteTimeout = 10 * time.Second
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	if r.wrap != nil {
+		l = r.wrap(l)
+	}
+	go func() { _ = r.srv.Serve(l) }()
+	t.Cleanup(func() { _ = r.srv.Close() })
+	return l.Addr().String()
+}
+
+// newLoopbackSender is a Sender that dials every MX host on the port of addr,
+// closed when the test ends.
+func newLoopbackSender(t *testing.T, addr string, opts ...SenderOption) *sender {
+	t.Helper()
+	_, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	s := NewSender("sender.test", opts...).(*sender)
+	s.port = port
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
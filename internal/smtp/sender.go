@@ -6,15 +6,20 @@ package smtp
 */
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
 	"net/smtp"
 	"net/textproto"
+	"strings"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/bodystore"
+	"github.com/kannon-email/kannon/internal/resolver"
 	"golang.org/x/net/idna"
 )
 
@@ -31,6 +36,17 @@ type smtpError struct {
 	err         error
 	isPermanent bool
 	code        uint32
+	// tlsFailure is set when the MX host could not be secured as its TLS
+	// policy requires, to the TLS-RPT result saying why.
+	tlsFailure string
+	// stage, enhanced and reply are what the failure puts in the Transcript:
+	// the step it happened at, its RFC 3463 code and the reply it came with.
+	stage    Stage
+	enhanced string
+	reply    string
+	// remoteIP is the host's address, for a session that failed after
+	// connecting and so has no connection left to ask.
+	remoteIP string
 }
 
 func (e smtpError) Error() string {
@@ -53,8 +69,34 @@ func newSMTPError(err error, isPermanent bool, code uint32) *smtpError {
 	}
 }
 
+// status gives e the enhanced status code it is reported with.
+func (e *smtpError) status(enhanced string) *smtpError {
+	e.enhanced = enhanced
+	return e
+}
+
+// wrap is e with err in place of its message, reported as transient or not
+// by isPermanent and otherwise as e is.
+func (e *smtpError) wrap(err error, isPermanent bool) *smtpError {
+	w := *e
+	w.err = err
+	w.isPermanent = isPermanent
+	return &w
+}
+
 type sender struct {
 	Hostname string
+	// port is the port MX hosts are dialled on: smtpPort, except in tests.
+	port     string
+	poolCfg  PoolConfig
+	pool     *connPool
+	routes   *RoutingTable
+	tlsCfg   TLSPolicyConfig
+	policy   *tlsPolicy
+	ipPools  *IPPools
+	resolver resolver.Resolver
+	family   AddressFamily
+	circuits CircuitBreaker
 }
 
 // SenderName implements sender name function
@@ -63,157 +105,485 @@ func (s *sender) SenderName() string {
 	return s.Hostname
 }
 
-// Send email
-func (s *sender) Send(from, to string, msg []byte) SenderError {
+// PoolStats implements PoolReporter.
+func (s *sender) PoolStats() PoolStats {
+	return s.pool.stats()
+}
+
+// TLSReport implements TLSReporter.
+func (s *sender) TLSReport() []TLSCount {
+	return s.policy.reports.drain()
+}
+
+// Sources implements SourceChooser.
+func (s *sender) Sources(from, to string, opts SendOptions) (string, []string) {
+	toDomain, err := GetEmailDomain(to)
+	if err != nil || s.routes.relayFor(from, toDomain) != nil {
+		return "", nil
+	}
+	return s.ipPools.sources(opts.IPPool, toDomain, s.family)
+}
+
+// Close QUITs the connections the Sender holds open. It may still send
+// afterwards, opening new ones.
+func (s *sender) Close() error {
+	s.pool.close()
+	return nil
+}
+
+// Send email.
+//
+// The message is sent from an address of the IP pool opts names (IPPools), the
+// same address for every MX host tried: opts.SourceIP, or the one the pool's
+// selection picks.
+//
+// Each MX host is held to the TLS policy that applies to it (TLSPolicyConfig,
+// SendOptions.RequireTLS). A host that cannot be secured as its policy
+// requires is passed over for the next, as one that cannot be reached is; when
+// none is left the message fails with CodeTLSPolicy.
+//
+// A host whose circuit is open (WithCircuitBreaker) is passed over without
+// being dialled; when every host is, the message fails transiently without a
+// connection having been opened.
+//
+// Sent.Transcript is the session with the last host tried, and carries the
+// enhanced status code of every failure, one before any session included.
+func (s *sender) Send(from, to string, body Body, opts SendOptions) (Sent, SenderError) {
+	sent, err := s.send(from, to, body, opts)
+	if err != nil {
+		sent.Transcript.failed(err)
+		return sent, err
+	}
+	return sent, nil
+}
+
+func (s *sender) send(from, to string, body Body, opts SendOptions) (Sent, *smtpError) {
 	toDomain, err := GetEmailDomain(to)
 	slog.Info(fmt.Sprintf("domain %v\n", toDomain))
 	if err != nil {
 		// CHECK: 510: indiritto email errato
-		return newSMTPError(err, true, 510)
+		return Sent{}, newSMTPError(err, true, 510).status("5.1.3")
+	}
+
+	if r := s.routes.relayFor(from, toDomain); r != nil {
+		return s.sendRelay(from, to, body, r, opts)
 	}
 
-	mxs, lerr := lookupMXs(toDomain)
+	mxs, lerr := s.lookupMXs(toDomain)
 	if lerr != nil {
-		return lerr
+		return Sent{}, lerr
 	}
 
 	if len(mxs) == 0 {
-		return newSMTPError(errors.New("recipient domain has null MX"), true, 550)
+		// RFC 7505: the domain says it accepts no mail at all.
+		return Sent{}, newSMTPError(errors.New("recipient domain has null MX"), true, 550).status("5.1.10")
 	}
 
+	src := s.ipPools.sourceFor(opts.IPPool, toDomain, opts.SourceIP)
+	if opts.IPPool != "" && src.pool != opts.IPPool {
+		slog.Warn("IP pool is not defined, sending from the default pool", "ip_pool", opts.IPPool, "default", src.pool)
+	}
+	sent := Sent{IPPool: src.pool}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tlsPolicyTimeout)
+	defer cancel()
+	sts := s.policy.domainPolicy(ctx, toDomain)
+
 	var lastErr *smtpError
 	for _, mx := range mxs {
-		err := deliver(from, to, msg, mx, false, s.Hostname)
+		if !s.circuitAllows(mx) {
+			sent.Transcript = Transcript{MX: mx}
+			lastErr = circuitOpenError(mx)
+			continue
+		}
+		mp := s.policy.forMX(ctx, toDomain, sts, mx, opts.RequireTLS)
+		var at attempt
+		var err *smtpError
+		if mp.failure != "" {
+			at, err = attempt{tlsResult: mp.failure, transcript: Transcript{MX: mx}}, tlsPolicyError(mp.err, mp.failure)
+		} else {
+			at, err = s.deliverMX(mx, src, mp.mode, from, to, body, opts)
+			s.recordCircuit(mx, err)
+		}
+		if at.tlsResult != "" {
+			s.policy.reports.add(toDomain, mp.policy, at.tlsResult)
+		}
+		if at.sourceIP != "" {
+			sent.SourceIP = at.sourceIP
+		}
+		sent.Transcript = at.transcript
 		if err == nil {
-			return nil
+			return sent, nil
 		}
-		if err.code > 200 {
+		if err.code > 200 && err.tlsFailure == "" {
 			slog.Info(fmt.Sprintf("Error sending email to %v, cannot retry other MXs", to), "err", err)
-			return err
+			return sent, err
 		}
 
 		lastErr = err
 	}
-	err = fmt.Errorf("all MXs failed, last error: %w", lastErr)
-	return newSMTPError(err, false, lastErr.Code())
+	return sent, lastErr.wrap(fmt.Errorf("all MXs failed, last error: %w", lastErr), false)
+}
+
+// deliverMX sends one message to one MX host, dialling its addresses in the
+// order the address family gives, each from an address of src's pool of the
+// same family, until one answers. A host that cannot be reached at one address
+// — a broken IPv6 route, most often — is tried at the next before the message
+// falls back to the next host; any failure once a session is open ends it.
+func (s *sender) deliverMX(mx string, src source, mode tlsMode, from, to string, body Body, opts SendOptions) (attempt, *smtpError) {
+	ctx, cancel := context.WithTimeout(context.Background(), smtpDialTimeout)
+	ips, err := s.resolver.LookupIP(ctx, mx)
+	cancel()
+	if err != nil {
+		slog.Debug(fmt.Sprintf("Cannot resolve MX host %v: %v", mx, err))
+		return attempt{transcript: Transcript{MX: mx}}, sessionError(err, StageConnect, "4.4.3")
+	}
+
+	at := attempt{transcript: Transcript{MX: mx}}
+	serr := sessionError(fmt.Errorf("%s has no address the Sender can dial under address_family %q", mx, string(s.family)), StageConnect, "4.4.4")
+	for _, ip := range s.family.order(ips) {
+		local, ok := s.ipPools.toward(src, ip)
+		if !ok {
+			continue
+		}
+		key := connKey{mx: mx, ip: ip.String(), localIP: local.ip, helo: local.helo, tls: mode}
+		at, serr = s.deliverKey(key, from, to, body, opts)
+		if serr == nil || serr.code != CodeNoReply || serr.stage != StageConnect {
+			break
+		}
+	}
+	return at, serr
+}
+
+// attempt is what delivering to one host reports besides its error: how the
+// session was secured, as a TLS-RPT result — empty when none was opened for
+// reasons that have nothing to do with TLS — the local address it was opened
+// from, and its Transcript so far as the session went.
+type attempt struct {
+	tlsResult  string
+	sourceIP   string
+	transcript Transcript
+}
+
+// deliverKey sends one message on a connection of key, a relay's included,
+// reusing a pooled one when one is open.
+//
+// A reused connection can have been dropped by the receiver after it answered
+// RSET, and the first sign of it is MAIL FROM failing without a reply. Nothing
+// of the message has been handed over at that point, so it is sent again once
+// on a connection opened for it rather than costing the Delivery an attempt.
+func (s *sender) deliverKey(key connKey, from, to string, body Body, opts SendOptions) (attempt, *smtpError) {
+	pc, reused, err := s.pool.get(key)
+	if err != nil {
+		return attempt{tlsResult: err.tlsFailure, transcript: Transcript{MX: key.mx}}, err
+	}
+
+	tx := transact(pc, from, to, body, opts)
+	if tx.err != nil && reused && !tx.mailed && tx.err.code == 0 {
+		s.pool.evict(pc)
+		slog.Debug(fmt.Sprintf("Pooled connection to %v is gone, opening a new one: %v", key.mx, tx.err))
+		if pc, err = s.pool.open(key); err != nil {
+			return attempt{tlsResult: err.tlsFailure, transcript: Transcript{MX: key.mx}}, err
+		}
+		tx = transact(pc, from, to, body, opts)
+	}
+
+	if tx.keep {
+		s.pool.put(pc)
+	} else {
+		s.pool.evict(pc)
+	}
+	return attempt{tlsResult: pc.tlsResult, sourceIP: sourceIPOf(pc.conn), transcript: tx.transcript}, tx.err
+}
+
+// dial opens a session to key's MX host, greeted and secured as key's TLS mode
+// requires; or, for a relay's key, to the relay.
+func (s *sender) dial(key connKey) (*pooledConn, *smtpError) {
+	switch {
+	case key.transport != "":
+		return s.dialRelay(key, s.routes.relays[key.transport])
+	case key.tls != tlsOpportunistic:
+		return s.dialEnforced(key)
+	}
+	return s.dialTLS(key, false)
 }
 
-func deliver(from, to string, msg []byte, mx string, insecure bool, domain string) *smtpError {
-	smtpURL := fmt.Sprintf("%v:%v", mx, smtpPort)
-	conn, err := net.DialTimeout("tcp", smtpURL, smtpDialTimeout)
+// dialEnforced opens a session that must be under TLS the policy accepts:
+// STARTTLS that the host offers and that succeeds, with no retry that skips
+// verification and no carrying on in plaintext.
+func (s *sender) dialEnforced(key connKey) (*pooledConn, *smtpError) {
+	ctx, cancel := context.WithTimeout(context.Background(), tlsPolicyTimeout)
+	defer cancel()
+	config, err := s.policy.tlsConfig(ctx, key)
+	if err != nil {
+		return nil, tlsPolicyError(err, tlsResultTLSAInvalid)
+	}
+
+	conn, c, serr := s.greet(key)
+	if serr != nil {
+		return nil, serr
+	}
+	if ok, _ := c.Extension("STARTTLS"); !ok {
+		conn.Close()
+		return nil, connectedError(tlsPolicyError(fmt.Errorf("%s does not offer STARTTLS", key.mx), tlsResultStartTLSNotSupported), conn)
+	}
+	if err := c.StartTLS(config); err != nil {
+		conn.Close()
+		slog.Debug(fmt.Sprintf("TLS error under policy: %v", err))
+		return nil, connectedError(tlsPolicyError(fmt.Errorf("STARTTLS with %s failed: %w", key.mx, err), tlsFailureOf(err)), conn)
+	}
+	return &pooledConn{key: key, conn: conn, client: c, tlsResult: tlsResultSuccess}, nil
+}
+
+// greet connects to key's MX host from key's local address and says EHLO with
+// key's name.
+func (s *sender) greet(key connKey) (net.Conn, *smtp.Client, *smtpError) {
+	dialer := net.Dialer{Timeout: smtpDialTimeout}
+	if key.localIP != "" {
+		dialer.LocalAddr = &net.TCPAddr{IP: net.ParseIP(key.localIP)}
+	}
+	host := key.ip
+	if host == "" {
+		host = key.mx
+	}
+	conn, err := dialer.Dial("tcp", net.JoinHostPort(host, s.port))
 	if err != nil {
 		slog.Debug(fmt.Sprintf("Could not dial: %v", err))
-		// TODO: add error code
-		// Cannot dial SMTP 111
-		return newSMTPError(err, false, 111)
+		e := sessionError(err, StageConnect, "4.4.1")
+		e.remoteIP = key.ip
+		return nil, nil, e
 	}
-	defer conn.Close()
-	if err := conn.SetDeadline(time.Now().Add(smtpTotalTimeout)); err != nil {
+	if err := setTotalDeadline(conn); err != nil {
+		conn.Close()
 		slog.Debug(fmt.Sprintf("Cannot set deadline: %v", err))
-		// TODO: add error code
-		return newSMTPError(err, false, 111)
+		return nil, nil, sessionError(err, StageConnect, "4.4.2")
 	}
 
-	c, err := smtp.NewClient(conn, mx)
+	c, err := smtp.NewClient(conn, key.mx)
 	if err != nil {
+		conn.Close()
 		slog.Debug(fmt.Sprintf("Error creating client: %v", err))
-		// TODO: add error code
-		return newSMTPError(err, false, 111)
+		return nil, nil, connectedError(sessionError(err, StageConnect, "4.4.2"), conn)
 	}
 
-	if err = c.Hello(domain); err != nil {
+	helo := key.helo
+	if helo == "" {
+		helo = s.Hostname
+	}
+	if err = c.Hello(helo); err != nil {
+		conn.Close()
 		slog.Debug(fmt.Sprintf("Error saying hello: %v", err))
-		// TODO: add error code
-		return newSMTPError(err, false, 111)
+		return nil, nil, connectedError(sessionError(err, StageEHLO, "4.4.2"), conn)
+	}
+	return conn, c, nil
+}
+
+// connectedError is e, a failure on a session conn had opened, with the
+// address conn was connected to.
+func connectedError(e *smtpError, conn net.Conn) *smtpError {
+	e.remoteIP = remoteIPOf(conn)
+	return e
+}
+
+// dialTLS opens a session under opportunistic TLS.
+func (s *sender) dialTLS(key connKey, insecure bool) (*pooledConn, *smtpError) {
+	conn, c, serr := s.greet(key)
+	if serr != nil {
+		return nil, serr
 	}
 
-	if ok, _ := c.Extension("STARTTLS"); ok {
-		config := &tls.Config{
-			ServerName:         mx,
-			InsecureSkipVerify: insecure,
+	if ok, _ := c.Extension("STARTTLS"); !ok {
+		return &pooledConn{key: key, conn: conn, client: c, tlsResult: tlsResultStartTLSNotSupported}, nil
+	}
+	config := &tls.Config{
+		ServerName:         strings.TrimSuffix(key.mx, "."),
+		InsecureSkipVerify: insecure,
+		RootCAs:            s.policy.roots,
+	}
+	if err := c.StartTLS(config); err != nil {
+		conn.Close()
+		// Unfortunately, many servers use self-signed certs, so if we
+		// fail verification we just try again without validating.
+		if insecure {
+			slog.Debug(fmt.Sprintf("TLS error: %v", err))
+			return nil, connectedError(sessionError(err, StageSTARTTLS, "4.7.5"), conn)
 		}
-		err = c.StartTLS(config)
-		if err != nil {
-			// Unfortunately, many servers use self-signed certs, so if we
-			// fail verification we just try again without validating.
-			if insecure {
-				slog.Debug(fmt.Sprintf("TLS error: %v", err))
-				// TODO: add error code
-				return newSMTPError(err, false, 111)
-			}
-			slog.Debug("TLS error, retrying insecurely\n")
-			return deliver(from, to, msg, mx, true, domain)
+		slog.Debug("TLS error, retrying insecurely\n")
+		pc, serr := s.dialTLS(key, true)
+		if pc != nil {
+			pc.tlsResult = tlsFailureOf(err)
 		}
+		return pc, serr
+	}
+	return &pooledConn{key: key, conn: conn, client: c, tlsResult: tlsResultSuccess}, nil
+}
+
+// setTotalDeadline gives whatever happens next on conn smtpTotalTimeout.
+func setTotalDeadline(conn net.Conn) error {
+	return conn.SetDeadline(time.Now().Add(smtpTotalTimeout))
+}
+
+// txResult is how one mail transaction ended.
+type txResult struct {
+	err *smtpError
+	// mailed is whether the receiver answered MAIL FROM at all.
+	mailed bool
+	// keep is whether the session is still in step with the receiver, and so
+	// fit for the next message after an RSET: true after a success and after a
+	// reply that refused this message, false when the connection failed or the
+	// message was cut short mid-DATA.
+	keep bool
+	// transcript is the session and the transaction as it went.
+	transcript Transcript
+}
+
+// transact runs one mail transaction on an open session.
+func transact(pc *pooledConn, from, to string, body Body, opts SendOptions) txResult {
+	t := transcriptOf(pc)
+	tx := transactOn(pc, &t, from, to, body, opts)
+	tx.transcript = t
+	return tx
+}
+
+func transactOn(pc *pooledConn, t *Transcript, from, to string, body Body, opts SendOptions) txResult {
+	if err := setTotalDeadline(pc.conn); err != nil {
+		slog.Debug(fmt.Sprintf("Cannot set deadline: %v", err))
+		return txResult{err: newSMTPError(err, false, CodeNoReply).status("4.4.2")}
 	}
+	c := pc.client
 
-	if err := c.Mail(from); err != nil {
+	if strings.ContainsAny(from+to, "\r\n") {
+		return refused(StageMAIL, errors.New("smtp: A line must not contain CR or LF"), false)
+	}
+
+	params, from, to, serr := mailParams(c, from, to, opts)
+	if serr != nil {
+		return txResult{err: serr, keep: true}
+	}
+	if _, err := t.cmd(c, 250, "MAIL FROM:<%s>%s", from, params); err != nil {
 		slog.Debug(fmt.Sprintf("err: %v\n", err))
-		return newSMTPErrorFromSTMP(err)
+		return refused(StageMAIL, err, false)
 	}
 
-	if err := c.Rcpt(to); err != nil {
+	if _, err := t.cmd(c, 25, "RCPT TO:<%s>", to); err != nil {
 		slog.Debug(fmt.Sprintf("err: %v\n", err))
-		return newSMTPErrorFromSTMP(err)
+		return refused(StageRCPT, err, true)
 	}
 
-	w, err := c.Data()
+	msg, err := body()
 	if err != nil {
+		slog.Debug(fmt.Sprintf("Cannot open the message body: %v", err))
+		return txResult{
+			err:    bodyError(fmt.Errorf("cannot open the message body: %w", err)),
+			mailed: true,
+			keep:   true,
+		}
+	}
+	defer msg.Close()
+
+	if _, err := t.cmd(c, 354, "DATA"); err != nil {
 		slog.Debug(fmt.Sprintf("err: %v\n", err))
-		return newSMTPErrorFromSTMP(err)
+		return refused(StageDATA, err, true)
+	}
+	w := c.Text.DotWriter()
+	src := &bodyReader{r: msg}
+	_, err = io.Copy(w, src)
+	if src.err != nil {
+		// Returning without closing w leaves DATA unterminated, and evicting
+		// the connection drops it: the relay discards the transaction rather
+		// than delivering a message cut short.
+		slog.Debug(fmt.Sprintf("Cannot read the message body: %v", src.err))
+		return txResult{
+			err:    bodyError(fmt.Errorf("cannot read the message body: %w", src.err)),
+			mailed: true,
+		}
+	}
+	if err == nil {
+		err = w.Close()
 	}
-	_, err = w.Write(msg)
 	if err != nil {
 		slog.Debug(fmt.Sprintf("err: %v\n", err))
-		return newSMTPErrorFromSTMP(err)
+		return txResult{err: replyError(StageDATA, err), mailed: true}
 	}
 
-	err = w.Close()
+	reply, err := t.read(c, 250)
 	if err != nil {
 		slog.Debug(fmt.Sprintf("err: %v\n", err))
-		return newSMTPErrorFromSTMP(err)
+		return refused(StageDATA, err, true)
 	}
+	t.QueueID = queueIDOf(reply)
 
-	if err := c.Quit(); err != nil {
-		slog.Debug(fmt.Sprintf("err: %v\n", err))
-		return newSMTPErrorFromSTMP(err)
+	return txResult{mailed: true, keep: true}
+}
+
+// refused is the result of a command at stage that failed: with a reply the
+// session carries on, unless the reply is 421 — the receiver closing the
+// connection.
+func refused(stage Stage, err error, mailed bool) txResult {
+	serr := replyError(stage, err)
+	return txResult{
+		err:    serr,
+		mailed: mailed || serr.code != 0,
+		keep:   serr.code != 0 && serr.code != 421,
+	}
+}
+
+// bodyError is a message whose body could not be read out of the body store.
+// 451 is "local error in processing": transient, and above the codes Send
+// falls back to the next MX for, since the next MX would be handed the same
+// unreadable body. A body the store does not hold — expired, or never
+// written — is 554 instead: no retry will find it.
+func bodyError(err error) *smtpError {
+	e := newSMTPError(err, false, 451).status("4.3.0")
+	if errors.Is(err, bodystore.ErrNotFound) {
+		e = newSMTPError(err, true, 554).status("5.3.0")
 	}
+	e.stage = StageDATA
+	return e
+}
 
-	return nil
+// bodyReader records a failure to read the body, which io.Copy reports in the
+// same breath as a failure to write it to the relay.
+type bodyReader struct {
+	r   io.Reader
+	err error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
 }
 
-func lookupMXs(domain string) ([]string, *smtpError) {
+// lookupMXs is the hosts to try for domain, at most five, most preferred
+// first. None is a null MX. A domain the DNS says does not exist, or that has
+// neither MX nor address, is a permanent failure; a lookup no nameserver
+// answered is a transient one, retried with the message.
+func (s *sender) lookupMXs(domain string) ([]string, *smtpError) {
 	domain, err := idna.ToASCII(domain)
 	if err != nil {
-		// TODO: add error code
-		return nil, newSMTPError(err, true, 512)
+		return nil, newSMTPError(err, true, 512).status("5.1.2")
 	}
 
-	mxs := []string{}
-
-	mxRecords, err := net.LookupMX(domain)
+	mxRecords, err := s.resolver.LookupMX(context.Background(), domain)
 	if err != nil {
-		// TODO: Better handle Temporary errors.
-		dnsErr := &net.DNSError{}
-		ok := errors.As(err, &dnsErr)
-		if !ok || !dnsErr.IsNotFound {
-			slog.Debug(fmt.Sprintf("MX lookup error: %v", err))
-			// TODO: add error code
-			return nil, newSMTPError(dnsErr, !dnsErr.Temporary(), 512)
-		}
-		// Permanent error, we assume MX does not exist and fall back to A.
-		slog.Debug(fmt.Sprintf("failed to resolve MX for %s, falling back to A", domain))
-		mxs = []string{domain}
-	} else {
-		// Convert the DNS records to a plain string slice. They're already
-		// sorted by priority.
-		for _, r := range mxRecords {
-			mxs = append(mxs, r.Host)
+		slog.Debug(fmt.Sprintf("MX lookup error: %v", err))
+		var rerr *resolver.Error
+		switch {
+		case errors.Is(err, resolver.ErrNXDomain):
+			return nil, newSMTPError(err, true, 512).status("5.1.2")
+		case errors.As(err, &rerr) && !rerr.Temporary():
+			return nil, newSMTPError(err, true, 512).status("5.4.4")
 		}
+		return nil, newSMTPError(err, false, 512).status("4.4.3")
 	}
 
-	// Note that mxs could be empty; in that case we do NOT fall back to A.
-	// This case is explicitly covered by the SMTP RFC.
-	// https://tools.ietf.org/html/rfc5321#section-5.1
+	mxs := make([]string, 0, len(mxRecords))
+	for _, r := range mxRecords {
+		mxs = append(mxs, r.Host)
+	}
 
 	// Cap the list of MXs to 5 hosts, to keep delivery attempt times
 	// sane and prevent abuse.
@@ -225,15 +595,25 @@ func lookupMXs(domain string) ([]string, *smtpError) {
 	return mxs, nil
 }
 
+// replyError is the failure of a command at stage: the reply it drew, or —
+// with code 0 — the connection that failed before one came.
+func replyError(stage Stage, err error) *smtpError {
+	e := newSMTPErrorFromSTMP(err)
+	e.stage = stage
+	return e
+}
+
 func newSMTPErrorFromSTMP(err error) *smtpError {
 	terr := &textproto.Error{}
 	ok := errors.As(err, &terr)
 	if !ok {
 		// unknown error
-		return newSMTPError(err, false, 0)
+		return newSMTPError(err, false, 0).status("4.4.2")
 	}
 
 	isPermanent := terr.Code >= 500 && terr.Code < 600
 
-	return newSMTPError(err, isPermanent, uint32(terr.Code))
+	e := newSMTPError(err, isPermanent, uint32(terr.Code)).status(enhancedCodeOf(terr.Code, terr.Msg))
+	e.reply = replyText(terr.Code, terr.Msg)
+	return e
 }
@@ -1,17 +1,157 @@
 package smtp
 
+import (
+	"bytes"
+	"io"
+
+	"github.com/kannon-email/kannon/internal/resolver"
+)
+
 // Sender interface represents a email sender
 // object that can send a message
 type Sender interface {
-	Send(from string, to string, msg []byte) SenderError
+	Send(from string, to string, body Body, opts SendOptions) (Sent, SenderError)
 	SenderName() string
 }
 
-// NewSender construct a new sender for a given hostname
-func NewSender(hostname string) Sender {
-	return &sender{
+// SendOptions is what one message asks of the Sender beyond its addresses and
+// body. The zero SendOptions sends it as the Sender's configuration says.
+type SendOptions struct {
+	// RequireTLS sends the message only over STARTTLS to an MX host whose
+	// certificate verifies — against DANE records where the host has them —
+	// and fails it with CodeTLSPolicy, transiently, when no MX host can offer
+	// that.
+	RequireTLS bool
+	// IPPool names the IP pool the message is sent from, the default pool
+	// when empty or when the Sender has no pool by that name.
+	IPPool string
+	// SourceIP is the address of the pool to send from, one SourceChooser
+	// offered. Empty, or not an address of the pool, leaves the choice to the
+	// pool's selection.
+	SourceIP string
+	// Size is the message's length in bytes, declared to a receiver that
+	// offers SIZE; one larger than the receiver's limit fails permanently
+	// without being sent. Zero declares nothing.
+	Size int64
+	// SMTPUTF8 is set for a message whose header needs SMTPUTF8: it fails
+	// permanently at a receiver that does not offer it, as one to or from an
+	// internationalised local part does whatever this says.
+	SMTPUTF8 bool
+}
+
+// SourceChooser is a Sender that sends from IP pools and can say, before a
+// message is sent, which addresses it could go out from. The SMTPSender asks
+// it when an address's warm-up has to be consulted before the message is
+// handed over, and names the address it settled on in SendOptions.SourceIP.
+type SourceChooser interface {
+	// Sources returns the pool a message is sent from and its addresses, in
+	// the order the pool's selection would use them; none for a message that
+	// is sent from no pool, or through a relay.
+	Sources(from, to string, opts SendOptions) (pool string, addresses []string)
+}
+
+// Sent is where a Send sent the message from, whether or not it was accepted:
+// a Bounce is as much a fact about the sending address as a delivery is.
+type Sent struct {
+	// IPPool is the pool the address was chosen from, empty for none.
+	IPPool string
+	// SourceIP is the local address of the last connection the message went
+	// over, empty when none was opened.
+	SourceIP string
+	// Transcript is the session the message was last offered over, and how it
+	// fared there.
+	Transcript Transcript
+}
+
+// Body is the message a Send transmits, opened afresh for every attempt: a
+// Send that falls back to the next MX writes the message again from its first
+// byte, and a body streamed out of the body store cannot be rewound.
+type Body func() (io.ReadCloser, error)
+
+// BytesBody is a Body already held in memory.
+func BytesBody(b []byte) Body {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+}
+
+// SenderOption configures a Sender beyond its hostname.
+type SenderOption func(*sender)
+
+// WithPool sets how the Sender keeps connections open between messages.
+// Without it the pool runs on its defaults.
+func WithPool(cfg PoolConfig) SenderOption {
+	return func(s *sender) {
+		s.poolCfg = cfg
+	}
+}
+
+// WithRoutingTable routes messages through the relays the table names.
+// Without it every message is delivered directly to the recipient's MX hosts.
+func WithRoutingTable(rt *RoutingTable) SenderOption {
+	return func(s *sender) {
+		s.routes = rt
+	}
+}
+
+// WithIPPools sends mail from the pools' addresses, greeting with their
+// hostnames. Without it every message is sent from the address the system
+// chooses. Relays are not subject to pools: what a receiver sees is the relay's
+// address, not ours.
+func WithIPPools(pools *IPPools) SenderOption {
+	return func(s *sender) {
+		s.ipPools = pools
+	}
+}
+
+// WithTLSPolicy holds MX hosts to MTA-STS and DANE as cfg enables them.
+// Without it only a message that requires TLS is held to more than
+// opportunistic STARTTLS. cfg is expected to have been validated.
+func WithTLSPolicy(cfg TLSPolicyConfig) SenderOption {
+	return func(s *sender) {
+		s.tlsCfg = cfg
+	}
+}
+
+// WithAddressFamily dials MX hosts over the address family f names. Without
+// it IPv4 addresses are preferred. f is expected to have been validated.
+func WithAddressFamily(f AddressFamily) SenderOption {
+	return func(s *sender) {
+		s.family = f
+	}
+}
+
+// WithResolver looks MX hosts up through r, which the Sender shares with
+// whatever else r serves. Without it the Sender has a resolver of its own that
+// asks the system's.
+func WithResolver(r resolver.Resolver) SenderOption {
+	return func(s *sender) {
+		s.resolver = r
+	}
+}
+
+// NewSender construct a new sender for a given hostname.
+//
+// The Sender keeps connections to MX hosts open and sends the next message
+// for the same host over them (PoolConfig); Close it to QUIT them on the way
+// out.
+func NewSender(hostname string, opts ...SenderOption) Sender {
+	s := &sender{
 		Hostname: hostname,
+		port:     smtpPort,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.resolver == nil {
+		s.resolver = resolver.New(resolver.Config{})
+	}
+	if s.family == "" {
+		s.family = FamilyPreferIPv4
 	}
+	s.policy = newTLSPolicy(s.tlsCfg)
+	s.pool = newConnPool(s.poolCfg, s.dial)
+	return s
 }
 
 // SenderError represent an smtp error
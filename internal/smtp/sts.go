@@ -0,0 +1,277 @@
+package smtp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// stsRecheck is how often a domain's `_mta-sts` TXT record is looked at
+	// again. The record's id is how a domain announces a new policy, so it
+	// bounds how long an updated policy goes unnoticed; the policy itself is
+	// fetched again only when the id changes or its max_age runs out.
+	stsRecheck = 10 * time.Minute
+	// stsMaxPolicy is the largest policy file read (RFC 8461 §3.3 suggests
+	// senders cap it at 64 KiB).
+	stsMaxPolicy = 64 << 10
+	// stsFetchTimeout bounds the HTTPS fetch of a policy file.
+	stsFetchTimeout = 10 * time.Second
+	// stsMaxAge caps the max_age a policy may state (RFC 8461 §3.2).
+	stsMaxAge = 31557600 * time.Second
+	// stsCacheMax bounds how many domains are remembered. Past it the cache
+	// starts over, which costs a TXT lookup per domain and keeps no policy
+	// past its max_age either way.
+	stsCacheMax = 10000
+)
+
+type stsMode string
+
+const (
+	stsEnforce stsMode = "enforce"
+	stsTesting stsMode = "testing"
+	stsNone    stsMode = "none"
+)
+
+// stsPolicy is a domain's MTA-STS policy file, parsed.
+type stsPolicy struct {
+	// id is the TXT record's id the policy was fetched under.
+	id     string
+	mode   stsMode
+	mx     []string
+	maxAge time.Duration
+}
+
+// parseSTSPolicy reads a policy file (RFC 8461 §3.2). Unknown keys are
+// ignored, as the RFC asks, so that a domain may add to its policy.
+func parseSTSPolicy(b []byte) (*stsPolicy, error) {
+	p := &stsPolicy{}
+	var version string
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "version":
+			version = value
+		case "mode":
+			p.mode = stsMode(value)
+		case "mx":
+			p.mx = append(p.mx, strings.ToLower(value))
+		case "max_age":
+			secs, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("max_age %q is not a number of seconds", value)
+			}
+			p.maxAge = min(time.Duration(secs)*time.Second, stsMaxAge)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case version != "STSv1":
+		return nil, fmt.Errorf("version %q is not STSv1", version)
+	case p.mode != stsEnforce && p.mode != stsTesting && p.mode != stsNone:
+		return nil, fmt.Errorf("mode %q is none of enforce, testing and none", p.mode)
+	case p.maxAge == 0:
+		return nil, errors.New("no max_age")
+	case len(p.mx) == 0 && p.mode != stsNone:
+		return nil, errors.New("no mx")
+	}
+	return p, nil
+}
+
+// matches reports whether mx is one of the policy's hosts: "mx.example.com" is
+// that host, and "*.example.com" any host one label below example.com.
+func (p *stsPolicy) matches(mx string) bool {
+	mx = strings.ToLower(strings.TrimSuffix(mx, "."))
+	for _, pattern := range p.mx {
+		pattern = strings.TrimSuffix(pattern, ".")
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			label, found := strings.CutSuffix(mx, suffix)
+			if found && label != "" && !strings.Contains(label, ".") {
+				return true
+			}
+			continue
+		}
+		if mx == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// stsCache keeps each recipient domain's policy for its max_age, and looks at
+// the domain's TXT record every stsRecheck for a new one.
+type stsCache struct {
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+	fetch     func(ctx context.Context, domain string) ([]byte, error)
+	now       func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]stsEntry
+}
+
+type stsEntry struct {
+	// policy is the domain's last policy fetched, nil when it has none.
+	policy *stsPolicy
+	// expires is when policy stops applying: fetched plus its max_age.
+	expires time.Time
+	// checked is when the TXT record was last looked at.
+	checked time.Time
+}
+
+// active is the entry's policy while it applies, nil otherwise. A policy of
+// mode none applies as none.
+func (e stsEntry) active(now time.Time) *stsPolicy {
+	if e.policy == nil || !now.Before(e.expires) || e.policy.mode == stsNone {
+		return nil
+	}
+	return e.policy
+}
+
+func newSTSCache() *stsCache {
+	return &stsCache{
+		lookupTXT: net.DefaultResolver.LookupTXT,
+		fetch:     fetchSTSPolicy,
+		now:       time.Now,
+		entries:   map[string]stsEntry{},
+	}
+}
+
+// lookup returns domain's policy, nil when none applies, and the TLS-RPT
+// result of a policy that could not be obtained.
+//
+// A domain that stops publishing its TXT record, or whose policy cannot be
+// fetched, is still held to the policy cached for it until its max_age runs
+// out (RFC 8461 §5.1): removing the record is not how a domain turns MTA-STS
+// off, and an attacker who can block the lookups must not be able to either.
+func (c *stsCache) lookup(ctx context.Context, domain string) (*stsPolicy, string) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	now := c.now()
+
+	c.mu.Lock()
+	e, cached := c.entries[domain]
+	c.mu.Unlock()
+	if cached && now.Sub(e.checked) < stsRecheck {
+		return e.active(now), ""
+	}
+
+	e.checked = now
+	id, ok := c.txtID(ctx, domain)
+	if !ok || (e.policy != nil && e.policy.id == id && now.Before(e.expires)) {
+		c.store(domain, e)
+		return e.active(now), ""
+	}
+
+	body, err := c.fetch(ctx, domain)
+	if err != nil {
+		c.store(domain, e)
+		if p := e.active(now); p != nil {
+			return p, ""
+		}
+		return nil, tlsResultSTSPolicyFetchError
+	}
+	p, err := parseSTSPolicy(body)
+	if err != nil {
+		c.store(domain, e)
+		if p := e.active(now); p != nil {
+			return p, ""
+		}
+		return nil, tlsResultSTSPolicyInvalid
+	}
+	p.id = id
+	e = stsEntry{policy: p, expires: now.Add(p.maxAge), checked: now}
+	c.store(domain, e)
+	return e.active(now), ""
+}
+
+func (c *stsCache) store(domain string, e stsEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[domain]; !ok && len(c.entries) >= stsCacheMax {
+		c.entries = map[string]stsEntry{}
+	}
+	c.entries[domain] = e
+}
+
+// txtID reads the id of domain's `_mta-sts` TXT record. A domain with no such
+// record, or more than one, publishes no policy (RFC 8461 §3.1).
+func (c *stsCache) txtID(ctx context.Context, domain string) (string, bool) {
+	txts, err := c.lookupTXT(ctx, "_mta-sts."+domain)
+	if err != nil {
+		return "", false
+	}
+	var record string
+	for _, t := range txts {
+		if !strings.HasPrefix(t, "v=STSv1") {
+			continue
+		}
+		if record != "" {
+			return "", false
+		}
+		record = t
+	}
+	for field := range strings.SplitSeq(record, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		if key == "id" && value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// stsClient fetches policy files. A redirect is refused (RFC 8461 §3.3): the
+// policy is only the domain's if it is served from the domain's own
+// mta-sts host.
+var stsClient = &http.Client{
+	Timeout: stsFetchTimeout,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return errors.New("MTA-STS policies are not fetched through redirects")
+	},
+}
+
+// fetchSTSPolicy fetches domain's policy file over HTTPS, its certificate
+// verified against the system roots.
+func fetchSTSPolicy(ctx context.Context, domain string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"https://mta-sts."+domain+"/.well-known/mta-sts.txt", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := stsClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("policy fetch answered %s", resp.Status)
+	}
+	if mt, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err != nil || mt != "text/plain" {
+		return nil, fmt.Errorf("policy is served as %q, not text/plain", resp.Header.Get("Content-Type"))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, stsMaxPolicy+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > stsMaxPolicy {
+		return nil, errors.New("policy is larger than 64 KiB")
+	}
+	return body, nil
+}
@@ -0,0 +1,279 @@
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/resolver"
+)
+
+// TLSPolicyConfig is the `sender.tls` section: what, beyond opportunistic
+// STARTTLS, the Sender holds an MX host to before handing it a message.
+//
+// With nothing enabled a message goes out as it always has — STARTTLS when
+// offered, an unverifiable certificate accepted, plaintext when STARTTLS is not
+// offered — unless the message itself requires TLS (SendOptions.RequireTLS).
+type TLSPolicyConfig struct {
+	// MTASTS looks up the recipient domain's MTA-STS policy (RFC 8461) and,
+	// when its mode is enforce, sends only to the MX hosts it lists, over
+	// STARTTLS with a certificate that verifies. A policy in testing mode is
+	// only reported on.
+	MTASTS bool `mapstructure:"mta_sts"`
+	// DANE authenticates an MX host against its TLSA records (RFC 7672) when
+	// its zone is DNSSEC-signed. It needs DNSSECResolver.
+	DANE bool `mapstructure:"dane"`
+	// DNSSECResolver is the host:port of a validating resolver the TLSA
+	// records are asked of; its AD bit is trusted, so it should be one the
+	// Sender reaches over a network it trusts — typically a resolver on the
+	// same host. The port defaults to 53.
+	DNSSECResolver string `mapstructure:"dnssec_resolver"`
+}
+
+// Validate refuses a section that cannot be applied as written.
+func (c TLSPolicyConfig) Validate() error {
+	if c.DANE && c.DNSSECResolver == "" {
+		return errors.New("tls: dane needs a dnssec_resolver to ask for TLSA records")
+	}
+	if c.DNSSECResolver != "" {
+		if _, _, err := net.SplitHostPort(resolver.Addr(c.DNSSECResolver)); err != nil {
+			return fmt.Errorf("tls: dnssec_resolver %q is not host or host:port: %w", c.DNSSECResolver, err)
+		}
+	}
+	return nil
+}
+
+// CodeTLSPolicy is the code of a message the TLS policy held back: no MX host
+// could be reached over TLS the policy accepts. It is transient — the
+// recipient's MX hosts may be fixed before the Delivery runs out of time — and
+// is none of the codes an MX host replies with to a message, so it can be told
+// apart from them in the stats.
+const CodeTLSPolicy uint32 = 454
+
+// tlsVerified and tlsDANE are the enforced TLS modes: STARTTLS that must be
+// offered and must succeed, with the certificate verified against the system
+// roots and the MX host's name, or against the host's TLSA records.
+const (
+	tlsVerified tlsMode = "verified"
+	tlsDANE     tlsMode = "dane"
+)
+
+// TLS-RPT policy types (RFC 8460 §4.4): the policy a session was held to.
+const (
+	tlsPolicySTS  = "sts"
+	tlsPolicyTLSA = "tlsa"
+	tlsPolicyNone = "no-policy-found"
+)
+
+// TLS-RPT result types (RFC 8460 §4.3), and success. A session under
+// opportunistic TLS records the failure it fell back from, so a receiver with
+// an untrusted certificate shows up in the counts even while mail to it flows.
+const (
+	tlsResultSuccess                 = "success"
+	tlsResultStartTLSNotSupported    = "starttls-not-supported"
+	tlsResultCertificateHostMismatch = "certificate-host-mismatch"
+	tlsResultCertificateExpired      = "certificate-expired"
+	tlsResultCertificateNotTrusted   = "certificate-not-trusted"
+	tlsResultValidationFailure       = "validation-failure"
+	tlsResultTLSAInvalid             = "tlsa-invalid"
+	tlsResultDNSSECInvalid           = "dnssec-invalid"
+	tlsResultSTSPolicyFetchError     = "sts-policy-fetch-error"
+	tlsResultSTSPolicyInvalid        = "sts-policy-invalid"
+)
+
+// tlsPolicyTimeout bounds the lookups a policy decision makes — the TXT record
+// and policy file of MTA-STS, the TLSA records of DANE — for one message.
+const tlsPolicyTimeout = 15 * time.Second
+
+// tlsPolicy decides how each MX host of a message must be secured, and counts
+// how it went.
+type tlsPolicy struct {
+	sts  *stsCache  // nil when MTA-STS is off
+	dane *daneCache // nil when DANE is off
+	// roots verifies certificates; nil is the system pool.
+	roots   *x509.CertPool
+	reports tlsCounters
+}
+
+func newTLSPolicy(cfg TLSPolicyConfig) *tlsPolicy {
+	p := &tlsPolicy{reports: tlsCounters{counts: map[tlsCountKey]uint64{}}}
+	if cfg.MTASTS {
+		p.sts = newSTSCache()
+	}
+	if cfg.DANE {
+		addr := resolver.Addr(cfg.DNSSECResolver)
+		p.dane = newDANECache(newTLSAQuery(addr), newMXQuery(addr))
+	}
+	return p
+}
+
+// mxPolicy is how one MX host of a message must be secured.
+type mxPolicy struct {
+	mode tlsMode
+	// policy is the TLS-RPT policy type the session is counted under.
+	policy string
+	// failure, when set, is why the host cannot be used at all — its TLS-RPT
+	// result — and err says so in words.
+	failure string
+	err     error
+}
+
+// domainPolicy is the recipient domain's MTA-STS policy, nil when it has none
+// that applies. A failure to obtain one is counted but sends as no policy: RFC
+// 8461 §5 treats a domain whose policy cannot be fetched, and that has none
+// cached, as one that publishes none.
+func (p *tlsPolicy) domainPolicy(ctx context.Context, domain string) *stsPolicy {
+	if p.sts == nil {
+		return nil
+	}
+	sts, failure := p.sts.lookup(ctx, domain)
+	if failure != "" {
+		p.reports.add(domain, tlsPolicySTS, failure)
+	}
+	return sts
+}
+
+// forMX decides how mx, an MX host of domain, must be secured. DANE, where
+// the host publishes TLSA records, takes precedence over MTA-STS (RFC 8461
+// §2); either satisfies a message that requires TLS.
+func (p *tlsPolicy) forMX(ctx context.Context, domain string, sts *stsPolicy, mx string, requireTLS bool) mxPolicy {
+	if p.dane != nil {
+		records, err := p.dane.lookup(ctx, domain, mx)
+		if err != nil {
+			return mxPolicy{policy: tlsPolicyTLSA, failure: tlsResultDNSSECInvalid,
+				err: fmt.Errorf("whether %s is held to DANE cannot be trusted: %w", mx, err)}
+		}
+		if len(records) > 0 {
+			return mxPolicy{mode: tlsDANE, policy: tlsPolicyTLSA}
+		}
+	}
+
+	if sts != nil {
+		if sts.mode != stsEnforce {
+			return mxPolicy{mode: tlsOpportunistic, policy: tlsPolicySTS}
+		}
+		if !sts.matches(mx) {
+			return mxPolicy{policy: tlsPolicySTS, failure: tlsResultValidationFailure,
+				err: fmt.Errorf("MX host %s is not in the MTA-STS policy", mx)}
+		}
+		return mxPolicy{mode: tlsVerified, policy: tlsPolicySTS}
+	}
+
+	if requireTLS {
+		return mxPolicy{mode: tlsVerified, policy: tlsPolicyNone}
+	}
+	return mxPolicy{mode: tlsOpportunistic, policy: tlsPolicyNone}
+}
+
+// tlsConfig is the configuration a session in an enforced mode is secured
+// with.
+func (p *tlsPolicy) tlsConfig(ctx context.Context, key connKey) (*tls.Config, error) {
+	name := strings.TrimSuffix(key.mx, ".")
+	if key.tls != tlsDANE {
+		return &tls.Config{ServerName: name, RootCAs: p.roots}, nil
+	}
+	records, err := p.dane.tlsa(ctx, key.mx)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s no longer publishes TLSA records", key.mx)
+	}
+	return &tls.Config{
+		ServerName: name,
+		// Verification is entirely VerifyConnection's: under DANE the TLSA
+		// records, not the system roots, say which certificate is the host's.
+		InsecureSkipVerify: true,
+		VerifyConnection:   daneVerifier(records, name),
+	}, nil
+}
+
+// tlsPolicyError is a message held back because an MX host could not be
+// secured as its policy requires. It is transient, and Send moves on to the
+// next MX host rather than stopping at it.
+func tlsPolicyError(err error, failure string) *smtpError {
+	e := newSMTPError(fmt.Errorf("TLS policy: %w", err), false, CodeTLSPolicy).status("4.7.5")
+	e.tlsFailure = failure
+	e.stage = StageSTARTTLS
+	return e
+}
+
+// tlsFailureOf names, as a TLS-RPT result, why a handshake failed.
+func tlsFailureOf(err error) string {
+	var hostErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var authorityErr x509.UnknownAuthorityError
+	switch {
+	case errors.Is(err, errTLSAMismatch):
+		return tlsResultValidationFailure
+	case errors.As(err, &hostErr):
+		return tlsResultCertificateHostMismatch
+	case errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired:
+		return tlsResultCertificateExpired
+	case errors.As(err, &invalidErr), errors.As(err, &authorityErr):
+		return tlsResultCertificateNotTrusted
+	}
+	return tlsResultValidationFailure
+}
+
+// TLSCount is one TLS-RPT counter: how many messages to Domain were handed
+// over — or held back — under Policy with Result.
+type TLSCount struct {
+	Domain string
+	Policy string
+	Result string
+	Count  uint64
+}
+
+// TLSReporter is implemented by a Sender that counts how its sessions were
+// secured, per recipient domain, the way a TLS-RPT report (RFC 8460) sums
+// them.
+type TLSReporter interface {
+	// TLSReport returns the counts since the last call and starts afresh.
+	TLSReport() []TLSCount
+}
+
+type tlsCountKey struct {
+	domain, policy, result string
+}
+
+type tlsCounters struct {
+	mu     sync.Mutex
+	counts map[tlsCountKey]uint64
+}
+
+func (c *tlsCounters) add(domain, policy, result string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[tlsCountKey{strings.ToLower(domain), policy, result}]++
+}
+
+func (c *tlsCounters) drain() []TLSCount {
+	c.mu.Lock()
+	counts := c.counts
+	c.counts = make(map[tlsCountKey]uint64, len(counts))
+	c.mu.Unlock()
+
+	out := make([]TLSCount, 0, len(counts))
+	for k, n := range counts {
+		out = append(out, TLSCount{Domain: k.domain, Policy: k.policy, Result: k.result, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		if a.Policy != b.Policy {
+			return a.Policy < b.Policy
+		}
+		return a.Result < b.Result
+	})
+	return out
+}
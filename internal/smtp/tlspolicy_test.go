@@ -0,0 +1,389 @@
+package smtp
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	gosmtp "github.com/emersion/go-smtp"
+	"github.com/kannon-email/kannon/internal/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// newPolicySender starts a receiver — offering STARTTLS with a self-signed
+// certificate for 127.0.0.1 unless plaintext — and returns a Sender that finds
+// it as the MX host of every domain the tests send to, with the pool that trusts the
+// certificate. The Sender trusts nothing but the system roots until a test
+// says otherwise.
+func newPolicySender(t *testing.T, plaintext bool) (*sender, *receiver, *x509.CertPool, *x509.Certificate) {
+	t.Helper()
+
+	cert, roots := selfSigned(t)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+
+	rcv := &receiver{}
+	var opts []receiverOption
+	if !plaintext {
+		opts = append(opts, withServer(func(srv *gosmtp.Server) {
+			srv.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		}))
+	}
+
+	s := newLoopbackSender(t, startReceiver(t, rcv, opts...))
+	loopback := []*net.MX{{Host: loopbackMX, Pref: 10}}
+	s.resolver = &resolver.Fake{MX: map[string][]*net.MX{
+		"plain.test": loopback, "selfsigned.test": loopback, "elsewhere.test": loopback,
+		"listed.test": loopback, "testing.test": loopback, "dane.test": loopback,
+		"recipient.test": loopback, "mx.test": loopback, "refused.test": loopback,
+	}}
+	return s, rcv, roots, leaf
+}
+
+func sendTo(s *sender, to string, opts SendOptions) SenderError {
+	_, err := s.Send("bounce@k.sender.test", to, BytesBody([]byte("Subject: hi\r\n\r\nhi\r\n")), opts)
+	return err
+}
+
+func assertHeldBackByPolicy(t *testing.T, err SenderError) {
+	t.Helper()
+	require.NotNil(t, err)
+	assert.False(t, err.IsPermanent(), "a policy failure is for the recipient's MX hosts to fix, not a bounce")
+	assert.Equal(t, CodeTLSPolicy, err.Code())
+}
+
+func TestRequireTLSHoldsBackMailForAPlaintextMX(t *testing.T) {
+	s, rcv, _, _ := newPolicySender(t, true)
+
+	assertHeldBackByPolicy(t, sendTo(s, "someone@plain.test", SendOptions{RequireTLS: true}))
+	assert.Zero(t, rcv.accepted())
+
+	require.Nil(t, sendTo(s, "someone@plain.test", SendOptions{}), "without the requirement it goes as it always has")
+	assert.Equal(t, 1, rcv.accepted())
+
+	assert.Equal(t, []TLSCount{
+		{Domain: "plain.test", Policy: tlsPolicyNone, Result: tlsResultStartTLSNotSupported, Count: 2},
+	}, s.TLSReport())
+	assert.Empty(t, s.TLSReport(), "a report starts afresh once taken")
+}
+
+func TestRequireTLSVerifiesTheCertificate(t *testing.T) {
+	s, rcv, roots, _ := newPolicySender(t, false)
+
+	assertHeldBackByPolicy(t, sendTo(s, "someone@selfsigned.test", SendOptions{RequireTLS: true}))
+	require.Nil(t, sendTo(s, "someone@selfsigned.test", SendOptions{}),
+		"opportunistic TLS still settles for a certificate it cannot verify")
+	assert.Equal(t, []TLSCount{
+		{Domain: "selfsigned.test", Policy: tlsPolicyNone, Result: tlsResultCertificateNotTrusted, Count: 2},
+	}, s.TLSReport())
+
+	s.policy.roots = roots
+	require.Nil(t, sendTo(s, "someone@selfsigned.test", SendOptions{RequireTLS: true}))
+	assert.Equal(t, 2, rcv.accepted())
+	assert.Equal(t, []TLSCount{
+		{Domain: "selfsigned.test", Policy: tlsPolicyNone, Result: tlsResultSuccess, Count: 1},
+	}, s.TLSReport())
+}
+
+// fakeSTS publishes the policies of a few domains under one id each.
+func fakeSTS(policies map[string]string) *stsCache {
+	c := newSTSCache()
+	c.lookupTXT = func(_ context.Context, name string) ([]string, error) {
+		if _, ok := policies[name[len("_mta-sts."):]]; !ok {
+			return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+		}
+		return []string{"v=STSv1; id=1"}, nil
+	}
+	c.fetch = func(_ context.Context, domain string) ([]byte, error) {
+		return []byte(policies[domain]), nil
+	}
+	return c
+}
+
+func TestMTASTSEnforcesTheHostsOfThePolicy(t *testing.T) {
+	s, rcv, roots, _ := newPolicySender(t, false)
+	s.policy.roots = roots
+	s.policy.sts = fakeSTS(map[string]string{
+		"elsewhere.test": "version: STSv1\nmode: enforce\nmx: mx.elsewhere.test\nmax_age: 86400\n",
+		"listed.test":    "version: STSv1\nmode: enforce\nmx: 127.0.0.1\nmax_age: 86400\n",
+		"testing.test":   "version: STSv1\nmode: testing\nmx: mx.elsewhere.test\nmax_age: 86400\n",
+	})
+
+	assertHeldBackByPolicy(t, sendTo(s, "someone@elsewhere.test", SendOptions{}))
+	require.Nil(t, sendTo(s, "someone@listed.test", SendOptions{}))
+	require.Nil(t, sendTo(s, "someone@testing.test", SendOptions{}), "a policy in testing mode is only reported on")
+	assert.Equal(t, 2, rcv.accepted())
+
+	assert.Equal(t, []TLSCount{
+		{Domain: "elsewhere.test", Policy: tlsPolicySTS, Result: tlsResultValidationFailure, Count: 1},
+		{Domain: "listed.test", Policy: tlsPolicySTS, Result: tlsResultSuccess, Count: 1},
+		{Domain: "testing.test", Policy: tlsPolicySTS, Result: tlsResultSuccess, Count: 1},
+	}, s.TLSReport())
+}
+
+func TestMTASTSPolicyRefusesAnUntrustedCertificate(t *testing.T) {
+	s, rcv, _, _ := newPolicySender(t, false)
+	s.policy.sts = fakeSTS(map[string]string{
+		"listed.test": "version: STSv1\nmode: enforce\nmx: 127.0.0.1\nmax_age: 86400\n",
+	})
+
+	assertHeldBackByPolicy(t, sendTo(s, "someone@listed.test", SendOptions{}))
+	assert.Zero(t, rcv.accepted(), "no insecure retry under an enforced policy")
+}
+
+func TestParseSTSPolicy(t *testing.T) {
+	p, err := parseSTSPolicy([]byte("version: STSv1\r\nmode: enforce\r\nmx: mail.example.com\r\nmx: *.example.net\r\nmax_age: 604800\r\nextension: ignored\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, stsEnforce, p.mode)
+	assert.Equal(t, 7*24*time.Hour, p.maxAge)
+
+	assert.True(t, p.matches("mail.example.com."))
+	assert.True(t, p.matches("MX1.example.net"))
+	assert.False(t, p.matches("example.net"), "a wildcard stands for one label, not none")
+	assert.False(t, p.matches("a.b.example.net"), "a wildcard stands for one label, not two")
+	assert.False(t, p.matches("mail.example.org"))
+
+	for name, policy := range map[string]string{
+		"wrong version": "version: STSv2\nmode: enforce\nmx: a.test\nmax_age: 1\n",
+		"unknown mode":  "version: STSv1\nmode: strict\nmx: a.test\nmax_age: 1\n",
+		"no max_age":    "version: STSv1\nmode: enforce\nmx: a.test\n",
+		"no mx":         "version: STSv1\nmode: enforce\nmax_age: 1\n",
+	} {
+		_, err := parseSTSPolicy([]byte(policy))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestSTSCacheFollowsTheTXTRecord(t *testing.T) {
+	id, fetches, fetchErr := "1", 0, error(nil)
+	published := true
+	c := newSTSCache()
+	c.lookupTXT = func(context.Context, string) ([]string, error) {
+		if !published {
+			return nil, &net.DNSError{Err: "no such host", IsNotFound: true}
+		}
+		return []string{"v=spf1 -all", "v=STSv1; id=" + id}, nil
+	}
+	c.fetch = func(context.Context, string) ([]byte, error) {
+		fetches++
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return []byte("version: STSv1\nmode: enforce\nmx: mx" + id + ".example.com\nmax_age: 3600\n"), nil
+	}
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := t.Context()
+
+	p, failure := c.lookup(ctx, "example.com")
+	require.NotNil(t, p)
+	assert.Empty(t, failure)
+	c.lookup(ctx, "example.com")
+	now = now.Add(stsRecheck)
+	c.lookup(ctx, "example.com")
+	assert.Equal(t, 1, fetches, "the same id is the same policy")
+
+	id = "2"
+	now = now.Add(stsRecheck)
+	p, _ = c.lookup(ctx, "example.com")
+	assert.Equal(t, 2, fetches, "a new id is a new policy")
+	assert.True(t, p.matches("mx2.example.com"))
+
+	id, fetchErr = "3", errors.New("connection refused")
+	now = now.Add(stsRecheck)
+	p, failure = c.lookup(ctx, "example.com")
+	assert.True(t, p.matches("mx2.example.com"), "a policy that cannot be fetched leaves the cached one in force")
+	assert.Empty(t, failure)
+
+	published = false
+	now = now.Add(stsRecheck)
+	p, _ = c.lookup(ctx, "example.com")
+	assert.NotNil(t, p, "removing the TXT record does not end a cached policy")
+	now = now.Add(time.Hour)
+	p, _ = c.lookup(ctx, "example.com")
+	assert.Nil(t, p, "its max_age does")
+
+	_, failure = c.lookup(ctx, "unfetchable.example.com")
+	assert.Empty(t, failure, "no TXT record, no policy to fail to fetch")
+	published = true
+	now = now.Add(stsRecheck)
+	p, failure = c.lookup(ctx, "unfetchable.example.com")
+	assert.Nil(t, p)
+	assert.Equal(t, tlsResultSTSPolicyFetchError, failure)
+}
+
+// fakeTLSA answers for the loopback MX host with records, or with err, behind
+// a validated MX answer naming it.
+func fakeTLSA(records []tlsaRecord, err error) *daneCache {
+	return fakeDANE(records, err, mxAnswer{validated: true, hosts: []string{loopbackMX}, ttl: time.Hour})
+}
+
+// fakeDANE answers for the loopback MX host with records, or with err, and for
+// every domain's MX records with mx.
+func fakeDANE(records []tlsaRecord, err error, mx mxAnswer) *daneCache {
+	return newDANECache(func(context.Context, string) (tlsaAnswer, error) {
+		return tlsaAnswer{records: records, ttl: time.Hour}, err
+	}, func(context.Context, string) (mxAnswer, error) {
+		return mx, nil
+	})
+}
+
+func TestDANEAuthenticatesAgainstTLSARecords(t *testing.T) {
+	s, rcv, _, leaf := newPolicySender(t, false)
+	spki := sha256.Sum256(leaf.RawSubjectPublicKeyInfo)
+
+	cases := []struct {
+		name    string
+		dane    *daneCache
+		result  string
+		deliver bool
+	}{
+		{"DANE-EE on the key", fakeTLSA([]tlsaRecord{{usage: 3, selector: 1, matching: 1, data: spki[:]}}, nil), tlsResultSuccess, true},
+		{"DANE-TA on the certificate", fakeTLSA([]tlsaRecord{{usage: 2, selector: 0, matching: 0, data: leaf.Raw}}, nil), tlsResultSuccess, true},
+		{"a record the certificate does not match", fakeTLSA([]tlsaRecord{{usage: 3, selector: 1, matching: 1, data: make([]byte, 32)}}, nil), tlsResultValidationFailure, false},
+		{"records that cannot be trusted", fakeTLSA(nil, errors.New("resolver answered RCodeServerFailure")), tlsResultDNSSECInvalid, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// A pooled session was authenticated when it was opened, and is
+			// not checked again against records that changed since.
+			s.pool.close()
+			s.policy.dane = c.dane
+			before := rcv.accepted()
+
+			err := sendTo(s, "someone@dane.test", SendOptions{})
+			if c.deliver {
+				require.Nil(t, err)
+				assert.Equal(t, before+1, rcv.accepted())
+			} else {
+				assertHeldBackByPolicy(t, err)
+				assert.Equal(t, before, rcv.accepted())
+			}
+			assert.Equal(t, []TLSCount{{Domain: "dane.test", Policy: tlsPolicyTLSA, Result: c.result, Count: 1}}, s.TLSReport())
+		})
+	}
+
+	s.pool.close()
+	s.policy.dane = fakeTLSA(nil, nil)
+	require.Nil(t, sendTo(s, "someone@dane.test", SendOptions{}), "a host without records is not held to DANE")
+	assert.Equal(t, tlsPolicyNone, s.TLSReport()[0].Policy)
+}
+
+// dnsServer answers TLSA and MX queries on the loopback interface: names
+// starting "secure." with a validated record, "insecure." with the same record
+// unvalidated, and anything else with SERVFAIL.
+func dnsServer(t *testing.T) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var q dnsmessage.Message
+			if err := q.Unpack(buf[:n]); err != nil {
+				continue
+			}
+			name := q.Questions[0].Name.String()
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: q.Header.ID, Response: true, RCode: dnsmessage.RCodeServerFailure},
+				Questions: q.Questions,
+			}
+			secure, insecure := name[:7] == "secure.", len(name) > 9 && name[:9] == "insecure."
+			if secure || insecure {
+				resp.Header.RCode = dnsmessage.RCodeSuccess
+				resp.Header.AuthenticData = secure
+				resp.Answers = []dnsmessage.Resource{{
+					Header: dnsmessage.ResourceHeader{Name: q.Questions[0].Name, Type: typeTLSA, Class: dnsmessage.ClassINET, TTL: 600},
+					Body:   &dnsmessage.UnknownResource{Type: typeTLSA, Data: append([]byte{3, 1, 1}, make([]byte, 32)...)},
+				}}
+				if q.Questions[0].Type == dnsmessage.TypeMX {
+					resp.Answers = []dnsmessage.Resource{{
+						Header: dnsmessage.ResourceHeader{Name: q.Questions[0].Name, Type: dnsmessage.TypeMX, Class: dnsmessage.ClassINET, TTL: 300},
+						Body:   &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("MX.Test.")},
+					}}
+				}
+			}
+			out, err := resp.Pack()
+			if err != nil {
+				continue
+			}
+			_, _ = pc.WriteTo(out, addr)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+func TestTLSAQueryTrustsOnlyValidatedAnswers(t *testing.T) {
+	query := newTLSAQuery(dnsServer(t))
+	ctx := t.Context()
+
+	ans, err := query(ctx, "secure._25._tcp.mx.test.")
+	require.NoError(t, err)
+	require.Len(t, ans.records, 1)
+	assert.Equal(t, tlsaRecord{usage: 3, selector: 1, matching: 1, data: make([]byte, 32)}, ans.records[0])
+	assert.Equal(t, 10*time.Minute, ans.ttl)
+
+	ans, err = query(ctx, "insecure._25._tcp.mx.test.")
+	require.NoError(t, err)
+	assert.Empty(t, ans.records, "records from an unsigned zone are as good as none")
+
+	_, err = query(ctx, "bogus._25._tcp.mx.test.")
+	assert.Error(t, err)
+}
+
+func TestMXQueryTellsValidatedAnswers(t *testing.T) {
+	query := newMXQuery(dnsServer(t))
+	ctx := t.Context()
+
+	ans, err := query(ctx, "secure.example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, mxAnswer{validated: true, hosts: []string{"mx.test"}, ttl: 5 * time.Minute}, ans)
+
+	ans, err = query(ctx, "insecure.example.com.")
+	require.NoError(t, err)
+	assert.False(t, ans.validated)
+
+	_, err = query(ctx, "bogus.example.com.")
+	assert.Error(t, err)
+}
+
+// DANE applies only to a host the recipient domain's validated MX records
+// name: TLSA records are no protection for a host an attacker could have put in
+// an unsigned MX answer.
+func TestDANERequiresAValidatedMXAnswer(t *testing.T) {
+	s, rcv, _, _ := newPolicySender(t, false)
+	mismatch := []tlsaRecord{{usage: 3, selector: 1, matching: 1, data: make([]byte, 32)}}
+
+	s.policy.dane = fakeDANE(mismatch, nil, mxAnswer{hosts: []string{loopbackMX}, ttl: time.Hour})
+	require.Nil(t, sendTo(s, "someone@dane.test", SendOptions{}), "an unvalidated MX answer is not held to DANE")
+	assert.Equal(t, tlsPolicyNone, s.TLSReport()[0].Policy)
+
+	s.pool.close()
+	s.policy.dane = fakeDANE(nil, nil, mxAnswer{validated: true, hosts: []string{"elsewhere.test"}, ttl: time.Hour})
+	before := rcv.accepted()
+	err := sendTo(s, "someone@dane.test", SendOptions{})
+	assertHeldBackByPolicy(t, err)
+	assert.Equal(t, before, rcv.accepted(), "a host the validated MX records do not name is not used")
+	assert.Equal(t, []TLSCount{{Domain: "dane.test", Policy: tlsPolicyTLSA, Result: tlsResultDNSSECInvalid, Count: 1}}, s.TLSReport())
+}
+
+func TestTLSPolicyConfigValidate(t *testing.T) {
+	assert.NoError(t, TLSPolicyConfig{}.Validate())
+	assert.NoError(t, TLSPolicyConfig{MTASTS: true}.Validate())
+	assert.NoError(t, TLSPolicyConfig{DANE: true, DNSSECResolver: "127.0.0.1"}.Validate())
+	assert.NoError(t, TLSPolicyConfig{DANE: true, DNSSECResolver: "[::1]:5353"}.Validate())
+	assert.Error(t, TLSPolicyConfig{DANE: true}.Validate())
+}
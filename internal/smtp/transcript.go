@@ -0,0 +1,204 @@
+package smtp
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"regexp"
+	"strings"
+)
+
+// Stage is the step of an SMTP session a message failed at.
+type Stage string
+
+const (
+	StageConnect  Stage = "connect"
+	StageEHLO     Stage = "ehlo"
+	StageSTARTTLS Stage = "starttls"
+	// StageAuth is logging in to a relay; MX hosts are never logged in to.
+	StageAuth Stage = "auth"
+	StageMAIL Stage = "mail"
+	StageRCPT Stage = "rcpt"
+	StageDATA Stage = "data"
+)
+
+// CodeNoReply is the code of a failure that is not the answer of an MX host to
+// the message: the host could not be reached, dropped the connection, refused
+// the session before MAIL FROM, or could not be secured. It is below the codes
+// MX hosts reply with, so Send falls back to the next host, and it stays 111 —
+// the code every such failure has always been recorded under. The enhanced
+// status code says which of them it was.
+const CodeNoReply uint32 = 111
+
+// Transcript is what is known of the SMTP session a message was last offered
+// over: the host and how the session was secured, how the message fared and
+// the transaction as it was spoken. A Send that failed before a session was
+// opened — an address that cannot be mailed, a domain that cannot be resolved
+// — has a Transcript with an EnhancedCode and nothing else.
+type Transcript struct {
+	// MX is the host offered the message, the relay's host for relayed mail.
+	MX string
+	// RemoteIP is the address of MX connected to, or the last one dialled
+	// when none answered; empty when none was.
+	RemoteIP string
+	// TLSVersion and TLSCipher are how the session was secured, in the names
+	// crypto/tls gives them; empty for a session in plaintext.
+	TLSVersion string
+	TLSCipher  string
+	// Stage is the step the message failed at, empty when it was accepted or
+	// failed before there was a session.
+	Stage Stage
+	// EnhancedCode is the RFC 3463 status of the outcome: the one the last
+	// reply carried, X.0.0 of its class for a reply that carried none, and for
+	// a failure no reply came with the code that describes it (4.4.1 for a
+	// host that did not answer, 4.4.2 for a connection lost, 4.7.5 for TLS
+	// that could not be set up, and so on).
+	EnhancedCode string
+	// Reply is the last reply as the host wrote it, every line of it, empty
+	// when the failure came with none.
+	Reply string
+	// QueueID is the ID an accepted message was queued under at the host, as
+	// its reply to DATA gave it; empty when the reply is in no form it is
+	// recognised in. Reply has it either way.
+	QueueID string
+	// Lines are the mail transaction as it was spoken, from MAIL FROM to the
+	// reply that ended it, prefixed "C: " and "S: ". The body is not in it.
+	// The greeting, EHLO and STARTTLS are not either — they are summed up by
+	// the fields above, and a reply refusing one of them is Reply.
+	Lines []string
+}
+
+// failed records e, the failure the message ended with, on t.
+func (t *Transcript) failed(e *smtpError) {
+	t.Stage = e.stage
+	t.EnhancedCode = e.enhanced
+	t.Reply = e.reply
+	if e.remoteIP != "" {
+		t.RemoteIP = e.remoteIP
+	}
+}
+
+// transcriptOf is the part of a Transcript the session on pc answers for.
+func transcriptOf(pc *pooledConn) Transcript {
+	t := Transcript{MX: pc.key.mx, RemoteIP: remoteIPOf(pc.conn)}
+	if st, ok := pc.client.TLSConnectionState(); ok {
+		t.TLSVersion = tls.VersionName(st.Version)
+		t.TLSCipher = tls.CipherSuiteName(st.CipherSuite)
+	}
+	return t
+}
+
+// remoteIPOf is the address conn is connected to.
+func remoteIPOf(conn net.Conn) string {
+	if a, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		return a.IP.String()
+	}
+	return ""
+}
+
+// cmd sends one command of the transaction and reads its reply, writing both
+// down. The transaction is spoken over the client's textproto.Conn rather than
+// through its Mail, Rcpt and Data, which return nothing of a reply that
+// succeeded — least of all the queue ID of the one that ends DATA.
+func (t *Transcript) cmd(c *smtp.Client, expect int, format string, args ...any) (string, error) {
+	line := fmt.Sprintf(format, args...)
+	t.Lines = append(t.Lines, "C: "+line)
+	id, err := c.Text.Cmd("%s", line)
+	if err != nil {
+		return "", err
+	}
+	c.Text.StartResponse(id)
+	defer c.Text.EndResponse(id)
+	return t.read(c, expect)
+}
+
+// read reads a reply and writes it down.
+func (t *Transcript) read(c *smtp.Client, expect int) (string, error) {
+	code, msg, err := c.Text.ReadResponse(expect)
+	if code != 0 {
+		t.reply(code, msg)
+	}
+	return msg, err
+}
+
+func (t *Transcript) reply(code int, msg string) {
+	t.Reply = replyText(code, msg)
+	t.EnhancedCode = enhancedCodeOf(code, msg)
+	lines := strings.Split(msg, "\n")
+	for i, l := range lines {
+		sep := "-"
+		if i == len(lines)-1 {
+			sep = " "
+		}
+		t.Lines = append(t.Lines, fmt.Sprintf("S: %03d%s%s", code, sep, l))
+	}
+}
+
+// replyText is a reply as the host wrote it, its lines joined by "\n".
+// textproto.Error's own Error quotes the text.
+func replyText(code int, msg string) string {
+	return fmt.Sprintf("%03d %s", code, msg)
+}
+
+// enhancedCodeRE is an RFC 3463 status code at the start of a reply's text.
+var enhancedCodeRE = regexp.MustCompile(`^([245])\.(\d{1,3})\.(\d{1,3})(\s|$)`)
+
+// enhancedCodeOf is the enhanced status code of a reply: the one its text
+// opens with, if its class is the reply's, and otherwise X.0.0 of the reply's
+// class, as RFC 3463 has a host that gives none be read.
+func enhancedCodeOf(code int, msg string) string {
+	class := code / 100
+	if class != 2 && class != 4 && class != 5 {
+		return ""
+	}
+	if m := enhancedCodeRE.FindStringSubmatch(msg); m != nil && m[1] == fmt.Sprint(class) {
+		return m[1] + "." + m[2] + "." + m[3]
+	}
+	return fmt.Sprintf("%d.0.0", class)
+}
+
+// queueIDREs are the forms the reply to DATA names the queue ID in, as the
+// common MTAs write it.
+var queueIDREs = []*regexp.Regexp{
+	// Postfix: 250 2.0.0 Ok: queued as 4Xy3Zt1Q2bz9
+	regexp.MustCompile(`(?i)\bqueued as ([A-Za-z0-9._-]+)`),
+	// Exim: 250 OK id=1qABCD-0001ab-2X
+	regexp.MustCompile(`\bid=([A-Za-z0-9._-]+)`),
+	// Gmail: 250 2.0.0 OK  1697712345 a1b2c3si123456qtb.123 - gsmtp
+	regexp.MustCompile(`^(?:[245]\.\d{1,3}\.\d{1,3}\s+)?OK\s+\d+\s+(\S+)\s+-\s+gsmtp`),
+	// Microsoft: 250 2.6.0 <id@host> [InternalId=123456, Hostname=...] Queued mail for delivery
+	regexp.MustCompile(`\bInternalId=(\d+)`),
+	// Sendmail: 250 2.0.0 w9JAbCdE012345 Message accepted for delivery
+	regexp.MustCompile(`^(?:[245]\.\d{1,3}\.\d{1,3}\s+)?(\S+) Message accepted for delivery`),
+}
+
+// queueIDOf is the queue ID a reply to DATA names, empty when it is in no
+// form queueIDREs know.
+func queueIDOf(msg string) string {
+	for _, re := range queueIDREs {
+		if m := re.FindStringSubmatch(msg); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// sessionError is a failure to open a session to a host at stage, before any
+// message was offered to it. It is transient and CodeNoReply whatever the host
+// replied — a host that refuses the session has said nothing about the
+// recipient, and the next host may take the message — and its enhanced code is
+// the reply's, or enhanced when there was none.
+func sessionError(err error, stage Stage, enhanced string) *smtpError {
+	e := newSMTPError(err, false, CodeNoReply)
+	e.stage = stage
+	e.enhanced = enhanced
+	terr := &textproto.Error{}
+	if errors.As(err, &terr) {
+		e.enhanced = enhancedCodeOf(terr.Code, terr.Msg)
+		e.reply = replyText(terr.Code, terr.Msg)
+	}
+	return e
+}
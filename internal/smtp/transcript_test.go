@@ -0,0 +1,138 @@
+package smtp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnhancedCodeOf(t *testing.T) {
+	cases := []struct {
+		code int
+		msg  string
+		want string
+	}{
+		{550, "5.1.1 no such user", "5.1.1"},
+		{421, "4.7.28 rate limited\nsee https://support.example", "4.7.28"},
+		{250, "2.0.0 Ok: queued as 4Xy3Zt1Q2bz9", "2.0.0"},
+		{550, "no such user", "5.0.0"},
+		{452, "5.2.2 mailbox full", "4.0.0"},
+		{550, "5.1.1user", "5.0.0"},
+		{354, "go ahead", ""},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, enhancedCodeOf(c.code, c.msg), "%d %s", c.code, c.msg)
+	}
+}
+
+func TestQueueIDOf(t *testing.T) {
+	cases := map[string]string{
+		"2.0.0 Ok: queued as 4Xy3Zt1Q2bz9":                                           "4Xy3Zt1Q2bz9",
+		"OK id=1qABCD-0001ab-2X":                                                     "1qABCD-0001ab-2X",
+		"2.0.0 OK  1697712345 a1b2c3si123456qtb.123 - gsmtp":                         "a1b2c3si123456qtb.123",
+		"2.6.0 <a@b> [InternalId=123456, Hostname=AM0PR.eurprd] Queued for delivery": "123456",
+		"2.0.0 w9JAbCdE012345 Message accepted for delivery":                         "w9JAbCdE012345",
+		"2.0.0 OK: queued":                                                           "",
+	}
+	for reply, want := range cases {
+		assert.Equal(t, want, queueIDOf(reply), reply)
+	}
+}
+
+func TestTranscriptOfADelivery(t *testing.T) {
+	s, _, _, _ := newPolicySender(t, false)
+
+	sent, err := s.Send("bounce@k.sender.test", "to@mx.test", BytesBody([]byte("Subject: hi\r\n\r\nhi\r\n")), SendOptions{})
+	require.Nil(t, err)
+
+	tr := sent.Transcript
+	assert.Equal(t, loopbackMX, tr.MX)
+	assert.Equal(t, "127.0.0.1", tr.RemoteIP)
+	assert.NotEmpty(t, tr.TLSVersion, "the certificate does not verify, so the session settled for TLS unverified")
+	assert.NotEmpty(t, tr.TLSCipher)
+	assert.Empty(t, tr.Stage)
+	assert.Equal(t, "2.0.0", tr.EnhancedCode)
+	assert.Equal(t, "250 2.0.0 OK: queued", tr.Reply)
+	assert.Equal(t, []string{
+		"C: MAIL FROM:<bounce@k.sender.test> BODY=8BITMIME",
+		"S: 250 2.0.0 Roger, accepting mail from <bounce@k.sender.test>",
+		"C: RCPT TO:<to@mx.test>",
+		"S: 250 2.0.0 I'll make sure <to@mx.test> gets this",
+		"C: DATA",
+		"S: 354 Go ahead. End your data with <CR><LF>.<CR><LF>",
+		"S: 250 2.0.0 OK: queued",
+	}, tr.Lines)
+}
+
+func TestTranscriptOfARefusal(t *testing.T) {
+	s, _, _, _ := newPolicySender(t, true)
+
+	sent, err := s.Send("bounce@k.sender.test", "nobody@refused.test", BytesBody([]byte("hi\r\n")), SendOptions{})
+	require.NotNil(t, err)
+	assert.Equal(t, uint32(550), err.Code())
+
+	tr := sent.Transcript
+	assert.Empty(t, tr.TLSVersion, "the receiver offers no STARTTLS")
+	assert.Equal(t, StageRCPT, tr.Stage)
+	assert.Equal(t, "5.1.1", tr.EnhancedCode)
+	assert.Equal(t, "550 5.1.1 no such user", tr.Reply)
+	assert.Equal(t, "S: 550 5.1.1 no such user", tr.Lines[len(tr.Lines)-1])
+}
+
+// Failures with no reply behind them keep the code they always had, and say
+// what they were in the enhanced code instead.
+func TestTranscriptOfAFailureWithoutAReply(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	s := NewSender("sender.test").(*sender)
+	s.port = port
+	s.resolver = &resolver.Fake{MX: map[string][]*net.MX{
+		"mx.test": {{Host: loopbackMX, Pref: 10}},
+	}}
+
+	sent, serr := s.Send("bounce@k.sender.test", "to@mx.test", BytesBody([]byte("hi\r\n")), SendOptions{})
+	require.NotNil(t, serr)
+	assert.Equal(t, CodeNoReply, serr.Code())
+	assert.False(t, serr.IsPermanent())
+	assert.Equal(t, Transcript{MX: loopbackMX, RemoteIP: loopbackMX, Stage: StageConnect, EnhancedCode: "4.4.1"}, sent.Transcript,
+		"the address that did not answer is named")
+
+	s.resolver = &resolver.Fake{MX: map[string][]*net.MX{"null.test": {}}}
+	sent, serr = s.Send("bounce@k.sender.test", "to@null.test", BytesBody([]byte("hi\r\n")), SendOptions{})
+	require.NotNil(t, serr)
+	assert.True(t, serr.IsPermanent())
+	assert.Equal(t, Transcript{EnhancedCode: "5.1.10"}, sent.Transcript)
+}
+
+// A domain the DNS says cannot take mail is bounced; one whose nameservers did
+// not answer is retried.
+func TestTranscriptOfAFailedMXLookup(t *testing.T) {
+	s := NewSender("sender.test").(*sender)
+	s.resolver = &resolver.Fake{
+		MX:  map[string][]*net.MX{},
+		Err: map[string]error{"noaddress.test": resolver.ErrNoAddress, "servfail.test": resolver.ErrServFail},
+	}
+
+	cases := []struct {
+		domain    string
+		permanent bool
+		enhanced  string
+	}{
+		{"nx.test", true, "5.1.2"},
+		{"noaddress.test", true, "5.4.4"},
+		{"servfail.test", false, "4.4.3"},
+	}
+	for _, c := range cases {
+		sent, serr := s.Send("bounce@k.sender.test", "to@"+c.domain, BytesBody([]byte("hi\r\n")), SendOptions{})
+		require.NotNil(t, serr, c.domain)
+		assert.Equal(t, c.permanent, serr.IsPermanent(), c.domain)
+		assert.Equal(t, Transcript{EnhancedCode: c.enhanced}, sent.Transcript, c.domain)
+	}
+}
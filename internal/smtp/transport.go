@@ -0,0 +1,321 @@
+package smtp
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+)
+
+// TransportDirect is the transport every message takes unless a route says
+// otherwise: the recipient domain's MX hosts, dialled on port 25. It needs no
+// entry in `sender.transports`.
+const TransportDirect = "direct"
+
+// TransportConfig is one entry of `sender.transports`: a relay the Sender
+// hands mail to instead of the recipient's MX hosts — a corporate smarthost or
+// a provider's submission endpoint.
+type TransportConfig struct {
+	// Relay is the relay's host:port.
+	Relay string `mapstructure:"relay"`
+	// TLS is "starttls", the default, or "implicit" for a relay that speaks
+	// TLS from the first byte (port 465). Either way the relay's certificate
+	// is verified and a relay that cannot be secured is not sent to: the
+	// credentials below are on the wire.
+	TLS string `mapstructure:"tls"`
+	// Auth is the SASL mechanism, "plain" — the default — or "login". No
+	// username means no AUTH.
+	Auth string `mapstructure:"auth"`
+	// Username and Password are the relay's credentials, normally written as
+	// `env://` references rather than into the file.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// RouteConfig is one entry of `sender.routes`. A message takes the transport
+// of the first route it matches, and TransportDirect when it matches none.
+type RouteConfig struct {
+	// Recipients are recipient domain patterns: "example.com" is that domain,
+	// "*.example.com" its subdomains, and "*" every domain. Empty matches any
+	// recipient.
+	Recipients []string `mapstructure:"recipients"`
+	// Senders are sending domains as they appear in MAIL FROM: the Domain, or
+	// its return-path domain when it has one. Empty matches any sender.
+	Senders []string `mapstructure:"senders"`
+	// Transport names an entry of `sender.transports`, or TransportDirect.
+	Transport string `mapstructure:"transport"`
+}
+
+// tlsStartTLS and tlsImplicit are the relay TLS modes: STARTTLS that must
+// succeed, and TLS from the first byte. Both verify the certificate.
+const (
+	tlsStartTLS tlsMode = "starttls"
+	tlsImplicit tlsMode = "implicit"
+)
+
+// relay is a validated TransportConfig.
+type relay struct {
+	name      string
+	host      string
+	port      string
+	tls       tlsMode
+	auth      smtp.Auth
+	tlsConfig *tls.Config
+}
+
+// route is a validated RouteConfig. A nil relay is TransportDirect.
+type route struct {
+	recipients []string
+	senders    []string
+	relay      *relay
+}
+
+// RoutingTable decides the transport of each message.
+type RoutingTable struct {
+	relays map[string]*relay
+	routes []route
+}
+
+// NewRoutingTable validates the `sender.transports` and `sender.routes`
+// sections. A route to a transport nobody defined, or a transport that could
+// not be used as written, is an error here rather than mail that fails on its
+// way out.
+func NewRoutingTable(transports map[string]TransportConfig, routes []RouteConfig) (*RoutingTable, error) {
+	rt := &RoutingTable{relays: make(map[string]*relay, len(transports))}
+	for name, tc := range transports {
+		if name == TransportDirect {
+			return nil, fmt.Errorf("transport %q is built in and cannot be redefined", name)
+		}
+		r, err := newRelay(name, tc)
+		if err != nil {
+			return nil, err
+		}
+		rt.relays[name] = r
+	}
+
+	for i, rc := range routes {
+		rr := route{
+			recipients: lowerAll(rc.Recipients),
+			senders:    lowerAll(rc.Senders),
+		}
+		switch rc.Transport {
+		case TransportDirect:
+		case "":
+			return nil, fmt.Errorf("route %d names no transport", i)
+		default:
+			r, ok := rt.relays[rc.Transport]
+			if !ok {
+				return nil, fmt.Errorf("route %d names transport %q, which is not defined", i, rc.Transport)
+			}
+			rr.relay = r
+		}
+		rt.routes = append(rt.routes, rr)
+	}
+	return rt, nil
+}
+
+func newRelay(name string, tc TransportConfig) (*relay, error) {
+	host, port, err := net.SplitHostPort(tc.Relay)
+	if err != nil {
+		return nil, fmt.Errorf("transport %q: relay %q is not host:port: %w", name, tc.Relay, err)
+	}
+
+	r := &relay{
+		name:      name,
+		host:      host,
+		port:      port,
+		tlsConfig: &tls.Config{ServerName: host},
+	}
+	switch strings.ToLower(tc.TLS) {
+	case "", string(tlsStartTLS):
+		r.tls = tlsStartTLS
+	case string(tlsImplicit):
+		r.tls = tlsImplicit
+	default:
+		return nil, fmt.Errorf("transport %q: tls %q is neither starttls nor implicit", name, tc.TLS)
+	}
+
+	if tc.Username == "" {
+		if tc.Password != "" {
+			return nil, fmt.Errorf("transport %q has a password but no username", name)
+		}
+		return r, nil
+	}
+	switch strings.ToLower(tc.Auth) {
+	case "", "plain":
+		r.auth = smtp.PlainAuth("", tc.Username, tc.Password, host)
+	case "login":
+		r.auth = &loginAuth{host: host, username: tc.Username, password: tc.Password}
+	default:
+		return nil, fmt.Errorf("transport %q: auth %q is neither plain nor login", name, tc.Auth)
+	}
+	return r, nil
+}
+
+// relayFor returns the relay the message from → to is routed to, nil for
+// TransportDirect. A nil table routes everything directly.
+func (rt *RoutingTable) relayFor(from, toDomain string) *relay {
+	if rt == nil {
+		return nil
+	}
+	toDomain = strings.ToLower(toDomain)
+	fromDomain := ""
+	if at := strings.LastIndexByte(from, '@'); at >= 0 {
+		fromDomain = strings.ToLower(from[at+1:])
+	}
+	for _, r := range rt.routes {
+		if matchesAny(r.recipients, toDomain) && matchesAny(r.senders, fromDomain) {
+			return r.relay
+		}
+	}
+	return nil
+}
+
+// matchesAny reports whether domain matches one of patterns; an empty list
+// matches everything.
+func matchesAny(patterns []string, domain string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		switch {
+		case p == "*":
+			return true
+		case strings.HasPrefix(p, "*."):
+			if strings.HasSuffix(domain, p[1:]) {
+				return true
+			}
+		case p == domain:
+			return true
+		}
+	}
+	return false
+}
+
+func lowerAll(ss []string) []string {
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = strings.ToLower(s)
+	}
+	return out
+}
+
+// sendRelay hands the message to a relay.
+//
+// The relay's replies to the transaction mean what an MX host's would — a 5xx
+// to RCPT TO is the relay refusing the recipient — and are reported as they
+// came. Failing to reach, secure or log in to the relay says nothing about the
+// recipient, so it is transient whatever the reply: a misconfigured relay must
+// hold mail back, not bounce it.
+func (s *sender) sendRelay(from, to string, body Body, r *relay, opts SendOptions) (Sent, *smtpError) {
+	at, err := s.deliverKey(connKey{mx: r.host, tls: r.tls, transport: r.name}, from, to, body, opts)
+	sent := Sent{SourceIP: at.sourceIP, Transcript: at.transcript}
+	if err == nil {
+		return sent, nil
+	}
+	return sent, err.wrap(fmt.Errorf("relay %s: %w", r.name, err.err), err.isPermanent)
+}
+
+// dialRelay opens an authenticated session to a relay.
+func (s *sender) dialRelay(key connKey, r *relay) (*pooledConn, *smtpError) {
+	addr := net.JoinHostPort(r.host, r.port)
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+
+	var conn net.Conn
+	var err error
+	if r.tls == tlsImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, r.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, relaySetupError(fmt.Errorf("cannot connect: %w", err), StageConnect, "4.4.1")
+	}
+
+	pc, serr := s.openRelaySession(conn, key, r)
+	if serr != nil {
+		conn.Close()
+		return nil, connectedError(serr, conn)
+	}
+	return pc, nil
+}
+
+func (s *sender) openRelaySession(conn net.Conn, key connKey, r *relay) (*pooledConn, *smtpError) {
+	if err := setTotalDeadline(conn); err != nil {
+		return nil, relaySetupError(err, StageConnect, "4.4.2")
+	}
+	c, err := smtp.NewClient(conn, r.host)
+	if err != nil {
+		return nil, relaySetupError(fmt.Errorf("no greeting: %w", err), StageConnect, "4.4.2")
+	}
+	if err := c.Hello(s.Hostname); err != nil {
+		return nil, relaySetupError(fmt.Errorf("EHLO refused: %w", err), StageEHLO, "4.4.2")
+	}
+
+	if r.tls == tlsStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return nil, relaySetupError(errors.New("relay does not offer STARTTLS"), StageSTARTTLS, "4.7.5")
+		}
+		if err := c.StartTLS(r.tlsConfig); err != nil {
+			return nil, relaySetupError(fmt.Errorf("STARTTLS failed: %w", err), StageSTARTTLS, "4.7.5")
+		}
+	}
+
+	if r.auth != nil {
+		if err := c.Auth(r.auth); err != nil {
+			return nil, relaySetupError(fmt.Errorf("authentication failed: %w", err), StageAuth, "4.7.8")
+		}
+	}
+	return &pooledConn{key: key, conn: conn, client: c}, nil
+}
+
+// relaySetupError reports a failure to reach, secure or log in to a relay at
+// stage, as sessionError does a host's. Unlike an MX host's refusal it keeps
+// the relay's reply code when there was one; it is never permanent either.
+func relaySetupError(err error, stage Stage, enhanced string) *smtpError {
+	e := sessionError(err, stage, enhanced)
+	terr := &textproto.Error{}
+	if errors.As(err, &terr) {
+		e.code = uint32(terr.Code)
+	}
+	return e
+}
+
+// loginAuth is the LOGIN SASL mechanism, which net/smtp does not provide and
+// which plenty of relays still ask for.
+type loginAuth struct {
+	host               string
+	username, password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	// The same refusal smtp.PlainAuth makes: LOGIN sends the password as
+	// plainly as PLAIN does.
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	}
+	return nil, fmt.Errorf("unexpected LOGIN challenge %q", fromServer)
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
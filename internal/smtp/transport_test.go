@@ -0,0 +1,291 @@
+package smtp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-sasl"
+	gosmtp "github.com/emersion/go-smtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutingTableTakesFirstMatchingRoute(t *testing.T) {
+	rt, err := NewRoutingTable(
+		map[string]TransportConfig{
+			"corp":     {Relay: "relay.corp.test:587"},
+			"provider": {Relay: "smtp.provider.test:465", TLS: "implicit"},
+		},
+		[]RouteConfig{
+			{Recipients: []string{"*.corp.test", "corp.test"}, Transport: "corp"},
+			{Recipients: []string{"direct.test"}, Transport: TransportDirect},
+			{Senders: []string{"k.marketing.test"}, Transport: "provider"},
+		},
+	)
+	require.NoError(t, err)
+
+	cases := []struct {
+		from, to string
+		want     string
+	}{
+		{"bounce@k.marketing.test", "corp.test", "corp"},
+		{"bounce@k.marketing.test", "mail.Corp.Test", "corp"},
+		{"bounce@k.marketing.test", "direct.test", ""},
+		{"bounce@k.marketing.test", "gmail.com", "provider"},
+		{"bounce@k.other.test", "gmail.com", ""},
+		{"bounce@k.other.test", "notcorp.test", ""},
+	}
+	for _, c := range cases {
+		got := ""
+		if r := rt.relayFor(c.from, c.to); r != nil {
+			got = r.name
+		}
+		assert.Equal(t, c.want, got, "%s -> %s", c.from, c.to)
+	}
+
+	var none *RoutingTable
+	assert.Nil(t, none.relayFor("a@b.test", "c.test"), "without a table everything is direct")
+}
+
+func TestRoutingTableRefusesWhatCannotBeUsed(t *testing.T) {
+	cases := map[string]struct {
+		transports map[string]TransportConfig
+		routes     []RouteConfig
+	}{
+		"undefined transport": {
+			routes: []RouteConfig{{Recipients: []string{"*"}, Transport: "nowhere"}},
+		},
+		"route without transport": {
+			routes: []RouteConfig{{Recipients: []string{"*"}}},
+		},
+		"relay without port": {
+			transports: map[string]TransportConfig{"corp": {Relay: "relay.corp.test"}},
+		},
+		"unknown tls mode": {
+			transports: map[string]TransportConfig{"corp": {Relay: "relay.corp.test:25", TLS: "none"}},
+		},
+		"unknown mechanism": {
+			transports: map[string]TransportConfig{"corp": {Relay: "relay.corp.test:25", Username: "u", Auth: "cram-md5"}},
+		},
+		"password without username": {
+			transports: map[string]TransportConfig{"corp": {Relay: "relay.corp.test:25", Password: "p"}},
+		},
+		"redefined direct": {
+			transports: map[string]TransportConfig{TransportDirect: {Relay: "relay.corp.test:25"}},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewRoutingTable(c.transports, c.routes)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// relayBackend is a receiver that requires a login before it takes mail, the
+// way a smarthost does.
+type relayBackend struct {
+	receiver
+	username, password string
+
+	authMu   sync.Mutex
+	attempts int
+}
+
+func (b *relayBackend) NewSession(c *gosmtp.Conn) (gosmtp.Session, error) {
+	s, err := b.receiver.NewSession(c)
+	if err != nil {
+		return nil, err
+	}
+	return &relaySession{receiverSession: s.(*receiverSession), b: b}, nil
+}
+
+func (b *relayBackend) authAttempts() int {
+	b.authMu.Lock()
+	defer b.authMu.Unlock()
+	return b.attempts
+}
+
+type relaySession struct {
+	*receiverSession
+	b      *relayBackend
+	authed bool
+}
+
+func (s *relaySession) AuthMechanisms() []string { return []string{sasl.Plain, sasl.Login} }
+
+func (s *relaySession) Auth(mech string) (sasl.Server, error) {
+	switch mech {
+	case sasl.Plain:
+		return sasl.NewPlainServer(func(_, username, password string) error {
+			return s.login(username, password)
+		}), nil
+	case sasl.Login:
+		return &loginServer{check: s.login}, nil
+	}
+	return nil, gosmtp.ErrAuthUnknownMechanism
+}
+
+func (s *relaySession) login(username, password string) error {
+	s.b.authMu.Lock()
+	s.b.attempts++
+	s.b.authMu.Unlock()
+	if username != s.b.username || password != s.b.password {
+		return &gosmtp.SMTPError{Code: 535, EnhancedCode: gosmtp.EnhancedCode{5, 7, 8}, Message: "invalid credentials"}
+	}
+	s.authed = true
+	return nil
+}
+
+func (s *relaySession) Mail(from string, opts *gosmtp.MailOptions) error {
+	if !s.authed {
+		return &gosmtp.SMTPError{Code: 530, EnhancedCode: gosmtp.EnhancedCode{5, 7, 0}, Message: "authentication required"}
+	}
+	return s.receiverSession.Mail(from, opts)
+}
+
+// loginServer is the server side of LOGIN, which go-sasl does not provide.
+type loginServer struct {
+	check    func(username, password string) error
+	username string
+	step     int
+}
+
+func (l *loginServer) Next(response []byte) ([]byte, bool, error) {
+	l.step++
+	switch l.step {
+	case 1:
+		return []byte("Username:"), false, nil
+	case 2:
+		l.username = string(response)
+		return []byte("Password:"), false, nil
+	}
+	return nil, true, l.check(l.username, string(response))
+}
+
+// selfSigned is a certificate for 127.0.0.1 and a pool that trusts it.
+func selfSigned(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "relay.test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, roots
+}
+
+type relayOptions struct {
+	implicit bool
+	noTLS    bool
+}
+
+// newRelaySender starts a relay requiring user/secret and returns a Sender
+// routing every message to it through the transport tc describes.
+func newRelaySender(t *testing.T, tc TransportConfig, o relayOptions) (*sender, *relayBackend) {
+	t.Helper()
+
+	cert, roots := selfSigned(t)
+	backend := &relayBackend{username: "user", password: "secret"}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+	opts := []receiverOption{withServer(func(srv *gosmtp.Server) {
+		srv.Domain = "relay.test"
+		if !o.noTLS {
+			srv.TLSConfig = tlsConfig
+		}
+		srv.AllowInsecureAuth = o.noTLS
+	})}
+	if o.implicit {
+		opts = append(opts, withImplicitTLS(tlsConfig))
+	}
+
+	tc.Relay = startReceiver(t, backend, opts...)
+	rt, err := NewRoutingTable(
+		map[string]TransportConfig{"corp": tc},
+		[]RouteConfig{{Recipients: []string{"*"}, Transport: "corp"}},
+	)
+	require.NoError(t, err)
+	rt.relays["corp"].tlsConfig.RootCAs = roots
+
+	s := NewSender("sender.test", WithRoutingTable(rt)).(*sender)
+	t.Cleanup(func() { _ = s.Close() })
+	return s, backend
+}
+
+func sendVia(s *sender, to string) SenderError {
+	_, err := s.Send("bounce@k.sender.test", to, BytesBody([]byte("hi\r\n")), SendOptions{})
+	return err
+}
+
+func TestRelayDeliversOverStartTLSWithPlain(t *testing.T) {
+	s, relay := newRelaySender(t, TransportConfig{Username: "user", Password: "secret"}, relayOptions{})
+
+	for range 2 {
+		require.Nil(t, sendVia(s, "someone@recipient.test"))
+	}
+
+	assert.Equal(t, 2, relay.accepted())
+	assert.Equal(t, 1, relay.authAttempts(), "the logged-in session is pooled like any other")
+}
+
+func TestRelayDeliversOverImplicitTLSWithLogin(t *testing.T) {
+	s, relay := newRelaySender(t,
+		TransportConfig{TLS: "implicit", Auth: "login", Username: "user", Password: "secret"},
+		relayOptions{implicit: true})
+
+	require.Nil(t, sendVia(s, "someone@recipient.test"))
+	assert.Equal(t, 1, relay.accepted())
+}
+
+func TestRelayLoginFailureIsTransient(t *testing.T) {
+	s, relay := newRelaySender(t, TransportConfig{Username: "user", Password: "wrong"}, relayOptions{})
+
+	err := sendVia(s, "someone@recipient.test")
+	require.NotNil(t, err)
+	assert.False(t, err.IsPermanent(), "wrong credentials say nothing about the recipient")
+	assert.Equal(t, uint32(535), err.Code())
+	assert.Contains(t, err.Error(), "relay corp")
+	assert.Zero(t, relay.accepted())
+}
+
+func TestRelayRefusalOfTheRecipientIsReportedAsIs(t *testing.T) {
+	s, _ := newRelaySender(t, TransportConfig{Username: "user", Password: "secret"}, relayOptions{})
+
+	err := sendVia(s, "nobody@refused.test")
+	require.NotNil(t, err)
+	assert.True(t, err.IsPermanent())
+	assert.Equal(t, uint32(550), err.Code())
+}
+
+func TestRelayWithoutStartTLSIsNotLoggedInto(t *testing.T) {
+	s, relay := newRelaySender(t, TransportConfig{Username: "user", Password: "secret"}, relayOptions{noTLS: true})
+
+	err := sendVia(s, "someone@recipient.test")
+	require.NotNil(t, err)
+	assert.False(t, err.IsPermanent())
+	assert.Contains(t, err.Error(), "STARTTLS")
+	assert.Zero(t, relay.authAttempts(), "the credentials never went over the unencrypted connection")
+}
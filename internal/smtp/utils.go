@@ -4,13 +4,15 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 var emailValidator = regexp.MustCompile(`^[^@ \t\r\n]+@[^@ \t\r\n]+\.[^@ \t\r\n]{2,}$`)
 
-// Validate if email address is formally correct
+// Validate if email address is formally correct. Internationalised addresses
+// (RFC 6531) are: a UTF-8 local part or domain is for the Sender to negotiate.
 func Validate(addr string) bool {
-	return emailValidator.MatchString(addr)
+	return utf8.ValidString(addr) && emailValidator.MatchString(addr)
 }
 
 // GetEmailDomain extracts domain host from a given email address
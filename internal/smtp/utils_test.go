@@ -19,6 +19,9 @@ func TestValidate(t *testing.T) {
 		{"a@b.com\nBcc: evil@x.com", false},
 		{"prefix a@b.com", false},
 		{"a@b.com suffix", false},
+		{"用户@例子.中国", true},
+		{"jörg@müller.de", true},
+		{"a\xff@b.com", false},
 	}
 
 	for _, tt := range examples {
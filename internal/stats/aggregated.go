@@ -1,10 +1,17 @@
 package stats
 
-import "time"
+import (
+	"time"
 
-// AggregatedStat represents a time-bucketed count of stats events.
+	"github.com/kannon-email/kannon/internal/bounce"
+)
+
+// AggregatedStat represents a time-bucketed count of stats events. Bounces are
+// counted per Category, so a bucket holds one AggregatedStat for each kind of
+// Bounce it saw; every other Type has an empty Category.
 type AggregatedStat struct {
 	Type      Type
+	Category  bounce.Category
 	Timestamp time.Time
 	Count     int64
 }
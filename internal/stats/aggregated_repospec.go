@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/values"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,9 @@ func RunAggregatedRepoSpec(t *testing.T, repo AggregatedStatsRepository) {
 	t.Run("Increment/SeparateEntries", func(t *testing.T) {
 		testIncrementSeparateEntries(t, repo)
 	})
+	t.Run("Increment/SeparateCategories", func(t *testing.T) {
+		testIncrementSeparateCategories(t, repo)
+	})
 	t.Run("Query/FiltersByDomain", func(t *testing.T) {
 		testAggregatedQueryFiltersByDomain(t, repo)
 	})
@@ -37,7 +41,7 @@ func testIncrementAndQuery(t *testing.T, repo AggregatedStatsRepository) {
 	domain := values.MustParse(fmt.Sprintf("incr-query-%d.test", time.Now().UnixNano()))
 	hour := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
 
-	err := repo.Increment(ctx, domain, hour, TypeDelivered)
+	err := repo.Increment(ctx, domain, hour, TypeDelivered, "")
 	require.NoError(t, err)
 
 	results, err := repo.Query(ctx, domain, TimeRange{
@@ -57,7 +61,7 @@ func testIncrementAccumulates(t *testing.T, repo AggregatedStatsRepository) {
 	hour := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
 
 	for range 5 {
-		err := repo.Increment(ctx, domain, hour, TypeDelivered)
+		err := repo.Increment(ctx, domain, hour, TypeDelivered, "")
 		require.NoError(t, err)
 	}
 
@@ -77,13 +81,13 @@ func testIncrementSeparateEntries(t *testing.T, repo AggregatedStatsRepository)
 	hour2 := time.Date(2026, 1, 15, 11, 0, 0, 0, time.UTC)
 
 	// Different hour of the same day, same type
-	err := repo.Increment(ctx, domain, hour1, TypeDelivered)
+	err := repo.Increment(ctx, domain, hour1, TypeDelivered, "")
 	require.NoError(t, err)
-	err = repo.Increment(ctx, domain, hour2, TypeDelivered)
+	err = repo.Increment(ctx, domain, hour2, TypeDelivered, "")
 	require.NoError(t, err)
 
 	// Same hour, different type
-	err = repo.Increment(ctx, domain, hour1, TypeOpened)
+	err = repo.Increment(ctx, domain, hour1, TypeOpened, "")
 	require.NoError(t, err)
 
 	results, err := repo.Query(ctx, domain, TimeRange{
@@ -94,6 +98,28 @@ func testIncrementSeparateEntries(t *testing.T, repo AggregatedStatsRepository)
 	assert.Len(t, results, 3, "different hour/type combos should create separate entries")
 }
 
+func testIncrementSeparateCategories(t *testing.T, repo AggregatedStatsRepository) {
+	ctx := t.Context()
+	domain := values.MustParse(fmt.Sprintf("incr-cat-%d.test", time.Now().UnixNano()))
+	hour := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for _, c := range []bounce.Category{bounce.CategoryMailboxFull, bounce.CategoryMailboxFull, bounce.CategorySpamBlock} {
+		require.NoError(t, repo.Increment(ctx, domain, hour, TypeBounce, c))
+	}
+
+	results, err := repo.Query(ctx, domain, TimeRange{Start: hour, Stop: hour.Add(time.Hour)})
+	require.NoError(t, err)
+	counts := map[bounce.Category]int64{}
+	for _, r := range results {
+		assert.Equal(t, TypeBounce, r.Type)
+		counts[r.Category] = r.Count
+	}
+	assert.Equal(t, map[bounce.Category]int64{
+		bounce.CategoryMailboxFull: 2,
+		bounce.CategorySpamBlock:   1,
+	}, counts, "a bucket of bounces is a counter per category")
+}
+
 func testAggregatedQueryFiltersByDomain(t *testing.T, repo AggregatedStatsRepository) {
 	ctx := t.Context()
 	suffix := strconv.FormatInt(time.Now().UnixNano(), 10)
@@ -102,11 +128,11 @@ func testAggregatedQueryFiltersByDomain(t *testing.T, repo AggregatedStatsReposi
 	hour := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
 
 	for range 3 {
-		err := repo.Increment(ctx, domainA, hour, TypeDelivered)
+		err := repo.Increment(ctx, domainA, hour, TypeDelivered, "")
 		require.NoError(t, err)
 	}
 	for range 2 {
-		err := repo.Increment(ctx, domainB, hour, TypeDelivered)
+		err := repo.Increment(ctx, domainB, hour, TypeDelivered, "")
 		require.NoError(t, err)
 	}
 
@@ -131,7 +157,7 @@ func testAggregatedQueryFiltersByTimeRange(t *testing.T, repo AggregatedStatsRep
 	hour3 := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
 
 	for _, hour := range []time.Time{hour1, hour2, hour3} {
-		err := repo.Increment(ctx, domain, hour, TypeDelivered)
+		err := repo.Increment(ctx, domain, hour, TypeDelivered, "")
 		require.NoError(t, err)
 	}
 
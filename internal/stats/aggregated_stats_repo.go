@@ -4,13 +4,15 @@ import (
 	"context"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/values"
 )
 
 // AggregatedStatsRepository defines persistence operations for aggregated stats. As with Repository,
 // the Domain is named by its canonical domain name: a counter incremented under one spelling and
-// read under another would silently be two counters.
+// read under another would silently be two counters. A counter is kept for each category of a
+// type, which is empty for every type but TypeBounce.
 type AggregatedStatsRepository interface {
-	Increment(ctx context.Context, domain values.DomainName, timestamp time.Time, statType Type) error
+	Increment(ctx context.Context, domain values.DomainName, timestamp time.Time, statType Type, category bounce.Category) error
 	Query(ctx context.Context, domain values.DomainName, timeRange TimeRange) ([]*AggregatedStat, error)
 }
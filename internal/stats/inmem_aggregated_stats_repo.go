@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/values"
 )
 
@@ -18,6 +19,7 @@ type aggregatedKey struct {
 	Domain    values.DomainName
 	Timestamp time.Time
 	Type      Type
+	Category  bounce.Category
 }
 
 func NewInMemAggregatedStatsRepository() *InMemAggregatedStatsRepository {
@@ -26,16 +28,17 @@ func NewInMemAggregatedStatsRepository() *InMemAggregatedStatsRepository {
 	}
 }
 
-func (r *InMemAggregatedStatsRepository) Increment(_ context.Context, domain values.DomainName, timestamp time.Time, statType Type) error {
+func (r *InMemAggregatedStatsRepository) Increment(_ context.Context, domain values.DomainName, timestamp time.Time, statType Type, category bounce.Category) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	k := aggregatedKey{Domain: domain, Timestamp: timestamp, Type: statType}
+	k := aggregatedKey{Domain: domain, Timestamp: timestamp, Type: statType, Category: category}
 	if existing, ok := r.stats[k]; ok {
 		existing.Count++
 	} else {
 		r.stats[k] = &AggregatedStat{
 			Type:      statType,
+			Category:  category,
 			Timestamp: timestamp,
 			Count:     1,
 		}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/values"
 )
 
@@ -82,14 +83,16 @@ func (r *InMemRepository) QueryTimeline(_ context.Context, domain values.DomainN
 	filtered := r.filter(domain, tr)
 
 	type key struct {
-		Type Type
-		Hour time.Time
+		Type     Type
+		Category bounce.Category
+		Hour     time.Time
 	}
 	buckets := make(map[key]int64)
 	for _, s := range filtered {
 		k := key{
-			Type: s.Type,
-			Hour: s.Timestamp.Truncate(time.Hour),
+			Type:     s.Type,
+			Category: s.Outcome.Category(),
+			Hour:     s.Timestamp.Truncate(time.Hour),
 		}
 		buckets[k]++
 	}
@@ -98,6 +101,7 @@ func (r *InMemRepository) QueryTimeline(_ context.Context, domain values.DomainN
 	for k, count := range buckets {
 		result = append(result, &AggregatedStat{
 			Type:      k.Type,
+			Category:  k.Category,
 			Timestamp: k.Hour,
 			Count:     count,
 		})
@@ -105,6 +109,9 @@ func (r *InMemRepository) QueryTimeline(_ context.Context, domain values.DomainN
 
 	sort.Slice(result, func(i, j int) bool {
 		if result[i].Timestamp.Equal(result[j].Timestamp) {
+			if result[i].Type == result[j].Type {
+				return result[i].Category < result[j].Category
+			}
 			return result[i].Type < result[j].Type
 		}
 		return result[i].Timestamp.Before(result[j].Timestamp)
@@ -1,5 +1,11 @@
 package stats
 
+import (
+	"time"
+
+	"github.com/kannon-email/kannon/internal/bounce"
+)
+
 // Outcome is what happened to one Delivery — one of the events CONTEXT.md lists
 // under "Outcomes (per Delivery)", together with whatever that particular event
 // has to say about itself. It is the shape every producer and consumer inside
@@ -18,8 +24,8 @@ package stats
 type Outcome struct {
 	typ Type
 
-	// reason is what Rejected and Failed have to say, msg what Bounced and
-	// Errored have. They are separate fields because they are not the same
+	// reason is what Rejected, Failed and Postponed have to say, msg what Bounced,
+	// Delayed and Errored have. They are separate fields because they are not the same
 	// thing: a reason is Kannon's own account of a Delivery it gave up on, a msg
 	// is a remote mail system quoted back. The wire keeps them apart too.
 	reason string
@@ -31,6 +37,44 @@ type Outcome struct {
 	userAgent string
 	ip        string
 	url       string
+
+	ipPool   string
+	sourceIP string
+
+	smtp SMTPSession
+
+	category bounce.Category
+
+	until time.Time
+
+	action string
+}
+
+// SMTPSession is the SMTP session an outcome of the SMTPSender came out of:
+// the host the message was offered to, how the session was secured, where it
+// failed and what the host said. It is what a Bounce is investigated with —
+// the reply code alone says that a receiver refused, not which of its hosts,
+// at which command or in what words.
+//
+// The transcript is one string, a line to each command and reply, so that the
+// struct stays the scalars an Outcome is made of.
+type SMTPSession struct {
+	MXHost string
+	MXIP   string
+	// Stage is where the session failed: connect, ehlo, starttls, auth, mail,
+	// rcpt or data. Empty on Delivered.
+	Stage string
+	// EnhancedCode is the RFC 3463 status, set on every outcome the
+	// SMTPSender reports — one no reply came with included.
+	EnhancedCode string
+	TLSVersion   string
+	TLSCipher    string
+	// Reply is the host's last reply, every line of it.
+	Reply string
+	// QueueID is the ID a delivered message was queued under at the host,
+	// when its reply named one in a form that is recognised.
+	QueueID    string
+	Transcript string
 }
 
 // Accepted is the Validator having accepted the recipient address. CONTEXT.md
@@ -74,6 +118,26 @@ func Bounced(permanent bool, code uint32, msg string) Outcome {
 	return Outcome{typ: TypeBounce, permanent: permanent, code: code, msg: msg}
 }
 
+// Delayed is a DSN saying the remote MTA has not delivered the message yet and
+// is still trying: RFC 3464's `Action: delayed`. It is not terminal — should
+// the MTA give up, it reports again and that report is Bounced — so the
+// Dispatcher does not consume it; counting it as a Bounce is what inflated
+// bounce rates before it existed. code and msg are the DSN's, as on an asynchronous
+// Bounced.
+func Delayed(code uint32, msg string) Outcome {
+	return Outcome{typ: TypeDelayed, code: code, msg: msg}
+}
+
+// Reported is a DSN saying the remote MTA delivered, relayed or expanded the
+// message: RFC 3464's `Action: delivered`, `relayed` or `expanded`, which
+// action is. The Delivery was already Delivered at handoff, so it is not
+// terminal either and the Dispatcher does not consume it — reusing Delivered
+// for it would have the Pool take the report for a second handoff — but it is
+// what the remote MTA said, and is recorded as Delayed is.
+func Reported(action string) Outcome {
+	return Outcome{typ: TypeReported, action: action}
+}
+
 // Errored is a transient transmission failure, which triggers a reschedule with
 // backoff.
 //
@@ -88,6 +152,21 @@ func Errored(code uint32, msg string) Outcome {
 	return Outcome{typ: TypeError, code: code, msg: msg}
 }
 
+// Postponed is the SMTPSender having held an Envelope back without offering it
+// — its sending address has spent its warm-up allowance, its destination is
+// cooling down or saturated — for longer than the sending stream should keep
+// it. The Dispatcher hands the Delivery back to the Pool to be claimed again
+// at until, without spending an attempt: nothing was sent, and a hold-back
+// left on the stream instead outlives the reclaim of 'sending' and goes out
+// twice (ADR 0004).
+//
+// Like Errored it is plumbing rather than an outcome of the Delivery, and
+// nothing customer-facing should render one. reason is Kannon's own account of
+// the hold-back, a short fixed phrase.
+func Postponed(until time.Time, reason string) Outcome {
+	return Outcome{typ: TypePostponed, until: until, reason: reason}
+}
+
 // Opened is a tracking pixel having been retrieved. Non-terminal and repeatable.
 // The user agent and IP are populated only under the Full Tracking Mode; under
 // any lower Mode they are empty because nothing was retained, which is why the
@@ -102,6 +181,36 @@ func Clicked(userAgent, ip, url string) Outcome {
 	return Outcome{typ: TypeClicked, userAgent: userAgent, ip: ip, url: url}
 }
 
+// WithSource is the outcome sent from sourceIP, an address of ipPool — empty
+// for none. It is meaningful on Delivered and on a Bounced the SMTPSender took
+// during transmission: receivers judge mail by the address it came from, so
+// that is what deliverability is analysed by. A Bounce that arrived later as a
+// DSN has no source the receiver's report can be trusted to name.
+func (o Outcome) WithSource(ipPool, sourceIP string) Outcome {
+	o.ipPool, o.sourceIP = ipPool, sourceIP
+	return o
+}
+
+// WithSMTP is the outcome with the SMTP session it came out of. It is
+// meaningful on Delivered, Errored and on a Bounced the SMTPSender took during
+// transmission. A Bounced, Delayed or Reported that arrived later as a DSN
+// came out of no session of ours, and carries what the report says of the
+// remote MTA's: the host it names as Remote-MTA, and its Status as the
+// enhanced code.
+func (o Outcome) WithSMTP(s SMTPSession) Outcome {
+	o.smtp = s
+	return o
+}
+
+// WithCategory is the outcome with the kind of failure it was. It is
+// meaningful on Bounced alone, and set by the Stats worker rather than by
+// whoever produced the Bounce, so that one set of rules classifies the
+// synchronous and the asynchronous ones alike.
+func (o Outcome) WithCategory(c bounce.Category) Outcome {
+	o.category = c
+	return o
+}
+
 // Type reports which outcome this is. The zero Outcome reports TypeUnknown: an
 // event that states no outcome is one this build cannot read, which is what the
 // protobuf-inspecting predicate this replaced reported for a nil payload.
@@ -112,11 +221,11 @@ func (o Outcome) Type() Type {
 	return o.typ
 }
 
-// Reason is Rejected's or Failed's account of itself, and empty for every other
-// outcome.
+// Reason is Rejected's, Failed's or Postponed's account of itself, and empty
+// for every other outcome.
 func (o Outcome) Reason() string { return o.reason }
 
-// Msg is the reply text Bounced or Errored was built from, and empty for every
+// Msg is the reply text Bounced, Delayed or Errored was built from, and empty for every
 // other outcome.
 func (o Outcome) Msg() string { return o.msg }
 
@@ -124,7 +233,7 @@ func (o Outcome) Msg() string { return o.msg }
 // does and does not assert.
 func (o Outcome) Permanent() bool { return o.permanent }
 
-// Code is the SMTP reply code Bounced or Errored carries. Failed has none by
+// Code is the SMTP reply code Bounced, Delayed or Errored carries. Failed has none by
 // construction.
 func (o Outcome) Code() uint32 { return o.code }
 
@@ -136,3 +245,25 @@ func (o Outcome) IP() string { return o.ip }
 
 // URL is the link a Clicked event followed.
 func (o Outcome) URL() string { return o.url }
+
+// IPPool is the IP pool a Delivered or Bounced was sent from, empty for none.
+func (o Outcome) IPPool() string { return o.ipPool }
+
+// SourceIP is the local address a Delivered or Bounced was sent from.
+func (o Outcome) SourceIP() string { return o.sourceIP }
+
+// SMTP is the session a Delivered, Errored or Bounced came out of, or what a
+// DSN said of the remote MTA's, the zero SMTPSession for none.
+func (o Outcome) SMTP() SMTPSession { return o.smtp }
+
+// Until is when a Postponed Delivery is to be claimed again, the zero time on
+// every other outcome.
+func (o Outcome) Until() time.Time { return o.until }
+
+// Action is what a Reported DSN says became of the message — delivered,
+// relayed or expanded — and empty on every other outcome.
+func (o Outcome) Action() string { return o.action }
+
+// Category is the kind of failure a Bounced was, empty on every other outcome
+// and on a Bounced stored before Bounces were classified.
+func (o Outcome) Category() bounce.Category { return o.category }
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/values"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,6 +41,9 @@ func RunRepoSpec(t *testing.T, repo Repository) {
 	t.Run("QueryTimeline", func(t *testing.T) {
 		testQueryTimeline(t, repo)
 	})
+	t.Run("QueryTimeline/BouncesByCategory", func(t *testing.T) {
+		testQueryTimelineBouncesByCategory(t, repo)
+	})
 	t.Run("DeleteOlderThan", func(t *testing.T) {
 		testDeleteOlderThan(t, repo)
 	})
@@ -275,6 +279,35 @@ func testQueryTimeline(t *testing.T, repo Repository) {
 	assert.Equal(t, int64(2), hour1Delivered.Count)
 }
 
+func testQueryTimelineBouncesByCategory(t *testing.T, repo Repository) {
+	ctx := t.Context()
+	domain := values.MustParse(fmt.Sprintf("timeline-cat-%d.test", time.Now().UnixNano()))
+	hour := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	full := Bounced(false, 452, "452 4.2.2 mailbox full").WithCategory(bounce.CategoryMailboxFull)
+	spam := Bounced(true, 550, "550 5.7.1 spam").WithCategory(bounce.CategorySpamBlock)
+	unclassified := Bounced(true, 550, "550 no")
+	for i, o := range []Outcome{full, full, spam, unclassified} {
+		require.NoError(t, repo.Insert(ctx, &Stat{
+			Type: TypeBounce, Email: fmt.Sprintf("r%d@e.com", i), MessageID: "m1",
+			Domain: domain, Timestamp: hour.Add(time.Duration(i) * time.Minute), Outcome: o,
+		}))
+	}
+
+	timeline, err := repo.QueryTimeline(ctx, domain, TimeRange{Start: hour, Stop: hour.Add(time.Hour)})
+	require.NoError(t, err)
+	counts := map[bounce.Category]int64{}
+	for _, a := range timeline {
+		assert.Equal(t, TypeBounce, a.Type)
+		counts[a.Category] = a.Count
+	}
+	assert.Equal(t, map[bounce.Category]int64{
+		bounce.CategoryMailboxFull: 2,
+		bounce.CategorySpamBlock:   1,
+		"":                         1,
+	}, counts)
+}
+
 func testDeleteOlderThan(t *testing.T, repo Repository) {
 	ctx := t.Context()
 	domain := values.MustParse(fmt.Sprintf("delete-%d.test", time.Now().UnixNano()))
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/kannon-email/kannon/internal/authz"
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/values"
 )
 
@@ -78,15 +79,16 @@ func (s *Service) QueryTimeline(ctx context.Context, domain values.DomainName, t
 // ErrNoAggregatedRepo is returned when aggregated stats operations are called without a configured repository.
 var ErrNoAggregatedRepo = errors.New("aggregated stats repository not configured")
 
-// IncrementAggregatedStat increments the hourly counter for a stat type. The bucket is the UTC
-// hour, the same granularity v1 reports, so a consumer can roll buckets into days of whatever
-// timezone it displays — which a UTC day bucket cannot do for negative offsets.
-func (s *Service) IncrementAggregatedStat(ctx context.Context, domain values.DomainName, timestamp time.Time, statType Type) error {
+// IncrementAggregatedStat increments the hourly counter for a stat type and, for a Bounce, its
+// category. The bucket is the UTC hour, the same granularity v1 reports, so a consumer can roll
+// buckets into days of whatever timezone it displays — which a UTC day bucket cannot do for
+// negative offsets.
+func (s *Service) IncrementAggregatedStat(ctx context.Context, domain values.DomainName, timestamp time.Time, statType Type, category bounce.Category) error {
 	if s.aggregatedRepo == nil {
 		return ErrNoAggregatedRepo
 	}
 	truncated := timestamp.UTC().Truncate(time.Hour)
-	return s.aggregatedRepo.Increment(ctx, domain, truncated, statType)
+	return s.aggregatedRepo.Increment(ctx, domain, truncated, statType, category)
 }
 
 // QueryAggregatedStats returns v2's counters, the one read guarded on the narrower AggregatedStats
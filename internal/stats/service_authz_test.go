@@ -134,7 +134,7 @@ func TestWritesNeedNoPrincipal(t *testing.T) {
 
 	stat := stats.NewStat("u@example.com", "msg-worker", exampleCom, ts, stats.Delivered())
 	require.NoError(t, service.InsertStat(context.Background(), stat))
-	require.NoError(t, service.IncrementAggregatedStat(context.Background(), exampleCom, ts, stats.TypeDelivered))
+	require.NoError(t, service.IncrementAggregatedStat(context.Background(), exampleCom, ts, stats.TypeDelivered, ""))
 
 	_, err := service.Cleanup(context.Background(), time.Hour)
 	require.NoError(t, err)
@@ -152,7 +152,7 @@ func seededService(t *testing.T) *stats.Service {
 
 	stat := stats.NewStat("u@example.com", "msg-seeded", exampleCom, ts, stats.Delivered())
 	require.NoError(t, service.InsertStat(t.Context(), stat))
-	require.NoError(t, service.IncrementAggregatedStat(t.Context(), exampleCom, ts, stats.TypeDelivered))
+	require.NoError(t, service.IncrementAggregatedStat(t.Context(), exampleCom, ts, stats.TypeDelivered, ""))
 
 	return service
 }
@@ -254,6 +254,9 @@ func TestOutcomeType(t *testing.T) {
 		{"clicked", stats.Clicked("", "", "https://example.com"), stats.TypeClicked},
 		{"bounced", stats.Bounced(true, 550, "no such user"), stats.TypeBounce},
 		{"error", stats.Errored(421, "try again"), stats.TypeError},
+		{"delayed", stats.Delayed(451, "still trying"), stats.TypeDelayed},
+		{"postponed", stats.Postponed(time.Now(), "IP warm-up"), stats.TypePostponed},
+		{"reported", stats.Reported("delivered"), stats.TypeReported},
 		// Failed is the absence of any reply at all — a Delivery whose retry budget ran out
 		// without a single attempt ever being answered (CONTEXT.md, Failed / ADR 0007).
 		{"failed", stats.Failed("retry budget exhausted"), stats.TypeFailed},
@@ -277,7 +280,7 @@ func TestIncrementAggregatedStat(t *testing.T) {
 
 	// Increment the same domain/hour/type 3 times.
 	for range 3 {
-		if err := svc.IncrementAggregatedStat(ctx, exampleCom, ts, stats.TypeDelivered); err != nil {
+		if err := svc.IncrementAggregatedStat(ctx, exampleCom, ts, stats.TypeDelivered, ""); err != nil {
 			t.Fatalf("IncrementAggregatedStat: %v", err)
 		}
 	}
@@ -310,13 +313,13 @@ func TestIncrementAggregatedStat_SeparateEntries(t *testing.T) {
 	day2 := time.Date(2026, 1, 16, 14, 0, 0, 0, time.UTC)
 
 	// Different days and types should create separate entries.
-	if err := svc.IncrementAggregatedStat(ctx, exampleCom, day1, stats.TypeDelivered); err != nil {
+	if err := svc.IncrementAggregatedStat(ctx, exampleCom, day1, stats.TypeDelivered, ""); err != nil {
 		t.Fatalf("IncrementAggregatedStat: %v", err)
 	}
-	if err := svc.IncrementAggregatedStat(ctx, exampleCom, day1, stats.TypeOpened); err != nil {
+	if err := svc.IncrementAggregatedStat(ctx, exampleCom, day1, stats.TypeOpened, ""); err != nil {
 		t.Fatalf("IncrementAggregatedStat: %v", err)
 	}
-	if err := svc.IncrementAggregatedStat(ctx, exampleCom, day2, stats.TypeDelivered); err != nil {
+	if err := svc.IncrementAggregatedStat(ctx, exampleCom, day2, stats.TypeDelivered, ""); err != nil {
 		t.Fatalf("IncrementAggregatedStat: %v", err)
 	}
 
@@ -346,7 +349,7 @@ func TestIncrementAggregatedStat_HourlyBuckets(t *testing.T) {
 	hour14 := time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)
 
 	for _, ts := range []time.Time{hour10, sameHour, hour14} {
-		if err := svc.IncrementAggregatedStat(ctx, exampleCom, ts, stats.TypeDelivered); err != nil {
+		if err := svc.IncrementAggregatedStat(ctx, exampleCom, ts, stats.TypeDelivered, ""); err != nil {
 			t.Fatalf("IncrementAggregatedStat: %v", err)
 		}
 	}
@@ -382,10 +385,10 @@ func TestQueryAggregatedStats_FiltersByDomainAndTimeRange(t *testing.T) {
 
 	ts := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
 
-	if err := svc.IncrementAggregatedStat(ctx, aCom, ts, stats.TypeDelivered); err != nil {
+	if err := svc.IncrementAggregatedStat(ctx, aCom, ts, stats.TypeDelivered, ""); err != nil {
 		t.Fatalf("IncrementAggregatedStat: %v", err)
 	}
-	if err := svc.IncrementAggregatedStat(ctx, bCom, ts, stats.TypeDelivered); err != nil {
+	if err := svc.IncrementAggregatedStat(ctx, bCom, ts, stats.TypeDelivered, ""); err != nil {
 		t.Fatalf("IncrementAggregatedStat: %v", err)
 	}
 
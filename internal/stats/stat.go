@@ -16,7 +16,10 @@ const (
 	TypeOpened    Type = "opened"
 	TypeClicked   Type = "clicked"
 	TypeBounce    Type = "bounced"
+	TypeDelayed   Type = "delayed"
+	TypeReported  Type = "reported"
 	TypeError     Type = "error"
+	TypePostponed Type = "postponed"
 	TypeFailed    Type = "failed"
 	TypeUnknown   Type = "unknown"
 )
@@ -74,8 +77,11 @@ var DisplayName = map[Type]string{
 	TypeRejected:  "Rejected",
 	TypeBounce:    "Bounced",
 	TypeClicked:   "Clicked",
+	TypeDelayed:   "Delayed",
 	TypeDelivered: "Delivered",
 	TypeError:     "Send Error",
+	TypePostponed: "Postponed",
+	TypeReported:  "Delivery Reported",
 	TypeFailed:    "Failed",
 	TypeOpened:    "Opened",
 	TypeUnknown:   "Unknown",
@@ -13,6 +13,7 @@
 package statspb
 
 import (
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/stats"
 	"github.com/kannon-email/kannon/internal/trackingpb"
 	pbtypes "github.com/kannon-email/kannon/proto/kannon/stats/types"
@@ -53,7 +54,7 @@ func FromOutcome(o stats.Outcome) *pbtypes.StatsData {
 		}}
 	case stats.TypeDelivered:
 		return &pbtypes.StatsData{Data: &pbtypes.StatsData_Delivered{
-			Delivered: &pbtypes.StatsDataDelivered{},
+			Delivered: &pbtypes.StatsDataDelivered{IpPool: o.IPPool(), SourceIp: o.SourceIP(), Smtp: fromSMTP(o.SMTP())},
 		}}
 	case stats.TypeRejected:
 		return &pbtypes.StatsData{Data: &pbtypes.StatsData_Rejected{
@@ -69,11 +70,27 @@ func FromOutcome(o stats.Outcome) *pbtypes.StatsData {
 				Permanent: o.Permanent(),
 				Code:      o.Code(),
 				Msg:       o.Msg(),
+				IpPool:    o.IPPool(),
+				SourceIp:  o.SourceIP(),
+				Smtp:      fromSMTP(o.SMTP()),
+				Category:  string(o.Category()),
 			},
 		}}
+	case stats.TypeDelayed:
+		return &pbtypes.StatsData{Data: &pbtypes.StatsData_Delayed{
+			Delayed: &pbtypes.StatsDataDelayed{Code: o.Code(), Msg: o.Msg(), Smtp: fromSMTP(o.SMTP())},
+		}}
+	case stats.TypeReported:
+		return &pbtypes.StatsData{Data: &pbtypes.StatsData_Reported{
+			Reported: &pbtypes.StatsDataReported{Action: o.Action(), Smtp: fromSMTP(o.SMTP())},
+		}}
 	case stats.TypeError:
 		return &pbtypes.StatsData{Data: &pbtypes.StatsData_Error{
-			Error: &pbtypes.StatsDataError{Code: o.Code(), Msg: o.Msg()},
+			Error: &pbtypes.StatsDataError{Code: o.Code(), Msg: o.Msg(), Smtp: fromSMTP(o.SMTP())},
+		}}
+	case stats.TypePostponed:
+		return &pbtypes.StatsData{Data: &pbtypes.StatsData_Postponed{
+			Postponed: &pbtypes.StatsDataPostponed{Until: timestamppb.New(o.Until()), Reason: o.Reason()},
 		}}
 	case stats.TypeOpened:
 		return &pbtypes.StatsData{Data: &pbtypes.StatsData_Opened{
@@ -102,15 +119,25 @@ func ToOutcome(d *pbtypes.StatsData) stats.Outcome {
 	case *pbtypes.StatsData_Accepted:
 		return stats.Accepted()
 	case *pbtypes.StatsData_Delivered:
-		return stats.Delivered()
+		return stats.Delivered().WithSource(v.Delivered.GetIpPool(), v.Delivered.GetSourceIp()).
+			WithSMTP(toSMTP(v.Delivered.GetSmtp()))
 	case *pbtypes.StatsData_Rejected:
 		return stats.Rejected(v.Rejected.GetReason())
 	case *pbtypes.StatsData_Failed:
 		return stats.Failed(v.Failed.GetReason())
 	case *pbtypes.StatsData_Bounced:
-		return stats.Bounced(v.Bounced.GetPermanent(), v.Bounced.GetCode(), v.Bounced.GetMsg())
+		return stats.Bounced(v.Bounced.GetPermanent(), v.Bounced.GetCode(), v.Bounced.GetMsg()).
+			WithSource(v.Bounced.GetIpPool(), v.Bounced.GetSourceIp()).
+			WithSMTP(toSMTP(v.Bounced.GetSmtp())).
+			WithCategory(bounce.Category(v.Bounced.GetCategory()))
+	case *pbtypes.StatsData_Delayed:
+		return stats.Delayed(v.Delayed.GetCode(), v.Delayed.GetMsg()).WithSMTP(toSMTP(v.Delayed.GetSmtp()))
+	case *pbtypes.StatsData_Reported:
+		return stats.Reported(v.Reported.GetAction()).WithSMTP(toSMTP(v.Reported.GetSmtp()))
 	case *pbtypes.StatsData_Error:
-		return stats.Errored(v.Error.GetCode(), v.Error.GetMsg())
+		return stats.Errored(v.Error.GetCode(), v.Error.GetMsg()).WithSMTP(toSMTP(v.Error.GetSmtp()))
+	case *pbtypes.StatsData_Postponed:
+		return stats.Postponed(v.Postponed.GetUntil().AsTime(), v.Postponed.GetReason())
 	case *pbtypes.StatsData_Opened:
 		return stats.Opened(v.Opened.GetUserAgent(), v.Opened.GetIp())
 	case *pbtypes.StatsData_Clicked:
@@ -120,6 +147,39 @@ func ToOutcome(d *pbtypes.StatsData) stats.Outcome {
 	}
 }
 
+// fromSMTP renders a session, nil for none so that an outcome without one is
+// the bytes it was before sessions were recorded.
+func fromSMTP(s stats.SMTPSession) *pbtypes.StatsDataSMTP {
+	if s == (stats.SMTPSession{}) {
+		return nil
+	}
+	return &pbtypes.StatsDataSMTP{
+		MxHost:       s.MXHost,
+		MxIp:         s.MXIP,
+		Stage:        s.Stage,
+		EnhancedCode: s.EnhancedCode,
+		TlsVersion:   s.TLSVersion,
+		TlsCipher:    s.TLSCipher,
+		Reply:        s.Reply,
+		QueueId:      s.QueueID,
+		Transcript:   s.Transcript,
+	}
+}
+
+func toSMTP(s *pbtypes.StatsDataSMTP) stats.SMTPSession {
+	return stats.SMTPSession{
+		MXHost:       s.GetMxHost(),
+		MXIP:         s.GetMxIp(),
+		Stage:        s.GetStage(),
+		EnhancedCode: s.GetEnhancedCode(),
+		TLSVersion:   s.GetTlsVersion(),
+		TLSCipher:    s.GetTlsCipher(),
+		Reply:        s.GetReply(),
+		QueueID:      s.GetQueueId(),
+		Transcript:   s.GetTranscript(),
+	}
+}
+
 // FromEvent renders an Event as the Stats message published on kannon.stats.*.
 // The subject it is published under is derived from the same Outcome, in
 // internal/publisher, so the two cannot disagree (#376).
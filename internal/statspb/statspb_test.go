@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/bounce"
 	"github.com/kannon-email/kannon/internal/stats"
 	"github.com/kannon-email/kannon/internal/statspb"
 	"github.com/kannon-email/kannon/internal/tracking"
@@ -34,6 +35,13 @@ var everyOutcome = []struct {
 		stats.Delivered(),
 		&pbtypes.StatsData{Data: &pbtypes.StatsData_Delivered{Delivered: &pbtypes.StatsDataDelivered{}}},
 	},
+	{
+		"DeliveredFromAPool",
+		stats.Delivered().WithSource("bulk", "192.0.2.1"),
+		&pbtypes.StatsData{Data: &pbtypes.StatsData_Delivered{
+			Delivered: &pbtypes.StatsDataDelivered{IpPool: "bulk", SourceIp: "192.0.2.1"},
+		}},
+	},
 	{
 		"Rejected",
 		stats.Rejected("not a valid email"),
@@ -65,6 +73,73 @@ var everyOutcome = []struct {
 			Bounced: &pbtypes.StatsDataBounced{Permanent: false, Code: 450, Msg: "450 mailbox temporarily unavailable"},
 		}},
 	},
+	{
+		"BouncedFromAPool",
+		stats.Bounced(true, 550, "550 no such user").WithSource("bulk", "2001:db8::1"),
+		&pbtypes.StatsData{Data: &pbtypes.StatsData_Bounced{
+			Bounced: &pbtypes.StatsDataBounced{Permanent: true, Code: 550, Msg: "550 no such user", IpPool: "bulk", SourceIp: "2001:db8::1"},
+		}},
+	},
+	{
+		"BouncedInASession",
+		stats.Bounced(true, 550, "550 no such user").WithSMTP(stats.SMTPSession{
+			MXHost:       "mx1.example.com",
+			MXIP:         "192.0.2.25",
+			Stage:        "rcpt",
+			EnhancedCode: "5.1.1",
+			TLSVersion:   "TLS 1.3",
+			TLSCipher:    "TLS_AES_128_GCM_SHA256",
+			Reply:        "550 5.1.1 no such user",
+			Transcript:   "C: RCPT TO:<a@example.com>\nS: 550 5.1.1 no such user",
+		}),
+		&pbtypes.StatsData{Data: &pbtypes.StatsData_Bounced{
+			Bounced: &pbtypes.StatsDataBounced{Permanent: true, Code: 550, Msg: "550 no such user", Smtp: &pbtypes.StatsDataSMTP{
+				MxHost:       "mx1.example.com",
+				MxIp:         "192.0.2.25",
+				Stage:        "rcpt",
+				EnhancedCode: "5.1.1",
+				TlsVersion:   "TLS 1.3",
+				TlsCipher:    "TLS_AES_128_GCM_SHA256",
+				Reply:        "550 5.1.1 no such user",
+				Transcript:   "C: RCPT TO:<a@example.com>\nS: 550 5.1.1 no such user",
+			}},
+		}},
+	},
+	{
+		"BouncedWithACategory",
+		stats.Bounced(false, 452, "452 4.2.2 mailbox full").WithCategory(bounce.CategoryMailboxFull),
+		&pbtypes.StatsData{Data: &pbtypes.StatsData_Bounced{
+			Bounced: &pbtypes.StatsDataBounced{Code: 452, Msg: "452 4.2.2 mailbox full", Category: "mailbox_full"},
+		}},
+	},
+	{
+		"DeliveredWithAQueueID",
+		stats.Delivered().WithSMTP(stats.SMTPSession{MXHost: "mx1.example.com", EnhancedCode: "2.0.0", QueueID: "4Xy3Zt1Q2bz9"}),
+		&pbtypes.StatsData{Data: &pbtypes.StatsData_Delivered{
+			Delivered: &pbtypes.StatsDataDelivered{Smtp: &pbtypes.StatsDataSMTP{MxHost: "mx1.example.com", EnhancedCode: "2.0.0", QueueId: "4Xy3Zt1Q2bz9"}},
+		}},
+	},
+	{
+		"Delayed",
+		stats.Delayed(451, "smtp; 451 4.4.1 no answer from host").WithSMTP(stats.SMTPSession{MXHost: "mx1.example.com", EnhancedCode: "4.4.1"}),
+		&pbtypes.StatsData{Data: &pbtypes.StatsData_Delayed{
+			Delayed: &pbtypes.StatsDataDelayed{Code: 451, Msg: "smtp; 451 4.4.1 no answer from host", Smtp: &pbtypes.StatsDataSMTP{MxHost: "mx1.example.com", EnhancedCode: "4.4.1"}},
+		}},
+	},
+	{
+		"Postponed",
+		stats.Postponed(time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), "IP warm-up"),
+		&pbtypes.StatsData{Data: &pbtypes.StatsData_Postponed{
+			Postponed: &pbtypes.StatsDataPostponed{Until: timestamppb.New(time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)), Reason: "IP warm-up"},
+		}},
+	},
+	{
+		"Reported",
+		stats.Reported("relayed").WithSMTP(stats.SMTPSession{MXHost: "mx1.example.com", EnhancedCode: "2.0.0"}),
+		&pbtypes.StatsData{Data: &pbtypes.StatsData_Reported{
+			Reported: &pbtypes.StatsDataReported{Action: "relayed", Smtp: &pbtypes.StatsDataSMTP{MxHost: "mx1.example.com", EnhancedCode: "2.0.0"}},
+		}},
+	},
 	{
 		"Errored",
 		stats.Errored(421, "451 try again later"),
@@ -108,14 +183,15 @@ func TestOutcomeRoundTrip(t *testing.T) {
 
 // TestOutcomeTypesAreDistinct guards the switch in FromOutcome against two
 // outcomes collapsing onto one wire variant, which a per-case round trip would
-// not notice if the pair happened to be symmetric. The table holds nine cases
-// for eight types: the two Bounced ones differ only in reply class.
+// not notice if the pair happened to be symmetric. The table holds more cases
+// than there are types: several outcomes appear with and without their
+// optional fields.
 func TestOutcomeTypesAreDistinct(t *testing.T) {
 	seen := make(map[stats.Type]bool, len(everyOutcome))
 	for _, tc := range everyOutcome {
 		seen[statspb.ToOutcome(statspb.FromOutcome(tc.out)).Type()] = true
 	}
-	assert.Len(t, seen, 8, "every outcome should translate to a type of its own")
+	assert.Len(t, seen, 11, "every outcome should translate to a type of its own")
 	assert.NotContains(t, seen, stats.TypeUnknown, "no outcome should translate to Unknown")
 }
 
@@ -203,6 +279,7 @@ func TestLegacyTypeFieldIsPreserved(t *testing.T) {
 	for _, o := range []stats.Outcome{
 		stats.Accepted(), stats.Delivered(), stats.Rejected("r"),
 		stats.Failed("r"), stats.Bounced(true, 550, "m"), stats.Errored(421, "m"),
+		stats.Delayed(451, "m"), stats.Postponed(time.Now(), "r"), stats.Reported("delivered"),
 	} {
 		assert.Empty(t, event(o).Type,
 			"%s has never carried the redundant type field", o.Type())
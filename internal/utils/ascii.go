@@ -0,0 +1,14 @@
+package utils
+
+import "unicode/utf8"
+
+// IsASCII reports whether s holds no byte outside 7-bit ASCII: a header,
+// body or address that needs no encoding and no SMTPUTF8 to travel as it is.
+func IsASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
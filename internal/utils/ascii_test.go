@@ -0,0 +1,15 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsASCII(t *testing.T) {
+	assert.True(t, utils.IsASCII(""))
+	assert.True(t, utils.IsASCII("user+tag@example.com"))
+	assert.False(t, utils.IsASCII("josé@example.com"))
+	assert.False(t, utils.IsASCII("user@bücher.example"))
+}
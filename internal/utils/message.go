@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 var extractMsgIDReg = regexp.MustCompile(`<.+\/(?P<messageId>.+)>`)
@@ -32,18 +33,30 @@ func ExtractMsgIDAndDomainFromEmailID(emailID string) (msgID string, domain stri
 	return
 }
 
-var parseReturnPath = regexp.MustCompile(`bump_(?P<emailHash>[^+]*)\+(?P<messageID>.*)`)
+var parseReturnPath = regexp.MustCompile(`bump_(?P<emailHash>[^+.]*)(?:\.(?P<tag>[^+]*))?\+(?P<messageID>.*)`)
 
+// ParseBounceReturnPath recovers the recipient and message ID a bounce address
+// was built for. It reads both shapes buildReturnPath produces
+// (internal/envelope/message.go): "bump_<email>+<messageID>", addressed to the
+// sending Domain, and "bump_<email>+<id>=<domain>@<return-path domain>", where
+// the message ID's own "@" is written "=" so the address can end at a
+// return-path domain instead. The domain reported is always the message ID's
+// — the Domain that sent the mail — never the return-path domain.
+//
+// Either shape may carry a signature after the email, "bump_<email>.<tag>+...";
+// it is skipped here, and read by BounceReturnPathTag for internal/returnpath
+// to verify. The '.' cannot occur in the email segment, whose alphabet is the
+// URL-safe base64 one.
 func ParseBounceReturnPath(returnPath string) (email string, messageID string, domain string, found bool, err error) {
 	match := parseReturnPath.FindStringSubmatch(returnPath)
 	if match == nil {
 		return "", "", "", false, nil
 	}
-	if len(match) != 3 {
+	if len(match) != 4 {
 		return "", "", "", false, fmt.Errorf("invalid returnPath: %v", returnPath)
 	}
 	emailHash := match[1]
-	messageID = match[2]
+	messageID = messageIDFromReturnPath(match[3])
 	found = true
 
 	// The email segment is encoded with the URL-safe alphabet by buildReturnPath
@@ -62,3 +75,29 @@ func ParseBounceReturnPath(returnPath string) (email string, messageID string, d
 	}
 	return
 }
+
+// BounceReturnPathTag returns the signature a bounce address carries, "" when
+// it carries none or is not a bounce address at all.
+func BounceReturnPathTag(returnPath string) string {
+	match := parseReturnPath.FindStringSubmatch(returnPath)
+	if match == nil {
+		return ""
+	}
+	return match[2]
+}
+
+// messageIDFromReturnPath reads the message ID out of what follows the '+'. A
+// local part carrying '=' is the return-path-domain shape, whose '=' stands for
+// the message ID's '@'; anything else is the message ID as it was written.
+func messageIDFromReturnPath(tail string) string {
+	at := strings.LastIndex(tail, "@")
+	if at < 0 {
+		return tail
+	}
+	local := tail[:at]
+	eq := strings.LastIndex(local, "=")
+	if eq < 0 {
+		return tail
+	}
+	return local[:eq] + "@" + local[eq+1:]
+}
@@ -43,6 +43,33 @@ func TestParseBounceReturnPath(t *testing.T) {
 	assert.Equal(t, "msg_cl6g7ndft0001018ut5octeun@k.test.com", messageID)
 }
 
+// A bounce to a return-path domain carries the message ID with its '@' written
+// '=', and is attributed to the Domain the message ID names.
+func TestParseBounceReturnPathOnAReturnPathDomain(t *testing.T) {
+	returnPath := "bump_dGVzdEB0ZXN0LmNvbQ==+msg_cl6g7ndft0001018ut5octeun=k.test.com@bounces.k.test.com"
+	email, messageID, domain, found, err := utils.ParseBounceReturnPath(returnPath)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "k.test.com", domain)
+	assert.Equal(t, "test@test.com", email)
+	assert.Equal(t, "msg_cl6g7ndft0001018ut5octeun@k.test.com", messageID)
+}
+
+// A signed bounce address parses as the unsigned one does, with its tag read
+// separately.
+func TestParseBounceReturnPathSkipsTheSignature(t *testing.T) {
+	returnPath := "bump_dGVzdEB0ZXN0LmNvbQ==.17450123456789abcdef+msg_cl6g7ndft0001018ut5octeun=k.test.com@bounces.k.test.com"
+	email, messageID, domain, found, err := utils.ParseBounceReturnPath(returnPath)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "k.test.com", domain)
+	assert.Equal(t, "test@test.com", email)
+	assert.Equal(t, "msg_cl6g7ndft0001018ut5octeun@k.test.com", messageID)
+	assert.Equal(t, "17450123456789abcdef", utils.BounceReturnPathTag(returnPath))
+
+	assert.Empty(t, utils.BounceReturnPathTag("bump_dGVzdEB0ZXN0LmNvbQ==+msg_cl6g7ndft0001018ut5octeun@k.test.com"))
+}
+
 // buildReturnPathForTest replicates buildReturnPath from internal/envelope/message.go,
 // which is unexported and therefore not reachable from here. This copy could in
 // principle drift from the real encoder, so the encoder and decoder are also pinned
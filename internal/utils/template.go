@@ -1,13 +1,13 @@
 package utils
 
 import (
-	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
 )
 
 func ReplaceCustomFields(str string, fields map[string]string) string {
-	return replaceCustomFields(str, fields, func(v string) string { return v })
+	return ParseTemplate(str).Render(fields)
 }
 
 // ReplaceCustomFieldsInURL substitutes the same placeholders as
@@ -25,24 +25,72 @@ func ReplaceCustomFields(str string, fields map[string]string) string {
 // unsubscribe URL sits in practice. A value substituted into a path segment is
 // still safe, but a space in one arrives as `+` rather than `%20`.
 func ReplaceCustomFieldsInURL(str string, fields map[string]string) string {
-	return replaceCustomFields(str, fields, url.QueryEscape)
+	return ParseTemplate(str).RenderURL(fields)
 }
 
-func replaceCustomFields(str string, fields map[string]string, escape func(string) string) string {
-	for key, value := range fields {
-		regExp := fmt.Sprintf(`\{\{ *%s *\}\}`, key)
-		reg, err := regexp.Compile(regExp)
-		if err != nil {
-			continue
-		}
-		str = reg.ReplaceAllString(str, escape(value))
+// placeholderReg matches a placeholder in the shape a Template substitutes:
+// `{{ name }}`, with optional spaces and no nesting. The group is the name.
+var placeholderReg = regexp.MustCompile(`\{\{ *([^{}]*?) *\}\}`)
+
+// Template is a string with its placeholders located once, so that rendering it
+// for one Recipient after another is a walk over its pieces rather than a pass
+// of a regular expression per field. The Builder parses a Batch's subject and
+// HTML once and renders them for every Delivery of the Batch.
+//
+// A placeholder naming a field the map does not hold is left exactly as written,
+// which is what HasUnresolvedPlaceholders looks for afterwards. A value is put in
+// as it is and never read again for placeholders of its own, so a Recipient's
+// field cannot smuggle in another's.
+type Template struct {
+	// literals has one more entry than names: the text before each placeholder,
+	// then whatever follows the last one.
+	literals []string
+	names    []string
+	// raw is each placeholder as written, put back when its field is missing.
+	raw []string
+}
+
+// ParseTemplate locates the placeholders of str.
+func ParseTemplate(str string) Template {
+	var t Template
+	last := 0
+	for _, m := range placeholderReg.FindAllStringSubmatchIndex(str, -1) {
+		t.literals = append(t.literals, str[last:m[0]])
+		t.names = append(t.names, str[m[2]:m[3]])
+		t.raw = append(t.raw, str[m[0]:m[1]])
+		last = m[1]
 	}
-	return str
+	t.literals = append(t.literals, str[last:])
+	return t
+}
+
+// Render substitutes fields into the template as they are.
+func (t Template) Render(fields map[string]string) string {
+	return t.render(fields, func(v string) string { return v })
 }
 
-// placeholderReg matches a placeholder in the shape replaceCustomFields
-// substitutes: `{{ name }}`, with optional spaces and no nesting.
-var placeholderReg = regexp.MustCompile(`\{\{ *[^{}]* *\}\}`)
+// RenderURL substitutes fields percent-encoded for a query component, for the
+// reason ReplaceCustomFieldsInURL gives.
+func (t Template) RenderURL(fields map[string]string) string {
+	return t.render(fields, url.QueryEscape)
+}
+
+func (t Template) render(fields map[string]string, escape func(string) string) string {
+	if len(t.names) == 0 {
+		return strings.Join(t.literals, "")
+	}
+	var out strings.Builder
+	for i, name := range t.names {
+		out.WriteString(t.literals[i])
+		if value, ok := fields[name]; ok {
+			out.WriteString(escape(value))
+		} else {
+			out.WriteString(t.raw[i])
+		}
+	}
+	out.WriteString(t.literals[len(t.literals)-1])
+	return out.String()
+}
 
 // HasUnresolvedPlaceholders reports whether a substituted string still contains
 // a placeholder — meaning the fields on hand did not name it.
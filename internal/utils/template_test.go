@@ -80,3 +80,19 @@ func TestEffectiveFieldsDoesNotMutateTheCallersMap(t *testing.T) {
 
 	assert.Equal(t, map[string]string{"name": "Mario"}, original)
 }
+
+func TestTemplateRendersEachRecipientFromOneParse(t *testing.T) {
+	tpl := utils.ParseTemplate("Hi {{ name }}, {{name}} — {{ missing }}")
+
+	assert.Equal(t, "Hi Mario, Mario — {{ missing }}", tpl.Render(map[string]string{"name": "Mario"}),
+		"a placeholder with no field stays as written")
+	assert.Equal(t, "Hi Luigi, Luigi — {{ missing }}", tpl.Render(map[string]string{"name": "Luigi"}))
+	assert.Equal(t, "no placeholders", utils.ParseTemplate("no placeholders").Render(nil))
+}
+
+func TestTemplateDoesNotReadValuesForPlaceholders(t *testing.T) {
+	tpl := utils.ParseTemplate("{{ a }} {{ b }}")
+
+	assert.Equal(t, "{{ b }} B", tpl.Render(map[string]string{"a": "{{ b }}", "b": "B"}),
+		"a value is put in as it is, whatever order the fields come in")
+}
@@ -0,0 +1,25 @@
+package values
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrInvalidIPPool is a name no IP pool can have.
+var ErrInvalidIPPool = errors.New("invalid IP pool name")
+
+// ipPoolName is what an IP pool can be called: a key of `sender.ip_pools`,
+// which the configuration loader lower-cases and which cannot hold a dot.
+var ipPoolName = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+// ValidateIPPool checks that name could name an IP pool; empty names none,
+// the sender's default. Whether a sender has such a pool is the sender's
+// configuration, which neither the database nor the API can see, so this is
+// as far as a name given to either can be checked.
+func ValidateIPPool(name string) error {
+	if name != "" && !ipPoolName.MatchString(name) {
+		return fmt.Errorf("%w %q: up to 64 lower-case letters, digits, '-' or '_'", ErrInvalidIPPool, name)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package values_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kannon-email/kannon/internal/values"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIPPool(t *testing.T) {
+	for _, name := range []string{"", "transactional", "bulk-eu_2"} {
+		assert.NoError(t, values.ValidateIPPool(name), name)
+	}
+	// Upper case and dots cannot be keys of `sender.ip_pools`: the loader
+	// lower-cases the one and nests on the other.
+	for _, name := range []string{"Bulk", "pools.bulk", "bulk pool", strings.Repeat("a", 65)} {
+		assert.ErrorIs(t, values.ValidateIPPool(name), values.ErrInvalidIPPool, name)
+	}
+}
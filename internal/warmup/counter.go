@@ -0,0 +1,127 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Counter counts the messages sent in each Window, across every replica.
+type Counter interface {
+	// Take counts one message against each of windows, unless one of them is
+	// spent already: then it counts none and returns that one.
+	Take(ctx context.Context, windows []Window) (spent *Window, err error)
+	// Count returns how many messages key has counted, zero for a key that
+	// never counted one.
+	Count(ctx context.Context, key string) (int, error)
+}
+
+const (
+	// Bucket holds the counters.
+	Bucket = "kannon-warmup"
+
+	// counterTTL is how long a counter is kept after its last message. It
+	// has to outlast the day it counts, which is the longest window there
+	// is, and no longer: a counter is never read once its window is over.
+	counterTTL = 25 * time.Hour
+
+	// counterRetries is how many times an update lost to another replica is
+	// tried again before Take gives up.
+	counterRetries = 5
+)
+
+// OpenNATS opens the counters' key/value bucket. It is file-backed, unlike
+// the throttle's: a day's count lost to a restart would hand the address its
+// whole allowance a second time on the same day.
+func OpenNATS(ctx context.Context, js jetstream.JetStream) (Counter, error) {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      Bucket,
+		Description: "Messages sent by warming IP addresses, by day and hour",
+		TTL:         counterTTL,
+		Storage:     jetstream.FileStorage,
+		Replicas:    1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot open key/value bucket %s: %w", Bucket, err)
+	}
+	return &kvCounter{kv: kv}, nil
+}
+
+// kvCounter keeps each window's count under its key, advanced by
+// compare-and-swap on the key's revision.
+type kvCounter struct {
+	kv jetstream.KeyValue
+}
+
+// Take reads every window before it counts against any, so a message refused
+// by its destination's hourly window does not cost the address's day a
+// message it never sent. Two replicas taking the last message of a window at
+// once can still get a window counted for a message another refused: the
+// error is a message too few sent, never one too many.
+func (c *kvCounter) Take(ctx context.Context, windows []Window) (*Window, error) {
+	for i := range windows {
+		n, err := c.Count(ctx, windows[i].Key)
+		if err != nil {
+			return nil, err
+		}
+		if n >= windows[i].Limit {
+			return &windows[i], nil
+		}
+	}
+	for i := range windows {
+		counted, err := c.countBelow(ctx, windows[i].Key, windows[i].Limit)
+		if err != nil {
+			return nil, err
+		}
+		if !counted {
+			return &windows[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *kvCounter) Count(ctx context.Context, key string) (int, error) {
+	entry, err := c.kv.Get(ctx, key)
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot read %s: %w", key, err)
+	}
+	n, _ := strconv.Atoi(string(entry.Value()))
+	return n, nil
+}
+
+// countBelow counts one message under key unless it has counted limit
+// already.
+func (c *kvCounter) countBelow(ctx context.Context, key string, limit int) (bool, error) {
+	for range counterRetries {
+		entry, err := c.kv.Get(ctx, key)
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			if _, err := c.kv.Create(ctx, key, []byte("1")); err == nil {
+				return true, nil
+			} else if !errors.Is(err, jetstream.ErrKeyExists) {
+				return false, fmt.Errorf("cannot count %s: %w", key, err)
+			}
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("cannot read %s: %w", key, err)
+		}
+
+		n, _ := strconv.Atoi(string(entry.Value()))
+		if n >= limit {
+			return false, nil
+		}
+		if _, err := c.kv.Update(ctx, key, []byte(strconv.Itoa(n+1)), entry.Revision()); err == nil {
+			return true, nil
+		} else if !errors.Is(err, jetstream.ErrKeyExists) {
+			return false, fmt.Errorf("cannot count %s: %w", key, err)
+		}
+	}
+	return false, fmt.Errorf("cannot count %s: lost %d updates in a row", key, counterRetries)
+}
@@ -0,0 +1,131 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/authz"
+)
+
+// ErrNoCounter is a warm-up whose counts cannot be read, because the process
+// reporting it could not reach the bucket they are kept in.
+var ErrNoCounter = errors.New("warm-up counters are not available")
+
+// Progress is how far along its plan one address is.
+type Progress struct {
+	Address string
+	Plan    string
+	Start   time.Time
+	// Day is the day of the plan it is on, from 1; 0 before the plan starts.
+	Day int
+	// Days is how many days the plan lasts.
+	Days int
+	// Warm is set once the plan is over.
+	Warm bool
+	// Today and Hour are what the address has sent of its allowance, zero on
+	// a day or hour its plan does not cap.
+	Today Usage
+	Hour  Usage
+	// Providers are its provider ramps still running.
+	Providers []ProviderProgress
+}
+
+// ProviderProgress is an address's progress on one provider ramp.
+type ProviderProgress struct {
+	Destination string
+	Today       Usage
+	Hour        Usage
+}
+
+// Usage is what a window has counted of what it allows. A Limit of zero is no
+// cap.
+type Usage struct {
+	Sent  int
+	Limit int
+}
+
+// Service reports warm-up progress on behalf of the Admin API, which is why it
+// is guarded: the addresses, and what they send, are the installation's and
+// not any Domain's.
+type Service struct {
+	schedule *Schedule
+	counter  Counter
+	now      func() time.Time
+}
+
+// NewService reports the progress of schedule as counter has counted it. A
+// nil schedule reports nothing warming; a nil counter is ErrNoCounter for
+// any address that is.
+func NewService(schedule *Schedule, counter Counter) *Service {
+	return &Service{schedule: schedule, counter: counter, now: time.Now}
+}
+
+// Progress reports every warming address, in the order they are configured,
+// addresses whose plan is over included until they are taken out of it.
+func (s *Service) Progress(ctx context.Context) ([]Progress, error) {
+	return authz.Guard(ctx, authz.Read, authz.SendingAddresses(), func() ([]Progress, error) {
+		if !s.schedule.Enabled() {
+			return nil, nil
+		}
+		if s.counter == nil {
+			return nil, ErrNoCounter
+		}
+		now := s.now().UTC()
+		res := make([]Progress, 0, len(s.schedule.order))
+		for _, a := range s.schedule.order {
+			p, err := s.progress(ctx, a, now)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, p)
+		}
+		return res, nil
+	})
+}
+
+func (s *Service) progress(ctx context.Context, a *address, now time.Time) (Progress, error) {
+	day := a.day(now)
+	p := Progress{Address: a.ip, Plan: a.plan, Start: a.start, Days: a.days()}
+	switch {
+	case day < 0:
+		return p, nil
+	case day >= p.Days:
+		p.Day, p.Warm = p.Days, true
+		return p, nil
+	}
+	p.Day = day + 1
+
+	var err error
+	if p.Today, p.Hour, err = s.usage(ctx, a.ramp, a.ip, "", day, now); err != nil {
+		return Progress{}, err
+	}
+	for _, pr := range a.providers {
+		if day >= len(pr.daily) {
+			continue
+		}
+		pp := ProviderProgress{Destination: pr.destination}
+		if pp.Today, pp.Hour, err = s.usage(ctx, pr.ramp, a.ip, pr.destination, day, now); err != nil {
+			return Progress{}, err
+		}
+		p.Providers = append(p.Providers, pp)
+	}
+	return p, nil
+}
+
+// usage reads today's and this hour's counts of r.
+func (s *Service) usage(ctx context.Context, r ramp, ip, destination string, day int, now time.Time) (today, hour Usage, err error) {
+	if day < len(r.daily) {
+		today.Limit = r.daily[day]
+		if today.Sent, err = s.counter.Count(ctx, dayKey(ip, destination, now)); err != nil {
+			return Usage{}, Usage{}, err
+		}
+	}
+	if day < len(r.hourly) {
+		hour.Limit = r.hourly[day]
+		if hour.Sent, err = s.counter.Count(ctx, hourKey(ip, destination, now)); err != nil {
+			return Usage{}, Usage{}, err
+		}
+	}
+	return today, hour, nil
+}
@@ -0,0 +1,284 @@
+// Package warmup holds new outbound IP addresses to a volume ramp while
+// receivers learn to trust them.
+//
+// A receiver meeting an address it has no history for treats a sudden flood
+// from it as a spammer's, so an address is brought into service by sending a
+// little on its first day and more on each day after: its warm-up plan. The
+// SMTPSender enforces the plan (pkg/smtpsender) and the Admin API reports how
+// far along it is (Service); the counts both read are kept in a NATS key/value
+// bucket, so a plan holds across every sender replica together.
+package warmup
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/kvstate"
+)
+
+// Config is the `sender.warmup` section: the plans, and the addresses each is
+// attached to.
+type Config struct {
+	// WhenSpent is what becomes of a message whose address has sent all it
+	// may for now: "reroute", the default, sends it from another address of
+	// its IP pool that may still send; "delay" holds it back until the
+	// address may send again. Either way, a message no address can take is
+	// held back until the first of them may.
+	WhenSpent string `mapstructure:"when_spent"`
+	// Plans are the ramps, by name.
+	Plans map[string]PlanConfig `mapstructure:"plans"`
+	// Addresses are the addresses being warmed up. An address none of them
+	// names is warm already and sends as much as it is given.
+	Addresses []AddressConfig `mapstructure:"addresses"`
+}
+
+// What becomes of a message whose address has spent its allowance.
+const (
+	WhenSpentReroute = "reroute"
+	WhenSpentDelay   = "delay"
+)
+
+// PlanConfig is one warm-up plan. Its ramps are indexed by day of the plan,
+// the first entry being the first day; past the last day the address is warm
+// and the plan no longer applies.
+type PlanConfig struct {
+	// Daily is how many messages the address may send on each day.
+	Daily []int `mapstructure:"daily"`
+	// Hourly, when set, is how many of them it may send in any one hour of
+	// that day, so a day's allowance is not spent in its first minutes. It
+	// has an entry for each day of Daily.
+	Hourly []int `mapstructure:"hourly"`
+	// Providers are ramps of their own for the mail to one destination, on
+	// top of the address's: a provider that is stricter than the rest can be
+	// brought along more slowly.
+	Providers []ProviderRampConfig `mapstructure:"providers"`
+}
+
+// ProviderRampConfig is a plan's ramp for one destination.
+type ProviderRampConfig struct {
+	// Destination is a destination as `sender.throttle` knows them: the name
+	// of one of its groups — every domain whose mail Google receives, say —
+	// or a recipient domain no group claims.
+	Destination string `mapstructure:"destination"`
+	Daily       []int  `mapstructure:"daily"`
+	Hourly      []int  `mapstructure:"hourly"`
+}
+
+// AddressConfig attaches a plan to an address.
+type AddressConfig struct {
+	// Address is the IP address, one of an IP pool's.
+	Address string `mapstructure:"address"`
+	// Plan is the name of the plan it follows.
+	Plan string `mapstructure:"plan"`
+	// Start is the plan's first day, as YYYY-MM-DD. Days are UTC days. Before
+	// it the address sends nothing.
+	Start string `mapstructure:"start"`
+}
+
+// Schedule is a validated Config: what each warming address may send, when.
+// A nil Schedule warms nothing.
+type Schedule struct {
+	reroute       bool
+	byDestination bool
+	addresses     map[string]*address
+	// order is the addresses as configured, which is how they are reported.
+	order []*address
+}
+
+type address struct {
+	ip        string
+	plan      string
+	start     time.Time
+	ramp      ramp
+	providers []providerRamp
+}
+
+type ramp struct {
+	daily  []int
+	hourly []int
+}
+
+type providerRamp struct {
+	destination string
+	ramp
+}
+
+// New validates cfg. A plan with no days, an address that does not parse or a
+// plan nobody defined is an error here, at boot, rather than an address that
+// sends without the ramp it was meant to follow.
+func New(cfg Config) (*Schedule, error) {
+	s := &Schedule{addresses: make(map[string]*address, len(cfg.Addresses))}
+	switch cfg.WhenSpent {
+	case "", WhenSpentReroute:
+		s.reroute = true
+	case WhenSpentDelay:
+	default:
+		return nil, fmt.Errorf("when_spent %q is neither %q nor %q", cfg.WhenSpent, WhenSpentReroute, WhenSpentDelay)
+	}
+
+	for i, ac := range cfg.Addresses {
+		ip := net.ParseIP(ac.Address)
+		if ip == nil {
+			return nil, fmt.Errorf("address %d: %q is not an IP address", i, ac.Address)
+		}
+		if _, ok := s.addresses[ip.String()]; ok {
+			return nil, fmt.Errorf("address %s is listed twice", ip)
+		}
+		pc, ok := cfg.Plans[ac.Plan]
+		if !ok {
+			return nil, fmt.Errorf("address %s: plan %q is not defined", ip, ac.Plan)
+		}
+		start, err := time.Parse(time.DateOnly, ac.Start)
+		if err != nil {
+			return nil, fmt.Errorf("address %s: start %q is not a YYYY-MM-DD date", ip, ac.Start)
+		}
+		a, err := newAddress(ip.String(), ac.Plan, start, pc)
+		if err != nil {
+			return nil, err
+		}
+		s.byDestination = s.byDestination || len(a.providers) > 0
+		s.addresses[a.ip] = a
+		s.order = append(s.order, a)
+	}
+	return s, nil
+}
+
+func newAddress(ip, plan string, start time.Time, pc PlanConfig) (*address, error) {
+	r, err := newRamp(pc.Daily, pc.Hourly)
+	if err != nil {
+		return nil, fmt.Errorf("plan %q: %w", plan, err)
+	}
+	a := &address{ip: ip, plan: plan, start: start, ramp: r}
+	for _, prc := range pc.Providers {
+		if prc.Destination == "" {
+			return nil, fmt.Errorf("plan %q: a provider ramp names no destination", plan)
+		}
+		r, err := newRamp(prc.Daily, prc.Hourly)
+		if err != nil {
+			return nil, fmt.Errorf("plan %q, provider %q: %w", plan, prc.Destination, err)
+		}
+		a.providers = append(a.providers, providerRamp{destination: strings.ToLower(prc.Destination), ramp: r})
+	}
+	return a, nil
+}
+
+func newRamp(daily, hourly []int) (ramp, error) {
+	if len(daily) == 0 {
+		return ramp{}, fmt.Errorf("no daily ramp")
+	}
+	if len(hourly) > 0 && len(hourly) != len(daily) {
+		return ramp{}, fmt.Errorf("the hourly ramp has %d days and the daily one %d", len(hourly), len(daily))
+	}
+	if slices.ContainsFunc(daily, notPositive) || slices.ContainsFunc(hourly, notPositive) {
+		return ramp{}, fmt.Errorf("a day's allowance must be at least 1")
+	}
+	return ramp{daily: daily, hourly: hourly}, nil
+}
+
+func notPositive(n int) bool { return n <= 0 }
+
+// Enabled reports whether any address is warming at all.
+func (s *Schedule) Enabled() bool {
+	return s != nil && len(s.order) > 0
+}
+
+// Reroute reports whether a message whose address has spent its allowance is
+// tried from another address of its pool (WhenSpent).
+func (s *Schedule) Reroute() bool {
+	return s != nil && s.reroute
+}
+
+// ByDestination reports whether any plan has a provider ramp, and so whether a
+// message's destination has to be worked out to find the windows it counts
+// against.
+func (s *Schedule) ByDestination() bool {
+	return s != nil && s.byDestination
+}
+
+// Window is one counter a message counts against: the key it is counted
+// under, how many messages the window allows, and when it gives way to the
+// next.
+type Window struct {
+	Key   string
+	Limit int
+	Ends  time.Time
+}
+
+// Windows returns the windows a message sent from ip to destination at now
+// counts against, none for an address that is not warming — one no plan is
+// attached to, or one past the end of its plan.
+//
+// An address whose plan has not started yet has a single window that allows
+// nothing and ends when the plan starts.
+func (s *Schedule) Windows(ip, destination string, now time.Time) []Window {
+	if s == nil {
+		return nil
+	}
+	a, ok := s.addresses[ip]
+	if !ok {
+		return nil
+	}
+	now = now.UTC()
+	day := a.day(now)
+	if day < 0 {
+		return []Window{{Key: dayKey(a.ip, "", now), Limit: 0, Ends: a.start}}
+	}
+
+	ws := a.ramp.windows(nil, a.ip, "", day, now)
+	destination = strings.ToLower(destination)
+	for _, p := range a.providers {
+		if p.destination == destination {
+			ws = p.windows(ws, a.ip, p.destination, day, now)
+		}
+	}
+	return ws
+}
+
+// day is which day of its plan now is for a, from zero; negative before the
+// plan starts.
+func (a *address) day(now time.Time) int {
+	return int(now.Truncate(24*time.Hour).Sub(a.start) / (24 * time.Hour))
+}
+
+// days is how many days a's plan lasts, its longest ramp's.
+func (a *address) days() int {
+	n := len(a.ramp.daily)
+	for _, p := range a.providers {
+		n = max(n, len(p.daily))
+	}
+	return n
+}
+
+// windows appends the windows of day of r to ws: none once r is over.
+func (r ramp) windows(ws []Window, ip, destination string, day int, now time.Time) []Window {
+	if day >= len(r.daily) {
+		return ws
+	}
+	ws = append(ws, Window{Key: dayKey(ip, destination, now), Limit: r.daily[day], Ends: now.Truncate(24 * time.Hour).Add(24 * time.Hour)})
+	if day < len(r.hourly) {
+		ws = append(ws, Window{Key: hourKey(ip, destination, now), Limit: r.hourly[day], Ends: now.Truncate(time.Hour).Add(time.Hour)})
+	}
+	return ws
+}
+
+// dayKey and hourKey name the counters of the day and hour now falls in, for
+// an address and, when destination is set, its mail to that destination.
+func dayKey(ip, destination string, now time.Time) string {
+	return counterKey("day", ip, destination, now.Format("20060102"))
+}
+
+func hourKey(ip, destination string, now time.Time) string {
+	return counterKey("hour", ip, destination, now.Format("2006010215"))
+}
+
+// counterKey names a counter by kvstate.Key, so that two addresses or
+// destinations never share one, however they are spelled.
+func counterKey(kind, ip, destination, period string) string {
+	if destination == "" {
+		return kvstate.Key(kind, ip, period)
+	}
+	return kvstate.Key(kind, ip, destination, period)
+}
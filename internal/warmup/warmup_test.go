@@ -0,0 +1,228 @@
+package warmup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/authz"
+	"github.com/kannon-email/kannon/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memCounter is Counter in a map.
+type memCounter map[string]int
+
+func (c memCounter) Take(_ context.Context, windows []Window) (*Window, error) {
+	for i := range windows {
+		if c[windows[i].Key] >= windows[i].Limit {
+			return &windows[i], nil
+		}
+	}
+	for _, w := range windows {
+		c[w.Key]++
+	}
+	return nil, nil
+}
+
+func (c memCounter) Count(_ context.Context, key string) (int, error) {
+	return c[key], nil
+}
+
+func newSchedule(t *testing.T) *Schedule {
+	t.Helper()
+	s, err := New(Config{
+		Plans: map[string]PlanConfig{
+			"standard": {
+				Daily:  []int{50, 100, 500},
+				Hourly: []int{10, 20, 100},
+				Providers: []ProviderRampConfig{
+					{Destination: "Google", Daily: []int{5, 10, 50, 100}},
+				},
+			},
+		},
+		Addresses: []AddressConfig{{Address: "192.0.2.10", Plan: "standard", Start: "2026-10-01"}},
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestNewRefusesWhatCannotBeFollowed(t *testing.T) {
+	plan := map[string]PlanConfig{"standard": {Daily: []int{50, 100}}}
+	cases := []struct {
+		name string
+		cfg  Config
+	}{
+		{"unknown when_spent", Config{WhenSpent: "drop"}},
+		{"not an address", Config{Plans: plan, Addresses: []AddressConfig{{Address: "mail.example.com", Plan: "standard", Start: "2026-10-01"}}}},
+		{"an address twice", Config{Plans: plan, Addresses: []AddressConfig{
+			{Address: "192.0.2.10", Plan: "standard", Start: "2026-10-01"},
+			{Address: "192.0.2.10", Plan: "standard", Start: "2026-10-02"},
+		}}},
+		{"undefined plan", Config{Plans: plan, Addresses: []AddressConfig{{Address: "192.0.2.10", Plan: "slow", Start: "2026-10-01"}}}},
+		{"no start", Config{Plans: plan, Addresses: []AddressConfig{{Address: "192.0.2.10", Plan: "standard"}}}},
+		{"no days", Config{Plans: map[string]PlanConfig{"standard": {}}, Addresses: []AddressConfig{{Address: "192.0.2.10", Plan: "standard", Start: "2026-10-01"}}}},
+		{"hourly of another length", Config{Plans: map[string]PlanConfig{"standard": {Daily: []int{50, 100}, Hourly: []int{10}}}, Addresses: []AddressConfig{{Address: "192.0.2.10", Plan: "standard", Start: "2026-10-01"}}}},
+		{"a day of nothing", Config{Plans: map[string]PlanConfig{"standard": {Daily: []int{50, 0}}}, Addresses: []AddressConfig{{Address: "192.0.2.10", Plan: "standard", Start: "2026-10-01"}}}},
+		{"a provider without destination", Config{Plans: map[string]PlanConfig{"standard": {Daily: []int{50}, Providers: []ProviderRampConfig{{Daily: []int{5}}}}}, Addresses: []AddressConfig{{Address: "192.0.2.10", Plan: "standard", Start: "2026-10-01"}}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := New(c.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWindows(t *testing.T) {
+	s := newSchedule(t)
+	assert.True(t, s.Reroute(), "rerouting is the default")
+	assert.True(t, s.ByDestination())
+
+	t.Run("an address no plan is attached to", func(t *testing.T) {
+		assert.Empty(t, s.Windows("192.0.2.11", "", at("2026-10-01T10:30:00Z")))
+	})
+
+	t.Run("the first day", func(t *testing.T) {
+		ws := s.Windows("192.0.2.10", "example.com", at("2026-10-01T10:30:00Z"))
+		assert.Equal(t, []Window{
+			{Key: "day_192.0.2.10_20261001", Limit: 50, Ends: at("2026-10-02T00:00:00Z")},
+			{Key: "hour_192.0.2.10_2026100110", Limit: 10, Ends: at("2026-10-01T11:00:00Z")},
+		}, ws)
+	})
+
+	t.Run("a provider's mail counts against its ramp too", func(t *testing.T) {
+		ws := s.Windows("192.0.2.10", "google", at("2026-10-02T10:30:00Z"))
+		require.Len(t, ws, 3)
+		assert.Equal(t, Window{Key: "day_192.0.2.10_google_20261002", Limit: 10, Ends: at("2026-10-03T00:00:00Z")}, ws[2])
+	})
+
+	t.Run("before the plan starts nothing is sent", func(t *testing.T) {
+		ws := s.Windows("192.0.2.10", "", at("2026-09-30T23:00:00Z"))
+		require.Len(t, ws, 1)
+		assert.Zero(t, ws[0].Limit)
+		assert.Equal(t, at("2026-10-01T00:00:00Z"), ws[0].Ends)
+	})
+
+	t.Run("the provider ramp outlasts the address's", func(t *testing.T) {
+		assert.Empty(t, s.Windows("192.0.2.10", "example.com", at("2026-10-04T10:00:00Z")))
+		assert.Len(t, s.Windows("192.0.2.10", "google", at("2026-10-04T10:00:00Z")), 1)
+		assert.Empty(t, s.Windows("192.0.2.10", "google", at("2026-10-05T10:00:00Z")))
+	})
+
+	var none *Schedule
+	assert.False(t, none.Enabled())
+	assert.Empty(t, none.Windows("192.0.2.10", "", at("2026-10-01T10:30:00Z")))
+}
+
+func TestProgress(t *testing.T) {
+	s := newSchedule(t)
+	counter := memCounter{}
+	service := NewService(s, counter)
+	service.now = func() time.Time { return at("2026-10-02T10:30:00Z") }
+
+	for range 3 {
+		spent, err := counter.Take(t.Context(), s.Windows("192.0.2.10", "google", service.now()))
+		require.NoError(t, err)
+		require.Nil(t, spent)
+	}
+
+	progress, err := service.Progress(tests.AdminContext(t.Context()))
+	require.NoError(t, err)
+	assert.Equal(t, []Progress{{
+		Address: "192.0.2.10",
+		Plan:    "standard",
+		Start:   at("2026-10-01T00:00:00Z"),
+		Day:     2,
+		Days:    4,
+		Today:   Usage{Sent: 3, Limit: 100},
+		Hour:    Usage{Sent: 3, Limit: 20},
+		Providers: []ProviderProgress{
+			{Destination: "google", Today: Usage{Sent: 3, Limit: 10}},
+		},
+	}}, progress)
+
+	service.now = func() time.Time { return at("2026-10-09T10:30:00Z") }
+	progress, err = service.Progress(tests.AdminContext(t.Context()))
+	require.NoError(t, err)
+	require.Len(t, progress, 1)
+	assert.True(t, progress[0].Warm)
+	assert.Equal(t, 4, progress[0].Day)
+}
+
+func TestProgressIsForTheRootAlone(t *testing.T) {
+	service := NewService(newSchedule(t), memCounter{})
+
+	_, err := service.Progress(t.Context())
+	assert.ErrorIs(t, err, authz.ErrNoPrincipal)
+
+	everyDomain := authz.MustNewPrincipal("every-domain-admin", authz.MustNewGrant(authz.RoleAdmin, authz.AllDomainsAnchor()))
+	_, err = service.Progress(authz.NewContext(t.Context(), everyDomain))
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+
+	_, err = NewService(newSchedule(t), nil).Progress(tests.AdminContext(t.Context()))
+	assert.ErrorIs(t, err, ErrNoCounter)
+
+	progress, err := NewService(nil, nil).Progress(tests.AdminContext(t.Context()))
+	require.NoError(t, err)
+	assert.Empty(t, progress)
+}
+
+// A message is counted against every window or none: the one its hour
+// refuses costs its day nothing, and the day's count is read back as taken.
+func TestNATSCounterCountsARefusedMessageAgainstNoWindow(t *testing.T) {
+	ctx := t.Context()
+	c, err := OpenNATS(ctx, tests.NatsJetStream(t))
+	require.NoError(t, err)
+
+	now := at("2026-10-01T10:00:00Z")
+	day := Window{Key: dayKey("192.0.2.10", "", now), Limit: 3}
+	hour := Window{Key: hourKey("192.0.2.10", "", now), Limit: 2}
+	for range 2 {
+		spent, err := c.Take(ctx, []Window{day, hour})
+		require.NoError(t, err)
+		require.Nil(t, spent)
+	}
+	spent, err := c.Take(ctx, []Window{day, hour})
+	require.NoError(t, err)
+	require.NotNil(t, spent)
+	assert.Equal(t, hour.Key, spent.Key)
+
+	n, err := c.Count(ctx, day.Key)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n, "the day was not counted for the message its hour refused")
+
+	n, err = c.Count(ctx, dayKey("192.0.2.11", "", now))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+// Every address, and every destination it ramps to, counts on its own:
+// internationalized domains that share no character a key may hold, and an
+// address's own day beside its day towards one destination, never share a
+// counter.
+func TestCounterKeysKeepDestinationsApart(t *testing.T) {
+	now := at("2026-10-01T10:00:00Z")
+	keys := []string{
+		dayKey("192.0.2.10", "", now),
+		dayKey("192.0.2.10", "例子.中国", now),
+		dayKey("192.0.2.10", "中国.中国", now),
+		dayKey("2001:db8::1", "", now),
+		dayKey("2001:db8::2", "", now),
+		hourKey("192.0.2.10", "", now),
+	}
+	seen := map[string]bool{}
+	for _, k := range keys {
+		assert.False(t, seen[k], "%s is shared", k)
+		seen[k] = true
+	}
+}
@@ -7,10 +7,17 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kannon-email/kannon/internal/apikeys"
 	"github.com/kannon-email/kannon/internal/authzconnect"
+	"github.com/kannon-email/kannon/internal/cooldown"
 	sqlc "github.com/kannon-email/kannon/internal/db"
+	"github.com/kannon-email/kannon/internal/dkim"
 	"github.com/kannon-email/kannon/internal/domains"
+	"github.com/kannon-email/kannon/internal/feedback"
+	"github.com/kannon-email/kannon/internal/linkparams"
+	"github.com/kannon-email/kannon/internal/smime"
 	"github.com/kannon-email/kannon/internal/templates"
 	"github.com/kannon-email/kannon/internal/trackingpb"
+	"github.com/kannon-email/kannon/internal/values"
+	"github.com/kannon-email/kannon/internal/warmup"
 
 	"connectrpc.com/connect"
 	pb "github.com/kannon-email/kannon/proto/kannon/admin/apiv1"
@@ -40,7 +47,7 @@ func (a *adminAPIConnectAdapter) GetDomain(ctx context.Context, req *connect.Req
 func (a *adminAPIConnectAdapter) CreateDomain(ctx context.Context, req *connect.Request[pb.CreateDomainRequest]) (*connect.Response[pb.Domain], error) {
 	resp, err := a.impl.CreateDomain(ctx, req.Msg)
 	if err != nil {
-		return nil, serviceError(err)
+		return nil, createDomainError(err)
 	}
 	return connect.NewResponse(resp), nil
 }
@@ -53,6 +60,78 @@ func (a *adminAPIConnectAdapter) SetTrackingPolicy(ctx context.Context, req *con
 	return connect.NewResponse(resp), nil
 }
 
+func (a *adminAPIConnectAdapter) SetReturnPathDomain(ctx context.Context, req *connect.Request[pb.SetReturnPathDomainReq]) (*connect.Response[pb.SetReturnPathDomainRes], error) {
+	resp, err := a.impl.SetReturnPathDomain(ctx, req.Msg)
+	if err != nil {
+		return nil, returnPathError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (a *adminAPIConnectAdapter) SetFeedbackIdentity(ctx context.Context, req *connect.Request[pb.SetFeedbackIdentityReq]) (*connect.Response[pb.SetFeedbackIdentityRes], error) {
+	resp, err := a.impl.SetFeedbackIdentity(ctx, req.Msg)
+	if err != nil {
+		return nil, feedbackIdentityError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (a *adminAPIConnectAdapter) SetSMIMECertificate(ctx context.Context, req *connect.Request[pb.SetSMIMECertificateReq]) (*connect.Response[pb.SetSMIMECertificateRes], error) {
+	resp, err := a.impl.SetSMIMECertificate(ctx, req.Msg)
+	if err != nil {
+		return nil, smimeCertificateError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (a *adminAPIConnectAdapter) SetLinkDecoration(ctx context.Context, req *connect.Request[pb.SetLinkDecorationReq]) (*connect.Response[pb.SetLinkDecorationRes], error) {
+	resp, err := a.impl.SetLinkDecoration(ctx, req.Msg)
+	if err != nil {
+		return nil, linkDecorationError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (a *adminAPIConnectAdapter) SetRequireTLS(ctx context.Context, req *connect.Request[pb.SetRequireTLSReq]) (*connect.Response[pb.SetRequireTLSRes], error) {
+	resp, err := a.impl.SetRequireTLS(ctx, req.Msg)
+	if err != nil {
+		return nil, requireTLSError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (a *adminAPIConnectAdapter) SetIPPool(ctx context.Context, req *connect.Request[pb.SetIPPoolReq]) (*connect.Response[pb.SetIPPoolRes], error) {
+	resp, err := a.impl.SetIPPool(ctx, req.Msg)
+	if err != nil {
+		return nil, ipPoolError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (a *adminAPIConnectAdapter) RotateDKIMKey(ctx context.Context, req *connect.Request[pb.RotateDKIMKeyReq]) (*connect.Response[pb.RotateDKIMKeyRes], error) {
+	resp, err := a.impl.RotateDKIMKey(ctx, req.Msg)
+	if err != nil {
+		return nil, dkimKeyError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (a *adminAPIConnectAdapter) ActivateDKIMKey(ctx context.Context, req *connect.Request[pb.ActivateDKIMKeyReq]) (*connect.Response[pb.ActivateDKIMKeyRes], error) {
+	resp, err := a.impl.ActivateDKIMKey(ctx, req.Msg)
+	if err != nil {
+		return nil, dkimKeyError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (a *adminAPIConnectAdapter) RetireDKIMKey(ctx context.Context, req *connect.Request[pb.RetireDKIMKeyReq]) (*connect.Response[pb.RetireDKIMKeyRes], error) {
+	resp, err := a.impl.RetireDKIMKey(ctx, req.Msg)
+	if err != nil {
+		return nil, dkimKeyError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
 // serviceError maps what a guarded service returns onto a Connect code. Every method of this
 // adapter used to answer CodeInternal for everything: a refusal reported as an internal fault
 // tells the caller to retry what will never succeed, so it becomes CodePermissionDenied.
@@ -74,6 +153,116 @@ func trackingPolicyError(err error) *connect.Error {
 	}
 }
 
+// returnPathError maps the ways a return-path domain can be refused onto Connect codes: a name
+// that is not one, or not beneath the Domain, is a bad argument; an unknown Domain is not found.
+func returnPathError(err error) *connect.Error {
+	switch {
+	case errors.Is(err, errInvalidReturnPathDomain), errors.Is(err, domains.ErrReturnPathNotSubdomain):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, domains.ErrDomainNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	default:
+		return serviceError(err)
+	}
+}
+
+// feedbackIdentityError maps the ways a Feedback Identity can be refused onto Connect codes: a
+// character its header cannot carry is a bad argument, an unknown Domain is not found.
+func feedbackIdentityError(err error) *connect.Error {
+	switch {
+	case errors.Is(err, feedback.ErrInvalidIdentity):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, domains.ErrDomainNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	default:
+		return serviceError(err)
+	}
+}
+
+// linkDecorationError maps the ways a Link Decoration can be refused onto Connect codes: a
+// parameter or host that cannot be applied as stated is a bad argument, an unknown Domain is not
+// found.
+func linkDecorationError(err error) *connect.Error {
+	switch {
+	case errors.Is(err, linkparams.ErrInvalid):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, domains.ErrDomainNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	default:
+		return serviceError(err)
+	}
+}
+
+// requireTLSError maps an unknown Domain onto CodeNotFound; there is nothing else about a
+// yes-or-no setting to refuse.
+func requireTLSError(err error) *connect.Error {
+	if errors.Is(err, domains.ErrDomainNotFound) {
+		return connect.NewError(connect.CodeNotFound, err)
+	}
+	return serviceError(err)
+}
+
+// ipPoolError maps a name no IP pool can have onto CodeInvalidArgument and an unknown Domain onto
+// CodeNotFound. A pool the sender does not have is not refused: only the sender knows its pools.
+func ipPoolError(err error) *connect.Error {
+	switch {
+	case errors.Is(err, values.ErrInvalidIPPool):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, domains.ErrDomainNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	default:
+		return serviceError(err)
+	}
+}
+
+// smimeCertificateError maps the ways an S/MIME certificate can be refused onto Connect codes: a
+// pair that cannot sign — unparseable, expired, not for email, or a key that is not the
+// certificate's — is a bad argument, an unknown Domain is not found.
+func smimeCertificateError(err error) *connect.Error {
+	switch {
+	case errors.Is(err, smime.ErrInvalidCertificate):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, domains.ErrDomainNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	default:
+		return serviceError(err)
+	}
+}
+
+// createDomainError maps a first DKIM key CreateDomain cannot use onto CodeInvalidArgument: a key
+// that is malformed, too weak to verify anywhere, of an unsupported size or algorithm, or imported
+// without the selector it is published under. The message names which, so the operator can fix
+// the request rather than guess.
+func createDomainError(err error) *connect.Error {
+	switch {
+	case errors.Is(err, dkim.ErrMalformedKey), errors.Is(err, dkim.ErrWeakKey),
+		errors.Is(err, dkim.ErrUnsupportedKeySize), errors.Is(err, dkim.ErrUnsupportedAlgorithm),
+		errors.Is(err, dkim.ErrInvalidSelector), errors.Is(err, domains.ErrInitialDKIMKeyConflict):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	default:
+		return serviceError(err)
+	}
+}
+
+// dkimKeyError maps the ways a rotation step can be refused onto Connect codes. A key in the
+// wrong state, or a record not yet in DNS, is a failed precondition: the same call succeeds once
+// the operator has done the step that was missing, which is what the code tells a client.
+func dkimKeyError(err error) *connect.Error {
+	switch {
+	case errors.Is(err, dkim.ErrInvalidSelector), errors.Is(err, dkim.ErrUnsupportedAlgorithm):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, domains.ErrDomainNotFound), errors.Is(err, domains.ErrDKIMKeyNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, domains.ErrDKIMSelectorTaken):
+		return connect.NewError(connect.CodeAlreadyExists, err)
+	case errors.Is(err, domains.ErrDKIMKeyActive), errors.Is(err, domains.ErrDKIMKeyRetired),
+		errors.Is(err, domains.ErrDKIMRecordNotVerified):
+		return connect.NewError(connect.CodeFailedPrecondition, err)
+	default:
+		return serviceError(err)
+	}
+}
+
 func (a *adminAPIConnectAdapter) CreateTemplate(ctx context.Context, req *connect.Request[pb.CreateTemplateReq]) (*connect.Response[pb.CreateTemplateRes], error) {
 	resp, err := a.impl.CreateTemplate(ctx, req.Msg)
 	if err != nil {
@@ -146,18 +335,83 @@ func (a *adminAPIConnectAdapter) DeactivateAPIKey(ctx context.Context, req *conn
 	return connect.NewResponse(resp), nil
 }
 
-// CreateAdminAPIService assembles the Admin API over the three guarded services. Note what it
+func (a *adminAPIConnectAdapter) GetWarmupProgress(ctx context.Context, req *connect.Request[pb.GetWarmupProgressReq]) (*connect.Response[pb.GetWarmupProgressRes], error) {
+	resp, err := a.impl.GetWarmupProgress(ctx, req.Msg)
+	if err != nil {
+		return nil, warmupError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// warmupError maps counts that cannot be read onto CodeUnavailable: the API process reached no
+// NATS, which a retry may well find again.
+func warmupError(err error) *connect.Error {
+	if errors.Is(err, warmup.ErrNoCounter) {
+		return connect.NewError(connect.CodeUnavailable, err)
+	}
+	return serviceError(err)
+}
+
+func (a *adminAPIConnectAdapter) GetDestinationCooldowns(ctx context.Context, req *connect.Request[pb.GetDestinationCooldownsReq]) (*connect.Response[pb.GetDestinationCooldownsRes], error) {
+	resp, err := a.impl.GetDestinationCooldowns(ctx, req.Msg)
+	if err != nil {
+		return nil, cooldownsError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// cooldownsError maps cool-downs that cannot be read onto CodeUnavailable, as warmupError does.
+func cooldownsError(err error) *connect.Error {
+	if errors.Is(err, cooldown.ErrNoStore) {
+		return connect.NewError(connect.CodeUnavailable, err)
+	}
+	return serviceError(err)
+}
+
+// Option configures CreateAdminAPIService.
+type Option func(*adminAPIService)
+
+// WithReturnPathDNS supplies the hosts a return-path domain's records point at, so a Domain with
+// one is reported together with the MX and SPF records it needs. Without it those records are
+// left out: the Admin API does not know where the installation's SMTP server runs.
+func WithReturnPathDNS(dns domains.ReturnPathDNS) Option {
+	return func(s *adminAPIService) {
+		s.returnPathDNS = dns
+	}
+}
+
+// WithWarmup reports the warm-up progress of the service's addresses. Without it the Admin API
+// reports no address warming: it does not read the sender's configuration on its own.
+func WithWarmup(w *warmup.Service) Option {
+	return func(s *adminAPIService) {
+		s.warmup = w
+	}
+}
+
+// WithCooldowns reports the destinations the SMTPSender is holding mail back from. Without it the
+// Admin API reports none, as WithWarmup does for warm-up.
+func WithCooldowns(c *cooldown.Service) Option {
+	return func(s *adminAPIService) {
+		s.cooldowns = c
+	}
+}
+
+// CreateAdminAPIService assembles the Admin API over the guarded services. Note what it
 // does not do: it installs no Principal, so a caller holding this handler reaches operations that
 // refuse unless something put one in the context — in production, the interceptor in pkg/api.
-func CreateAdminAPIService(db *pgxpool.Pool) adminv1connect.ApiHandler {
+func CreateAdminAPIService(db *pgxpool.Pool, opts ...Option) adminv1connect.ApiHandler {
 	domainsRepo := sqlc.NewDomainsRepository(db)
 	templatesRepo := sqlc.NewTemplatesRepository(db)
 	apiKeysRepo := sqlc.NewAPIKeysRepository(db)
-	return &adminAPIConnectAdapter{
-		impl: &adminAPIService{
-			domains:   domains.NewService(domainsRepo),
-			templates: templates.NewService(templatesRepo),
-			apiKeys:   apikeys.NewService(apiKeysRepo),
-		},
+	impl := &adminAPIService{
+		domains:   domains.NewService(domainsRepo),
+		templates: templates.NewService(templatesRepo),
+		apiKeys:   apikeys.NewService(apiKeysRepo),
+		warmup:    warmup.NewService(nil, nil),
+		cooldowns: cooldown.NewService(false, nil),
+	}
+	for _, opt := range opts {
+		opt(impl)
 	}
+	return &adminAPIConnectAdapter{impl: impl}
 }
@@ -0,0 +1,28 @@
+package adminapi
+
+import (
+	"context"
+
+	pb "github.com/kannon-email/kannon/proto/kannon/admin/apiv1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *adminAPIService) GetDestinationCooldowns(ctx context.Context, _ *pb.GetDestinationCooldownsReq) (*pb.GetDestinationCooldownsRes, error) {
+	cooldowns, err := s.cooldowns.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.GetDestinationCooldownsRes{}
+	for _, c := range cooldowns {
+		res.Cooldowns = append(res.Cooldowns, &pb.DestinationCooldown{
+			Destination: c.Destination,
+			Level:       uint32(c.Level),
+			Since:       timestamppb.New(c.Since),
+			Until:       timestamppb.New(c.Until),
+			Code:        c.Reply.Code,
+			Mx:          c.Reply.MX,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,113 @@
+package adminapi
+
+import (
+	"context"
+
+	"github.com/kannon-email/kannon/internal/domains"
+	"github.com/kannon-email/kannon/internal/values"
+	pb "github.com/kannon-email/kannon/proto/kannon/admin/apiv1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *adminAPIService) RotateDKIMKey(ctx context.Context, in *pb.RotateDKIMKeyReq) (*pb.RotateDKIMKeyRes, error) {
+	name, err := values.Parse(in.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := s.domains.RotateDKIMKey(ctx, name, in.Selector, in.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	// A rotated key is pending by construction; no sibling can make it anything else.
+	return &pb.RotateDKIMKeyRes{Key: dkimKeyToPb(name, k, domains.DKIMKeyPending)}, nil
+}
+
+func (s *adminAPIService) ActivateDKIMKey(ctx context.Context, in *pb.ActivateDKIMKeyReq) (*pb.ActivateDKIMKeyRes, error) {
+	name, err := values.Parse(in.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.domains.ActivateDKIMKey(ctx, name, in.Selector, in.VerifyDns)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ActivateDKIMKeyRes{Domain: s.domainToPb(d)}, nil
+}
+
+func (s *adminAPIService) RetireDKIMKey(ctx context.Context, in *pb.RetireDKIMKeyReq) (*pb.RetireDKIMKeyRes, error) {
+	name, err := values.Parse(in.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.domains.RetireDKIMKey(ctx, name, in.Selector)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RetireDKIMKeyRes{Domain: s.domainToPb(d)}, nil
+}
+
+func dkimKeysToPb(d *domains.Domain) []*pb.DKIMKey {
+	keys := make([]*pb.DKIMKey, 0, len(d.DKIMKeys()))
+	for _, k := range d.DKIMKeys() {
+		keys = append(keys, dkimKeyToPb(d.Name(), k, d.DKIMKeyState(k)))
+	}
+	return keys
+}
+
+// initialDKIMKeyFromPb reads the dkim_key oneof of a CreateDomainRequest; unset is the zero
+// InitialDKIMKey, a default-sized generated key.
+func initialDKIMKeyFromPb(in *pb.CreateDomainRequest) domains.InitialDKIMKey {
+	switch k := in.DkimKey.(type) {
+	case *pb.CreateDomainRequest_DkimRsaBits:
+		return domains.InitialDKIMKey{RSABits: int(k.DkimRsaBits)}
+	case *pb.CreateDomainRequest_DkimImport:
+		return domains.InitialDKIMKey{
+			PrivateKeyPEM: k.DkimImport.GetPrivateKeyPem(),
+			Selector:      k.DkimImport.GetSelector(),
+		}
+	default:
+		return domains.InitialDKIMKey{}
+	}
+}
+
+// dkimKeyToPb renders one key with the record that publishes it. Like domainToPb, it carries
+// the public half only.
+func dkimKeyToPb(domain values.DomainName, k domains.DKIMKey, state domains.DKIMKeyState) *pb.DKIMKey {
+	key := &pb.DKIMKey{
+		Selector:    k.Selector(),
+		Algorithm:   k.Algorithm(),
+		PublicKey:   k.PublicKey(),
+		State:       dkimKeyStateToPb(state),
+		RecordName:  k.RecordName(domain.String()),
+		RecordValue: k.RecordValue(),
+		CreatedAt:   timestamppb.New(k.CreatedAt()),
+		Origin:      string(k.Origin()),
+		Bits:        uint32(k.Bits()),
+	}
+	if k.Activated() {
+		key.ActivatedAt = timestamppb.New(k.ActivatedAt())
+	}
+	if k.Retired() {
+		key.RetiredAt = timestamppb.New(k.RetiredAt())
+	}
+	return key
+}
+
+func dkimKeyStateToPb(s domains.DKIMKeyState) pb.DKIMKeyState {
+	switch s {
+	case domains.DKIMKeyPending:
+		return pb.DKIMKeyState_DKIM_KEY_STATE_PENDING
+	case domains.DKIMKeyActive:
+		return pb.DKIMKeyState_DKIM_KEY_STATE_ACTIVE
+	case domains.DKIMKeySuperseded:
+		return pb.DKIMKeyState_DKIM_KEY_STATE_SUPERSEDED
+	case domains.DKIMKeyRetired:
+		return pb.DKIMKeyState_DKIM_KEY_STATE_RETIRED
+	default:
+		return pb.DKIMKeyState_DKIM_KEY_STATE_UNSPECIFIED
+	}
+}
@@ -2,12 +2,18 @@ package adminapi
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/kannon-email/kannon/internal/apikeys"
+	"github.com/kannon-email/kannon/internal/cooldown"
 	"github.com/kannon-email/kannon/internal/domains"
+	"github.com/kannon-email/kannon/internal/feedbackpb"
+	"github.com/kannon-email/kannon/internal/linkparamspb"
 	"github.com/kannon-email/kannon/internal/templates"
 	"github.com/kannon-email/kannon/internal/trackingpb"
 	"github.com/kannon-email/kannon/internal/values"
+	"github.com/kannon-email/kannon/internal/warmup"
 
 	pb "github.com/kannon-email/kannon/proto/kannon/admin/apiv1"
 )
@@ -19,6 +25,12 @@ type adminAPIService struct {
 	domains   *domains.Service
 	templates *templates.Service
 	apiKeys   *apikeys.Service
+	warmup    *warmup.Service
+	cooldowns *cooldown.Service
+
+	// returnPathDNS is the installation's side of a return-path domain's records; see
+	// WithReturnPathDNS.
+	returnPathDNS domains.ReturnPathDNS
 }
 
 func (s *adminAPIService) GetDomains(ctx context.Context, in *pb.GetDomainsReq) (*pb.GetDomainsResponse, error) {
@@ -29,7 +41,7 @@ func (s *adminAPIService) GetDomains(ctx context.Context, in *pb.GetDomainsReq)
 
 	res := pb.GetDomainsResponse{}
 	for _, d := range all {
-		res.Domains = append(res.Domains, domainToPb(d))
+		res.Domains = append(res.Domains, s.domainToPb(d))
 	}
 	return &res, nil
 }
@@ -49,7 +61,7 @@ func (s *adminAPIService) GetDomain(ctx context.Context, in *pb.GetDomainReq) (*
 	}
 
 	return &pb.GetDomainRes{
-		Domain: domainToPb(d),
+		Domain: s.domainToPb(d),
 	}, nil
 }
 
@@ -59,11 +71,11 @@ func (s *adminAPIService) CreateDomain(ctx context.Context, in *pb.CreateDomainR
 		return nil, err
 	}
 
-	d, err := s.domains.CreateDomain(ctx, name)
+	d, err := s.domains.CreateDomain(ctx, name, initialDKIMKeyFromPb(in))
 	if err != nil {
 		return nil, err
 	}
-	return domainToPb(d), nil
+	return s.domainToPb(d), nil
 }
 
 func (s *adminAPIService) SetTrackingPolicy(ctx context.Context, in *pb.SetTrackingPolicyReq) (*pb.SetTrackingPolicyRes, error) {
@@ -82,15 +94,122 @@ func (s *adminAPIService) SetTrackingPolicy(ctx context.Context, in *pb.SetTrack
 		return nil, err
 	}
 
-	return &pb.SetTrackingPolicyRes{Domain: domainToPb(d)}, nil
+	return &pb.SetTrackingPolicyRes{Domain: s.domainToPb(d)}, nil
+}
+
+// errInvalidReturnPathDomain marks a return-path domain that is not a domain name at all, which the
+// adapter answers as a bad argument, unlike a malformed name of the Domain being addressed.
+var errInvalidReturnPathDomain = errors.New("invalid return-path domain")
+
+func (s *adminAPIService) SetReturnPathDomain(ctx context.Context, in *pb.SetReturnPathDomainReq) (*pb.SetReturnPathDomainRes, error) {
+	name, err := values.Parse(in.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	// Empty on the wire is the zero name, which hands bounces back to the Domain itself.
+	var rp values.DomainName
+	if in.ReturnPathDomain != "" {
+		if rp, err = values.Parse(in.ReturnPathDomain); err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidReturnPathDomain, err)
+		}
+	}
+
+	d, err := s.domains.SetReturnPathDomain(ctx, name, rp)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetReturnPathDomainRes{Domain: s.domainToPb(d)}, nil
+}
+
+func (s *adminAPIService) SetFeedbackIdentity(ctx context.Context, in *pb.SetFeedbackIdentityReq) (*pb.SetFeedbackIdentityRes, error) {
+	name, err := values.Parse(in.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.domains.SetFeedbackIdentity(ctx, name, feedbackpb.ToIdentity(in.Feedback))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetFeedbackIdentityRes{Domain: s.domainToPb(d)}, nil
+}
+
+func (s *adminAPIService) SetLinkDecoration(ctx context.Context, in *pb.SetLinkDecorationReq) (*pb.SetLinkDecorationRes, error) {
+	name, err := values.Parse(in.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.domains.SetLinkDecoration(ctx, name, linkparamspb.ToDecoration(in.LinkDecoration))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetLinkDecorationRes{Domain: s.domainToPb(d)}, nil
 }
 
-// domainToPb renders a Domain onto the wire type. Only the domain name, the public DKIM key and
-// the Tracking Policy are exposed on the wire — the private key never leaves the server.
-func domainToPb(d *domains.Domain) *pb.Domain {
+func (s *adminAPIService) SetRequireTLS(ctx context.Context, in *pb.SetRequireTLSReq) (*pb.SetRequireTLSRes, error) {
+	name, err := values.Parse(in.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.domains.SetRequireTLS(ctx, name, in.RequireTls)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetRequireTLSRes{Domain: s.domainToPb(d)}, nil
+}
+
+func (s *adminAPIService) SetIPPool(ctx context.Context, in *pb.SetIPPoolReq) (*pb.SetIPPoolRes, error) {
+	name, err := values.Parse(in.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.domains.SetIPPool(ctx, name, in.IpPool)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetIPPoolRes{Domain: s.domainToPb(d)}, nil
+}
+
+func (s *adminAPIService) SetSMIMECertificate(ctx context.Context, in *pb.SetSMIMECertificateReq) (*pb.SetSMIMECertificateRes, error) {
+	name, err := values.Parse(in.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.domains.SetSMIMECertificate(ctx, name, in.Certificate, in.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetSMIMECertificateRes{Domain: s.domainToPb(d)}, nil
+}
+
+// domainToPb renders a Domain onto the wire type. Only the domain name, the public DKIM keys, the
+// Tracking Policy, the return path, the Feedback Identity, the Link Decoration, the S/MIME
+// certificate and whether TLS is required are exposed on the wire — no private key ever leaves the server.
+func (s *adminAPIService) domainToPb(d *domains.Domain) *pb.Domain {
 	return &pb.Domain{
-		Domain:     d.Domain(),
-		DkimPubKey: d.DkimPublicKey(),
-		Tracking:   trackingpb.FromPolicy(d.TrackingPolicy()),
+		Domain:            d.Domain(),
+		DkimPubKey:        d.DkimPublicKey(),
+		Tracking:          trackingpb.FromPolicy(d.TrackingPolicy()),
+		DkimKeys:          dkimKeysToPb(d),
+		ReturnPathDomain:  d.ReturnPathDomain().String(),
+		ReturnPathRecords: dnsRecordsToPb(d.ReturnPathRecords(s.returnPathDNS)),
+		Feedback:          feedbackpb.FromIdentity(d.Feedback()),
+		SmimeCertificate:  d.SMIME().Certificate,
+		LinkDecoration:    linkparamspb.FromDecoration(d.LinkDecoration()),
+		RequireTls:        d.RequireTLS(),
+		IpPool:            d.IPPool(),
+	}
+}
+
+func dnsRecordsToPb(records []domains.DNSRecord) []*pb.DNSRecord {
+	out := make([]*pb.DNSRecord, 0, len(records))
+	for _, r := range records {
+		out = append(out, &pb.DNSRecord{Type: r.Type, Name: r.Name, Value: r.Value})
 	}
+	return out
 }
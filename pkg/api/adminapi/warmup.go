@@ -0,0 +1,43 @@
+package adminapi
+
+import (
+	"context"
+
+	"github.com/kannon-email/kannon/internal/warmup"
+	pb "github.com/kannon-email/kannon/proto/kannon/admin/apiv1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *adminAPIService) GetWarmupProgress(ctx context.Context, _ *pb.GetWarmupProgressReq) (*pb.GetWarmupProgressRes, error) {
+	progress, err := s.warmup.Progress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.GetWarmupProgressRes{}
+	for _, p := range progress {
+		wp := &pb.WarmupProgress{
+			Address: p.Address,
+			Plan:    p.Plan,
+			Start:   timestamppb.New(p.Start),
+			Day:     uint32(p.Day),
+			Days:    uint32(p.Days),
+			Warm:    p.Warm,
+			Today:   warmupUsageToPb(p.Today),
+			Hour:    warmupUsageToPb(p.Hour),
+		}
+		for _, pp := range p.Providers {
+			wp.Providers = append(wp.Providers, &pb.ProviderWarmupProgress{
+				Destination: pp.Destination,
+				Today:       warmupUsageToPb(pp.Today),
+				Hour:        warmupUsageToPb(pp.Hour),
+			})
+		}
+		res.Addresses = append(res.Addresses, wp)
+	}
+	return res, nil
+}
+
+func warmupUsageToPb(u warmup.Usage) *pb.WarmupUsage {
+	return &pb.WarmupUsage{Sent: uint32(u.Sent), Limit: uint32(u.Limit)}
+}
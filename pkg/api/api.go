@@ -12,11 +12,16 @@ import (
 	"github.com/kannon-email/kannon/internal/audit"
 	"github.com/kannon-email/kannon/internal/authz"
 	"github.com/kannon-email/kannon/internal/authzconnect"
+	"github.com/kannon-email/kannon/internal/circuit"
+	"github.com/kannon-email/kannon/internal/cooldown"
 	sq "github.com/kannon-email/kannon/internal/db"
+	"github.com/kannon-email/kannon/internal/domains"
 	"github.com/kannon-email/kannon/internal/stats"
+	"github.com/kannon-email/kannon/internal/warmup"
 	"github.com/kannon-email/kannon/pkg/api/adminapi"
 	"github.com/kannon-email/kannon/pkg/api/hzapi"
 	"github.com/kannon-email/kannon/pkg/api/mailapi"
+	"github.com/kannon-email/kannon/pkg/hz"
 	"github.com/kannon-email/kannon/pkg/statsapi/statsv1"
 	"github.com/kannon-email/kannon/pkg/statsapi/statsv2"
 	adminv1connect "github.com/kannon-email/kannon/proto/kannon/admin/apiv1/apiv1connect"
@@ -99,11 +104,16 @@ func run(ctx context.Context, config Config, cnt *container.Container) error {
 	// operator who enabled it must not include the API refusing to serve.
 	recorder := startAuditRecording(ctx, cnt)
 
-	adminAPIService := adminapi.CreateAdminAPIService(db)
+	cooldowns := destinationCooldowns(ctx, cnt)
+	adminAPIService := adminapi.CreateAdminAPIService(db,
+		adminapi.WithReturnPathDNS(returnPathDNS()),
+		adminapi.WithWarmup(warmupProgress(ctx, cnt)),
+		adminapi.WithCooldowns(cooldowns),
+	)
 	mailAPIService := mailapi.NewMailerAPIV1(db, cnt.BackoffPolicy(), cnt.RetryWindow())
 	statsAPIService := statsv1.NewStatsAPIService(statsService)
 	statsV2APIService := statsv2.NewStatsAPIService(statsService)
-	hzAPIService := hzapi.CreateHZAPIService(cnt)
+	hzAPIService := hzapi.CreateHZAPIService(cnt, append(mxCircuits(ctx, cnt), hz.WithCooldowns(cooldowns))...)
 
 	// The operator's credential is read here, once, and handed down. Nothing beneath
 	// this point asks the configuration layer what authority a request has.
@@ -112,6 +122,121 @@ func run(ctx context.Context, config Config, cnt *container.Container) error {
 	return startAPIServer(ctx, port, adminAuth, recorder, adminAPIService, mailAPIService, statsAPIService, statsV2APIService, hzAPIService)
 }
 
+// returnPathDNS reads the hosts a return-path domain's records point at out of the sections of the
+// components that own them: bounces arrive at the SMTP server announced as `smtp.domain`, and mail
+// leaves from `sender.hostname`. One config file describes the whole installation, so an API
+// process can read both without running either.
+//
+// Neither can fail the boot. A section that cannot be read — a reference to a variable only the
+// sender pods set, say — leaves its record out of what the Admin API reports, which is the same
+// answer it gives an installation that never set the key.
+func returnPathDNS() domains.ReturnPathDNS {
+	var smtpCfg struct {
+		Domain string `mapstructure:"domain"`
+	}
+	if err := config.TryLoadSection("smtp", &smtpCfg); err != nil {
+		slog.Warn("cannot read smtp.domain, so return-path MX records are not reported", "err", err)
+	}
+	var senderCfg struct {
+		Hostname string `mapstructure:"hostname"`
+	}
+	if err := config.TryLoadSection("sender", &senderCfg); err != nil {
+		slog.Warn("cannot read sender.hostname, so return-path SPF records are not reported", "err", err)
+	}
+	return domains.ReturnPathDNS{MXHost: smtpCfg.Domain, SenderHost: senderCfg.Hostname}
+}
+
+// warmupProgress reads `sender.warmup` for the Admin API to report on, and opens the bucket the
+// SMTPSender counts in. Like returnPathDNS it cannot fail the boot: a section that cannot be read
+// reports no address warming, with a warning, and a NATS that cannot be reached has
+// GetWarmupProgress answer Unavailable — which is the truth — rather than the API refuse to serve.
+// An installation warming nothing never connects to NATS on its account.
+func warmupProgress(ctx context.Context, cnt *container.Container) *warmup.Service {
+	var senderCfg struct {
+		Warmup warmup.Config `mapstructure:"warmup"`
+	}
+	if err := config.TryLoadSection("sender", &senderCfg); err != nil {
+		slog.Warn("cannot read sender.warmup, so no warm-up progress is reported", "err", err)
+		return warmup.NewService(nil, nil)
+	}
+	schedule, err := warmup.New(senderCfg.Warmup)
+	if err != nil {
+		slog.Warn("sender.warmup is not valid, so no warm-up progress is reported", "err", err)
+		return warmup.NewService(nil, nil)
+	}
+	if !schedule.Enabled() {
+		return warmup.NewService(nil, nil)
+	}
+
+	js, err := cnt.TryNatsJetStream()
+	if err != nil {
+		slog.Error("cannot reach NATS, so warm-up progress cannot be reported", "err", err)
+		return warmup.NewService(schedule, nil)
+	}
+	counter, err := warmup.OpenNATS(ctx, js)
+	if err != nil {
+		slog.Error("cannot open the warm-up bucket, so warm-up progress cannot be reported", "err", err)
+		return warmup.NewService(schedule, nil)
+	}
+	return warmup.NewService(schedule, counter)
+}
+
+// destinationCooldowns reads `sender.cooldown` for the Admin API to list and the HZ service to
+// count, and opens the bucket the SMTPSender keeps cool-downs in. Like warmupProgress it cannot
+// fail the boot: a section that cannot be read, or cool-downs turned off, reports none, and a NATS
+// that cannot be reached is reported as cool-downs that cannot be read.
+func destinationCooldowns(ctx context.Context, cnt *container.Container) *cooldown.Service {
+	var senderCfg struct {
+		Cooldown cooldown.Config `mapstructure:"cooldown"`
+	}
+	if err := config.TryLoadSection("sender", &senderCfg); err != nil {
+		slog.Warn("cannot read sender.cooldown, so no destination cool-downs are reported", "err", err)
+		return cooldown.NewService(false, nil)
+	}
+	if senderCfg.Cooldown.Disabled {
+		return cooldown.NewService(false, nil)
+	}
+
+	js, err := cnt.TryNatsJetStream()
+	if err != nil {
+		slog.Error("cannot reach NATS, so destination cool-downs cannot be reported", "err", err)
+		return cooldown.NewService(true, nil)
+	}
+	store, err := cooldown.OpenNATS(ctx, js, senderCfg.Cooldown)
+	if err != nil {
+		slog.Error("cannot open the cool-down bucket, so destination cool-downs cannot be reported", "err", err)
+		return cooldown.NewService(true, nil)
+	}
+	return cooldown.NewService(true, store)
+}
+
+// mxCircuits reads `sender.circuit` for the HZ service to report on, and opens the bucket the
+// Sender keeps MX circuits in, the way destinationCooldowns does for cool-downs.
+func mxCircuits(ctx context.Context, cnt *container.Container) []hz.Option {
+	var senderCfg struct {
+		Circuit circuit.Config `mapstructure:"circuit"`
+	}
+	if err := config.TryLoadSection("sender", &senderCfg); err != nil {
+		slog.Warn("cannot read sender.circuit, so no MX circuits are reported", "err", err)
+		return nil
+	}
+	if senderCfg.Circuit.Disabled {
+		return nil
+	}
+
+	js, err := cnt.TryNatsJetStream()
+	if err != nil {
+		slog.Error("cannot reach NATS, so MX circuits cannot be reported", "err", err)
+		return []hz.Option{hz.WithCircuits(nil)}
+	}
+	breaker, err := circuit.OpenNATS(ctx, js, senderCfg.Circuit)
+	if err != nil {
+		slog.Error("cannot open the MX circuit bucket, so MX circuits cannot be reported", "err", err)
+		return []hz.Option{hz.WithCircuits(nil)}
+	}
+	return []hz.Option{hz.WithCircuits(breaker)}
+}
+
 // startAuditRecording resolves the Recorder every authorization decision on this process reports to,
 // and nil when the operator asked for no audit trail — which is the default. Nil means "install
 // nothing", so Guard keeps the logging Recorder it has always had and this process never connects to
@@ -193,7 +318,9 @@ func withRecorder(r authz.Recorder, next http.Handler) http.Handler {
 
 // startAPIServer mounts every handler. adminAuth authenticates the three surfaces that answer to
 // the operator's admin token. The mailer handler must never get it — it authenticates its own
-// sender credential — and neither must health, which discloses nothing and is polled unauthenticated.
+// sender credential — and neither must health, which is polled unauthenticated and discloses
+// nothing beyond which destinations are throttling this installation, with no reply text that could
+// name its addresses.
 func startAPIServer(ctx context.Context, port uint, adminAuth []connect.HandlerOption, recorder authz.Recorder, adminServer adminv1connect.ApiHandler, mailerServer mailerv1connect.MailerHandler, statsServer statsv1connect.StatsApiV1Handler, statsV2Server statsv2connect.StatsApiV2Handler, hzServer adminv1connect.HZServiceHandler) error {
 	addr := fmt.Sprintf("0.0.0.0:%d", port)
 	mux := http.NewServeMux()
@@ -8,6 +8,7 @@ import (
 	pb "github.com/kannon-email/kannon/proto/kannon/admin/apiv1"
 	hzv1connect "github.com/kannon-email/kannon/proto/kannon/admin/apiv1/apiv1connect"
 	"github.com/kannon-email/kannon/x/container"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type hzAPIConnectAdapter struct {
@@ -31,11 +32,34 @@ func (h *hzAPIConnectAdapter) HZ(ctx context.Context, req *connect.Request[pb.HZ
 		Result: stringResult,
 	}
 
+	// A count and not the destinations: they name where mail goes and the MX
+	// host that throttled it, and health is not authenticated.
+	coolingDown, err := h.hzService.CoolingDown(ctx)
+	if err != nil {
+		stringResult[hz.CooldownsCheck] = err.Error()
+	}
+	response.CoolingDown = uint32(coolingDown)
+
+	circuits, err := h.hzService.Circuits(ctx)
+	if err != nil {
+		stringResult[hz.CircuitsCheck] = err.Error()
+	}
+	for _, c := range circuits {
+		// Not the failure that opened it: it may name the addresses this
+		// installation sends from, and health is not authenticated.
+		response.Circuits = append(response.Circuits, &pb.MXCircuit{
+			Mx:       c.MX,
+			State:    string(c.State),
+			OpenedAt: timestamppb.New(c.OpenedAt),
+			RetryAt:  timestamppb.New(c.RetryAt),
+		})
+	}
+
 	return connect.NewResponse(response), nil
 }
 
-func CreateHZAPIService(cnt *container.Container) hzv1connect.HZServiceHandler {
-	hzService := hz.NewHZ(cnt)
+func CreateHZAPIService(cnt *container.Container, opts ...hz.Option) hzv1connect.HZServiceHandler {
+	hzService := hz.NewHZ(cnt, opts...)
 	return &hzAPIConnectAdapter{
 		hzService: hzService,
 	}
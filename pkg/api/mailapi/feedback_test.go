@@ -0,0 +1,40 @@
+package mailapi_test
+
+import (
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	feedbacktypes "github.com/kannon-email/kannon/proto/kannon/feedback/types"
+	mailerv1 "github.com/kannon-email/kannon/proto/kannon/mailer/apiv1"
+	types "github.com/kannon-email/kannon/proto/kannon/mailer/types"
+)
+
+// A Batch's Feedback Identity override is validated with the request, like its
+// unsubscribe endpoint: a value its header cannot carry fails the whole call.
+func TestSendValidatesTheFeedbackOverride(t *testing.T) {
+	defer cleanDB(t)
+
+	d := createTestDomain(t)
+	send := func(f *feedbacktypes.FeedbackIdentity) error {
+		req := connect.NewRequest(&mailerv1.SendHTMLReq{
+			Sender:        &types.Sender{Email: "test@" + d.Domain.Domain, Alias: "Test"},
+			Recipients:    []*types.Recipient{{Email: "first@email.com"}},
+			Subject:       "Test",
+			Html:          `<p>Hello</p>`,
+			ScheduledTime: timestamppb.Now(),
+			Feedback:      f,
+		})
+		authRequest(req, d)
+		_, err := ts.SendHTML(t.Context(), req)
+		return err
+	}
+
+	require.NoError(t, send(&feedbacktypes.FeedbackIdentity{Campaign: "spring", ListId: "news." + d.Domain.Domain}))
+
+	err := send(&feedbacktypes.FeedbackIdentity{Campaign: "spring sale"})
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
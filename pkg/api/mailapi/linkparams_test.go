@@ -0,0 +1,39 @@
+package mailapi_test
+
+import (
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	mailerv1 "github.com/kannon-email/kannon/proto/kannon/mailer/apiv1"
+	types "github.com/kannon-email/kannon/proto/kannon/mailer/types"
+)
+
+// A Batch's link parameters are validated with the request, like its Feedback
+// Identity: a name no URL should carry fails the whole call.
+func TestSendValidatesTheLinkParamsOverride(t *testing.T) {
+	defer cleanDB(t)
+
+	d := createTestDomain(t)
+	send := func(p map[string]string) error {
+		req := connect.NewRequest(&mailerv1.SendHTMLReq{
+			Sender:        &types.Sender{Email: "test@" + d.Domain.Domain, Alias: "Test"},
+			Recipients:    []*types.Recipient{{Email: "first@email.com"}},
+			Subject:       "Test",
+			Html:          `<p><a href="https://example.com">Hello</a></p>`,
+			ScheduledTime: timestamppb.Now(),
+			LinkParams:    p,
+		})
+		authRequest(req, d)
+		_, err := ts.SendHTML(t.Context(), req)
+		return err
+	}
+
+	require.NoError(t, send(map[string]string{"utm_campaign": "spring", "utm_source": ""}))
+
+	err := send(map[string]string{"utm campaign": "spring"})
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
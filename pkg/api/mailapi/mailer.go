@@ -19,6 +19,9 @@ import (
 	sqlc "github.com/kannon-email/kannon/internal/db"
 	"github.com/kannon-email/kannon/internal/delivery"
 	"github.com/kannon-email/kannon/internal/domains"
+	"github.com/kannon-email/kannon/internal/feedbackpb"
+	"github.com/kannon-email/kannon/internal/linkparamspb"
+	"github.com/kannon-email/kannon/internal/smime"
 	smtputils "github.com/kannon-email/kannon/internal/smtp"
 	"github.com/kannon-email/kannon/internal/templates"
 	"github.com/kannon-email/kannon/internal/tracking"
@@ -69,6 +72,10 @@ func (s mailAPIService) SendHTML(ctx context.Context, req *connect.Request[pb.Se
 		Headers:             req.Msg.Headers,
 		Tracking:            req.Msg.Tracking,
 		OneClickUnsubscribe: req.Msg.OneClickUnsubscribe,
+		Feedback:            req.Msg.Feedback,
+		LinkParams:          req.Msg.LinkParams,
+		RequireTls:          req.Msg.RequireTls,
+		IpPool:              req.Msg.IpPool,
 	}
 
 	return s.sendTemplate(ctx, domain, connect.NewRequest(res))
@@ -155,7 +162,9 @@ func (s mailAPIService) createBatch(ctx context.Context, domain *domains.Domain,
 
 	// A malformed or non-https endpoint is a fault in the request as a whole,
 	// not in one of its rows, so it fails the call rather than refusing
-	// Recipients one by one (ADR 0005). batch.New holds the invariant.
+	// Recipients one by one (ADR 0005). batch.New holds the invariant, and the
+	// same for a Feedback Identity its headers cannot carry and for link
+	// parameters no URL should.
 	b, err := batch.New(batch.NewParams{
 		Domain:              domain.Domain(),
 		Subject:             req.Msg.Subject,
@@ -164,7 +173,11 @@ func (s mailAPIService) createBatch(ctx context.Context, domain *domains.Domain,
 		Attachments:         attachments,
 		Headers:             customHeaders,
 		OneClickUnsubscribe: unsubscribeFromRequest(req.Msg.OneClickUnsubscribe),
+		Feedback:            feedbackpb.ToIdentity(req.Msg.Feedback),
+		LinkParams:          linkparamspb.ToParams(req.Msg.LinkParams),
 		Tracking:            batchPolicy,
+		RequireTLS:          req.Msg.RequireTls,
+		IPPool:              req.Msg.IpPool,
 	})
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
@@ -213,6 +226,10 @@ const (
 	// the Batch's one-click unsubscribe URL. Refusing beats sending a DKIM-signed header
 	// advertising an authenticated endpoint that is a URL with braces in it (ADR 0005).
 	reasonUnsubscribeURLUnresolved rejectionReason = "unsubscribe_url_unresolved"
+	// reasonSMIMECertificateInvalid is a Recipient whose S/MIME certificate cannot be
+	// encrypted to: unparseable, expired, not RSA, or not meant for email. Sending in
+	// the clear instead would quietly break the promise the certificate asked for.
+	reasonSMIMECertificateInvalid rejectionReason = "smime_certificate_invalid"
 )
 
 // intake is what became of a Batch's Recipients: those accepted onto the Pool, and
@@ -265,6 +282,8 @@ func recipientsFromRequest(rs []*mailertypes.Recipient) []statedRecipient {
 				Email:    r.GetEmail(),
 				Fields:   r.GetFields(),
 				Tracking: policy,
+
+				SMIMECertificate: r.GetSmimeCertificate(),
 			},
 			trackingErr: err,
 		})
@@ -294,6 +313,11 @@ func (s mailAPIService) scheduleBatch(ctx context.Context, domain *domains.Domai
 			taken.reject(r.Email, reasonUnsubscribeURLUnresolved, detail)
 			continue
 		}
+		smimeCertificate, err := recipientSMIMECertificate(r.Recipient, scheduled)
+		if err != nil {
+			taken.reject(r.Email, reasonSMIMECertificateInvalid, err.Error())
+			continue
+		}
 		d, err := delivery.New(delivery.NewParams{
 			BatchID:       b.ID(),
 			Email:         r.Email,
@@ -303,6 +327,8 @@ func (s mailAPIService) scheduleBatch(ctx context.Context, domain *domains.Domai
 			Backoff:       s.backoff,
 			RetryWindow:   s.retryWindow,
 			Tracking:      policy,
+
+			SMIMECertificate: smimeCertificate,
 		})
 		if err != nil {
 			taken.reject(r.Email, reasonInvalidEmail, err.Error())
@@ -319,6 +345,22 @@ func (s mailAPIService) scheduleBatch(ctx context.Context, domain *domains.Domai
 	return taken, nil
 }
 
+// recipientSMIMECertificate checks the certificate a Recipient asked to be
+// encrypted to and returns it DER, the form the Delivery keeps; nil when none
+// was stated. It is checked against the time the Delivery is scheduled for, so
+// that a certificate that will have expired by then is refused now rather than
+// failing in the Dispatcher with nobody to tell.
+func recipientSMIMECertificate(r batch.Recipient, at time.Time) ([]byte, error) {
+	if r.SMIMECertificate == "" {
+		return nil, nil
+	}
+	cert, err := smime.ParseRecipientCertificate(r.SMIMECertificate, at)
+	if err != nil {
+		return nil, err
+	}
+	return cert.Raw, nil
+}
+
 // unsubscribeFromRequest maps the wire type onto the domain value object. A
 // caller stating nothing yields the zero value, and no unsubscribe header.
 func unsubscribeFromRequest(u *mailertypes.OneClickUnsubscribe) batch.OneClickUnsubscribe {
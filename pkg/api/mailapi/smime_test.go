@@ -0,0 +1,35 @@
+package mailapi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kannon-email/kannon/internal/tests"
+	types "github.com/kannon-email/kannon/proto/kannon/mailer/types"
+)
+
+// A Recipient's S/MIME certificate is checked at intake: one that cannot be
+// encrypted to Rejects that Recipient alone, rather than sending them in the
+// clear what they asked to receive sealed.
+func TestSendRejectsAnUnusableSMIMECertificate(t *testing.T) {
+	defer cleanDB(t)
+
+	d := createTestDomain(t)
+	requireOnlyBadRowRejected(t, d, &types.Recipient{
+		Email:            "sealed@email.com",
+		SmimeCertificate: "-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydGlmaWNhdGU=\n-----END CERTIFICATE-----\n",
+	}, "smime_certificate_invalid")
+}
+
+// A usable one is accepted and travels with the Delivery to the Builder.
+func TestSendAcceptsAnSMIMECertificate(t *testing.T) {
+	defer cleanDB(t)
+
+	d := createTestDomain(t)
+	cert, _ := tests.SMIMECertificate(t, "sealed@email.com")
+	res := requireSend(t, d, nil, &types.Recipient{Email: "sealed@email.com", SmimeCertificate: cert})
+
+	assert.Empty(t, rejections(t, res))
+	assert.Equal(t, []string{"sealed@email.com"}, poolEmails(t, res.MessageId))
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/kannon-email/kannon/internal/batch"
+	"github.com/kannon-email/kannon/internal/bodystore"
 	"github.com/kannon-email/kannon/internal/delivery"
 	"github.com/kannon-email/kannon/internal/envelope"
 	"github.com/kannon-email/kannon/internal/pool"
@@ -25,18 +27,26 @@ type disp struct {
 	eb      envelope.Builder
 	pub     publisher.Publisher
 	js      jetstream.JetStream
+	// bodies takes the bodies larger than maxInline off the sending stream.
+	// Nil publishes every body inline, whatever its size.
+	bodies    bodystore.Store
+	maxInline int
+	// workers is how many Deliveries of a claimed page are built at once.
+	// Zero or one builds them one after the other.
+	workers int
 }
 
 func (d *disp) log() *slog.Logger {
 	return slog.With("component", "dispatcher")
 }
 
-// Dispatch timeouts. The claim gets its own budget; each Delivery then
-// gets an individual budget for Build. A single per-cycle budget shared
-// across the whole claimed page guillotines the page tail when one
-// Delivery is slow — see #400.
+// Dispatch timeouts. The claim gets its own budget; each Batch of the page
+// gets one for its preparation and each Delivery then an individual budget
+// for Build. A single per-cycle budget shared across the whole claimed page
+// guillotines the page tail when one Delivery is slow — see #400.
 const (
 	claimTimeout       = 10 * time.Second
+	perBatchTimeout    = 5 * time.Second
 	perDeliveryTimeout = 5 * time.Second
 )
 
@@ -47,7 +57,8 @@ const (
 const (
 	// reasonBudgetSpentDispatching: no Envelope for this Delivery could be built
 	// or handed on before the budget ran out — its Batch's Template is gone, its
-	// Domain's DKIM key is unusable, or NATS would not take the Envelope.
+	// Domain's DKIM key is unusable, or NATS would not take the Envelope or its
+	// body.
 	reasonBudgetSpentDispatching = "retry budget exhausted while dispatching"
 
 	// reasonBudgetSpentSending: the budget ran out with a transmission attempt
@@ -63,14 +74,102 @@ func (d *disp) DispatchCycle(ctx context.Context) error {
 
 	d.log().Debug(fmt.Sprintf("seding %d emails", len(emails)))
 
-	for _, dlv := range emails {
-		d.dispatchOne(ctx, dlv)
+	var jobs []dispatchJob
+	for _, group := range groupByBatch(emails) {
+		jobs = append(jobs, d.prepareBatch(ctx, group)...)
 	}
+	d.runJobs(ctx, jobs)
 
 	d.log().Debug("done sending emails")
 	return nil
 }
 
+// dispatchJob is one claimed Delivery together with the preparation of its
+// Batch it is built from.
+type dispatchJob struct {
+	batch envelope.BatchBuilder
+	dlv   *delivery.Delivery
+}
+
+// groupByBatch splits a claimed page by Batch, keeping the order in which each
+// Batch first appears and the claim order within each.
+func groupByBatch(dlvs []*delivery.Delivery) [][]*delivery.Delivery {
+	var groups [][]*delivery.Delivery
+	index := make(map[batch.ID]int)
+	for _, dlv := range dlvs {
+		i, ok := index[dlv.BatchID()]
+		if !ok {
+			i = len(groups)
+			index[dlv.BatchID()] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], dlv)
+	}
+	return groups
+}
+
+// prepareBatch prepares one Batch of the page — its SendingData, its parsed
+// template, its signer and its attachments, once rather than per Delivery — and
+// returns its Deliveries as jobs. A Batch that cannot be prepared could not have
+// built a single one of its Deliveries, so they are all handed back through the
+// retry chokepoint at once, and the rest of the page carries on.
+func (d *disp) prepareBatch(ctx context.Context, group []*delivery.Delivery) []dispatchJob {
+	batchID := group[0].BatchID()
+
+	prepCtx, cancel := context.WithTimeout(ctx, perBatchTimeout)
+	defer cancel()
+
+	prepared, err := d.eb.ForBatch(prepCtx, batchID)
+	if err != nil {
+		log := d.log().With("batch_id", batchID.String(), "deliveries", len(group))
+		log.With("err", err).Error("Cannot prepare batch")
+		for _, dlv := range group {
+			d.reschedule(ctx, dlv, log.With("email", utils.ObfuscateEmail(dlv.Email())))
+		}
+		return nil
+	}
+
+	jobs := make([]dispatchJob, 0, len(group))
+	for _, dlv := range group {
+		jobs = append(jobs, dispatchJob{batch: prepared, dlv: dlv})
+	}
+	return jobs
+}
+
+// runJobs builds and publishes the page on d.workers workers. Rendering and
+// signing are CPU-bound and a Delivery's tracking tokens are a round trip each,
+// so a page built one Delivery at a time leaves both the cores and the database
+// idle most of the time.
+//
+// Every job keeps its own budget (dispatchOne), so a slow Delivery still holds
+// up only the worker it runs on.
+func (d *disp) runJobs(ctx context.Context, jobs []dispatchJob) {
+	workers := min(max(d.workers, 1), len(jobs))
+	if workers <= 1 {
+		for _, job := range jobs {
+			d.dispatchOne(ctx, job.batch, job.dlv)
+		}
+		return
+	}
+
+	queue := make(chan dispatchJob)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				d.dispatchOne(ctx, job.batch, job.dlv)
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+}
+
 func (d *disp) claimForDispatch(ctx context.Context) ([]*delivery.Delivery, error) {
 	ctx, cancel := context.WithTimeout(ctx, claimTimeout)
 	defer cancel()
@@ -84,7 +183,7 @@ func (d *disp) claimForDispatch(ctx context.Context) ([]*delivery.Delivery, erro
 // Envelope that was never published. Dropping the row on the floor here
 // loses it silently and permanently (#400), so it is either handed back to
 // the pool or ended as Failed, never neither.
-func (d *disp) dispatchOne(ctx context.Context, dlv *delivery.Delivery) {
+func (d *disp) dispatchOne(ctx context.Context, eb envelope.BatchBuilder, dlv *delivery.Delivery) {
 	log := d.log().With(
 		"email", utils.ObfuscateEmail(dlv.Email()),
 		"batch_id", dlv.BatchID().String(),
@@ -93,7 +192,7 @@ func (d *disp) dispatchOne(ctx context.Context, dlv *delivery.Delivery) {
 	buildCtx, cancel := context.WithTimeout(ctx, perDeliveryTimeout)
 	defer cancel()
 
-	env, err := d.eb.Build(buildCtx, dlv)
+	env, err := eb.Build(buildCtx, dlv)
 	if err != nil {
 		log.With("err", err).Error("Cannot send email")
 		d.reschedule(ctx, dlv, log)
@@ -102,6 +201,15 @@ func (d *disp) dispatchOne(ctx context.Context, dlv *delivery.Delivery) {
 
 	log = log.With("email_id", env.EmailID())
 
+	if d.bodies != nil {
+		env, err = bodystore.Offload(buildCtx, d.bodies, env, d.maxInline)
+		if err != nil {
+			log.With("err", err).Error("Cannot send email")
+			d.reschedule(ctx, dlv, log)
+			return
+		}
+	}
+
 	if err := publisher.SendEmail(d.pub, env); err != nil {
 		log.With("err", err).Error("Cannot send email")
 		d.reschedule(ctx, dlv, log)
@@ -244,6 +352,34 @@ func (d *disp) parsBouncedFunc(ctx context.Context, e stats.Event) error {
 	return nil
 }
 
+func (d *disp) handlePostponed(ctx context.Context) error {
+	sbj := "kannon.stats.postponed"
+	subName := "dispatcher-postponed"
+	return d.handleMsg(ctx, sbj, subName, d.parsePostponedFunc)
+}
+
+// parsePostponedFunc hands a Delivery whose Envelope the SMTPSender held back
+// to the Pool to be claimed again when the sender said, without touching its
+// Retry Budget: no attempt was made, so there is nothing for retryOrFail to
+// weigh. Leaving the hold-back on the sending stream instead is what it
+// replaced, and a hold of hours outlived sendingStrandThreshold, so the
+// reclaim published the Delivery a second time while the first Envelope was
+// still waiting to go out.
+func (d *disp) parsePostponedFunc(ctx context.Context, e stats.Event) error {
+	if e.Outcome.Type() != stats.TypePostponed {
+		return errors.New("stats is not of type postponed")
+	}
+
+	dlv, err := d.claimer.Lookup(ctx, batch.ID(e.MessageID), e.Email)
+	if err != nil {
+		return fmt.Errorf("cannot lookup delivery: %w", err)
+	}
+	if err := d.claimer.Postpone(ctx, dlv, e.Outcome.Until()); err != nil {
+		return fmt.Errorf("cannot postpone delivery: %w", err)
+	}
+	return nil
+}
+
 type parseFunc func(ctx context.Context, e stats.Event) error
 
 // handleMsg is the one place the Dispatcher decodes a stat message, so the
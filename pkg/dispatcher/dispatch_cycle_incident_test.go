@@ -20,7 +20,9 @@ package dispatcher
 //     published by later healthy cycles — zero silent loss.
 //
 // The DB latency spike is injected at the seam the builder already exposes
-// (envelope.NewBuilderWith): gateSource serves the first `free` calls
+// (envelope.NewBuilderWith). A Batch's SendingData is loaded once per cycle
+// now, so the spike sits on the one query every Delivery still makes on its
+// own — minting its open token: gateTokens serves the first `free` calls
 // instantly, then blocks until its context dies.
 
 import (
@@ -29,6 +31,7 @@ import (
 	"log/slog"
 	"math"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -49,55 +52,77 @@ import (
 	mailertypes "github.com/kannon-email/kannon/proto/kannon/mailer/types"
 )
 
-var testDB *pgxpool.Pool
-
-func TestMain(m *testing.M) {
-	var purge tests.PurgeFunc
-	var err error
+// The package's Postgres, started by the first test that asks for it and
+// shared by every test after it. A run of the benchmarks alone starts none:
+// they stub the Pool and the source (batch_build_test.go).
+var (
+	testDBOnce  sync.Once
+	testDBPool  *pgxpool.Pool
+	testDBPurge tests.PurgeFunc
+	testDBErr   error
+)
 
-	testDB, purge, err = tests.TestPostgresInit(schema.Schema)
-	if err != nil {
-		slog.Error("could not start test postgres", "err", err)
-		os.Exit(1)
-	}
+func testDB(t testing.TB) *pgxpool.Pool {
+	testDBOnce.Do(func() {
+		testDBPool, testDBPurge, testDBErr = tests.TestPostgresInit(schema.Schema)
+	})
+	require.NoError(t, testDBErr, "could not start test postgres")
+	return testDBPool
+}
 
+func TestMain(m *testing.M) {
 	code := m.Run()
 
-	if err := purge(); err != nil {
-		slog.Error("could not purge test postgres", "err", err)
-		os.Exit(1)
+	if testDBPurge != nil {
+		if err := testDBPurge(); err != nil {
+			slog.Error("could not purge test postgres", "err", err)
+			os.Exit(1)
+		}
 	}
 
 	os.Exit(code)
 }
 
-// gateSource simulates the production DB latency spike inside Build.
-// The first `free` GetSendingData calls return instantly; the next call
+type staticSource struct {
+	data envelope.SendingData
+}
+
+func (s staticSource) GetSendingData(context.Context, batch.ID) (envelope.SendingData, error) {
+	return s.data, nil
+}
+
+// gateTokens simulates the production DB latency spike inside Build.
+// The first `free` CreateOpenToken calls return instantly; the next call
 // blocks until its context dies; every call after that sees a dead parent
-// and fails in microseconds — the original incident burst.
-type gateSource struct {
-	data  envelope.SendingData
+// and fails in microseconds — the original incident burst. Each Delivery
+// of the test's Batch mints its own open token, since its Tracking Policy
+// states nothing and so isolates the Recipient.
+type gateTokens struct {
+	mu    sync.Mutex
 	free  int
 	calls int
 }
 
-func (s *gateSource) GetSendingData(ctx context.Context, _ batch.ID) (envelope.SendingData, error) {
-	s.calls++
-	if s.calls <= s.free {
-		return s.data, nil
-	}
-	<-ctx.Done()
-	return envelope.SendingData{}, ctx.Err()
+func (g *gateTokens) heal() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.free = math.MaxInt
 }
 
-type noopTokens struct{}
-
-func (noopTokens) CreateLinkToken(_ context.Context, _, _, _ string, _ tracking.Mode) (string, error) {
+func (g *gateTokens) CreateLinkToken(_ context.Context, _, _, _ string, _ tracking.Mode) (string, error) {
 	return "tok", nil
 }
 
-func (noopTokens) CreateOpenToken(_ context.Context, _, _ string, _ tracking.Mode) (string, error) {
-	return "tok", nil
+func (g *gateTokens) CreateOpenToken(ctx context.Context, _, _ string, _ tracking.Mode) (string, error) {
+	g.mu.Lock()
+	g.calls++
+	open := g.calls <= g.free
+	g.mu.Unlock()
+	if open {
+		return "tok", nil
+	}
+	<-ctx.Done()
+	return "", ctx.Err()
 }
 
 // recordingPublisher captures the recipient of every published Envelope.
@@ -116,18 +141,25 @@ func (p *recordingPublisher) Publish(_ string, data []byte) error {
 
 func TestDispatchCycle_BudgetDeathMidPage_NoDeliveryLost(t *testing.T) {
 	ctx := t.Context()
-	q := sqlc.New(testDB)
+	q := sqlc.New(testDB(t))
 
 	// --- seed: Domain + transient Template + Batch (same shape as a
 	// SendTemplate call) --------------------------------------------------
-	keys, err := dkim.GenerateDKIMKeysPair()
+	keys, err := dkim.GenerateDKIMKeysPair(dkim.AlgorithmRSA)
 	require.NoError(t, err)
 
 	const domain = "k.incident.test"
-	_, err = q.CreateDomain(ctx, sqlc.CreateDomainParams{
-		Domain:         domain,
-		DkimPrivateKey: keys.PrivateKey,
-		DkimPublicKey:  keys.PublicKey,
+	_, err = q.CreateDomain(ctx, domain)
+	require.NoError(t, err)
+	_, err = q.CreateDKIMKey(ctx, sqlc.CreateDKIMKeyParams{
+		Domain:     domain,
+		Selector:   "kannon",
+		Algorithm:  dkim.AlgorithmRSA,
+		PrivateKey: keys.PrivateKey,
+		PublicKey:  keys.PublicKey,
+		Origin:     "generated",
+		Bits:       dkim.DefaultRSABits,
+		Activate:   true,
 	})
 	require.NoError(t, err)
 
@@ -147,7 +179,7 @@ func TestDispatchCycle_BudgetDeathMidPage_NoDeliveryLost(t *testing.T) {
 		Attachments: batch.Attachments{},
 	})
 	require.NoError(t, err)
-	require.NoError(t, sqlc.NewBatchRepository(testDB).Create(ctx, b))
+	require.NoError(t, sqlc.NewBatchRepository(testDB(t)).Create(ctx, b))
 
 	// --- seed: 50 due Deliveries, validated ('scheduled') ----------------
 	const (
@@ -164,7 +196,7 @@ func TestDispatchCycle_BudgetDeathMidPage_NoDeliveryLost(t *testing.T) {
 	// what this test wants: it asserts that every rescheduled victim is
 	// eventually published, so nothing here may be terminated. The budget's own
 	// boundary is exercised in retry_budget_test.go.
-	repo := sqlc.NewDeliveryRepository(testDB, backoff, delivery.DefaultRetryWindow)
+	repo := sqlc.NewDeliveryRepository(testDB(t), backoff, delivery.DefaultRetryWindow)
 	claimer := pool.NewClaimer(repo)
 
 	ds := make([]*delivery.Delivery, total)
@@ -186,27 +218,27 @@ func TestDispatchCycle_BudgetDeathMidPage_NoDeliveryLost(t *testing.T) {
 	}
 
 	// --- the real dispatcher, with latency injected in Build -------------
-	src := &gateSource{
-		free: accepted,
+	tokens := &gateTokens{free: accepted}
+	src := staticSource{
 		data: envelope.SendingData{
-			Subject:        "incident repro",
-			HTML:           tpl.Html,
-			Domain:         domain,
-			MessageID:      b.ID().String(),
-			SenderEmail:    "noreply@" + domain,
-			SenderAlias:    "Incident",
-			DkimPrivateKey: keys.PrivateKey,
+			Subject:     "incident repro",
+			HTML:        tpl.Html,
+			Domain:      domain,
+			MessageID:   b.ID().String(),
+			SenderEmail: "noreply@" + domain,
+			SenderAlias: "Incident",
+			DkimKeys:    []dkim.SigningKey{{Selector: "kannon", PrivateKey: keys.PrivateKey}},
 		},
 	}
 	pub := &recordingPublisher{}
 	d := &disp{
 		claimer: claimer,
-		eb:      envelope.NewBuilderWith(src, noopTokens{}),
+		eb:      envelope.NewBuilderWith(src, tokens),
 		pub:     pub,
 	}
 
 	// Cycle 1 — the incident cycle. The parent context carries a 1s
-	// deadline, shrinking the per-Delivery budget into test time; gateSource
+	// deadline, shrinking the per-Delivery budget into test time; gateTokens
 	// blocks build #accepted+1 until that deadline fires, and every later
 	// build in the page sees a dead parent.
 	cycleCtx, cancel := context.WithTimeout(ctx, 1*time.Second)
@@ -239,7 +271,7 @@ func TestDispatchCycle_BudgetDeathMidPage_NoDeliveryLost(t *testing.T) {
 		"after the deadline fires, remaining builds must fail fast")
 
 	// --- cycles 2..n: latency gone, drain the pool -----------------------
-	src.free = math.MaxInt // heal the "DB": every later build succeeds
+	tokens.heal() // heal the "DB": every later build succeeds
 
 	// FIX assertion 2 — zero silent loss: every Delivery, including the
 	// rescheduled victims, is eventually published by healthy cycles.
@@ -265,7 +297,7 @@ func TestDispatchCycle_BudgetDeathMidPage_NoDeliveryLost(t *testing.T) {
 func countByStatus(t *testing.T, status string) int {
 	t.Helper()
 	var n int
-	require.NoError(t, testDB.QueryRow(t.Context(),
+	require.NoError(t, testDB(t).QueryRow(t.Context(),
 		`SELECT count(*) FROM sending_pool_emails WHERE status = $1`, status).Scan(&n))
 	return n
 }
@@ -273,7 +305,7 @@ func countByStatus(t *testing.T, status string) int {
 func countRescheduled(t *testing.T) int {
 	t.Helper()
 	var n int
-	require.NoError(t, testDB.QueryRow(t.Context(),
+	require.NoError(t, testDB(t).QueryRow(t.Context(),
 		`SELECT count(*) FROM sending_pool_emails WHERE send_attempts_cnt > 0`).Scan(&n))
 	return n
 }
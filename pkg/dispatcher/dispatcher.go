@@ -3,50 +3,87 @@ package dispatcher
 import (
 	"context"
 	"fmt"
+	"runtime"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/kannon-email/kannon/internal/bodystore"
 	sqlc "github.com/kannon-email/kannon/internal/db"
 	"github.com/kannon-email/kannon/internal/envelope"
 	"github.com/kannon-email/kannon/internal/pool"
+	"github.com/kannon-email/kannon/internal/returnpath"
 	"github.com/kannon-email/kannon/internal/runner"
 	"github.com/kannon-email/kannon/internal/statssec"
 	"github.com/kannon-email/kannon/internal/utils"
+	"github.com/kannon-email/kannon/x/config"
 	"github.com/kannon-email/kannon/x/container"
 
 	"github.com/nats-io/nats.go/jetstream"
 )
 
-// New constructs the dispatcher runnable. The dispatcher has no
-// configurable knobs today, so it does not call config.LoadSection.
+// Config is the "dispatcher" section.
+type Config struct {
+	// MaxInlineBody is the largest Envelope body published whole on
+	// kannon-sending, in bytes; a larger one goes through internal/bodystore.
+	// bodystore.DefaultMaxInline when zero.
+	MaxInlineBody int `mapstructure:"max_inline_body"`
+	// Workers is how many Deliveries of a claimed page are built and
+	// published at once. The number of CPUs when zero: building is mostly
+	// rendering and signing.
+	Workers int `mapstructure:"workers"`
+}
+
+func (c *Config) setDefaults() {
+	if c.MaxInlineBody == 0 {
+		c.MaxInlineBody = bodystore.DefaultMaxInline
+	}
+	if c.Workers <= 0 {
+		c.Workers = runtime.NumCPU()
+	}
+}
+
+// New constructs the dispatcher runnable from its own "dispatcher" section
+// and the "return_path" section it signs bounce addresses with, shared with
+// the SMTPServer that verifies them.
 func New(cnt *container.Container) container.Runnable {
+	var cfg Config
+	config.LoadSection("dispatcher", &cfg)
+	cfg.setDefaults()
+	returnPaths := returnpath.MustLoad()
 	return container.Runnable{
 		Name: "dispatcher",
 		Run: func(ctx context.Context) error {
-			return run(ctx, cnt)
+			return run(ctx, cnt, cfg, returnPaths)
 		},
 	}
 }
 
-func run(ctx context.Context, cnt *container.Container) error {
+func run(ctx context.Context, cnt *container.Container, cfg Config, returnPaths *returnpath.Keyring) error {
 	q := cnt.Queries()
 
 	ss := statssec.NewStatsService(q)
 	claimer := pool.NewClaimer(sqlc.NewDeliveryRepository(cnt.DB(), cnt.BackoffPolicy(), cnt.RetryWindow()))
-	eb := envelope.NewBuilder(q, ss)
+	eb := envelope.NewBuilder(q, ss, envelope.WithReturnPathKeys(returnPaths))
 
 	js := cnt.NatsJetStream()
 	if err := configureSendingStream(ctx, js); err != nil {
 		return fmt.Errorf("cannot configure sending stream: %w", err)
 	}
+	bodies, err := bodystore.OpenNATS(ctx, js)
+	if err != nil {
+		return err
+	}
 
 	d := disp{
-		ss:      ss,
-		claimer: claimer,
-		eb:      eb,
-		pub:     cnt.NatsPublisher(),
-		js:      js,
+		ss:        ss,
+		claimer:   claimer,
+		eb:        eb,
+		pub:       cnt.NatsPublisher(),
+		js:        js,
+		bodies:    bodies,
+		maxInline: cfg.MaxInlineBody,
+		workers:   cfg.Workers,
 	}
 
 	d.log().Info("🚀 Starting dispatcher")
@@ -65,6 +102,10 @@ func run(ctx context.Context, cnt *container.Container) error {
 		return d.handleBounced(ctx)
 	})
 
+	eg.Go(func() error {
+		return d.handlePostponed(ctx)
+	})
+
 	eg.Go(func() error {
 		return runner.Run(ctx, d.DispatchCycle, runner.WaitLoop(1*time.Second))
 	})
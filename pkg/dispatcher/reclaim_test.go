@@ -21,6 +21,7 @@ import (
 	sqlc "github.com/kannon-email/kannon/internal/db"
 	"github.com/kannon-email/kannon/internal/delivery"
 	"github.com/kannon-email/kannon/internal/pool"
+	"github.com/kannon-email/kannon/internal/stats"
 )
 
 // TestReclaimCycle_SendingStrandedPastThreshold_ReturnsToPoolWithAttemptBumped
@@ -37,7 +38,7 @@ func TestReclaimCycle_SendingStrandedPastThreshold_ReturnsToPoolWithAttemptBumpe
 	ctx := t.Context()
 	batchID, domain := seedReclaimBatch(t)
 
-	repo := sqlc.NewDeliveryRepository(testDB, delivery.DefaultBackoff, delivery.DefaultRetryWindow)
+	repo := sqlc.NewDeliveryRepository(testDB(t), delivery.DefaultBackoff, delivery.DefaultRetryWindow)
 	claimer := pool.NewClaimer(repo)
 	d := &disp{claimer: claimer}
 
@@ -59,6 +60,41 @@ func TestReclaimCycle_SendingStrandedPastThreshold_ReturnsToPoolWithAttemptBumpe
 		"a Delivery that is no longer in flight must not claim to be: claimed_at is cleared")
 }
 
+// TestParsePostponed_LeavesSendingWithoutAnAttempt pins the other way a
+// Delivery leaves 'sending' with nothing sent. An Envelope the SMTPSender holds
+// back for hours — a warm-up allowance spent, a destination cooling down — used
+// to wait on the sending stream, where the reclaim found its Delivery past the
+// threshold and published it a second time. Postponed, it waits in the Pool
+// instead, out of the reclaim's reach and with its attempts untouched.
+func TestParsePostponed_LeavesSendingWithoutAnAttempt(t *testing.T) {
+	ctx := t.Context()
+	batchID, domain := seedReclaimBatch(t)
+
+	repo := sqlc.NewDeliveryRepository(testDB(t), delivery.DefaultBackoff, delivery.DefaultRetryWindow)
+	claimer := pool.NewClaimer(repo)
+	d := &disp{claimer: claimer}
+
+	email := "postponed@" + domain
+	claimForDispatch(t, repo, claimer, batchID, domain, email)
+
+	until := time.Now().UTC().Add(6 * time.Hour).Truncate(time.Second)
+	require.NoError(t, d.parsePostponedFunc(ctx, stats.Event{
+		MessageID: batchID.String(),
+		Domain:    domain,
+		Email:     email,
+		Outcome:   stats.Postponed(until, "IP warm-up"),
+	}))
+
+	got := poolRow(t, batchID, email)
+	assert.Equal(t, sqlc.SendingPoolStatusScheduled, got.Status)
+	assert.EqualValues(t, 0, got.SendAttemptsCnt, "nothing was sent, so no attempt was spent")
+	assert.True(t, until.Equal(got.ScheduledTime.Time), "claimed again when the sender said")
+	assert.False(t, got.ClaimedAt.Valid)
+
+	require.NoError(t, d.ReclaimCycle(ctx))
+	assert.EqualValues(t, 0, poolRow(t, batchID, email).SendAttemptsCnt, "the reclaim has nothing to take back")
+}
+
 // TestReclaimCycle_FreshlyClaimedUnderBacklog_IsUntouched is the assertion this
 // whole part exists for. #378 proposed reclaiming on
 // `status='sending' AND scheduled_time < NOW() - INTERVAL '15 minutes'`, but
@@ -72,7 +108,7 @@ func TestReclaimCycle_FreshlyClaimedUnderBacklog_IsUntouched(t *testing.T) {
 	ctx := t.Context()
 	batchID, domain := seedReclaimBatch(t)
 
-	repo := sqlc.NewDeliveryRepository(testDB, delivery.DefaultBackoff, delivery.DefaultRetryWindow)
+	repo := sqlc.NewDeliveryRepository(testDB(t), delivery.DefaultBackoff, delivery.DefaultRetryWindow)
 	claimer := pool.NewClaimer(repo)
 	d := &disp{claimer: claimer}
 
@@ -99,14 +135,10 @@ func TestReclaimCycle_FreshlyClaimedUnderBacklog_IsUntouched(t *testing.T) {
 func seedReclaimBatch(t *testing.T) (batch.ID, string) {
 	t.Helper()
 	ctx := t.Context()
-	q := sqlc.New(testDB)
+	q := sqlc.New(testDB(t))
 
 	domain := fmt.Sprintf("reclaim-%d.test", time.Now().UnixNano())
-	_, err := q.CreateDomain(ctx, sqlc.CreateDomainParams{
-		Domain:         domain,
-		DkimPrivateKey: "test-private",
-		DkimPublicKey:  "test-public",
-	})
+	_, err := q.CreateDomain(ctx, domain)
 	require.NoError(t, err)
 
 	b, err := batch.New(batch.NewParams{
@@ -117,7 +149,7 @@ func seedReclaimBatch(t *testing.T) (batch.ID, string) {
 		Attachments: batch.Attachments{},
 	})
 	require.NoError(t, err)
-	require.NoError(t, sqlc.NewBatchRepository(testDB).Create(ctx, b))
+	require.NoError(t, sqlc.NewBatchRepository(testDB(t)).Create(ctx, b))
 
 	// context.Background(), not t.Context(): the test's context is cancelled
 	// before Cleanup runs, and a fixture row left behind here would show up in
@@ -125,11 +157,11 @@ func seedReclaimBatch(t *testing.T) (batch.ID, string) {
 	t.Cleanup(func() {
 		cleanupCtx := context.Background()
 		//nolint:errcheck // best-effort test cleanup
-		testDB.Exec(cleanupCtx, "DELETE FROM sending_pool_emails WHERE domain = $1", domain)
+		testDB(t).Exec(cleanupCtx, "DELETE FROM sending_pool_emails WHERE domain = $1", domain)
 		//nolint:errcheck // best-effort test cleanup
-		testDB.Exec(cleanupCtx, "DELETE FROM messages WHERE domain = $1", domain)
+		testDB(t).Exec(cleanupCtx, "DELETE FROM messages WHERE domain = $1", domain)
 		//nolint:errcheck // best-effort test cleanup
-		testDB.Exec(cleanupCtx, "DELETE FROM domains WHERE domain = $1", domain)
+		testDB(t).Exec(cleanupCtx, "DELETE FROM domains WHERE domain = $1", domain)
 	})
 
 	return b.ID(), domain
@@ -164,7 +196,7 @@ func claimForDispatch(t *testing.T, repo delivery.Repository, claimer pool.Claim
 // taken that long before now.
 func backdateClaim(t *testing.T, batchID batch.ID, email string, age time.Duration) {
 	t.Helper()
-	_, err := testDB.Exec(t.Context(),
+	_, err := testDB(t).Exec(t.Context(),
 		`UPDATE sending_pool_emails
 		    SET claimed_at = NOW() - make_interval(secs => $1)
 		  WHERE message_id = $2 AND email = $3`,
@@ -176,7 +208,7 @@ func backdateClaim(t *testing.T, batchID batch.ID, email string, age time.Durati
 // backlog the claim path never clears.
 func backdateSchedule(t *testing.T, batchID batch.ID, email string, age time.Duration) {
 	t.Helper()
-	_, err := testDB.Exec(t.Context(),
+	_, err := testDB(t).Exec(t.Context(),
 		`UPDATE sending_pool_emails
 		    SET scheduled_time = NOW() - make_interval(secs => $1),
 		        original_scheduled_time = NOW() - make_interval(secs => $1)
@@ -187,7 +219,7 @@ func backdateSchedule(t *testing.T, batchID batch.ID, email string, age time.Dur
 
 func poolRow(t *testing.T, batchID batch.ID, email string) sqlc.SendingPoolEmail {
 	t.Helper()
-	row, err := sqlc.New(testDB).GetPool(t.Context(), sqlc.GetPoolParams{
+	row, err := sqlc.New(testDB(t)).GetPool(t.Context(), sqlc.GetPoolParams{
 		Email:     email,
 		MessageID: batchID.String(),
 	})
@@ -89,9 +89,9 @@ func TestDispatchCycle_RetryBudgetSpent_FailsInsteadOfReschedulingForever(t *tes
 	require.Less(t, backoff.Delay(1), retryWindow, "fixture arithmetic: the second retry is inside the window")
 	require.Greater(t, backoff.Delay(2), retryWindow, "fixture arithmetic: the third retry is outside it")
 
-	repo := sqlc.NewDeliveryRepository(testDB, backoff, retryWindow)
+	repo := sqlc.NewDeliveryRepository(testDB(t), backoff, retryWindow)
 	claimer := pool.NewClaimer(repo)
-	q := sqlc.New(testDB)
+	q := sqlc.New(testDB(t))
 	pub := &subjectPublisher{}
 	d := &disp{
 		claimer: claimer,
@@ -154,7 +154,7 @@ func TestParseErrors_RetryBudgetSpent_FailsTheDelivery(t *testing.T) {
 
 	// A window narrower than the very first retry: this Delivery has no room
 	// left whatever its attempt count.
-	repo := sqlc.NewDeliveryRepository(testDB, delivery.DefaultBackoff, time.Nanosecond)
+	repo := sqlc.NewDeliveryRepository(testDB(t), delivery.DefaultBackoff, time.Nanosecond)
 	claimer := pool.NewClaimer(repo)
 	pub := &subjectPublisher{}
 	d := &disp{claimer: claimer, pub: pub}
@@ -201,7 +201,7 @@ func scheduleValidated(t *testing.T, repo delivery.Repository, claimer pool.Clai
 func poolRowExists(t *testing.T, batchID batch.ID, email string) bool {
 	t.Helper()
 	var n int
-	require.NoError(t, testDB.QueryRow(t.Context(),
+	require.NoError(t, testDB(t).QueryRow(t.Context(),
 		`SELECT count(*) FROM sending_pool_emails WHERE message_id = $1 AND email = $2`,
 		batchID.String(), email).Scan(&n))
 	return n > 0
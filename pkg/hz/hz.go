@@ -2,22 +2,73 @@ package hz
 
 import (
 	"context"
+	"errors"
 
+	"github.com/kannon-email/kannon/internal/circuit"
+	"github.com/kannon-email/kannon/internal/cooldown"
 	"github.com/kannon-email/kannon/x/container"
 )
 
+// CooldownsCheck is the name a failure to read the cool-downs is reported
+// under, next to the container's checks.
+const CooldownsCheck = "destination-cooldowns"
+
+// ErrNoCircuits is MX circuits that cannot be read, because the process
+// reporting them could not reach the bucket they are kept in.
+var ErrNoCircuits = errors.New("MX circuits are not available")
+
+// CircuitsCheck is the name a failure to read the MX circuits is reported
+// under, next to the container's checks.
+const CircuitsCheck = "mx-circuits"
+
 type HZService interface {
 	HZ(context.Context) HZRes
+	// CoolingDown is how many destinations the SMTPSender is holding mail
+	// back from, none when cool-downs are off. Which ones is the Admin API's
+	// to say: health is not authenticated.
+	CoolingDown(context.Context) (int, error)
+	// Circuits are the MX hosts the SMTPSender is passing over, none when
+	// circuits are off.
+	Circuits(context.Context) ([]circuit.Circuit, error)
 }
 
 type hZService struct {
 	cnt *container.Container
+
+	cooldowns *cooldown.Service
+
+	circuitsOn bool
+	circuits   *circuit.Breaker
+}
+
+// Option configures what the HZ service reports beyond the container's
+// checks.
+type Option func(*hZService)
+
+// WithCooldowns reports how many destinations s has cooling down.
+func WithCooldowns(s *cooldown.Service) Option {
+	return func(h *hZService) {
+		h.cooldowns = s
+	}
+}
+
+// WithCircuits reports the MX circuits b keeps that are not closed. A nil b
+// is circuits that are on and cannot be read: ErrNoCircuits.
+func WithCircuits(b *circuit.Breaker) Option {
+	return func(h *hZService) {
+		h.circuitsOn = true
+		h.circuits = b
+	}
 }
 
-func NewHZ(cnt *container.Container) *hZService {
-	return &hZService{
+func NewHZ(cnt *container.Container, opts ...Option) *hZService {
+	h := &hZService{
 		cnt: cnt,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 type HZRes = container.HZRes
@@ -25,3 +76,20 @@ type HZRes = container.HZRes
 func (h *hZService) HZ(ctx context.Context) HZRes {
 	return h.cnt.HZ(ctx)
 }
+
+func (h *hZService) CoolingDown(ctx context.Context) (int, error) {
+	if h.cooldowns == nil {
+		return 0, nil
+	}
+	return h.cooldowns.Count(ctx)
+}
+
+func (h *hZService) Circuits(ctx context.Context) ([]circuit.Circuit, error) {
+	if !h.circuitsOn {
+		return nil, nil
+	}
+	if h.circuits == nil {
+		return nil, ErrNoCircuits
+	}
+	return h.circuits.List(ctx)
+}
@@ -0,0 +1,270 @@
+package smtp
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/emersion/go-message"
+	"github.com/emersion/go-message/textproto"
+	"github.com/kannon-email/kannon/internal/stats"
+)
+
+// dsnAction is what the reporting MTA did with the message for one recipient,
+// RFC 3464 §2.3.3.
+type dsnAction string
+
+const (
+	actionFailed    dsnAction = "failed"
+	actionDelayed   dsnAction = "delayed"
+	actionDelivered dsnAction = "delivered"
+	actionRelayed   dsnAction = "relayed"
+	actionExpanded  dsnAction = "expanded"
+)
+
+// recipientReport is one per-recipient block of a DSN: the fields of it that
+// say what became of the message, each as the report wrote it, its type
+// prefix stripped where it has one.
+type recipientReport struct {
+	originalRecipient string
+	finalRecipient    string
+	action            dsnAction
+	// status is the RFC 3463 enhanced code, X.Y.Z.
+	status string
+	// diagnostic is the Diagnostic-Code field whole, type included —
+	// "smtp; 550 5.1.1 no such user" — since that is what a Bounce's msg has
+	// always been.
+	diagnostic string
+	remoteMTA  string
+}
+
+// readReports reads the recipient reports out of a received message. An RFC
+// 3464 report — multipart/report with a message/delivery-status part — gives
+// one per recipient block. Anything else, and a report whose status part
+// cannot be read, is read by guessReport from its subject and its text, since
+// plenty of MTAs still send bounces in prose.
+//
+// The entity is consumed.
+func readReports(e *message.Entity) []recipientReport {
+	var text bytes.Buffer
+	var reports []recipientReport
+	_ = e.Walk(func(_ []int, part *message.Entity, err error) error {
+		if err != nil && !message.IsUnknownCharset(err) {
+			return nil
+		}
+		if part.MultipartReader() != nil {
+			return nil
+		}
+		t, _, _ := part.Header.ContentType()
+		switch t {
+		case "message/delivery-status", "message/global-delivery-status":
+			b, _ := io.ReadAll(part.Body)
+			reports = append(reports, parseDeliveryStatus(b)...)
+			text.Write(b)
+		case "text/plain", "":
+			_, _ = io.Copy(&text, part.Body)
+		}
+		text.WriteString("\n")
+		return nil
+	})
+	if len(reports) > 0 {
+		return reports
+	}
+	return []recipientReport{guessReport(e.Header.Get("Subject"), &text)}
+}
+
+// parseDeliveryStatus reads the body of a message/delivery-status part: a
+// per-message block and then one block per recipient, separated by blank
+// lines and each written as header fields. A block with no Action is not a
+// recipient's. Reading stops at the first block that does not parse, keeping
+// the ones before it.
+func parseDeliveryStatus(b []byte) []recipientReport {
+	// The last field is often not followed by a line break before the MIME
+	// boundary, and the header reader drops a line without one.
+	r := bufio.NewReader(io.MultiReader(bytes.NewReader(b), strings.NewReader("\r\n\r\n")))
+	var reports []recipientReport
+	for {
+		if !skipBlankLines(r) {
+			return reports
+		}
+		h, err := textproto.ReadHeader(r)
+		if err != nil {
+			return reports
+		}
+		action := dsnAction(strings.ToLower(strings.TrimSpace(h.Get("Action"))))
+		if action == "" {
+			continue
+		}
+		reports = append(reports, recipientReport{
+			originalRecipient: typedValue(h.Get("Original-Recipient")),
+			finalRecipient:    typedValue(h.Get("Final-Recipient")),
+			action:            action,
+			status:            enhancedStatusReg.FindString(h.Get("Status")),
+			diagnostic:        strings.TrimSpace(h.Get("Diagnostic-Code")),
+			remoteMTA:         typedValue(h.Get("Remote-MTA")),
+		})
+	}
+}
+
+// skipBlankLines reads past the blank lines before the next block, reporting
+// false when there is none.
+func skipBlankLines(r *bufio.Reader) bool {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return false
+		}
+		if b[0] != '\r' && b[0] != '\n' {
+			return true
+		}
+		_, _ = r.ReadByte()
+	}
+}
+
+// typedValue is a DSN field's value without the type it is prefixed with —
+// "rfc822; user@example.com", "dns; mx.example.com".
+func typedValue(v string) string {
+	if _, after, ok := strings.Cut(v, ";"); ok {
+		v = after
+	}
+	return strings.TrimSpace(v)
+}
+
+var (
+	enhancedStatusReg = regexp.MustCompile(`\b[245]\.[0-9]{1,3}\.[0-9]{1,3}\b`)
+	replyCodeReg      = regexp.MustCompile(`^\s*([245][0-9]{2})\b`)
+
+	guessFieldReg = regexp.MustCompile(`(?i)^\s*(action|status|diagnostic-code|remote-mta|final-recipient):\s*(.*)$`)
+	guessReplyReg = regexp.MustCompile(`(?i)(?:said:|^)\s*([45][0-9]{2})[ -](.*)$`)
+	guessDelayReg = regexp.MustCompile(`(?i)still (?:being )?retr|will (?:continue|keep) (?:to )?try|not yet been delivered|delivery (?:status notification \()?delay`)
+)
+
+// guessReport reads a bounce that is not an RFC 3464 report by what it says:
+// the DSN fields it quotes in its text, else the first SMTP reply it quotes,
+// and — unless it quotes an Action — a delay by the way the common MTAs word
+// one, in its subject or its text. A bounce that says nothing recognisable is
+// a failure with 550: it was sent to a bounce address, and treating it as
+// anything milder would lose the only report there may be.
+func guessReport(subject string, text io.Reader) recipientReport {
+	rep := recipientReport{action: actionFailed}
+	var quotedAction, delayed bool
+	var reply string
+	scanner := bufio.NewScanner(text)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := guessFieldReg.FindStringSubmatch(line); m != nil {
+			v := strings.TrimSpace(m[2])
+			switch strings.ToLower(m[1]) {
+			case "action":
+				if !quotedAction {
+					rep.action, quotedAction = dsnAction(strings.ToLower(v)), true
+				}
+			case "status":
+				if rep.status == "" {
+					rep.status = enhancedStatusReg.FindString(v)
+				}
+			case "diagnostic-code":
+				if rep.diagnostic == "" {
+					rep.diagnostic = v
+				}
+			case "remote-mta":
+				if rep.remoteMTA == "" {
+					rep.remoteMTA = typedValue(v)
+				}
+			case "final-recipient":
+				if rep.finalRecipient == "" {
+					rep.finalRecipient = typedValue(v)
+				}
+			}
+			continue
+		}
+		if reply == "" && guessReplyReg.MatchString(line) {
+			m := guessReplyReg.FindStringSubmatch(line)
+			reply = m[1] + " " + strings.TrimSpace(m[2])
+		}
+		delayed = delayed || guessDelayReg.MatchString(line)
+	}
+	if rep.diagnostic == "" && reply != "" {
+		rep.diagnostic = "smtp; " + reply
+	}
+	if !quotedAction && (delayed || guessDelayReg.MatchString(subject)) {
+		rep.action = actionDelayed
+	}
+	return rep
+}
+
+// code is the SMTP reply code the report gives: the diagnostic's when it is an
+// SMTP reply, else the class of its status — 550, 450 or 250 — and 550 when it
+// has neither.
+func (r recipientReport) code() int {
+	if m := replyCodeReg.FindStringSubmatch(typedValue(r.diagnostic)); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code
+	}
+	switch {
+	case strings.HasPrefix(r.status, "4."):
+		return 450
+	case strings.HasPrefix(r.status, "2."):
+		return 250
+	}
+	return 550
+}
+
+// msg is what the report says went wrong, in the words of the remote MTA
+// where it quotes them.
+func (r recipientReport) msg() string {
+	switch {
+	case r.diagnostic != "":
+		return r.diagnostic
+	case r.status != "":
+		return "status " + r.status
+	}
+	return "unknown error"
+}
+
+// session is what the report says of the remote MTA's session.
+func (r recipientReport) session() stats.SMTPSession {
+	return stats.SMTPSession{MXHost: r.remoteMTA, EnhancedCode: r.status}
+}
+
+// names reports whether the block is about email, under the address it was
+// sent to or the one it was finally offered to.
+func (r recipientReport) names(email string) bool {
+	return strings.EqualFold(r.originalRecipient, email) || strings.EqualFold(r.finalRecipient, email)
+}
+
+// reportFor picks the block that reports on email among those a DSN sent to
+// its bounce address carries. Kannon sends each recipient a message of its
+// own, so there is normally one; a forwarding MTA may name addresses of its
+// own instead, in which case the block that matters most stands for the
+// recipient — a failure over a delay over a delivery — so that one DSN is
+// never counted twice.
+func reportFor(email string, reports []recipientReport) recipientReport {
+	for _, r := range reports {
+		if r.names(email) {
+			return r
+		}
+	}
+	best := reports[0]
+	for _, r := range reports[1:] {
+		if r.action.weight() > best.action.weight() {
+			best = r
+		}
+	}
+	return best
+}
+
+// weight ranks actions for reportFor. An action RFC 3464 does not name is
+// read as a failure, as every DSN was before actions were read at all.
+func (a dsnAction) weight() int {
+	switch a {
+	case actionDelivered, actionRelayed, actionExpanded:
+		return 0
+	case actionDelayed:
+		return 1
+	}
+	return 2
+}
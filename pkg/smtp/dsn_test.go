@@ -0,0 +1,167 @@
+package smtp
+
+import (
+	"strings"
+	"testing"
+
+	st "github.com/kannon-email/kannon/proto/kannon/stats/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// otherReturnPath encodes other@test.com in batch msg_test01 on domain k.test.com.
+const otherReturnPath = "bump_b3RoZXJAdGVzdC5jb20=+msg_test01@k.test.com"
+
+// multiDSN is a report on two of Kannon's messages sent to both of their
+// bounce addresses in one transaction, in CRLF as it arrives and with no line
+// break between the last field and the boundary.
+var multiDSN = strings.ReplaceAll(`From: MAILER-DAEMON@mx.example.com
+Subject: Undelivered Mail Returned to Sender
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=delivery-status; boundary="B"
+
+--B
+Content-Type: text/plain
+
+This is the mail system at host mx.example.com.
+
+--B
+Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mx.example.com
+Arrival-Date: Mon, 19 Oct 2026 12:00:00 +0000
+
+Final-Recipient: rfc822; other@test.com
+Original-Recipient: rfc822;other@test.com
+Action: delayed
+Status: 4.4.1
+Remote-MTA: dns; mx2.test.com
+Diagnostic-Code: X-Postfix; connect to mx2.test.com[192.0.2.2]:25:
+    Connection timed out
+
+Final-Recipient: rfc822; test@test.com
+Action: failed
+Status: 5.1.1
+Remote-MTA: dns; mx1.test.com
+Diagnostic-Code: smtp; 550 5.1.1 <test@test.com>: Recipient address
+    rejected: User unknown
+--B
+Content-Type: text/rfc822-headers
+
+Subject: hello
+--B--
+`, "\n", "\r\n")
+
+func TestDataReportsEveryRecipientOfADSN(t *testing.T) {
+	pub := &capturingPublisher{}
+	s := &Session{nc: pub}
+	require.NoError(t, s.Rcpt(otherReturnPath, nil))
+	require.NoError(t, s.Rcpt(bounceReturnPath, nil))
+
+	require.NoError(t, s.Data(strings.NewReader(multiDSN)))
+	require.Equal(t, []string{"kannon.stats.delayed", "kannon.stats.bounced"}, pub.subjects,
+		"every bounce address the DSN was sent to is reported on")
+
+	var delayed, bounced st.Stats
+	require.NoError(t, proto.Unmarshal(pub.payloads[0], &delayed))
+	require.NoError(t, proto.Unmarshal(pub.payloads[1], &bounced))
+
+	assert.Equal(t, "other@test.com", delayed.Email)
+	d := delayed.Data.GetDelayed()
+	require.NotNil(t, d, "a delay is not a Bounce")
+	assert.Equal(t, uint32(450), d.Code, "a diagnostic that is no SMTP reply takes the class of the status")
+	assert.Equal(t, "X-Postfix; connect to mx2.test.com[192.0.2.2]:25: Connection timed out", d.Msg)
+	assert.Equal(t, "mx2.test.com", d.Smtp.GetMxHost())
+	assert.Equal(t, "4.4.1", d.Smtp.GetEnhancedCode())
+
+	assert.Equal(t, "test@test.com", bounced.Email)
+	b := bounced.Data.GetBounced()
+	require.NotNil(t, b)
+	assert.True(t, b.Permanent)
+	assert.Equal(t, uint32(550), b.Code)
+	assert.Equal(t, "smtp; 550 5.1.1 <test@test.com>: Recipient address rejected: User unknown", b.Msg)
+	assert.Equal(t, "mx1.test.com", b.Smtp.GetMxHost())
+	assert.Equal(t, "5.1.1", b.Smtp.GetEnhancedCode())
+}
+
+// A report of delivery is recorded as what it is, and never as Delivered: the
+// Delivery was counted Delivered at handoff already, and the Dispatcher would
+// take a second Delivered for the Pool's.
+func TestDataReportsADeliveryReport(t *testing.T) {
+	for _, action := range []string{"delivered", "relayed", "expanded"} {
+		pub := &capturingPublisher{}
+		s := &Session{To: []string{bounceReturnPath}, nc: pub}
+
+		report := strings.Replace(dsn("Status: 2.0.0\nDiagnostic-Code: smtp; 250 2.0.0 OK"), "Action: failed", "Action: "+action, 1)
+		require.NoError(t, s.Data(strings.NewReader(report)))
+		require.Equal(t, []string{"kannon.stats.reported"}, pub.subjects, action)
+		r := pub.lastStat(t).Data.GetReported()
+		require.NotNil(t, r, action)
+		assert.Equal(t, action, r.Action)
+		assert.Equal(t, "2.0.0", r.Smtp.GetEnhancedCode())
+	}
+}
+
+func TestDataGuessesNonStandardBounces(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		subject string
+		code    uint32
+	}{
+		{
+			"a prose bounce",
+			"Subject: failure notice\n\nHi. This is the qmail-send program.\n<test@test.com>:\n192.0.2.1 does not like recipient.\nRemote host said: 550 5.1.1 mailbox unavailable\n",
+			"kannon.stats.bounced",
+			550,
+		},
+		{
+			"a prose delay",
+			"Subject: Delayed Mail (still being retried)\n\nYour message could not be delivered for 4 hours.\nIt will be retried until it is 5 days old.\n",
+			"kannon.stats.delayed",
+			550,
+		},
+		{
+			"a delay quoting its reply",
+			"Subject: Delivery Status Notification (Delay)\n\nmx.test.com said: 451 4.7.1 greylisted, try again later\n",
+			"kannon.stats.delayed",
+			451,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &capturingPublisher{}
+			s := &Session{To: []string{bounceReturnPath}, nc: pub}
+
+			require.NoError(t, s.Data(strings.NewReader(tt.message)))
+			require.Equal(t, []string{tt.subject}, pub.subjects)
+			m := pub.lastStat(t)
+			if d := m.Data.GetDelayed(); d != nil {
+				assert.Equal(t, tt.code, d.Code)
+			} else {
+				assert.Equal(t, tt.code, m.Data.GetBounced().GetCode())
+			}
+		})
+	}
+}
+
+func TestReportForPicksTheRecipientsBlock(t *testing.T) {
+	failed := recipientReport{finalRecipient: "a@test.com", action: actionFailed}
+	delayed := recipientReport{finalRecipient: "b@test.com", action: actionDelayed}
+	delivered := recipientReport{originalRecipient: "c@test.com", finalRecipient: "c@forward.test", action: actionDelivered}
+	reports := []recipientReport{delivered, delayed, failed}
+
+	assert.Equal(t, delayed, reportFor("B@test.com", reports))
+	assert.Equal(t, delivered, reportFor("c@test.com", reports), "the original recipient names it too")
+	assert.Equal(t, failed, reportFor("forwarded@test.com", reports), "a failure stands for a recipient no block names")
+}
+
+func TestResetForgetsTheRecipients(t *testing.T) {
+	s := &Session{nc: &capturingPublisher{}}
+	require.NoError(t, s.Mail("mailer-daemon@mx.example.com", nil))
+	require.NoError(t, s.Rcpt(bounceReturnPath, nil))
+	s.Reset()
+	assert.Empty(t, s.From)
+	assert.Empty(t, s.To)
+}
@@ -7,27 +7,33 @@ import (
 	"time"
 
 	"github.com/emersion/go-smtp"
+	"github.com/kannon-email/kannon/internal/returnpath"
 	"github.com/kannon-email/kannon/x/config"
 	"github.com/kannon-email/kannon/x/container"
 	"github.com/nats-io/nats.go"
 )
 
 // New constructs the SMTP server runnable, loading its slice of configuration
-// from viper under the "smtp" key.
+// from viper under the "smtp" key, and the keys it verifies bounce addresses
+// with from "return_path".
 func New(cnt *container.Container) container.Runnable {
 	var cfg Config
 	config.LoadSection("smtp", &cfg)
 	cfg.setDefaults()
+	returnPaths := returnpath.MustLoad()
+	if !returnPaths.Enabled() {
+		slog.Warn("return_path.keys is empty: bounce addresses are not signed, and any bounce this server receives is believed")
+	}
 	return container.Runnable{
 		Name: "smtp",
 		Run: func(ctx context.Context) error {
-			return run(ctx, cnt.Nats(), cfg)
+			return run(ctx, cnt.Nats(), cfg, returnPaths)
 		},
 	}
 }
 
-func run(ctx context.Context, nc *nats.Conn, config Config) error {
-	s := buildServer(config, nc)
+func run(ctx context.Context, nc *nats.Conn, config Config, returnPaths *returnpath.Keyring) error {
+	s := buildServer(config, nc, returnPaths)
 	defer s.Close()
 
 	slog.Info(fmt.Sprintf("Starting server at: %v", s.Addr))
@@ -46,9 +52,10 @@ func run(ctx context.Context, nc *nats.Conn, config Config) error {
 	return s.ListenAndServe()
 }
 
-func buildServer(config Config, nc *nats.Conn) *smtp.Server {
+func buildServer(config Config, nc *nats.Conn, returnPaths *returnpath.Keyring) *smtp.Server {
 	backend := &Backend{
-		nc: nc,
+		nc:          nc,
+		returnPaths: returnPaths,
 	}
 
 	s := smtp.NewServer(backend)
@@ -1,17 +1,15 @@
 package smtp
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"log/slog"
-	"net/mail"
-	"regexp"
-	"strconv"
 	"time"
 
+	"github.com/emersion/go-message"
 	"github.com/emersion/go-smtp"
 	"github.com/kannon-email/kannon/internal/publisher"
+	"github.com/kannon-email/kannon/internal/returnpath"
 	"github.com/kannon-email/kannon/internal/stats"
 	"github.com/kannon-email/kannon/internal/utils"
 )
@@ -21,21 +19,29 @@ import (
 // The bounce feed is held as a publisher.Publisher rather than a *nats.Conn
 // (which satisfies it) so the subject a DSN lands on is observable in a test —
 // see #376, where it silently drifted onto one nobody consumed.
+//
+// returnPaths verifies the bounce address each RCPT names; nil accepts them all,
+// which is what a Kannon that does not sign them has to do.
 type Backend struct {
-	nc publisher.Publisher
+	nc          publisher.Publisher
+	returnPaths *returnpath.Keyring
 }
 
 func (bkd *Backend) NewSession(_ *smtp.Conn) (smtp.Session, error) {
 	return &Session{
-		nc: bkd.nc,
+		nc:          bkd.nc,
+		returnPaths: bkd.returnPaths,
 	}, nil
 }
 
-// A Session is returned after EHLO.
+// A Session is returned after EHLO. To holds every bounce address the
+// transaction was accepted for: an MTA reporting on several of Kannon's
+// messages at once may send one DSN to all of their bounce addresses.
 type Session struct {
-	From string
-	To   string
-	nc   publisher.Publisher
+	From        string
+	To          []string
+	nc          publisher.Publisher
+	returnPaths *returnpath.Keyring
 }
 
 func (s *Session) AuthPlain(username, password string) error {
@@ -48,49 +54,90 @@ func (s *Session) Mail(from string, opts *smtp.MailOptions) error {
 	return nil
 }
 
+// errReturnPathRefused is the answer to a bounce address that does not verify.
+// A 550 and not a 4xx: no retry will make a forged or expired address good, so
+// the sender should give up on it now rather than try again for days. The
+// reason is logged and not sent, since telling a forger which check failed
+// only helps the next attempt.
+var errReturnPathRefused = &smtp.SMTPError{
+	Code:         550,
+	EnhancedCode: smtp.EnhancedCode{5, 7, 1},
+	Message:      "Bounce address not recognised",
+}
+
+// Rcpt refuses a bounce address Kannon did not sign, before DATA: a forged
+// bounce must not reach the point where it is published as a Bounced stat, and
+// refusing the recipient costs neither side the body.
 func (s *Session) Rcpt(to string, opts *smtp.RcptOptions) error {
-	s.To = to
+	if err := s.returnPaths.Verify(to, time.Now()); err != nil {
+		slog.Warn("refusing bounce address", "err", err)
+		return errReturnPathRefused
+	}
+	s.To = append(s.To, to)
 	return nil
 }
 
+// Data reads the DSN and reports what it says of each bounce address the
+// transaction was for: Bounced for a recipient the remote MTA failed, Delayed
+// for one it is still trying, and Reported for one it delivered, relayed or
+// expanded. Reported is never Delivered: the Delivery was already counted
+// Delivered at handoff, and counting it again would inflate the delivery rate,
+// so the report is recorded as what the next hop said, and ends nothing.
 func (s *Session) Data(r io.Reader) error {
-	emailmsg, err := mail.ReadMessage(r)
-	if err != nil {
+	e, err := message.Read(r)
+	if err != nil && !message.IsUnknownCharset(err) {
 		return err
 	}
+	reports := readReports(e)
+	for _, to := range s.To {
+		s.report(to, reports)
+	}
+	return nil
+}
 
-	email, messageID, domain, found, err := utils.ParseBounceReturnPath(s.To)
+// report publishes what reports say of the Delivery whose bounce address is
+// to. An address that is not a bounce address is not Kannon's to report on.
+func (s *Session) report(to string, reports []recipientReport) {
+	email, messageID, domain, found, err := utils.ParseBounceReturnPath(to)
 	if err != nil {
 		slog.Warn(fmt.Sprintf("Error parsing bounce return path: %s", err))
-		return nil
+		return
 	}
-
 	if !found {
-		return nil
+		return
 	}
 
-	code, errMsg := parseCode(emailmsg.Body)
+	rep := reportFor(email, reports)
+	code := rep.code()
+	var outcome stats.Outcome
+	switch rep.action {
+	case actionDelivered, actionRelayed, actionExpanded:
+		outcome = stats.Reported(string(rep.action)).WithSMTP(rep.session())
+		slog.Info(fmt.Sprintf("[📬 got delivery report] %v - %s", utils.ObfuscateEmail(email), rep.action),
+			"message_id", messageID, "remote_mta", rep.remoteMTA, "status", rep.status)
+	case actionDelayed:
+		outcome = stats.Delayed(uint32(code), rep.msg()).WithSMTP(rep.session())
+		slog.Info(fmt.Sprintf("[⏳ got delay report] %v - %d - %s", utils.ObfuscateEmail(email), code, rep.msg()))
+	default:
+		outcome = stats.Bounced(isPermanentCode(code), uint32(code), rep.msg()).WithSMTP(rep.session())
+		slog.Info(fmt.Sprintf("[🤷 got bounce] %v - %d - %s", utils.ObfuscateEmail(email), code, rep.msg()))
+	}
 
 	event := stats.Event{
 		MessageID: messageID,
 		Email:     email,
 		Timestamp: time.Now(),
 		Domain:    domain,
-		Outcome:   stats.Bounced(isPermanentCode(code), uint32(code), errMsg),
+		Outcome:   outcome,
 	}
 
-	slog.Info(fmt.Sprintf("[🤷 got bounce] %vs - %d - %s", utils.ObfuscateEmail(email), code, errMsg))
-
 	// PublishStat derives the subject from the Outcome, so an asynchronous
 	// bounce lands on the same kannon.stats.bounced as a synchronous one.
 	// Naming the subject by hand here is what put these events on a topic no
 	// consumer subscribed to (#376).
 	if err := publisher.PublishStat(s.nc, event); err != nil {
 		slog.Error("Cannot publish data", "err", err)
-		return nil
 	}
-
-	return nil
 }
 
 // isPermanentCode classifies a DSN diagnostic code by its SMTP reply class,
@@ -103,29 +150,13 @@ func isPermanentCode(code int) bool {
 	return code >= 500 && code < 600
 }
 
-func (s *Session) Reset() {}
+// Reset forgets the transaction, so that the next one's DSN is not reported
+// against this one's bounce addresses.
+func (s *Session) Reset() {
+	s.From = ""
+	s.To = nil
+}
 
 func (s *Session) Logout() error {
 	return nil
 }
-
-var parseMessageReg = regexp.MustCompile(`^Diagnostic-Code: (SMTP; ([0-9]+) .*$)`)
-
-func parseCode(msg io.Reader) (int, string) {
-	scanner := bufio.NewScanner(msg)
-	for scanner.Scan() {
-		line := scanner.Text()
-		m := parseMessageReg.FindStringSubmatch(line)
-		if m == nil {
-			continue
-		}
-		msg := m[1]
-		code, err := strconv.Atoi(m[2])
-		if err != nil {
-			continue
-		}
-		return code, msg
-	}
-
-	return 550, "unknown error"
-}
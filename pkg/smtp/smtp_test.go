@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/emersion/go-smtp"
+	"github.com/kannon-email/kannon/internal/returnpath"
 	st "github.com/kannon-email/kannon/proto/kannon/stats/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -34,9 +37,10 @@ Diagnostic-Code: SMTP; 550 No such recipient
 
 ------33D00BEF02924490820158550AD524A1--`
 
-	code, msg := parseCode(strings.NewReader(msg))
-	assert.Equal(t, 550, code)
-	assert.Equal(t, "SMTP; 550 No such recipient", msg)
+	rep := guessReport("", strings.NewReader(msg))
+	assert.Equal(t, actionFailed, rep.action)
+	assert.Equal(t, 550, rep.code())
+	assert.Equal(t, "SMTP; 550 No such recipient", rep.msg())
 }
 
 // bounceReturnPath encodes test@test.com in batch msg_test01 on domain k.test.com.
@@ -83,7 +87,7 @@ func (p *capturingPublisher) lastStat(t *testing.T) *st.Stats {
 // (#376).
 func TestDataPublishesAsyncBounceOnBouncedSubject(t *testing.T) {
 	pub := &capturingPublisher{}
-	s := &Session{To: bounceReturnPath, nc: pub}
+	s := &Session{To: []string{bounceReturnPath}, nc: pub}
 
 	require.NoError(t, s.Data(strings.NewReader(dsn("Diagnostic-Code: SMTP; 550 No such recipient"))))
 
@@ -100,6 +104,20 @@ func TestDataPublishesAsyncBounceOnBouncedSubject(t *testing.T) {
 	assert.Equal(t, "SMTP; 550 No such recipient", bounced.Msg)
 }
 
+// A bounce addressed to a Domain's return-path domain is attributed to the
+// Domain that sent the mail, not to the subdomain it arrived at.
+func TestDataAttributesAReturnPathDomainBounceToTheDomain(t *testing.T) {
+	pub := &capturingPublisher{}
+	s := &Session{To: []string{"bump_dGVzdEB0ZXN0LmNvbQ==+msg_test01=k.test.com@bounces.k.test.com"}, nc: pub}
+
+	require.NoError(t, s.Data(strings.NewReader(dsn("Diagnostic-Code: SMTP; 550 No such recipient"))))
+
+	m := pub.lastStat(t)
+	assert.Equal(t, "test@test.com", m.Email)
+	assert.Equal(t, "msg_test01@k.test.com", m.MessageId)
+	assert.Equal(t, "k.test.com", m.Domain)
+}
+
 // The permanent flag follows the SMTP reply class instead of being asserted
 // unconditionally: a 4xx DSN means the remote MTA gave up after its own
 // retries, which is terminal for us but no proof the address is dead.
@@ -118,7 +136,7 @@ func TestAsyncBouncePermanentFollowsReplyClass(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			pub := &capturingPublisher{}
-			s := &Session{To: bounceReturnPath, nc: pub}
+			s := &Session{To: []string{bounceReturnPath}, nc: pub}
 
 			require.NoError(t, s.Data(strings.NewReader(dsn(tt.diagnostic))))
 
@@ -133,13 +151,53 @@ func TestAsyncBouncePermanentFollowsReplyClass(t *testing.T) {
 // A message whose recipient is not a bounce return path is not ours to report.
 func TestDataIgnoresNonBounceRecipient(t *testing.T) {
 	pub := &capturingPublisher{}
-	s := &Session{To: "someone@example.com", nc: pub}
+	s := &Session{To: []string{"someone@example.com"}, nc: pub}
 
 	require.NoError(t, s.Data(strings.NewReader(dsn("Diagnostic-Code: SMTP; 550 No such recipient"))))
 
 	assert.Empty(t, pub.subjects, "no stat should be published")
 }
 
+func signingKeyring(t *testing.T, acceptUnsigned bool) *returnpath.Keyring {
+	t.Helper()
+	k, err := returnpath.New(returnpath.Config{
+		Keys:           []returnpath.KeyConfig{{ID: "1", Secret: "0123456789abcdef"}},
+		AcceptUnsigned: acceptUnsigned,
+	})
+	require.NoError(t, err)
+	return k
+}
+
+// A signed bounce address is accepted at RCPT, and an unsigned or forged one
+// is refused there with a 550, before the DSN it would carry is ever read.
+func TestRcptVerifiesTheBounceAddress(t *testing.T) {
+	k := signingKeyring(t, false)
+	s := &Session{nc: &capturingPublisher{}, returnPaths: k}
+
+	tag := k.Tag("test@test.com", "msg_test01@k.test.com", time.Now())
+	signed := "bump_dGVzdEB0ZXN0LmNvbQ==." + tag + "+msg_test01@k.test.com"
+	require.NoError(t, s.Rcpt(signed, nil))
+	assert.Equal(t, []string{signed}, s.To)
+
+	for _, to := range []string{
+		bounceReturnPath,
+		"bump_dGVzdEB0ZXN0LmNvbQ==.1000aaaaaaaaaaaaaaaa+msg_test01@k.test.com",
+	} {
+		err := s.Rcpt(to, nil)
+		var smtpErr *smtp.SMTPError
+		require.ErrorAs(t, err, &smtpErr, to)
+		assert.Equal(t, 550, smtpErr.Code)
+	}
+	assert.Equal(t, []string{signed}, s.To, "a refused RCPT must not become the Session's recipient")
+}
+
+// During a migration the unsigned form is still accepted, so mail sent before
+// signing was turned on can still bounce.
+func TestRcptAcceptsUnsignedWhenMigrating(t *testing.T) {
+	s := &Session{nc: &capturingPublisher{}, returnPaths: signingKeyring(t, true)}
+	require.NoError(t, s.Rcpt(bounceReturnPath, nil))
+}
+
 func TestIsPermanentCode(t *testing.T) {
 	assert.True(t, isPermanentCode(500))
 	assert.True(t, isPermanentCode(550))
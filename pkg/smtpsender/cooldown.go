@@ -0,0 +1,133 @@
+package smtpsender
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/bounce"
+	"github.com/kannon-email/kannon/internal/cooldown"
+	"github.com/kannon-email/kannon/internal/smtp"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// mustGetCooldowns opens the cool-downs' key/value bucket, exiting on failure
+// the way mustGetThrottle does: a destination that keeps being offered mail
+// while it throttles is the reputation damage cool-downs are on by default to
+// prevent.
+func mustGetCooldowns(ctx context.Context, js jetstream.JetStream, cfg cooldown.Config) cooldown.Store {
+	store, err := cooldown.OpenNATS(ctx, js, cfg)
+	if err != nil {
+		slog.Error("cannot create cool-down bucket", "bucket", cooldown.Bucket, "err", err)
+		os.Exit(1)
+	}
+
+	slog.Info("destination cool-downs ready", "bucket", cooldown.Bucket)
+	return store
+}
+
+// coolingDownError is an Envelope held back because its destination is
+// cooling down, to come back once the cool-down is over: on the stream for
+// the first minutes of one, postponed in the Pool for what is left of a long
+// one (holdBack). Either way no attempt of the Delivery is spent on it.
+type coolingDownError struct {
+	destination string
+	wait        time.Duration
+}
+
+func (e *coolingDownError) Error() string {
+	return fmt.Sprintf("destination %s is cooling down after a throttling reply, retrying in %v", e.destination, e.wait.Round(time.Second))
+}
+
+func (e *coolingDownError) retryIn(uint64) time.Duration { return e.wait }
+
+func (e *coolingDownError) reason() string { return "destination cooling down" }
+
+// cooldownJitterMin is the least the Envelopes held back by one cool-down are
+// spread over when it ends. They are spread over a quarter of what was left of
+// it otherwise, so that a destination coming out of an hour's cool-down is not
+// offered an hour's mail in the same second.
+const cooldownJitterMin = 5 * time.Second
+
+// holdForCooldown holds the Envelope back while its destination is cooling
+// down, and returns the destination, empty when cool-downs are off. It comes
+// before the throttle, so that an Envelope held back takes no connection slot
+// and no place in a minute.
+//
+// Like the throttle, a cool-down fails open: an Envelope is offered when its
+// destination's cool-down cannot be read. What that costs is one more
+// deferral from a provider that is throttling, which the next reply records.
+func (s *smtpSender) holdForCooldown(ctx context.Context, to string) (string, error) {
+	if s.cooldowns == nil {
+		return "", nil
+	}
+	at := strings.LastIndexByte(to, '@')
+	if at < 0 {
+		return "", nil
+	}
+
+	dest := s.destinations.resolve(ctx, to[at+1:]).name
+	c, err := s.cooldowns.Get(ctx, dest)
+	if err != nil {
+		slog.Error("destination cool-downs unavailable, sending anyway", "destination", dest, "err", err)
+		return dest, nil
+	}
+	if wait := c.Remaining(s.now()); wait > 0 {
+		return dest, &coolingDownError{destination: dest, wait: jitter(wait, max(wait/4, cooldownJitterMin))}
+	}
+	return dest, nil
+}
+
+// noteThrottling puts dest into a cool-down when the send failed with a
+// throttling reply. The Envelope that received the reply has made its
+// attempt, and is retried as any deferred Envelope is; it is the ones behind
+// it that the cool-down holds back.
+func (s *smtpSender) noteThrottling(ctx context.Context, dest string, sendErr smtp.SenderError, sent smtp.Sent) {
+	if s.cooldowns == nil || dest == "" {
+		return
+	}
+	reply, ok := throttlingReply(s.classifier, sendErr, sent.Transcript)
+	if !ok {
+		return
+	}
+	c, err := s.cooldowns.Throttled(ctx, dest, reply)
+	if err != nil {
+		slog.Error("cannot record destination cool-down", "destination", dest, "err", err)
+		return
+	}
+	slog.Warn("destination throttled, cooling down",
+		"destination", dest, "mx", reply.MX, "code", reply.Code, "level", c.Level, "until", c.Until)
+}
+
+// throttlingReply is the reply a send failed with, when it is a provider
+// throttling the address it came from: a transient reply classified
+// rate_limited by the same rules the stats classify Bounces by.
+//
+// A mailbox's own rate is not the destination's: Gmail's 4.2.1 "receiving
+// mail at a rate that prevents additional messages" is about one recipient,
+// and holding back the rest of Gmail for it would delay every other
+// recipient's mail for nothing. The X.2.* subject, the mailbox's status, is
+// left out for that reason.
+func throttlingReply(classifier *bounce.Classifier, sendErr smtp.SenderError, t smtp.Transcript) (cooldown.Reply, bool) {
+	if sendErr.IsPermanent() {
+		return cooldown.Reply{}, false
+	}
+	text := t.Reply
+	if text == "" {
+		text = sendErr.Error()
+	}
+	code := t.EnhancedCode
+	if code == "" {
+		code = bounce.EnhancedCodeOf(text)
+	}
+	if strings.HasPrefix(code, "4.2.") {
+		return cooldown.Reply{}, false
+	}
+	if classifier.Classify(code, text) != bounce.CategoryRateLimited {
+		return cooldown.Reply{}, false
+	}
+	return cooldown.Reply{Code: code, Text: text, MX: t.MX}, true
+}
@@ -0,0 +1,156 @@
+package smtpsender
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/bounce"
+	"github.com/kannon-email/kannon/internal/cooldown"
+	"github.com/kannon-email/kannon/internal/smtp"
+	"github.com/kannon-email/kannon/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottlingReplies(t *testing.T) {
+	classifier := bounce.Default()
+	cases := []struct {
+		name       string
+		permanent  bool
+		transcript smtp.Transcript
+		throttling bool
+	}{
+		{
+			name:       "Gmail rate limit",
+			transcript: smtp.Transcript{EnhancedCode: "4.7.28", Reply: "421-4.7.28 Gmail has detected an unusual rate of unsolicited mail originating from your IP address"},
+			throttling: true,
+		},
+		{
+			name:       "Outlook server busy",
+			transcript: smtp.Transcript{EnhancedCode: "4.7.500", Reply: "451 4.7.500 Server busy. Please try again later from [203.0.113.7]. (S77719)"},
+			throttling: true,
+		},
+		{
+			name:       "Yahoo deferral by words alone",
+			transcript: smtp.Transcript{EnhancedCode: "4.0.0", Reply: "421 4.7.0 [TSS04] Messages from 203.0.113.7 temporarily deferred due to unexpected volume or user complaints"},
+			throttling: true,
+		},
+		{
+			name:       "one mailbox receiving too fast",
+			transcript: smtp.Transcript{EnhancedCode: "4.2.1", Reply: "450-4.2.1 The user you are trying to contact is receiving mail at a rate that prevents additional messages from being delivered"},
+		},
+		{
+			name:       "a host that did not answer",
+			transcript: smtp.Transcript{EnhancedCode: "4.4.1"},
+		},
+		{
+			name:       "a permanent refusal",
+			permanent:  true,
+			transcript: smtp.Transcript{EnhancedCode: "5.7.28", Reply: "550 5.7.28 rate limit exceeded"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sendErr := &fakeSenderError{msg: c.transcript.Reply, permanent: c.permanent, code: 421}
+			_, ok := throttlingReply(classifier, sendErr, c.transcript)
+			assert.Equal(t, c.throttling, ok)
+		})
+	}
+}
+
+// A cool-down longer than the stream holds an Envelope postpones it in the
+// Pool for what is left, without an attempt, rather than leaving it to be
+// reclaimed and sent twice.
+func TestLongCooldownPostponesTheDelivery(t *testing.T) {
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+
+	store, err := cooldown.OpenNATS(ctx, js, cooldown.Config{Initial: time.Hour, Max: time.Hour})
+	require.NoError(t, err)
+	now := time.Now()
+	_, err = store.Throttled(ctx, "example.com", cooldown.Reply{Code: "4.7.0", Text: "421 4.7.0 slow down"})
+	require.NoError(t, err)
+
+	sender := &countingSender{}
+	pub := &recordingPublisher{}
+	s := &smtpSender{
+		sender:       sender,
+		publisher:    pub,
+		js:           js,
+		guard:        mustGetSendGuard(ctx, js),
+		destinations: newDestinations(ThrottleConfig{}, fakeMX(nil)),
+		cooldowns:    store,
+		classifier:   bounce.Default(),
+		now:          func() time.Time { return now },
+	}
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "held@example.com", 1)))
+	assert.Zero(t, sender.count())
+	require.Equal(t, []string{"kannon.stats.postponed"}, pub.subjects())
+	postponed := pub.stats(t)[0].Data.GetPostponed()
+	assert.Equal(t, "destination cooling down", postponed.Reason)
+	assert.True(t, postponed.Until.AsTime().After(now.Add(55*time.Minute)), "for what is left of the cool-down")
+}
+
+// throttlingSender answers every message the way Gmail does when it throttles.
+type throttlingSender struct {
+	countingSender
+}
+
+func (s *throttlingSender) Send(from, to string, body smtp.Body, opts smtp.SendOptions) (smtp.Sent, smtp.SenderError) {
+	_, _ = s.countingSender.Send(from, to, body, opts)
+	reply := "421-4.7.28 Gmail has detected an unusual rate of unsolicited mail originating from your IP address"
+	return smtp.Sent{Transcript: smtp.Transcript{MX: "gmail-smtp-in.l.google.com", EnhancedCode: "4.7.28", Reply: reply}},
+		&fakeSenderError{msg: reply, code: 421}
+}
+
+// The Envelope that receives a throttling reply makes its attempt; every
+// Envelope behind it for the same destination — another domain of the same
+// group included — is handed back to the stream untouched until the cool-down
+// is over: it never reaches the relay, takes no claim and publishes no stat,
+// so the Delivery's attempts are not spent on it.
+func TestThrottlingReplyCoolsDownTheDestination(t *testing.T) {
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+
+	store, err := cooldown.OpenNATS(ctx, js, cooldown.Config{})
+	require.NoError(t, err)
+	sender := &throttlingSender{}
+	guard := &countingGuard{inner: mustGetSendGuard(ctx, js)}
+	pub := &recordingPublisher{}
+	s := &smtpSender{
+		sender:    sender,
+		publisher: pub,
+		js:        js,
+		guard:     guard,
+		destinations: newDestinations(ThrottleConfig{
+			Groups: []ThrottleGroup{{Name: "google", Domains: []string{"gmail.com", "googlemail.com"}}},
+		}, fakeMX(nil)),
+		cooldowns:  store,
+		classifier: bounce.Default(),
+		now:        time.Now,
+	}
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "first@gmail.com", 1)))
+	assert.Equal(t, 1, sender.count())
+	assert.Len(t, pub.subjects(), 1, "the throttled attempt is reported as any deferral is")
+
+	err = s.handleMessage(ctx, envelopeMsg(t, "second@googlemail.com", 2))
+	var cooling *coolingDownError
+	require.True(t, errors.As(err, &cooling), "got %v", err)
+	assert.Equal(t, "google", cooling.destination)
+	assert.GreaterOrEqual(t, cooling.wait, 50*time.Second)
+	assert.Less(t, cooling.wait, cooldown.DefaultInitial+cooldown.DefaultInitial/4+time.Second)
+	assert.Equal(t, 1, sender.count())
+	assert.Equal(t, 1, guard.count())
+	assert.Len(t, pub.subjects(), 1)
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "third@example.com", 3)),
+		"another destination is not held back")
+	assert.Equal(t, 2, sender.count())
+
+	s.now = func() time.Time { return time.Now().Add(cooldown.DefaultInitial) }
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "second@googlemail.com", 2)))
+	assert.Equal(t, 3, sender.count(), "once the cool-down is over the same message is sent")
+}
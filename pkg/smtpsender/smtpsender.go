@@ -2,24 +2,35 @@ package smtpsender
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/bodystore"
+	"github.com/kannon-email/kannon/internal/bounce"
+	"github.com/kannon-email/kannon/internal/capture"
+	"github.com/kannon-email/kannon/internal/cooldown"
 	"github.com/kannon-email/kannon/internal/envelope"
 	"github.com/kannon-email/kannon/internal/envelopepb"
 	"github.com/kannon-email/kannon/internal/publisher"
+	"github.com/kannon-email/kannon/internal/resolver"
 	"github.com/kannon-email/kannon/internal/smtp"
 	"github.com/kannon-email/kannon/internal/stats"
 	"github.com/kannon-email/kannon/internal/utils"
+	"github.com/kannon-email/kannon/internal/warmup"
 	"github.com/kannon-email/kannon/x/config"
 	"github.com/kannon-email/kannon/x/container"
 	"github.com/nats-io/nats.go/jetstream"
 )
 
 type Config struct {
-	MaxJobs uint `mapstructure:"max_jobs"`
+	MaxJobs  uint            `mapstructure:"max_jobs"`
+	Throttle ThrottleConfig  `mapstructure:"throttle"`
+	Warmup   warmup.Config   `mapstructure:"warmup"`
+	Cooldown cooldown.Config `mapstructure:"cooldown"`
 }
 
 func (c *Config) setDefaults() {
@@ -33,7 +44,26 @@ type smtpSender struct {
 	publisher publisher.Publisher
 	js        jetstream.JetStream
 	guard     sendGuard
+	bodies    bodystore.Store
 	cfg       Config
+
+	throttle     throttle
+	destinations *destinations
+	// resolver is what destinations look MX hosts up through: the
+	// container's, which the Sender shares.
+	resolver resolver.Resolver
+
+	warmup      *warmup.Schedule
+	warmCounter warmup.Counter
+
+	cooldowns cooldown.Store
+	// classifier tells a throttling reply from the rest. bounceRules is the
+	// rule file it is loaded from, the stats': a reply the operator's rules
+	// call rate_limited in the stats starts a cool-down too.
+	classifier  *bounce.Classifier
+	bounceRules string
+
+	now func() time.Time
 }
 
 // sendAckPolicy is the ack deadline curve of the sending consumer, and it is
@@ -62,6 +92,8 @@ func New(cnt *container.Container) container.Runnable {
 	config.LoadSection("sender", &cfg)
 	cfg.setDefaults()
 	s := NewSMTPSender(cnt.NatsPublisher(), cnt.NatsJetStream(), cnt.Sender(), cfg)
+	s.resolver = cnt.Resolver()
+	s.bounceRules = statsBounceRules()
 	return container.Runnable{
 		Name: "smtpsender",
 		Run:  s.Run,
@@ -74,6 +106,7 @@ func NewSMTPSender(publisher publisher.Publisher, js jetstream.JetStream, s smtp
 		publisher: publisher,
 		js:        js,
 		cfg:       cfg,
+		now:       time.Now,
 	}
 }
 
@@ -82,12 +115,62 @@ func (s *smtpSender) Run(ctx context.Context) error {
 		Info("Starting SMTPSender Service")
 	mustConfigureStatsJS(ctx, s.js)
 
+	schedule, err := warmup.New(s.cfg.Warmup)
+	if err != nil {
+		return fmt.Errorf("config: sender.warmup: %w", err)
+	}
+
+	if err := s.cfg.Cooldown.Validate(); err != nil {
+		return fmt.Errorf("config: sender.cooldown: %w", err)
+	}
+
 	s.guard = mustGetSendGuard(ctx, s.js)
+	if !s.cfg.Cooldown.Disabled {
+		classifier, err := bounce.Load(s.bounceRules)
+		if err != nil {
+			return fmt.Errorf("config: stats.bounce_rules: %w", err)
+		}
+		s.classifier = classifier
+		s.cooldowns = mustGetCooldowns(ctx, s.js, s.cfg.Cooldown)
+	}
+	if s.cfg.Throttle.enabled() {
+		s.throttle = mustGetThrottle(ctx, s.js)
+	}
+	if schedule.Enabled() {
+		s.warmup = schedule
+		s.warmCounter = mustGetWarmupCounter(ctx, s.js)
+	}
+	if s.throttle != nil || s.warmup.ByDestination() || s.cooldowns != nil {
+		if s.resolver == nil {
+			s.resolver = resolver.New(resolver.Config{})
+		}
+		s.destinations = newDestinations(s.cfg.Throttle, s.resolver.LookupMX)
+	}
+	bodies, err := bodystore.OpenNATS(ctx, s.js)
+	if err != nil {
+		return err
+	}
+	s.bodies = bodies
 	consumer := s.mustSendingConsumer(ctx)
 
 	return s.handleSend(ctx, consumer)
 }
 
+// statsBounceRules is `stats.bounce_rules`, which the sender reads to tell
+// throttling replies by the rules the stats classify them by. One config file
+// describes the whole installation, so a sender process can read it without
+// running the stats. A section that cannot be read leaves the embedded rules,
+// with a warning.
+func statsBounceRules() string {
+	var statsCfg struct {
+		BounceRules string `mapstructure:"bounce_rules"`
+	}
+	if err := config.TryLoadSection("stats", &statsCfg); err != nil {
+		slog.Warn("cannot read stats.bounce_rules, so throttling replies are told by the embedded rules", "err", err)
+	}
+	return statsCfg.BounceRules
+}
+
 // mustSendingConsumer subscribes to the Envelopes waiting to be transmitted.
 func (s *smtpSender) mustSendingConsumer(ctx context.Context) jetstream.Consumer {
 	return utils.MustGetPullSubscriber(ctx, s.js, "kannon-sending", "kannon.sending", "kannon-sending-pool",
@@ -112,6 +195,19 @@ func (s *smtpSender) handleSend(ctx context.Context, consumer jetstream.Consumer
 	}
 	defer con.Drain()
 
+	if r, ok := s.sender.(smtp.PoolReporter); ok {
+		go reportPool(ctx, r)
+	}
+	if r, ok := s.sender.(smtp.CircuitReporter); ok {
+		go reportCircuits(ctx, r)
+	}
+	if r, ok := s.sender.(smtp.TLSReporter); ok {
+		go reportTLS(ctx, r, mustGetTLSReports(ctx, s.js))
+	}
+	if sink, ok := s.sender.(*capture.Sink); ok {
+		go serveInbox(ctx, sink)
+	}
+
 	<-ctx.Done()
 	tasks.WaitAndClose()
 
@@ -119,7 +215,139 @@ func (s *smtpSender) handleSend(ctx context.Context, consumer jetstream.Consumer
 	return ctx.Err()
 }
 
+// serveInbox serves the capture sink's inbox until ctx ends. An inbox that
+// cannot be served — its port taken — is logged rather than stopping the
+// sender: the messages are still kept.
+func serveInbox(ctx context.Context, sink *capture.Sink) {
+	if err := sink.Serve(ctx); err != nil {
+		slog.Error("cannot serve the capture inbox", "err", err)
+	}
+}
+
+// poolReportInterval is how often the connection pool's counters are logged.
+const poolReportInterval = time.Minute
+
+// reportPool logs how well the Sender's connection pool is doing until ctx
+// ends. A hit rate that stays low while the same providers receive most of the
+// mail means connections are not outliving the gap between messages: see
+// `sender.pool.idle_timeout`.
+func reportPool(ctx context.Context, r smtp.PoolReporter) {
+	t := time.NewTicker(poolReportInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			st := r.PoolStats()
+			slog.Info("SMTP connection pool",
+				"hits", st.Hits,
+				"misses", st.Misses,
+				"hit_rate", fmt.Sprintf("%.2f", st.HitRate()),
+				"evicted", st.Evicted,
+				"idle", st.Idle)
+		}
+	}
+}
+
+// reportCircuits logs how often the Sender passed over MX hosts that stopped
+// answering until ctx ends, every poolReportInterval in which anything
+// happened: a Sender without a circuit breaker, or whose hosts all answer,
+// logs nothing. The counters are this replica's; which circuits are open, on
+// any replica, is listed by the HZ service.
+func reportCircuits(ctx context.Context, r smtp.CircuitReporter) {
+	t := time.NewTicker(poolReportInterval)
+	defer t.Stop()
+	var last smtp.CircuitStats
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			st := r.CircuitStats()
+			if st == last {
+				continue
+			}
+			last = st
+			slog.Info("MX circuits",
+				"skipped", st.Skipped,
+				"failures", st.Failures,
+				"opened", st.Opened,
+				"probes", st.Probes,
+				"recovered", st.Recovered)
+		}
+	}
+}
+
+// heldBackError is an Envelope the SMTPSender chose not to send yet, by its
+// destination's limits or cool-down or its address's warm-up. It is to come
+// back after retryIn, without having been sent or claimed; reason is the short
+// account of it a Postponed stat carries.
+//
+// retryIn is told how many times the message was delivered before, so that a
+// hold-back that cannot say when it will end can wait longer each time.
+type heldBackError interface {
+	error
+	retryIn(earlier uint64) time.Duration
+	reason() string
+}
+
+// earlierDeliveries is how many times msg was delivered before this once.
+func earlierDeliveries(msg jetstream.Msg) uint64 {
+	md, err := msg.Metadata()
+	if err != nil || md.NumDelivered == 0 {
+		return 0
+	}
+	return md.NumDelivered - 1
+}
+
+// maxStreamHold is the longest an Envelope is held back on the sending stream.
+// A longer hold-back is postponed in the Pool instead. An Envelope waiting on
+// the stream keeps its Delivery in 'sending', and one still waiting once
+// sendingStrandThreshold (pkg/dispatcher) has passed is reclaimed and published
+// a second time under a stream sequence the send guard has never seen — the
+// warm-up's wait for the next day and a long cool-down both went out twice.
+// Each hold-back is also one of the consumer's MaxDeliver deliveries.
+const maxStreamHold = 5 * time.Minute
+
+// holdBack answers an Envelope admission held back. One that is to come back
+// within maxStreamHold is left to handleMsgAck, which hands it back to the
+// stream; a longer wait is reported Postponed, for the Dispatcher to claim the
+// Delivery again when it is over, and the message is acknowledged. Any other
+// error is returned as it is.
+func (s *smtpSender) holdBack(msg jetstream.Msg, env *envelope.Envelope, err error) error {
+	var held heldBackError
+	if !errors.As(err, &held) {
+		return err
+	}
+	wait := held.retryIn(earlierDeliveries(msg))
+	if wait <= maxStreamHold {
+		return err
+	}
+	msgID, domain, idErr := utils.ExtractMsgIDAndDomainFromEmailID(env.EmailID())
+	if idErr != nil {
+		return err
+	}
+
+	slog.Debug("envelope postponed", "err", err)
+	return publisher.PublishStat(s.publisher, stats.Event{
+		MessageID: msgID,
+		Domain:    domain,
+		Email:     env.To(),
+		Timestamp: time.Now(),
+		Outcome:   stats.Postponed(s.now().Add(wait), held.reason()),
+	})
+}
+
 func (s *smtpSender) handleMsgAck(msg jetstream.Msg, err error) {
+	var held heldBackError
+	if errors.As(err, &held) {
+		slog.Debug("envelope held back", "err", err)
+		if err := msg.NakWithDelay(held.retryIn(earlierDeliveries(msg))); err != nil {
+			slog.Error("cannot nak message", "err", err)
+		}
+		return
+	}
 	if err != nil {
 		slog.Error("error in handling message", "err", err)
 		if err := msg.Nak(); err != nil {
@@ -142,17 +370,50 @@ func (s *smtpSender) handleMessage(ctx context.Context, msg jetstream.Msg) error
 		return err
 	}
 
+	// Admission — the destination's limits, then the warm-up of the address it
+	// goes out from — comes before the claim: an Envelope held back is sent
+	// later, and a claim taken now would have the guard drop it as a
+	// redelivery when it returns. The limits come first so
+	// that an Envelope they hold back has spent nothing of an address's day.
+	// A destination cooling down after a throttling reply is offered nothing
+	// at all, so it is checked before either.
+	dest, err := s.holdForCooldown(ctx, env.To())
+	if err != nil {
+		return s.holdBack(msg, env, err)
+	}
+	release, err := s.admit(ctx, env.To())
+	if err != nil {
+		return s.holdBack(msg, env, err)
+	}
+	defer release()
+
+	sourceIP, err := s.chooseSource(ctx, env)
+	if err != nil {
+		return s.holdBack(msg, env, err)
+	}
+
 	if !s.claimSend(ctx, msg, env) {
 		return nil
 	}
 
-	sendErr := s.sender.Send(env.ReturnPath(), env.To(), env.Body())
+	// A body carried out of band is streamed from the store during DATA, one
+	// part at a time, rather than assembled here: the point of storing it was
+	// never to hold ten megabytes per worker.
+	sent, sendErr := s.sender.Send(env.ReturnPath(), env.To(), bodystore.Body(ctx, s.bodies, env),
+		smtp.SendOptions{
+			RequireTLS: env.RequireTLS(),
+			IPPool:     env.IPPool(),
+			SourceIP:   sourceIP,
+			Size:       env.Size(),
+			SMTPUTF8:   env.SMTPUTF8(),
+		})
 	if sendErr != nil {
 		slog.Info(fmt.Sprintf("Cannot send email %v - %v: %v", utils.ObfuscateEmail(env.To()), env.EmailID(), sendErr.Error()))
-		return s.handleSendError(sendErr, env)
+		s.noteThrottling(ctx, dest, sendErr, sent)
+		return s.handleSendError(sendErr, sent, env)
 	}
 	slog.Info(fmt.Sprintf("Email delivered: %v - %v", utils.ObfuscateEmail(env.To()), env.EmailID()))
-	return s.handleSendSuccess(env)
+	return s.handleSendSuccess(sent, env)
 }
 
 // claimSend reports whether this delivery of the Envelope is the one allowed
@@ -188,7 +449,7 @@ func (s *smtpSender) claimSend(ctx context.Context, msg jetstream.Msg, env *enve
 	return claimed
 }
 
-func (s *smtpSender) handleSendSuccess(env *envelope.Envelope) error {
+func (s *smtpSender) handleSendSuccess(sent smtp.Sent, env *envelope.Envelope) error {
 	msgID, domain, err := utils.ExtractMsgIDAndDomainFromEmailID(env.EmailID())
 	if err != nil {
 		return nil
@@ -203,11 +464,11 @@ func (s *smtpSender) handleSendSuccess(env *envelope.Envelope) error {
 		Domain:    domain,
 		Email:     env.To(),
 		Timestamp: time.Now(),
-		Outcome:   stats.Delivered(),
+		Outcome:   stats.Delivered().WithSource(sent.IPPool, sent.SourceIP).WithSMTP(smtpSession(sent.Transcript)),
 	})
 }
 
-func (s *smtpSender) handleSendError(sendErr smtp.SenderError, env *envelope.Envelope) error {
+func (s *smtpSender) handleSendError(sendErr smtp.SenderError, sent smtp.Sent, env *envelope.Envelope) error {
 	msgID, domain, err := utils.ExtractMsgIDAndDomainFromEmailID(env.EmailID())
 	if err != nil {
 		return nil
@@ -230,14 +491,31 @@ func (s *smtpSender) handleSendError(sendErr smtp.SenderError, env *envelope.Env
 		// (CONTEXT.md, Bounced), which is exactly what this line already
 		// did. "Fixing" it would regress the async 4xx assertions in
 		// e2e/e2e_test.go.
-		event.Outcome = stats.Bounced(sendErr.IsPermanent(), sendErr.Code(), sendErr.Error())
+		event.Outcome = stats.Bounced(sendErr.IsPermanent(), sendErr.Code(), sendErr.Error()).
+			WithSource(sent.IPPool, sent.SourceIP)
 	} else {
 		event.Outcome = stats.Errored(sendErr.Code(), sendErr.Error())
 	}
+	event.Outcome = event.Outcome.WithSMTP(smtpSession(sent.Transcript))
 
 	return publisher.PublishStat(s.publisher, event)
 }
 
+// smtpSession is the stats' account of the session a Send reports.
+func smtpSession(t smtp.Transcript) stats.SMTPSession {
+	return stats.SMTPSession{
+		MXHost:       t.MX,
+		MXIP:         t.RemoteIP,
+		Stage:        string(t.Stage),
+		EnhancedCode: t.EnhancedCode,
+		TLSVersion:   t.TLSVersion,
+		TLSCipher:    t.TLSCipher,
+		Reply:        t.Reply,
+		QueueID:      t.QueueID,
+		Transcript:   strings.Join(t.Lines, "\n"),
+	}
+}
+
 func mustConfigureStatsJS(ctx context.Context, js jetstream.JetStream) {
 	name := "kannon-stats"
 
@@ -1,12 +1,15 @@
 package smtpsender
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"io"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/kannon-email/kannon/internal/bodystore"
 	"github.com/kannon-email/kannon/internal/envelope"
 	"github.com/kannon-email/kannon/internal/envelopepb"
 	"github.com/kannon-email/kannon/internal/smtp"
@@ -83,6 +86,34 @@ func TestRedispatchedEnvelopeIsSentAgain(t *testing.T) {
 	assert.Equal(t, 2, sender.count())
 }
 
+// An Envelope carried out of band reaches the relay as the message that was
+// built: the SMTPSender streams its parts from the Object Store in order.
+func TestOutOfBandEnvelopeIsTransmittedWhole(t *testing.T) {
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+	bodies, err := bodystore.OpenNATS(ctx, js)
+	require.NoError(t, err)
+
+	built := append([]byte("To: big@example.com\r\n\r\n"), bytes.Repeat([]byte("attachment"), 50000)...)
+	env, err := bodystore.Offload(ctx, bodies, envelope.New(envelope.Params{
+		EmailID: "<" + base64.URLEncoding.EncodeToString([]byte("big@example.com")) + "/msg_test@example.com>",
+		To:      "big@example.com",
+		Body:    built,
+	}), 1024)
+	require.NoError(t, err)
+	require.NotEmpty(t, env.Parts())
+
+	data, err := proto.Marshal(envelopepb.FromEnvelope(env))
+	require.NoError(t, err)
+	assert.Less(t, len(data), 1024, "the message on the stream carries references, not the body")
+
+	sender := &recordingSender{}
+	s := &smtpSender{sender: sender, publisher: &recordingPublisher{}, js: js, bodies: bodies}
+	require.NoError(t, s.handleMessage(ctx, &fakeMsg{data: data, seq: 1}))
+
+	assert.Equal(t, built, sender.body)
+}
+
 // End to end against a real consumer: a deadline too short for the handler —
 // the shape #425 ran in production — does make the server redeliver, and the
 // guard is what keeps that from reaching the relay twice.
@@ -167,7 +198,8 @@ func TestHandleSendErrorClassifiesByReplyClassNotByRetryDecision(t *testing.T) {
 			pub := &recordingPublisher{}
 			s := &smtpSender{publisher: pub}
 
-			require.NoError(t, s.handleSendError(tt.sendErr, envelopeTo("bounce@example.com", tt.shouldRetry)))
+			sent := smtp.Sent{IPPool: "bulk", SourceIP: "192.0.2.10"}
+			require.NoError(t, s.handleSendError(tt.sendErr, sent, envelopeTo("bounce@example.com", tt.shouldRetry)))
 
 			require.Equal(t, []string{tt.wantSubject}, pub.subjects())
 			published := pub.stats(t)
@@ -178,6 +210,8 @@ func TestHandleSendErrorClassifiesByReplyClassNotByRetryDecision(t *testing.T) {
 			assert.Equal(t, tt.wantPermanent, bounced.Permanent)
 			assert.EqualValues(t, tt.sendErr.code, bounced.Code)
 			assert.Equal(t, tt.sendErr.msg, bounced.Msg)
+			assert.Equal(t, "bulk", bounced.IpPool, "the address a Bounce was sent from is kept")
+			assert.Equal(t, "192.0.2.10", bounced.SourceIp)
 		})
 	}
 }
@@ -192,7 +226,7 @@ func TestHandleSendErrorWithRetriesRemainingPublishesError(t *testing.T) {
 
 	sendErr := &fakeSenderError{msg: "451 try again later", permanent: false, code: 451}
 
-	require.NoError(t, s.handleSendError(sendErr, envelopeTo("retry@example.com", true)))
+	require.NoError(t, s.handleSendError(sendErr, smtp.Sent{}, envelopeTo("retry@example.com", true)))
 
 	require.Equal(t, []string{"kannon.stats.error"}, pub.subjects())
 	published := pub.stats(t)
@@ -205,6 +239,64 @@ func TestHandleSendErrorWithRetriesRemainingPublishesError(t *testing.T) {
 	assert.Nil(t, published[0].Data.GetBounced(), "a retryable transient failure must not be reported as Bounced")
 }
 
+// The session a Send reports rides on the stat it publishes, the transcript
+// as one line per command and reply.
+func TestTheSMTPSessionIsRecordedOnTheStat(t *testing.T) {
+	pub := &recordingPublisher{}
+	s := &smtpSender{publisher: pub}
+
+	sendErr := &fakeSenderError{msg: "452 mailbox full", permanent: false, code: 452}
+	sent := smtp.Sent{Transcript: smtp.Transcript{
+		MX:           "mx1.example.com",
+		RemoteIP:     "192.0.2.25",
+		TLSVersion:   "TLS 1.3",
+		Stage:        smtp.StageRCPT,
+		EnhancedCode: "4.2.2",
+		Reply:        "452 4.2.2 mailbox full",
+		Lines:        []string{"C: RCPT TO:<retry@example.com>", "S: 452 4.2.2 mailbox full"},
+	}}
+	require.NoError(t, s.handleSendError(sendErr, sent, envelopeTo("retry@example.com", true)))
+
+	published := pub.stats(t)
+	require.Len(t, published, 1)
+	session := published[0].Data.GetError().GetSmtp()
+	require.NotNil(t, session)
+	assert.Equal(t, "mx1.example.com", session.MxHost)
+	assert.Equal(t, "192.0.2.25", session.MxIp)
+	assert.Equal(t, "TLS 1.3", session.TlsVersion)
+	assert.Equal(t, "rcpt", session.Stage)
+	assert.Equal(t, "4.2.2", session.EnhancedCode)
+	assert.Equal(t, "452 4.2.2 mailbox full", session.Reply)
+	assert.Equal(t, "C: RCPT TO:<retry@example.com>\nS: 452 4.2.2 mailbox full", session.Transcript)
+}
+
+// An Envelope's IP pool is handed to the Sender, and the address the Sender
+// reports sending from is recorded on the Delivered it publishes.
+func TestIPPoolReachesTheSenderAndTheDeliveredStat(t *testing.T) {
+	ctx := t.Context()
+	to := "pooled@example.com"
+	data, err := proto.Marshal(envelopepb.FromEnvelope(envelope.New(envelope.Params{
+		EmailID: "<" + base64.URLEncoding.EncodeToString([]byte(to)) + "/msg_test@example.com>",
+		To:      to,
+		Body:    []byte("body"),
+		IPPool:  "transactional",
+	})))
+	require.NoError(t, err)
+
+	sender := &recordingSender{sent: smtp.Sent{IPPool: "transactional", SourceIP: "192.0.2.7"}}
+	pub := &recordingPublisher{}
+	s := &smtpSender{sender: sender, publisher: pub, js: tests.NatsJetStream(t)}
+	require.NoError(t, s.handleMessage(ctx, &fakeMsg{data: data, seq: 1}))
+
+	assert.Equal(t, "transactional", sender.opts.IPPool)
+	published := pub.stats(t)
+	require.Len(t, published, 1)
+	delivered := published[0].Data.GetDelivered()
+	require.NotNil(t, delivered)
+	assert.Equal(t, "transactional", delivered.IpPool)
+	assert.Equal(t, "192.0.2.7", delivered.SourceIp)
+}
+
 // fakeSenderError is a local stand-in for smtp.SenderError: the concrete
 // smtpError type in internal/smtp is unexported, so a test outside that
 // package cannot construct one directly.
@@ -263,16 +355,18 @@ func envelopeMsg(t *testing.T, to string, seq uint64) jetstream.Msg {
 // those panics loudly rather than passing silently.
 type fakeMsg struct {
 	jetstream.Msg
-	data []byte
-	seq  uint64
+	data      []byte
+	seq       uint64
+	delivered uint64
 }
 
 func (m *fakeMsg) Data() []byte { return m.data }
 
 func (m *fakeMsg) Metadata() (*jetstream.MsgMetadata, error) {
 	return &jetstream.MsgMetadata{
-		Stream:   "kannon-sending",
-		Sequence: jetstream.SequencePair{Stream: m.seq},
+		Stream:       "kannon-sending",
+		Sequence:     jetstream.SequencePair{Stream: m.seq},
+		NumDelivered: m.delivered,
 	}, nil
 }
 
@@ -283,11 +377,11 @@ type countingSender struct {
 
 func (s *countingSender) SenderName() string { return "countingSender" }
 
-func (s *countingSender) Send(_, _ string, _ []byte) smtp.SenderError {
+func (s *countingSender) Send(_, _ string, _ smtp.Body, _ smtp.SendOptions) (smtp.Sent, smtp.SenderError) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.calls++
-	return nil
+	return smtp.Sent{}, nil
 }
 
 func (s *countingSender) count() int {
@@ -296,6 +390,29 @@ func (s *countingSender) count() int {
 	return s.calls
 }
 
+// recordingSender reads the body it is handed, as a relay connection would,
+// keeps the options it was sent with and reports sending from sent.
+type recordingSender struct {
+	body []byte
+	opts smtp.SendOptions
+	sent smtp.Sent
+}
+
+func (s *recordingSender) SenderName() string { return "recordingSender" }
+
+func (s *recordingSender) Send(_, _ string, body smtp.Body, opts smtp.SendOptions) (smtp.Sent, smtp.SenderError) {
+	s.opts = opts
+	r, err := body()
+	if err != nil {
+		return smtp.Sent{}, &fakeSenderError{msg: err.Error(), code: 451}
+	}
+	defer r.Close()
+	if s.body, err = io.ReadAll(r); err != nil {
+		return smtp.Sent{}, &fakeSenderError{msg: err.Error(), code: 451}
+	}
+	return s.sent, nil
+}
+
 // blockingSender holds the SMTP transaction open until the test releases it,
 // standing in for a relay slower than the consumer's ack deadline.
 type blockingSender struct {
@@ -303,10 +420,10 @@ type blockingSender struct {
 	released chan struct{}
 }
 
-func (s *blockingSender) Send(from, to string, body []byte) smtp.SenderError {
-	err := s.countingSender.Send(from, to, body)
+func (s *blockingSender) Send(from, to string, body smtp.Body, opts smtp.SendOptions) (smtp.Sent, smtp.SenderError) {
+	sent, err := s.countingSender.Send(from, to, body, opts)
 	<-s.released
-	return err
+	return sent, err
 }
 
 type recordingPublisher struct {
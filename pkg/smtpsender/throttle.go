@@ -0,0 +1,406 @@
+package smtpsender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/kvstate"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// ThrottleConfig is the `sender.throttle` section: how many messages may be in
+// flight to one destination at once, and how many may start per minute.
+//
+// A destination is either a group of recipient domains that share mail
+// servers — every domain whose MX hosts are Google's is one destination, so a
+// Batch split across gmail.com and a thousand Workspace domains is still held
+// to what Google accepts — or, for a domain no group claims, the recipient
+// domain itself. The limits are counted in a NATS key/value bucket, so they
+// hold across every sender replica together rather than per process.
+//
+// An Envelope over a limit is handed back to the stream with a delay instead
+// of waiting for its turn in a worker slot: a worker stays free for mail to
+// destinations that are not saturated.
+type ThrottleConfig struct {
+	// Default are the limits of each recipient domain no group claims. Zero
+	// means unlimited, which is the default.
+	Default Limits `mapstructure:"default"`
+	// Groups are destinations made of several recipient domains.
+	Groups []ThrottleGroup `mapstructure:"groups"`
+}
+
+// Limits are one destination's limits. Zero means unlimited.
+type Limits struct {
+	// MaxConnections is how many SMTP transactions to the destination may run
+	// at once, across every replica.
+	MaxConnections int `mapstructure:"max_connections"`
+	// PerMinute is how many messages to the destination may start per
+	// calendar minute, across every replica.
+	PerMinute int `mapstructure:"per_minute"`
+}
+
+func (l Limits) unlimited() bool {
+	return l.MaxConnections <= 0 && l.PerMinute <= 0
+}
+
+// ThrottleGroup is a named destination. A recipient domain belongs to it when
+// it is listed in Domains, or when its most preferred MX host ends in one of
+// MXSuffixes — ".google.com" catches Gmail and every Workspace domain alike.
+type ThrottleGroup struct {
+	Name       string   `mapstructure:"name"`
+	Domains    []string `mapstructure:"domains"`
+	MXSuffixes []string `mapstructure:"mx_suffixes"`
+	Limits     `mapstructure:",squash"`
+}
+
+// enabled reports whether any limit is configured at all. Without one the
+// SMTPSender neither opens the bucket nor resolves a single MX record.
+func (c ThrottleConfig) enabled() bool {
+	if !c.Default.unlimited() {
+		return true
+	}
+	for _, g := range c.Groups {
+		if !g.unlimited() {
+			return true
+		}
+	}
+	return false
+}
+
+// destination is where an Envelope is going, as far as the limits are
+// concerned.
+type destination struct {
+	// name is the group's name, or the recipient domain.
+	name   string
+	limits Limits
+}
+
+// mxCacheTTL is how long a recipient domain's group, worked out from its MX
+// records, is remembered. Domains move between providers rarely, and a stale
+// answer only means a few minutes under the wrong limits.
+const mxCacheTTL = 10 * time.Minute
+
+// mxCacheMax bounds how many recipient domains are remembered. The cache is
+// dropped whole when it fills: one round of lookups is cheaper than tracking
+// which entries are worth keeping.
+const mxCacheMax = 10_000
+
+// destinations works out the destination of a recipient domain.
+type destinations struct {
+	cfg      ThrottleConfig
+	byDomain map[string]*ThrottleGroup
+	lookupMX func(ctx context.Context, domain string) ([]*net.MX, error)
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]mxCacheEntry
+}
+
+type mxCacheEntry struct {
+	// group is nil for a domain no group's suffixes matched.
+	group   *ThrottleGroup
+	expires time.Time
+}
+
+func newDestinations(cfg ThrottleConfig, lookupMX func(context.Context, string) ([]*net.MX, error)) *destinations {
+	d := &destinations{
+		cfg:      cfg,
+		byDomain: make(map[string]*ThrottleGroup),
+		lookupMX: lookupMX,
+		now:      time.Now,
+		cache:    make(map[string]mxCacheEntry),
+	}
+	for i := range cfg.Groups {
+		g := &d.cfg.Groups[i]
+		for _, domain := range g.Domains {
+			d.byDomain[strings.ToLower(domain)] = g
+		}
+	}
+	return d
+}
+
+// resolve returns the destination of domain. A domain whose MX records cannot
+// be read is its own destination: the limits fail open to the default rather
+// than holding back mail over a DNS hiccup the relay may well not share.
+func (d *destinations) resolve(ctx context.Context, domain string) destination {
+	domain = strings.ToLower(domain)
+	if g, ok := d.byDomain[domain]; ok {
+		return destination{name: g.Name, limits: g.Limits}
+	}
+	if g := d.groupByMX(ctx, domain); g != nil {
+		return destination{name: g.Name, limits: g.Limits}
+	}
+	return destination{name: domain, limits: d.cfg.Default}
+}
+
+func (d *destinations) groupByMX(ctx context.Context, domain string) *ThrottleGroup {
+	if !d.matchesByMX() {
+		return nil
+	}
+
+	d.mu.Lock()
+	e, ok := d.cache[domain]
+	d.mu.Unlock()
+	if ok && d.now().Before(e.expires) {
+		return e.group
+	}
+
+	mxs, err := d.lookupMX(ctx, domain)
+	if err != nil {
+		slog.Debug("cannot resolve MX for throttling, using default limits", "domain", domain, "err", err)
+		return nil
+	}
+	e = mxCacheEntry{group: d.matchMX(mxs), expires: d.now().Add(mxCacheTTL)}
+
+	d.mu.Lock()
+	if len(d.cache) >= mxCacheMax {
+		d.cache = make(map[string]mxCacheEntry)
+	}
+	d.cache[domain] = e
+	d.mu.Unlock()
+	return e.group
+}
+
+func (d *destinations) matchesByMX() bool {
+	for _, g := range d.cfg.Groups {
+		if len(g.MXSuffixes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMX returns the group of the most preferred MX host. The resolver sorts
+// by preference; a domain without MX records belongs to no group.
+func (d *destinations) matchMX(mxs []*net.MX) *ThrottleGroup {
+	if len(mxs) == 0 {
+		return nil
+	}
+	host := strings.ToLower(strings.TrimSuffix(mxs[0].Host, "."))
+	for i := range d.cfg.Groups {
+		g := &d.cfg.Groups[i]
+		for _, suffix := range g.MXSuffixes {
+			if strings.HasSuffix(host, strings.ToLower(strings.TrimSuffix(suffix, "."))) {
+				return g
+			}
+		}
+	}
+	return nil
+}
+
+// throttle admits sends to a destination within its limits.
+type throttle interface {
+	// Acquire admits one send to dest. It returns the function that ends the
+	// send, or, for a destination at its limit, how long to wait before trying
+	// again.
+	Acquire(ctx context.Context, dest destination) (release func(), wait time.Duration, err error)
+}
+
+const (
+	// throttleBucket holds the destinations' counters.
+	throttleBucket = "kannon-destination-throttle"
+
+	// throttleTTL is how long a counter is kept. It bounds what a replica that
+	// dies mid-send costs: the connection slot it held frees itself this long
+	// after it was taken. It has to outlast the longest SMTP transaction worth
+	// waiting for, or a slow send's slot is handed to a second one.
+	throttleTTL = 15 * time.Minute
+
+	// connectionRetryDelay is the wait before an Envelope held back by its
+	// destination's connection limit is redelivered, before jitter. A
+	// transaction takes a few seconds; the jitter keeps the Envelopes held back
+	// together from coming back together.
+	connectionRetryDelay = 5 * time.Second
+
+	// rateRetryJitter spreads the Envelopes held back by a per-minute limit
+	// over the start of the next minute.
+	rateRetryJitter = 10 * time.Second
+
+	// maxThrottleDoublings bounds how often a throttled Envelope's wait is
+	// doubled; far fewer take it past maxStreamHold.
+	maxThrottleDoublings = 10
+
+	// counterRetries is how many times a counter update lost to another
+	// replica is tried again before the throttle gives up and fails open.
+	counterRetries = 5
+)
+
+// mustGetThrottle opens the throttle's key/value bucket, exiting on failure the
+// way mustGetSendGuard does: a limit that is configured and not enforced is
+// the rate-limit trip the operator configured it to avoid.
+func mustGetThrottle(ctx context.Context, js jetstream.JetStream) throttle {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      throttleBucket,
+		Description: "Connections in use and messages sent per minute, by destination",
+		TTL:         throttleTTL,
+		Storage:     jetstream.MemoryStorage,
+		Replicas:    1,
+	})
+	if err != nil {
+		slog.Error("cannot create throttle bucket", "bucket", throttleBucket, "err", err)
+		os.Exit(1)
+	}
+
+	slog.Info("destination throttle ready", "bucket", throttleBucket)
+	return &kvThrottle{kv: kv, now: time.Now}
+}
+
+// kvThrottle counts in a JetStream key/value bucket, so every replica sees the
+// others' sends.
+//
+// A connection is one of MaxConnections slot keys, taken with Create — which
+// only one replica can win — and given back with Delete. A minute is one
+// counter key, advanced by compare-and-swap on its revision.
+type kvThrottle struct {
+	kv  jetstream.KeyValue
+	now func() time.Time
+}
+
+func (t *kvThrottle) Acquire(ctx context.Context, dest destination) (func(), time.Duration, error) {
+	release := func() {}
+	if n := dest.limits.MaxConnections; n > 0 {
+		slot, err := t.takeSlot(ctx, dest.name, n)
+		if err != nil {
+			return nil, 0, err
+		}
+		if slot == "" {
+			return nil, jitter(connectionRetryDelay, connectionRetryDelay), nil
+		}
+		release = func() {
+			// The send is over whatever its context did, so the slot is given
+			// back on a context of its own.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := t.kv.Delete(ctx, slot); err != nil {
+				slog.Warn("cannot release destination slot, it frees itself on expiry",
+					"key", slot, "err", err)
+			}
+		}
+	}
+
+	if n := dest.limits.PerMinute; n > 0 {
+		wait, err := t.countMinute(ctx, dest.name, n)
+		if err != nil || wait > 0 {
+			release()
+			return nil, wait, err
+		}
+	}
+	return release, 0, nil
+}
+
+// takeSlot takes a free connection slot for dest and returns its key, or the
+// empty key when all n are taken. The scan starts at a random slot so that
+// replicas do not all contend for the first.
+func (t *kvThrottle) takeSlot(ctx context.Context, dest string, n int) (string, error) {
+	start := rand.IntN(n)
+	for i := range n {
+		key := kvstate.Key("conn", dest, strconv.Itoa((start+i)%n))
+		_, err := t.kv.Create(ctx, key, nil)
+		if err == nil {
+			return key, nil
+		}
+		if !errors.Is(err, jetstream.ErrKeyExists) {
+			return "", fmt.Errorf("cannot take slot %s: %w", key, err)
+		}
+	}
+	return "", nil
+}
+
+// countMinute counts one message against dest's current minute. It returns
+// how long until the next minute when the current one is already full.
+func (t *kvThrottle) countMinute(ctx context.Context, dest string, limit int) (time.Duration, error) {
+	now := t.now()
+	minute := now.Truncate(time.Minute)
+	key := kvstate.Key("rate", dest, strconv.FormatInt(minute.Unix(), 10))
+
+	for range counterRetries {
+		entry, err := t.kv.Get(ctx, key)
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			if _, err := t.kv.Create(ctx, key, []byte("1")); err == nil {
+				return 0, nil
+			} else if !errors.Is(err, jetstream.ErrKeyExists) {
+				return 0, fmt.Errorf("cannot count %s: %w", key, err)
+			}
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("cannot read %s: %w", key, err)
+		}
+
+		sent, _ := strconv.Atoi(string(entry.Value()))
+		if sent >= limit {
+			return jitter(minute.Add(time.Minute).Sub(now), rateRetryJitter), nil
+		}
+		if _, err := t.kv.Update(ctx, key, []byte(strconv.Itoa(sent+1)), entry.Revision()); err == nil {
+			return 0, nil
+		} else if !errors.Is(err, jetstream.ErrKeyExists) {
+			return 0, fmt.Errorf("cannot count %s: %w", key, err)
+		}
+	}
+	return 0, fmt.Errorf("cannot count %s: lost %d updates in a row", key, counterRetries)
+}
+
+// jitter is d plus up to spread, at random.
+func jitter(d, spread time.Duration) time.Duration {
+	return d + rand.N(spread)
+}
+
+// throttledError is an Envelope held back by its destination's limits. The
+// message is handed back to the stream to come back after wait.
+type throttledError struct {
+	destination string
+	wait        time.Duration
+}
+
+func (e *throttledError) Error() string {
+	return fmt.Sprintf("destination %s is at its limit, retrying in %v", e.destination, e.wait.Round(time.Second))
+}
+
+// retryIn doubles the wait for each time the message was delivered before: a
+// slot is free again when some other transaction ends, which the throttle
+// cannot foresee, and a destination saturated for minutes would otherwise
+// spend the consumer's MaxDeliver deliveries every few seconds. The doubling
+// soon passes maxStreamHold, and the Delivery is postponed in the Pool.
+func (e *throttledError) retryIn(earlier uint64) time.Duration {
+	return e.wait << min(earlier, maxThrottleDoublings)
+}
+
+func (e *throttledError) reason() string { return "destination at its limits" }
+
+// admit holds the Envelope to its destination's limits. The returned function
+// ends the send and must be called once it is over.
+//
+// The throttle fails open for the same reason the send guard does: a bucket
+// that cannot be reached is no reason to stop sending mail.
+func (s *smtpSender) admit(ctx context.Context, to string) (func(), error) {
+	if s.throttle == nil {
+		return func() {}, nil
+	}
+	at := strings.LastIndexByte(to, '@')
+	if at < 0 {
+		return func() {}, nil
+	}
+
+	dest := s.destinations.resolve(ctx, to[at+1:])
+	if dest.limits.unlimited() {
+		return func() {}, nil
+	}
+	release, wait, err := s.throttle.Acquire(ctx, dest)
+	if err != nil {
+		slog.Error("destination throttle unavailable, sending anyway", "destination", dest.name, "err", err)
+		return func() {}, nil
+	}
+	if wait > 0 {
+		return nil, &throttledError{destination: dest.name, wait: wait}
+	}
+	return release, nil
+}
@@ -0,0 +1,193 @@
+package smtpsender
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var throttleGroups = ThrottleConfig{
+	Default: Limits{PerMinute: 1000},
+	Groups: []ThrottleGroup{
+		{Name: "google", MXSuffixes: []string{".google.com."}, Limits: Limits{MaxConnections: 2}},
+		{Name: "microsoft", Domains: []string{"outlook.com", "hotmail.com"}, Limits: Limits{PerMinute: 10}},
+	},
+}
+
+func fakeMX(records map[string]string) func(context.Context, string) ([]*net.MX, error) {
+	return func(_ context.Context, domain string) ([]*net.MX, error) {
+		host, ok := records[domain]
+		if !ok {
+			return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+		}
+		return []*net.MX{{Host: host, Pref: 10}}, nil
+	}
+}
+
+func TestDestinationsGroupDomainsByListAndByMX(t *testing.T) {
+	d := newDestinations(throttleGroups, fakeMX(map[string]string{
+		"gmail.com":      "gmail-smtp-in.l.google.com.",
+		"workspace.test": "aspmx.l.google.com.",
+		"other.test":     "mx.other.test.",
+	}))
+	ctx := t.Context()
+
+	assert.Equal(t, "google", d.resolve(ctx, "gmail.com").name)
+	assert.Equal(t, "google", d.resolve(ctx, "Workspace.Test").name,
+		"a Workspace domain shares Google's limits through its MX")
+	assert.Equal(t, "microsoft", d.resolve(ctx, "hotmail.com").name)
+
+	other := d.resolve(ctx, "other.test")
+	assert.Equal(t, "other.test", other.name, "a domain no group claims is its own destination")
+	assert.Equal(t, throttleGroups.Default, other.limits)
+
+	broken := d.resolve(ctx, "unresolvable.test")
+	assert.Equal(t, "unresolvable.test", broken.name, "a failed lookup falls back to the default limits")
+}
+
+func TestDestinationsCacheMXLookups(t *testing.T) {
+	lookups := 0
+	d := newDestinations(throttleGroups, func(ctx context.Context, domain string) ([]*net.MX, error) {
+		lookups++
+		return fakeMX(map[string]string{"gmail.com": "alt1.gmail-smtp-in.l.google.com."})(ctx, domain)
+	})
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	d.resolve(t.Context(), "gmail.com")
+	d.resolve(t.Context(), "gmail.com")
+	assert.Equal(t, 1, lookups)
+
+	now = now.Add(mxCacheTTL + time.Second)
+	d.resolve(t.Context(), "gmail.com")
+	assert.Equal(t, 2, lookups, "an expired entry is looked up again")
+}
+
+func TestThrottleCapsConnectionsAcrossReplicas(t *testing.T) {
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+	replicaA, replicaB := mustGetThrottle(ctx, js), mustGetThrottle(ctx, js)
+	dest := destination{name: "google", limits: Limits{MaxConnections: 2}}
+
+	releaseA, wait, err := replicaA.Acquire(ctx, dest)
+	require.NoError(t, err)
+	require.Zero(t, wait)
+	_, wait, err = replicaB.Acquire(ctx, dest)
+	require.NoError(t, err)
+	require.Zero(t, wait)
+
+	_, wait, err = replicaA.Acquire(ctx, dest)
+	require.NoError(t, err)
+	assert.Positive(t, wait, "a third connection is over the limit the replicas share")
+
+	releaseA()
+	_, wait, err = replicaB.Acquire(ctx, dest)
+	require.NoError(t, err)
+	assert.Zero(t, wait, "a released slot is free again for any replica")
+}
+
+func TestThrottleCapsMessagesPerMinute(t *testing.T) {
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+	th := mustGetThrottle(ctx, js).(*kvThrottle)
+	now := time.Date(2026, 10, 19, 12, 0, 15, 0, time.UTC)
+	th.now = func() time.Time { return now }
+	dest := destination{name: "microsoft", limits: Limits{PerMinute: 3}}
+
+	for range 3 {
+		_, wait, err := th.Acquire(ctx, dest)
+		require.NoError(t, err)
+		require.Zero(t, wait)
+	}
+
+	_, wait, err := th.Acquire(ctx, dest)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, wait, 45*time.Second, "the fourth waits for the next minute")
+	assert.Less(t, wait, 45*time.Second+rateRetryJitter)
+
+	now = now.Add(time.Minute)
+	_, wait, err = th.Acquire(ctx, dest)
+	require.NoError(t, err)
+	assert.Zero(t, wait, "a new minute starts a new count")
+}
+
+// An Envelope held back is handed back to the stream untouched: it never
+// reaches the relay, and it never takes a send guard claim, which would have
+// it dropped as a redelivery when it comes back.
+func TestThrottledEnvelopeIsHeldBackWithoutClaim(t *testing.T) {
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+
+	sender := &countingSender{}
+	guard := &countingGuard{inner: mustGetSendGuard(ctx, js)}
+	s := &smtpSender{
+		sender:    sender,
+		publisher: &recordingPublisher{},
+		js:        js,
+		guard:     guard,
+		throttle:  mustGetThrottle(ctx, js),
+		destinations: newDestinations(ThrottleConfig{
+			Default: Limits{MaxConnections: 1},
+		}, fakeMX(nil)),
+	}
+
+	release, err := s.admit(ctx, "first@example.com")
+	require.NoError(t, err)
+
+	err = s.handleMessage(ctx, envelopeMsg(t, "second@example.com", 1))
+	var throttled *throttledError
+	require.True(t, errors.As(err, &throttled), "got %v", err)
+	assert.Equal(t, "example.com", throttled.destination)
+	assert.Zero(t, sender.count())
+	assert.Zero(t, guard.count())
+
+	release()
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "second@example.com", 1)))
+	assert.Equal(t, 1, sender.count(), "once the slot is free the same message is sent")
+}
+
+// A slot held for minutes must not spend the consumer's deliveries every few
+// seconds: the hold-back doubles with each earlier delivery, and once it is
+// longer than the stream holds a message the Delivery is postponed in the Pool.
+func TestRepeatedlyThrottledEnvelopeIsPostponed(t *testing.T) {
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+
+	sender := &countingSender{}
+	pub := &recordingPublisher{}
+	now := time.Now()
+	s := &smtpSender{
+		sender:    sender,
+		publisher: pub,
+		js:        js,
+		guard:     mustGetSendGuard(ctx, js),
+		throttle:  mustGetThrottle(ctx, js),
+		destinations: newDestinations(ThrottleConfig{
+			Default: Limits{MaxConnections: 1},
+		}, fakeMX(nil)),
+		now: func() time.Time { return now },
+	}
+
+	_, err := s.admit(ctx, "first@example.com")
+	require.NoError(t, err)
+
+	msg := &fakeMsg{data: envelopeBytes(t, "second@example.com"), seq: 1, delivered: 1}
+	var throttled *throttledError
+	require.True(t, errors.As(s.handleMessage(ctx, msg), &throttled))
+	assert.LessOrEqual(t, throttled.retryIn(0), 2*connectionRetryDelay)
+	assert.Empty(t, pub.subjects(), "a first hold-back stays on the stream")
+
+	msg.delivered = 8
+	require.NoError(t, s.handleMessage(ctx, msg))
+	assert.Zero(t, sender.count())
+	require.Equal(t, []string{"kannon.stats.postponed"}, pub.subjects())
+	postponed := pub.stats(t)[0].Data.GetPostponed()
+	assert.Equal(t, "destination at its limits", postponed.Reason)
+	assert.True(t, postponed.Until.AsTime().After(now.Add(maxStreamHold)))
+}
@@ -0,0 +1,118 @@
+package smtpsender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/smtp"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// tlsReportBucket holds the TLS-RPT counters of every replica, summed per
+	// day, recipient domain, policy type and result:
+	// `<YYYY-MM-DD>.<domain>.<policy>.<result>`, the value a decimal count.
+	tlsReportBucket = "kannon-tls-reports"
+
+	// tlsReportTTL is how long a day's counters are kept: a report covers one
+	// UTC day (RFC 8460 §4.1), and a week leaves room to produce it late.
+	tlsReportTTL = 8 * 24 * time.Hour
+
+	// tlsReportInterval is how often the Sender's counts are added to the
+	// bucket.
+	tlsReportInterval = time.Minute
+)
+
+// mustGetTLSReports opens the TLS-RPT bucket, exiting on failure the way
+// mustGetThrottle does.
+func mustGetTLSReports(ctx context.Context, js jetstream.JetStream) jetstream.KeyValue {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      tlsReportBucket,
+		Description: "TLS sessions by day, recipient domain, policy type and result",
+		TTL:         tlsReportTTL,
+		Storage:     jetstream.FileStorage,
+		Replicas:    1,
+	})
+	if err != nil {
+		slog.Error("cannot create TLS report bucket", "bucket", tlsReportBucket, "err", err)
+		os.Exit(1)
+	}
+	return kv
+}
+
+// reportTLS adds the Sender's TLS-RPT counts to the bucket every
+// tlsReportInterval until ctx ends, and once more on the way out.
+func reportTLS(ctx context.Context, r smtp.TLSReporter, kv jetstream.KeyValue) {
+	t := time.NewTicker(tlsReportInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			flushTLSReport(flushCtx, kv, r.TLSReport(), time.Now())
+			cancel()
+			return
+		case <-t.C:
+			flushTLSReport(ctx, kv, r.TLSReport(), time.Now())
+		}
+	}
+}
+
+// flushTLSReport adds counts to the day of now. A count that cannot be added
+// is logged and dropped: the report is a statistic, and holding counts back
+// from one flush to the next would only have them land on the wrong day.
+func flushTLSReport(ctx context.Context, kv jetstream.KeyValue, counts []smtp.TLSCount, now time.Time) {
+	day := now.UTC().Format(time.DateOnly)
+	for _, c := range counts {
+		key := tlsReportKey(day, c)
+		if err := addCount(ctx, kv, key, c.Count); err != nil {
+			slog.Warn("cannot record TLS report count", "key", key, "count", c.Count, "err", err)
+		}
+	}
+}
+
+// tlsReportKey names a counter. The domain keeps its dots, which are the key's
+// token separators too: the policy and result are always the last two tokens.
+func tlsReportKey(day string, c smtp.TLSCount) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, strings.Trim(c.Domain, "."))
+	return day + "." + safe + "." + c.Policy + "." + c.Result
+}
+
+// addCount adds n to the counter at key by compare-and-swap, as countMinute
+// does.
+func addCount(ctx context.Context, kv jetstream.KeyValue, key string, n uint64) error {
+	for range counterRetries {
+		entry, err := kv.Get(ctx, key)
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			if _, err := kv.Create(ctx, key, []byte(strconv.FormatUint(n, 10))); err == nil {
+				return nil
+			} else if !errors.Is(err, jetstream.ErrKeyExists) {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		total, _ := strconv.ParseUint(string(entry.Value()), 10, 64)
+		if _, err := kv.Update(ctx, key, []byte(strconv.FormatUint(total+n, 10)), entry.Revision()); err == nil {
+			return nil
+		} else if !errors.Is(err, jetstream.ErrKeyExists) {
+			return err
+		}
+	}
+	return fmt.Errorf("lost %d updates in a row", counterRetries)
+}
@@ -0,0 +1,67 @@
+package smtpsender
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/envelope"
+	"github.com/kannon-email/kannon/internal/envelopepb"
+	"github.com/kannon-email/kannon/internal/smtp"
+	"github.com/kannon-email/kannon/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// An Envelope whose Domain or Batch requires TLS is handed to the Sender with
+// the requirement, which is where it is enforced.
+func TestRequiredTLSReachesTheSender(t *testing.T) {
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+
+	to := "strict@example.com"
+	data, err := proto.Marshal(envelopepb.FromEnvelope(envelope.New(envelope.Params{
+		EmailID:    "<" + base64.URLEncoding.EncodeToString([]byte(to)) + "/msg_test@example.com>",
+		To:         to,
+		Body:       []byte("body"),
+		RequireTLS: true,
+	})))
+	require.NoError(t, err)
+
+	sender := &recordingSender{}
+	s := &smtpSender{sender: sender, publisher: &recordingPublisher{}, js: js}
+	require.NoError(t, s.handleMessage(ctx, &fakeMsg{data: data, seq: 1}))
+	assert.True(t, sender.opts.RequireTLS)
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "relaxed@example.com", 2)))
+	assert.False(t, sender.opts.RequireTLS)
+}
+
+// Counts from every replica, flushed every minute, add up to one counter per
+// day, domain, policy type and result.
+func TestTLSReportSumsFlushesPerDay(t *testing.T) {
+	ctx := t.Context()
+	kv := mustGetTLSReports(ctx, tests.NatsJetStream(t))
+	day := time.Date(2026, 10, 19, 23, 59, 0, 0, time.UTC)
+
+	flushTLSReport(ctx, kv, []smtp.TLSCount{
+		{Domain: "gmail.com", Policy: "sts", Result: "success", Count: 40},
+		{Domain: "example.org", Policy: "no-policy-found", Result: "starttls-not-supported", Count: 2},
+	}, day)
+	flushTLSReport(ctx, kv, []smtp.TLSCount{
+		{Domain: "gmail.com", Policy: "sts", Result: "success", Count: 2},
+	}, day)
+	flushTLSReport(ctx, kv, []smtp.TLSCount{
+		{Domain: "gmail.com", Policy: "sts", Result: "success", Count: 7},
+	}, day.Add(time.Minute))
+
+	count := func(key string) string {
+		entry, err := kv.Get(ctx, key)
+		require.NoError(t, err, key)
+		return string(entry.Value())
+	}
+	assert.Equal(t, "42", count("2026-10-19.gmail.com.sts.success"))
+	assert.Equal(t, "2", count("2026-10-19.example.org.no-policy-found.starttls-not-supported"))
+	assert.Equal(t, "7", count("2026-10-20.gmail.com.sts.success"), "a new UTC day starts a new count")
+}
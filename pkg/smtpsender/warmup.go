@@ -0,0 +1,104 @@
+package smtpsender
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/envelope"
+	"github.com/kannon-email/kannon/internal/smtp"
+	"github.com/kannon-email/kannon/internal/warmup"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// mustGetWarmupCounter opens the warm-up's key/value bucket, exiting on
+// failure the way mustGetThrottle does: a ramp that is configured and not
+// enforced is the flood from a new address it was configured to prevent.
+func mustGetWarmupCounter(ctx context.Context, js jetstream.JetStream) warmup.Counter {
+	counter, err := warmup.OpenNATS(ctx, js)
+	if err != nil {
+		slog.Error("cannot create warm-up bucket", "bucket", warmup.Bucket, "err", err)
+		os.Exit(1)
+	}
+
+	slog.Info("IP warm-up ready", "bucket", warmup.Bucket)
+	return counter
+}
+
+// warmupSpentError is an Envelope no address of its pool may send yet. It is
+// to come back once one may, which is usually the next day and so postponed
+// in the Pool rather than held on the stream (holdBack).
+type warmupSpentError struct {
+	pool string
+	wait time.Duration
+}
+
+func (e *warmupSpentError) Error() string {
+	return fmt.Sprintf("IP pool %s has spent its warm-up allowance, retrying in %v", e.pool, e.wait.Round(time.Second))
+}
+
+func (e *warmupSpentError) retryIn(uint64) time.Duration { return e.wait }
+
+func (e *warmupSpentError) reason() string { return "IP warm-up allowance spent" }
+
+// chooseSource holds the Envelope to the warm-up of the addresses it could be
+// sent from, and returns the one it is to be sent from: empty, to leave the
+// choice to the Sender, when none of them is warming.
+//
+// Addresses are tried in the order the pool would use them, so that mail goes
+// out from the address it would have anyway whenever that one may send. An
+// address that may not is passed over for the next when the warm-up reroutes,
+// and otherwise holds the Envelope back; an Envelope no address may send is
+// held back until the first window that closes.
+//
+// Like the throttle, the warm-up fails open: an address sends its mail
+// rather than none when its counters cannot be reached. It counts a message
+// when it is admitted, so one that then fails to send has still spent its
+// place in the ramp — which is what a receiver counts, too.
+func (s *smtpSender) chooseSource(ctx context.Context, env *envelope.Envelope) (string, error) {
+	if !s.warmup.Enabled() {
+		return "", nil
+	}
+	chooser, ok := s.sender.(smtp.SourceChooser)
+	if !ok {
+		return "", nil
+	}
+	pool, addrs := chooser.Sources(env.ReturnPath(), env.To(), smtp.SendOptions{IPPool: env.IPPool()})
+	if len(addrs) == 0 {
+		return "", nil
+	}
+
+	var dest string
+	if s.warmup.ByDestination() {
+		if at := strings.LastIndexByte(env.To(), '@'); at >= 0 {
+			dest = s.destinations.resolve(ctx, env.To()[at+1:]).name
+		}
+	}
+
+	now := s.now()
+	var until time.Time
+	for _, ip := range addrs {
+		windows := s.warmup.Windows(ip, dest, now)
+		if len(windows) == 0 {
+			return ip, nil
+		}
+		spent, err := s.warmCounter.Take(ctx, windows)
+		if err != nil {
+			slog.Error("warm-up counters unavailable, sending anyway", "address", ip, "err", err)
+			return ip, nil
+		}
+		if spent == nil {
+			return ip, nil
+		}
+		if until.IsZero() || spent.Ends.Before(until) {
+			until = spent.Ends
+		}
+		if !s.warmup.Reroute() {
+			break
+		}
+	}
+	return "", &warmupSpentError{pool: pool, wait: jitter(until.Sub(now), rateRetryJitter)}
+}
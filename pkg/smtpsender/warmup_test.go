@@ -0,0 +1,138 @@
+package smtpsender
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/smtp"
+	"github.com/kannon-email/kannon/internal/tests"
+	"github.com/kannon-email/kannon/internal/warmup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pooledSender is a recordingSender with a pool of two addresses, offered in
+// the order given.
+type pooledSender struct {
+	recordingSender
+	addresses []string
+}
+
+func (s *pooledSender) Sources(_, _ string, _ smtp.SendOptions) (string, []string) {
+	return "tx", s.addresses
+}
+
+// warmingSender is an SMTPSender whose pool's first address is on the first day
+// of a plan allowing one message, and whose second is warm unless warmSecond
+// is false.
+func warmingSender(t *testing.T, whenSpent string, warmSecond bool) (*smtpSender, *pooledSender, *countingGuard) {
+	t.Helper()
+	ctx := t.Context()
+	js := tests.NatsJetStream(t)
+
+	addresses := []warmup.AddressConfig{{Address: "192.0.2.1", Plan: "new", Start: "2026-10-19"}}
+	if !warmSecond {
+		addresses = append(addresses, warmup.AddressConfig{Address: "192.0.2.2", Plan: "new", Start: "2026-10-19"})
+	}
+	schedule, err := warmup.New(warmup.Config{
+		WhenSpent: whenSpent,
+		Plans:     map[string]warmup.PlanConfig{"new": {Daily: []int{1, 10}}},
+		Addresses: addresses,
+	})
+	require.NoError(t, err)
+
+	sender := &pooledSender{addresses: []string{"192.0.2.1", "192.0.2.2"}}
+	guard := &countingGuard{inner: mustGetSendGuard(ctx, js)}
+	s := &smtpSender{
+		sender:      sender,
+		publisher:   &recordingPublisher{},
+		js:          js,
+		guard:       guard,
+		warmup:      schedule,
+		warmCounter: mustGetWarmupCounter(ctx, js),
+		now:         func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) },
+	}
+	return s, sender, guard
+}
+
+func TestWarmingAddressSendsItsAllowanceThenReroutes(t *testing.T) {
+	ctx := t.Context()
+	s, sender, _ := warmingSender(t, "", true)
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "first@example.com", 1)))
+	assert.Equal(t, "192.0.2.1", sender.opts.SourceIP)
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "second@example.com", 2)))
+	assert.Equal(t, "192.0.2.2", sender.opts.SourceIP, "a spent address hands its mail to the next of the pool")
+}
+
+// Held back until the day is over, an Envelope is postponed in the Pool rather
+// than left on the stream past the Dispatcher's reclaim, and takes no claim
+// that would have it dropped as a redelivery.
+func TestEnvelopeNoAddressMaySendIsPostponedUntilTheWindowCloses(t *testing.T) {
+	ctx := t.Context()
+	s, sender, guard := warmingSender(t, warmup.WhenSpentReroute, false)
+	pub := s.publisher.(*recordingPublisher)
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "first@example.com", 1)))
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "second@example.com", 2)))
+	assert.Equal(t, "192.0.2.2", sender.opts.SourceIP)
+	assert.Equal(t, 2, guard.count())
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "third@example.com", 3)), "the message is acknowledged")
+	assert.Equal(t, 2, guard.count(), "a held-back Envelope takes no claim")
+
+	published := pub.stats(t)
+	require.Len(t, published, 3)
+	postponed := published[2].Data.GetPostponed()
+	require.NotNil(t, postponed, "got %v", pub.subjects())
+	assert.Equal(t, "third@example.com", published[2].Email)
+	nextDay := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	assert.False(t, postponed.Until.AsTime().Before(nextDay), "until the next UTC day")
+	assert.True(t, postponed.Until.AsTime().Before(nextDay.Add(rateRetryJitter)))
+}
+
+// A hold-back within maxStreamHold stays on the stream, for handleMsgAck to
+// nak with its delay.
+func TestShortHoldBackStaysOnTheStream(t *testing.T) {
+	ctx := t.Context()
+	s, _, _ := warmingSender(t, warmup.WhenSpentReroute, false)
+	s.now = func() time.Time { return time.Date(2026, 10, 19, 23, 58, 0, 0, time.UTC) }
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "first@example.com", 1)))
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "second@example.com", 2)))
+
+	err := s.handleMessage(ctx, envelopeMsg(t, "third@example.com", 3))
+	var spent *warmupSpentError
+	require.True(t, errors.As(err, &spent), "got %v", err)
+	assert.LessOrEqual(t, spent.retryIn(0), maxStreamHold)
+}
+
+func TestDelayHoldsMailBackRatherThanRerouting(t *testing.T) {
+	ctx := t.Context()
+	s, sender, _ := warmingSender(t, warmup.WhenSpentDelay, true)
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "first@example.com", 1)))
+	assert.Equal(t, "192.0.2.1", sender.opts.SourceIP)
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "second@example.com", 2)))
+	assert.Equal(t, []string{"kannon.stats.delivered", "kannon.stats.postponed"}, s.publisher.(*recordingPublisher).subjects(),
+		"the warm second address is not tried")
+}
+
+// A Sender that cannot say where it sends from, or a pool with no warming
+// address, leaves the choice to the Sender as it always has.
+func TestWarmupLeavesTheChoiceAloneWhenNothingIsWarming(t *testing.T) {
+	ctx := t.Context()
+	s, sender, _ := warmingSender(t, "", true)
+	sender.addresses = []string{"192.0.2.2"}
+
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "first@example.com", 1)))
+	assert.Equal(t, "192.0.2.2", sender.opts.SourceIP)
+
+	plain := &recordingSender{}
+	s.sender = plain
+	require.NoError(t, s.handleMessage(ctx, envelopeMsg(t, "second@example.com", 2)))
+	assert.Empty(t, plain.opts.SourceIP)
+}
@@ -0,0 +1,78 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kannon-email/kannon/internal/bounce"
+	sq "github.com/kannon-email/kannon/internal/db"
+	"github.com/kannon-email/kannon/internal/stats"
+	"github.com/kannon-email/kannon/internal/statspb"
+	"github.com/kannon-email/kannon/internal/tests"
+	"github.com/kannon-email/kannon/internal/tracking"
+	"github.com/kannon-email/kannon/internal/values"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bounceEvent(t *testing.T, domain string, outcome stats.Outcome) *fakeMsg {
+	t.Helper()
+
+	data, err := statspb.MarshalEvent(stats.Event{
+		MessageID:    "batch@" + domain,
+		Email:        "rcpt@example.com",
+		Domain:       domain,
+		Timestamp:    time.Now().UTC(),
+		TrackingMode: tracking.ModeFull,
+		Outcome:      outcome,
+	})
+	require.NoError(t, err)
+	return &fakeMsg{data: data}
+}
+
+// TestBouncesAreClassified follows a synchronous and an asynchronous Bounce through both handlers:
+// each is stored with the category its code or its text gives it, and counted under that category.
+func TestBouncesAreClassified(t *testing.T) {
+	domain := tests.FakeDomain(t)
+	h := newTestHandler()
+
+	synchronous := stats.Bounced(false, 452, "mailbox full").WithSMTP(stats.SMTPSession{
+		Stage: "rcpt", EnhancedCode: "4.2.2", Reply: "452 4.2.2 Mailbox full",
+	})
+	asynchronous := stats.Bounced(true, 554, "SMTP; 554 Service unavailable; Client host blocked using zen.spamhaus.org")
+
+	for _, o := range []stats.Outcome{synchronous, asynchronous} {
+		msg := bounceEvent(t, domain, o)
+		require.NoError(t, h.handleStatsMsg(t.Context(), msg))
+		assert.Equal(t, 1, msg.acked)
+
+		counted := bounceEvent(t, domain, o)
+		require.NoError(t, h.handleAggregatedStatsMsg(t.Context(), counted))
+		assert.Equal(t, 1, counted.acked)
+	}
+
+	rows, err := sq.NewStatsRepository(db).QueryTimeline(t.Context(), values.MustParse(domain), stats.TimeRange{
+		Start: time.Now().UTC().Add(-time.Hour),
+		Stop:  time.Now().UTC().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	categories := map[bounce.Category]int64{}
+	for _, r := range rows {
+		categories[r.Category] += r.Count
+	}
+	assert.Equal(t, map[bounce.Category]int64{
+		bounce.CategoryMailboxFull: 1,
+		bounce.CategorySpamBlock:   1,
+	}, categories)
+
+	assert.Equal(t, int64(2), aggregatedCount(t, domain, stats.TypeBounce))
+}
+
+func TestAClassifiedBounceKeepsItsCategory(t *testing.T) {
+	h := statsHandler{classifier: bounce.Default()}
+
+	o := stats.Bounced(true, 550, "550 5.1.1 user unknown").WithCategory(bounce.CategoryOther)
+	assert.Equal(t, bounce.CategoryOther, h.classify(o).Category())
+
+	assert.Empty(t, h.classify(stats.Delivered()).Category(), "only a Bounce has a category")
+}
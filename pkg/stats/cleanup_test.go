@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	schema "github.com/kannon-email/kannon/db"
+	"github.com/kannon-email/kannon/internal/bounce"
 	sq "github.com/kannon-email/kannon/internal/db"
 	"github.com/kannon-email/kannon/internal/runner"
 	"github.com/kannon-email/kannon/internal/stats"
@@ -49,9 +50,10 @@ func newTestHandler() statsHandler {
 	service := stats.NewService(repo,
 		stats.WithAggregatedStatsRepository(sq.NewAggregatedStatsRepository(db)))
 	return statsHandler{
-		service:   service,
-		q:         q,
-		retention: testRetention,
+		service:    service,
+		q:          q,
+		retention:  testRetention,
+		classifier: bounce.Default(),
 	}
 }
 
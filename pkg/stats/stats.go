@@ -8,6 +8,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/kannon-email/kannon/internal/bounce"
 	sq "github.com/kannon-email/kannon/internal/db"
 	"github.com/kannon-email/kannon/internal/runner"
 	"github.com/kannon-email/kannon/internal/stats"
@@ -23,6 +24,10 @@ import (
 
 type Config struct {
 	Retention time.Duration `mapstructure:"retention"`
+	// BounceRules is a rule file of the operator's own, tried before the
+	// embedded rules Bounces are classified by (internal/bounce). Empty for
+	// the embedded rules alone.
+	BounceRules string `mapstructure:"bounce_rules"`
 }
 
 func (c *Config) setDefaults() {
@@ -32,10 +37,11 @@ func (c *Config) setDefaults() {
 }
 
 type statsHandler struct {
-	js        jetstream.JetStream
-	service   *stats.Service
-	q         *sq.Queries
-	retention time.Duration
+	js         jetstream.JetStream
+	service    *stats.Service
+	q          *sq.Queries
+	retention  time.Duration
+	classifier *bounce.Classifier
 }
 
 // New constructs the stats runnable, loading its slice of configuration from
@@ -53,6 +59,11 @@ func New(cnt *container.Container) container.Runnable {
 }
 
 func run(ctx context.Context, cnt *container.Container, cfg Config) error {
+	classifier, err := bounce.Load(cfg.BounceRules)
+	if err != nil {
+		return err
+	}
+
 	q := cnt.Queries()
 	js := cnt.NatsJetStream()
 
@@ -62,10 +73,11 @@ func run(ctx context.Context, cnt *container.Container, cfg Config) error {
 	service := stats.NewService(repo, stats.WithAggregatedStatsRepository(aggregatedRepo))
 
 	h := statsHandler{
-		js:        js,
-		service:   service,
-		q:         q,
-		retention: cfg.Retention,
+		js:         js,
+		service:    service,
+		q:          q,
+		retention:  cfg.Retention,
+		classifier: classifier,
 	}
 
 	eg, ctx := errgroup.WithContext(ctx)
@@ -144,10 +156,10 @@ func (h *statsHandler) handleAggregatedStats(ctx context.Context) error {
 }
 
 // handleAggregatedStatsMsg counts one stat event against its Domain's hourly counter, reading only
-// the Domain, timestamp and type — so every Mode alike, Anonymous included. Splitting the subject
+// the Domain, timestamp, type and a Bounce's category — so every Mode alike, Anonymous included. Splitting the subject
 // to stop the two consumers overlapping was rejected: kannon.stats.* matches no longer subject.
 func (h *statsHandler) handleAggregatedStatsMsg(ctx context.Context, msg jetstream.Msg) error {
-	event, ok := decodeEvent(msg)
+	event, ok := h.decodeEvent(msg)
 	if !ok {
 		return msg.Term()
 	}
@@ -158,7 +170,7 @@ func (h *statsHandler) handleAggregatedStatsMsg(ctx context.Context, msg jetstre
 	}
 
 	statType := event.Outcome.Type()
-	if err := h.service.IncrementAggregatedStat(ctx, domain, event.Timestamp, statType); err != nil {
+	if err := h.service.IncrementAggregatedStat(ctx, domain, event.Timestamp, statType, event.Outcome.Category()); err != nil {
 		slog.Error("cannot increment aggregated stat", "err", err)
 		return msg.Nak()
 	}
@@ -166,16 +178,38 @@ func (h *statsHandler) handleAggregatedStatsMsg(ctx context.Context, msg jetstre
 	return msg.Ack()
 }
 
-// decodeEvent reads a published stat message into the domain Event both handlers work in. This is
-// the only place in the worker that knows the events arrive as protobuf.
-func decodeEvent(msg jetstream.Msg) (stats.Event, bool) {
+// decodeEvent reads a published stat message into the domain Event both handlers work in, a
+// Bounce classified. This is the only place in the worker that knows the events arrive as
+// protobuf, and the one place Bounces are classified: the SMTPSender and the SMTP server that
+// takes DSNs both publish here, so one set of rules covers the synchronous and the asynchronous
+// alike, and the row and the counter of a Bounce cannot disagree on its category.
+func (h *statsHandler) decodeEvent(msg jetstream.Msg) (stats.Event, bool) {
 	event, err := statspb.UnmarshalEvent(msg.Data())
 	if err != nil {
 		return stats.Event{}, false
 	}
+	event.Outcome = h.classify(event.Outcome)
 	return event, true
 }
 
+// classify is o with its Category, when o is a Bounce no producer already classified. The
+// enhanced status code is the one the SMTPSender recorded, or for a DSN the one its text quotes;
+// the text is the reply as the host wrote it, when the SMTPSender recorded one.
+func (h *statsHandler) classify(o stats.Outcome) stats.Outcome {
+	if o.Type() != stats.TypeBounce || o.Category() != "" {
+		return o
+	}
+	session := o.SMTP()
+	code, reply := session.EnhancedCode, session.Reply
+	if reply == "" {
+		reply = o.Msg()
+	}
+	if code == "" {
+		code = bounce.EnhancedCodeOf(reply)
+	}
+	return o.WithCategory(h.classifier.Classify(code, reply))
+}
+
 // eventDomain canonicalises the Domain an event was published under, reporting false when the
 // value is not a domain name at all. The published value comes from a Domain row, so a failure is
 // a fault in the message: both handlers Term it, since Nak'ing would reproduce the #396 hot loop.
@@ -193,7 +227,7 @@ func eventDomain(event stats.Event) (values.DomainName, bool) {
 // counted in aggregate only (CONTEXT.md), and one under Pseudonymous, whose pseudonym takes this
 // same email-shaped path (ADR 0006). An event naming nobody under any other Mode is Termed.
 func (h *statsHandler) handleStatsMsg(ctx context.Context, msg jetstream.Msg) error {
-	event, ok := decodeEvent(msg)
+	event, ok := h.decodeEvent(msg)
 	if !ok {
 		return msg.Term()
 	}
@@ -71,6 +71,7 @@ func (a *statsV1Impl) GetStatsAggregated(ctx context.Context, req *apiv1.GetStat
 			Type:      string(s.Type),
 			Timestamp: timestamppb.New(s.Timestamp),
 			Count:     s.Count,
+			Category:  string(s.Category),
 		})
 	}
 
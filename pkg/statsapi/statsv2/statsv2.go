@@ -53,6 +53,7 @@ func (s *statsAPIConnectAdapter) GetAggregatedStats(ctx context.Context, req *co
 			Type:      string(r.Type),
 			Timestamp: timestamppb.New(r.Timestamp),
 			Count:     r.Count,
+			Category:  string(r.Category),
 		})
 	}
 
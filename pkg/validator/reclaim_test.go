@@ -68,11 +68,7 @@ func seedReclaimBatch(t *testing.T) (batch.ID, string) {
 	ctx := t.Context()
 
 	domain := fmt.Sprintf("reclaim-%d.test", time.Now().UnixNano())
-	_, err := q.CreateDomain(ctx, sqlc.CreateDomainParams{
-		Domain:         domain,
-		DkimPrivateKey: "test-private",
-		DkimPublicKey:  "test-public",
-	})
+	_, err := q.CreateDomain(ctx, domain)
 	require.NoError(t, err)
 
 	b, err := batch.New(batch.NewParams{
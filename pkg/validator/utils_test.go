@@ -16,3 +16,9 @@ func TestInvalidEmail(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.ErrorIs(t, ErrInvalidEmailAddress, err)
 }
+
+func TestInternationalisedEmail(t *testing.T) {
+	assert.Nil(t, validateEmail("用户@例子.中国"))
+	assert.Nil(t, validateEmail("jörg@müller.de"))
+	assert.ErrorIs(t, validateEmail("用户 例子.中国"), ErrInvalidEmailAddress)
+}
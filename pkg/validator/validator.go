@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	sqlc "github.com/kannon-email/kannon/internal/db"
 	"github.com/kannon-email/kannon/internal/delivery"
@@ -16,6 +17,7 @@ import (
 	"github.com/kannon-email/kannon/internal/runner"
 	"github.com/kannon-email/kannon/internal/stats"
 	"github.com/kannon-email/kannon/x/container"
+	"golang.org/x/net/idna"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -120,10 +122,32 @@ func validateEmail(email string) error {
 	if strings.HasSuffix(email, "@localhost") {
 		return nil
 	}
-	if emailReg.Match([]byte(email)) {
+	if emailReg.MatchString(asciiForm(email)) {
 		return nil
 	}
 	return ErrInvalidEmailAddress
 }
 
+// asciiForm is email as emailReg can judge it. An internationalised address
+// (RFC 6531) is valid when its domain has an A-label and its local part is
+// UTF-8 that would be valid were its non-ASCII characters letters: the Sender
+// delivers it over SMTPUTF8, or bounces it where that is not offered.
+func asciiForm(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 || !utf8.ValidString(email) {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+	if d, err := idna.Lookup.ToASCII(domain); err == nil {
+		domain = d
+	}
+	local = strings.Map(func(r rune) rune {
+		if r >= utf8.RuneSelf {
+			return 'x'
+		}
+		return r
+	}, local)
+	return local + "@" + domain
+}
+
 var ErrInvalidEmailAddress = errors.New(" is not a valid email")
@@ -7,6 +7,8 @@
 package apiv1
 
 import (
+	types1 "github.com/kannon-email/kannon/proto/kannon/feedback/types"
+	types2 "github.com/kannon-email/kannon/proto/kannon/linkparams/types"
 	types "github.com/kannon-email/kannon/proto/kannon/tracking/types"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -23,6 +25,66 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type DKIMKeyState int32
+
+const (
+	DKIMKeyState_DKIM_KEY_STATE_UNSPECIFIED DKIMKeyState = 0
+	// Created, not yet signing: its record is to be published.
+	DKIMKeyState_DKIM_KEY_STATE_PENDING DKIMKeyState = 1
+	// The key mail is signed with, one per algorithm.
+	DKIMKeyState_DKIM_KEY_STATE_ACTIVE DKIMKeyState = 2
+	// Replaced by a newer key of its algorithm; its record must stay published
+	// until retired.
+	DKIMKeyState_DKIM_KEY_STATE_SUPERSEDED DKIMKeyState = 3
+	// Withdrawn from DNS.
+	DKIMKeyState_DKIM_KEY_STATE_RETIRED DKIMKeyState = 4
+)
+
+// Enum value maps for DKIMKeyState.
+var (
+	DKIMKeyState_name = map[int32]string{
+		0: "DKIM_KEY_STATE_UNSPECIFIED",
+		1: "DKIM_KEY_STATE_PENDING",
+		2: "DKIM_KEY_STATE_ACTIVE",
+		3: "DKIM_KEY_STATE_SUPERSEDED",
+		4: "DKIM_KEY_STATE_RETIRED",
+	}
+	DKIMKeyState_value = map[string]int32{
+		"DKIM_KEY_STATE_UNSPECIFIED": 0,
+		"DKIM_KEY_STATE_PENDING":     1,
+		"DKIM_KEY_STATE_ACTIVE":      2,
+		"DKIM_KEY_STATE_SUPERSEDED":  3,
+		"DKIM_KEY_STATE_RETIRED":     4,
+	}
+)
+
+func (x DKIMKeyState) Enum() *DKIMKeyState {
+	p := new(DKIMKeyState)
+	*p = x
+	return p
+}
+
+func (x DKIMKeyState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DKIMKeyState) Descriptor() protoreflect.EnumDescriptor {
+	return file_kannon_admin_apiv1_adminapiv1_proto_enumTypes[0].Descriptor()
+}
+
+func (DKIMKeyState) Type() protoreflect.EnumType {
+	return &file_kannon_admin_apiv1_adminapiv1_proto_enumTypes[0]
+}
+
+func (x DKIMKeyState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DKIMKeyState.Descriptor instead.
+func (DKIMKeyState) EnumDescriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{0}
+}
+
 type GetDomainsReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -192,8 +254,16 @@ func (x *GetDomainRes) GetDomain() *Domain {
 }
 
 type CreateDomainRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Domain string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// How the domain's first DKIM key is come by. When unset, a 2048-bit RSA key
+	// is generated under the selector "kannon".
+	//
+	// Types that are valid to be assigned to DkimKey:
+	//
+	//	*CreateDomainRequest_DkimRsaBits
+	//	*CreateDomainRequest_DkimImport
+	DkimKey       isCreateDomainRequest_DkimKey `protobuf_oneof:"dkim_key"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -235,30 +305,75 @@ func (x *CreateDomainRequest) GetDomain() string {
 	return ""
 }
 
-type Domain struct {
-	state      protoimpl.MessageState `protogen:"open.v1"`
-	Domain     string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
-	DkimPubKey string                 `protobuf:"bytes,3,opt,name=dkim_pub_key,json=dkimPubKey,proto3" json:"dkim_pub_key,omitempty"`
-	// The ceiling every batch and recipient of this domain is resolved against.
-	Tracking      *types.TrackingPolicy `protobuf:"bytes,4,opt,name=tracking,proto3" json:"tracking,omitempty"`
+func (x *CreateDomainRequest) GetDkimKey() isCreateDomainRequest_DkimKey {
+	if x != nil {
+		return x.DkimKey
+	}
+	return nil
+}
+
+func (x *CreateDomainRequest) GetDkimRsaBits() uint32 {
+	if x != nil {
+		if x, ok := x.DkimKey.(*CreateDomainRequest_DkimRsaBits); ok {
+			return x.DkimRsaBits
+		}
+	}
+	return 0
+}
+
+func (x *CreateDomainRequest) GetDkimImport() *ImportedDKIMKey {
+	if x != nil {
+		if x, ok := x.DkimKey.(*CreateDomainRequest_DkimImport); ok {
+			return x.DkimImport
+		}
+	}
+	return nil
+}
+
+type isCreateDomainRequest_DkimKey interface {
+	isCreateDomainRequest_DkimKey()
+}
+
+type CreateDomainRequest_DkimRsaBits struct {
+	// Generate an RSA key of this many bits: 1024, 2048 or 4096.
+	DkimRsaBits uint32 `protobuf:"varint,2,opt,name=dkim_rsa_bits,json=dkimRsaBits,proto3,oneof"`
+}
+
+type CreateDomainRequest_DkimImport struct {
+	// Import the key the domain already publishes, e.g. when migrating from
+	// another provider, so that no DNS record has to change.
+	DkimImport *ImportedDKIMKey `protobuf:"bytes,3,opt,name=dkim_import,json=dkimImport,proto3,oneof"`
+}
+
+func (*CreateDomainRequest_DkimRsaBits) isCreateDomainRequest_DkimKey() {}
+
+func (*CreateDomainRequest_DkimImport) isCreateDomainRequest_DkimKey() {}
+
+type ImportedDKIMKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The selector the key is already published under.
+	Selector string `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	// An unencrypted PEM private key: PKCS#1 ("RSA PRIVATE KEY") or PKCS#8
+	// ("PRIVATE KEY"), RSA of at least 1024 bits or Ed25519.
+	PrivateKeyPem string `protobuf:"bytes,2,opt,name=private_key_pem,json=privateKeyPem,proto3" json:"private_key_pem,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Domain) Reset() {
-	*x = Domain{}
+func (x *ImportedDKIMKey) Reset() {
+	*x = ImportedDKIMKey{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Domain) String() string {
+func (x *ImportedDKIMKey) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Domain) ProtoMessage() {}
+func (*ImportedDKIMKey) ProtoMessage() {}
 
-func (x *Domain) ProtoReflect() protoreflect.Message {
+func (x *ImportedDKIMKey) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -270,54 +385,73 @@ func (x *Domain) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Domain.ProtoReflect.Descriptor instead.
-func (*Domain) Descriptor() ([]byte, []int) {
+// Deprecated: Use ImportedDKIMKey.ProtoReflect.Descriptor instead.
+func (*ImportedDKIMKey) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Domain) GetDomain() string {
+func (x *ImportedDKIMKey) GetSelector() string {
 	if x != nil {
-		return x.Domain
+		return x.Selector
 	}
 	return ""
 }
 
-func (x *Domain) GetDkimPubKey() string {
+func (x *ImportedDKIMKey) GetPrivateKeyPem() string {
 	if x != nil {
-		return x.DkimPubKey
+		return x.PrivateKeyPem
 	}
 	return ""
 }
 
-func (x *Domain) GetTracking() *types.TrackingPolicy {
-	if x != nil {
-		return x.Tracking
-	}
-	return nil
-}
-
-type SetTrackingPolicyReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
-	Tracking      *types.TrackingPolicy  `protobuf:"bytes,2,opt,name=tracking,proto3" json:"tracking,omitempty"`
+type Domain struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Domain string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// The public key of the RSA DKIM key mail is currently signed with.
+	DkimPubKey string `protobuf:"bytes,3,opt,name=dkim_pub_key,json=dkimPubKey,proto3" json:"dkim_pub_key,omitempty"`
+	// The ceiling every batch and recipient of this domain is resolved against.
+	Tracking *types.TrackingPolicy `protobuf:"bytes,4,opt,name=tracking,proto3" json:"tracking,omitempty"`
+	// Every DKIM key of the domain, oldest first, retired ones included.
+	DkimKeys []*DKIMKey `protobuf:"bytes,5,rep,name=dkim_keys,json=dkimKeys,proto3" json:"dkim_keys,omitempty"`
+	// The subdomain bounces are addressed to, the domain of every MAIL FROM;
+	// empty when they are addressed to the domain itself.
+	ReturnPathDomain string `protobuf:"bytes,6,opt,name=return_path_domain,json=returnPathDomain,proto3" json:"return_path_domain,omitempty"`
+	// The records return_path_domain needs: an MX so bounces reach Kannon, and
+	// an SPF record so the MAIL FROM passes. Empty without a return-path domain.
+	ReturnPathRecords []*DNSRecord `protobuf:"bytes,7,rep,name=return_path_records,json=returnPathRecords,proto3" json:"return_path_records,omitempty"`
+	// The Feedback-ID and List-Id every batch of the domain carries, unless the
+	// batch overrides them.
+	Feedback *types1.FeedbackIdentity `protobuf:"bytes,8,opt,name=feedback,proto3" json:"feedback,omitempty"`
+	// The S/MIME certificate chain the domain's mail is signed with, PEM, leaf
+	// first; empty when it is not signed.
+	SmimeCertificate string `protobuf:"bytes,9,opt,name=smime_certificate,json=smimeCertificate,proto3" json:"smime_certificate,omitempty"`
+	// The query parameters appended to the domain's links, unless a batch
+	// overrides them, and the hosts they are appended on.
+	LinkDecoration *types2.LinkDecoration `protobuf:"bytes,10,opt,name=link_decoration,json=linkDecoration,proto3" json:"link_decoration,omitempty"`
+	// Whether the domain's mail is held back rather than sent without
+	// authenticated TLS.
+	RequireTls bool `protobuf:"varint,11,opt,name=require_tls,json=requireTls,proto3" json:"require_tls,omitempty"`
+	// The IP pool the domain's mail is sent from, unless a batch names its own;
+	// empty for the sender's default pool.
+	IpPool        string `protobuf:"bytes,12,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetTrackingPolicyReq) Reset() {
-	*x = SetTrackingPolicyReq{}
+func (x *Domain) Reset() {
+	*x = Domain{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetTrackingPolicyReq) String() string {
+func (x *Domain) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetTrackingPolicyReq) ProtoMessage() {}
+func (*Domain) ProtoMessage() {}
 
-func (x *SetTrackingPolicyReq) ProtoReflect() protoreflect.Message {
+func (x *Domain) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -329,46 +463,112 @@ func (x *SetTrackingPolicyReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetTrackingPolicyReq.ProtoReflect.Descriptor instead.
-func (*SetTrackingPolicyReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use Domain.ProtoReflect.Descriptor instead.
+func (*Domain) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *SetTrackingPolicyReq) GetDomain() string {
+func (x *Domain) GetDomain() string {
 	if x != nil {
 		return x.Domain
 	}
 	return ""
 }
 
-func (x *SetTrackingPolicyReq) GetTracking() *types.TrackingPolicy {
+func (x *Domain) GetDkimPubKey() string {
+	if x != nil {
+		return x.DkimPubKey
+	}
+	return ""
+}
+
+func (x *Domain) GetTracking() *types.TrackingPolicy {
 	if x != nil {
 		return x.Tracking
 	}
 	return nil
 }
 
-type SetTrackingPolicyRes struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+func (x *Domain) GetDkimKeys() []*DKIMKey {
+	if x != nil {
+		return x.DkimKeys
+	}
+	return nil
+}
+
+func (x *Domain) GetReturnPathDomain() string {
+	if x != nil {
+		return x.ReturnPathDomain
+	}
+	return ""
+}
+
+func (x *Domain) GetReturnPathRecords() []*DNSRecord {
+	if x != nil {
+		return x.ReturnPathRecords
+	}
+	return nil
+}
+
+func (x *Domain) GetFeedback() *types1.FeedbackIdentity {
+	if x != nil {
+		return x.Feedback
+	}
+	return nil
+}
+
+func (x *Domain) GetSmimeCertificate() string {
+	if x != nil {
+		return x.SmimeCertificate
+	}
+	return ""
+}
+
+func (x *Domain) GetLinkDecoration() *types2.LinkDecoration {
+	if x != nil {
+		return x.LinkDecoration
+	}
+	return nil
+}
+
+func (x *Domain) GetRequireTls() bool {
+	if x != nil {
+		return x.RequireTls
+	}
+	return false
+}
+
+func (x *Domain) GetIpPool() string {
+	if x != nil {
+		return x.IpPool
+	}
+	return ""
+}
+
+type DNSRecord struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "MX" or "TXT".
+	Type          string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value         string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetTrackingPolicyRes) Reset() {
-	*x = SetTrackingPolicyRes{}
+func (x *DNSRecord) Reset() {
+	*x = DNSRecord{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetTrackingPolicyRes) String() string {
+func (x *DNSRecord) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetTrackingPolicyRes) ProtoMessage() {}
+func (*DNSRecord) ProtoMessage() {}
 
-func (x *SetTrackingPolicyRes) ProtoReflect() protoreflect.Message {
+func (x *DNSRecord) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -380,42 +580,67 @@ func (x *SetTrackingPolicyRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetTrackingPolicyRes.ProtoReflect.Descriptor instead.
-func (*SetTrackingPolicyRes) Descriptor() ([]byte, []int) {
+// Deprecated: Use DNSRecord.ProtoReflect.Descriptor instead.
+func (*DNSRecord) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *SetTrackingPolicyRes) GetDomain() *Domain {
+func (x *DNSRecord) GetType() string {
 	if x != nil {
-		return x.Domain
+		return x.Type
 	}
-	return nil
+	return ""
 }
 
-type Template struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
-	Html          string                 `protobuf:"bytes,2,opt,name=html,proto3" json:"html,omitempty"`
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+func (x *DNSRecord) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DNSRecord) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type DKIMKey struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Selector  string                 `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	Algorithm string                 `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	PublicKey string                 `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	State     DKIMKeyState           `protobuf:"varint,4,opt,name=state,proto3,enum=pkg.kannon.admin.apiv1.DKIMKeyState" json:"state,omitempty"`
+	// The TXT record to publish: its name and its value.
+	RecordName  string                 `protobuf:"bytes,5,opt,name=record_name,json=recordName,proto3" json:"record_name,omitempty"`
+	RecordValue string                 `protobuf:"bytes,6,opt,name=record_value,json=recordValue,proto3" json:"record_value,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ActivatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=activated_at,json=activatedAt,proto3" json:"activated_at,omitempty"`
+	RetiredAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=retired_at,json=retiredAt,proto3" json:"retired_at,omitempty"`
+	// "generated" for a key Kannon generated, "imported" for one brought along
+	// at CreateDomain.
+	Origin string `protobuf:"bytes,10,opt,name=origin,proto3" json:"origin,omitempty"`
+	// The key's size: the RSA modulus length, or 256 for Ed25519.
+	Bits          uint32 `protobuf:"varint,11,opt,name=bits,proto3" json:"bits,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Template) Reset() {
-	*x = Template{}
+func (x *DKIMKey) Reset() {
+	*x = DKIMKey{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Template) String() string {
+func (x *DKIMKey) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Template) ProtoMessage() {}
+func (*DKIMKey) ProtoMessage() {}
 
-func (x *Template) ProtoReflect() protoreflect.Message {
+func (x *DKIMKey) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -427,62 +652,114 @@ func (x *Template) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Template.ProtoReflect.Descriptor instead.
-func (*Template) Descriptor() ([]byte, []int) {
+// Deprecated: Use DKIMKey.ProtoReflect.Descriptor instead.
+func (*DKIMKey) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Template) GetTemplateId() string {
+func (x *DKIMKey) GetSelector() string {
 	if x != nil {
-		return x.TemplateId
+		return x.Selector
 	}
 	return ""
 }
 
-func (x *Template) GetHtml() string {
+func (x *DKIMKey) GetAlgorithm() string {
 	if x != nil {
-		return x.Html
+		return x.Algorithm
 	}
 	return ""
 }
 
-func (x *Template) GetTitle() string {
+func (x *DKIMKey) GetPublicKey() string {
 	if x != nil {
-		return x.Title
+		return x.PublicKey
 	}
 	return ""
 }
 
-func (x *Template) GetType() string {
+func (x *DKIMKey) GetState() DKIMKeyState {
 	if x != nil {
-		return x.Type
+		return x.State
+	}
+	return DKIMKeyState_DKIM_KEY_STATE_UNSPECIFIED
+}
+
+func (x *DKIMKey) GetRecordName() string {
+	if x != nil {
+		return x.RecordName
 	}
 	return ""
 }
 
-type CreateTemplateReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Html          string                 `protobuf:"bytes,1,opt,name=html,proto3" json:"html,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+func (x *DKIMKey) GetRecordValue() string {
+	if x != nil {
+		return x.RecordValue
+	}
+	return ""
+}
+
+func (x *DKIMKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *DKIMKey) GetActivatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActivatedAt
+	}
+	return nil
+}
+
+func (x *DKIMKey) GetRetiredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetiredAt
+	}
+	return nil
+}
+
+func (x *DKIMKey) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *DKIMKey) GetBits() uint32 {
+	if x != nil {
+		return x.Bits
+	}
+	return 0
+}
+
+type RotateDKIMKeyReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Domain string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Optional; a time-stamped selector is chosen when empty.
+	Selector string `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+	// "rsa" or "ed25519"; rsa when empty. Keys of different algorithms rotate
+	// independently, and the domain signs with the active key of each.
+	Algorithm     string `protobuf:"bytes,3,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTemplateReq) Reset() {
-	*x = CreateTemplateReq{}
+func (x *RotateDKIMKeyReq) Reset() {
+	*x = RotateDKIMKeyReq{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTemplateReq) String() string {
+func (x *RotateDKIMKeyReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTemplateReq) ProtoMessage() {}
+func (*RotateDKIMKeyReq) ProtoMessage() {}
 
-func (x *CreateTemplateReq) ProtoReflect() protoreflect.Message {
+func (x *RotateDKIMKeyReq) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -494,53 +771,54 @@ func (x *CreateTemplateReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTemplateReq.ProtoReflect.Descriptor instead.
-func (*CreateTemplateReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use RotateDKIMKeyReq.ProtoReflect.Descriptor instead.
+func (*RotateDKIMKeyReq) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *CreateTemplateReq) GetHtml() string {
+func (x *RotateDKIMKeyReq) GetDomain() string {
 	if x != nil {
-		return x.Html
+		return x.Domain
 	}
 	return ""
 }
 
-func (x *CreateTemplateReq) GetTitle() string {
+func (x *RotateDKIMKeyReq) GetSelector() string {
 	if x != nil {
-		return x.Title
+		return x.Selector
 	}
 	return ""
 }
 
-func (x *CreateTemplateReq) GetDomain() string {
+func (x *RotateDKIMKeyReq) GetAlgorithm() string {
 	if x != nil {
-		return x.Domain
+		return x.Algorithm
 	}
 	return ""
 }
 
-type CreateTemplateRes struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Template      *Template              `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+type RotateDKIMKeyRes struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The pending key, with the record to publish before activating it.
+	Key           *DKIMKey `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTemplateRes) Reset() {
-	*x = CreateTemplateRes{}
+func (x *RotateDKIMKeyRes) Reset() {
+	*x = RotateDKIMKeyRes{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTemplateRes) String() string {
+func (x *RotateDKIMKeyRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTemplateRes) ProtoMessage() {}
+func (*RotateDKIMKeyRes) ProtoMessage() {}
 
-func (x *CreateTemplateRes) ProtoReflect() protoreflect.Message {
+func (x *RotateDKIMKeyRes) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -552,41 +830,42 @@ func (x *CreateTemplateRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTemplateRes.ProtoReflect.Descriptor instead.
-func (*CreateTemplateRes) Descriptor() ([]byte, []int) {
+// Deprecated: Use RotateDKIMKeyRes.ProtoReflect.Descriptor instead.
+func (*RotateDKIMKeyRes) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *CreateTemplateRes) GetTemplate() *Template {
+func (x *RotateDKIMKeyRes) GetKey() *DKIMKey {
 	if x != nil {
-		return x.Template
+		return x.Key
 	}
 	return nil
 }
 
-type UpdateTemplateReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
-	Html          string                 `protobuf:"bytes,2,opt,name=html,proto3" json:"html,omitempty"`
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+type ActivateDKIMKeyReq struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Domain   string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Selector string                 `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+	// Refuse to activate unless the key's record resolves with the key.
+	VerifyDns     bool `protobuf:"varint,3,opt,name=verify_dns,json=verifyDns,proto3" json:"verify_dns,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateTemplateReq) Reset() {
-	*x = UpdateTemplateReq{}
+func (x *ActivateDKIMKeyReq) Reset() {
+	*x = ActivateDKIMKeyReq{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateTemplateReq) String() string {
+func (x *ActivateDKIMKeyReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateTemplateReq) ProtoMessage() {}
+func (*ActivateDKIMKeyReq) ProtoMessage() {}
 
-func (x *UpdateTemplateReq) ProtoReflect() protoreflect.Message {
+func (x *ActivateDKIMKeyReq) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -598,53 +877,53 @@ func (x *UpdateTemplateReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateTemplateReq.ProtoReflect.Descriptor instead.
-func (*UpdateTemplateReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use ActivateDKIMKeyReq.ProtoReflect.Descriptor instead.
+func (*ActivateDKIMKeyReq) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *UpdateTemplateReq) GetTemplateId() string {
+func (x *ActivateDKIMKeyReq) GetDomain() string {
 	if x != nil {
-		return x.TemplateId
+		return x.Domain
 	}
 	return ""
 }
 
-func (x *UpdateTemplateReq) GetHtml() string {
+func (x *ActivateDKIMKeyReq) GetSelector() string {
 	if x != nil {
-		return x.Html
+		return x.Selector
 	}
 	return ""
 }
 
-func (x *UpdateTemplateReq) GetTitle() string {
+func (x *ActivateDKIMKeyReq) GetVerifyDns() bool {
 	if x != nil {
-		return x.Title
+		return x.VerifyDns
 	}
-	return ""
+	return false
 }
 
-type UpdateTemplateRes struct {
+type ActivateDKIMKeyRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Template      *Template              `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateTemplateRes) Reset() {
-	*x = UpdateTemplateRes{}
+func (x *ActivateDKIMKeyRes) Reset() {
+	*x = ActivateDKIMKeyRes{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateTemplateRes) String() string {
+func (x *ActivateDKIMKeyRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateTemplateRes) ProtoMessage() {}
+func (*ActivateDKIMKeyRes) ProtoMessage() {}
 
-func (x *UpdateTemplateRes) ProtoReflect() protoreflect.Message {
+func (x *ActivateDKIMKeyRes) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -656,39 +935,40 @@ func (x *UpdateTemplateRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateTemplateRes.ProtoReflect.Descriptor instead.
-func (*UpdateTemplateRes) Descriptor() ([]byte, []int) {
+// Deprecated: Use ActivateDKIMKeyRes.ProtoReflect.Descriptor instead.
+func (*ActivateDKIMKeyRes) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *UpdateTemplateRes) GetTemplate() *Template {
+func (x *ActivateDKIMKeyRes) GetDomain() *Domain {
 	if x != nil {
-		return x.Template
+		return x.Domain
 	}
 	return nil
 }
 
-type DeleteTemplateReq struct {
+type RetireDKIMKeyReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Selector      string                 `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteTemplateReq) Reset() {
-	*x = DeleteTemplateReq{}
+func (x *RetireDKIMKeyReq) Reset() {
+	*x = RetireDKIMKeyReq{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteTemplateReq) String() string {
+func (x *RetireDKIMKeyReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTemplateReq) ProtoMessage() {}
+func (*RetireDKIMKeyReq) ProtoMessage() {}
 
-func (x *DeleteTemplateReq) ProtoReflect() protoreflect.Message {
+func (x *RetireDKIMKeyReq) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -700,39 +980,46 @@ func (x *DeleteTemplateReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTemplateReq.ProtoReflect.Descriptor instead.
-func (*DeleteTemplateReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use RetireDKIMKeyReq.ProtoReflect.Descriptor instead.
+func (*RetireDKIMKeyReq) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *DeleteTemplateReq) GetTemplateId() string {
+func (x *RetireDKIMKeyReq) GetDomain() string {
 	if x != nil {
-		return x.TemplateId
+		return x.Domain
 	}
 	return ""
 }
 
-type DeleteTemplateRes struct {
+func (x *RetireDKIMKeyReq) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+type RetireDKIMKeyRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Template      *Template              `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteTemplateRes) Reset() {
-	*x = DeleteTemplateRes{}
+func (x *RetireDKIMKeyRes) Reset() {
+	*x = RetireDKIMKeyRes{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteTemplateRes) String() string {
+func (x *RetireDKIMKeyRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTemplateRes) ProtoMessage() {}
+func (*RetireDKIMKeyRes) ProtoMessage() {}
 
-func (x *DeleteTemplateRes) ProtoReflect() protoreflect.Message {
+func (x *RetireDKIMKeyRes) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -744,39 +1031,40 @@ func (x *DeleteTemplateRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTemplateRes.ProtoReflect.Descriptor instead.
-func (*DeleteTemplateRes) Descriptor() ([]byte, []int) {
+// Deprecated: Use RetireDKIMKeyRes.ProtoReflect.Descriptor instead.
+func (*RetireDKIMKeyRes) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *DeleteTemplateRes) GetTemplate() *Template {
+func (x *RetireDKIMKeyRes) GetDomain() *Domain {
 	if x != nil {
-		return x.Template
+		return x.Domain
 	}
 	return nil
 }
 
-type GetTemplateReq struct {
+type SetTrackingPolicyReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Tracking      *types.TrackingPolicy  `protobuf:"bytes,2,opt,name=tracking,proto3" json:"tracking,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTemplateReq) Reset() {
-	*x = GetTemplateReq{}
+func (x *SetTrackingPolicyReq) Reset() {
+	*x = SetTrackingPolicyReq{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTemplateReq) String() string {
+func (x *SetTrackingPolicyReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTemplateReq) ProtoMessage() {}
+func (*SetTrackingPolicyReq) ProtoMessage() {}
 
-func (x *GetTemplateReq) ProtoReflect() protoreflect.Message {
+func (x *SetTrackingPolicyReq) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -788,39 +1076,46 @@ func (x *GetTemplateReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTemplateReq.ProtoReflect.Descriptor instead.
-func (*GetTemplateReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetTrackingPolicyReq.ProtoReflect.Descriptor instead.
+func (*SetTrackingPolicyReq) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *GetTemplateReq) GetTemplateId() string {
+func (x *SetTrackingPolicyReq) GetDomain() string {
 	if x != nil {
-		return x.TemplateId
+		return x.Domain
 	}
 	return ""
 }
 
-type GetTemplateRes struct {
+func (x *SetTrackingPolicyReq) GetTracking() *types.TrackingPolicy {
+	if x != nil {
+		return x.Tracking
+	}
+	return nil
+}
+
+type SetTrackingPolicyRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Template      *Template              `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTemplateRes) Reset() {
-	*x = GetTemplateRes{}
+func (x *SetTrackingPolicyRes) Reset() {
+	*x = SetTrackingPolicyRes{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTemplateRes) String() string {
+func (x *SetTrackingPolicyRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTemplateRes) ProtoMessage() {}
+func (*SetTrackingPolicyRes) ProtoMessage() {}
 
-func (x *GetTemplateRes) ProtoReflect() protoreflect.Message {
+func (x *SetTrackingPolicyRes) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -832,41 +1127,42 @@ func (x *GetTemplateRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTemplateRes.ProtoReflect.Descriptor instead.
-func (*GetTemplateRes) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetTrackingPolicyRes.ProtoReflect.Descriptor instead.
+func (*SetTrackingPolicyRes) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *GetTemplateRes) GetTemplate() *Template {
+func (x *SetTrackingPolicyRes) GetDomain() *Domain {
 	if x != nil {
-		return x.Template
+		return x.Domain
 	}
 	return nil
 }
 
-type GetTemplatesReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
-	Skip          uint32                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
-	Take          uint32                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type SetReturnPathDomainReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Domain string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// A subdomain of domain, e.g. "bounces.example.com"; empty to address
+	// bounces to the domain itself again.
+	ReturnPathDomain string `protobuf:"bytes,2,opt,name=return_path_domain,json=returnPathDomain,proto3" json:"return_path_domain,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *GetTemplatesReq) Reset() {
-	*x = GetTemplatesReq{}
+func (x *SetReturnPathDomainReq) Reset() {
+	*x = SetReturnPathDomainReq{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTemplatesReq) String() string {
+func (x *SetReturnPathDomainReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTemplatesReq) ProtoMessage() {}
+func (*SetReturnPathDomainReq) ProtoMessage() {}
 
-func (x *GetTemplatesReq) ProtoReflect() protoreflect.Message {
+func (x *SetReturnPathDomainReq) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -878,54 +1174,46 @@ func (x *GetTemplatesReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTemplatesReq.ProtoReflect.Descriptor instead.
-func (*GetTemplatesReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetReturnPathDomainReq.ProtoReflect.Descriptor instead.
+func (*SetReturnPathDomainReq) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *GetTemplatesReq) GetDomain() string {
+func (x *SetReturnPathDomainReq) GetDomain() string {
 	if x != nil {
 		return x.Domain
 	}
 	return ""
 }
 
-func (x *GetTemplatesReq) GetSkip() uint32 {
-	if x != nil {
-		return x.Skip
-	}
-	return 0
-}
-
-func (x *GetTemplatesReq) GetTake() uint32 {
+func (x *SetReturnPathDomainReq) GetReturnPathDomain() string {
 	if x != nil {
-		return x.Take
+		return x.ReturnPathDomain
 	}
-	return 0
+	return ""
 }
 
-type GetTemplatesRes struct {
+type SetReturnPathDomainRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Templates     []*Template            `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
-	Total         uint32                 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTemplatesRes) Reset() {
-	*x = GetTemplatesRes{}
+func (x *SetReturnPathDomainRes) Reset() {
+	*x = SetReturnPathDomainRes{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTemplatesRes) String() string {
+func (x *SetReturnPathDomainRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTemplatesRes) ProtoMessage() {}
+func (*SetReturnPathDomainRes) ProtoMessage() {}
 
-func (x *GetTemplatesRes) ProtoReflect() protoreflect.Message {
+func (x *SetReturnPathDomainRes) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -937,53 +1225,41 @@ func (x *GetTemplatesRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTemplatesRes.ProtoReflect.Descriptor instead.
-func (*GetTemplatesRes) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetReturnPathDomainRes.ProtoReflect.Descriptor instead.
+func (*SetReturnPathDomainRes) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *GetTemplatesRes) GetTemplates() []*Template {
+func (x *SetReturnPathDomainRes) GetDomain() *Domain {
 	if x != nil {
-		return x.Templates
+		return x.Domain
 	}
 	return nil
 }
 
-func (x *GetTemplatesRes) GetTotal() uint32 {
-	if x != nil {
-		return x.Total
-	}
-	return 0
-}
-
-type APIKey struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Domain        string                 `protobuf:"bytes,4,opt,name=domain,proto3" json:"domain,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
-	IsActive      bool                   `protobuf:"varint,7,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	DeactivatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=deactivated_at,json=deactivatedAt,proto3" json:"deactivated_at,omitempty"`
+type SetFeedbackIdentityReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Domain string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Replaces the domain's whole identity; omitted or empty, it clears it.
+	Feedback      *types1.FeedbackIdentity `protobuf:"bytes,2,opt,name=feedback,proto3" json:"feedback,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *APIKey) Reset() {
-	*x = APIKey{}
+func (x *SetFeedbackIdentityReq) Reset() {
+	*x = SetFeedbackIdentityReq{}
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *APIKey) String() string {
+func (x *SetFeedbackIdentityReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*APIKey) ProtoMessage() {}
+func (*SetFeedbackIdentityReq) ProtoMessage() {}
 
-func (x *APIKey) ProtoReflect() protoreflect.Message {
+func (x *SetFeedbackIdentityReq) ProtoReflect() protoreflect.Message {
 	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -995,91 +1271,1591 @@ func (x *APIKey) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
-func (*APIKey) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetFeedbackIdentityReq.ProtoReflect.Descriptor instead.
+func (*SetFeedbackIdentityReq) Descriptor() ([]byte, []int) {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *APIKey) GetId() string {
+func (x *SetFeedbackIdentityReq) GetDomain() string {
 	if x != nil {
-		return x.Id
+		return x.Domain
 	}
 	return ""
 }
 
-func (x *APIKey) GetKey() string {
+func (x *SetFeedbackIdentityReq) GetFeedback() *types1.FeedbackIdentity {
 	if x != nil {
-		return x.Key
+		return x.Feedback
 	}
-	return ""
+	return nil
 }
 
-func (x *APIKey) GetName() string {
+type SetFeedbackIdentityRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetFeedbackIdentityRes) Reset() {
+	*x = SetFeedbackIdentityRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetFeedbackIdentityRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFeedbackIdentityRes) ProtoMessage() {}
+
+func (x *SetFeedbackIdentityRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[20]
 	if x != nil {
-		return x.Name
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *APIKey) GetDomain() string {
+// Deprecated: Use SetFeedbackIdentityRes.ProtoReflect.Descriptor instead.
+func (*SetFeedbackIdentityRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SetFeedbackIdentityRes) GetDomain() *Domain {
 	if x != nil {
 		return x.Domain
 	}
-	return ""
+	return nil
 }
 
-func (x *APIKey) GetCreatedAt() *timestamppb.Timestamp {
+type SetLinkDecorationReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Domain string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Replaces the domain's whole decoration; omitted or empty, it clears it.
+	LinkDecoration *types2.LinkDecoration `protobuf:"bytes,2,opt,name=link_decoration,json=linkDecoration,proto3" json:"link_decoration,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetLinkDecorationReq) Reset() {
+	*x = SetLinkDecorationReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkDecorationReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkDecorationReq) ProtoMessage() {}
+
+func (x *SetLinkDecorationReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[21]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *APIKey) GetExpiresAt() *timestamppb.Timestamp {
+// Deprecated: Use SetLinkDecorationReq.ProtoReflect.Descriptor instead.
+func (*SetLinkDecorationReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetLinkDecorationReq) GetDomain() string {
 	if x != nil {
-		return x.ExpiresAt
+		return x.Domain
 	}
-	return nil
+	return ""
 }
 
-func (x *APIKey) GetIsActive() bool {
+func (x *SetLinkDecorationReq) GetLinkDecoration() *types2.LinkDecoration {
 	if x != nil {
-		return x.IsActive
+		return x.LinkDecoration
 	}
-	return false
+	return nil
+}
+
+type SetLinkDecorationRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkDecorationRes) Reset() {
+	*x = SetLinkDecorationRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkDecorationRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkDecorationRes) ProtoMessage() {}
+
+func (x *SetLinkDecorationRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLinkDecorationRes.ProtoReflect.Descriptor instead.
+func (*SetLinkDecorationRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SetLinkDecorationRes) GetDomain() *Domain {
+	if x != nil {
+		return x.Domain
+	}
+	return nil
+}
+
+// Has the domain's mail sent only over STARTTLS to an MX host whose
+// certificate verifies, and held back — retried, then bounced when its
+// attempts run out — when no MX host can offer that. A batch may require it
+// for itself when its domain does not.
+type SetRequireTLSReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	RequireTls    bool                   `protobuf:"varint,2,opt,name=require_tls,json=requireTls,proto3" json:"require_tls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRequireTLSReq) Reset() {
+	*x = SetRequireTLSReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRequireTLSReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRequireTLSReq) ProtoMessage() {}
+
+func (x *SetRequireTLSReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRequireTLSReq.ProtoReflect.Descriptor instead.
+func (*SetRequireTLSReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SetRequireTLSReq) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *SetRequireTLSReq) GetRequireTls() bool {
+	if x != nil {
+		return x.RequireTls
+	}
+	return false
+}
+
+type SetRequireTLSRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRequireTLSRes) Reset() {
+	*x = SetRequireTLSRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRequireTLSRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRequireTLSRes) ProtoMessage() {}
+
+func (x *SetRequireTLSRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRequireTLSRes.ProtoReflect.Descriptor instead.
+func (*SetRequireTLSRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SetRequireTLSRes) GetDomain() *Domain {
+	if x != nil {
+		return x.Domain
+	}
+	return nil
+}
+
+// Has the domain's mail sent from an IP pool of the sender's `sender.ip_pools`,
+// or from its default pool when ip_pool is empty. A batch may name its own.
+// The sender, not this API, knows which pools exist: mail assigned to one it
+// lacks goes out from its default pool.
+type SetIPPoolReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	IpPool        string                 `protobuf:"bytes,2,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetIPPoolReq) Reset() {
+	*x = SetIPPoolReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIPPoolReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIPPoolReq) ProtoMessage() {}
+
+func (x *SetIPPoolReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIPPoolReq.ProtoReflect.Descriptor instead.
+func (*SetIPPoolReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SetIPPoolReq) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *SetIPPoolReq) GetIpPool() string {
+	if x != nil {
+		return x.IpPool
+	}
+	return ""
+}
+
+type SetIPPoolRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetIPPoolRes) Reset() {
+	*x = SetIPPoolRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIPPoolRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIPPoolRes) ProtoMessage() {}
+
+func (x *SetIPPoolRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIPPoolRes.ProtoReflect.Descriptor instead.
+func (*SetIPPoolRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SetIPPoolRes) GetDomain() *Domain {
+	if x != nil {
+		return x.Domain
+	}
+	return nil
+}
+
+// Has the domain's mail S/MIME-signed. The certificate must be valid now, fit
+// for signing mail (email protection), and hold an RSA key of at least 2048
+// bits or an ECDSA key on P-256, P-384 or P-521; the key must be its own. An
+// unusable pair fails with InvalidArgument and changes nothing.
+type SetSMIMECertificateReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Domain string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// The certificate chain, PEM, leaf first. Intermediates are sent with every
+	// signature so a recipient can build the chain to its own roots.
+	Certificate string `protobuf:"bytes,2,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// The private key, one unencrypted PEM block: PKCS#8, PKCS#1 or SEC 1. It
+	// is never returned. Both fields empty stops signing.
+	PrivateKey    string `protobuf:"bytes,3,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSMIMECertificateReq) Reset() {
+	*x = SetSMIMECertificateReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSMIMECertificateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSMIMECertificateReq) ProtoMessage() {}
+
+func (x *SetSMIMECertificateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSMIMECertificateReq.ProtoReflect.Descriptor instead.
+func (*SetSMIMECertificateReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SetSMIMECertificateReq) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *SetSMIMECertificateReq) GetCertificate() string {
+	if x != nil {
+		return x.Certificate
+	}
+	return ""
+}
+
+func (x *SetSMIMECertificateReq) GetPrivateKey() string {
+	if x != nil {
+		return x.PrivateKey
+	}
+	return ""
+}
+
+type SetSMIMECertificateRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSMIMECertificateRes) Reset() {
+	*x = SetSMIMECertificateRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSMIMECertificateRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSMIMECertificateRes) ProtoMessage() {}
+
+func (x *SetSMIMECertificateRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSMIMECertificateRes.ProtoReflect.Descriptor instead.
+func (*SetSMIMECertificateRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SetSMIMECertificateRes) GetDomain() *Domain {
+	if x != nil {
+		return x.Domain
+	}
+	return nil
+}
+
+type Template struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Html          string                 `protobuf:"bytes,2,opt,name=html,proto3" json:"html,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Template) Reset() {
+	*x = Template{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Template) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Template) ProtoMessage() {}
+
+func (x *Template) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Template.ProtoReflect.Descriptor instead.
+func (*Template) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Template) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *Template) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+func (x *Template) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Template) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type CreateTemplateReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Html          string                 `protobuf:"bytes,1,opt,name=html,proto3" json:"html,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTemplateReq) Reset() {
+	*x = CreateTemplateReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTemplateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTemplateReq) ProtoMessage() {}
+
+func (x *CreateTemplateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTemplateReq.ProtoReflect.Descriptor instead.
+func (*CreateTemplateReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CreateTemplateReq) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+func (x *CreateTemplateReq) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateTemplateReq) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type CreateTemplateRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Template      *Template              `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTemplateRes) Reset() {
+	*x = CreateTemplateRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTemplateRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTemplateRes) ProtoMessage() {}
+
+func (x *CreateTemplateRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTemplateRes.ProtoReflect.Descriptor instead.
+func (*CreateTemplateRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CreateTemplateRes) GetTemplate() *Template {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+type UpdateTemplateReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Html          string                 `protobuf:"bytes,2,opt,name=html,proto3" json:"html,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTemplateReq) Reset() {
+	*x = UpdateTemplateReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTemplateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTemplateReq) ProtoMessage() {}
+
+func (x *UpdateTemplateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTemplateReq.ProtoReflect.Descriptor instead.
+func (*UpdateTemplateReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UpdateTemplateReq) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *UpdateTemplateReq) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+func (x *UpdateTemplateReq) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type UpdateTemplateRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Template      *Template              `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTemplateRes) Reset() {
+	*x = UpdateTemplateRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTemplateRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTemplateRes) ProtoMessage() {}
+
+func (x *UpdateTemplateRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTemplateRes.ProtoReflect.Descriptor instead.
+func (*UpdateTemplateRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *UpdateTemplateRes) GetTemplate() *Template {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+type DeleteTemplateReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTemplateReq) Reset() {
+	*x = DeleteTemplateReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTemplateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTemplateReq) ProtoMessage() {}
+
+func (x *DeleteTemplateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTemplateReq.ProtoReflect.Descriptor instead.
+func (*DeleteTemplateReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *DeleteTemplateReq) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+type DeleteTemplateRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Template      *Template              `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTemplateRes) Reset() {
+	*x = DeleteTemplateRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTemplateRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTemplateRes) ProtoMessage() {}
+
+func (x *DeleteTemplateRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTemplateRes.ProtoReflect.Descriptor instead.
+func (*DeleteTemplateRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *DeleteTemplateRes) GetTemplate() *Template {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+type GetTemplateReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemplateReq) Reset() {
+	*x = GetTemplateReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemplateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemplateReq) ProtoMessage() {}
+
+func (x *GetTemplateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemplateReq.ProtoReflect.Descriptor instead.
+func (*GetTemplateReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetTemplateReq) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+type GetTemplateRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Template      *Template              `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemplateRes) Reset() {
+	*x = GetTemplateRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemplateRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemplateRes) ProtoMessage() {}
+
+func (x *GetTemplateRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemplateRes.ProtoReflect.Descriptor instead.
+func (*GetTemplateRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetTemplateRes) GetTemplate() *Template {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+type GetTemplatesReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Skip          uint32                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint32                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemplatesReq) Reset() {
+	*x = GetTemplatesReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemplatesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemplatesReq) ProtoMessage() {}
+
+func (x *GetTemplatesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemplatesReq.ProtoReflect.Descriptor instead.
+func (*GetTemplatesReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetTemplatesReq) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetTemplatesReq) GetSkip() uint32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetTemplatesReq) GetTake() uint32 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type GetTemplatesRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Templates     []*Template            `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	Total         uint32                 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemplatesRes) Reset() {
+	*x = GetTemplatesRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemplatesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemplatesRes) ProtoMessage() {}
+
+func (x *GetTemplatesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemplatesRes.ProtoReflect.Descriptor instead.
+func (*GetTemplatesRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetTemplatesRes) GetTemplates() []*Template {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+func (x *GetTemplatesRes) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type APIKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Domain        string                 `protobuf:"bytes,4,opt,name=domain,proto3" json:"domain,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	IsActive      bool                   `protobuf:"varint,7,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	DeactivatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=deactivated_at,json=deactivatedAt,proto3" json:"deactivated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *APIKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *APIKey) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIKey) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *APIKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *APIKey) GetDeactivatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeactivatedAt
+	}
+	return nil
+}
+
+type CreateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CreateAPIKeyRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type CreateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CreateAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	OnlyActive    bool                   `protobuf:"varint,2,opt,name=only_active,json=onlyActive,proto3" json:"only_active,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListAPIKeysRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ListAPIKeysRequest) GetOnlyActive() bool {
+	if x != nil {
+		return x.OnlyActive
+	}
+	return false
+}
+
+func (x *ListAPIKeysRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListAPIKeysRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*APIKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysResponse) Reset() {
+	*x = ListAPIKeysResponse{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListAPIKeysResponse) GetApiKeys() []*APIKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+func (x *ListAPIKeysResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAPIKeyRequest) Reset() {
+	*x = GetAPIKeyRequest{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAPIKeyRequest) ProtoMessage() {}
+
+func (x *GetAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetAPIKeyRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetAPIKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAPIKeyResponse) Reset() {
+	*x = GetAPIKeyResponse{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAPIKeyResponse) ProtoMessage() {}
+
+func (x *GetAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*GetAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+type DeactivateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateAPIKeyRequest) Reset() {
+	*x = DeactivateAPIKeyRequest{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateAPIKeyRequest) ProtoMessage() {}
+
+func (x *DeactivateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*DeactivateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *DeactivateAPIKeyRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DeactivateAPIKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeactivateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateAPIKeyResponse) Reset() {
+	*x = DeactivateAPIKeyResponse{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateAPIKeyResponse) ProtoMessage() {}
+
+func (x *DeactivateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*DeactivateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *APIKey) GetDeactivatedAt() *timestamppb.Timestamp {
+func (x *DeactivateAPIKeyResponse) GetApiKey() *APIKey {
 	if x != nil {
-		return x.DeactivatedAt
+		return x.ApiKey
 	}
 	return nil
 }
 
-type CreateAPIKeyRequest struct {
+// Reports how far along its warm-up plan each address of `sender.warmup` is.
+// The addresses are the installation's and carry the mail of every domain, so
+// only the admin token reads them. Fails with Unavailable when the counts
+// cannot be read from NATS.
+type GetWarmupProgressReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateAPIKeyRequest) Reset() {
-	*x = CreateAPIKeyRequest{}
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[20]
+func (x *GetWarmupProgressReq) Reset() {
+	*x = GetWarmupProgressReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateAPIKeyRequest) String() string {
+func (x *GetWarmupProgressReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateAPIKeyRequest) ProtoMessage() {}
+func (*GetWarmupProgressReq) ProtoMessage() {}
 
-func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[20]
+func (x *GetWarmupProgressReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1090,55 +2866,35 @@ func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
-func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
-	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{20}
-}
-
-func (x *CreateAPIKeyRequest) GetDomain() string {
-	if x != nil {
-		return x.Domain
-	}
-	return ""
-}
-
-func (x *CreateAPIKeyRequest) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *CreateAPIKeyRequest) GetExpiresAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.ExpiresAt
-	}
-	return nil
+// Deprecated: Use GetWarmupProgressReq.ProtoReflect.Descriptor instead.
+func (*GetWarmupProgressReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{49}
 }
 
-type CreateAPIKeyResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
-	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+type GetWarmupProgressRes struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Every address a plan is attached to, in the order configured, those whose
+	// plan is over included.
+	Addresses     []*WarmupProgress `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateAPIKeyResponse) Reset() {
-	*x = CreateAPIKeyResponse{}
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[21]
+func (x *GetWarmupProgressRes) Reset() {
+	*x = GetWarmupProgressRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateAPIKeyResponse) String() string {
+func (x *GetWarmupProgressRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateAPIKeyResponse) ProtoMessage() {}
+func (*GetWarmupProgressRes) ProtoMessage() {}
 
-func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[21]
+func (x *GetWarmupProgressRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1149,50 +2905,53 @@ func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
-func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
-	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use GetWarmupProgressRes.ProtoReflect.Descriptor instead.
+func (*GetWarmupProgressRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *CreateAPIKeyResponse) GetApiKey() *APIKey {
+func (x *GetWarmupProgressRes) GetAddresses() []*WarmupProgress {
 	if x != nil {
-		return x.ApiKey
+		return x.Addresses
 	}
 	return nil
 }
 
-func (x *CreateAPIKeyResponse) GetKey() string {
-	if x != nil {
-		return x.Key
-	}
-	return ""
-}
-
-type ListAPIKeysRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
-	OnlyActive    bool                   `protobuf:"varint,2,opt,name=only_active,json=onlyActive,proto3" json:"only_active,omitempty"`
-	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+type WarmupProgress struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Address string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Plan    string                 `protobuf:"bytes,2,opt,name=plan,proto3" json:"plan,omitempty"`
+	// The plan's first day; days are UTC days.
+	Start *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	// The day of the plan the address is on, from 1; 0 before it starts.
+	Day uint32 `protobuf:"varint,4,opt,name=day,proto3" json:"day,omitempty"`
+	// How many days the plan lasts.
+	Days uint32 `protobuf:"varint,5,opt,name=days,proto3" json:"days,omitempty"`
+	// Set once the plan is over: the address sends as much as it is given.
+	Warm  bool         `protobuf:"varint,6,opt,name=warm,proto3" json:"warm,omitempty"`
+	Today *WarmupUsage `protobuf:"bytes,7,opt,name=today,proto3" json:"today,omitempty"`
+	Hour  *WarmupUsage `protobuf:"bytes,8,opt,name=hour,proto3" json:"hour,omitempty"`
+	// The plan's ramps for single destinations still running.
+	Providers     []*ProviderWarmupProgress `protobuf:"bytes,9,rep,name=providers,proto3" json:"providers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAPIKeysRequest) Reset() {
-	*x = ListAPIKeysRequest{}
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[22]
+func (x *WarmupProgress) Reset() {
+	*x = WarmupProgress{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAPIKeysRequest) String() string {
+func (x *WarmupProgress) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAPIKeysRequest) ProtoMessage() {}
+func (*WarmupProgress) ProtoMessage() {}
 
-func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[22]
+func (x *WarmupProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1203,62 +2962,99 @@ func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
-func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
-	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use WarmupProgress.ProtoReflect.Descriptor instead.
+func (*WarmupProgress) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *ListAPIKeysRequest) GetDomain() string {
+func (x *WarmupProgress) GetAddress() string {
 	if x != nil {
-		return x.Domain
+		return x.Address
 	}
 	return ""
 }
 
-func (x *ListAPIKeysRequest) GetOnlyActive() bool {
+func (x *WarmupProgress) GetPlan() string {
 	if x != nil {
-		return x.OnlyActive
+		return x.Plan
 	}
-	return false
+	return ""
 }
 
-func (x *ListAPIKeysRequest) GetLimit() int32 {
+func (x *WarmupProgress) GetStart() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Limit
+		return x.Start
+	}
+	return nil
+}
+
+func (x *WarmupProgress) GetDay() uint32 {
+	if x != nil {
+		return x.Day
 	}
 	return 0
 }
 
-func (x *ListAPIKeysRequest) GetOffset() int32 {
+func (x *WarmupProgress) GetDays() uint32 {
 	if x != nil {
-		return x.Offset
+		return x.Days
 	}
 	return 0
 }
 
-type ListAPIKeysResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiKeys       []*APIKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+func (x *WarmupProgress) GetWarm() bool {
+	if x != nil {
+		return x.Warm
+	}
+	return false
+}
+
+func (x *WarmupProgress) GetToday() *WarmupUsage {
+	if x != nil {
+		return x.Today
+	}
+	return nil
+}
+
+func (x *WarmupProgress) GetHour() *WarmupUsage {
+	if x != nil {
+		return x.Hour
+	}
+	return nil
+}
+
+func (x *WarmupProgress) GetProviders() []*ProviderWarmupProgress {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+type ProviderWarmupProgress struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A `sender.throttle` group's name, or a recipient domain.
+	Destination   string       `protobuf:"bytes,1,opt,name=destination,proto3" json:"destination,omitempty"`
+	Today         *WarmupUsage `protobuf:"bytes,2,opt,name=today,proto3" json:"today,omitempty"`
+	Hour          *WarmupUsage `protobuf:"bytes,3,opt,name=hour,proto3" json:"hour,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAPIKeysResponse) Reset() {
-	*x = ListAPIKeysResponse{}
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[23]
+func (x *ProviderWarmupProgress) Reset() {
+	*x = ProviderWarmupProgress{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAPIKeysResponse) String() string {
+func (x *ProviderWarmupProgress) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAPIKeysResponse) ProtoMessage() {}
+func (*ProviderWarmupProgress) ProtoMessage() {}
 
-func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[23]
+func (x *ProviderWarmupProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1269,48 +3065,57 @@ func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
-func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
-	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use ProviderWarmupProgress.ProtoReflect.Descriptor instead.
+func (*ProviderWarmupProgress) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *ListAPIKeysResponse) GetApiKeys() []*APIKey {
+func (x *ProviderWarmupProgress) GetDestination() string {
 	if x != nil {
-		return x.ApiKeys
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *ProviderWarmupProgress) GetToday() *WarmupUsage {
+	if x != nil {
+		return x.Today
 	}
 	return nil
 }
 
-func (x *ListAPIKeysResponse) GetTotal() int32 {
+func (x *ProviderWarmupProgress) GetHour() *WarmupUsage {
 	if x != nil {
-		return x.Total
+		return x.Hour
 	}
-	return 0
+	return nil
 }
 
-type GetAPIKeyRequest struct {
+// What a window has counted of what it allows. A limit of 0 is no cap: a day
+// or hour the plan does not limit.
+type WarmupUsage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Sent          uint32                 `protobuf:"varint,1,opt,name=sent,proto3" json:"sent,omitempty"`
+	Limit         uint32                 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAPIKeyRequest) Reset() {
-	*x = GetAPIKeyRequest{}
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[24]
+func (x *WarmupUsage) Reset() {
+	*x = WarmupUsage{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAPIKeyRequest) String() string {
+func (x *WarmupUsage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAPIKeyRequest) ProtoMessage() {}
+func (*WarmupUsage) ProtoMessage() {}
 
-func (x *GetAPIKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[24]
+func (x *WarmupUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1321,47 +3126,50 @@ func (x *GetAPIKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAPIKeyRequest.ProtoReflect.Descriptor instead.
-func (*GetAPIKeyRequest) Descriptor() ([]byte, []int) {
-	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use WarmupUsage.ProtoReflect.Descriptor instead.
+func (*WarmupUsage) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *GetAPIKeyRequest) GetDomain() string {
+func (x *WarmupUsage) GetSent() uint32 {
 	if x != nil {
-		return x.Domain
+		return x.Sent
 	}
-	return ""
+	return 0
 }
 
-func (x *GetAPIKeyRequest) GetId() string {
+func (x *WarmupUsage) GetLimit() uint32 {
 	if x != nil {
-		return x.Id
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-type GetAPIKeyResponse struct {
+// Reports the destinations the SMTPSender is holding mail back from after a
+// throttling reply. The reply names the MX host and recipient domains mail
+// goes to, so like the addresses only the admin token reads them. Fails with
+// Unavailable when the cool-downs cannot be read from NATS.
+type GetDestinationCooldownsReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAPIKeyResponse) Reset() {
-	*x = GetAPIKeyResponse{}
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[25]
+func (x *GetDestinationCooldownsReq) Reset() {
+	*x = GetDestinationCooldownsReq{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAPIKeyResponse) String() string {
+func (x *GetDestinationCooldownsReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAPIKeyResponse) ProtoMessage() {}
+func (*GetDestinationCooldownsReq) ProtoMessage() {}
 
-func (x *GetAPIKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[25]
+func (x *GetDestinationCooldownsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1372,41 +3180,34 @@ func (x *GetAPIKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAPIKeyResponse.ProtoReflect.Descriptor instead.
-func (*GetAPIKeyResponse) Descriptor() ([]byte, []int) {
-	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{25}
-}
-
-func (x *GetAPIKeyResponse) GetApiKey() *APIKey {
-	if x != nil {
-		return x.ApiKey
-	}
-	return nil
+// Deprecated: Use GetDestinationCooldownsReq.ProtoReflect.Descriptor instead.
+func (*GetDestinationCooldownsReq) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{54}
 }
 
-type DeactivateAPIKeyRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+type GetDestinationCooldownsRes struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The one cooling down longest first; none when `sender.cooldown` is off.
+	Cooldowns     []*DestinationCooldown `protobuf:"bytes,1,rep,name=cooldowns,proto3" json:"cooldowns,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeactivateAPIKeyRequest) Reset() {
-	*x = DeactivateAPIKeyRequest{}
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[26]
+func (x *GetDestinationCooldownsRes) Reset() {
+	*x = GetDestinationCooldownsRes{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeactivateAPIKeyRequest) String() string {
+func (x *GetDestinationCooldownsRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeactivateAPIKeyRequest) ProtoMessage() {}
+func (*GetDestinationCooldownsRes) ProtoMessage() {}
 
-func (x *DeactivateAPIKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[26]
+func (x *GetDestinationCooldownsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1417,47 +3218,50 @@ func (x *DeactivateAPIKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeactivateAPIKeyRequest.ProtoReflect.Descriptor instead.
-func (*DeactivateAPIKeyRequest) Descriptor() ([]byte, []int) {
-	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{26}
-}
-
-func (x *DeactivateAPIKeyRequest) GetDomain() string {
-	if x != nil {
-		return x.Domain
-	}
-	return ""
+// Deprecated: Use GetDestinationCooldownsRes.ProtoReflect.Descriptor instead.
+func (*GetDestinationCooldownsRes) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *DeactivateAPIKeyRequest) GetId() string {
+func (x *GetDestinationCooldownsRes) GetCooldowns() []*DestinationCooldown {
 	if x != nil {
-		return x.Id
+		return x.Cooldowns
 	}
-	return ""
+	return nil
 }
 
-type DeactivateAPIKeyResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+type DestinationCooldown struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A `sender.throttle` group's name, or a recipient domain.
+	Destination string `protobuf:"bytes,1,opt,name=destination,proto3" json:"destination,omitempty"`
+	// How many times in a row the destination throttled again once a
+	// cool-down was over; 0 for a first cool-down.
+	Level uint32                 `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	Since *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	Until *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=until,proto3" json:"until,omitempty"`
+	// The enhanced status code of the reply that started it, and the MX host
+	// that gave it.
+	Code          string `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	Mx            string `protobuf:"bytes,6,opt,name=mx,proto3" json:"mx,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeactivateAPIKeyResponse) Reset() {
-	*x = DeactivateAPIKeyResponse{}
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[27]
+func (x *DestinationCooldown) Reset() {
+	*x = DestinationCooldown{}
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeactivateAPIKeyResponse) String() string {
+func (x *DestinationCooldown) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeactivateAPIKeyResponse) ProtoMessage() {}
+func (*DestinationCooldown) ProtoMessage() {}
 
-func (x *DeactivateAPIKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[27]
+func (x *DestinationCooldown) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1468,41 +3272,166 @@ func (x *DeactivateAPIKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeactivateAPIKeyResponse.ProtoReflect.Descriptor instead.
-func (*DeactivateAPIKeyResponse) Descriptor() ([]byte, []int) {
-	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use DestinationCooldown.ProtoReflect.Descriptor instead.
+func (*DestinationCooldown) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *DeactivateAPIKeyResponse) GetApiKey() *APIKey {
+func (x *DestinationCooldown) GetDestination() string {
 	if x != nil {
-		return x.ApiKey
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *DestinationCooldown) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *DestinationCooldown) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
 	}
 	return nil
 }
 
+func (x *DestinationCooldown) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *DestinationCooldown) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *DestinationCooldown) GetMx() string {
+	if x != nil {
+		return x.Mx
+	}
+	return ""
+}
+
 var File_kannon_admin_apiv1_adminapiv1_proto protoreflect.FileDescriptor
 
 const file_kannon_admin_apiv1_adminapiv1_proto_rawDesc = "" +
 	"\n" +
-	"#kannon/admin/apiv1/adminapiv1.proto\x12\x16pkg.kannon.admin.apiv1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a$kannon/tracking/types/tracking.proto\"\x0f\n" +
+	"#kannon/admin/apiv1/adminapiv1.proto\x12\x16pkg.kannon.admin.apiv1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a$kannon/feedback/types/feedback.proto\x1a(kannon/linkparams/types/linkparams.proto\x1a$kannon/tracking/types/tracking.proto\"\x0f\n" +
 	"\rGetDomainsReq\"N\n" +
 	"\x12GetDomainsResponse\x128\n" +
 	"\adomains\x18\x01 \x03(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\adomains\"&\n" +
 	"\fGetDomainReq\x12\x16\n" +
 	"\x06domain\x18\x01 \x01(\tR\x06domain\"F\n" +
 	"\fGetDomainRes\x126\n" +
-	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"-\n" +
+	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"\xab\x01\n" +
 	"\x13CreateDomainRequest\x12\x16\n" +
-	"\x06domain\x18\x01 \x01(\tR\x06domain\"\x89\x01\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12$\n" +
+	"\rdkim_rsa_bits\x18\x02 \x01(\rH\x00R\vdkimRsaBits\x12J\n" +
+	"\vdkim_import\x18\x03 \x01(\v2'.pkg.kannon.admin.apiv1.ImportedDKIMKeyH\x00R\n" +
+	"dkimImportB\n" +
+	"\n" +
+	"\bdkim_key\"U\n" +
+	"\x0fImportedDKIMKey\x12\x1a\n" +
+	"\bselector\x18\x01 \x01(\tR\bselector\x12&\n" +
+	"\x0fprivate_key_pem\x18\x02 \x01(\tR\rprivateKeyPem\"\xce\x04\n" +
 	"\x06Domain\x12\x16\n" +
 	"\x06domain\x18\x01 \x01(\tR\x06domain\x12 \n" +
 	"\fdkim_pub_key\x18\x03 \x01(\tR\n" +
 	"dkimPubKey\x12E\n" +
-	"\btracking\x18\x04 \x01(\v2).pkg.kannon.tracking.types.TrackingPolicyR\btracking\"u\n" +
+	"\btracking\x18\x04 \x01(\v2).pkg.kannon.tracking.types.TrackingPolicyR\btracking\x12<\n" +
+	"\tdkim_keys\x18\x05 \x03(\v2\x1f.pkg.kannon.admin.apiv1.DKIMKeyR\bdkimKeys\x12,\n" +
+	"\x12return_path_domain\x18\x06 \x01(\tR\x10returnPathDomain\x12Q\n" +
+	"\x13return_path_records\x18\a \x03(\v2!.pkg.kannon.admin.apiv1.DNSRecordR\x11returnPathRecords\x12G\n" +
+	"\bfeedback\x18\b \x01(\v2+.pkg.kannon.feedback.types.FeedbackIdentityR\bfeedback\x12+\n" +
+	"\x11smime_certificate\x18\t \x01(\tR\x10smimeCertificate\x12T\n" +
+	"\x0flink_decoration\x18\n" +
+	" \x01(\v2+.pkg.kannon.linkparams.types.LinkDecorationR\x0elinkDecoration\x12\x1f\n" +
+	"\vrequire_tls\x18\v \x01(\bR\n" +
+	"requireTls\x12\x17\n" +
+	"\aip_pool\x18\f \x01(\tR\x06ipPool\"I\n" +
+	"\tDNSRecord\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\"\xc3\x03\n" +
+	"\aDKIMKey\x12\x1a\n" +
+	"\bselector\x18\x01 \x01(\tR\bselector\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\tR\tpublicKey\x12:\n" +
+	"\x05state\x18\x04 \x01(\x0e2$.pkg.kannon.admin.apiv1.DKIMKeyStateR\x05state\x12\x1f\n" +
+	"\vrecord_name\x18\x05 \x01(\tR\n" +
+	"recordName\x12!\n" +
+	"\frecord_value\x18\x06 \x01(\tR\vrecordValue\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\factivated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vactivatedAt\x129\n" +
+	"\n" +
+	"retired_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tretiredAt\x12\x16\n" +
+	"\x06origin\x18\n" +
+	" \x01(\tR\x06origin\x12\x12\n" +
+	"\x04bits\x18\v \x01(\rR\x04bits\"d\n" +
+	"\x10RotateDKIMKeyReq\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1a\n" +
+	"\bselector\x18\x02 \x01(\tR\bselector\x12\x1c\n" +
+	"\talgorithm\x18\x03 \x01(\tR\talgorithm\"E\n" +
+	"\x10RotateDKIMKeyRes\x121\n" +
+	"\x03key\x18\x01 \x01(\v2\x1f.pkg.kannon.admin.apiv1.DKIMKeyR\x03key\"g\n" +
+	"\x12ActivateDKIMKeyReq\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1a\n" +
+	"\bselector\x18\x02 \x01(\tR\bselector\x12\x1d\n" +
+	"\n" +
+	"verify_dns\x18\x03 \x01(\bR\tverifyDns\"L\n" +
+	"\x12ActivateDKIMKeyRes\x126\n" +
+	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"F\n" +
+	"\x10RetireDKIMKeyReq\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1a\n" +
+	"\bselector\x18\x02 \x01(\tR\bselector\"J\n" +
+	"\x10RetireDKIMKeyRes\x126\n" +
+	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"u\n" +
 	"\x14SetTrackingPolicyReq\x12\x16\n" +
 	"\x06domain\x18\x01 \x01(\tR\x06domain\x12E\n" +
 	"\btracking\x18\x02 \x01(\v2).pkg.kannon.tracking.types.TrackingPolicyR\btracking\"N\n" +
 	"\x14SetTrackingPolicyRes\x126\n" +
+	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"^\n" +
+	"\x16SetReturnPathDomainReq\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12,\n" +
+	"\x12return_path_domain\x18\x02 \x01(\tR\x10returnPathDomain\"P\n" +
+	"\x16SetReturnPathDomainRes\x126\n" +
+	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"y\n" +
+	"\x16SetFeedbackIdentityReq\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12G\n" +
+	"\bfeedback\x18\x02 \x01(\v2+.pkg.kannon.feedback.types.FeedbackIdentityR\bfeedback\"P\n" +
+	"\x16SetFeedbackIdentityRes\x126\n" +
+	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"\x84\x01\n" +
+	"\x14SetLinkDecorationReq\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12T\n" +
+	"\x0flink_decoration\x18\x02 \x01(\v2+.pkg.kannon.linkparams.types.LinkDecorationR\x0elinkDecoration\"N\n" +
+	"\x14SetLinkDecorationRes\x126\n" +
+	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"K\n" +
+	"\x10SetRequireTLSReq\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1f\n" +
+	"\vrequire_tls\x18\x02 \x01(\bR\n" +
+	"requireTls\"J\n" +
+	"\x10SetRequireTLSRes\x126\n" +
+	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"?\n" +
+	"\fSetIPPoolReq\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x17\n" +
+	"\aip_pool\x18\x02 \x01(\tR\x06ipPool\"F\n" +
+	"\fSetIPPoolRes\x126\n" +
+	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"s\n" +
+	"\x16SetSMIMECertificateReq\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12 \n" +
+	"\vcertificate\x18\x02 \x01(\tR\vcertificate\x12\x1f\n" +
+	"\vprivate_key\x18\x03 \x01(\tR\n" +
+	"privateKey\"P\n" +
+	"\x16SetSMIMECertificateRes\x126\n" +
 	"\x06domain\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.DomainR\x06domain\"i\n" +
 	"\bTemplate\x12\x1f\n" +
 	"\vtemplate_id\x18\x01 \x01(\tR\n" +
@@ -1577,14 +3506,58 @@ const file_kannon_admin_apiv1_adminapiv1_proto_rawDesc = "" +
 	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\"S\n" +
 	"\x18DeactivateAPIKeyResponse\x127\n" +
-	"\aapi_key\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.APIKeyR\x06apiKey2\xcc\n" +
-	"\n" +
+	"\aapi_key\x18\x01 \x01(\v2\x1e.pkg.kannon.admin.apiv1.APIKeyR\x06apiKey\"\x16\n" +
+	"\x14GetWarmupProgressReq\"\\\n" +
+	"\x14GetWarmupProgressRes\x12D\n" +
+	"\taddresses\x18\x01 \x03(\v2&.pkg.kannon.admin.apiv1.WarmupProgressR\taddresses\"\xec\x02\n" +
+	"\x0eWarmupProgress\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04plan\x18\x02 \x01(\tR\x04plan\x120\n" +
+	"\x05start\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x10\n" +
+	"\x03day\x18\x04 \x01(\rR\x03day\x12\x12\n" +
+	"\x04days\x18\x05 \x01(\rR\x04days\x12\x12\n" +
+	"\x04warm\x18\x06 \x01(\bR\x04warm\x129\n" +
+	"\x05today\x18\a \x01(\v2#.pkg.kannon.admin.apiv1.WarmupUsageR\x05today\x127\n" +
+	"\x04hour\x18\b \x01(\v2#.pkg.kannon.admin.apiv1.WarmupUsageR\x04hour\x12L\n" +
+	"\tproviders\x18\t \x03(\v2..pkg.kannon.admin.apiv1.ProviderWarmupProgressR\tproviders\"\xae\x01\n" +
+	"\x16ProviderWarmupProgress\x12 \n" +
+	"\vdestination\x18\x01 \x01(\tR\vdestination\x129\n" +
+	"\x05today\x18\x02 \x01(\v2#.pkg.kannon.admin.apiv1.WarmupUsageR\x05today\x127\n" +
+	"\x04hour\x18\x03 \x01(\v2#.pkg.kannon.admin.apiv1.WarmupUsageR\x04hour\"7\n" +
+	"\vWarmupUsage\x12\x12\n" +
+	"\x04sent\x18\x01 \x01(\rR\x04sent\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\rR\x05limit\"\x1c\n" +
+	"\x1aGetDestinationCooldownsReq\"g\n" +
+	"\x1aGetDestinationCooldownsRes\x12I\n" +
+	"\tcooldowns\x18\x01 \x03(\v2+.pkg.kannon.admin.apiv1.DestinationCooldownR\tcooldowns\"\xd5\x01\n" +
+	"\x13DestinationCooldown\x12 \n" +
+	"\vdestination\x18\x01 \x01(\tR\vdestination\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\rR\x05level\x120\n" +
+	"\x05since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x12\n" +
+	"\x04code\x18\x05 \x01(\tR\x04code\x12\x0e\n" +
+	"\x02mx\x18\x06 \x01(\tR\x02mx*\xa0\x01\n" +
+	"\fDKIMKeyState\x12\x1e\n" +
+	"\x1aDKIM_KEY_STATE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16DKIM_KEY_STATE_PENDING\x10\x01\x12\x19\n" +
+	"\x15DKIM_KEY_STATE_ACTIVE\x10\x02\x12\x1d\n" +
+	"\x19DKIM_KEY_STATE_SUPERSEDED\x10\x03\x12\x1a\n" +
+	"\x16DKIM_KEY_STATE_RETIRED\x10\x042\xa0\x14\n" +
 	"\x03Api\x12a\n" +
 	"\n" +
 	"GetDomains\x12%.pkg.kannon.admin.apiv1.GetDomainsReq\x1a*.pkg.kannon.admin.apiv1.GetDomainsResponse\"\x00\x12Y\n" +
 	"\tGetDomain\x12$.pkg.kannon.admin.apiv1.GetDomainReq\x1a$.pkg.kannon.admin.apiv1.GetDomainRes\"\x00\x12]\n" +
 	"\fCreateDomain\x12+.pkg.kannon.admin.apiv1.CreateDomainRequest\x1a\x1e.pkg.kannon.admin.apiv1.Domain\"\x00\x12q\n" +
-	"\x11SetTrackingPolicy\x12,.pkg.kannon.admin.apiv1.SetTrackingPolicyReq\x1a,.pkg.kannon.admin.apiv1.SetTrackingPolicyRes\"\x00\x12h\n" +
+	"\x11SetTrackingPolicy\x12,.pkg.kannon.admin.apiv1.SetTrackingPolicyReq\x1a,.pkg.kannon.admin.apiv1.SetTrackingPolicyRes\"\x00\x12w\n" +
+	"\x13SetReturnPathDomain\x12..pkg.kannon.admin.apiv1.SetReturnPathDomainReq\x1a..pkg.kannon.admin.apiv1.SetReturnPathDomainRes\"\x00\x12w\n" +
+	"\x13SetFeedbackIdentity\x12..pkg.kannon.admin.apiv1.SetFeedbackIdentityReq\x1a..pkg.kannon.admin.apiv1.SetFeedbackIdentityRes\"\x00\x12q\n" +
+	"\x11SetLinkDecoration\x12,.pkg.kannon.admin.apiv1.SetLinkDecorationReq\x1a,.pkg.kannon.admin.apiv1.SetLinkDecorationRes\"\x00\x12e\n" +
+	"\rSetRequireTLS\x12(.pkg.kannon.admin.apiv1.SetRequireTLSReq\x1a(.pkg.kannon.admin.apiv1.SetRequireTLSRes\"\x00\x12Y\n" +
+	"\tSetIPPool\x12$.pkg.kannon.admin.apiv1.SetIPPoolReq\x1a$.pkg.kannon.admin.apiv1.SetIPPoolRes\"\x00\x12w\n" +
+	"\x13SetSMIMECertificate\x12..pkg.kannon.admin.apiv1.SetSMIMECertificateReq\x1a..pkg.kannon.admin.apiv1.SetSMIMECertificateRes\"\x00\x12e\n" +
+	"\rRotateDKIMKey\x12(.pkg.kannon.admin.apiv1.RotateDKIMKeyReq\x1a(.pkg.kannon.admin.apiv1.RotateDKIMKeyRes\"\x00\x12k\n" +
+	"\x0fActivateDKIMKey\x12*.pkg.kannon.admin.apiv1.ActivateDKIMKeyReq\x1a*.pkg.kannon.admin.apiv1.ActivateDKIMKeyRes\"\x00\x12e\n" +
+	"\rRetireDKIMKey\x12(.pkg.kannon.admin.apiv1.RetireDKIMKeyReq\x1a(.pkg.kannon.admin.apiv1.RetireDKIMKeyRes\"\x00\x12h\n" +
 	"\x0eCreateTemplate\x12).pkg.kannon.admin.apiv1.CreateTemplateReq\x1a).pkg.kannon.admin.apiv1.CreateTemplateRes\"\x00\x12h\n" +
 	"\x0eUpdateTemplate\x12).pkg.kannon.admin.apiv1.UpdateTemplateReq\x1a).pkg.kannon.admin.apiv1.UpdateTemplateRes\"\x00\x12h\n" +
 	"\x0eDeleteTemplate\x12).pkg.kannon.admin.apiv1.DeleteTemplateReq\x1a).pkg.kannon.admin.apiv1.DeleteTemplateRes\"\x00\x12_\n" +
@@ -1593,7 +3566,9 @@ const file_kannon_admin_apiv1_adminapiv1_proto_rawDesc = "" +
 	"\fCreateAPIKey\x12+.pkg.kannon.admin.apiv1.CreateAPIKeyRequest\x1a,.pkg.kannon.admin.apiv1.CreateAPIKeyResponse\"\x00\x12h\n" +
 	"\vListAPIKeys\x12*.pkg.kannon.admin.apiv1.ListAPIKeysRequest\x1a+.pkg.kannon.admin.apiv1.ListAPIKeysResponse\"\x00\x12b\n" +
 	"\tGetAPIKey\x12(.pkg.kannon.admin.apiv1.GetAPIKeyRequest\x1a).pkg.kannon.admin.apiv1.GetAPIKeyResponse\"\x00\x12w\n" +
-	"\x10DeactivateAPIKey\x12/.pkg.kannon.admin.apiv1.DeactivateAPIKeyRequest\x1a0.pkg.kannon.admin.apiv1.DeactivateAPIKeyResponse\"\x00B\xe2\x01\n" +
+	"\x10DeactivateAPIKey\x12/.pkg.kannon.admin.apiv1.DeactivateAPIKeyRequest\x1a0.pkg.kannon.admin.apiv1.DeactivateAPIKeyResponse\"\x00\x12q\n" +
+	"\x11GetWarmupProgress\x12,.pkg.kannon.admin.apiv1.GetWarmupProgressReq\x1a,.pkg.kannon.admin.apiv1.GetWarmupProgressRes\"\x00\x12\x83\x01\n" +
+	"\x17GetDestinationCooldowns\x122.pkg.kannon.admin.apiv1.GetDestinationCooldownsReq\x1a2.pkg.kannon.admin.apiv1.GetDestinationCooldownsRes\"\x00B\xe2\x01\n" +
 	"\x1acom.pkg.kannon.admin.apiv1B\x0fAdminapiv1ProtoP\x01Z7github.com/kannon-email/kannon/proto/kannon/admin/apiv1\xa2\x02\x04PKAA\xaa\x02\x16Pkg.Kannon.Admin.Apiv1\xca\x02\x16Pkg\\Kannon\\Admin\\Apiv1\xe2\x02\"Pkg\\Kannon\\Admin\\Apiv1\\GPBMetadata\xea\x02\x19Pkg::Kannon::Admin::Apiv1b\x06proto3"
 
 var (
@@ -1608,89 +3583,174 @@ func file_kannon_admin_apiv1_adminapiv1_proto_rawDescGZIP() []byte {
 	return file_kannon_admin_apiv1_adminapiv1_proto_rawDescData
 }
 
-var file_kannon_admin_apiv1_adminapiv1_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_kannon_admin_apiv1_adminapiv1_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_kannon_admin_apiv1_adminapiv1_proto_msgTypes = make([]protoimpl.MessageInfo, 57)
 var file_kannon_admin_apiv1_adminapiv1_proto_goTypes = []any{
-	(*GetDomainsReq)(nil),            // 0: pkg.kannon.admin.apiv1.GetDomainsReq
-	(*GetDomainsResponse)(nil),       // 1: pkg.kannon.admin.apiv1.GetDomainsResponse
-	(*GetDomainReq)(nil),             // 2: pkg.kannon.admin.apiv1.GetDomainReq
-	(*GetDomainRes)(nil),             // 3: pkg.kannon.admin.apiv1.GetDomainRes
-	(*CreateDomainRequest)(nil),      // 4: pkg.kannon.admin.apiv1.CreateDomainRequest
-	(*Domain)(nil),                   // 5: pkg.kannon.admin.apiv1.Domain
-	(*SetTrackingPolicyReq)(nil),     // 6: pkg.kannon.admin.apiv1.SetTrackingPolicyReq
-	(*SetTrackingPolicyRes)(nil),     // 7: pkg.kannon.admin.apiv1.SetTrackingPolicyRes
-	(*Template)(nil),                 // 8: pkg.kannon.admin.apiv1.Template
-	(*CreateTemplateReq)(nil),        // 9: pkg.kannon.admin.apiv1.CreateTemplateReq
-	(*CreateTemplateRes)(nil),        // 10: pkg.kannon.admin.apiv1.CreateTemplateRes
-	(*UpdateTemplateReq)(nil),        // 11: pkg.kannon.admin.apiv1.UpdateTemplateReq
-	(*UpdateTemplateRes)(nil),        // 12: pkg.kannon.admin.apiv1.UpdateTemplateRes
-	(*DeleteTemplateReq)(nil),        // 13: pkg.kannon.admin.apiv1.DeleteTemplateReq
-	(*DeleteTemplateRes)(nil),        // 14: pkg.kannon.admin.apiv1.DeleteTemplateRes
-	(*GetTemplateReq)(nil),           // 15: pkg.kannon.admin.apiv1.GetTemplateReq
-	(*GetTemplateRes)(nil),           // 16: pkg.kannon.admin.apiv1.GetTemplateRes
-	(*GetTemplatesReq)(nil),          // 17: pkg.kannon.admin.apiv1.GetTemplatesReq
-	(*GetTemplatesRes)(nil),          // 18: pkg.kannon.admin.apiv1.GetTemplatesRes
-	(*APIKey)(nil),                   // 19: pkg.kannon.admin.apiv1.APIKey
-	(*CreateAPIKeyRequest)(nil),      // 20: pkg.kannon.admin.apiv1.CreateAPIKeyRequest
-	(*CreateAPIKeyResponse)(nil),     // 21: pkg.kannon.admin.apiv1.CreateAPIKeyResponse
-	(*ListAPIKeysRequest)(nil),       // 22: pkg.kannon.admin.apiv1.ListAPIKeysRequest
-	(*ListAPIKeysResponse)(nil),      // 23: pkg.kannon.admin.apiv1.ListAPIKeysResponse
-	(*GetAPIKeyRequest)(nil),         // 24: pkg.kannon.admin.apiv1.GetAPIKeyRequest
-	(*GetAPIKeyResponse)(nil),        // 25: pkg.kannon.admin.apiv1.GetAPIKeyResponse
-	(*DeactivateAPIKeyRequest)(nil),  // 26: pkg.kannon.admin.apiv1.DeactivateAPIKeyRequest
-	(*DeactivateAPIKeyResponse)(nil), // 27: pkg.kannon.admin.apiv1.DeactivateAPIKeyResponse
-	(*types.TrackingPolicy)(nil),     // 28: pkg.kannon.tracking.types.TrackingPolicy
-	(*timestamppb.Timestamp)(nil),    // 29: google.protobuf.Timestamp
+	(DKIMKeyState)(0),                  // 0: pkg.kannon.admin.apiv1.DKIMKeyState
+	(*GetDomainsReq)(nil),              // 1: pkg.kannon.admin.apiv1.GetDomainsReq
+	(*GetDomainsResponse)(nil),         // 2: pkg.kannon.admin.apiv1.GetDomainsResponse
+	(*GetDomainReq)(nil),               // 3: pkg.kannon.admin.apiv1.GetDomainReq
+	(*GetDomainRes)(nil),               // 4: pkg.kannon.admin.apiv1.GetDomainRes
+	(*CreateDomainRequest)(nil),        // 5: pkg.kannon.admin.apiv1.CreateDomainRequest
+	(*ImportedDKIMKey)(nil),            // 6: pkg.kannon.admin.apiv1.ImportedDKIMKey
+	(*Domain)(nil),                     // 7: pkg.kannon.admin.apiv1.Domain
+	(*DNSRecord)(nil),                  // 8: pkg.kannon.admin.apiv1.DNSRecord
+	(*DKIMKey)(nil),                    // 9: pkg.kannon.admin.apiv1.DKIMKey
+	(*RotateDKIMKeyReq)(nil),           // 10: pkg.kannon.admin.apiv1.RotateDKIMKeyReq
+	(*RotateDKIMKeyRes)(nil),           // 11: pkg.kannon.admin.apiv1.RotateDKIMKeyRes
+	(*ActivateDKIMKeyReq)(nil),         // 12: pkg.kannon.admin.apiv1.ActivateDKIMKeyReq
+	(*ActivateDKIMKeyRes)(nil),         // 13: pkg.kannon.admin.apiv1.ActivateDKIMKeyRes
+	(*RetireDKIMKeyReq)(nil),           // 14: pkg.kannon.admin.apiv1.RetireDKIMKeyReq
+	(*RetireDKIMKeyRes)(nil),           // 15: pkg.kannon.admin.apiv1.RetireDKIMKeyRes
+	(*SetTrackingPolicyReq)(nil),       // 16: pkg.kannon.admin.apiv1.SetTrackingPolicyReq
+	(*SetTrackingPolicyRes)(nil),       // 17: pkg.kannon.admin.apiv1.SetTrackingPolicyRes
+	(*SetReturnPathDomainReq)(nil),     // 18: pkg.kannon.admin.apiv1.SetReturnPathDomainReq
+	(*SetReturnPathDomainRes)(nil),     // 19: pkg.kannon.admin.apiv1.SetReturnPathDomainRes
+	(*SetFeedbackIdentityReq)(nil),     // 20: pkg.kannon.admin.apiv1.SetFeedbackIdentityReq
+	(*SetFeedbackIdentityRes)(nil),     // 21: pkg.kannon.admin.apiv1.SetFeedbackIdentityRes
+	(*SetLinkDecorationReq)(nil),       // 22: pkg.kannon.admin.apiv1.SetLinkDecorationReq
+	(*SetLinkDecorationRes)(nil),       // 23: pkg.kannon.admin.apiv1.SetLinkDecorationRes
+	(*SetRequireTLSReq)(nil),           // 24: pkg.kannon.admin.apiv1.SetRequireTLSReq
+	(*SetRequireTLSRes)(nil),           // 25: pkg.kannon.admin.apiv1.SetRequireTLSRes
+	(*SetIPPoolReq)(nil),               // 26: pkg.kannon.admin.apiv1.SetIPPoolReq
+	(*SetIPPoolRes)(nil),               // 27: pkg.kannon.admin.apiv1.SetIPPoolRes
+	(*SetSMIMECertificateReq)(nil),     // 28: pkg.kannon.admin.apiv1.SetSMIMECertificateReq
+	(*SetSMIMECertificateRes)(nil),     // 29: pkg.kannon.admin.apiv1.SetSMIMECertificateRes
+	(*Template)(nil),                   // 30: pkg.kannon.admin.apiv1.Template
+	(*CreateTemplateReq)(nil),          // 31: pkg.kannon.admin.apiv1.CreateTemplateReq
+	(*CreateTemplateRes)(nil),          // 32: pkg.kannon.admin.apiv1.CreateTemplateRes
+	(*UpdateTemplateReq)(nil),          // 33: pkg.kannon.admin.apiv1.UpdateTemplateReq
+	(*UpdateTemplateRes)(nil),          // 34: pkg.kannon.admin.apiv1.UpdateTemplateRes
+	(*DeleteTemplateReq)(nil),          // 35: pkg.kannon.admin.apiv1.DeleteTemplateReq
+	(*DeleteTemplateRes)(nil),          // 36: pkg.kannon.admin.apiv1.DeleteTemplateRes
+	(*GetTemplateReq)(nil),             // 37: pkg.kannon.admin.apiv1.GetTemplateReq
+	(*GetTemplateRes)(nil),             // 38: pkg.kannon.admin.apiv1.GetTemplateRes
+	(*GetTemplatesReq)(nil),            // 39: pkg.kannon.admin.apiv1.GetTemplatesReq
+	(*GetTemplatesRes)(nil),            // 40: pkg.kannon.admin.apiv1.GetTemplatesRes
+	(*APIKey)(nil),                     // 41: pkg.kannon.admin.apiv1.APIKey
+	(*CreateAPIKeyRequest)(nil),        // 42: pkg.kannon.admin.apiv1.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),       // 43: pkg.kannon.admin.apiv1.CreateAPIKeyResponse
+	(*ListAPIKeysRequest)(nil),         // 44: pkg.kannon.admin.apiv1.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),        // 45: pkg.kannon.admin.apiv1.ListAPIKeysResponse
+	(*GetAPIKeyRequest)(nil),           // 46: pkg.kannon.admin.apiv1.GetAPIKeyRequest
+	(*GetAPIKeyResponse)(nil),          // 47: pkg.kannon.admin.apiv1.GetAPIKeyResponse
+	(*DeactivateAPIKeyRequest)(nil),    // 48: pkg.kannon.admin.apiv1.DeactivateAPIKeyRequest
+	(*DeactivateAPIKeyResponse)(nil),   // 49: pkg.kannon.admin.apiv1.DeactivateAPIKeyResponse
+	(*GetWarmupProgressReq)(nil),       // 50: pkg.kannon.admin.apiv1.GetWarmupProgressReq
+	(*GetWarmupProgressRes)(nil),       // 51: pkg.kannon.admin.apiv1.GetWarmupProgressRes
+	(*WarmupProgress)(nil),             // 52: pkg.kannon.admin.apiv1.WarmupProgress
+	(*ProviderWarmupProgress)(nil),     // 53: pkg.kannon.admin.apiv1.ProviderWarmupProgress
+	(*WarmupUsage)(nil),                // 54: pkg.kannon.admin.apiv1.WarmupUsage
+	(*GetDestinationCooldownsReq)(nil), // 55: pkg.kannon.admin.apiv1.GetDestinationCooldownsReq
+	(*GetDestinationCooldownsRes)(nil), // 56: pkg.kannon.admin.apiv1.GetDestinationCooldownsRes
+	(*DestinationCooldown)(nil),        // 57: pkg.kannon.admin.apiv1.DestinationCooldown
+	(*types.TrackingPolicy)(nil),       // 58: pkg.kannon.tracking.types.TrackingPolicy
+	(*types1.FeedbackIdentity)(nil),    // 59: pkg.kannon.feedback.types.FeedbackIdentity
+	(*types2.LinkDecoration)(nil),      // 60: pkg.kannon.linkparams.types.LinkDecoration
+	(*timestamppb.Timestamp)(nil),      // 61: google.protobuf.Timestamp
 }
 var file_kannon_admin_apiv1_adminapiv1_proto_depIdxs = []int32{
-	5,  // 0: pkg.kannon.admin.apiv1.GetDomainsResponse.domains:type_name -> pkg.kannon.admin.apiv1.Domain
-	5,  // 1: pkg.kannon.admin.apiv1.GetDomainRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
-	28, // 2: pkg.kannon.admin.apiv1.Domain.tracking:type_name -> pkg.kannon.tracking.types.TrackingPolicy
-	28, // 3: pkg.kannon.admin.apiv1.SetTrackingPolicyReq.tracking:type_name -> pkg.kannon.tracking.types.TrackingPolicy
-	5,  // 4: pkg.kannon.admin.apiv1.SetTrackingPolicyRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
-	8,  // 5: pkg.kannon.admin.apiv1.CreateTemplateRes.template:type_name -> pkg.kannon.admin.apiv1.Template
-	8,  // 6: pkg.kannon.admin.apiv1.UpdateTemplateRes.template:type_name -> pkg.kannon.admin.apiv1.Template
-	8,  // 7: pkg.kannon.admin.apiv1.DeleteTemplateRes.template:type_name -> pkg.kannon.admin.apiv1.Template
-	8,  // 8: pkg.kannon.admin.apiv1.GetTemplateRes.template:type_name -> pkg.kannon.admin.apiv1.Template
-	8,  // 9: pkg.kannon.admin.apiv1.GetTemplatesRes.templates:type_name -> pkg.kannon.admin.apiv1.Template
-	29, // 10: pkg.kannon.admin.apiv1.APIKey.created_at:type_name -> google.protobuf.Timestamp
-	29, // 11: pkg.kannon.admin.apiv1.APIKey.expires_at:type_name -> google.protobuf.Timestamp
-	29, // 12: pkg.kannon.admin.apiv1.APIKey.deactivated_at:type_name -> google.protobuf.Timestamp
-	29, // 13: pkg.kannon.admin.apiv1.CreateAPIKeyRequest.expires_at:type_name -> google.protobuf.Timestamp
-	19, // 14: pkg.kannon.admin.apiv1.CreateAPIKeyResponse.api_key:type_name -> pkg.kannon.admin.apiv1.APIKey
-	19, // 15: pkg.kannon.admin.apiv1.ListAPIKeysResponse.api_keys:type_name -> pkg.kannon.admin.apiv1.APIKey
-	19, // 16: pkg.kannon.admin.apiv1.GetAPIKeyResponse.api_key:type_name -> pkg.kannon.admin.apiv1.APIKey
-	19, // 17: pkg.kannon.admin.apiv1.DeactivateAPIKeyResponse.api_key:type_name -> pkg.kannon.admin.apiv1.APIKey
-	0,  // 18: pkg.kannon.admin.apiv1.Api.GetDomains:input_type -> pkg.kannon.admin.apiv1.GetDomainsReq
-	2,  // 19: pkg.kannon.admin.apiv1.Api.GetDomain:input_type -> pkg.kannon.admin.apiv1.GetDomainReq
-	4,  // 20: pkg.kannon.admin.apiv1.Api.CreateDomain:input_type -> pkg.kannon.admin.apiv1.CreateDomainRequest
-	6,  // 21: pkg.kannon.admin.apiv1.Api.SetTrackingPolicy:input_type -> pkg.kannon.admin.apiv1.SetTrackingPolicyReq
-	9,  // 22: pkg.kannon.admin.apiv1.Api.CreateTemplate:input_type -> pkg.kannon.admin.apiv1.CreateTemplateReq
-	11, // 23: pkg.kannon.admin.apiv1.Api.UpdateTemplate:input_type -> pkg.kannon.admin.apiv1.UpdateTemplateReq
-	13, // 24: pkg.kannon.admin.apiv1.Api.DeleteTemplate:input_type -> pkg.kannon.admin.apiv1.DeleteTemplateReq
-	15, // 25: pkg.kannon.admin.apiv1.Api.GetTemplate:input_type -> pkg.kannon.admin.apiv1.GetTemplateReq
-	17, // 26: pkg.kannon.admin.apiv1.Api.GetTemplates:input_type -> pkg.kannon.admin.apiv1.GetTemplatesReq
-	20, // 27: pkg.kannon.admin.apiv1.Api.CreateAPIKey:input_type -> pkg.kannon.admin.apiv1.CreateAPIKeyRequest
-	22, // 28: pkg.kannon.admin.apiv1.Api.ListAPIKeys:input_type -> pkg.kannon.admin.apiv1.ListAPIKeysRequest
-	24, // 29: pkg.kannon.admin.apiv1.Api.GetAPIKey:input_type -> pkg.kannon.admin.apiv1.GetAPIKeyRequest
-	26, // 30: pkg.kannon.admin.apiv1.Api.DeactivateAPIKey:input_type -> pkg.kannon.admin.apiv1.DeactivateAPIKeyRequest
-	1,  // 31: pkg.kannon.admin.apiv1.Api.GetDomains:output_type -> pkg.kannon.admin.apiv1.GetDomainsResponse
-	3,  // 32: pkg.kannon.admin.apiv1.Api.GetDomain:output_type -> pkg.kannon.admin.apiv1.GetDomainRes
-	5,  // 33: pkg.kannon.admin.apiv1.Api.CreateDomain:output_type -> pkg.kannon.admin.apiv1.Domain
-	7,  // 34: pkg.kannon.admin.apiv1.Api.SetTrackingPolicy:output_type -> pkg.kannon.admin.apiv1.SetTrackingPolicyRes
-	10, // 35: pkg.kannon.admin.apiv1.Api.CreateTemplate:output_type -> pkg.kannon.admin.apiv1.CreateTemplateRes
-	12, // 36: pkg.kannon.admin.apiv1.Api.UpdateTemplate:output_type -> pkg.kannon.admin.apiv1.UpdateTemplateRes
-	14, // 37: pkg.kannon.admin.apiv1.Api.DeleteTemplate:output_type -> pkg.kannon.admin.apiv1.DeleteTemplateRes
-	16, // 38: pkg.kannon.admin.apiv1.Api.GetTemplate:output_type -> pkg.kannon.admin.apiv1.GetTemplateRes
-	18, // 39: pkg.kannon.admin.apiv1.Api.GetTemplates:output_type -> pkg.kannon.admin.apiv1.GetTemplatesRes
-	21, // 40: pkg.kannon.admin.apiv1.Api.CreateAPIKey:output_type -> pkg.kannon.admin.apiv1.CreateAPIKeyResponse
-	23, // 41: pkg.kannon.admin.apiv1.Api.ListAPIKeys:output_type -> pkg.kannon.admin.apiv1.ListAPIKeysResponse
-	25, // 42: pkg.kannon.admin.apiv1.Api.GetAPIKey:output_type -> pkg.kannon.admin.apiv1.GetAPIKeyResponse
-	27, // 43: pkg.kannon.admin.apiv1.Api.DeactivateAPIKey:output_type -> pkg.kannon.admin.apiv1.DeactivateAPIKeyResponse
-	31, // [31:44] is the sub-list for method output_type
-	18, // [18:31] is the sub-list for method input_type
-	18, // [18:18] is the sub-list for extension type_name
-	18, // [18:18] is the sub-list for extension extendee
-	0,  // [0:18] is the sub-list for field type_name
+	7,  // 0: pkg.kannon.admin.apiv1.GetDomainsResponse.domains:type_name -> pkg.kannon.admin.apiv1.Domain
+	7,  // 1: pkg.kannon.admin.apiv1.GetDomainRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
+	6,  // 2: pkg.kannon.admin.apiv1.CreateDomainRequest.dkim_import:type_name -> pkg.kannon.admin.apiv1.ImportedDKIMKey
+	58, // 3: pkg.kannon.admin.apiv1.Domain.tracking:type_name -> pkg.kannon.tracking.types.TrackingPolicy
+	9,  // 4: pkg.kannon.admin.apiv1.Domain.dkim_keys:type_name -> pkg.kannon.admin.apiv1.DKIMKey
+	8,  // 5: pkg.kannon.admin.apiv1.Domain.return_path_records:type_name -> pkg.kannon.admin.apiv1.DNSRecord
+	59, // 6: pkg.kannon.admin.apiv1.Domain.feedback:type_name -> pkg.kannon.feedback.types.FeedbackIdentity
+	60, // 7: pkg.kannon.admin.apiv1.Domain.link_decoration:type_name -> pkg.kannon.linkparams.types.LinkDecoration
+	0,  // 8: pkg.kannon.admin.apiv1.DKIMKey.state:type_name -> pkg.kannon.admin.apiv1.DKIMKeyState
+	61, // 9: pkg.kannon.admin.apiv1.DKIMKey.created_at:type_name -> google.protobuf.Timestamp
+	61, // 10: pkg.kannon.admin.apiv1.DKIMKey.activated_at:type_name -> google.protobuf.Timestamp
+	61, // 11: pkg.kannon.admin.apiv1.DKIMKey.retired_at:type_name -> google.protobuf.Timestamp
+	9,  // 12: pkg.kannon.admin.apiv1.RotateDKIMKeyRes.key:type_name -> pkg.kannon.admin.apiv1.DKIMKey
+	7,  // 13: pkg.kannon.admin.apiv1.ActivateDKIMKeyRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
+	7,  // 14: pkg.kannon.admin.apiv1.RetireDKIMKeyRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
+	58, // 15: pkg.kannon.admin.apiv1.SetTrackingPolicyReq.tracking:type_name -> pkg.kannon.tracking.types.TrackingPolicy
+	7,  // 16: pkg.kannon.admin.apiv1.SetTrackingPolicyRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
+	7,  // 17: pkg.kannon.admin.apiv1.SetReturnPathDomainRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
+	59, // 18: pkg.kannon.admin.apiv1.SetFeedbackIdentityReq.feedback:type_name -> pkg.kannon.feedback.types.FeedbackIdentity
+	7,  // 19: pkg.kannon.admin.apiv1.SetFeedbackIdentityRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
+	60, // 20: pkg.kannon.admin.apiv1.SetLinkDecorationReq.link_decoration:type_name -> pkg.kannon.linkparams.types.LinkDecoration
+	7,  // 21: pkg.kannon.admin.apiv1.SetLinkDecorationRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
+	7,  // 22: pkg.kannon.admin.apiv1.SetRequireTLSRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
+	7,  // 23: pkg.kannon.admin.apiv1.SetIPPoolRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
+	7,  // 24: pkg.kannon.admin.apiv1.SetSMIMECertificateRes.domain:type_name -> pkg.kannon.admin.apiv1.Domain
+	30, // 25: pkg.kannon.admin.apiv1.CreateTemplateRes.template:type_name -> pkg.kannon.admin.apiv1.Template
+	30, // 26: pkg.kannon.admin.apiv1.UpdateTemplateRes.template:type_name -> pkg.kannon.admin.apiv1.Template
+	30, // 27: pkg.kannon.admin.apiv1.DeleteTemplateRes.template:type_name -> pkg.kannon.admin.apiv1.Template
+	30, // 28: pkg.kannon.admin.apiv1.GetTemplateRes.template:type_name -> pkg.kannon.admin.apiv1.Template
+	30, // 29: pkg.kannon.admin.apiv1.GetTemplatesRes.templates:type_name -> pkg.kannon.admin.apiv1.Template
+	61, // 30: pkg.kannon.admin.apiv1.APIKey.created_at:type_name -> google.protobuf.Timestamp
+	61, // 31: pkg.kannon.admin.apiv1.APIKey.expires_at:type_name -> google.protobuf.Timestamp
+	61, // 32: pkg.kannon.admin.apiv1.APIKey.deactivated_at:type_name -> google.protobuf.Timestamp
+	61, // 33: pkg.kannon.admin.apiv1.CreateAPIKeyRequest.expires_at:type_name -> google.protobuf.Timestamp
+	41, // 34: pkg.kannon.admin.apiv1.CreateAPIKeyResponse.api_key:type_name -> pkg.kannon.admin.apiv1.APIKey
+	41, // 35: pkg.kannon.admin.apiv1.ListAPIKeysResponse.api_keys:type_name -> pkg.kannon.admin.apiv1.APIKey
+	41, // 36: pkg.kannon.admin.apiv1.GetAPIKeyResponse.api_key:type_name -> pkg.kannon.admin.apiv1.APIKey
+	41, // 37: pkg.kannon.admin.apiv1.DeactivateAPIKeyResponse.api_key:type_name -> pkg.kannon.admin.apiv1.APIKey
+	52, // 38: pkg.kannon.admin.apiv1.GetWarmupProgressRes.addresses:type_name -> pkg.kannon.admin.apiv1.WarmupProgress
+	61, // 39: pkg.kannon.admin.apiv1.WarmupProgress.start:type_name -> google.protobuf.Timestamp
+	54, // 40: pkg.kannon.admin.apiv1.WarmupProgress.today:type_name -> pkg.kannon.admin.apiv1.WarmupUsage
+	54, // 41: pkg.kannon.admin.apiv1.WarmupProgress.hour:type_name -> pkg.kannon.admin.apiv1.WarmupUsage
+	53, // 42: pkg.kannon.admin.apiv1.WarmupProgress.providers:type_name -> pkg.kannon.admin.apiv1.ProviderWarmupProgress
+	54, // 43: pkg.kannon.admin.apiv1.ProviderWarmupProgress.today:type_name -> pkg.kannon.admin.apiv1.WarmupUsage
+	54, // 44: pkg.kannon.admin.apiv1.ProviderWarmupProgress.hour:type_name -> pkg.kannon.admin.apiv1.WarmupUsage
+	57, // 45: pkg.kannon.admin.apiv1.GetDestinationCooldownsRes.cooldowns:type_name -> pkg.kannon.admin.apiv1.DestinationCooldown
+	61, // 46: pkg.kannon.admin.apiv1.DestinationCooldown.since:type_name -> google.protobuf.Timestamp
+	61, // 47: pkg.kannon.admin.apiv1.DestinationCooldown.until:type_name -> google.protobuf.Timestamp
+	1,  // 48: pkg.kannon.admin.apiv1.Api.GetDomains:input_type -> pkg.kannon.admin.apiv1.GetDomainsReq
+	3,  // 49: pkg.kannon.admin.apiv1.Api.GetDomain:input_type -> pkg.kannon.admin.apiv1.GetDomainReq
+	5,  // 50: pkg.kannon.admin.apiv1.Api.CreateDomain:input_type -> pkg.kannon.admin.apiv1.CreateDomainRequest
+	16, // 51: pkg.kannon.admin.apiv1.Api.SetTrackingPolicy:input_type -> pkg.kannon.admin.apiv1.SetTrackingPolicyReq
+	18, // 52: pkg.kannon.admin.apiv1.Api.SetReturnPathDomain:input_type -> pkg.kannon.admin.apiv1.SetReturnPathDomainReq
+	20, // 53: pkg.kannon.admin.apiv1.Api.SetFeedbackIdentity:input_type -> pkg.kannon.admin.apiv1.SetFeedbackIdentityReq
+	22, // 54: pkg.kannon.admin.apiv1.Api.SetLinkDecoration:input_type -> pkg.kannon.admin.apiv1.SetLinkDecorationReq
+	24, // 55: pkg.kannon.admin.apiv1.Api.SetRequireTLS:input_type -> pkg.kannon.admin.apiv1.SetRequireTLSReq
+	26, // 56: pkg.kannon.admin.apiv1.Api.SetIPPool:input_type -> pkg.kannon.admin.apiv1.SetIPPoolReq
+	28, // 57: pkg.kannon.admin.apiv1.Api.SetSMIMECertificate:input_type -> pkg.kannon.admin.apiv1.SetSMIMECertificateReq
+	10, // 58: pkg.kannon.admin.apiv1.Api.RotateDKIMKey:input_type -> pkg.kannon.admin.apiv1.RotateDKIMKeyReq
+	12, // 59: pkg.kannon.admin.apiv1.Api.ActivateDKIMKey:input_type -> pkg.kannon.admin.apiv1.ActivateDKIMKeyReq
+	14, // 60: pkg.kannon.admin.apiv1.Api.RetireDKIMKey:input_type -> pkg.kannon.admin.apiv1.RetireDKIMKeyReq
+	31, // 61: pkg.kannon.admin.apiv1.Api.CreateTemplate:input_type -> pkg.kannon.admin.apiv1.CreateTemplateReq
+	33, // 62: pkg.kannon.admin.apiv1.Api.UpdateTemplate:input_type -> pkg.kannon.admin.apiv1.UpdateTemplateReq
+	35, // 63: pkg.kannon.admin.apiv1.Api.DeleteTemplate:input_type -> pkg.kannon.admin.apiv1.DeleteTemplateReq
+	37, // 64: pkg.kannon.admin.apiv1.Api.GetTemplate:input_type -> pkg.kannon.admin.apiv1.GetTemplateReq
+	39, // 65: pkg.kannon.admin.apiv1.Api.GetTemplates:input_type -> pkg.kannon.admin.apiv1.GetTemplatesReq
+	42, // 66: pkg.kannon.admin.apiv1.Api.CreateAPIKey:input_type -> pkg.kannon.admin.apiv1.CreateAPIKeyRequest
+	44, // 67: pkg.kannon.admin.apiv1.Api.ListAPIKeys:input_type -> pkg.kannon.admin.apiv1.ListAPIKeysRequest
+	46, // 68: pkg.kannon.admin.apiv1.Api.GetAPIKey:input_type -> pkg.kannon.admin.apiv1.GetAPIKeyRequest
+	48, // 69: pkg.kannon.admin.apiv1.Api.DeactivateAPIKey:input_type -> pkg.kannon.admin.apiv1.DeactivateAPIKeyRequest
+	50, // 70: pkg.kannon.admin.apiv1.Api.GetWarmupProgress:input_type -> pkg.kannon.admin.apiv1.GetWarmupProgressReq
+	55, // 71: pkg.kannon.admin.apiv1.Api.GetDestinationCooldowns:input_type -> pkg.kannon.admin.apiv1.GetDestinationCooldownsReq
+	2,  // 72: pkg.kannon.admin.apiv1.Api.GetDomains:output_type -> pkg.kannon.admin.apiv1.GetDomainsResponse
+	4,  // 73: pkg.kannon.admin.apiv1.Api.GetDomain:output_type -> pkg.kannon.admin.apiv1.GetDomainRes
+	7,  // 74: pkg.kannon.admin.apiv1.Api.CreateDomain:output_type -> pkg.kannon.admin.apiv1.Domain
+	17, // 75: pkg.kannon.admin.apiv1.Api.SetTrackingPolicy:output_type -> pkg.kannon.admin.apiv1.SetTrackingPolicyRes
+	19, // 76: pkg.kannon.admin.apiv1.Api.SetReturnPathDomain:output_type -> pkg.kannon.admin.apiv1.SetReturnPathDomainRes
+	21, // 77: pkg.kannon.admin.apiv1.Api.SetFeedbackIdentity:output_type -> pkg.kannon.admin.apiv1.SetFeedbackIdentityRes
+	23, // 78: pkg.kannon.admin.apiv1.Api.SetLinkDecoration:output_type -> pkg.kannon.admin.apiv1.SetLinkDecorationRes
+	25, // 79: pkg.kannon.admin.apiv1.Api.SetRequireTLS:output_type -> pkg.kannon.admin.apiv1.SetRequireTLSRes
+	27, // 80: pkg.kannon.admin.apiv1.Api.SetIPPool:output_type -> pkg.kannon.admin.apiv1.SetIPPoolRes
+	29, // 81: pkg.kannon.admin.apiv1.Api.SetSMIMECertificate:output_type -> pkg.kannon.admin.apiv1.SetSMIMECertificateRes
+	11, // 82: pkg.kannon.admin.apiv1.Api.RotateDKIMKey:output_type -> pkg.kannon.admin.apiv1.RotateDKIMKeyRes
+	13, // 83: pkg.kannon.admin.apiv1.Api.ActivateDKIMKey:output_type -> pkg.kannon.admin.apiv1.ActivateDKIMKeyRes
+	15, // 84: pkg.kannon.admin.apiv1.Api.RetireDKIMKey:output_type -> pkg.kannon.admin.apiv1.RetireDKIMKeyRes
+	32, // 85: pkg.kannon.admin.apiv1.Api.CreateTemplate:output_type -> pkg.kannon.admin.apiv1.CreateTemplateRes
+	34, // 86: pkg.kannon.admin.apiv1.Api.UpdateTemplate:output_type -> pkg.kannon.admin.apiv1.UpdateTemplateRes
+	36, // 87: pkg.kannon.admin.apiv1.Api.DeleteTemplate:output_type -> pkg.kannon.admin.apiv1.DeleteTemplateRes
+	38, // 88: pkg.kannon.admin.apiv1.Api.GetTemplate:output_type -> pkg.kannon.admin.apiv1.GetTemplateRes
+	40, // 89: pkg.kannon.admin.apiv1.Api.GetTemplates:output_type -> pkg.kannon.admin.apiv1.GetTemplatesRes
+	43, // 90: pkg.kannon.admin.apiv1.Api.CreateAPIKey:output_type -> pkg.kannon.admin.apiv1.CreateAPIKeyResponse
+	45, // 91: pkg.kannon.admin.apiv1.Api.ListAPIKeys:output_type -> pkg.kannon.admin.apiv1.ListAPIKeysResponse
+	47, // 92: pkg.kannon.admin.apiv1.Api.GetAPIKey:output_type -> pkg.kannon.admin.apiv1.GetAPIKeyResponse
+	49, // 93: pkg.kannon.admin.apiv1.Api.DeactivateAPIKey:output_type -> pkg.kannon.admin.apiv1.DeactivateAPIKeyResponse
+	51, // 94: pkg.kannon.admin.apiv1.Api.GetWarmupProgress:output_type -> pkg.kannon.admin.apiv1.GetWarmupProgressRes
+	56, // 95: pkg.kannon.admin.apiv1.Api.GetDestinationCooldowns:output_type -> pkg.kannon.admin.apiv1.GetDestinationCooldownsRes
+	72, // [72:96] is the sub-list for method output_type
+	48, // [48:72] is the sub-list for method input_type
+	48, // [48:48] is the sub-list for extension type_name
+	48, // [48:48] is the sub-list for extension extendee
+	0,  // [0:48] is the sub-list for field type_name
 }
 
 func init() { file_kannon_admin_apiv1_adminapiv1_proto_init() }
@@ -1698,18 +3758,23 @@ func file_kannon_admin_apiv1_adminapiv1_proto_init() {
 	if File_kannon_admin_apiv1_adminapiv1_proto != nil {
 		return
 	}
+	file_kannon_admin_apiv1_adminapiv1_proto_msgTypes[4].OneofWrappers = []any{
+		(*CreateDomainRequest_DkimRsaBits)(nil),
+		(*CreateDomainRequest_DkimImport)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kannon_admin_apiv1_adminapiv1_proto_rawDesc), len(file_kannon_admin_apiv1_adminapiv1_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   28,
+			NumEnums:      1,
+			NumMessages:   57,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_kannon_admin_apiv1_adminapiv1_proto_goTypes,
 		DependencyIndexes: file_kannon_admin_apiv1_adminapiv1_proto_depIdxs,
+		EnumInfos:         file_kannon_admin_apiv1_adminapiv1_proto_enumTypes,
 		MessageInfos:      file_kannon_admin_apiv1_adminapiv1_proto_msgTypes,
 	}.Build()
 	File_kannon_admin_apiv1_adminapiv1_proto = out.File
@@ -41,6 +41,24 @@ const (
 	ApiCreateDomainProcedure = "/pkg.kannon.admin.apiv1.Api/CreateDomain"
 	// ApiSetTrackingPolicyProcedure is the fully-qualified name of the Api's SetTrackingPolicy RPC.
 	ApiSetTrackingPolicyProcedure = "/pkg.kannon.admin.apiv1.Api/SetTrackingPolicy"
+	// ApiSetReturnPathDomainProcedure is the fully-qualified name of the Api's SetReturnPathDomain RPC.
+	ApiSetReturnPathDomainProcedure = "/pkg.kannon.admin.apiv1.Api/SetReturnPathDomain"
+	// ApiSetFeedbackIdentityProcedure is the fully-qualified name of the Api's SetFeedbackIdentity RPC.
+	ApiSetFeedbackIdentityProcedure = "/pkg.kannon.admin.apiv1.Api/SetFeedbackIdentity"
+	// ApiSetLinkDecorationProcedure is the fully-qualified name of the Api's SetLinkDecoration RPC.
+	ApiSetLinkDecorationProcedure = "/pkg.kannon.admin.apiv1.Api/SetLinkDecoration"
+	// ApiSetRequireTLSProcedure is the fully-qualified name of the Api's SetRequireTLS RPC.
+	ApiSetRequireTLSProcedure = "/pkg.kannon.admin.apiv1.Api/SetRequireTLS"
+	// ApiSetIPPoolProcedure is the fully-qualified name of the Api's SetIPPool RPC.
+	ApiSetIPPoolProcedure = "/pkg.kannon.admin.apiv1.Api/SetIPPool"
+	// ApiSetSMIMECertificateProcedure is the fully-qualified name of the Api's SetSMIMECertificate RPC.
+	ApiSetSMIMECertificateProcedure = "/pkg.kannon.admin.apiv1.Api/SetSMIMECertificate"
+	// ApiRotateDKIMKeyProcedure is the fully-qualified name of the Api's RotateDKIMKey RPC.
+	ApiRotateDKIMKeyProcedure = "/pkg.kannon.admin.apiv1.Api/RotateDKIMKey"
+	// ApiActivateDKIMKeyProcedure is the fully-qualified name of the Api's ActivateDKIMKey RPC.
+	ApiActivateDKIMKeyProcedure = "/pkg.kannon.admin.apiv1.Api/ActivateDKIMKey"
+	// ApiRetireDKIMKeyProcedure is the fully-qualified name of the Api's RetireDKIMKey RPC.
+	ApiRetireDKIMKeyProcedure = "/pkg.kannon.admin.apiv1.Api/RetireDKIMKey"
 	// ApiCreateTemplateProcedure is the fully-qualified name of the Api's CreateTemplate RPC.
 	ApiCreateTemplateProcedure = "/pkg.kannon.admin.apiv1.Api/CreateTemplate"
 	// ApiUpdateTemplateProcedure is the fully-qualified name of the Api's UpdateTemplate RPC.
@@ -59,6 +77,11 @@ const (
 	ApiGetAPIKeyProcedure = "/pkg.kannon.admin.apiv1.Api/GetAPIKey"
 	// ApiDeactivateAPIKeyProcedure is the fully-qualified name of the Api's DeactivateAPIKey RPC.
 	ApiDeactivateAPIKeyProcedure = "/pkg.kannon.admin.apiv1.Api/DeactivateAPIKey"
+	// ApiGetWarmupProgressProcedure is the fully-qualified name of the Api's GetWarmupProgress RPC.
+	ApiGetWarmupProgressProcedure = "/pkg.kannon.admin.apiv1.Api/GetWarmupProgress"
+	// ApiGetDestinationCooldownsProcedure is the fully-qualified name of the Api's
+	// GetDestinationCooldowns RPC.
+	ApiGetDestinationCooldownsProcedure = "/pkg.kannon.admin.apiv1.Api/GetDestinationCooldowns"
 )
 
 // ApiClient is a client for the pkg.kannon.admin.apiv1.Api service.
@@ -67,6 +90,15 @@ type ApiClient interface {
 	GetDomain(context.Context, *connect.Request[apiv1.GetDomainReq]) (*connect.Response[apiv1.GetDomainRes], error)
 	CreateDomain(context.Context, *connect.Request[apiv1.CreateDomainRequest]) (*connect.Response[apiv1.Domain], error)
 	SetTrackingPolicy(context.Context, *connect.Request[apiv1.SetTrackingPolicyReq]) (*connect.Response[apiv1.SetTrackingPolicyRes], error)
+	SetReturnPathDomain(context.Context, *connect.Request[apiv1.SetReturnPathDomainReq]) (*connect.Response[apiv1.SetReturnPathDomainRes], error)
+	SetFeedbackIdentity(context.Context, *connect.Request[apiv1.SetFeedbackIdentityReq]) (*connect.Response[apiv1.SetFeedbackIdentityRes], error)
+	SetLinkDecoration(context.Context, *connect.Request[apiv1.SetLinkDecorationReq]) (*connect.Response[apiv1.SetLinkDecorationRes], error)
+	SetRequireTLS(context.Context, *connect.Request[apiv1.SetRequireTLSReq]) (*connect.Response[apiv1.SetRequireTLSRes], error)
+	SetIPPool(context.Context, *connect.Request[apiv1.SetIPPoolReq]) (*connect.Response[apiv1.SetIPPoolRes], error)
+	SetSMIMECertificate(context.Context, *connect.Request[apiv1.SetSMIMECertificateReq]) (*connect.Response[apiv1.SetSMIMECertificateRes], error)
+	RotateDKIMKey(context.Context, *connect.Request[apiv1.RotateDKIMKeyReq]) (*connect.Response[apiv1.RotateDKIMKeyRes], error)
+	ActivateDKIMKey(context.Context, *connect.Request[apiv1.ActivateDKIMKeyReq]) (*connect.Response[apiv1.ActivateDKIMKeyRes], error)
+	RetireDKIMKey(context.Context, *connect.Request[apiv1.RetireDKIMKeyReq]) (*connect.Response[apiv1.RetireDKIMKeyRes], error)
 	CreateTemplate(context.Context, *connect.Request[apiv1.CreateTemplateReq]) (*connect.Response[apiv1.CreateTemplateRes], error)
 	UpdateTemplate(context.Context, *connect.Request[apiv1.UpdateTemplateReq]) (*connect.Response[apiv1.UpdateTemplateRes], error)
 	DeleteTemplate(context.Context, *connect.Request[apiv1.DeleteTemplateReq]) (*connect.Response[apiv1.DeleteTemplateRes], error)
@@ -76,6 +108,8 @@ type ApiClient interface {
 	ListAPIKeys(context.Context, *connect.Request[apiv1.ListAPIKeysRequest]) (*connect.Response[apiv1.ListAPIKeysResponse], error)
 	GetAPIKey(context.Context, *connect.Request[apiv1.GetAPIKeyRequest]) (*connect.Response[apiv1.GetAPIKeyResponse], error)
 	DeactivateAPIKey(context.Context, *connect.Request[apiv1.DeactivateAPIKeyRequest]) (*connect.Response[apiv1.DeactivateAPIKeyResponse], error)
+	GetWarmupProgress(context.Context, *connect.Request[apiv1.GetWarmupProgressReq]) (*connect.Response[apiv1.GetWarmupProgressRes], error)
+	GetDestinationCooldowns(context.Context, *connect.Request[apiv1.GetDestinationCooldownsReq]) (*connect.Response[apiv1.GetDestinationCooldownsRes], error)
 }
 
 // NewApiClient constructs a client for the pkg.kannon.admin.apiv1.Api service. By default, it uses
@@ -113,6 +147,60 @@ func NewApiClient(httpClient connect.HTTPClient, baseURL string, opts ...connect
 			connect.WithSchema(apiMethods.ByName("SetTrackingPolicy")),
 			connect.WithClientOptions(opts...),
 		),
+		setReturnPathDomain: connect.NewClient[apiv1.SetReturnPathDomainReq, apiv1.SetReturnPathDomainRes](
+			httpClient,
+			baseURL+ApiSetReturnPathDomainProcedure,
+			connect.WithSchema(apiMethods.ByName("SetReturnPathDomain")),
+			connect.WithClientOptions(opts...),
+		),
+		setFeedbackIdentity: connect.NewClient[apiv1.SetFeedbackIdentityReq, apiv1.SetFeedbackIdentityRes](
+			httpClient,
+			baseURL+ApiSetFeedbackIdentityProcedure,
+			connect.WithSchema(apiMethods.ByName("SetFeedbackIdentity")),
+			connect.WithClientOptions(opts...),
+		),
+		setLinkDecoration: connect.NewClient[apiv1.SetLinkDecorationReq, apiv1.SetLinkDecorationRes](
+			httpClient,
+			baseURL+ApiSetLinkDecorationProcedure,
+			connect.WithSchema(apiMethods.ByName("SetLinkDecoration")),
+			connect.WithClientOptions(opts...),
+		),
+		setRequireTLS: connect.NewClient[apiv1.SetRequireTLSReq, apiv1.SetRequireTLSRes](
+			httpClient,
+			baseURL+ApiSetRequireTLSProcedure,
+			connect.WithSchema(apiMethods.ByName("SetRequireTLS")),
+			connect.WithClientOptions(opts...),
+		),
+		setIPPool: connect.NewClient[apiv1.SetIPPoolReq, apiv1.SetIPPoolRes](
+			httpClient,
+			baseURL+ApiSetIPPoolProcedure,
+			connect.WithSchema(apiMethods.ByName("SetIPPool")),
+			connect.WithClientOptions(opts...),
+		),
+		setSMIMECertificate: connect.NewClient[apiv1.SetSMIMECertificateReq, apiv1.SetSMIMECertificateRes](
+			httpClient,
+			baseURL+ApiSetSMIMECertificateProcedure,
+			connect.WithSchema(apiMethods.ByName("SetSMIMECertificate")),
+			connect.WithClientOptions(opts...),
+		),
+		rotateDKIMKey: connect.NewClient[apiv1.RotateDKIMKeyReq, apiv1.RotateDKIMKeyRes](
+			httpClient,
+			baseURL+ApiRotateDKIMKeyProcedure,
+			connect.WithSchema(apiMethods.ByName("RotateDKIMKey")),
+			connect.WithClientOptions(opts...),
+		),
+		activateDKIMKey: connect.NewClient[apiv1.ActivateDKIMKeyReq, apiv1.ActivateDKIMKeyRes](
+			httpClient,
+			baseURL+ApiActivateDKIMKeyProcedure,
+			connect.WithSchema(apiMethods.ByName("ActivateDKIMKey")),
+			connect.WithClientOptions(opts...),
+		),
+		retireDKIMKey: connect.NewClient[apiv1.RetireDKIMKeyReq, apiv1.RetireDKIMKeyRes](
+			httpClient,
+			baseURL+ApiRetireDKIMKeyProcedure,
+			connect.WithSchema(apiMethods.ByName("RetireDKIMKey")),
+			connect.WithClientOptions(opts...),
+		),
 		createTemplate: connect.NewClient[apiv1.CreateTemplateReq, apiv1.CreateTemplateRes](
 			httpClient,
 			baseURL+ApiCreateTemplateProcedure,
@@ -167,24 +255,47 @@ func NewApiClient(httpClient connect.HTTPClient, baseURL string, opts ...connect
 			connect.WithSchema(apiMethods.ByName("DeactivateAPIKey")),
 			connect.WithClientOptions(opts...),
 		),
+		getWarmupProgress: connect.NewClient[apiv1.GetWarmupProgressReq, apiv1.GetWarmupProgressRes](
+			httpClient,
+			baseURL+ApiGetWarmupProgressProcedure,
+			connect.WithSchema(apiMethods.ByName("GetWarmupProgress")),
+			connect.WithClientOptions(opts...),
+		),
+		getDestinationCooldowns: connect.NewClient[apiv1.GetDestinationCooldownsReq, apiv1.GetDestinationCooldownsRes](
+			httpClient,
+			baseURL+ApiGetDestinationCooldownsProcedure,
+			connect.WithSchema(apiMethods.ByName("GetDestinationCooldowns")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // apiClient implements ApiClient.
 type apiClient struct {
-	getDomains        *connect.Client[apiv1.GetDomainsReq, apiv1.GetDomainsResponse]
-	getDomain         *connect.Client[apiv1.GetDomainReq, apiv1.GetDomainRes]
-	createDomain      *connect.Client[apiv1.CreateDomainRequest, apiv1.Domain]
-	setTrackingPolicy *connect.Client[apiv1.SetTrackingPolicyReq, apiv1.SetTrackingPolicyRes]
-	createTemplate    *connect.Client[apiv1.CreateTemplateReq, apiv1.CreateTemplateRes]
-	updateTemplate    *connect.Client[apiv1.UpdateTemplateReq, apiv1.UpdateTemplateRes]
-	deleteTemplate    *connect.Client[apiv1.DeleteTemplateReq, apiv1.DeleteTemplateRes]
-	getTemplate       *connect.Client[apiv1.GetTemplateReq, apiv1.GetTemplateRes]
-	getTemplates      *connect.Client[apiv1.GetTemplatesReq, apiv1.GetTemplatesRes]
-	createAPIKey      *connect.Client[apiv1.CreateAPIKeyRequest, apiv1.CreateAPIKeyResponse]
-	listAPIKeys       *connect.Client[apiv1.ListAPIKeysRequest, apiv1.ListAPIKeysResponse]
-	getAPIKey         *connect.Client[apiv1.GetAPIKeyRequest, apiv1.GetAPIKeyResponse]
-	deactivateAPIKey  *connect.Client[apiv1.DeactivateAPIKeyRequest, apiv1.DeactivateAPIKeyResponse]
+	getDomains              *connect.Client[apiv1.GetDomainsReq, apiv1.GetDomainsResponse]
+	getDomain               *connect.Client[apiv1.GetDomainReq, apiv1.GetDomainRes]
+	createDomain            *connect.Client[apiv1.CreateDomainRequest, apiv1.Domain]
+	setTrackingPolicy       *connect.Client[apiv1.SetTrackingPolicyReq, apiv1.SetTrackingPolicyRes]
+	setReturnPathDomain     *connect.Client[apiv1.SetReturnPathDomainReq, apiv1.SetReturnPathDomainRes]
+	setFeedbackIdentity     *connect.Client[apiv1.SetFeedbackIdentityReq, apiv1.SetFeedbackIdentityRes]
+	setLinkDecoration       *connect.Client[apiv1.SetLinkDecorationReq, apiv1.SetLinkDecorationRes]
+	setRequireTLS           *connect.Client[apiv1.SetRequireTLSReq, apiv1.SetRequireTLSRes]
+	setIPPool               *connect.Client[apiv1.SetIPPoolReq, apiv1.SetIPPoolRes]
+	setSMIMECertificate     *connect.Client[apiv1.SetSMIMECertificateReq, apiv1.SetSMIMECertificateRes]
+	rotateDKIMKey           *connect.Client[apiv1.RotateDKIMKeyReq, apiv1.RotateDKIMKeyRes]
+	activateDKIMKey         *connect.Client[apiv1.ActivateDKIMKeyReq, apiv1.ActivateDKIMKeyRes]
+	retireDKIMKey           *connect.Client[apiv1.RetireDKIMKeyReq, apiv1.RetireDKIMKeyRes]
+	createTemplate          *connect.Client[apiv1.CreateTemplateReq, apiv1.CreateTemplateRes]
+	updateTemplate          *connect.Client[apiv1.UpdateTemplateReq, apiv1.UpdateTemplateRes]
+	deleteTemplate          *connect.Client[apiv1.DeleteTemplateReq, apiv1.DeleteTemplateRes]
+	getTemplate             *connect.Client[apiv1.GetTemplateReq, apiv1.GetTemplateRes]
+	getTemplates            *connect.Client[apiv1.GetTemplatesReq, apiv1.GetTemplatesRes]
+	createAPIKey            *connect.Client[apiv1.CreateAPIKeyRequest, apiv1.CreateAPIKeyResponse]
+	listAPIKeys             *connect.Client[apiv1.ListAPIKeysRequest, apiv1.ListAPIKeysResponse]
+	getAPIKey               *connect.Client[apiv1.GetAPIKeyRequest, apiv1.GetAPIKeyResponse]
+	deactivateAPIKey        *connect.Client[apiv1.DeactivateAPIKeyRequest, apiv1.DeactivateAPIKeyResponse]
+	getWarmupProgress       *connect.Client[apiv1.GetWarmupProgressReq, apiv1.GetWarmupProgressRes]
+	getDestinationCooldowns *connect.Client[apiv1.GetDestinationCooldownsReq, apiv1.GetDestinationCooldownsRes]
 }
 
 // GetDomains calls pkg.kannon.admin.apiv1.Api.GetDomains.
@@ -207,6 +318,51 @@ func (c *apiClient) SetTrackingPolicy(ctx context.Context, req *connect.Request[
 	return c.setTrackingPolicy.CallUnary(ctx, req)
 }
 
+// SetReturnPathDomain calls pkg.kannon.admin.apiv1.Api.SetReturnPathDomain.
+func (c *apiClient) SetReturnPathDomain(ctx context.Context, req *connect.Request[apiv1.SetReturnPathDomainReq]) (*connect.Response[apiv1.SetReturnPathDomainRes], error) {
+	return c.setReturnPathDomain.CallUnary(ctx, req)
+}
+
+// SetFeedbackIdentity calls pkg.kannon.admin.apiv1.Api.SetFeedbackIdentity.
+func (c *apiClient) SetFeedbackIdentity(ctx context.Context, req *connect.Request[apiv1.SetFeedbackIdentityReq]) (*connect.Response[apiv1.SetFeedbackIdentityRes], error) {
+	return c.setFeedbackIdentity.CallUnary(ctx, req)
+}
+
+// SetLinkDecoration calls pkg.kannon.admin.apiv1.Api.SetLinkDecoration.
+func (c *apiClient) SetLinkDecoration(ctx context.Context, req *connect.Request[apiv1.SetLinkDecorationReq]) (*connect.Response[apiv1.SetLinkDecorationRes], error) {
+	return c.setLinkDecoration.CallUnary(ctx, req)
+}
+
+// SetRequireTLS calls pkg.kannon.admin.apiv1.Api.SetRequireTLS.
+func (c *apiClient) SetRequireTLS(ctx context.Context, req *connect.Request[apiv1.SetRequireTLSReq]) (*connect.Response[apiv1.SetRequireTLSRes], error) {
+	return c.setRequireTLS.CallUnary(ctx, req)
+}
+
+// SetIPPool calls pkg.kannon.admin.apiv1.Api.SetIPPool.
+func (c *apiClient) SetIPPool(ctx context.Context, req *connect.Request[apiv1.SetIPPoolReq]) (*connect.Response[apiv1.SetIPPoolRes], error) {
+	return c.setIPPool.CallUnary(ctx, req)
+}
+
+// SetSMIMECertificate calls pkg.kannon.admin.apiv1.Api.SetSMIMECertificate.
+func (c *apiClient) SetSMIMECertificate(ctx context.Context, req *connect.Request[apiv1.SetSMIMECertificateReq]) (*connect.Response[apiv1.SetSMIMECertificateRes], error) {
+	return c.setSMIMECertificate.CallUnary(ctx, req)
+}
+
+// RotateDKIMKey calls pkg.kannon.admin.apiv1.Api.RotateDKIMKey.
+func (c *apiClient) RotateDKIMKey(ctx context.Context, req *connect.Request[apiv1.RotateDKIMKeyReq]) (*connect.Response[apiv1.RotateDKIMKeyRes], error) {
+	return c.rotateDKIMKey.CallUnary(ctx, req)
+}
+
+// ActivateDKIMKey calls pkg.kannon.admin.apiv1.Api.ActivateDKIMKey.
+func (c *apiClient) ActivateDKIMKey(ctx context.Context, req *connect.Request[apiv1.ActivateDKIMKeyReq]) (*connect.Response[apiv1.ActivateDKIMKeyRes], error) {
+	return c.activateDKIMKey.CallUnary(ctx, req)
+}
+
+// RetireDKIMKey calls pkg.kannon.admin.apiv1.Api.RetireDKIMKey.
+func (c *apiClient) RetireDKIMKey(ctx context.Context, req *connect.Request[apiv1.RetireDKIMKeyReq]) (*connect.Response[apiv1.RetireDKIMKeyRes], error) {
+	return c.retireDKIMKey.CallUnary(ctx, req)
+}
+
 // CreateTemplate calls pkg.kannon.admin.apiv1.Api.CreateTemplate.
 func (c *apiClient) CreateTemplate(ctx context.Context, req *connect.Request[apiv1.CreateTemplateReq]) (*connect.Response[apiv1.CreateTemplateRes], error) {
 	return c.createTemplate.CallUnary(ctx, req)
@@ -252,12 +408,31 @@ func (c *apiClient) DeactivateAPIKey(ctx context.Context, req *connect.Request[a
 	return c.deactivateAPIKey.CallUnary(ctx, req)
 }
 
+// GetWarmupProgress calls pkg.kannon.admin.apiv1.Api.GetWarmupProgress.
+func (c *apiClient) GetWarmupProgress(ctx context.Context, req *connect.Request[apiv1.GetWarmupProgressReq]) (*connect.Response[apiv1.GetWarmupProgressRes], error) {
+	return c.getWarmupProgress.CallUnary(ctx, req)
+}
+
+// GetDestinationCooldowns calls pkg.kannon.admin.apiv1.Api.GetDestinationCooldowns.
+func (c *apiClient) GetDestinationCooldowns(ctx context.Context, req *connect.Request[apiv1.GetDestinationCooldownsReq]) (*connect.Response[apiv1.GetDestinationCooldownsRes], error) {
+	return c.getDestinationCooldowns.CallUnary(ctx, req)
+}
+
 // ApiHandler is an implementation of the pkg.kannon.admin.apiv1.Api service.
 type ApiHandler interface {
 	GetDomains(context.Context, *connect.Request[apiv1.GetDomainsReq]) (*connect.Response[apiv1.GetDomainsResponse], error)
 	GetDomain(context.Context, *connect.Request[apiv1.GetDomainReq]) (*connect.Response[apiv1.GetDomainRes], error)
 	CreateDomain(context.Context, *connect.Request[apiv1.CreateDomainRequest]) (*connect.Response[apiv1.Domain], error)
 	SetTrackingPolicy(context.Context, *connect.Request[apiv1.SetTrackingPolicyReq]) (*connect.Response[apiv1.SetTrackingPolicyRes], error)
+	SetReturnPathDomain(context.Context, *connect.Request[apiv1.SetReturnPathDomainReq]) (*connect.Response[apiv1.SetReturnPathDomainRes], error)
+	SetFeedbackIdentity(context.Context, *connect.Request[apiv1.SetFeedbackIdentityReq]) (*connect.Response[apiv1.SetFeedbackIdentityRes], error)
+	SetLinkDecoration(context.Context, *connect.Request[apiv1.SetLinkDecorationReq]) (*connect.Response[apiv1.SetLinkDecorationRes], error)
+	SetRequireTLS(context.Context, *connect.Request[apiv1.SetRequireTLSReq]) (*connect.Response[apiv1.SetRequireTLSRes], error)
+	SetIPPool(context.Context, *connect.Request[apiv1.SetIPPoolReq]) (*connect.Response[apiv1.SetIPPoolRes], error)
+	SetSMIMECertificate(context.Context, *connect.Request[apiv1.SetSMIMECertificateReq]) (*connect.Response[apiv1.SetSMIMECertificateRes], error)
+	RotateDKIMKey(context.Context, *connect.Request[apiv1.RotateDKIMKeyReq]) (*connect.Response[apiv1.RotateDKIMKeyRes], error)
+	ActivateDKIMKey(context.Context, *connect.Request[apiv1.ActivateDKIMKeyReq]) (*connect.Response[apiv1.ActivateDKIMKeyRes], error)
+	RetireDKIMKey(context.Context, *connect.Request[apiv1.RetireDKIMKeyReq]) (*connect.Response[apiv1.RetireDKIMKeyRes], error)
 	CreateTemplate(context.Context, *connect.Request[apiv1.CreateTemplateReq]) (*connect.Response[apiv1.CreateTemplateRes], error)
 	UpdateTemplate(context.Context, *connect.Request[apiv1.UpdateTemplateReq]) (*connect.Response[apiv1.UpdateTemplateRes], error)
 	DeleteTemplate(context.Context, *connect.Request[apiv1.DeleteTemplateReq]) (*connect.Response[apiv1.DeleteTemplateRes], error)
@@ -267,6 +442,8 @@ type ApiHandler interface {
 	ListAPIKeys(context.Context, *connect.Request[apiv1.ListAPIKeysRequest]) (*connect.Response[apiv1.ListAPIKeysResponse], error)
 	GetAPIKey(context.Context, *connect.Request[apiv1.GetAPIKeyRequest]) (*connect.Response[apiv1.GetAPIKeyResponse], error)
 	DeactivateAPIKey(context.Context, *connect.Request[apiv1.DeactivateAPIKeyRequest]) (*connect.Response[apiv1.DeactivateAPIKeyResponse], error)
+	GetWarmupProgress(context.Context, *connect.Request[apiv1.GetWarmupProgressReq]) (*connect.Response[apiv1.GetWarmupProgressRes], error)
+	GetDestinationCooldowns(context.Context, *connect.Request[apiv1.GetDestinationCooldownsReq]) (*connect.Response[apiv1.GetDestinationCooldownsRes], error)
 }
 
 // NewApiHandler builds an HTTP handler from the service implementation. It returns the path on
@@ -300,6 +477,60 @@ func NewApiHandler(svc ApiHandler, opts ...connect.HandlerOption) (string, http.
 		connect.WithSchema(apiMethods.ByName("SetTrackingPolicy")),
 		connect.WithHandlerOptions(opts...),
 	)
+	apiSetReturnPathDomainHandler := connect.NewUnaryHandler(
+		ApiSetReturnPathDomainProcedure,
+		svc.SetReturnPathDomain,
+		connect.WithSchema(apiMethods.ByName("SetReturnPathDomain")),
+		connect.WithHandlerOptions(opts...),
+	)
+	apiSetFeedbackIdentityHandler := connect.NewUnaryHandler(
+		ApiSetFeedbackIdentityProcedure,
+		svc.SetFeedbackIdentity,
+		connect.WithSchema(apiMethods.ByName("SetFeedbackIdentity")),
+		connect.WithHandlerOptions(opts...),
+	)
+	apiSetLinkDecorationHandler := connect.NewUnaryHandler(
+		ApiSetLinkDecorationProcedure,
+		svc.SetLinkDecoration,
+		connect.WithSchema(apiMethods.ByName("SetLinkDecoration")),
+		connect.WithHandlerOptions(opts...),
+	)
+	apiSetRequireTLSHandler := connect.NewUnaryHandler(
+		ApiSetRequireTLSProcedure,
+		svc.SetRequireTLS,
+		connect.WithSchema(apiMethods.ByName("SetRequireTLS")),
+		connect.WithHandlerOptions(opts...),
+	)
+	apiSetIPPoolHandler := connect.NewUnaryHandler(
+		ApiSetIPPoolProcedure,
+		svc.SetIPPool,
+		connect.WithSchema(apiMethods.ByName("SetIPPool")),
+		connect.WithHandlerOptions(opts...),
+	)
+	apiSetSMIMECertificateHandler := connect.NewUnaryHandler(
+		ApiSetSMIMECertificateProcedure,
+		svc.SetSMIMECertificate,
+		connect.WithSchema(apiMethods.ByName("SetSMIMECertificate")),
+		connect.WithHandlerOptions(opts...),
+	)
+	apiRotateDKIMKeyHandler := connect.NewUnaryHandler(
+		ApiRotateDKIMKeyProcedure,
+		svc.RotateDKIMKey,
+		connect.WithSchema(apiMethods.ByName("RotateDKIMKey")),
+		connect.WithHandlerOptions(opts...),
+	)
+	apiActivateDKIMKeyHandler := connect.NewUnaryHandler(
+		ApiActivateDKIMKeyProcedure,
+		svc.ActivateDKIMKey,
+		connect.WithSchema(apiMethods.ByName("ActivateDKIMKey")),
+		connect.WithHandlerOptions(opts...),
+	)
+	apiRetireDKIMKeyHandler := connect.NewUnaryHandler(
+		ApiRetireDKIMKeyProcedure,
+		svc.RetireDKIMKey,
+		connect.WithSchema(apiMethods.ByName("RetireDKIMKey")),
+		connect.WithHandlerOptions(opts...),
+	)
 	apiCreateTemplateHandler := connect.NewUnaryHandler(
 		ApiCreateTemplateProcedure,
 		svc.CreateTemplate,
@@ -354,6 +585,18 @@ func NewApiHandler(svc ApiHandler, opts ...connect.HandlerOption) (string, http.
 		connect.WithSchema(apiMethods.ByName("DeactivateAPIKey")),
 		connect.WithHandlerOptions(opts...),
 	)
+	apiGetWarmupProgressHandler := connect.NewUnaryHandler(
+		ApiGetWarmupProgressProcedure,
+		svc.GetWarmupProgress,
+		connect.WithSchema(apiMethods.ByName("GetWarmupProgress")),
+		connect.WithHandlerOptions(opts...),
+	)
+	apiGetDestinationCooldownsHandler := connect.NewUnaryHandler(
+		ApiGetDestinationCooldownsProcedure,
+		svc.GetDestinationCooldowns,
+		connect.WithSchema(apiMethods.ByName("GetDestinationCooldowns")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/pkg.kannon.admin.apiv1.Api/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case ApiGetDomainsProcedure:
@@ -364,6 +607,24 @@ func NewApiHandler(svc ApiHandler, opts ...connect.HandlerOption) (string, http.
 			apiCreateDomainHandler.ServeHTTP(w, r)
 		case ApiSetTrackingPolicyProcedure:
 			apiSetTrackingPolicyHandler.ServeHTTP(w, r)
+		case ApiSetReturnPathDomainProcedure:
+			apiSetReturnPathDomainHandler.ServeHTTP(w, r)
+		case ApiSetFeedbackIdentityProcedure:
+			apiSetFeedbackIdentityHandler.ServeHTTP(w, r)
+		case ApiSetLinkDecorationProcedure:
+			apiSetLinkDecorationHandler.ServeHTTP(w, r)
+		case ApiSetRequireTLSProcedure:
+			apiSetRequireTLSHandler.ServeHTTP(w, r)
+		case ApiSetIPPoolProcedure:
+			apiSetIPPoolHandler.ServeHTTP(w, r)
+		case ApiSetSMIMECertificateProcedure:
+			apiSetSMIMECertificateHandler.ServeHTTP(w, r)
+		case ApiRotateDKIMKeyProcedure:
+			apiRotateDKIMKeyHandler.ServeHTTP(w, r)
+		case ApiActivateDKIMKeyProcedure:
+			apiActivateDKIMKeyHandler.ServeHTTP(w, r)
+		case ApiRetireDKIMKeyProcedure:
+			apiRetireDKIMKeyHandler.ServeHTTP(w, r)
 		case ApiCreateTemplateProcedure:
 			apiCreateTemplateHandler.ServeHTTP(w, r)
 		case ApiUpdateTemplateProcedure:
@@ -382,6 +643,10 @@ func NewApiHandler(svc ApiHandler, opts ...connect.HandlerOption) (string, http.
 			apiGetAPIKeyHandler.ServeHTTP(w, r)
 		case ApiDeactivateAPIKeyProcedure:
 			apiDeactivateAPIKeyHandler.ServeHTTP(w, r)
+		case ApiGetWarmupProgressProcedure:
+			apiGetWarmupProgressHandler.ServeHTTP(w, r)
+		case ApiGetDestinationCooldownsProcedure:
+			apiGetDestinationCooldownsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -407,6 +672,42 @@ func (UnimplementedApiHandler) SetTrackingPolicy(context.Context, *connect.Reque
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.SetTrackingPolicy is not implemented"))
 }
 
+func (UnimplementedApiHandler) SetReturnPathDomain(context.Context, *connect.Request[apiv1.SetReturnPathDomainReq]) (*connect.Response[apiv1.SetReturnPathDomainRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.SetReturnPathDomain is not implemented"))
+}
+
+func (UnimplementedApiHandler) SetFeedbackIdentity(context.Context, *connect.Request[apiv1.SetFeedbackIdentityReq]) (*connect.Response[apiv1.SetFeedbackIdentityRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.SetFeedbackIdentity is not implemented"))
+}
+
+func (UnimplementedApiHandler) SetLinkDecoration(context.Context, *connect.Request[apiv1.SetLinkDecorationReq]) (*connect.Response[apiv1.SetLinkDecorationRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.SetLinkDecoration is not implemented"))
+}
+
+func (UnimplementedApiHandler) SetRequireTLS(context.Context, *connect.Request[apiv1.SetRequireTLSReq]) (*connect.Response[apiv1.SetRequireTLSRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.SetRequireTLS is not implemented"))
+}
+
+func (UnimplementedApiHandler) SetIPPool(context.Context, *connect.Request[apiv1.SetIPPoolReq]) (*connect.Response[apiv1.SetIPPoolRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.SetIPPool is not implemented"))
+}
+
+func (UnimplementedApiHandler) SetSMIMECertificate(context.Context, *connect.Request[apiv1.SetSMIMECertificateReq]) (*connect.Response[apiv1.SetSMIMECertificateRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.SetSMIMECertificate is not implemented"))
+}
+
+func (UnimplementedApiHandler) RotateDKIMKey(context.Context, *connect.Request[apiv1.RotateDKIMKeyReq]) (*connect.Response[apiv1.RotateDKIMKeyRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.RotateDKIMKey is not implemented"))
+}
+
+func (UnimplementedApiHandler) ActivateDKIMKey(context.Context, *connect.Request[apiv1.ActivateDKIMKeyReq]) (*connect.Response[apiv1.ActivateDKIMKeyRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.ActivateDKIMKey is not implemented"))
+}
+
+func (UnimplementedApiHandler) RetireDKIMKey(context.Context, *connect.Request[apiv1.RetireDKIMKeyReq]) (*connect.Response[apiv1.RetireDKIMKeyRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.RetireDKIMKey is not implemented"))
+}
+
 func (UnimplementedApiHandler) CreateTemplate(context.Context, *connect.Request[apiv1.CreateTemplateReq]) (*connect.Response[apiv1.CreateTemplateRes], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.CreateTemplate is not implemented"))
 }
@@ -442,3 +743,11 @@ func (UnimplementedApiHandler) GetAPIKey(context.Context, *connect.Request[apiv1
 func (UnimplementedApiHandler) DeactivateAPIKey(context.Context, *connect.Request[apiv1.DeactivateAPIKeyRequest]) (*connect.Response[apiv1.DeactivateAPIKeyResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.DeactivateAPIKey is not implemented"))
 }
+
+func (UnimplementedApiHandler) GetWarmupProgress(context.Context, *connect.Request[apiv1.GetWarmupProgressReq]) (*connect.Response[apiv1.GetWarmupProgressRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.GetWarmupProgress is not implemented"))
+}
+
+func (UnimplementedApiHandler) GetDestinationCooldowns(context.Context, *connect.Request[apiv1.GetDestinationCooldownsReq]) (*connect.Response[apiv1.GetDestinationCooldownsRes], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pkg.kannon.admin.apiv1.Api.GetDestinationCooldowns is not implemented"))
+}
@@ -9,6 +9,7 @@ package apiv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -58,8 +59,16 @@ func (*HZRequest) Descriptor() ([]byte, []int) {
 }
 
 type HZResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Result        map[string]string      `protobuf:"bytes,1,rep,name=result" json:"result,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Result map[string]string      `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// The MX hosts the SMTPSender is passing over because they stopped
+	// answering, the one opened last first. Reading them failing is
+	// reported in result, under "mx-circuits".
+	Circuits []*MXCircuit `protobuf:"bytes,3,rep,name=circuits,proto3" json:"circuits,omitempty"`
+	// How many destinations the SMTPSender is holding mail back from after a
+	// throttling reply. Reading them failing is reported in result, under
+	// "destination-cooldowns".
+	CoolingDown   uint32 `protobuf:"varint,4,opt,name=cooling_down,json=coolingDown,proto3" json:"cooling_down,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -101,21 +110,112 @@ func (x *HZResponse) GetResult() map[string]string {
 	return nil
 }
 
+func (x *HZResponse) GetCircuits() []*MXCircuit {
+	if x != nil {
+		return x.Circuits
+	}
+	return nil
+}
+
+func (x *HZResponse) GetCoolingDown() uint32 {
+	if x != nil {
+		return x.CoolingDown
+	}
+	return 0
+}
+
+type MXCircuit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Mx    string                 `protobuf:"bytes,1,opt,name=mx,proto3" json:"mx,omitempty"`
+	// "open", or "half_open" while one message probes the host.
+	State    string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	OpenedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=opened_at,json=openedAt,proto3" json:"opened_at,omitempty"`
+	// When an open circuit lets a probe through.
+	RetryAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=retry_at,json=retryAt,proto3" json:"retry_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MXCircuit) Reset() {
+	*x = MXCircuit{}
+	mi := &file_kannon_admin_apiv1_hz_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MXCircuit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MXCircuit) ProtoMessage() {}
+
+func (x *MXCircuit) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_admin_apiv1_hz_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MXCircuit.ProtoReflect.Descriptor instead.
+func (*MXCircuit) Descriptor() ([]byte, []int) {
+	return file_kannon_admin_apiv1_hz_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MXCircuit) GetMx() string {
+	if x != nil {
+		return x.Mx
+	}
+	return ""
+}
+
+func (x *MXCircuit) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *MXCircuit) GetOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return nil
+}
+
+func (x *MXCircuit) GetRetryAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetryAt
+	}
+	return nil
+}
+
 var File_kannon_admin_apiv1_hz_proto protoreflect.FileDescriptor
 
 const file_kannon_admin_apiv1_hz_proto_rawDesc = "" +
 	"\n" +
-	"\x1bkannon/admin/apiv1/hz.proto\x12\x16pkg.kannon.admin.apiv1\"\v\n" +
-	"\tHZRequest\"\x8f\x01\n" +
+	"\x1bkannon/admin/apiv1/hz.proto\x12\x16pkg.kannon.admin.apiv1\x1a\x1fgoogle/protobuf/timestamp.proto\"\v\n" +
+	"\tHZRequest\"\x82\x02\n" +
 	"\n" +
 	"HZResponse\x12F\n" +
-	"\x06result\x18\x01 \x03(\v2..pkg.kannon.admin.apiv1.HZResponse.ResultEntryR\x06result\x1a9\n" +
+	"\x06result\x18\x01 \x03(\v2..pkg.kannon.admin.apiv1.HZResponse.ResultEntryR\x06result\x12=\n" +
+	"\bcircuits\x18\x03 \x03(\v2!.pkg.kannon.admin.apiv1.MXCircuitR\bcircuits\x12!\n" +
+	"\fcooling_down\x18\x04 \x01(\rR\vcoolingDown\x1a9\n" +
 	"\vResultEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012Z\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01J\x04\b\x02\x10\x03R\tcooldowns\"\xa1\x01\n" +
+	"\tMXCircuit\x12\x0e\n" +
+	"\x02mx\x18\x01 \x01(\tR\x02mx\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x127\n" +
+	"\topened_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bopenedAt\x125\n" +
+	"\bretry_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aretryAt2Z\n" +
 	"\tHZService\x12M\n" +
 	"\x02HZ\x12!.pkg.kannon.admin.apiv1.HZRequest\x1a\".pkg.kannon.admin.apiv1.HZResponse\"\x00B\xda\x01\n" +
-	"\x1acom.pkg.kannon.admin.apiv1B\aHzProtoP\x01Z7github.com/kannon-email/kannon/proto/kannon/admin/apiv1\xa2\x02\x04PKAA\xaa\x02\x16Pkg.Kannon.Admin.Apiv1\xca\x02\x16Pkg\\Kannon\\Admin\\Apiv1\xe2\x02\"Pkg\\Kannon\\Admin\\Apiv1\\GPBMetadata\xea\x02\x19Pkg::Kannon::Admin::Apiv1"
+	"\x1acom.pkg.kannon.admin.apiv1B\aHzProtoP\x01Z7github.com/kannon-email/kannon/proto/kannon/admin/apiv1\xa2\x02\x04PKAA\xaa\x02\x16Pkg.Kannon.Admin.Apiv1\xca\x02\x16Pkg\\Kannon\\Admin\\Apiv1\xe2\x02\"Pkg\\Kannon\\Admin\\Apiv1\\GPBMetadata\xea\x02\x19Pkg::Kannon::Admin::Apiv1b\x06proto3"
 
 var (
 	file_kannon_admin_apiv1_hz_proto_rawDescOnce sync.Once
@@ -129,21 +229,26 @@ func file_kannon_admin_apiv1_hz_proto_rawDescGZIP() []byte {
 	return file_kannon_admin_apiv1_hz_proto_rawDescData
 }
 
-var file_kannon_admin_apiv1_hz_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_kannon_admin_apiv1_hz_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_kannon_admin_apiv1_hz_proto_goTypes = []any{
-	(*HZRequest)(nil),  // 0: pkg.kannon.admin.apiv1.HZRequest
-	(*HZResponse)(nil), // 1: pkg.kannon.admin.apiv1.HZResponse
-	nil,                // 2: pkg.kannon.admin.apiv1.HZResponse.ResultEntry
+	(*HZRequest)(nil),             // 0: pkg.kannon.admin.apiv1.HZRequest
+	(*HZResponse)(nil),            // 1: pkg.kannon.admin.apiv1.HZResponse
+	(*MXCircuit)(nil),             // 2: pkg.kannon.admin.apiv1.MXCircuit
+	nil,                           // 3: pkg.kannon.admin.apiv1.HZResponse.ResultEntry
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
 }
 var file_kannon_admin_apiv1_hz_proto_depIdxs = []int32{
-	2, // 0: pkg.kannon.admin.apiv1.HZResponse.result:type_name -> pkg.kannon.admin.apiv1.HZResponse.ResultEntry
-	0, // 1: pkg.kannon.admin.apiv1.HZService.HZ:input_type -> pkg.kannon.admin.apiv1.HZRequest
-	1, // 2: pkg.kannon.admin.apiv1.HZService.HZ:output_type -> pkg.kannon.admin.apiv1.HZResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3, // 0: pkg.kannon.admin.apiv1.HZResponse.result:type_name -> pkg.kannon.admin.apiv1.HZResponse.ResultEntry
+	2, // 1: pkg.kannon.admin.apiv1.HZResponse.circuits:type_name -> pkg.kannon.admin.apiv1.MXCircuit
+	4, // 2: pkg.kannon.admin.apiv1.MXCircuit.opened_at:type_name -> google.protobuf.Timestamp
+	4, // 3: pkg.kannon.admin.apiv1.MXCircuit.retry_at:type_name -> google.protobuf.Timestamp
+	0, // 4: pkg.kannon.admin.apiv1.HZService.HZ:input_type -> pkg.kannon.admin.apiv1.HZRequest
+	1, // 5: pkg.kannon.admin.apiv1.HZService.HZ:output_type -> pkg.kannon.admin.apiv1.HZResponse
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_kannon_admin_apiv1_hz_proto_init() }
@@ -157,7 +262,7 @@ func file_kannon_admin_apiv1_hz_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kannon_admin_apiv1_hz_proto_rawDesc), len(file_kannon_admin_apiv1_hz_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
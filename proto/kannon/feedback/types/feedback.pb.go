@@ -0,0 +1,156 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: kannon/feedback/types/feedback.proto
+
+package types
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FeedbackIdentity is what a domain, or a batch overriding it, states about the
+// two headers mailbox providers key complaint feedback on:
+//
+//	Feedback-ID: <campaign>:<domain>:<sender_id>
+//	List-Id: <list_id>
+//
+// The domain is the sending domain's own name. Every field is optional: without
+// a sender_id no Feedback-ID is written, and without a list_id no List-Id. A
+// batch's statement overrides its domain's field by field, so a batch can name
+// its own campaign and keep the domain's sender_id. Both headers are
+// DKIM-signed.
+type FeedbackIdentity struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Up to 64 letters, digits, '.', '_' or '-'.
+	SenderId string `protobuf:"bytes,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	// Up to 64 letters, digits, '.', '_' or '-'.
+	Campaign string `protobuf:"bytes,2,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	// An RFC 2919 list-id without its angle brackets, e.g.
+	// "newsletter.example.com".
+	ListId        string `protobuf:"bytes,3,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedbackIdentity) Reset() {
+	*x = FeedbackIdentity{}
+	mi := &file_kannon_feedback_types_feedback_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedbackIdentity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedbackIdentity) ProtoMessage() {}
+
+func (x *FeedbackIdentity) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_feedback_types_feedback_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedbackIdentity.ProtoReflect.Descriptor instead.
+func (*FeedbackIdentity) Descriptor() ([]byte, []int) {
+	return file_kannon_feedback_types_feedback_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FeedbackIdentity) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *FeedbackIdentity) GetCampaign() string {
+	if x != nil {
+		return x.Campaign
+	}
+	return ""
+}
+
+func (x *FeedbackIdentity) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+var File_kannon_feedback_types_feedback_proto protoreflect.FileDescriptor
+
+const file_kannon_feedback_types_feedback_proto_rawDesc = "" +
+	"\n" +
+	"$kannon/feedback/types/feedback.proto\x12\x19pkg.kannon.feedback.types\"d\n" +
+	"\x10FeedbackIdentity\x12\x1b\n" +
+	"\tsender_id\x18\x01 \x01(\tR\bsenderId\x12\x1a\n" +
+	"\bcampaign\x18\x02 \x01(\tR\bcampaign\x12\x17\n" +
+	"\alist_id\x18\x03 \x01(\tR\x06listIdB\xf2\x01\n" +
+	"\x1dcom.pkg.kannon.feedback.typesB\rFeedbackProtoP\x01Z:github.com/kannon-email/kannon/proto/kannon/feedback/types\xa2\x02\x04PKFT\xaa\x02\x19Pkg.Kannon.Feedback.Types\xca\x02\x19Pkg\\Kannon\\Feedback\\Types\xe2\x02%Pkg\\Kannon\\Feedback\\Types\\GPBMetadata\xea\x02\x1cPkg::Kannon::Feedback::Typesb\x06proto3"
+
+var (
+	file_kannon_feedback_types_feedback_proto_rawDescOnce sync.Once
+	file_kannon_feedback_types_feedback_proto_rawDescData []byte
+)
+
+func file_kannon_feedback_types_feedback_proto_rawDescGZIP() []byte {
+	file_kannon_feedback_types_feedback_proto_rawDescOnce.Do(func() {
+		file_kannon_feedback_types_feedback_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_kannon_feedback_types_feedback_proto_rawDesc), len(file_kannon_feedback_types_feedback_proto_rawDesc)))
+	})
+	return file_kannon_feedback_types_feedback_proto_rawDescData
+}
+
+var file_kannon_feedback_types_feedback_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_kannon_feedback_types_feedback_proto_goTypes = []any{
+	(*FeedbackIdentity)(nil), // 0: pkg.kannon.feedback.types.FeedbackIdentity
+}
+var file_kannon_feedback_types_feedback_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_kannon_feedback_types_feedback_proto_init() }
+func file_kannon_feedback_types_feedback_proto_init() {
+	if File_kannon_feedback_types_feedback_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kannon_feedback_types_feedback_proto_rawDesc), len(file_kannon_feedback_types_feedback_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_kannon_feedback_types_feedback_proto_goTypes,
+		DependencyIndexes: file_kannon_feedback_types_feedback_proto_depIdxs,
+		MessageInfos:      file_kannon_feedback_types_feedback_proto_msgTypes,
+	}.Build()
+	File_kannon_feedback_types_feedback_proto = out.File
+	file_kannon_feedback_types_feedback_proto_goTypes = nil
+	file_kannon_feedback_types_feedback_proto_depIdxs = nil
+}
@@ -0,0 +1,164 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: kannon/linkparams/types/linkparams.proto
+
+package types
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// LinkDecoration is the set of query parameters — UTM tags, typically — a
+// domain has appended to the links of its mail, and the hosts it has them
+// appended on. Links are decorated before they are rewritten for click
+// tracking, so the tracked redirect lands on the decorated URL.
+//
+// A parameter already on a link is never replaced, and links marked
+// data-no-track, mailto: links and in-page anchors are never decorated. A
+// value may use the recipient's fields as placeholders, e.g.
+// "{{ segment }}"; a parameter whose placeholder the recipient has no field
+// for is left off that recipient's links.
+type LinkDecoration struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Parameter name to value. Names are up to 64 letters, digits, '.', '_' or
+	// '-'; values up to 512 characters, which are percent-encoded when applied.
+	Params map[string]string `protobuf:"bytes,1,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Only links to these hosts, or their subdomains, are decorated. Empty, any
+	// host is.
+	AllowHosts []string `protobuf:"bytes,2,rep,name=allow_hosts,json=allowHosts,proto3" json:"allow_hosts,omitempty"`
+	// Links to these hosts, or their subdomains, are never decorated, even when
+	// allow_hosts covers them.
+	DenyHosts     []string `protobuf:"bytes,3,rep,name=deny_hosts,json=denyHosts,proto3" json:"deny_hosts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkDecoration) Reset() {
+	*x = LinkDecoration{}
+	mi := &file_kannon_linkparams_types_linkparams_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkDecoration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkDecoration) ProtoMessage() {}
+
+func (x *LinkDecoration) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_linkparams_types_linkparams_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkDecoration.ProtoReflect.Descriptor instead.
+func (*LinkDecoration) Descriptor() ([]byte, []int) {
+	return file_kannon_linkparams_types_linkparams_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LinkDecoration) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *LinkDecoration) GetAllowHosts() []string {
+	if x != nil {
+		return x.AllowHosts
+	}
+	return nil
+}
+
+func (x *LinkDecoration) GetDenyHosts() []string {
+	if x != nil {
+		return x.DenyHosts
+	}
+	return nil
+}
+
+var File_kannon_linkparams_types_linkparams_proto protoreflect.FileDescriptor
+
+const file_kannon_linkparams_types_linkparams_proto_rawDesc = "" +
+	"\n" +
+	"(kannon/linkparams/types/linkparams.proto\x12\x1bpkg.kannon.linkparams.types\"\xdc\x01\n" +
+	"\x0eLinkDecoration\x12O\n" +
+	"\x06params\x18\x01 \x03(\v27.pkg.kannon.linkparams.types.LinkDecoration.ParamsEntryR\x06params\x12\x1f\n" +
+	"\vallow_hosts\x18\x02 \x03(\tR\n" +
+	"allowHosts\x12\x1d\n" +
+	"\n" +
+	"deny_hosts\x18\x03 \x03(\tR\tdenyHosts\x1a9\n" +
+	"\vParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x80\x02\n" +
+	"\x1fcom.pkg.kannon.linkparams.typesB\x0fLinkparamsProtoP\x01Z<github.com/kannon-email/kannon/proto/kannon/linkparams/types\xa2\x02\x04PKLT\xaa\x02\x1bPkg.Kannon.Linkparams.Types\xca\x02\x1bPkg\\Kannon\\Linkparams\\Types\xe2\x02'Pkg\\Kannon\\Linkparams\\Types\\GPBMetadata\xea\x02\x1ePkg::Kannon::Linkparams::Typesb\x06proto3"
+
+var (
+	file_kannon_linkparams_types_linkparams_proto_rawDescOnce sync.Once
+	file_kannon_linkparams_types_linkparams_proto_rawDescData []byte
+)
+
+func file_kannon_linkparams_types_linkparams_proto_rawDescGZIP() []byte {
+	file_kannon_linkparams_types_linkparams_proto_rawDescOnce.Do(func() {
+		file_kannon_linkparams_types_linkparams_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_kannon_linkparams_types_linkparams_proto_rawDesc), len(file_kannon_linkparams_types_linkparams_proto_rawDesc)))
+	})
+	return file_kannon_linkparams_types_linkparams_proto_rawDescData
+}
+
+var file_kannon_linkparams_types_linkparams_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_kannon_linkparams_types_linkparams_proto_goTypes = []any{
+	(*LinkDecoration)(nil), // 0: pkg.kannon.linkparams.types.LinkDecoration
+	nil,                    // 1: pkg.kannon.linkparams.types.LinkDecoration.ParamsEntry
+}
+var file_kannon_linkparams_types_linkparams_proto_depIdxs = []int32{
+	1, // 0: pkg.kannon.linkparams.types.LinkDecoration.params:type_name -> pkg.kannon.linkparams.types.LinkDecoration.ParamsEntry
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_kannon_linkparams_types_linkparams_proto_init() }
+func file_kannon_linkparams_types_linkparams_proto_init() {
+	if File_kannon_linkparams_types_linkparams_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kannon_linkparams_types_linkparams_proto_rawDesc), len(file_kannon_linkparams_types_linkparams_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_kannon_linkparams_types_linkparams_proto_goTypes,
+		DependencyIndexes: file_kannon_linkparams_types_linkparams_proto_depIdxs,
+		MessageInfos:      file_kannon_linkparams_types_linkparams_proto_msgTypes,
+	}.Build()
+	File_kannon_linkparams_types_linkparams_proto = out.File
+	file_kannon_linkparams_types_linkparams_proto_goTypes = nil
+	file_kannon_linkparams_types_linkparams_proto_depIdxs = nil
+}
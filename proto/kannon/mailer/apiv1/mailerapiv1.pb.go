@@ -7,6 +7,7 @@
 package apiv1
 
 import (
+	types2 "github.com/kannon-email/kannon/proto/kannon/feedback/types"
 	types "github.com/kannon-email/kannon/proto/kannon/mailer/types"
 	types1 "github.com/kannon-email/kannon/proto/kannon/tracking/types"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
@@ -94,8 +95,23 @@ type SendHTMLReq struct {
 	// List-Unsubscribe + List-Unsubscribe-Post on every Delivery of this Batch.
 	// Omitted when absent: Kannon never adds one of its own.
 	OneClickUnsubscribe *types.OneClickUnsubscribe `protobuf:"bytes,11,opt,name=one_click_unsubscribe,json=oneClickUnsubscribe,proto3,oneof" json:"one_click_unsubscribe,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// Overrides the domain's Feedback-ID and List-Id for this batch, field by
+	// field. A value outside the allowed character set fails the call.
+	Feedback *types2.FeedbackIdentity `protobuf:"bytes,12,opt,name=feedback,proto3,oneof" json:"feedback,omitempty"`
+	// Overrides the domain's link query parameters for this batch, parameter by
+	// parameter: a name given here replaces the domain's, and a name given with
+	// an empty value removes it. The domain's allowed and denied hosts still
+	// apply.
+	LinkParams map[string]string `protobuf:"bytes,13,rep,name=link_params,json=linkParams,proto3" json:"link_params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Holds every message of this batch back rather than sending it without
+	// authenticated TLS. False leaves it to the domain: a batch can require TLS
+	// its domain does not, but never relax a domain that requires it.
+	RequireTls bool `protobuf:"varint,14,opt,name=require_tls,json=requireTls,proto3" json:"require_tls,omitempty"`
+	// The IP pool the batch's mail is sent from, over its domain's; empty
+	// leaves it to the domain.
+	IpPool        string `protobuf:"bytes,15,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SendHTMLReq) Reset() {
@@ -198,6 +214,34 @@ func (x *SendHTMLReq) GetOneClickUnsubscribe() *types.OneClickUnsubscribe {
 	return nil
 }
 
+func (x *SendHTMLReq) GetFeedback() *types2.FeedbackIdentity {
+	if x != nil {
+		return x.Feedback
+	}
+	return nil
+}
+
+func (x *SendHTMLReq) GetLinkParams() map[string]string {
+	if x != nil {
+		return x.LinkParams
+	}
+	return nil
+}
+
+func (x *SendHTMLReq) GetRequireTls() bool {
+	if x != nil {
+		return x.RequireTls
+	}
+	return false
+}
+
+func (x *SendHTMLReq) GetIpPool() string {
+	if x != nil {
+		return x.IpPool
+	}
+	return ""
+}
+
 type SendTemplateReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Sender        *types.Sender          `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
@@ -216,8 +260,23 @@ type SendTemplateReq struct {
 	// List-Unsubscribe + List-Unsubscribe-Post on every Delivery of this Batch.
 	// Omitted when absent: Kannon never adds one of its own.
 	OneClickUnsubscribe *types.OneClickUnsubscribe `protobuf:"bytes,11,opt,name=one_click_unsubscribe,json=oneClickUnsubscribe,proto3,oneof" json:"one_click_unsubscribe,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// Overrides the domain's Feedback-ID and List-Id for this batch, field by
+	// field. A value outside the allowed character set fails the call.
+	Feedback *types2.FeedbackIdentity `protobuf:"bytes,12,opt,name=feedback,proto3,oneof" json:"feedback,omitempty"`
+	// Overrides the domain's link query parameters for this batch, parameter by
+	// parameter: a name given here replaces the domain's, and a name given with
+	// an empty value removes it. The domain's allowed and denied hosts still
+	// apply.
+	LinkParams map[string]string `protobuf:"bytes,13,rep,name=link_params,json=linkParams,proto3" json:"link_params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Holds every message of this batch back rather than sending it without
+	// authenticated TLS. False leaves it to the domain: a batch can require TLS
+	// its domain does not, but never relax a domain that requires it.
+	RequireTls bool `protobuf:"varint,14,opt,name=require_tls,json=requireTls,proto3" json:"require_tls,omitempty"`
+	// The IP pool the batch's mail is sent from, over its domain's; empty
+	// leaves it to the domain.
+	IpPool        string `protobuf:"bytes,15,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SendTemplateReq) Reset() {
@@ -320,6 +379,34 @@ func (x *SendTemplateReq) GetOneClickUnsubscribe() *types.OneClickUnsubscribe {
 	return nil
 }
 
+func (x *SendTemplateReq) GetFeedback() *types2.FeedbackIdentity {
+	if x != nil {
+		return x.Feedback
+	}
+	return nil
+}
+
+func (x *SendTemplateReq) GetLinkParams() map[string]string {
+	if x != nil {
+		return x.LinkParams
+	}
+	return nil
+}
+
+func (x *SendTemplateReq) GetRequireTls() bool {
+	if x != nil {
+		return x.RequireTls
+	}
+	return false
+}
+
+func (x *SendTemplateReq) GetIpPool() string {
+	if x != nil {
+		return x.IpPool
+	}
+	return ""
+}
+
 type SendRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
@@ -430,6 +517,9 @@ type RejectedRecipient struct {
 	//	                           one_click_unsubscribe.url_template unresolved,
 	//	                           so its unsubscribe endpoint would be advertised
 	//	                           as authenticated while being unreachable
+	//	smime_certificate_invalid  the Recipient's smime_certificate cannot be
+	//	                           encrypted to: unparseable, expired, not RSA,
+	//	                           or not for email
 	//
 	// Treat an unrecognised value as a refusal of unknown cause: the set grows as
 	// new causes are added.
@@ -486,11 +576,11 @@ var File_kannon_mailer_apiv1_mailerapiv1_proto protoreflect.FileDescriptor
 
 const file_kannon_mailer_apiv1_mailerapiv1_proto_rawDesc = "" +
 	"\n" +
-	"%kannon/mailer/apiv1/mailerapiv1.proto\x12\x17pkg.kannon.mailer.apiv1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1ekannon/mailer/types/send.proto\x1a$kannon/tracking/types/tracking.proto\"B\n" +
+	"%kannon/mailer/apiv1/mailerapiv1.proto\x12\x17pkg.kannon.mailer.apiv1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a$kannon/feedback/types/feedback.proto\x1a\x1ekannon/mailer/types/send.proto\x1a$kannon/tracking/types/tracking.proto\"B\n" +
 	"\n" +
 	"Attachment\x12\x1a\n" +
 	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x18\n" +
-	"\acontent\x18\x02 \x01(\fR\acontent\"\x9f\x06\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\"\xca\b\n" +
 	"\vSendHTMLReq\x127\n" +
 	"\x06sender\x18\x01 \x01(\v2\x1f.pkg.kannon.mailer.types.SenderR\x06sender\x12\x18\n" +
 	"\asubject\x18\x03 \x01(\tR\asubject\x12\x12\n" +
@@ -504,15 +594,25 @@ const file_kannon_mailer_apiv1_mailerapiv1_proto_rawDesc = "" +
 	"\aheaders\x18\t \x01(\v2 .pkg.kannon.mailer.types.HeadersH\x01R\aheaders\x88\x01\x01\x12J\n" +
 	"\btracking\x18\n" +
 	" \x01(\v2).pkg.kannon.tracking.types.TrackingPolicyH\x02R\btracking\x88\x01\x01\x12e\n" +
-	"\x15one_click_unsubscribe\x18\v \x01(\v2,.pkg.kannon.mailer.types.OneClickUnsubscribeH\x03R\x13oneClickUnsubscribe\x88\x01\x01\x1a?\n" +
+	"\x15one_click_unsubscribe\x18\v \x01(\v2,.pkg.kannon.mailer.types.OneClickUnsubscribeH\x03R\x13oneClickUnsubscribe\x88\x01\x01\x12L\n" +
+	"\bfeedback\x18\f \x01(\v2+.pkg.kannon.feedback.types.FeedbackIdentityH\x04R\bfeedback\x88\x01\x01\x12U\n" +
+	"\vlink_params\x18\r \x03(\v24.pkg.kannon.mailer.apiv1.SendHTMLReq.LinkParamsEntryR\n" +
+	"linkParams\x12\x1f\n" +
+	"\vrequire_tls\x18\x0e \x01(\bR\n" +
+	"requireTls\x12\x17\n" +
+	"\aip_pool\x18\x0f \x01(\tR\x06ipPool\x1a?\n" +
 	"\x11GlobalFieldsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a=\n" +
+	"\x0fLinkParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x11\n" +
 	"\x0f_scheduled_timeB\n" +
 	"\n" +
 	"\b_headersB\v\n" +
 	"\t_trackingB\x18\n" +
-	"\x16_one_click_unsubscribe\"\xb4\x06\n" +
+	"\x16_one_click_unsubscribeB\v\n" +
+	"\t_feedback\"\xe3\b\n" +
 	"\x0fSendTemplateReq\x127\n" +
 	"\x06sender\x18\x01 \x01(\v2\x1f.pkg.kannon.mailer.types.SenderR\x06sender\x12\x18\n" +
 	"\asubject\x18\x03 \x01(\tR\asubject\x12\x1f\n" +
@@ -527,15 +627,25 @@ const file_kannon_mailer_apiv1_mailerapiv1_proto_rawDesc = "" +
 	"\aheaders\x18\t \x01(\v2 .pkg.kannon.mailer.types.HeadersH\x01R\aheaders\x88\x01\x01\x12J\n" +
 	"\btracking\x18\n" +
 	" \x01(\v2).pkg.kannon.tracking.types.TrackingPolicyH\x02R\btracking\x88\x01\x01\x12e\n" +
-	"\x15one_click_unsubscribe\x18\v \x01(\v2,.pkg.kannon.mailer.types.OneClickUnsubscribeH\x03R\x13oneClickUnsubscribe\x88\x01\x01\x1a?\n" +
+	"\x15one_click_unsubscribe\x18\v \x01(\v2,.pkg.kannon.mailer.types.OneClickUnsubscribeH\x03R\x13oneClickUnsubscribe\x88\x01\x01\x12L\n" +
+	"\bfeedback\x18\f \x01(\v2+.pkg.kannon.feedback.types.FeedbackIdentityH\x04R\bfeedback\x88\x01\x01\x12Y\n" +
+	"\vlink_params\x18\r \x03(\v28.pkg.kannon.mailer.apiv1.SendTemplateReq.LinkParamsEntryR\n" +
+	"linkParams\x12\x1f\n" +
+	"\vrequire_tls\x18\x0e \x01(\bR\n" +
+	"requireTls\x12\x17\n" +
+	"\aip_pool\x18\x0f \x01(\tR\x06ipPool\x1a?\n" +
 	"\x11GlobalFieldsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a=\n" +
+	"\x0fLinkParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x11\n" +
 	"\x0f_scheduled_timeB\n" +
 	"\n" +
 	"\b_headersB\v\n" +
 	"\t_trackingB\x18\n" +
-	"\x16_one_click_unsubscribe\"\xb7\x02\n" +
+	"\x16_one_click_unsubscribeB\v\n" +
+	"\t_feedback\"\xb7\x02\n" +
 	"\aSendRes\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x1f\n" +
@@ -565,7 +675,7 @@ func file_kannon_mailer_apiv1_mailerapiv1_proto_rawDescGZIP() []byte {
 	return file_kannon_mailer_apiv1_mailerapiv1_proto_rawDescData
 }
 
-var file_kannon_mailer_apiv1_mailerapiv1_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_kannon_mailer_apiv1_mailerapiv1_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_kannon_mailer_apiv1_mailerapiv1_proto_goTypes = []any{
 	(*Attachment)(nil),                // 0: pkg.kannon.mailer.apiv1.Attachment
 	(*SendHTMLReq)(nil),               // 1: pkg.kannon.mailer.apiv1.SendHTMLReq
@@ -573,42 +683,49 @@ var file_kannon_mailer_apiv1_mailerapiv1_proto_goTypes = []any{
 	(*SendRes)(nil),                   // 3: pkg.kannon.mailer.apiv1.SendRes
 	(*RejectedRecipient)(nil),         // 4: pkg.kannon.mailer.apiv1.RejectedRecipient
 	nil,                               // 5: pkg.kannon.mailer.apiv1.SendHTMLReq.GlobalFieldsEntry
-	nil,                               // 6: pkg.kannon.mailer.apiv1.SendTemplateReq.GlobalFieldsEntry
-	(*types.Sender)(nil),              // 7: pkg.kannon.mailer.types.Sender
-	(*timestamppb.Timestamp)(nil),     // 8: google.protobuf.Timestamp
-	(*types.Recipient)(nil),           // 9: pkg.kannon.mailer.types.Recipient
-	(*types.Headers)(nil),             // 10: pkg.kannon.mailer.types.Headers
-	(*types1.TrackingPolicy)(nil),     // 11: pkg.kannon.tracking.types.TrackingPolicy
-	(*types.OneClickUnsubscribe)(nil), // 12: pkg.kannon.mailer.types.OneClickUnsubscribe
+	nil,                               // 6: pkg.kannon.mailer.apiv1.SendHTMLReq.LinkParamsEntry
+	nil,                               // 7: pkg.kannon.mailer.apiv1.SendTemplateReq.GlobalFieldsEntry
+	nil,                               // 8: pkg.kannon.mailer.apiv1.SendTemplateReq.LinkParamsEntry
+	(*types.Sender)(nil),              // 9: pkg.kannon.mailer.types.Sender
+	(*timestamppb.Timestamp)(nil),     // 10: google.protobuf.Timestamp
+	(*types.Recipient)(nil),           // 11: pkg.kannon.mailer.types.Recipient
+	(*types.Headers)(nil),             // 12: pkg.kannon.mailer.types.Headers
+	(*types1.TrackingPolicy)(nil),     // 13: pkg.kannon.tracking.types.TrackingPolicy
+	(*types.OneClickUnsubscribe)(nil), // 14: pkg.kannon.mailer.types.OneClickUnsubscribe
+	(*types2.FeedbackIdentity)(nil),   // 15: pkg.kannon.feedback.types.FeedbackIdentity
 }
 var file_kannon_mailer_apiv1_mailerapiv1_proto_depIdxs = []int32{
-	7,  // 0: pkg.kannon.mailer.apiv1.SendHTMLReq.sender:type_name -> pkg.kannon.mailer.types.Sender
-	8,  // 1: pkg.kannon.mailer.apiv1.SendHTMLReq.scheduled_time:type_name -> google.protobuf.Timestamp
-	9,  // 2: pkg.kannon.mailer.apiv1.SendHTMLReq.recipients:type_name -> pkg.kannon.mailer.types.Recipient
+	9,  // 0: pkg.kannon.mailer.apiv1.SendHTMLReq.sender:type_name -> pkg.kannon.mailer.types.Sender
+	10, // 1: pkg.kannon.mailer.apiv1.SendHTMLReq.scheduled_time:type_name -> google.protobuf.Timestamp
+	11, // 2: pkg.kannon.mailer.apiv1.SendHTMLReq.recipients:type_name -> pkg.kannon.mailer.types.Recipient
 	0,  // 3: pkg.kannon.mailer.apiv1.SendHTMLReq.attachments:type_name -> pkg.kannon.mailer.apiv1.Attachment
 	5,  // 4: pkg.kannon.mailer.apiv1.SendHTMLReq.global_fields:type_name -> pkg.kannon.mailer.apiv1.SendHTMLReq.GlobalFieldsEntry
-	10, // 5: pkg.kannon.mailer.apiv1.SendHTMLReq.headers:type_name -> pkg.kannon.mailer.types.Headers
-	11, // 6: pkg.kannon.mailer.apiv1.SendHTMLReq.tracking:type_name -> pkg.kannon.tracking.types.TrackingPolicy
-	12, // 7: pkg.kannon.mailer.apiv1.SendHTMLReq.one_click_unsubscribe:type_name -> pkg.kannon.mailer.types.OneClickUnsubscribe
-	7,  // 8: pkg.kannon.mailer.apiv1.SendTemplateReq.sender:type_name -> pkg.kannon.mailer.types.Sender
-	8,  // 9: pkg.kannon.mailer.apiv1.SendTemplateReq.scheduled_time:type_name -> google.protobuf.Timestamp
-	9,  // 10: pkg.kannon.mailer.apiv1.SendTemplateReq.recipients:type_name -> pkg.kannon.mailer.types.Recipient
-	0,  // 11: pkg.kannon.mailer.apiv1.SendTemplateReq.attachments:type_name -> pkg.kannon.mailer.apiv1.Attachment
-	6,  // 12: pkg.kannon.mailer.apiv1.SendTemplateReq.global_fields:type_name -> pkg.kannon.mailer.apiv1.SendTemplateReq.GlobalFieldsEntry
-	10, // 13: pkg.kannon.mailer.apiv1.SendTemplateReq.headers:type_name -> pkg.kannon.mailer.types.Headers
-	11, // 14: pkg.kannon.mailer.apiv1.SendTemplateReq.tracking:type_name -> pkg.kannon.tracking.types.TrackingPolicy
-	12, // 15: pkg.kannon.mailer.apiv1.SendTemplateReq.one_click_unsubscribe:type_name -> pkg.kannon.mailer.types.OneClickUnsubscribe
-	8,  // 16: pkg.kannon.mailer.apiv1.SendRes.scheduled_time:type_name -> google.protobuf.Timestamp
-	4,  // 17: pkg.kannon.mailer.apiv1.SendRes.rejected_recipients:type_name -> pkg.kannon.mailer.apiv1.RejectedRecipient
-	1,  // 18: pkg.kannon.mailer.apiv1.Mailer.SendHTML:input_type -> pkg.kannon.mailer.apiv1.SendHTMLReq
-	2,  // 19: pkg.kannon.mailer.apiv1.Mailer.SendTemplate:input_type -> pkg.kannon.mailer.apiv1.SendTemplateReq
-	3,  // 20: pkg.kannon.mailer.apiv1.Mailer.SendHTML:output_type -> pkg.kannon.mailer.apiv1.SendRes
-	3,  // 21: pkg.kannon.mailer.apiv1.Mailer.SendTemplate:output_type -> pkg.kannon.mailer.apiv1.SendRes
-	20, // [20:22] is the sub-list for method output_type
-	18, // [18:20] is the sub-list for method input_type
-	18, // [18:18] is the sub-list for extension type_name
-	18, // [18:18] is the sub-list for extension extendee
-	0,  // [0:18] is the sub-list for field type_name
+	12, // 5: pkg.kannon.mailer.apiv1.SendHTMLReq.headers:type_name -> pkg.kannon.mailer.types.Headers
+	13, // 6: pkg.kannon.mailer.apiv1.SendHTMLReq.tracking:type_name -> pkg.kannon.tracking.types.TrackingPolicy
+	14, // 7: pkg.kannon.mailer.apiv1.SendHTMLReq.one_click_unsubscribe:type_name -> pkg.kannon.mailer.types.OneClickUnsubscribe
+	15, // 8: pkg.kannon.mailer.apiv1.SendHTMLReq.feedback:type_name -> pkg.kannon.feedback.types.FeedbackIdentity
+	6,  // 9: pkg.kannon.mailer.apiv1.SendHTMLReq.link_params:type_name -> pkg.kannon.mailer.apiv1.SendHTMLReq.LinkParamsEntry
+	9,  // 10: pkg.kannon.mailer.apiv1.SendTemplateReq.sender:type_name -> pkg.kannon.mailer.types.Sender
+	10, // 11: pkg.kannon.mailer.apiv1.SendTemplateReq.scheduled_time:type_name -> google.protobuf.Timestamp
+	11, // 12: pkg.kannon.mailer.apiv1.SendTemplateReq.recipients:type_name -> pkg.kannon.mailer.types.Recipient
+	0,  // 13: pkg.kannon.mailer.apiv1.SendTemplateReq.attachments:type_name -> pkg.kannon.mailer.apiv1.Attachment
+	7,  // 14: pkg.kannon.mailer.apiv1.SendTemplateReq.global_fields:type_name -> pkg.kannon.mailer.apiv1.SendTemplateReq.GlobalFieldsEntry
+	12, // 15: pkg.kannon.mailer.apiv1.SendTemplateReq.headers:type_name -> pkg.kannon.mailer.types.Headers
+	13, // 16: pkg.kannon.mailer.apiv1.SendTemplateReq.tracking:type_name -> pkg.kannon.tracking.types.TrackingPolicy
+	14, // 17: pkg.kannon.mailer.apiv1.SendTemplateReq.one_click_unsubscribe:type_name -> pkg.kannon.mailer.types.OneClickUnsubscribe
+	15, // 18: pkg.kannon.mailer.apiv1.SendTemplateReq.feedback:type_name -> pkg.kannon.feedback.types.FeedbackIdentity
+	8,  // 19: pkg.kannon.mailer.apiv1.SendTemplateReq.link_params:type_name -> pkg.kannon.mailer.apiv1.SendTemplateReq.LinkParamsEntry
+	10, // 20: pkg.kannon.mailer.apiv1.SendRes.scheduled_time:type_name -> google.protobuf.Timestamp
+	4,  // 21: pkg.kannon.mailer.apiv1.SendRes.rejected_recipients:type_name -> pkg.kannon.mailer.apiv1.RejectedRecipient
+	1,  // 22: pkg.kannon.mailer.apiv1.Mailer.SendHTML:input_type -> pkg.kannon.mailer.apiv1.SendHTMLReq
+	2,  // 23: pkg.kannon.mailer.apiv1.Mailer.SendTemplate:input_type -> pkg.kannon.mailer.apiv1.SendTemplateReq
+	3,  // 24: pkg.kannon.mailer.apiv1.Mailer.SendHTML:output_type -> pkg.kannon.mailer.apiv1.SendRes
+	3,  // 25: pkg.kannon.mailer.apiv1.Mailer.SendTemplate:output_type -> pkg.kannon.mailer.apiv1.SendRes
+	24, // [24:26] is the sub-list for method output_type
+	22, // [22:24] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_kannon_mailer_apiv1_mailerapiv1_proto_init() }
@@ -624,7 +741,7 @@ func file_kannon_mailer_apiv1_mailerapiv1_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kannon_mailer_apiv1_mailerapiv1_proto_rawDesc), len(file_kannon_mailer_apiv1_mailerapiv1_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,13 +22,34 @@ const (
 )
 
 type EmailToSend struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	EmailId       string                 `protobuf:"bytes,1,opt,name=email_id,json=emailId,proto3" json:"email_id,omitempty"`
-	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
-	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
-	ReturnPath    string                 `protobuf:"bytes,4,opt,name=return_path,json=returnPath,proto3" json:"return_path,omitempty"`
-	Body          []byte                 `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
-	ShouldRetry   bool                   `protobuf:"varint,6,opt,name=should_retry,json=shouldRetry,proto3" json:"should_retry,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	EmailId    string                 `protobuf:"bytes,1,opt,name=email_id,json=emailId,proto3" json:"email_id,omitempty"`
+	From       string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To         string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	ReturnPath string                 `protobuf:"bytes,4,opt,name=return_path,json=returnPath,proto3" json:"return_path,omitempty"`
+	// body is the whole message, when it is carried inline. It is empty when
+	// body_parts is set.
+	Body        []byte `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	ShouldRetry bool   `protobuf:"varint,6,opt,name=should_retry,json=shouldRetry,proto3" json:"should_retry,omitempty"`
+	// body_parts is the message of an Envelope too large to carry inline, in
+	// order: the bytes that are this recipient's own travel here, and the large
+	// MIME parts every recipient of the Batch shares are named by reference into
+	// the kannon-envelope-bodies Object Store. Concatenated, they are the
+	// message byte for byte.
+	BodyParts []*BodyPart `protobuf:"bytes,7,rep,name=body_parts,json=bodyParts,proto3" json:"body_parts,omitempty"`
+	// require_tls has the message sent only over STARTTLS to an MX host whose
+	// certificate verifies — its Domain or its Batch requires it — and held
+	// back when no MX host can offer that.
+	RequireTls bool `protobuf:"varint,8,opt,name=require_tls,json=requireTls,proto3" json:"require_tls,omitempty"`
+	// ip_pool names the IP pool the message is sent from, empty for the
+	// sender's default.
+	IpPool string `protobuf:"bytes,9,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"`
+	// size is the body's length in bytes, inline or offloaded, which the
+	// sender declares to receivers that offer SIZE.
+	Size int64 `protobuf:"varint,10,opt,name=size,proto3" json:"size,omitempty"`
+	// smtputf8 is set when the header carries UTF-8 that only a receiver
+	// offering SMTPUTF8 may be given.
+	Smtputf8      bool `protobuf:"varint,11,opt,name=smtputf8,proto3" json:"smtputf8,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -105,11 +126,130 @@ func (x *EmailToSend) GetShouldRetry() bool {
 	return false
 }
 
+func (x *EmailToSend) GetBodyParts() []*BodyPart {
+	if x != nil {
+		return x.BodyParts
+	}
+	return nil
+}
+
+func (x *EmailToSend) GetRequireTls() bool {
+	if x != nil {
+		return x.RequireTls
+	}
+	return false
+}
+
+func (x *EmailToSend) GetIpPool() string {
+	if x != nil {
+		return x.IpPool
+	}
+	return ""
+}
+
+func (x *EmailToSend) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *EmailToSend) GetSmtputf8() bool {
+	if x != nil {
+		return x.Smtputf8
+	}
+	return false
+}
+
+type BodyPart struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Part:
+	//
+	//	*BodyPart_Inline
+	//	*BodyPart_Ref
+	Part          isBodyPart_Part `protobuf_oneof:"part"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BodyPart) Reset() {
+	*x = BodyPart{}
+	mi := &file_kannon_mailer_types_email_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BodyPart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BodyPart) ProtoMessage() {}
+
+func (x *BodyPart) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_mailer_types_email_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BodyPart.ProtoReflect.Descriptor instead.
+func (*BodyPart) Descriptor() ([]byte, []int) {
+	return file_kannon_mailer_types_email_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BodyPart) GetPart() isBodyPart_Part {
+	if x != nil {
+		return x.Part
+	}
+	return nil
+}
+
+func (x *BodyPart) GetInline() []byte {
+	if x != nil {
+		if x, ok := x.Part.(*BodyPart_Inline); ok {
+			return x.Inline
+		}
+	}
+	return nil
+}
+
+func (x *BodyPart) GetRef() string {
+	if x != nil {
+		if x, ok := x.Part.(*BodyPart_Ref); ok {
+			return x.Ref
+		}
+	}
+	return ""
+}
+
+type isBodyPart_Part interface {
+	isBodyPart_Part()
+}
+
+type BodyPart_Inline struct {
+	Inline []byte `protobuf:"bytes,1,opt,name=inline,proto3,oneof"`
+}
+
+type BodyPart_Ref struct {
+	// ref names an object in the kannon-envelope-bodies Object Store:
+	// "sha256-" and the hex digest of its content.
+	Ref string `protobuf:"bytes,2,opt,name=ref,proto3,oneof"`
+}
+
+func (*BodyPart_Inline) isBodyPart_Part() {}
+
+func (*BodyPart_Ref) isBodyPart_Part() {}
+
 var File_kannon_mailer_types_email_proto protoreflect.FileDescriptor
 
 const file_kannon_mailer_types_email_proto_rawDesc = "" +
 	"\n" +
-	"\x1fkannon/mailer/types/email.proto\x12\x17pkg.kannon.mailer.types\"\xa4\x01\n" +
+	"\x1fkannon/mailer/types/email.proto\x12\x17pkg.kannon.mailer.types\"\xd0\x02\n" +
 	"\vEmailToSend\x12\x19\n" +
 	"\bemail_id\x18\x01 \x01(\tR\aemailId\x12\x12\n" +
 	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
@@ -117,7 +257,19 @@ const file_kannon_mailer_types_email_proto_rawDesc = "" +
 	"\vreturn_path\x18\x04 \x01(\tR\n" +
 	"returnPath\x12\x12\n" +
 	"\x04body\x18\x05 \x01(\fR\x04body\x12!\n" +
-	"\fshould_retry\x18\x06 \x01(\bR\vshouldRetryB\xe3\x01\n" +
+	"\fshould_retry\x18\x06 \x01(\bR\vshouldRetry\x12@\n" +
+	"\n" +
+	"body_parts\x18\a \x03(\v2!.pkg.kannon.mailer.types.BodyPartR\tbodyParts\x12\x1f\n" +
+	"\vrequire_tls\x18\b \x01(\bR\n" +
+	"requireTls\x12\x17\n" +
+	"\aip_pool\x18\t \x01(\tR\x06ipPool\x12\x12\n" +
+	"\x04size\x18\n" +
+	" \x01(\x03R\x04size\x12\x1a\n" +
+	"\bsmtputf8\x18\v \x01(\bR\bsmtputf8\"@\n" +
+	"\bBodyPart\x12\x18\n" +
+	"\x06inline\x18\x01 \x01(\fH\x00R\x06inline\x12\x12\n" +
+	"\x03ref\x18\x02 \x01(\tH\x00R\x03refB\x06\n" +
+	"\x04partB\xe3\x01\n" +
 	"\x1bcom.pkg.kannon.mailer.typesB\n" +
 	"EmailProtoP\x01Z8github.com/kannon-email/kannon/proto/kannon/mailer/types\xa2\x02\x04PKMT\xaa\x02\x17Pkg.Kannon.Mailer.Types\xca\x02\x17Pkg\\Kannon\\Mailer\\Types\xe2\x02#Pkg\\Kannon\\Mailer\\Types\\GPBMetadata\xea\x02\x1aPkg::Kannon::Mailer::Typesb\x06proto3"
 
@@ -133,16 +285,18 @@ func file_kannon_mailer_types_email_proto_rawDescGZIP() []byte {
 	return file_kannon_mailer_types_email_proto_rawDescData
 }
 
-var file_kannon_mailer_types_email_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_kannon_mailer_types_email_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_kannon_mailer_types_email_proto_goTypes = []any{
 	(*EmailToSend)(nil), // 0: pkg.kannon.mailer.types.EmailToSend
+	(*BodyPart)(nil),    // 1: pkg.kannon.mailer.types.BodyPart
 }
 var file_kannon_mailer_types_email_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: pkg.kannon.mailer.types.EmailToSend.body_parts:type_name -> pkg.kannon.mailer.types.BodyPart
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_kannon_mailer_types_email_proto_init() }
@@ -150,13 +304,17 @@ func file_kannon_mailer_types_email_proto_init() {
 	if File_kannon_mailer_types_email_proto != nil {
 		return
 	}
+	file_kannon_mailer_types_email_proto_msgTypes[1].OneofWrappers = []any{
+		(*BodyPart_Inline)(nil),
+		(*BodyPart_Ref)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kannon_mailer_types_email_proto_rawDesc), len(file_kannon_mailer_types_email_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -88,9 +88,16 @@ type Recipient struct {
 	// stating a Mode above its Domain's ceiling is Rejected on its own, with a
 	// reason in SendRes.rejected_recipients, while the rest of the Batch
 	// proceeds — one bad row does not fail a send of thousands.
-	Tracking      *types.TrackingPolicy `protobuf:"bytes,3,opt,name=tracking,proto3,oneof" json:"tracking,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Tracking *types.TrackingPolicy `protobuf:"bytes,3,opt,name=tracking,proto3,oneof" json:"tracking,omitempty"`
+	// A certificate, PEM, to encrypt this Recipient's mail to with S/MIME. It
+	// must be valid now, hold an RSA key of at least 2048 bits, and allow key
+	// encipherment and email protection where it states its usages. The
+	// message is signed first when the domain has a signing certificate. A
+	// certificate that cannot be used Rejects this Recipient alone, with
+	// reason `smime_certificate_invalid`.
+	SmimeCertificate string `protobuf:"bytes,4,opt,name=smime_certificate,json=smimeCertificate,proto3" json:"smime_certificate,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *Recipient) Reset() {
@@ -144,6 +151,13 @@ func (x *Recipient) GetTracking() *types.TrackingPolicy {
 	return nil
 }
 
+func (x *Recipient) GetSmimeCertificate() string {
+	if x != nil {
+		return x.SmimeCertificate
+	}
+	return ""
+}
+
 type Headers struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	To            []string               `protobuf:"bytes,1,rep,name=to,proto3" json:"to,omitempty"`
@@ -276,11 +290,12 @@ const file_kannon_mailer_types_send_proto_rawDesc = "" +
 	"\x1ekannon/mailer/types/send.proto\x12\x17pkg.kannon.mailer.types\x1a$kannon/tracking/types/tracking.proto\"4\n" +
 	"\x06Sender\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x14\n" +
-	"\x05alias\x18\x02 \x01(\tR\x05alias\"\xfd\x01\n" +
+	"\x05alias\x18\x02 \x01(\tR\x05alias\"\xaa\x02\n" +
 	"\tRecipient\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12F\n" +
 	"\x06fields\x18\x02 \x03(\v2..pkg.kannon.mailer.types.Recipient.FieldsEntryR\x06fields\x12J\n" +
-	"\btracking\x18\x03 \x01(\v2).pkg.kannon.tracking.types.TrackingPolicyH\x00R\btracking\x88\x01\x01\x1a9\n" +
+	"\btracking\x18\x03 \x01(\v2).pkg.kannon.tracking.types.TrackingPolicyH\x00R\btracking\x88\x01\x01\x12+\n" +
+	"\x11smime_certificate\x18\x04 \x01(\tR\x10smimeCertificate\x1a9\n" +
 	"\vFieldsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\v\n" +
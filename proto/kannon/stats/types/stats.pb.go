@@ -24,10 +24,13 @@ const (
 )
 
 type StatsAggregated struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Type      string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Count     int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// The category of the bounces counted, for type "bounced"; empty for every
+	// other type. A bucket of bounces is one row per category.
+	Category      string `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -83,6 +86,13 @@ func (x *StatsAggregated) GetCount() int64 {
 	return 0
 }
 
+func (x *StatsAggregated) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
 type Stats struct {
 	state     protoimpl.MessageState `protogen:"open.v1"`
 	MessageId string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
@@ -192,6 +202,9 @@ type StatsData struct {
 	//	*StatsData_Clicked
 	//	*StatsData_Rejected
 	//	*StatsData_Error
+	//	*StatsData_Delayed
+	//	*StatsData_Postponed
+	//	*StatsData_Reported
 	Data          isStatsData_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -306,6 +319,33 @@ func (x *StatsData) GetError() *StatsDataError {
 	return nil
 }
 
+func (x *StatsData) GetDelayed() *StatsDataDelayed {
+	if x != nil {
+		if x, ok := x.Data.(*StatsData_Delayed); ok {
+			return x.Delayed
+		}
+	}
+	return nil
+}
+
+func (x *StatsData) GetPostponed() *StatsDataPostponed {
+	if x != nil {
+		if x, ok := x.Data.(*StatsData_Postponed); ok {
+			return x.Postponed
+		}
+	}
+	return nil
+}
+
+func (x *StatsData) GetReported() *StatsDataReported {
+	if x != nil {
+		if x, ok := x.Data.(*StatsData_Reported); ok {
+			return x.Reported
+		}
+	}
+	return nil
+}
+
 type isStatsData_Data interface {
 	isStatsData_Data()
 }
@@ -342,6 +382,18 @@ type StatsData_Error struct {
 	Error *StatsDataError `protobuf:"bytes,8,opt,name=error,proto3,oneof"`
 }
 
+type StatsData_Delayed struct {
+	Delayed *StatsDataDelayed `protobuf:"bytes,9,opt,name=delayed,proto3,oneof"`
+}
+
+type StatsData_Postponed struct {
+	Postponed *StatsDataPostponed `protobuf:"bytes,10,opt,name=postponed,proto3,oneof"`
+}
+
+type StatsData_Reported struct {
+	Reported *StatsDataReported `protobuf:"bytes,11,opt,name=reported,proto3,oneof"`
+}
+
 func (*StatsData_Accepted) isStatsData_Data() {}
 
 func (*StatsData_Delivered) isStatsData_Data() {}
@@ -358,6 +410,12 @@ func (*StatsData_Rejected) isStatsData_Data() {}
 
 func (*StatsData_Error) isStatsData_Data() {}
 
+func (*StatsData_Delayed) isStatsData_Data() {}
+
+func (*StatsData_Postponed) isStatsData_Data() {}
+
+func (*StatsData_Reported) isStatsData_Data() {}
+
 type StatsDataAccepted struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -438,8 +496,13 @@ func (x *StatsDataRejected) GetReason() string {
 	return ""
 }
 
+// ip_pool and source_ip are where the message was sent from: the IP pool,
+// empty for none, and the local address of the connection.
 type StatsDataDelivered struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	IpPool        string                 `protobuf:"bytes,1,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"`
+	SourceIp      string                 `protobuf:"bytes,2,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"`
+	Smtp          *StatsDataSMTP         `protobuf:"bytes,3,opt,name=smtp,proto3" json:"smtp,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -474,6 +537,144 @@ func (*StatsDataDelivered) Descriptor() ([]byte, []int) {
 	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{5}
 }
 
+func (x *StatsDataDelivered) GetIpPool() string {
+	if x != nil {
+		return x.IpPool
+	}
+	return ""
+}
+
+func (x *StatsDataDelivered) GetSourceIp() string {
+	if x != nil {
+		return x.SourceIp
+	}
+	return ""
+}
+
+func (x *StatsDataDelivered) GetSmtp() *StatsDataSMTP {
+	if x != nil {
+		return x.Smtp
+	}
+	return nil
+}
+
+// StatsDataSMTP is the SMTP session an outcome of the sender came out of.
+// enhanced_code is the RFC 3463 status — the reply's, or for a failure no
+// reply came with the code that describes it — and is set on every outcome
+// of the sender, one before any session was opened included. stage is where
+// the session failed (connect, ehlo, starttls, auth, mail, rcpt, data), empty
+// on a delivery. reply is the host's last reply, every line of it; queue_id
+// the ID a delivered message was queued under at the host, when its reply
+// named one in a recognised form; transcript the mail transaction as spoken,
+// a "C: " or "S: " line each, without the body.
+type StatsDataSMTP struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MxHost        string                 `protobuf:"bytes,1,opt,name=mx_host,json=mxHost,proto3" json:"mx_host,omitempty"`
+	MxIp          string                 `protobuf:"bytes,2,opt,name=mx_ip,json=mxIp,proto3" json:"mx_ip,omitempty"`
+	Stage         string                 `protobuf:"bytes,3,opt,name=stage,proto3" json:"stage,omitempty"`
+	EnhancedCode  string                 `protobuf:"bytes,4,opt,name=enhanced_code,json=enhancedCode,proto3" json:"enhanced_code,omitempty"`
+	TlsVersion    string                 `protobuf:"bytes,5,opt,name=tls_version,json=tlsVersion,proto3" json:"tls_version,omitempty"`
+	TlsCipher     string                 `protobuf:"bytes,6,opt,name=tls_cipher,json=tlsCipher,proto3" json:"tls_cipher,omitempty"`
+	Reply         string                 `protobuf:"bytes,7,opt,name=reply,proto3" json:"reply,omitempty"`
+	QueueId       string                 `protobuf:"bytes,8,opt,name=queue_id,json=queueId,proto3" json:"queue_id,omitempty"`
+	Transcript    string                 `protobuf:"bytes,9,opt,name=transcript,proto3" json:"transcript,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsDataSMTP) Reset() {
+	*x = StatsDataSMTP{}
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsDataSMTP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsDataSMTP) ProtoMessage() {}
+
+func (x *StatsDataSMTP) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsDataSMTP.ProtoReflect.Descriptor instead.
+func (*StatsDataSMTP) Descriptor() ([]byte, []int) {
+	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StatsDataSMTP) GetMxHost() string {
+	if x != nil {
+		return x.MxHost
+	}
+	return ""
+}
+
+func (x *StatsDataSMTP) GetMxIp() string {
+	if x != nil {
+		return x.MxIp
+	}
+	return ""
+}
+
+func (x *StatsDataSMTP) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *StatsDataSMTP) GetEnhancedCode() string {
+	if x != nil {
+		return x.EnhancedCode
+	}
+	return ""
+}
+
+func (x *StatsDataSMTP) GetTlsVersion() string {
+	if x != nil {
+		return x.TlsVersion
+	}
+	return ""
+}
+
+func (x *StatsDataSMTP) GetTlsCipher() string {
+	if x != nil {
+		return x.TlsCipher
+	}
+	return ""
+}
+
+func (x *StatsDataSMTP) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+func (x *StatsDataSMTP) GetQueueId() string {
+	if x != nil {
+		return x.QueueId
+	}
+	return ""
+}
+
+func (x *StatsDataSMTP) GetTranscript() string {
+	if x != nil {
+		return x.Transcript
+	}
+	return ""
+}
+
 // Failed is a Delivery whose retry budget ran out without a single attempt
 // ever being answered, so there is no reply to classify: deliberately no
 // `code`, only a `reason`, like Rejected.
@@ -486,7 +687,7 @@ type StatsDataFailed struct {
 
 func (x *StatsDataFailed) Reset() {
 	*x = StatsDataFailed{}
-	mi := &file_kannon_stats_types_stats_proto_msgTypes[6]
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -498,7 +699,7 @@ func (x *StatsDataFailed) String() string {
 func (*StatsDataFailed) ProtoMessage() {}
 
 func (x *StatsDataFailed) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_stats_types_stats_proto_msgTypes[6]
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -511,7 +712,7 @@ func (x *StatsDataFailed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatsDataFailed.ProtoReflect.Descriptor instead.
 func (*StatsDataFailed) Descriptor() ([]byte, []int) {
-	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{6}
+	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StatsDataFailed) GetReason() string {
@@ -521,18 +722,30 @@ func (x *StatsDataFailed) GetReason() string {
 	return ""
 }
 
+// ip_pool, source_ip and smtp are set on a Bounce the sender took during
+// transmission, as on Delivered. An asynchronous Bounce has no ip_pool or
+// source_ip, and its smtp is what the DSN says of the remote MTA: mx_host is
+// its Remote-MTA and enhanced_code its Status, when it gives them.
 type StatsDataBounced struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Permanent     bool                   `protobuf:"varint,1,opt,name=permanent,proto3" json:"permanent,omitempty"`
-	Code          uint32                 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
-	Msg           string                 `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Permanent bool                   `protobuf:"varint,1,opt,name=permanent,proto3" json:"permanent,omitempty"`
+	Code      uint32                 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Msg       string                 `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	IpPool    string                 `protobuf:"bytes,4,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"`
+	SourceIp  string                 `protobuf:"bytes,5,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"`
+	Smtp      *StatsDataSMTP         `protobuf:"bytes,6,opt,name=smtp,proto3" json:"smtp,omitempty"`
+	// The kind of failure this was, one of the internal/bounce categories:
+	// invalid_recipient, mailbox_full, policy_block, spam_block, dns_failure,
+	// tls_failure, rate_limited or other. Empty on a bounce stored before
+	// bounces were classified.
+	Category      string `protobuf:"bytes,7,opt,name=category,proto3" json:"category,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *StatsDataBounced) Reset() {
 	*x = StatsDataBounced{}
-	mi := &file_kannon_stats_types_stats_proto_msgTypes[7]
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -544,7 +757,7 @@ func (x *StatsDataBounced) String() string {
 func (*StatsDataBounced) ProtoMessage() {}
 
 func (x *StatsDataBounced) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_stats_types_stats_proto_msgTypes[7]
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -557,7 +770,7 @@ func (x *StatsDataBounced) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatsDataBounced.ProtoReflect.Descriptor instead.
 func (*StatsDataBounced) Descriptor() ([]byte, []int) {
-	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{7}
+	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *StatsDataBounced) GetPermanent() bool {
@@ -581,17 +794,164 @@ func (x *StatsDataBounced) GetMsg() string {
 	return ""
 }
 
+func (x *StatsDataBounced) GetIpPool() string {
+	if x != nil {
+		return x.IpPool
+	}
+	return ""
+}
+
+func (x *StatsDataBounced) GetSourceIp() string {
+	if x != nil {
+		return x.SourceIp
+	}
+	return ""
+}
+
+func (x *StatsDataBounced) GetSmtp() *StatsDataSMTP {
+	if x != nil {
+		return x.Smtp
+	}
+	return nil
+}
+
+func (x *StatsDataBounced) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+// Delayed is a DSN whose remote MTA has not delivered the message yet and is
+// still trying. Not terminal. code, msg and smtp are as on an asynchronous
+// Bounce.
+type StatsDataDelayed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          uint32                 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Smtp          *StatsDataSMTP         `protobuf:"bytes,3,opt,name=smtp,proto3" json:"smtp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsDataDelayed) Reset() {
+	*x = StatsDataDelayed{}
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsDataDelayed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsDataDelayed) ProtoMessage() {}
+
+func (x *StatsDataDelayed) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsDataDelayed.ProtoReflect.Descriptor instead.
+func (*StatsDataDelayed) Descriptor() ([]byte, []int) {
+	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StatsDataDelayed) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *StatsDataDelayed) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *StatsDataDelayed) GetSmtp() *StatsDataSMTP {
+	if x != nil {
+		return x.Smtp
+	}
+	return nil
+}
+
+// Reported is a DSN saying the remote MTA delivered, relayed or expanded the
+// message; action is which. Not terminal: the Delivery was Delivered at
+// handoff. smtp is the remote MTA and status the report names.
+type StatsDataReported struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Smtp          *StatsDataSMTP         `protobuf:"bytes,2,opt,name=smtp,proto3" json:"smtp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsDataReported) Reset() {
+	*x = StatsDataReported{}
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsDataReported) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsDataReported) ProtoMessage() {}
+
+func (x *StatsDataReported) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsDataReported.ProtoReflect.Descriptor instead.
+func (*StatsDataReported) Descriptor() ([]byte, []int) {
+	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StatsDataReported) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *StatsDataReported) GetSmtp() *StatsDataSMTP {
+	if x != nil {
+		return x.Smtp
+	}
+	return nil
+}
+
 type StatsDataError struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Code          uint32                 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
 	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Smtp          *StatsDataSMTP         `protobuf:"bytes,3,opt,name=smtp,proto3" json:"smtp,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *StatsDataError) Reset() {
 	*x = StatsDataError{}
-	mi := &file_kannon_stats_types_stats_proto_msgTypes[8]
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -603,7 +963,7 @@ func (x *StatsDataError) String() string {
 func (*StatsDataError) ProtoMessage() {}
 
 func (x *StatsDataError) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_stats_types_stats_proto_msgTypes[8]
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -616,7 +976,7 @@ func (x *StatsDataError) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatsDataError.ProtoReflect.Descriptor instead.
 func (*StatsDataError) Descriptor() ([]byte, []int) {
-	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{8}
+	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *StatsDataError) GetCode() uint32 {
@@ -633,6 +993,69 @@ func (x *StatsDataError) GetMsg() string {
 	return ""
 }
 
+func (x *StatsDataError) GetSmtp() *StatsDataSMTP {
+	if x != nil {
+		return x.Smtp
+	}
+	return nil
+}
+
+// Postponed is an Envelope the sender held back without offering it, for
+// longer than the sending stream keeps one: the Dispatcher schedules the
+// Delivery again at `until` without spending an attempt. Not an outcome of the
+// Delivery, like Error; reason is Kannon's own short account of the hold-back.
+type StatsDataPostponed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=until,proto3" json:"until,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsDataPostponed) Reset() {
+	*x = StatsDataPostponed{}
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsDataPostponed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsDataPostponed) ProtoMessage() {}
+
+func (x *StatsDataPostponed) ProtoReflect() protoreflect.Message {
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsDataPostponed.ProtoReflect.Descriptor instead.
+func (*StatsDataPostponed) Descriptor() ([]byte, []int) {
+	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StatsDataPostponed) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *StatsDataPostponed) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 type StatsDataOpened struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserAgent     string                 `protobuf:"bytes,1,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
@@ -643,7 +1066,7 @@ type StatsDataOpened struct {
 
 func (x *StatsDataOpened) Reset() {
 	*x = StatsDataOpened{}
-	mi := &file_kannon_stats_types_stats_proto_msgTypes[9]
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -655,7 +1078,7 @@ func (x *StatsDataOpened) String() string {
 func (*StatsDataOpened) ProtoMessage() {}
 
 func (x *StatsDataOpened) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_stats_types_stats_proto_msgTypes[9]
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -668,7 +1091,7 @@ func (x *StatsDataOpened) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatsDataOpened.ProtoReflect.Descriptor instead.
 func (*StatsDataOpened) Descriptor() ([]byte, []int) {
-	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{9}
+	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *StatsDataOpened) GetUserAgent() string {
@@ -696,7 +1119,7 @@ type StatsDataClicked struct {
 
 func (x *StatsDataClicked) Reset() {
 	*x = StatsDataClicked{}
-	mi := &file_kannon_stats_types_stats_proto_msgTypes[10]
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -708,7 +1131,7 @@ func (x *StatsDataClicked) String() string {
 func (*StatsDataClicked) ProtoMessage() {}
 
 func (x *StatsDataClicked) ProtoReflect() protoreflect.Message {
-	mi := &file_kannon_stats_types_stats_proto_msgTypes[10]
+	mi := &file_kannon_stats_types_stats_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -721,7 +1144,7 @@ func (x *StatsDataClicked) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatsDataClicked.ProtoReflect.Descriptor instead.
 func (*StatsDataClicked) Descriptor() ([]byte, []int) {
-	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{10}
+	return file_kannon_stats_types_stats_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *StatsDataClicked) GetUserAgent() string {
@@ -749,11 +1172,12 @@ var File_kannon_stats_types_stats_proto protoreflect.FileDescriptor
 
 const file_kannon_stats_types_stats_proto_rawDesc = "" +
 	"\n" +
-	"\x1ekannon/stats/types/stats.proto\x12\x16pkg.kannon.stats.types\x1a\x1fgoogle/protobuf/timestamp.proto\x1a$kannon/tracking/types/tracking.proto\"u\n" +
+	"\x1ekannon/stats/types/stats.proto\x12\x16pkg.kannon.stats.types\x1a\x1fgoogle/protobuf/timestamp.proto\x1a$kannon/tracking/types/tracking.proto\"\x91\x01\n" +
 	"\x0fStatsAggregated\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x128\n" +
 	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x14\n" +
-	"\x05count\x18\x03 \x01(\x03R\x05count\"\xa7\x02\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\x12\x1a\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\"\xa7\x02\n" +
 	"\x05Stats\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x16\n" +
@@ -762,7 +1186,7 @@ const file_kannon_stats_types_stats_proto_rawDesc = "" +
 	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
 	"\x04type\x18\x05 \x01(\tR\x04type\x125\n" +
 	"\x04data\x18\x06 \x01(\v2!.pkg.kannon.stats.types.StatsDataR\x04data\x12L\n" +
-	"\rtracking_mode\x18\a \x01(\x0e2'.pkg.kannon.tracking.types.TrackingModeR\ftrackingMode\"\xc3\x04\n" +
+	"\rtracking_mode\x18\a \x01(\x0e2'.pkg.kannon.tracking.types.TrackingModeR\ftrackingMode\"\x9e\x06\n" +
 	"\tStatsData\x12G\n" +
 	"\baccepted\x18\x01 \x01(\v2).pkg.kannon.stats.types.StatsDataAcceptedH\x00R\baccepted\x12J\n" +
 	"\tdelivered\x18\x02 \x01(\v2*.pkg.kannon.stats.types.StatsDataDeliveredH\x00R\tdelivered\x12A\n" +
@@ -771,21 +1195,57 @@ const file_kannon_stats_types_stats_proto_rawDesc = "" +
 	"\x06opened\x18\x05 \x01(\v2'.pkg.kannon.stats.types.StatsDataOpenedH\x00R\x06opened\x12D\n" +
 	"\aclicked\x18\x06 \x01(\v2(.pkg.kannon.stats.types.StatsDataClickedH\x00R\aclicked\x12G\n" +
 	"\brejected\x18\a \x01(\v2).pkg.kannon.stats.types.StatsDataRejectedH\x00R\brejected\x12>\n" +
-	"\x05error\x18\b \x01(\v2&.pkg.kannon.stats.types.StatsDataErrorH\x00R\x05errorB\x06\n" +
+	"\x05error\x18\b \x01(\v2&.pkg.kannon.stats.types.StatsDataErrorH\x00R\x05error\x12D\n" +
+	"\adelayed\x18\t \x01(\v2(.pkg.kannon.stats.types.StatsDataDelayedH\x00R\adelayed\x12J\n" +
+	"\tpostponed\x18\n" +
+	" \x01(\v2*.pkg.kannon.stats.types.StatsDataPostponedH\x00R\tpostponed\x12G\n" +
+	"\breported\x18\v \x01(\v2).pkg.kannon.stats.types.StatsDataReportedH\x00R\breportedB\x06\n" +
 	"\x04data\"\x13\n" +
 	"\x11StatsDataAccepted\"+\n" +
 	"\x11StatsDataRejected\x12\x16\n" +
-	"\x06reason\x18\x01 \x01(\tR\x06reason\"\x14\n" +
-	"\x12StatsDataDelivered\")\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\"\x85\x01\n" +
+	"\x12StatsDataDelivered\x12\x17\n" +
+	"\aip_pool\x18\x01 \x01(\tR\x06ipPool\x12\x1b\n" +
+	"\tsource_ip\x18\x02 \x01(\tR\bsourceIp\x129\n" +
+	"\x04smtp\x18\x03 \x01(\v2%.pkg.kannon.stats.types.StatsDataSMTPR\x04smtp\"\x89\x02\n" +
+	"\rStatsDataSMTP\x12\x17\n" +
+	"\amx_host\x18\x01 \x01(\tR\x06mxHost\x12\x13\n" +
+	"\x05mx_ip\x18\x02 \x01(\tR\x04mxIp\x12\x14\n" +
+	"\x05stage\x18\x03 \x01(\tR\x05stage\x12#\n" +
+	"\renhanced_code\x18\x04 \x01(\tR\fenhancedCode\x12\x1f\n" +
+	"\vtls_version\x18\x05 \x01(\tR\n" +
+	"tlsVersion\x12\x1d\n" +
+	"\n" +
+	"tls_cipher\x18\x06 \x01(\tR\ttlsCipher\x12\x14\n" +
+	"\x05reply\x18\a \x01(\tR\x05reply\x12\x19\n" +
+	"\bqueue_id\x18\b \x01(\tR\aqueueId\x12\x1e\n" +
+	"\n" +
+	"transcript\x18\t \x01(\tR\n" +
+	"transcript\")\n" +
 	"\x0fStatsDataFailed\x12\x16\n" +
-	"\x06reason\x18\x01 \x01(\tR\x06reason\"V\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\"\xe3\x01\n" +
 	"\x10StatsDataBounced\x12\x1c\n" +
 	"\tpermanent\x18\x01 \x01(\bR\tpermanent\x12\x12\n" +
 	"\x04code\x18\x02 \x01(\rR\x04code\x12\x10\n" +
-	"\x03msg\x18\x03 \x01(\tR\x03msg\"6\n" +
+	"\x03msg\x18\x03 \x01(\tR\x03msg\x12\x17\n" +
+	"\aip_pool\x18\x04 \x01(\tR\x06ipPool\x12\x1b\n" +
+	"\tsource_ip\x18\x05 \x01(\tR\bsourceIp\x129\n" +
+	"\x04smtp\x18\x06 \x01(\v2%.pkg.kannon.stats.types.StatsDataSMTPR\x04smtp\x12\x1a\n" +
+	"\bcategory\x18\a \x01(\tR\bcategory\"s\n" +
+	"\x10StatsDataDelayed\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\rR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x129\n" +
+	"\x04smtp\x18\x03 \x01(\v2%.pkg.kannon.stats.types.StatsDataSMTPR\x04smtp\"f\n" +
+	"\x11StatsDataReported\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x129\n" +
+	"\x04smtp\x18\x02 \x01(\v2%.pkg.kannon.stats.types.StatsDataSMTPR\x04smtp\"q\n" +
 	"\x0eStatsDataError\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\rR\x04code\x12\x10\n" +
-	"\x03msg\x18\x02 \x01(\tR\x03msg\"@\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x129\n" +
+	"\x04smtp\x18\x03 \x01(\v2%.pkg.kannon.stats.types.StatsDataSMTPR\x04smtp\"^\n" +
+	"\x12StatsDataPostponed\x120\n" +
+	"\x05until\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"@\n" +
 	"\x0fStatsDataOpened\x12\x1d\n" +
 	"\n" +
 	"user_agent\x18\x01 \x01(\tR\tuserAgent\x12\x0e\n" +
@@ -810,7 +1270,7 @@ func file_kannon_stats_types_stats_proto_rawDescGZIP() []byte {
 	return file_kannon_stats_types_stats_proto_rawDescData
 }
 
-var file_kannon_stats_types_stats_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_kannon_stats_types_stats_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_kannon_stats_types_stats_proto_goTypes = []any{
 	(*StatsAggregated)(nil),       // 0: pkg.kannon.stats.types.StatsAggregated
 	(*Stats)(nil),                 // 1: pkg.kannon.stats.types.Stats
@@ -818,32 +1278,45 @@ var file_kannon_stats_types_stats_proto_goTypes = []any{
 	(*StatsDataAccepted)(nil),     // 3: pkg.kannon.stats.types.StatsDataAccepted
 	(*StatsDataRejected)(nil),     // 4: pkg.kannon.stats.types.StatsDataRejected
 	(*StatsDataDelivered)(nil),    // 5: pkg.kannon.stats.types.StatsDataDelivered
-	(*StatsDataFailed)(nil),       // 6: pkg.kannon.stats.types.StatsDataFailed
-	(*StatsDataBounced)(nil),      // 7: pkg.kannon.stats.types.StatsDataBounced
-	(*StatsDataError)(nil),        // 8: pkg.kannon.stats.types.StatsDataError
-	(*StatsDataOpened)(nil),       // 9: pkg.kannon.stats.types.StatsDataOpened
-	(*StatsDataClicked)(nil),      // 10: pkg.kannon.stats.types.StatsDataClicked
-	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
-	(types.TrackingMode)(0),       // 12: pkg.kannon.tracking.types.TrackingMode
+	(*StatsDataSMTP)(nil),         // 6: pkg.kannon.stats.types.StatsDataSMTP
+	(*StatsDataFailed)(nil),       // 7: pkg.kannon.stats.types.StatsDataFailed
+	(*StatsDataBounced)(nil),      // 8: pkg.kannon.stats.types.StatsDataBounced
+	(*StatsDataDelayed)(nil),      // 9: pkg.kannon.stats.types.StatsDataDelayed
+	(*StatsDataReported)(nil),     // 10: pkg.kannon.stats.types.StatsDataReported
+	(*StatsDataError)(nil),        // 11: pkg.kannon.stats.types.StatsDataError
+	(*StatsDataPostponed)(nil),    // 12: pkg.kannon.stats.types.StatsDataPostponed
+	(*StatsDataOpened)(nil),       // 13: pkg.kannon.stats.types.StatsDataOpened
+	(*StatsDataClicked)(nil),      // 14: pkg.kannon.stats.types.StatsDataClicked
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+	(types.TrackingMode)(0),       // 16: pkg.kannon.tracking.types.TrackingMode
 }
 var file_kannon_stats_types_stats_proto_depIdxs = []int32{
-	11, // 0: pkg.kannon.stats.types.StatsAggregated.timestamp:type_name -> google.protobuf.Timestamp
-	11, // 1: pkg.kannon.stats.types.Stats.timestamp:type_name -> google.protobuf.Timestamp
+	15, // 0: pkg.kannon.stats.types.StatsAggregated.timestamp:type_name -> google.protobuf.Timestamp
+	15, // 1: pkg.kannon.stats.types.Stats.timestamp:type_name -> google.protobuf.Timestamp
 	2,  // 2: pkg.kannon.stats.types.Stats.data:type_name -> pkg.kannon.stats.types.StatsData
-	12, // 3: pkg.kannon.stats.types.Stats.tracking_mode:type_name -> pkg.kannon.tracking.types.TrackingMode
+	16, // 3: pkg.kannon.stats.types.Stats.tracking_mode:type_name -> pkg.kannon.tracking.types.TrackingMode
 	3,  // 4: pkg.kannon.stats.types.StatsData.accepted:type_name -> pkg.kannon.stats.types.StatsDataAccepted
 	5,  // 5: pkg.kannon.stats.types.StatsData.delivered:type_name -> pkg.kannon.stats.types.StatsDataDelivered
-	6,  // 6: pkg.kannon.stats.types.StatsData.failed:type_name -> pkg.kannon.stats.types.StatsDataFailed
-	7,  // 7: pkg.kannon.stats.types.StatsData.bounced:type_name -> pkg.kannon.stats.types.StatsDataBounced
-	9,  // 8: pkg.kannon.stats.types.StatsData.opened:type_name -> pkg.kannon.stats.types.StatsDataOpened
-	10, // 9: pkg.kannon.stats.types.StatsData.clicked:type_name -> pkg.kannon.stats.types.StatsDataClicked
+	7,  // 6: pkg.kannon.stats.types.StatsData.failed:type_name -> pkg.kannon.stats.types.StatsDataFailed
+	8,  // 7: pkg.kannon.stats.types.StatsData.bounced:type_name -> pkg.kannon.stats.types.StatsDataBounced
+	13, // 8: pkg.kannon.stats.types.StatsData.opened:type_name -> pkg.kannon.stats.types.StatsDataOpened
+	14, // 9: pkg.kannon.stats.types.StatsData.clicked:type_name -> pkg.kannon.stats.types.StatsDataClicked
 	4,  // 10: pkg.kannon.stats.types.StatsData.rejected:type_name -> pkg.kannon.stats.types.StatsDataRejected
-	8,  // 11: pkg.kannon.stats.types.StatsData.error:type_name -> pkg.kannon.stats.types.StatsDataError
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	11, // 11: pkg.kannon.stats.types.StatsData.error:type_name -> pkg.kannon.stats.types.StatsDataError
+	9,  // 12: pkg.kannon.stats.types.StatsData.delayed:type_name -> pkg.kannon.stats.types.StatsDataDelayed
+	12, // 13: pkg.kannon.stats.types.StatsData.postponed:type_name -> pkg.kannon.stats.types.StatsDataPostponed
+	10, // 14: pkg.kannon.stats.types.StatsData.reported:type_name -> pkg.kannon.stats.types.StatsDataReported
+	6,  // 15: pkg.kannon.stats.types.StatsDataDelivered.smtp:type_name -> pkg.kannon.stats.types.StatsDataSMTP
+	6,  // 16: pkg.kannon.stats.types.StatsDataBounced.smtp:type_name -> pkg.kannon.stats.types.StatsDataSMTP
+	6,  // 17: pkg.kannon.stats.types.StatsDataDelayed.smtp:type_name -> pkg.kannon.stats.types.StatsDataSMTP
+	6,  // 18: pkg.kannon.stats.types.StatsDataReported.smtp:type_name -> pkg.kannon.stats.types.StatsDataSMTP
+	6,  // 19: pkg.kannon.stats.types.StatsDataError.smtp:type_name -> pkg.kannon.stats.types.StatsDataSMTP
+	15, // 20: pkg.kannon.stats.types.StatsDataPostponed.until:type_name -> google.protobuf.Timestamp
+	21, // [21:21] is the sub-list for method output_type
+	21, // [21:21] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_kannon_stats_types_stats_proto_init() }
@@ -860,6 +1333,9 @@ func file_kannon_stats_types_stats_proto_init() {
 		(*StatsData_Clicked)(nil),
 		(*StatsData_Rejected)(nil),
 		(*StatsData_Error)(nil),
+		(*StatsData_Delayed)(nil),
+		(*StatsData_Postponed)(nil),
+		(*StatsData_Reported)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -867,7 +1343,7 @@ func file_kannon_stats_types_stats_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kannon_stats_types_stats_proto_rawDesc), len(file_kannon_stats_types_stats_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
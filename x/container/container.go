@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kannon-email/kannon/internal/capture"
+	"github.com/kannon-email/kannon/internal/circuit"
 	sqlc "github.com/kannon-email/kannon/internal/db"
 	"github.com/kannon-email/kannon/internal/delivery"
 	"github.com/kannon-email/kannon/internal/publisher"
+	"github.com/kannon-email/kannon/internal/resolver"
 	"github.com/kannon-email/kannon/internal/smtp"
 	"github.com/kannon-email/kannon/x/config"
 	"github.com/nats-io/nats-server/v2/server"
@@ -37,6 +41,7 @@ type Container struct {
 	nats               *singleton[*nats.Conn]
 	embeddedNatsServer *singleton[*server.Server]
 	sender             *singleton[smtp.Sender]
+	resolver           *singleton[resolver.Resolver]
 
 	// mu guards closers and hzs, which are appended to from singleton factory
 	// callbacks that may run concurrently across runnable goroutines.
@@ -48,6 +53,26 @@ type Container struct {
 type senderCfg struct {
 	Hostname   string `mapstructure:"hostname"`
 	DemoSender bool   `mapstructure:"demo_sender"`
+	// Capture keeps mail instead of sending it, and serves it back from an
+	// inbox; see capture.Config.
+	Capture capture.Config  `mapstructure:"capture"`
+	Pool    smtp.PoolConfig `mapstructure:"pool"`
+	// Transports and Routes send some mail through relays instead of
+	// directly; see smtp.NewRoutingTable.
+	Transports map[string]smtp.TransportConfig `mapstructure:"transports"`
+	Routes     []smtp.RouteConfig              `mapstructure:"routes"`
+	// TLS holds MX hosts to MTA-STS and DANE; see smtp.TLSPolicyConfig.
+	TLS smtp.TLSPolicyConfig `mapstructure:"tls"`
+	// IPPools and DefaultIPPool choose the address mail is sent from; see
+	// smtp.NewIPPools.
+	IPPools       map[string]smtp.IPPoolConfig `mapstructure:"ip_pools"`
+	DefaultIPPool string                       `mapstructure:"default_ip_pool"`
+	// AddressFamily is which of an MX host's addresses are dialled; see
+	// smtp.AddressFamily.
+	AddressFamily smtp.AddressFamily `mapstructure:"address_family"`
+	// Circuit passes over MX hosts that stopped answering; see
+	// circuit.Config.
+	Circuit circuit.Config `mapstructure:"circuit"`
 }
 
 // New creates a Container from the root configuration the boot path has already
@@ -70,6 +95,7 @@ func New(ctx context.Context, cfg config.RootConfig) *Container {
 		nats:               &singleton[*nats.Conn]{},
 		embeddedNatsServer: &singleton[*server.Server]{},
 		sender:             &singleton[smtp.Sender]{},
+		resolver:           &singleton[resolver.Resolver]{},
 	}
 }
 
@@ -113,6 +139,7 @@ func NewForTest(ctx context.Context, opts ...TestOption) *Container {
 		nats:               &singleton[*nats.Conn]{},
 		embeddedNatsServer: &singleton[*server.Server]{},
 		sender:             &singleton[smtp.Sender]{},
+		resolver:           &singleton[resolver.Resolver]{},
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -362,16 +389,88 @@ func (c *Container) RetryWindow() time.Duration {
 // sender runnable asks for this while it is being constructed — on the boot path —
 // so for the process that does send, an unresolvable reference is still a boot
 // failure and not a surprise mid-delivery.
+//
+// The connections the Sender holds open between messages are QUIT when the
+// Container closes. Unless `sender.circuit.disabled` is set, it connects to
+// NATS to share the circuits of MX hosts with the other sender replicas.
 func (c *Container) Sender() smtp.Sender {
 	return c.sender.MustGet(c.ctx, func(ctx context.Context) (smtp.Sender, error) {
 		var sc senderCfg
 		if err := config.TryLoadSection("sender", &sc); err != nil {
 			return nil, err
 		}
+		if sc.Capture.Enabled {
+			if sc.DemoSender {
+				return nil, errors.New("config: sender: capture and demo_sender cannot both be enabled")
+			}
+			sink, err := capture.New(sc.Hostname, sc.Capture,
+				capture.WithDomains(sqlc.NewDomainsRepository(c.DB())))
+			if err != nil {
+				return nil, fmt.Errorf("config: sender: %w", err)
+			}
+			return sink, nil
+		}
 		if sc.DemoSender {
 			return smtp.NewDemoSender(sc.Hostname), nil
 		}
-		return smtp.NewSender(sc.Hostname), nil
+		routes, err := smtp.NewRoutingTable(sc.Transports, sc.Routes)
+		if err != nil {
+			return nil, fmt.Errorf("config: sender: %w", err)
+		}
+		if err := sc.TLS.Validate(); err != nil {
+			return nil, fmt.Errorf("config: sender: %w", err)
+		}
+		pools, err := smtp.NewIPPools(sc.IPPools, sc.DefaultIPPool)
+		if err != nil {
+			return nil, fmt.Errorf("config: sender: %w", err)
+		}
+		if err := sc.AddressFamily.Validate(); err != nil {
+			return nil, fmt.Errorf("config: sender: %w", err)
+		}
+		if err := pools.ValidateFamily(sc.AddressFamily); err != nil {
+			return nil, fmt.Errorf("config: sender: %w", err)
+		}
+		if err := sc.Circuit.Validate(); err != nil {
+			return nil, fmt.Errorf("config: sender.circuit: %w", err)
+		}
+		opts := []smtp.SenderOption{
+			smtp.WithPool(sc.Pool),
+			smtp.WithRoutingTable(routes),
+			smtp.WithTLSPolicy(sc.TLS),
+			smtp.WithIPPools(pools),
+			smtp.WithResolver(c.Resolver()),
+			smtp.WithAddressFamily(sc.AddressFamily),
+		}
+		if !sc.Circuit.Disabled {
+			breaker, err := circuit.OpenNATS(ctx, c.NatsJetStream(), sc.Circuit)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, smtp.WithCircuitBreaker(breaker))
+		}
+		s := smtp.NewSender(sc.Hostname, opts...)
+		if closer, ok := s.(io.Closer); ok {
+			c.addClosers(func(context.Context) error {
+				return closer.Close()
+			})
+		}
+		return s, nil
+	})
+}
+
+// Resolver returns the singleton MX resolver, reading the `dns` section the first
+// time one is asked for. Everything in the process that looks up MX hosts asks
+// it, so that they share one cache and one answer.
+func (c *Container) Resolver() resolver.Resolver {
+	return c.resolver.MustGet(c.ctx, func(ctx context.Context) (resolver.Resolver, error) {
+		var cfg resolver.Config
+		if err := config.TryLoadSection("dns", &cfg); err != nil {
+			return nil, err
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		return resolver.New(cfg), nil
 	})
 }
 